@@ -46,6 +46,10 @@ type ControlMonitor struct {
 	// UI spectrogram manager for lifecycle management
 	uiSpectrogramManager *UiSpectrogramManager	
 
+	// Cancelled when the control monitor quits, stopping the managers started under it
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	// Track telemetry endpoint
 	telemetryEndpoint      *observability.Endpoint
 	telemetryEndpointMutex sync.Mutex
@@ -56,7 +60,10 @@ type ControlMonitor struct {
 
 // NewControlMonitor creates a new ControlMonitor instance
 func NewControlMonitor(wg *sync.WaitGroup, controlChan chan string, quitChan, restartChan chan struct{}, bufferManager *BufferManager, proc *processor.Processor, audioLevelChan chan myaudio.AudioLevelData, spectrogramChan chan myaudio.UiSpectrogramData, soundLevelChan chan myaudio.SoundLevelData, apiController *apiv2.Controller, metrics *observability.Metrics) *ControlMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
 	cm := &ControlMonitor{
+		ctx:            ctx,
+		cancel:         cancel,
 		wg:             wg,
 		controlChan:    controlChan,
 		quitChan:       quitChan,
@@ -98,7 +105,11 @@ func (cm *ControlMonitor) Stop() {
 	}
 	
 	if cm.uiSpectrogramManager != nil {
-		cm.uiSpectrogramManager.Stop()
+		if err := cm.uiSpectrogramManager.Stop(); err != nil {
+			GetLogger().Warn("UI spectrogram monitoring did not stop cleanly", logger.Error(err))
+		}
+		// Audio capture and the API must not keep using the stopped manager
+		activeUiSpectrogramManager.CompareAndSwap(cm.uiSpectrogramManager, nil)
 	}
 
 	// Stop telemetry endpoint if running
@@ -110,6 +121,8 @@ func (cm *ControlMonitor) Stop() {
 		cm.telemetryQuitChan = nil
 	}
 	cm.telemetryEndpointMutex.Unlock()
+
+	cm.cancel()
 }
 
 // initializeSoundLevelIfEnabled starts sound level monitoring if it's enabled in settings
@@ -134,13 +147,14 @@ func (cm *ControlMonitor) initializeUiSpectrogramIfEnabled() {
 	if settings.SoundId.Enabled {
 		// Initialize the UI spectrogram manager
 		if cm.uiSpectrogramManager == nil {
-			cm.uiSpectrogramManager = NewUiSpectrogramManager(cm.spectrogramChan, cm.proc, cm.apiController, cm.metrics)
+			cm.uiSpectrogramManager = NewUiSpectrogramManager(cm.spectrogramChan, cm.proc, cm.apiController, cm.metrics, nil)
+			activeUiSpectrogramManager.Store(cm.uiSpectrogramManager)
 		}
 
 		GetLogger().Info("starting UI spectrogram generation")
 		
 		// Start UI spectrogram generation
-		if err := cm.uiSpectrogramManager.Start(); err != nil {
+		if err := cm.uiSpectrogramManager.Start(cm.ctx); err != nil {
 			GetLogger().Warn("Failed to start UI spectrogram generation", logger.Error(err))
 		}
 	}
@@ -193,6 +207,7 @@ func (cm *ControlMonitor) monitor() {
 		case signal := <-cm.controlChan:
 			cm.handleControlSignal(signal)
 		case <-cm.quitChan:
+			cm.cancel()
 			return
 		}
 	}
@@ -219,6 +234,8 @@ func (cm *ControlMonitor) handleControlSignal(signal string) {
 		cm.handleReconfigureTelemetry()
 	case "reconfigure_species_tracking":
 		cm.handleReconfigureSpeciesTracking()
+	case "reconfigure_ui_spectrogram":
+		cm.handleReconfigureUiSpectrogram()
 	default:
 		GetLogger().Warn("Received unknown control signal", logger.String("signal", signal))
 	}
@@ -541,6 +558,34 @@ func (cm *ControlMonitor) handleReconfigureSoundLevel() {
 	}
 }
 
+// handleReconfigureUiSpectrogram starts or stops UI spectrogram generation to match the
+// current settings. Both directions are idempotent, so repeated signals are harmless.
+func (cm *ControlMonitor) handleReconfigureUiSpectrogram() {
+	if !conf.Setting().SoundId.Enabled {
+		if cm.uiSpectrogramManager != nil && cm.uiSpectrogramManager.IsRunning() {
+			if err := cm.uiSpectrogramManager.Stop(); err != nil {
+				cm.notifyError("UI spectrogram generation did not stop cleanly", err)
+				return
+			}
+			cm.notifySuccess("UI spectrogram generation disabled")
+		}
+		return
+	}
+
+	if cm.uiSpectrogramManager == nil {
+		cm.uiSpectrogramManager = NewUiSpectrogramManager(cm.spectrogramChan, cm.proc, cm.apiController, cm.metrics, nil)
+		activeUiSpectrogramManager.Store(cm.uiSpectrogramManager)
+	}
+	if cm.uiSpectrogramManager.IsRunning() {
+		return
+	}
+	if err := cm.uiSpectrogramManager.Start(cm.ctx); err != nil {
+		cm.notifyError("Failed to start UI spectrogram generation", err)
+		return
+	}
+	cm.notifySuccess("UI spectrogram generation enabled")
+}
+
 // handleReconfigureTelemetry reconfigures the telemetry/metrics endpoint
 func (cm *ControlMonitor) handleReconfigureTelemetry() {
 	GetLogger().Info("Reconfiguring telemetry endpoint")
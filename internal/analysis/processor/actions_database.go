@@ -226,6 +226,24 @@ func isEOFError(err error) bool {
 	return strings.Contains(strings.ToLower(err.Error()), "eof")
 }
 
+// isNewSpeciesNotificationExcluded reports whether an exclude entry matches the species by
+// common name, scientific name or genus (the first word of the scientific name).
+// Matching is case-insensitive.
+func isNewSpeciesNotificationExcluded(exclude []string, commonName, scientificName string) bool {
+	genus, _, _ := strings.Cut(strings.TrimSpace(scientificName), " ")
+	for _, entry := range exclude {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.EqualFold(entry, commonName) || strings.EqualFold(entry, scientificName) ||
+			(genus != "" && strings.EqualFold(entry, genus)) {
+			return true
+		}
+	}
+	return false
+}
+
 // shouldSuppressNewSpeciesNotification checks if a new species notification should be suppressed.
 // Returns true if notification should be suppressed, along with the notification time.
 func (a *DatabaseAction) shouldSuppressNewSpeciesNotification() (suppress bool, notificationTime time.Time) {
@@ -295,6 +313,10 @@ func (a *DatabaseAction) populateEventMetadata(detectionEvent events.DetectionEv
 	metadata["latitude"] = a.Result.Latitude
 	metadata["longitude"] = a.Result.Longitude
 	metadata["begin_time"] = a.Result.BeginTime
+	if entry, ok := lookupLifeList(a.Result.Species.ScientificName); ok {
+		metadata["life_list_status"] = string(entry.Status)
+		metadata["heard_only"] = entry.Status == LifeListStatusHeard
+	}
 
 	if a.processor != nil && a.processor.BirdImageCache != nil {
 		if birdImage, err := a.processor.BirdImageCache.Get(a.Result.Species.ScientificName); err == nil && birdImage.URL != "" {
@@ -328,11 +350,41 @@ func (a *DatabaseAction) publishNewSpeciesDetectionEvent(isNewSpecies bool, days
 		return
 	}
 
+	// The species is already tracked at this point; only the notification is skipped
+	if isNewSpeciesNotificationExcluded(a.Settings.Realtime.SpeciesTracking.NotificationExclude,
+		a.Result.Species.CommonName, a.Result.Species.ScientificName) {
+		if a.Settings.Debug {
+			GetLogger().Debug("New species notification disabled for species",
+				logger.String("component", "analysis.processor.actions"),
+				logger.String("detection_id", a.CorrelationID),
+				logger.String("species", a.Result.Species.CommonName),
+				logger.String("scientific_name", a.Result.Species.ScientificName),
+				logger.String("operation", "exclude_notification"))
+		}
+		return
+	}
+
 	suppress, notificationTime := a.shouldSuppressNewSpeciesNotification()
 	if suppress {
 		return
 	}
 
+	// Species tracking persists asynchronously; the announcement cache is written before a
+	// crash could lose it, so a restart doesn't announce the same species again
+	var announcements *recentAnnouncements
+	if a.processor != nil {
+		announcements = a.processor.announcements
+	}
+	announcedAt := a.Result.BeginTime
+	if announcements.announcedRecently(a.Result.Species.ScientificName, announcedAt) {
+		GetLogger().Debug("Species was announced recently, skipping new species event",
+			logger.String("component", "analysis.processor.actions"),
+			logger.String("detection_id", a.CorrelationID),
+			logger.String("scientific_name", a.Result.Species.ScientificName),
+			logger.String("operation", "recent_announcement_dedup"))
+		return
+	}
+
 	eventBus := events.GetEventBus()
 	if eventBus == nil {
 		return
@@ -347,6 +399,7 @@ func (a *DatabaseAction) publishNewSpeciesDetectionEvent(isNewSpecies bool, days
 
 	if published := eventBus.TryPublishDetection(detectionEvent); published {
 		a.recordNotificationSent(notificationTime)
+		announcements.record(a.Result.Species.ScientificName, announcedAt)
 	}
 }
 
@@ -26,9 +26,10 @@ import (
 // See: https://github.com/tphakala/birdnet-go/discussions/1759
 type NoteWithBirdImage struct {
 	datastore.Note
-	DetectionID uint                    `json:"detectionId"` // Database ID for URL construction (e.g., /api/v2/audio/{id})
-	SourceID    string                  `json:"sourceId"`    // Audio source ID for HA filtering (added for HA discovery)
-	BirdImage   imageprovider.BirdImage `json:"BirdImage"`   // PascalCase for backward compatibility - DO NOT CHANGE
+	DetectionID uint                    `json:"detectionId"`          // Database ID for URL construction (e.g., /api/v2/audio/{id})
+	SourceID    string                  `json:"sourceId"`             // Audio source ID for HA filtering (added for HA discovery)
+	BirdImage   imageprovider.BirdImage `json:"BirdImage"`            // PascalCase for backward compatibility - DO NOT CHANGE
+	Candidates  []DetectionCandidate    `json:"candidates,omitempty"` // Most probable species of the window, when BirdNET.TopK > 1
 }
 
 // Execute sends the note to the BirdWeather API
@@ -214,6 +215,7 @@ func (a *MqttAction) Execute(_ context.Context, data any) error {
 		DetectionID: detectionID, // Explicit field for URL construction (e.g., /api/v2/audio/{id})
 		SourceID:    note.Source.ID,
 		BirdImage:   birdImage,
+		Candidates:  a.Candidates,
 	}
 
 	// Create a JSON representation of the note
@@ -158,6 +158,7 @@ type MqttAction struct {
 	EventTracker   *EventTracker
 	DetectionCtx   *DetectionContext    // Shared context from DatabaseAction
 	RetryConfig    jobqueue.RetryConfig // Configuration for retry behavior
+	Candidates     []DetectionCandidate // Top-K species of the analysis window, empty unless BirdNET.TopK > 1
 	Description    string
 	CorrelationID  string     // Detection correlation ID for log tracking
 	mu             sync.Mutex // Protect concurrent access to Result
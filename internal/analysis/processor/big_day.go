@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// BigDaySpecies is one species detected during a big day.
+type BigDaySpecies struct {
+	ScientificName string    `json:"scientificName"`
+	CommonName     string    `json:"commonName,omitempty"`
+	FirstDetected  time.Time `json:"firstDetected"`
+}
+
+// BigDaySummary lists every species detected on one day, in the order they were first detected.
+type BigDaySummary struct {
+	Day       string          `json:"day"`       // Local date the species were detected, YYYY-MM-DD
+	CreatedAt time.Time       `json:"createdAt"` // When the summary was taken
+	Species   []BigDaySpecies `json:"species"`
+}
+
+// bigDayMu serializes reads and appends of the big day summary file.
+var bigDayMu sync.Mutex
+
+// startBigDay saves each day's species to the big day file when the day rolls over.
+func (p *Processor) startBigDay(settings *conf.Settings) {
+	if !settings.SoundId.BigDayEnabled {
+		return
+	}
+	path := settings.SoundId.BigDayPath
+	p.seenToday.setOnDayEnd(func(summary BigDaySummary) {
+		if err := appendBigDaySummary(path, &summary); err != nil {
+			GetLogger().Warn("Failed to save big day summary",
+				logger.String("day", summary.Day),
+				logger.String("path", path),
+				logger.Error(err),
+				logger.String("operation", "big_day_save"))
+			return
+		}
+		GetLogger().Info("Saved big day summary",
+			logger.String("day", summary.Day),
+			logger.Int("species", len(summary.Species)),
+			logger.String("operation", "big_day_save"))
+	})
+}
+
+// CompleteBigDay takes a summary of the species detected today, saves it and starts the
+// day's species over, so a big day can be closed before midnight.
+func (p *Processor) CompleteBigDay(now time.Time) (BigDaySummary, error) {
+	summary := p.seenToday.take(now)
+	if err := appendBigDaySummary(p.Settings.SoundId.BigDayPath, &summary); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// BigDaySummaries returns the saved big day summaries, oldest first.
+func (p *Processor) BigDaySummaries() ([]BigDaySummary, error) {
+	bigDayMu.Lock()
+	defer bigDayMu.Unlock()
+	return readBigDaySummaries(p.Settings.SoundId.BigDayPath)
+}
+
+// appendBigDaySummary adds a summary to the big day file.
+func appendBigDaySummary(path string, summary *BigDaySummary) error {
+	if path == "" {
+		return errors.Newf("big day summary path is not set in the configuration").
+			Component("big_day").
+			Category(errors.CategoryConfiguration).
+			Build()
+	}
+
+	bigDayMu.Lock()
+	defer bigDayMu.Unlock()
+
+	summaries, err := readBigDaySummaries(path)
+	if err != nil {
+		return err
+	}
+	summaries = append(summaries, *summary)
+	if err := writeJSONFile(path, summaries); err != nil {
+		return errors.New(err).
+			Component("big_day").
+			Category(errors.CategoryFileIO).
+			Context("operation", "write_summaries").
+			Build()
+	}
+	return nil
+}
+
+// readBigDaySummaries reads the big day file. A missing file holds no summaries. Callers hold bigDayMu.
+func readBigDaySummaries(path string) ([]BigDaySummary, error) {
+	summaries := []BigDaySummary{}
+	if path == "" {
+		return summaries, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return summaries, nil
+	}
+	if err != nil {
+		return nil, errors.New(err).
+			Component("big_day").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_summaries").
+			Build()
+	}
+	if err := json.Unmarshal(data, &summaries); err != nil {
+		return nil, errors.New(err).
+			Component("big_day").
+			Category(errors.CategoryFileParsing).
+			Context("operation", "parse_summaries").
+			Build()
+	}
+	return summaries, nil
+}
@@ -0,0 +1,68 @@
+// big_day_test.go: Tests for big day summaries of the daily species set
+package processor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestCompleteBigDay_CapturesAndResetsDailySet(t *testing.T) {
+	t.Parallel()
+
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		BigDayPath: filepath.Join(t.TempDir(), "bigday.json"),
+	}}}
+	dawn := time.Date(2026, 5, 15, 5, 0, 0, 0, time.Local)
+	p.seenToday.add("Erithacus rubecula", "European Robin", dawn.Add(10*time.Minute))
+	p.seenToday.add("Turdus merula", "Eurasian Blackbird", dawn)
+	p.seenToday.add("turdus merula", "Eurasian Blackbird", dawn.Add(time.Hour))
+
+	evening := dawn.Add(15 * time.Hour)
+	summary, err := p.CompleteBigDay(evening)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-05-15", summary.Day)
+	assert.True(t, summary.CreatedAt.Equal(evening))
+	require.Len(t, summary.Species, 2)
+	assert.Equal(t, "Turdus merula", summary.Species[0].ScientificName, "species are listed in detection order")
+	assert.True(t, summary.Species[0].FirstDetected.Equal(dawn), "the first detection of the day is kept")
+	assert.Equal(t, "European Robin", summary.Species[1].CommonName)
+
+	// The daily set starts over once the summary is taken
+	assert.Zero(t, p.seenToday.count(evening))
+
+	saved, err := p.BigDaySummaries()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "2026-05-15", saved[0].Day)
+	assert.Len(t, saved[0].Species, 2)
+}
+
+func TestBigDay_SavedAtMidnightRollover(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		BigDayEnabled: true,
+		BigDayPath:    filepath.Join(t.TempDir(), "bigday.json"),
+	}}
+	p := &Processor{Settings: settings}
+	p.startBigDay(settings)
+
+	day := time.Date(2026, 5, 15, 8, 0, 0, 0, time.Local)
+	p.seenToday.add("Parus major", "Great Tit", day)
+	p.seenToday.add("Apus apus", "Common Swift", day.Add(time.Hour))
+
+	// The first detection after midnight closes the previous day
+	p.seenToday.add("Turdus merula", "Eurasian Blackbird", day.AddDate(0, 0, 1))
+
+	saved, err := p.BigDaySummaries()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "2026-05-15", saved[0].Day)
+	assert.Len(t, saved[0].Species, 2)
+	assert.Equal(t, 1, p.seenToday.count(day.AddDate(0, 0, 1)))
+}
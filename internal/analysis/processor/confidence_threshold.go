@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// ConfidenceThreshold returns the global confidence threshold applied to species without a
+// custom threshold.
+func (p *Processor) ConfidenceThreshold() float64 {
+	p.globalThresholdMu.RLock()
+	defer p.globalThresholdMu.RUnlock()
+	return p.Settings.BirdNET.Threshold
+}
+
+// SetConfidenceThreshold changes the global confidence threshold at runtime. It applies to
+// every detection processed after it returns; detections already pending keep the
+// threshold they passed. The value must be between 0 and 1.
+func (p *Processor) SetConfidenceThreshold(threshold float64) error {
+	if threshold < 0 || threshold > 1 {
+		return errors.Newf("confidence threshold must be between 0 and 1").
+			Component("analysis.processor").
+			Category(errors.CategoryValidation).
+			Context("operation", "set_confidence_threshold").
+			Context("threshold", threshold).
+			Build()
+	}
+
+	p.globalThresholdMu.Lock()
+	previous := p.Settings.BirdNET.Threshold
+	p.Settings.BirdNET.Threshold = threshold
+	p.globalThresholdMu.Unlock()
+
+	GetLogger().Info("Global confidence threshold changed",
+		logger.Float64("previous_threshold", previous),
+		logger.Float64("threshold", threshold),
+		logger.String("operation", "set_confidence_threshold"))
+	return nil
+}
@@ -0,0 +1,40 @@
+// detection_candidates.go: top-K species candidates attached to detection events
+package processor
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+// DetectionCandidate is one of the most likely species for the analysis window a
+// detection came from, so consumers can see near-misses alongside the winner.
+type DetectionCandidate struct {
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Confidence     float64 `json:"confidence"`
+}
+
+// detectionCandidates returns the k most probable species from a window's results, most
+// probable first. It returns nil when k is 1 or less, as the winner is already the detection.
+func detectionCandidates(results []detection.AdditionalResult, k int) []DetectionCandidate {
+	if k <= 1 || len(results) == 0 {
+		return nil
+	}
+
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b detection.AdditionalResult) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+
+	candidates := make([]DetectionCandidate, 0, min(k, len(sorted)))
+	for _, r := range sorted[:min(k, len(sorted))] {
+		candidates = append(candidates, DetectionCandidate{
+			ScientificName: r.Species.ScientificName,
+			CommonName:     r.Species.CommonName,
+			Confidence:     r.Confidence,
+		})
+	}
+	return candidates
+}
@@ -0,0 +1,58 @@
+// detection_candidates_test.go: Tests for top-K species candidates in detection events
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+func TestMqttAction_Execute_IncludesTopKCandidates(t *testing.T) {
+	t.Parallel()
+
+	mockClient := NewMockMQTTClient()
+	settings := &conf.Settings{}
+	settings.Realtime.MQTT.Topic = testMQTTTopic
+	settings.BirdNET.TopK = 3
+
+	// Window results arrive in model order, not sorted by probability
+	results := []detection.AdditionalResult{
+		{Species: detection.Species{ScientificName: "Cyanistes caeruleus", CommonName: "Blue Tit"}, Confidence: 0.35},
+		{Species: detection.Species{ScientificName: "Parus major", CommonName: "Great Tit"}, Confidence: 0.91},
+		{Species: detection.Species{ScientificName: "Periparus ater", CommonName: "Coal Tit"}, Confidence: 0.05},
+		{Species: detection.Species{ScientificName: "Poecile palustris", CommonName: "Marsh Tit"}, Confidence: 0.52},
+	}
+
+	det := testDetection()
+	action := &MqttAction{
+		Settings:     settings,
+		Result:       det.Result,
+		MqttClient:   mockClient,
+		EventTracker: NewEventTracker(testEventTrackerInterval),
+		Candidates:   detectionCandidates(results, settings.BirdNET.TopK),
+	}
+	require.NoError(t, action.Execute(context.Background(), nil))
+
+	var payload NoteWithBirdImage
+	require.NoError(t, json.Unmarshal([]byte(mockClient.GetPublishedPayload()), &payload))
+	require.Len(t, payload.Candidates, 3, "K candidates are included")
+	assert.Equal(t, []string{"Parus major", "Poecile palustris", "Cyanistes caeruleus"},
+		[]string{payload.Candidates[0].ScientificName, payload.Candidates[1].ScientificName, payload.Candidates[2].ScientificName})
+	for i := 1; i < len(payload.Candidates); i++ {
+		assert.GreaterOrEqual(t, payload.Candidates[i-1].Confidence, payload.Candidates[i].Confidence, "candidates are sorted by probability")
+	}
+}
+
+func TestDetectionCandidates_WinnerOnlyWhenKIsOne(t *testing.T) {
+	t.Parallel()
+
+	results := []detection.AdditionalResult{{Species: detection.Species{ScientificName: "Parus major"}, Confidence: 0.9}}
+	assert.Nil(t, detectionCandidates(results, 0))
+	assert.Nil(t, detectionCandidates(results, 1))
+	assert.Len(t, detectionCandidates(results, 5), 1, "K larger than the results returns them all")
+}
@@ -0,0 +1,197 @@
+// detection_digest.go: periodic digest notifications summarizing recent detections
+package processor
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// defaultDigestInterval is used when no positive digest interval is configured
+const defaultDigestInterval = 15 * time.Minute
+
+// DigestSpecies is one species in a detection digest.
+type DigestSpecies struct {
+	ScientificName string `json:"scientificName"`
+	CommonName     string `json:"commonName"`
+	Count          int    `json:"count"`
+	Lifer          bool   `json:"lifer"` // Not on the life list, or only heard, when detected
+}
+
+// DetectionDigest summarizes the detections of one digest interval.
+type DetectionDigest struct {
+	Start      time.Time       `json:"start"`
+	End        time.Time       `json:"end"`
+	Detections int             `json:"detections"`
+	Species    []DigestSpecies `json:"species"` // Most detected first
+}
+
+// detectionDigest collects approved detections and emits one digest per interval. An interval
+// without detections emits nothing.
+type detectionDigest struct {
+	interval time.Duration
+	emit     func(DetectionDigest)
+
+	mu      sync.Mutex
+	start   time.Time
+	species map[string]*DigestSpecies // Keyed by lowercase scientific name
+	wg      sync.WaitGroup
+}
+
+// newDetectionDigest creates a digest that hands each interval's summary to emit.
+func newDetectionDigest(interval time.Duration, emit func(DetectionDigest)) *detectionDigest {
+	if interval <= 0 {
+		interval = defaultDigestInterval
+	}
+	return &detectionDigest{
+		interval: interval,
+		emit:     emit,
+		start:    time.Now(),
+		species:  make(map[string]*DigestSpecies),
+	}
+}
+
+// add counts a detection. A species is flagged as a lifer if any of its detections was.
+func (d *detectionDigest) add(scientificName, commonName string, lifer bool) {
+	key := strings.ToLower(scientificName)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.species[key]
+	if !ok {
+		entry = &DigestSpecies{ScientificName: scientificName, CommonName: commonName}
+		d.species[key] = entry
+	}
+	entry.Count++
+	entry.Lifer = entry.Lifer || lifer
+}
+
+// flush emits the digest of the detections collected since the last flush and starts a new
+// interval at end. It reports whether a digest was emitted.
+func (d *detectionDigest) flush(end time.Time) bool {
+	d.mu.Lock()
+	start, collected := d.start, d.species
+	d.start, d.species = end, make(map[string]*DigestSpecies)
+	d.mu.Unlock()
+
+	if len(collected) == 0 {
+		return false
+	}
+
+	digest := DetectionDigest{Start: start, End: end, Species: make([]DigestSpecies, 0, len(collected))}
+	for _, entry := range collected {
+		digest.Species = append(digest.Species, *entry)
+		digest.Detections += entry.Count
+	}
+	slices.SortFunc(digest.Species, func(a, b DigestSpecies) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CommonName, b.CommonName)
+	})
+	d.emit(digest)
+	return true
+}
+
+// run flushes every interval until ctx is cancelled, then flushes once more so detections
+// collected before shutdown aren't lost.
+func (d *detectionDigest) run(ctx context.Context) {
+	d.wg.Go(func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				d.flush(time.Now())
+				return
+			case now := <-ticker.C:
+				d.flush(now)
+			}
+		}
+	})
+}
+
+// wait blocks until run has returned and its final digest was emitted.
+func (d *detectionDigest) wait() {
+	d.wg.Wait()
+}
+
+// startDetectionDigest starts the digest schedule when digests are enabled.
+func (p *Processor) startDetectionDigest(settings *conf.Settings) {
+	if !settings.Notification.Digest.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.digest = newDetectionDigest(time.Duration(settings.Notification.Digest.Interval)*time.Minute, notifyDetectionDigest)
+	p.digestCancel = cancel
+	p.digest.run(ctx)
+}
+
+// addToDetectionDigest counts an approved detection in the digest, if digests are enabled.
+// It must run before the detection is added to the life list as heard, so a lifer is
+// still flagged as one.
+func (p *Processor) addToDetectionDigest(scientificName, commonName string) {
+	if p.digest == nil || scientificName == "" {
+		return
+	}
+	p.digest.add(scientificName, commonName, isLifer(scientificName))
+}
+
+// isLifer reports whether scientificName would be a lifer: a life list is loaded and the
+// species isn't on it as seen.
+func isLifer(scientificName string) bool {
+	entry, found, loaded := loadedLifeList.Lookup(scientificName)
+	return loaded && (!found || entry.Status == LifeListStatusHeard)
+}
+
+// notifyDetectionDigest sends a digest as a single detection notification.
+func notifyDetectionDigest(digest DetectionDigest) {
+	if !notification.IsInitialized() {
+		return
+	}
+	service := notification.GetService()
+	if service == nil {
+		return
+	}
+
+	minutes := int(digest.End.Sub(digest.Start).Round(time.Minute).Minutes())
+	title := fmt.Sprintf("%d species in the last %d minutes", len(digest.Species), minutes)
+
+	lines := make([]string, 0, len(digest.Species))
+	lifers := 0
+	for _, s := range digest.Species {
+		line := fmt.Sprintf("%s ×%d", s.CommonName, s.Count)
+		if s.Lifer {
+			line += " (lifer!)"
+			lifers++
+		}
+		lines = append(lines, line)
+	}
+
+	n := notification.NewNotification(notification.TypeDetection, notification.PriorityLow, title, strings.Join(lines, "\n")).
+		WithComponent("detection-digest").
+		WithMetadata("digest", digest)
+	if err := service.CreateWithMetadata(n); err != nil {
+		GetLogger().Warn("Failed to send detection digest",
+			logger.Error(err),
+			logger.String("operation", "detection_digest"))
+		return
+	}
+
+	GetLogger().Info("Sent detection digest",
+		logger.Int("species", len(digest.Species)),
+		logger.Int("detections", digest.Detections),
+		logger.Int("lifers", lifers),
+		logger.String("operation", "detection_digest"))
+}
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectionDigest_AggregatesInterval(t *testing.T) {
+	t.Parallel()
+
+	var digests []DetectionDigest
+	digest := newDetectionDigest(time.Hour, func(d DetectionDigest) { digests = append(digests, d) })
+
+	digest.add("Turdus migratorius", "American Robin", false)
+	digest.add("Cardinalis cardinalis", "Northern Cardinal", false)
+	digest.add("turdus migratorius", "American Robin", false)
+	digest.add("Setophaga kirtlandii", "Kirtland's Warbler", true)
+	digest.add("Cardinalis cardinalis", "Northern Cardinal", false)
+	digest.add("Turdus migratorius", "American Robin", false)
+
+	end := time.Now()
+	require.True(t, digest.flush(end))
+	require.Len(t, digests, 1, "an interval's detections must produce a single digest")
+
+	got := digests[0]
+	assert.Equal(t, end, got.End)
+	assert.Equal(t, 6, got.Detections)
+	assert.Equal(t, []DigestSpecies{
+		{ScientificName: "Turdus migratorius", CommonName: "American Robin", Count: 3},
+		{ScientificName: "Cardinalis cardinalis", CommonName: "Northern Cardinal", Count: 2},
+		{ScientificName: "Setophaga kirtlandii", CommonName: "Kirtland's Warbler", Count: 1, Lifer: true},
+	}, got.Species)
+
+	// The next interval starts where the last one ended, and an empty one sends nothing
+	assert.False(t, digest.flush(end.Add(time.Hour)))
+	assert.Len(t, digests, 1)
+
+	digest.add("Cardinalis cardinalis", "Northern Cardinal", false)
+	require.True(t, digest.flush(end.Add(2*time.Hour)))
+	assert.Equal(t, end.Add(time.Hour), digests[1].Start)
+	assert.Equal(t, 1, digests[1].Detections)
+}
+
+func TestDetectionDigest_LiferFlagSticksWithinInterval(t *testing.T) {
+	t.Parallel()
+
+	var got DetectionDigest
+	digest := newDetectionDigest(time.Hour, func(d DetectionDigest) { got = d })
+
+	// The first detection is a lifer; later ones aren't once it was recorded as heard
+	digest.add("Strix varia", "Barred Owl", true)
+	digest.add("Strix varia", "Barred Owl", false)
+	digest.flush(time.Now())
+
+	require.Len(t, got.Species, 1)
+	assert.True(t, got.Species[0].Lifer)
+	assert.Equal(t, 2, got.Species[0].Count)
+}
+
+func TestDetectionDigest_FlushesOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	emitted := make(chan DetectionDigest, 1)
+	digest := newDetectionDigest(time.Hour, func(d DetectionDigest) { emitted <- d })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	digest.run(ctx)
+	digest.add("Bubo virginianus", "Great Horned Owl", false)
+	cancel()
+	digest.wait()
+
+	select {
+	case d := <-emitted:
+		assert.Equal(t, 1, d.Detections)
+	default:
+		t.Fatal("shutdown must send the detections collected so far")
+	}
+}
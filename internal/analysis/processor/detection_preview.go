@@ -0,0 +1,201 @@
+// detection_preview.go: dry-run classification of synthetic detections
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Detection preview stages, in the order the detection pipeline applies them
+const (
+	PreviewStagePaused        = "paused"
+	PreviewStageHumanPrivacy  = "human_privacy"
+	PreviewStageConfidence    = "confidence"
+	PreviewStageSpeciesFilter = "species_filter"
+	PreviewStageMinDetections = "min_detections"
+	PreviewStagePrivacyFilter = "privacy_filter"
+	PreviewStageDogBark       = "dog_bark_filter"
+	PreviewStageCooldown      = "cooldown"
+	PreviewStageLifeList      = "life_list"
+)
+
+// DetectionPreviewInput is a synthetic detection to run through the decision logic.
+type DetectionPreviewInput struct {
+	ScientificName string
+	CommonName     string
+	Confidence     float64
+	Time           time.Time // Zero for now
+	Source         string
+}
+
+// DetectionPreviewStep is the outcome of one stage of the decision logic.
+type DetectionPreviewStep struct {
+	Stage  string `json:"stage"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+// DetectionPreview traces how a synthetic detection would be handled. The trace stops at
+// the first stage that drops the detection, as the pipeline would.
+type DetectionPreview struct {
+	Passed    bool                   `json:"passed"`
+	DroppedBy string                 `json:"droppedBy,omitempty"` // Stage that dropped the detection
+	Threshold float64                `json:"threshold"`           // Confidence threshold in effect for the species
+	Trace     []DetectionPreviewStep `json:"trace"`
+}
+
+// step appends a stage outcome, marking the preview dropped when the stage failed.
+func (d *DetectionPreview) step(stage string, passed bool, format string, args ...any) bool {
+	d.Trace = append(d.Trace, DetectionPreviewStep{Stage: stage, Passed: passed, Detail: fmt.Sprintf(format, args...)})
+	if !passed {
+		d.Passed = false
+		d.DroppedBy = stage
+	}
+	return passed
+}
+
+// PreviewDetection runs a synthetic detection through the filter chain, thresholds, cooldown
+// and life list checks without changing any state: dynamic thresholds aren't learned or
+// expired, cooldowns aren't started and nothing is queued or saved.
+func (p *Processor) PreviewDetection(input DetectionPreviewInput) (*DetectionPreview, error) {
+	if strings.TrimSpace(input.ScientificName) == "" {
+		return nil, errors.Newf("scientific name is required").
+			Component("analysis.processor").
+			Category(errors.CategoryValidation).
+			Context("operation", "preview_detection").
+			Build()
+	}
+	if input.Confidence < 0 || input.Confidence > 1 {
+		return nil, errors.Newf("confidence must be between 0 and 1").
+			Component("analysis.processor").
+			Category(errors.CategoryValidation).
+			Context("operation", "preview_detection").
+			Context("confidence", input.Confidence).
+			Build()
+	}
+	at := input.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	commonName, scientificName := input.CommonName, input.ScientificName
+	speciesLowercase := strings.ToLower(commonName)
+	if speciesLowercase == "" {
+		speciesLowercase = strings.ToLower(scientificName)
+	}
+	preview := &DetectionPreview{Passed: true}
+
+	paused := p.IsDetectionPaused()
+	if !preview.step(PreviewStagePaused, !paused, "detection processing is %s", pausedState(paused)) {
+		return preview, nil
+	}
+
+	baseThreshold := p.getBaseConfidenceThreshold(commonName, scientificName)
+	if strings.Contains(speciesLowercase, speciesHuman) && float32(input.Confidence) > baseThreshold {
+		preview.step(PreviewStageHumanPrivacy, false, "human vocalizations are never recorded")
+		return preview, nil
+	}
+
+	threshold := p.previewConfidenceThreshold(commonName, scientificName, speciesLowercase, baseThreshold)
+	preview.Threshold = float64(threshold)
+	if !preview.step(PreviewStageConfidence, float32(input.Confidence) >= threshold,
+		"confidence %.2f against threshold %.2f", input.Confidence, threshold) {
+		return preview, nil
+	}
+
+	included := p.Settings.IsSpeciesIncluded(scientificName)
+	if !preview.step(PreviewStageSpeciesFilter, included,
+		"species %s the include list for the current location and date", includedState(included)) {
+		return preview, nil
+	}
+
+	// A single synthetic detection can't show overlap confirmations, so this stage only reports the requirement
+	preview.step(PreviewStageMinDetections, true, "needs %d matching analyses within the detection window", p.calculateMinDetections())
+
+	if p.Settings.Realtime.PrivacyFilter.Enabled {
+		p.detectionMutex.RLock()
+		lastHuman, exists := p.LastHumanDetection[input.Source]
+		p.detectionMutex.RUnlock()
+		if !preview.step(PreviewStagePrivacyFilter, !exists || !lastHuman.After(at),
+			"last human detection on this source: %s", formatPreviewTime(lastHuman, exists)) {
+			return preview, nil
+		}
+	}
+
+	if p.Settings.Realtime.DogBarkFilter.Enabled {
+		p.detectionMutex.RLock()
+		lastDog, exists := p.LastDogDetection[input.Source]
+		p.detectionMutex.RUnlock()
+		barkFiltered := p.CheckDogBarkFilter(commonName, lastDog) || p.CheckDogBarkFilter(scientificName, lastDog)
+		if !preview.step(PreviewStageDogBark, !barkFiltered,
+			"last dog bark on this source: %s", formatPreviewTime(lastDog, exists)) {
+			return preview, nil
+		}
+	}
+
+	if tracker := p.GetEventTracker(); tracker != nil {
+		allowed, remaining := tracker.PeekEventWithNames(commonName, scientificName, DatabaseSave, at)
+		if !preview.step(PreviewStageCooldown, allowed, "%s", cooldownDetail(allowed, remaining)) {
+			return preview, nil
+		}
+	}
+
+	if entry, ok := lookupLifeList(scientificName); ok {
+		preview.step(PreviewStageLifeList, true, "on the life list with status %s", entry.Status)
+	} else {
+		preview.step(PreviewStageLifeList, true, "not on the life list, would be a new species")
+	}
+
+	return preview, nil
+}
+
+// previewConfidenceThreshold returns the threshold getAdjustedConfidenceThreshold would
+// apply, without adding, expiring or clamping the species' dynamic threshold.
+func (p *Processor) previewConfidenceThreshold(commonName, scientificName, speciesLowercase string, baseThreshold float32) float32 {
+	if !p.Settings.Realtime.DynamicThreshold.Enabled {
+		return baseThreshold
+	}
+	if config, exists := lookupSpeciesConfig(p.Settings.Realtime.Species.Config, commonName, scientificName); exists && config.Threshold > 0 {
+		return baseThreshold
+	}
+
+	p.thresholdsMutex.RLock()
+	defer p.thresholdsMutex.RUnlock()
+
+	dt, exists := p.DynamicThresholds[speciesLowercase]
+	if !exists || time.Now().After(dt.Timer) {
+		return baseThreshold
+	}
+	return float32(max(dt.CurrentValue, p.Settings.Realtime.DynamicThreshold.Min))
+}
+
+func pausedState(paused bool) string {
+	if paused {
+		return "paused"
+	}
+	return "running"
+}
+
+func includedState(included bool) string {
+	if included {
+		return "is on"
+	}
+	return "is not on"
+}
+
+func formatPreviewTime(t time.Time, exists bool) string {
+	if !exists || t.IsZero() {
+		return "none"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func cooldownDetail(allowed bool, remaining time.Duration) string {
+	if allowed {
+		return "no recent detection of this species is holding a cooldown"
+	}
+	return fmt.Sprintf("species was saved recently, cooldown has %s remaining", remaining.Round(time.Second))
+}
@@ -0,0 +1,60 @@
+// detection_preview_test.go: Tests for dry-run classification of synthetic detections
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewDetection_BelowThresholdDroppedByConfidence(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.BirdNET.Threshold = 0.8
+
+	preview, err := p.PreviewDetection(DetectionPreviewInput{
+		ScientificName: "Parus major",
+		CommonName:     "Great Tit",
+		Confidence:     0.5,
+		Source:         "mic",
+	})
+	require.NoError(t, err)
+
+	assert.False(t, preview.Passed)
+	assert.Equal(t, PreviewStageConfidence, preview.DroppedBy)
+	assert.InDelta(t, 0.8, preview.Threshold, 0.0001)
+	require.NotEmpty(t, preview.Trace)
+	last := preview.Trace[len(preview.Trace)-1]
+	assert.Equal(t, PreviewStageConfidence, last.Stage)
+	assert.False(t, last.Passed)
+	assert.Contains(t, last.Detail, "0.50")
+}
+
+func TestPreviewDetection_ReportsCooldownWithoutStartingOne(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.BirdNET.Threshold = 0.8
+	p.Settings.BirdNET.RangeFilter.Species = []string{"Parus major_Great Tit"}
+	p.EventTracker = NewEventTracker(time.Minute)
+
+	input := DetectionPreviewInput{ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.9, Source: "mic"}
+	for range 2 {
+		preview, err := p.PreviewDetection(input)
+		require.NoError(t, err)
+		assert.True(t, preview.Passed, "a preview must not start the cooldown it checks")
+		assert.Equal(t, PreviewStageLifeList, preview.Trace[len(preview.Trace)-1].Stage)
+	}
+
+	require.True(t, p.EventTracker.TrackEventWithNames("Great Tit", "Parus major", DatabaseSave))
+	preview, err := p.PreviewDetection(input)
+	require.NoError(t, err)
+	assert.False(t, preview.Passed)
+	assert.Equal(t, PreviewStageCooldown, preview.DroppedBy)
+}
+
+func TestPreviewDetection_RequiresSpecies(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+
+	_, err := p.PreviewDetection(DetectionPreviewInput{Confidence: 0.9})
+	require.Error(t, err)
+}
@@ -0,0 +1,317 @@
+// detection_sinks.go: fan-out of approved detections to registered sinks
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// defaultDetectionSinkTimeout bounds how long one sink may take to accept a detection when
+// its registration doesn't set a timeout.
+const defaultDetectionSinkTimeout = 10 * time.Second
+
+// maxDetectionSinkReplay bounds how many stored detections one replay re-delivers.
+const maxDetectionSinkReplay = 100
+
+// Detection sink types and filters of the configured sinks
+const (
+	DetectionSinkTypeWebhook = "webhook"
+
+	DetectionSinkFilterAll    = "all"
+	DetectionSinkFilterLifers = "lifers"
+)
+
+// DetectionSinkEvent is an approved detection as delivered to sinks.
+type DetectionSinkEvent struct {
+	CorrelationID string
+	Result        detection.Result
+	Lifer         bool // The species wasn't seen on the life list before this detection
+	Replay        bool // A stored detection delivered again by ReplayDetections, not a new one
+}
+
+// DetectionSink is a destination approved detections are sent to, such as a webhook.
+// Send must return once ctx is done.
+type DetectionSink interface {
+	Name() string
+	Send(ctx context.Context, event *DetectionSinkEvent) error
+}
+
+// DetectionSinkFilter selects the detections a sink receives. A nil filter accepts all.
+type DetectionSinkFilter func(event *DetectionSinkEvent) bool
+
+// LifersOnly is a DetectionSinkFilter accepting only detections of lifers.
+func LifersOnly(event *DetectionSinkEvent) bool {
+	return event.Lifer
+}
+
+// registeredSink is a sink with the filter and timeout it was registered with.
+type registeredSink struct {
+	sink    DetectionSink
+	filter  DetectionSinkFilter
+	timeout time.Duration
+}
+
+// detectionSinks is the registry of sinks detections fan out to. Deliveries run in their own
+// goroutines, tracked so shutdown can wait for them.
+type detectionSinks struct {
+	mu    sync.RWMutex
+	sinks []registeredSink
+	wg    sync.WaitGroup
+}
+
+// RegisterDetectionSink adds a sink that receives the approved detections accepted by filter,
+// each delivery bounded by timeout, or a default timeout when it is zero. Sink names must
+// be unique.
+func (p *Processor) RegisterDetectionSink(sink DetectionSink, filter DetectionSinkFilter, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultDetectionSinkTimeout
+	}
+
+	p.sinks.mu.Lock()
+	defer p.sinks.mu.Unlock()
+	for _, registered := range p.sinks.sinks {
+		if registered.sink.Name() == sink.Name() {
+			return errors.Newf("detection sink %q is already registered", sink.Name()).
+				Component("analysis.processor").
+				Category(errors.CategoryValidation).
+				Context("sink", sink.Name()).
+				Build()
+		}
+	}
+	p.sinks.sinks = append(p.sinks.sinks, registeredSink{sink: sink, filter: filter, timeout: timeout})
+	return nil
+}
+
+// UnregisterDetectionSink removes the sink with the given name, reporting whether it was
+// registered.
+func (p *Processor) UnregisterDetectionSink(name string) bool {
+	p.sinks.mu.Lock()
+	defer p.sinks.mu.Unlock()
+	for i, registered := range p.sinks.sinks {
+		if registered.sink.Name() == name {
+			p.sinks.sinks = append(p.sinks.sinks[:i:i], p.sinks.sinks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch sends event to every sink whose filter accepts it, concurrently and without
+// waiting for them, so a slow sink delays neither the others nor the detection pipeline.
+// Delivery failures are logged.
+func (s *detectionSinks) dispatch(event *DetectionSinkEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, registered := range s.sinks {
+		if registered.filter != nil && !registered.filter(event) {
+			continue
+		}
+		s.wg.Go(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), registered.timeout)
+			defer cancel()
+			if err := registered.sink.Send(ctx, event); err != nil {
+				GetLogger().Warn("Detection sink delivery failed",
+					logger.String("sink", registered.sink.Name()),
+					logger.String("species", event.Result.Species.ScientificName),
+					logger.String("correlation_id", event.CorrelationID),
+					logger.Error(err),
+					logger.String("operation", "detection_sink_send"))
+			}
+		})
+	}
+}
+
+// wait blocks until the deliveries in flight finished. Each is bounded by its sink's timeout.
+func (s *detectionSinks) wait() {
+	s.wg.Wait()
+}
+
+// dispatchToSinks sends an approved detection to the registered sinks. It must run before
+// the detection is added to the life list as heard, so a lifer is still flagged as one.
+func (p *Processor) dispatchToSinks(det *Detections) {
+	p.sinks.dispatch(&DetectionSinkEvent{
+		CorrelationID: det.CorrelationID,
+		Result:        det.Result,
+		Lifer:         isLifer(det.Result.Species.ScientificName),
+	})
+}
+
+// ReplayDetections re-delivers the count most recent stored detections to the registered
+// sinks, oldest first, with each sink's filter applied as for new detections. The events are
+// flagged as replays so receivers can tell them from new detections. Lifer status is judged
+// against the current life list, so species only heard so far still count as lifers. It
+// returns how many detections were replayed; deliveries continue in the background.
+func (p *Processor) ReplayDetections(ctx context.Context, count int) (int, error) {
+	if count < 1 || count > maxDetectionSinkReplay {
+		return 0, errors.Newf("replay count must be between 1 and %d, got %d", maxDetectionSinkReplay, count).
+			Component("analysis.processor").
+			Category(errors.CategoryValidation).
+			Context("count", count).
+			Build()
+	}
+	if p.Repo == nil {
+		return 0, errors.Newf("no detection repository to replay from").
+			Component("analysis.processor").
+			Category(errors.CategoryState).
+			Build()
+	}
+
+	results, err := p.Repo.GetRecent(ctx, count)
+	if err != nil {
+		return 0, errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryDatabase).
+			Context("operation", "replay_detections").
+			Build()
+	}
+
+	// GetRecent returns the newest first
+	for i := len(results) - 1; i >= 0; i-- {
+		result := results[i]
+		p.sinks.dispatch(&DetectionSinkEvent{
+			Result: *result,
+			Lifer:  isLifer(result.Species.ScientificName),
+			Replay: true,
+		})
+	}
+
+	GetLogger().Info("Replayed stored detections to sinks",
+		logger.Int("requested", count),
+		logger.Int("replayed", len(results)),
+		logger.String("operation", "detection_sink_replay"))
+	return len(results), nil
+}
+
+// registerConfiguredSinks registers the detection sinks configured in settings. Invalid
+// entries are logged and skipped.
+func (p *Processor) registerConfiguredSinks(settings []conf.DetectionSinkSettings) {
+	for i := range settings {
+		cfg := &settings[i]
+		sink, filter, err := newConfiguredSink(cfg)
+		if err == nil {
+			err = p.RegisterDetectionSink(sink, filter, time.Duration(cfg.Timeout)*time.Second)
+		}
+		if err != nil {
+			GetLogger().Warn("Skipping invalid detection sink",
+				logger.String("sink", cfg.Name),
+				logger.Error(err),
+				logger.String("operation", "register_detection_sink"))
+		}
+	}
+}
+
+// newConfiguredSink creates the sink and filter described by a sink configuration.
+func newConfiguredSink(cfg *conf.DetectionSinkSettings) (DetectionSink, DetectionSinkFilter, error) {
+	var filter DetectionSinkFilter
+	switch strings.ToLower(cfg.Filter) {
+	case "", DetectionSinkFilterAll:
+	case DetectionSinkFilterLifers:
+		filter = LifersOnly
+	default:
+		return nil, nil, errors.Newf("unknown detection sink filter %q", cfg.Filter).
+			Component("analysis.processor").
+			Category(errors.CategoryConfiguration).
+			Context("sink", cfg.Name).
+			Build()
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case DetectionSinkTypeWebhook:
+		if cfg.URL == "" {
+			return nil, nil, errors.Newf("webhook detection sink requires a URL").
+				Component("analysis.processor").
+				Category(errors.CategoryConfiguration).
+				Context("sink", cfg.Name).
+				Build()
+		}
+		return &WebhookSink{SinkName: cfg.Name, URL: cfg.URL}, filter, nil
+	default:
+		return nil, nil, errors.Newf("unknown detection sink type %q", cfg.Type).
+			Component("analysis.processor").
+			Category(errors.CategoryConfiguration).
+			Context("sink", cfg.Name).
+			Build()
+	}
+}
+
+// WebhookSink posts each detection it receives as JSON to a URL.
+type WebhookSink struct {
+	SinkName string
+	URL      string
+	Client   *http.Client // http.DefaultClient when nil
+}
+
+// webhookSinkPayload is the JSON body a WebhookSink posts.
+type webhookSinkPayload struct {
+	CorrelationID  string    `json:"correlationId,omitempty"`
+	ScientificName string    `json:"scientificName"`
+	CommonName     string    `json:"commonName"`
+	Confidence     float64   `json:"confidence"`
+	Source         string    `json:"source,omitempty"`
+	BeginTime      time.Time `json:"beginTime"`
+	Lifer          bool      `json:"lifer"`
+	Replay         bool      `json:"replay,omitempty"`
+}
+
+// Name returns the sink name.
+func (w *WebhookSink) Name() string {
+	return w.SinkName
+}
+
+// Send posts event to the webhook URL, failing on a non-2xx response.
+func (w *WebhookSink) Send(ctx context.Context, event *DetectionSinkEvent) error {
+	body, err := json.Marshal(webhookSinkPayload{
+		CorrelationID:  event.CorrelationID,
+		ScientificName: event.Result.Species.ScientificName,
+		CommonName:     event.Result.Species.CommonName,
+		Confidence:     event.Result.Confidence,
+		Source:         event.Result.AudioSource.DisplayName,
+		BeginTime:      event.Result.BeginTime,
+		Lifer:          event.Lifer,
+		Replay:         event.Replay,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryNetwork).
+			Context("sink", w.SinkName).
+			Build()
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New(fmt.Errorf("webhook responded with status %d", resp.StatusCode)).
+			Component("analysis.processor").
+			Category(errors.CategoryNetwork).
+			Context("sink", w.SinkName).
+			Context("status_code", resp.StatusCode).
+			Build()
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// detection_sinks_test.go: Tests for fanning out detections to registered sinks
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+// recordingSink remembers the species of the detections it received.
+type recordingSink struct {
+	name string
+
+	mu      sync.Mutex
+	species []string
+	replays int
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Send(_ context.Context, event *DetectionSinkEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.species = append(s.species, event.Result.Species.ScientificName)
+	if event.Replay {
+		s.replays++
+	}
+	return nil
+}
+
+func (s *recordingSink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slices.Sort(s.species)
+	return s.species
+}
+
+func sinkDetection(scientificName string) *Detections {
+	return &Detections{Result: detection.Result{Species: detection.Species{ScientificName: scientificName}}}
+}
+
+func TestDetectionSinks_FilterPerSink(t *testing.T) {
+	p := newLifeListStatusProcessor(t) // Parus major is already seen
+	lifers := &recordingSink{name: "lifers"}
+	everything := &recordingSink{name: "everything"}
+	require.NoError(t, p.RegisterDetectionSink(lifers, LifersOnly, 0))
+	require.NoError(t, p.RegisterDetectionSink(everything, nil, time.Second))
+	require.Error(t, p.RegisterDetectionSink(&recordingSink{name: "lifers"}, nil, 0), "sink names are unique")
+
+	p.dispatchToSinks(sinkDetection("Apus apus"))
+	p.dispatchToSinks(sinkDetection("Parus major"))
+	p.sinks.wait()
+
+	assert.Equal(t, []string{"Apus apus"}, lifers.received(), "only the lifer reaches the lifer-only sink")
+	assert.Equal(t, []string{"Apus apus", "Parus major"}, everything.received())
+
+	assert.True(t, p.UnregisterDetectionSink("lifers"))
+	assert.False(t, p.UnregisterDetectionSink("lifers"))
+}
+
+// recentDetectionsRepository serves a fixed list of recent detections, newest first.
+type recentDetectionsRepository struct {
+	*MockDetectionRepository
+	recent []*detection.Result
+}
+
+func (r *recentDetectionsRepository) GetRecent(_ context.Context, limit int) ([]*detection.Result, error) {
+	return r.recent[:min(limit, len(r.recent))], nil
+}
+
+func TestReplayDetections_RedeliversFlaggedAsReplays(t *testing.T) {
+	p := newLifeListStatusProcessor(t) // Parus major is already seen
+	p.Repo = &recentDetectionsRepository{
+		MockDetectionRepository: NewMockDetectionRepository(),
+		recent: []*detection.Result{
+			{Species: detection.Species{ScientificName: "Apus apus"}},
+			{Species: detection.Species{ScientificName: "Parus major"}},
+			{Species: detection.Species{ScientificName: "Turdus merula"}}, // Beyond the requested count
+		},
+	}
+	lifers := &recordingSink{name: "lifers"}
+	everything := &recordingSink{name: "everything"}
+	require.NoError(t, p.RegisterDetectionSink(lifers, LifersOnly, 0))
+	require.NoError(t, p.RegisterDetectionSink(everything, nil, 0))
+
+	replayed, err := p.ReplayDetections(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, replayed)
+	p.sinks.wait()
+
+	assert.Equal(t, []string{"Apus apus"}, lifers.received(), "replays respect the sink filters")
+	assert.Equal(t, []string{"Apus apus", "Parus major"}, everything.received())
+	assert.Equal(t, 1, lifers.replays)
+	assert.Equal(t, 2, everything.replays, "every replayed event is flagged")
+
+	_, err = p.ReplayDetections(t.Context(), 0)
+	assert.Error(t, err)
+}
+
+func TestWebhookSink_PostsDetection(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan webhookSinkPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookSinkPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	sink, filter, err := newConfiguredSink(&conf.DetectionSinkSettings{Name: "hook", Type: "webhook", URL: server.URL, Filter: "lifers"})
+	require.NoError(t, err)
+	require.NotNil(t, filter)
+
+	event := &DetectionSinkEvent{Result: sinkDetection("Apus apus").Result, Lifer: true, Replay: true}
+	event.Result.Species.CommonName = "Common Swift"
+	require.NoError(t, sink.Send(t.Context(), event))
+	payload := <-received
+	assert.Equal(t, "Apus apus", payload.ScientificName)
+	assert.Equal(t, "Common Swift", payload.CommonName)
+	assert.True(t, payload.Lifer)
+	assert.True(t, payload.Replay)
+
+	_, _, err = newConfiguredSink(&conf.DetectionSinkSettings{Name: "bad", Type: "webhook"})
+	assert.Error(t, err, "a webhook sink needs a URL")
+}
@@ -159,7 +159,7 @@ func (p *Processor) LearnFromApprovedDetection(speciesLowercase, scientificName
 	}
 
 	// Use global threshold as base (species has no custom threshold)
-	baseThreshold := float32(p.Settings.BirdNET.Threshold)
+	baseThreshold := float32(p.ConfidenceThreshold())
 
 	// Calculate learning cooldown based on detection window duration
 	// This prevents multiple threshold learnings within a single detection event
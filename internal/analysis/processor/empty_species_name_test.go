@@ -0,0 +1,133 @@
+// empty_species_name_test.go: Tests for handling detections with blank species names
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// newEmptyNameTestProcessor creates a Processor that accepts every detection so only
+// the empty name policy decides what comes out of processResults.
+func newEmptyNameTestProcessor(policy string) *Processor {
+	settings := &conf.Settings{
+		BirdNET: conf.BirdNETConfig{
+			Threshold: 0.1,
+			RangeFilter: conf.RangeFilterSettings{
+				Species: []string{"Turdus merula_Eurasian Blackbird", "Unknown", "_Mystery Bird"},
+			},
+		},
+		SoundId: conf.SoundIdConfig{EmptyNamePolicy: policy},
+	}
+	return &Processor{
+		Settings:           settings,
+		Bn:                 &birdnet.BirdNET{Settings: settings},
+		DynamicThresholds:  make(map[string]*DynamicThreshold),
+		LastDogDetection:   make(map[string]time.Time),
+		LastHumanDetection: make(map[string]time.Time),
+	}
+}
+
+// emptyNameResults returns one valid detection and one with a blank scientific name.
+func emptyNameResults() birdnet.Results {
+	return birdnet.Results{
+		StartTime: time.Now(),
+		Source:    testAudioSource(),
+		Results: []datastore.Results{
+			{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9},
+			{Species: "_Mystery Bird", Confidence: 0.9},
+		},
+	}
+}
+
+func TestProcessResults_EmptyScientificNamePolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         string
+		wantScientific []string
+	}{
+		{"default drops", "", []string{"Turdus merula"}},
+		{"drop", conf.EmptyNamePolicyDrop, []string{"Turdus merula"}},
+		{"tag", conf.EmptyNamePolicyTag, []string{"Turdus merula", unknownScientificName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newEmptyNameTestProcessor(tt.policy)
+
+			detections, _ := p.processResults(emptyNameResults())
+
+			got := make([]string, 0, len(detections))
+			for i := range detections {
+				species := detections[i].Result.Species
+				assert.NotEmpty(t, species.ScientificName, "scientific name must never be empty")
+				assert.NotEmpty(t, species.CommonName, "common name (metrics label) must never be empty")
+				got = append(got, species.ScientificName)
+			}
+			assert.Equal(t, tt.wantScientific, got)
+		})
+	}
+}
+
+func TestTagEmptySpeciesNames(t *testing.T) {
+	sci, common := tagEmptySpeciesNames("", "Mystery Bird")
+	assert.Equal(t, unknownScientificName, sci)
+	assert.Equal(t, "Mystery Bird", common)
+
+	sci, common = tagEmptySpeciesNames("Turdus merula", "")
+	assert.Equal(t, "Turdus merula", sci)
+	assert.Equal(t, unknownCommonName, common)
+}
+
+func TestLifeList_IgnoresEmptyNames(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	csv := "a,b,c,d,Turdus merula\na,b,c,d,\na,b,c,d,   \n"
+	require.NoError(t, os.WriteFile(path, []byte(csv), 0o600))
+
+	require.NoError(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}))
+
+	list := loadedLifeList.Snapshot()
+	assert.NotContains(t, list, "", "blank rows must not create an empty life list entry")
+	assert.Len(t, list, 1)
+	assert.True(t, isInLifeList("Turdus merula"))
+	assert.False(t, isInLifeList(""))
+}
+
+func TestProcessApprovedDetection_TaggedNamesStayOffLifeList(t *testing.T) {
+	detections, _ := newEmptyNameTestProcessor(conf.EmptyNamePolicyTag).processResults(emptyNameResults())
+	require.Len(t, detections, 2)
+
+	p := newLifeListStatusProcessor(t)
+	p.JobQueue = jobqueue.NewJobQueue() // Never started, so the detections' actions don't run
+	var alerted []string
+	p.SubscribeNewSpecies(func(event NewSpeciesEvent) { alerted = append(alerted, event.ScientificName) })
+	for i := range detections {
+		p.processApprovedDetection(&PendingDetection{
+			Detection:     detections[i],
+			Confidence:    0.9,
+			FirstDetected: time.Now(),
+		}, detections[i].Result.Species.CommonName)
+	}
+
+	assert.Equal(t, []string{"Turdus merula"}, alerted, "a placeholder name is not a new species")
+	_, listed := lookupLifeList("Turdus merula")
+	assert.True(t, listed, "named detections are still added as heard")
+	_, listed = lookupLifeList(unknownScientificName)
+	assert.False(t, listed, "placeholder names never reach the life list")
+
+	p.Settings.SoundId.LifeListReviewMode = true
+	p.processApprovedDetection(&PendingDetection{Detection: detections[1], Confidence: 0.9, FirstDetected: time.Now()},
+		detections[1].Result.Species.CommonName)
+	assert.Empty(t, p.PendingLifeListSpecies(), "placeholder names aren't queued for review")
+}
@@ -2,6 +2,7 @@
 package processor
 
 import (
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +23,24 @@ const (
 	SSEBroadcast                       // Represents a Server-Sent Events broadcast
 )
 
+// eventTypeNames holds the names event types are reported by.
+var eventTypeNames = map[EventType]string{
+	DatabaseSave:      "database_save",
+	LogToFile:         "log_to_file",
+	SendNotification:  "send_notification",
+	BirdWeatherSubmit: "birdweather_submit",
+	MQTTPublish:       "mqtt_publish",
+	SSEBroadcast:      "sse_broadcast",
+}
+
+// String returns the name of the event type.
+func (e EventType) String() string {
+	if name, ok := eventTypeNames[e]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 // EventBehaviorFunc defines the signature for functions that determine the behavior of an event.
 // It returns true if the event is allowed to be processed based on the given last event time and timeout.
 type EventBehaviorFunc func(lastEventTime time.Time, timeout time.Duration) bool
@@ -164,13 +183,24 @@ func (et *EventTracker) TrackEventWithNames(commonName, scientificName string, e
 		return false
 	}
 
-	// Determine the effective timeout for this species using both common and scientific name lookup
-	effectiveTimeout := et.DefaultInterval
+	effectiveTimeout := et.effectiveIntervalLocked(commonName, scientificName)
+
+	et.Mutex.RUnlock()
+
+	handler.Mutex.Lock()
+	allowEvent := handler.shouldHandleEventLocked(normalizedTrackingKey, effectiveTimeout)
+	handler.Mutex.Unlock()
+
+	return allowEvent
+}
 
+// effectiveIntervalLocked returns the event interval for a species, using its configured
+// interval when one is set. The caller must hold et.Mutex.
+func (et *EventTracker) effectiveIntervalLocked(commonName, scientificName string) time.Duration {
 	// Use lookupSpeciesConfig to support both common name and scientific name
 	if speciesConfig, found := lookupSpeciesConfig(et.SpeciesConfigs, commonName, scientificName); found {
 		if speciesConfig.Interval > 0 {
-			effectiveTimeout = time.Duration(speciesConfig.Interval) * time.Second
+			return time.Duration(speciesConfig.Interval) * time.Second
 		} else if speciesConfig.Interval < 0 {
 			log := GetLogger()
 			log.Warn("Negative interval configured for species, using default interval instead",
@@ -179,14 +209,40 @@ func (et *EventTracker) TrackEventWithNames(commonName, scientificName string, e
 				logger.String("scientificName", scientificName))
 		}
 	}
+	return et.DefaultInterval
+}
+
+// PeekEventWithNames reports whether an event for the species would be processed at the
+// given time without recording it, and how long remains until it would be when it would not.
+func (et *EventTracker) PeekEventWithNames(commonName, scientificName string, eventType EventType, at time.Time) (allowed bool, remaining time.Duration) {
+	trackingKey := commonName
+	if trackingKey == "" {
+		trackingKey = scientificName
+	}
+	if trackingKey == "" {
+		return true, 0
+	}
 
+	et.Mutex.RLock()
+	handler, exists := et.Handlers[eventType]
+	if !exists {
+		et.Mutex.RUnlock()
+		return false, 0
+	}
+	timeout := et.effectiveIntervalLocked(commonName, scientificName)
 	et.Mutex.RUnlock()
 
 	handler.Mutex.Lock()
-	allowEvent := handler.shouldHandleEventLocked(normalizedTrackingKey, effectiveTimeout)
+	lastTime, seen := handler.LastEventTime[strings.ToLower(trackingKey)]
 	handler.Mutex.Unlock()
 
-	return allowEvent
+	if !seen {
+		return true, 0
+	}
+	if elapsed := at.Sub(lastTime); elapsed < timeout {
+		return false, timeout - elapsed
+	}
+	return true, 0
 }
 
 // ResetEvent resets the state for a specific species and event type, clearing any tracked event timing.
@@ -205,3 +261,75 @@ func (et *EventTracker) ResetEvent(species string, eventType EventType) {
 		handler.ResetEvent(normalizedSpecies)
 	}
 }
+
+// Cooldown describes a species whose events of one type are currently suppressed.
+type Cooldown struct {
+	Species   string // Tracking key, the lowercase common name or scientific name
+	EventType EventType
+	Remaining time.Duration
+}
+
+// ActiveCooldowns returns the species whose events would be suppressed at the given time,
+// sorted by species and event type.
+func (et *EventTracker) ActiveCooldowns(at time.Time) []Cooldown {
+	et.Mutex.RLock()
+	handlers := make(map[EventType]*EventHandler, len(et.Handlers))
+	for eventType, handler := range et.Handlers {
+		handlers[eventType] = handler
+	}
+	et.Mutex.RUnlock()
+
+	// Copy the event times first so each handler lock is held only briefly, and never
+	// while et.Mutex is held, matching the lock ordering in TrackEventWithNames
+	lastTimes := make(map[EventType]map[string]time.Time, len(handlers))
+	for eventType, handler := range handlers {
+		handler.Mutex.Lock()
+		times := make(map[string]time.Time, len(handler.LastEventTime))
+		for species, t := range handler.LastEventTime {
+			times[species] = t
+		}
+		handler.Mutex.Unlock()
+		lastTimes[eventType] = times
+	}
+
+	var cooldowns []Cooldown
+	et.Mutex.RLock()
+	for eventType, times := range lastTimes {
+		for species, lastTime := range times {
+			// The tracking key is either name, so look it up as both
+			timeout := et.effectiveIntervalLocked(species, species)
+			if elapsed := at.Sub(lastTime); elapsed < timeout {
+				cooldowns = append(cooldowns, Cooldown{Species: species, EventType: eventType, Remaining: timeout - elapsed})
+			}
+		}
+	}
+	et.Mutex.RUnlock()
+
+	slices.SortFunc(cooldowns, func(a, b Cooldown) int {
+		if c := strings.Compare(a.Species, b.Species); c != 0 {
+			return c
+		}
+		return int(a.EventType) - int(b.EventType)
+	})
+	return cooldowns
+}
+
+// ResetAllEvents clears the tracked event timing of every species and event type, so the
+// next detection of any species is processed. It returns the number of cleared entries.
+func (et *EventTracker) ResetAllEvents() int {
+	et.Mutex.RLock()
+	handlers := make([]*EventHandler, 0, len(et.Handlers))
+	for _, handler := range et.Handlers {
+		handlers = append(handlers, handler)
+	}
+	et.Mutex.RUnlock()
+
+	cleared := 0
+	for _, handler := range handlers {
+		handler.Mutex.Lock()
+		cleared += len(handler.LastEventTime)
+		clear(handler.LastEventTime)
+		handler.Mutex.Unlock()
+	}
+	return cleared
+}
@@ -716,3 +716,29 @@ func TestEventTracker_MultipleTimeoutCycles(t *testing.T) {
 		time.Sleep(60 * time.Millisecond)
 	}
 }
+
+func TestEventTracker_ActiveCooldownsAndResetAll(t *testing.T) {
+	t.Parallel()
+
+	speciesConfigs := map[string]conf.SpeciesConfig{
+		"american robin": {Interval: 10},
+	}
+	tracker := NewEventTrackerWithConfig(60*time.Second, speciesConfigs)
+	require.True(t, tracker.TrackEvent("American Robin", DatabaseSave))
+	require.True(t, tracker.TrackEvent("Blue Jay", MQTTPublish))
+
+	now := time.Now()
+	cooldowns := tracker.ActiveCooldowns(now)
+	require.Len(t, cooldowns, 2)
+	assert.Equal(t, "american robin", cooldowns[0].Species)
+	assert.Equal(t, DatabaseSave, cooldowns[0].EventType)
+	assert.InDelta(t, 10, cooldowns[0].Remaining.Seconds(), 1, "species intervals apply")
+	assert.Equal(t, "blue jay", cooldowns[1].Species)
+	assert.Equal(t, "mqtt_publish", cooldowns[1].EventType.String())
+
+	assert.Len(t, tracker.ActiveCooldowns(now.Add(30*time.Second)), 1, "expired cooldowns aren't listed")
+
+	assert.Equal(t, 2, tracker.ResetAllEvents())
+	assert.Empty(t, tracker.ActiveCooldowns(now))
+	assert.True(t, tracker.TrackEvent("American Robin", DatabaseSave))
+}
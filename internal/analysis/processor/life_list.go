@@ -1,58 +1,945 @@
 package processor
 
 import (
+	"bufio"
+	"context"
 	"encoding/csv"
+	"fmt"
 	"io"
-	"strings"
+	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/httpclient"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// lifeListLayout holds the columns a life list CSV format keeps each field in.
+type lifeListLayout struct {
+	commonName     int
+	scientificName int
+	date           int
+	family         int
+}
+
+// Column layouts of the supported life list formats
+var (
+	// merlinLifeListLayout is the layout of Merlin's and eBird's life list exports, which
+	// the loader assumed for every file before formats were detected
+	merlinLifeListLayout = lifeListLayout{commonName: 3, scientificName: 4, date: 8, family: -1}
+	// ebirdLifeListLayout is the layout of eBird's "My eBird Data" download, one row per
+	// observation rather than per species
+	ebirdLifeListLayout = lifeListLayout{commonName: 1, scientificName: 2, date: 11, family: -1}
 )
 
-var s_life_list = map[string]bool{}
+// lifeListLayoutForFormat returns the layout of a LifeListFormat value, and whether the
+// layout should instead be taken from a header row when the file has one. Legacy files
+// keep the Merlin date column and read the names from the given columns. A negative
+// commonNameColumn means the file has no common names.
+func lifeListLayoutForFormat(format string, scientificNameColumn, commonNameColumn int) (layout lifeListLayout, detect bool) {
+	switch format {
+	case conf.LifeListFormatEBird:
+		return ebirdLifeListLayout, false
+	case conf.LifeListFormatMerlin:
+		return merlinLifeListLayout, false
+	case conf.LifeListFormatLegacy:
+		layout = merlinLifeListLayout
+		layout.scientificName = scientificNameColumn
+		layout.commonName = max(commonNameColumn, -1)
+		return layout, false
+	default:
+		return merlinLifeListLayout, true
+	}
+}
+
+// lifeListLayoutFromHeader maps the columns of a header row holding a "Scientific Name"
+// column. Columns the header lacks are set to -1.
+func lifeListLayoutFromHeader(record []string) (lifeListLayout, bool) {
+	layout := lifeListLayout{commonName: -1, scientificName: -1, date: -1, family: -1}
+	for i, field := range record {
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "scientific name":
+			layout.scientificName = i
+		case "common name":
+			layout.commonName = i
+		case "date":
+			layout.date = i
+		case "family":
+			layout.family = i
+		}
+	}
+	return layout, layout.scientificName >= 0
+}
+
+// lifeListDateLayouts are the first-seen date formats accepted in the date column
+var lifeListDateLayouts = []string{"2006-01-02", "02 Jan 2006", "2 Jan 2006", "01/02/2006"}
+
+// LifeListEntry is one species on the life list.
+type LifeListEntry struct {
+	ScientificName string
+	CommonName     string
+	FirstSeen      time.Time // Zero when the source has no parseable date
+	Family         string    // From the list's family column, empty when it has none
+	Status         LifeListStatus
+
+	merged bool // Added by an import merge or the API rather than the configured list, so it is persisted
+}
+
+// lifeList holds a life list keyed by lower-cased scientific name. A (re)load builds a
+// fresh map and swaps it in, so lookups never observe a partially loaded list. Lookups
+// take no lock, rather than the read lock of an RWMutex, so a slow reload never holds up
+// the detection pipeline; writers are serialized by lifeListStatusMu.
+type lifeList struct {
+	entries atomic.Pointer[map[string]LifeListEntry] // nil until a list is loaded; used only by the methods below
+}
+
+// Load replaces the list with entries, which must not be modified afterwards. A nil map
+// unloads the list.
+func (l *lifeList) Load(entries map[string]LifeListEntry) {
+	if entries == nil {
+		l.entries.Store(nil)
+		return
+	}
+	l.entries.Store(&entries)
+}
+
+// Loaded reports whether a list is loaded.
+func (l *lifeList) Loaded() bool {
+	return l.entries.Load() != nil
+}
+
+// Snapshot returns the loaded entries, which must not be modified, or nil when no list is
+// loaded. A reload swaps in a new map rather than changing this one.
+func (l *lifeList) Snapshot() map[string]LifeListEntry {
+	list := l.entries.Load()
+	if list == nil {
+		return nil
+	}
+	return *list
+}
+
+// Lookup returns the entry of a species, directly or through a group entry, and whether a
+// list is loaded, both from the same list.
+func (l *lifeList) Lookup(scientificName string) (entry LifeListEntry, found, loaded bool) {
+	list := l.Snapshot()
+	if list == nil {
+		return LifeListEntry{}, false, false
+	}
+	if scientificName == "" {
+		return LifeListEntry{}, false, true
+	}
+	entry, found = findLifeListEntry(list, scientificName)
+	return entry, found, true
+}
+
+// Contains reports whether a species is on the list, directly or through a group entry.
+// It is false while no list is loaded.
+func (l *lifeList) Contains(scientificName string) bool {
+	_, found, _ := l.Lookup(scientificName)
+	return found
+}
+
+// Len returns the number of entries on the list, 0 when none is loaded.
+func (l *lifeList) Len() int {
+	return len(l.Snapshot())
+}
+
+// loadedLifeList is the life list detections are checked against.
+var loadedLifeList lifeList
+
+// lifeListMetrics counts life list hits and misses when metrics are enabled, nil otherwise.
+// Like the list itself it is package state, set once a processor is created.
+var lifeListMetrics atomic.Pointer[metrics.BirdNETMetrics]
+
+// lifeListErrorMetrics counts failed life list loads by category when metrics are enabled,
+// nil otherwise.
+var lifeListErrorMetrics atomic.Pointer[metrics.ErrorMetrics]
+
+// initLifeListMetrics makes life list lookups record their outcome in m, if it has BirdNET
+// metrics, and failed loads in its error metrics.
+func initLifeListMetrics(m *observability.Metrics) {
+	if m == nil {
+		lifeListMetrics.Store(nil)
+		lifeListErrorMetrics.Store(nil)
+		return
+	}
+	lifeListMetrics.Store(m.BirdNET)
+	lifeListErrorMetrics.Store(m.Errors)
+}
+
+// lifeListHTTPClient fetches life lists configured as an http(s) URL.
+var lifeListHTTPClient = httpclient.New(nil)
+
+// isLifeListURL reports whether the configured life list location is an http(s) URL.
+func isLifeListURL(path string) bool {
+	return conf.IsLifeListURL(path)
+}
+
+// LoadLifeListResult reports what loading the life list found.
+type LoadLifeListResult struct {
+	Loaded     int      // Entries on the loaded list
+	Duplicates []string // Scientific names of rows repeating an earlier entry, once per row
+	Empty      []string // Configured files without a single species row, such as a blank or header-only file
+	Malformed  int      // Rows skipped as too short to hold a scientific name
+}
 
+// loadLifeList loads the life list like loadLifeListCtx, without a way to cancel it.
 func loadLifeList(settings *conf.Settings) error {
-	path := settings.SoundId.LifeListPath
-	if path == "" {
-		return errors.Newf("Life list path is not set in the configuration").
+	return loadLifeListCtx(context.Background(), settings)
+}
+
+// loadLifeListCtx loads the life list from the configured file path or URL and replaces
+// the current list on success. On failure the current list is kept. Once ctx is done the
+// load stops within a chunk of the file being read and fails with a cancellation error,
+// or a timeout error when its deadline passed.
+func loadLifeListCtx(ctx context.Context, settings *conf.Settings) error {
+	_, err := loadLifeListWithResult(ctx, settings)
+	return err
+}
+
+// loadLifeListWithResult loads the life list like loadLifeListCtx and also reports the
+// rows whose scientific name normalizes to that of an earlier row, so users can clean up
+// hand-edited files. The collision policy still decides whether such rows are merged.
+// When several files are configured their entries are merged into one list. A failed load
+// is counted in the error metrics.
+func loadLifeListWithResult(ctx context.Context, settings *conf.Settings) (result LoadLifeListResult, err error) {
+	defer func() {
+		if err != nil {
+			lifeListErrorMetrics.Load().RecordError(err)
+		}
+	}()
+
+	paths := lifeListPaths(&settings.SoundId)
+	if len(paths) == 0 {
+		return LoadLifeListResult{}, errors.Newf("Life list path is not set in the configuration").
 			Component("life_list").
 			Category(errors.CategoryFileIO).
+			Severity(errors.SeverityFatal).
 			Build()
 	}
-	
-	file, err := os.Open(path)
-	if err != nil {
-		return errors.New(err).
+
+	if column := settings.SoundId.LifeListScientificNameColumn; column < 0 {
+		return LoadLifeListResult{}, errors.Newf("life list scientific name column must not be negative, got %d", column).
+			Component("life_list").
+			Category(errors.CategoryConfiguration).
+			Context("column", column).
+			Severity(errors.SeverityFatal).
+			Build()
+	}
+
+	var list map[string]LifeListEntry
+	var collisions []lifeListCollision
+	var duplicates []string
+	var failures []error
+	var empty []string
+	malformed := 0
+	taxonomyVersion := strings.TrimSpace(settings.SoundId.LifeListTaxonomyVersion)
+	for _, path := range paths {
+		file, err := readLifeListFile(ctx, path, settings)
+		if err != nil {
+			// Reading the other files would fail the same way
+			if len(paths) == 1 || isLifeListCancelled(err) {
+				return LoadLifeListResult{}, err
+			}
+			err = lifeListFileError(path, err)
+			failures = append(failures, err)
+			if settings.SoundId.LifeListSkipUnreadable {
+				GetLogger().Warn("Skipping unreadable life list file",
+					logger.String("path", path),
+					logger.Error(err),
+					logger.String("operation", "life_list_load"))
+			}
+			continue
+		}
+		if len(file.list) == 0 {
+			// Loads fine, but makes every detection look new; often a path to the wrong file
+			GetLogger().Warn("Life list file is empty, no species were loaded from it",
+				logger.String("path", path),
+				logger.String("operation", "life_list_load"))
+			empty = append(empty, path)
+		}
+		collisions = append(collisions, file.collisions...)
+		duplicates = append(duplicates, file.duplicates...)
+		malformed += file.malformed
+		list = mergeLifeListFile(list, file.list)
+		// Each file is under the limit, but together they may not be
+		if limit := settings.SoundId.LifeListMaxEntries; limit > 0 && len(list) > limit {
+			return LoadLifeListResult{}, lifeListTooManyEntriesError(len(list), limit)
+		}
+		if taxonomyVersion == "" {
+			taxonomyVersion = file.taxonomy
+		}
+	}
+	// Every file is read before failing, so one load reports all the files to fix
+	if len(failures) > 0 && !settings.SoundId.LifeListSkipUnreadable {
+		return LoadLifeListResult{}, errors.Aggregate(failures...)
+	}
+	if list == nil {
+		return LoadLifeListResult{}, errors.Newf("none of the %d configured life list files could be read: %w", len(paths), errors.Aggregate(failures...)).
 			Component("life_list").
 			Category(errors.CategoryFileIO).
-			Context("operation", "open").
+			Context("paths", len(paths)).
+			Build()
+	}
+	logLifeListCollisions(settings.SoundId.LifeListCollisionPolicy, collisions)
+
+	result = LoadLifeListResult{Duplicates: duplicates, Empty: empty, Malformed: malformed}
+	if len(result.Duplicates) > 0 {
+		GetLogger().Info("Life list has duplicate entries",
+			logger.Int("duplicates", len(result.Duplicates)),
+			logger.Int("entries", len(list)),
+			logger.String("operation", "life_list_load"))
+	}
+
+	if settings.SoundId.LifeListAuthorityEnabled {
+		authority, err := loadLifeListAuthority(ctx, settings)
+		if err != nil {
+			return LoadLifeListResult{}, err
+		}
+		list = authority.canonicalize(list)
+	}
+	synonyms, err := loadLifeListSynonyms(ctx, settings)
+	if err != nil {
+		return LoadLifeListResult{}, err
+	}
+	list = synonyms.canonicalize(list)
+
+	// Hold the status lock so a species heard during the reload isn't lost from memory
+	lifeListStatusMu.Lock()
+	defer lifeListStatusMu.Unlock()
+	if err := applyLifeListStatuses(settings.SoundId.LifeListStatusPath, list); err != nil {
+		return LoadLifeListResult{}, err
+	}
+
+	taxonomy := checkLifeListTaxonomy(taxonomyVersion, modelTaxonomyVersion(settings), GetLogger())
+	lifeListFuzzy.Store(settings.SoundId.LifeListFuzzy)
+	lifeListSynonyms.Store(synonyms)
+	lifeListTaxonomy.Store(&taxonomy)
+	loadedLifeList.Load(list)
+	result.Loaded = len(list)
+	return result, nil
+}
+
+// lifeListPaths returns the configured life list locations, the main one first, without
+// blank or repeated entries
+func lifeListPaths(settings *conf.SoundIdConfig) []string {
+	var paths []string
+	for _, path := range append([]string{settings.LifeListPath}, settings.LifeListPaths...) {
+		path = strings.TrimSpace(path)
+		if path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// lifeListFile is what reading one configured life list location found
+type lifeListFile struct {
+	list       map[string]LifeListEntry
+	collisions []lifeListCollision
+	duplicates []string // Scientific names of rows repeating an earlier entry, once per row
+	taxonomy   string   // Taxonomy version a CSV file is annotated with, if any
+	malformed  int      // Rows skipped as too short to hold a scientific name
+	rows       int      // Species rows read, counting repeats, malformed rows and skipped group entries
+}
+
+// readLifeListFile opens, decodes and parses the life list at one configured location.
+func readLifeListFile(ctx context.Context, path string, settings *conf.Settings) (lifeListFile, error) {
+	reader, err := openLifeList(ctx, resolveLifeListPath(path, settings.SoundId.DataDir), lifeListMaxBytes(settings))
+	if err != nil {
+		return lifeListFile{}, err
+	}
+	defer reader.Close()
+
+	return decodeLifeListFile(&lifeListContextReader{ctx: ctx, r: reader}, path, settings.SoundId.LifeListEncoding, newLifeListParseOptions(&settings.SoundId))
+}
+
+// decodeLifeListFile decodes life list data in the named encoding and parses it as JSON or
+// CSV, going by the format of opts and, when that is detected, the extension of name.
+func decodeLifeListFile(r io.Reader, name, encoding string, opts lifeListParseOptions) (lifeListFile, error) {
+	decoded, err := decodeLifeList(r, encoding)
+	if err != nil {
+		return lifeListFile{}, err
+	}
+	if isLifeListJSON(name, opts.format) {
+		return parseLifeListJSONFile(decoded, opts)
+	}
+	taxonomy, decoded := readLifeListTaxonomyComment(decoded)
+	file, err := parseLifeListRows(decoded, opts)
+	file.taxonomy = taxonomy
+	return file, err
+}
+
+// lifeListFileError names the file a load of several life list files failed on, keeping
+// the category of the original error.
+func lifeListFileError(path string, err error) error {
+	return errors.New(fmt.Errorf("life list %s: %w", path, err)).
+		Component("life_list").
+		Category(lifeListErrorCategory(err)).
+		Context("path", path).
+		Build()
+}
+
+// lifeListReloadError marks a failed reload as a warning, since the list loaded before
+// stays in use; a first load failing on the same cause leaves no list and keeps its
+// severity.
+func lifeListReloadError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.New(err).
+		Component("life_list").
+		Category(lifeListErrorCategory(err)).
+		Context("operation", "reload").
+		Severity(errors.SeverityWarning).
+		Build()
+}
+
+// lifeListErrorCategory returns the category of err, or CategoryFileIO when it has none
+func lifeListErrorCategory(err error) errors.ErrorCategory {
+	var enhanced *errors.EnhancedError
+	if errors.As(err, &enhanced) {
+		return enhanced.Category
+	}
+	return errors.CategoryFileIO
+}
+
+// mergeLifeListFile adds the entries of one life list file to the entries of the files
+// loaded before it. A species on several lists is kept once, with the earliest first-seen
+// date any of them has and the first common name given.
+func mergeLifeListFile(list, file map[string]LifeListEntry) map[string]LifeListEntry {
+	if list == nil {
+		return file
+	}
+	for key, entry := range file {
+		existing, ok := list[key]
+		if !ok {
+			list[key] = entry
+			continue
+		}
+		if !entry.FirstSeen.IsZero() && (existing.FirstSeen.IsZero() || entry.FirstSeen.Before(existing.FirstSeen)) {
+			existing.FirstSeen = entry.FirstSeen
+		}
+		if existing.CommonName == "" {
+			existing.CommonName = entry.CommonName
+		}
+		list[key] = existing
+	}
+	return list
+}
+
+// resolveLifeListPath resolves a relative life list file path against dataDir, or against
+// the config directory when dataDir is empty, rather than the process working directory,
+// which for a service is rarely where the user put the file. URLs and absolute paths are
+// returned unchanged.
+func resolveLifeListPath(path, dataDir string) string {
+	resolved := conf.ResolveLifeListPath(path, dataDir)
+	if resolved != path {
+		GetLogger().Debug("Resolved relative life list path",
+			logger.String("config_path", path),
+			logger.String("absolute_path", resolved))
+	}
+	return resolved
+}
+
+// lifeListMaxBytes returns the configured life list size limit in bytes, 0 for no limit.
+func lifeListMaxBytes(settings *conf.Settings) int64 {
+	return int64(max(settings.SoundId.LifeListMaxSizeMB, 0)) << 20
+}
+
+// openLifeList opens the life list at path, fetching it over HTTP when path is a URL.
+// Files larger than maxBytes are rejected before anything is read, so an oversized list
+// can't exhaust the memory of a small device; downloads of unknown length fail once they
+// exceed it. A maxBytes of 0 disables the limit.
+func openLifeList(ctx context.Context, path string, maxBytes int64) (io.ReadCloser, error) {
+	if !isLifeListURL(path) {
+		file, err := os.Open(path)
+		if err != nil {
+			// The file may be mid-write or on a share that isn't mounted yet
+			return nil, errors.New(err).
+				Component("life_list").
+				Category(errors.CategoryFileIO).
+				Context("operation", "open").
+				Retryable(true).
+				Build()
+		}
+		if info, err := file.Stat(); err == nil && maxBytes > 0 && info.Size() > maxBytes {
+			_ = file.Close()
+			return nil, lifeListTooLargeError(fmt.Sprintf("life list file is %d bytes", info.Size()), info.Size(), maxBytes)
+		}
+		return file, nil
+	}
+
+	resp, err := lifeListHTTPClient.Get(ctx, path)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryNetwork).
+			Context("operation", "fetch").
+			Retryable(true).
+			Build()
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, errors.New(fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)).
+			Component("life_list").
+			Category(errors.CategoryNetwork).
+			Context("operation", "fetch").
+			Context("status_code", resp.StatusCode).
+			Retryable(resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests).
+			Build()
+	}
+	if maxBytes > 0 {
+		if resp.ContentLength > maxBytes {
+			_ = resp.Body.Close()
+			return nil, lifeListTooLargeError(fmt.Sprintf("life list download is %d bytes", resp.ContentLength), resp.ContentLength, maxBytes)
+		}
+		return &lifeListSizeLimiter{ReadCloser: resp.Body, limit: maxBytes}, nil
+	}
+	return resp.Body, nil
+}
+
+// lifeListSizeLimiter fails a read once more than limit bytes were read, for downloads
+// that don't announce their length.
+type lifeListSizeLimiter struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (r *lifeListSizeLimiter) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n, lifeListTooLargeError(fmt.Sprintf("life list download exceeded %d bytes", r.limit), r.read, r.limit)
+	}
+	return n, err
+}
+
+// lifeListContextReader fails a read once ctx is done, so loading a large life list or one on
+// a slow share stops between chunks rather than running to the end of the file.
+type lifeListContextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *lifeListContextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, lifeListCancelledError(err)
+	}
+	return r.r.Read(p)
+}
+
+// lifeListCancelledError reports a life list load given up on because its context ended.
+func lifeListCancelledError(err error) error {
+	category := errors.CategoryCancellation
+	if errors.Is(err, context.DeadlineExceeded) {
+		category = errors.CategoryTimeout
+	}
+	return errors.New(err).
+		Component("life_list").
+		Category(category).
+		Context("operation", "read").
+		Retryable(false).
+		Build()
+}
+
+// isLifeListCancelled reports whether err ended a load because its context ended, which
+// parse errors pass on as is rather than as a malformed file.
+func isLifeListCancelled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// lifeListTooLargeError reports a life list over the size limit, naming its size and the limit.
+func lifeListTooLargeError(what string, size, limit int64) error {
+	return errors.Newf("%s, over the %d MB limit set by lifelistMaxSizeMB", what, limit>>20).
+		Component("life_list").
+		Category(errors.CategoryValidation).
+		Context("size_bytes", size).
+		Context("limit_bytes", limit).
+		Retryable(false).
+		Build()
+}
+
+// lifeListTooManyEntriesError reports a life list holding more species than allowed,
+// naming the limit.
+func lifeListTooManyEntriesError(entries, limit int) error {
+	return errors.Newf("life list has more than %d species, the limit set by lifelistMaxEntries", limit).
+		Component("life_list").
+		Category(errors.CategoryValidation).
+		Context("entries", entries).
+		Context("limit", limit).
+		Retryable(false).
+		Build()
+}
+
+// lifeListEncodings maps accepted LifeListEncoding values to their encodings. UTF-8 needs
+// no transcoding and is handled separately.
+var lifeListEncodings = map[string]encoding.Encoding{
+	"latin1":       charmap.ISO8859_1,
+	"iso-8859-1":   charmap.ISO8859_1,
+	"windows-1252": charmap.Windows1252,
+	"cp1252":       charmap.Windows1252,
+}
+
+// decodeLifeList wraps r so the life list is read as UTF-8 regardless of the source encoding.
+func decodeLifeList(r io.Reader, name string) (io.Reader, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "utf-8" || name == "utf8" {
+		return r, nil
+	}
+
+	enc, ok := lifeListEncodings[name]
+	if !ok {
+		return nil, errors.Newf("unsupported life list encoding %q", name).
+			Component("life_list").
+			Category(errors.CategoryConfiguration).
+			Context("encoding", name).
+			Severity(errors.SeverityFatal).
+			Build()
+	}
+	return enc.NewDecoder().Reader(r), nil
+}
+
+// encodeLifeList converts UTF-8 life list data to the named encoding, for writing rows back
+// to the file.
+func encodeLifeList(data []byte, name string) ([]byte, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "utf-8" || name == "utf8" {
+		return data, nil
+	}
+
+	enc, ok := lifeListEncodings[name]
+	if !ok {
+		return nil, errors.Newf("unsupported life list encoding %q", name).
+			Component("life_list").
+			Category(errors.CategoryConfiguration).
+			Context("encoding", name).
+			Build()
+	}
+	encoded, err := enc.NewEncoder().Bytes(data)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryValidation).
+			Context("encoding", name).
 			Build()
 	}
-	defer file.Close()
+	return encoded, nil
+}
+
+// lifeListCollision records two life list entries whose scientific names normalize to the
+// same key.
+type lifeListCollision struct {
+	Key      string
+	First    string // Original scientific name of the entry already on the list
+	Second   string // Original scientific name of the later entry
+	KeptBoth bool   // The later entry was kept under the key of its original name rather than merged
+}
+
+// lifeListParseOptions controls how life list records are read.
+type lifeListParseOptions struct {
+	collisionPolicy string // How entries that normalize to the same name are resolved
+	groupMatching   bool   // Keep entries with a rank marker as group matchers rather than skipping them
+	skipMalformed   bool   // Skip rows too short to hold a scientific name instead of failing the load
+	fuzzy           bool   // Key entries by their whitespace-collapsed species name, dropping any subspecies
+	format          string // LifeListFormat value selecting the column layout, empty to detect it
+	maxEntries      int    // Most entries a file may hold, 0 for no limit
+
+	scientificNameColumn int // Zero-based scientific name column of legacy format files
+	commonNameColumn     int // Zero-based common name column of legacy format files, negative for none
+}
+
+// newLifeListParseOptions returns the parse options configured in settings.
+func newLifeListParseOptions(settings *conf.SoundIdConfig) lifeListParseOptions {
+	return lifeListParseOptions{
+		collisionPolicy: settings.LifeListCollisionPolicy,
+		groupMatching:   settings.LifeListGroupMatching,
+		skipMalformed:   settings.LifeListSkipMalformed,
+		fuzzy:           settings.LifeListFuzzy,
+		format:          settings.LifeListFormat,
+		maxEntries:      settings.LifeListMaxEntries,
+
+		scientificNameColumn: settings.LifeListScientificNameColumn,
+		commonNameColumn:     settings.LifeListCommonNameColumn,
+	}
+}
 
-	reader := csv.NewReader(file)
+// parseLifeList reads life list CSV records, taking the scientific name and, when present,
+// the common name and first-seen date from the columns of the configured format. When the
+// format is detected, a first row naming a "Scientific Name" column maps the columns, and
+// files without one are read with the Merlin life list layout. Imported
+// species are marked as seen. Entries whose names normalize to the same key are resolved
+// according to the collision policy and reported as collisions. Entries with a trailing
+// rank marker are skipped unless group matching is enabled. A row too short to hold a
+// scientific name fails the load with its line number, or is skipped and counted when
+// skipMalformed is set.
+func parseLifeList(r io.Reader, opts lifeListParseOptions) (map[string]LifeListEntry, []lifeListCollision, error) {
+	file, err := parseLifeListRows(r, opts)
+	return file.list, file.collisions, err
+}
+
+// parseLifeListRows parses like parseLifeList and also reports how many rows were read and
+// how many of them were skipped as malformed.
+func parseLifeListRows(r io.Reader, opts lifeListParseOptions) (lifeListFile, error) {
+	builder := newLifeListBuilder(opts)
+	malformed := 0
+	reader := csv.NewReader(skipLifeListBOM(r))
+	reader.FieldsPerRecord = -1 // Trailing optional columns may be missing, which is checked per row
+	reader.LazyQuotes = true    // Tolerate quotes around padded fields, which cleanLifeListField strips
+	layout, detect := lifeListLayoutForFormat(opts.format, opts.scientificNameColumn, opts.commonNameColumn)
 
 	for {
 		record, err := reader.Read()
 		if err == io.EOF {
 			break // End of file
 		}
+		if isLifeListCancelled(err) {
+			return lifeListFile{}, err
+		}
 		if err != nil {
-			return errors.New(err).
+			return lifeListFile{}, errors.New(err).
 				Component("life_list").
 				Category(errors.CategoryFileIO).
 				Context("operation", "read").
+				Retryable(false).
 				Build()
 		}
 
-		s_life_list[strings.ToLower(record[4])] = true
+		if detect && !isBlankLifeListRow(record) {
+			detect = false // Only the first row can be a header
+			if header, ok := lifeListLayoutFromHeader(record); ok {
+				layout = header
+				continue
+			}
+		}
+
+		if len(record) <= layout.scientificName {
+			if isBlankLifeListRow(record) {
+				continue // A whitespace-only line, e.g. at the end of the file
+			}
+			line, _ := reader.FieldPos(0)
+			if !opts.skipMalformed {
+				return lifeListFile{}, errors.Newf("life list row has %d columns, the scientific name is in column %d",
+					len(record), layout.scientificName+1).
+					Component("life_list").
+					Category(errors.CategoryFileIO).
+					Context("operation", "read").
+					Context("line", line).
+					Retryable(false).
+					Build()
+			}
+			malformed++
+			GetLogger().Debug("Skipped malformed life list row",
+				logger.Int("line", line),
+				logger.Int("columns", len(record)))
+			continue
+		}
+
+		// Blank rows must not create an empty key that every unnamed detection would match
+		original := record[layout.scientificName]
+		scientificName := cleanLifeListField(original)
+		if scientificName == "" || strings.EqualFold(scientificName, "scientific name") {
+			continue // Header row or blank
+		}
+		entry := LifeListEntry{ScientificName: scientificName, Status: LifeListStatusSeen}
+		if layout.commonName >= 0 && len(record) > layout.commonName {
+			entry.CommonName = cleanLifeListField(record[layout.commonName])
+		}
+		if layout.date >= 0 && len(record) > layout.date {
+			entry.FirstSeen = parseLifeListDate(record[layout.date])
+		}
+		if layout.family >= 0 && len(record) > layout.family {
+			entry.Family = cleanLifeListField(record[layout.family])
+		}
+		if err := builder.add(original, entry); err != nil {
+			return lifeListFile{}, err
+		}
+	}
+
+	if malformed > 0 {
+		GetLogger().Warn("Skipped malformed life list rows",
+			logger.Int("skipped_rows", malformed),
+			logger.Int("entries", len(builder.list)))
+	}
+	return lifeListFile{list: builder.list, collisions: builder.collisions, duplicates: builder.duplicates, malformed: malformed, rows: builder.rows + malformed}, nil
+}
+
+// lifeListBuilder collects parsed life list entries into a list keyed by normalized name,
+// whatever file format they were read from.
+type lifeListBuilder struct {
+	opts       lifeListParseOptions
+	list       map[string]LifeListEntry
+	originals  map[string]string // Original scientific name each key was first added under
+	collisions []lifeListCollision
+	duplicates []string // Scientific names of entries repeating an earlier one, once per entry
+	rows       int      // Entries handed to add, whether or not they landed on the list
+}
+
+func newLifeListBuilder(opts lifeListParseOptions) *lifeListBuilder {
+	return &lifeListBuilder{opts: opts, list: map[string]LifeListEntry{}, originals: map[string]string{}}
+}
+
+// add puts an entry on the list under its normalized name. An entry repeating the name of an
+// earlier one, as exports listing every observation do, is merged into it silently. Entries
+// whose different names normalize to the same key are resolved according to the collision
+// policy and recorded as collisions. Both are recorded as duplicates.
+// Entries with a trailing rank marker are skipped unless group matching is enabled.
+// original is the scientific name as written in the file. It fails once the list would
+// grow past the entry limit, so an oversized file stops being read there.
+func (b *lifeListBuilder) add(original string, entry LifeListEntry) error {
+	b.rows++
+	key := lifeListNameKey(entry.ScientificName, b.opts.fuzzy)
+	if groupKey, marked := lifeListGroupKey(entry.ScientificName); marked {
+		if !b.opts.groupMatching || groupKey == "" {
+			GetLogger().Debug("Skipped life list entry with a rank marker",
+				logger.String("scientific_name", entry.ScientificName),
+				logger.Bool("group_matching", b.opts.groupMatching))
+			return nil
+		}
+		key = groupKey
+	}
+
+	existing, exists := b.list[key]
+	if exists {
+		b.duplicates = append(b.duplicates, cleanLifeListField(original))
+		if cleanLifeListField(original) == cleanLifeListField(b.originals[key]) {
+			b.list[key] = mergeLifeListEntries(existing, entry)
+			return nil
+		}
+	}
+	// A kept later entry goes under the key a lookup of its original name produces
+	originalKey := lifeListKey(original)
+	_, taken := b.list[originalKey]
+	keepBoth := exists && b.opts.collisionPolicy == conf.LifeListCollisionKeepBoth && originalKey != key && !taken
+	if (!exists || keepBoth) && b.opts.maxEntries > 0 && len(b.list) >= b.opts.maxEntries {
+		return lifeListTooManyEntriesError(len(b.list)+1, b.opts.maxEntries)
+	}
+	if !exists {
+		b.list[key] = entry
+		b.originals[key] = original
+		return nil
 	}
-	
+
+	collision := lifeListCollision{Key: key, First: b.originals[key], Second: original}
+	if keepBoth {
+		b.list[originalKey] = entry
+		collision.KeptBoth = true
+	} else {
+		b.list[key] = mergeLifeListEntries(existing, entry)
+	}
+	b.collisions = append(b.collisions, collision)
 	return nil
 }
 
+// utf8BOM is the byte order mark spreadsheet applications write at the start of UTF-8 CSV files
+const utf8BOM = "\ufeff"
+
+// skipLifeListBOM returns r without a leading UTF-8 byte order mark, which would otherwise
+// become part of the first field.
+func skipLifeListBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && string(prefix) == utf8BOM {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+	return buffered
+}
+
+// cleanLifeListField trims whitespace and the quotes some exporters wrap around names.
+func cleanLifeListField(field string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(field), `"'`))
+}
+
+// isBlankLifeListRow reports whether every field of a row is empty or whitespace.
+func isBlankLifeListRow(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeLifeListEntries combines two entries for the same species, keeping the first entry's
+// names and family, and the earliest known first-seen date.
+func mergeLifeListEntries(first, second LifeListEntry) LifeListEntry {
+	if first.CommonName == "" {
+		first.CommonName = second.CommonName
+	}
+	if first.Family == "" {
+		first.Family = second.Family
+	}
+	if first.FirstSeen.IsZero() || (!second.FirstSeen.IsZero() && second.FirstSeen.Before(first.FirstSeen)) {
+		first.FirstSeen = second.FirstSeen
+	}
+	return first
+}
+
+// logLifeListCollisions reports normalization collisions found while parsing a life list.
+// Under the warn policy, or an unrecognized one, each collision is logged with both
+// original names; otherwise only at debug level.
+func logLifeListCollisions(policy string, collisions []lifeListCollision) {
+	log := GetLogger()
+	for _, c := range collisions {
+		fields := []logger.Field{
+			logger.String("normalized_name", c.Key),
+			logger.String("first_name", c.First),
+			logger.String("second_name", c.Second),
+			logger.Bool("kept_both", c.KeptBoth),
+			logger.String("policy", policy),
+		}
+		if policy == conf.LifeListCollisionMergeSilently || policy == conf.LifeListCollisionKeepBoth {
+			log.Debug("Life list entries normalize to the same name", fields...)
+			continue
+		}
+		log.Warn("Life list entries normalize to the same name and were merged", fields...)
+	}
+}
+
+// parseLifeListDate parses a first-seen date, returning the zero time when it doesn't match
+// any known layout.
+func parseLifeListDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range lifeListDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// isInLifeList reports whether a species is on the loaded life list. Lookups against a
+// loaded list are counted as hits or misses in the life list metrics, if set.
 func isInLifeList(scientificName string) bool {
-	_, exists := s_life_list[strings.ToLower(scientificName)]
+	_, exists, loaded := loadedLifeList.Lookup(scientificName)
+	if !loaded || scientificName == "" {
+		return false
+	}
+	if m := lifeListMetrics.Load(); m != nil {
+		m.RecordLifeListLookup(exists)
+	}
 	return exists
-}
\ No newline at end of file
+}
+
+// ReloadLifeList loads the life list again from the configured path or URL. A failed
+// reload keeps the current list and is a SeverityWarning error.
+func (p *Processor) ReloadLifeList(ctx context.Context) error {
+	_, err := p.ReloadLifeListWithResult(ctx)
+	return err
+}
+
+// ReloadLifeListWithResult reloads the life list like ReloadLifeList and also reports what
+// the new list holds: its size and the duplicate, malformed and empty inputs found.
+func (p *Processor) ReloadLifeListWithResult(ctx context.Context) (LoadLifeListResult, error) {
+	result, err := loadLifeListWithResult(ctx, p.Settings)
+	return result, lifeListReloadError(err)
+}
@@ -0,0 +1,129 @@
+// life_list_alerts.go: new species alerts for detections missing from the life list
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// NewSpeciesEvent is raised for the first approved detection of a species missing from the
+// loaded life list.
+type NewSpeciesEvent struct {
+	ScientificName string
+	CommonName     string
+	Confidence     float64
+	Source         string // Display name of the audio source
+	DetectedAt     time.Time
+}
+
+// newSpeciesAlerts raises each species' alert once per session and hands it to the
+// subscribers. The zero value is ready to use.
+type newSpeciesAlerts struct {
+	mu          sync.Mutex
+	alerted     map[string]struct{} // Life list keys of the species alerted this session
+	subscribers map[int]func(NewSpeciesEvent)
+	nextID      int
+}
+
+// subscribe adds fn to the subscribers and returns the function removing it again.
+func (a *newSpeciesAlerts) subscribe(fn func(NewSpeciesEvent)) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.subscribers == nil {
+		a.subscribers = make(map[int]func(NewSpeciesEvent))
+	}
+	id := a.nextID
+	a.nextID++
+	a.subscribers[id] = fn
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			delete(a.subscribers, id)
+		})
+	}
+}
+
+// claim marks the species of key as alerted, reporting false when it already was.
+func (a *newSpeciesAlerts) claim(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, done := a.alerted[key]; done {
+		return false
+	}
+	if a.alerted == nil {
+		a.alerted = make(map[string]struct{})
+	}
+	a.alerted[key] = struct{}{}
+	return true
+}
+
+// notify calls every subscriber with event, outside the lock so a subscriber may unsubscribe.
+func (a *newSpeciesAlerts) notify(event NewSpeciesEvent) {
+	a.mu.Lock()
+	subscribers := make([]func(NewSpeciesEvent), 0, len(a.subscribers))
+	for _, fn := range a.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	a.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
+
+// SubscribeNewSpecies calls fn with every new species alert until the returned function is
+// called. fn runs on the detection pipeline, so it must hand slow work off rather than block.
+func (p *Processor) SubscribeNewSpecies(fn func(NewSpeciesEvent)) (unsubscribe func()) {
+	return p.newSpeciesAlerts.subscribe(fn)
+}
+
+// alertNewSpecies raises a new species alert for an approved detection of a species missing
+// from the loaded life list, once per species per session and only at a confidence of at
+// least LifeListAlertThreshold. Species listed as heard only aren't new; detections of
+// species on the list are recorded as suppressed instead. It must run before the detection
+// is added to the life list as heard, so the species is still missing.
+func (p *Processor) alertNewSpecies(det *Detections, confidence float64) {
+	scientificName := det.Result.Species.ScientificName
+	if scientificName == "" || confidence < p.Settings.SoundId.LifeListAlertThreshold {
+		return
+	}
+	_, found, loaded := loadedLifeList.Lookup(scientificName)
+	if !loaded {
+		return
+	}
+	if found {
+		p.lifeListSuppressed.add(SuppressedLifeListDetection{
+			ScientificName: scientificName,
+			CommonName:     det.Result.Species.CommonName,
+			Confidence:     confidence,
+			Source:         det.Result.AudioSource.DisplayName,
+			DetectedAt:     det.Result.BeginTime,
+		})
+		return
+	}
+	if !p.newSpeciesAlerts.claim(lifeListKey(scientificName)) {
+		return
+	}
+
+	event := NewSpeciesEvent{
+		ScientificName: scientificName,
+		CommonName:     det.Result.Species.CommonName,
+		Confidence:     confidence,
+		Source:         det.Result.AudioSource.DisplayName,
+		DetectedAt:     det.Result.BeginTime,
+	}
+	GetLogger().Info("New species detected, not on the life list",
+		logger.String("species", event.CommonName),
+		logger.String("scientific_name", event.ScientificName),
+		logger.Float64("confidence", event.Confidence),
+		logger.String("source", event.Source),
+		logger.String("operation", "life_list_new_species"))
+	p.newSpeciesAlerts.notify(event)
+}
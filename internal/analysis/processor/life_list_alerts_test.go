@@ -0,0 +1,73 @@
+// life_list_alerts_test.go: Tests for new species alerts of detections missing from the life list
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+// newSpeciesDetection returns an approved detection of a species as the pipeline passes it on
+func newSpeciesDetection(scientificName, commonName string, at time.Time) *Detections {
+	return &Detections{Result: detection.Result{
+		Species:     detection.Species{ScientificName: scientificName, CommonName: commonName},
+		AudioSource: detection.AudioSource{DisplayName: "Garden"},
+		BeginTime:   at,
+	}}
+}
+
+func TestAlertNewSpecies_OncePerSpecies(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	var events []NewSpeciesEvent
+	p.SubscribeNewSpecies(func(event NewSpeciesEvent) { events = append(events, event) })
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+
+	p.alertNewSpecies(newSpeciesDetection("Turdus merula", "Eurasian Blackbird", at), 0.9)
+	require.Len(t, events, 1, "the first detection of a species missing from the list alerts")
+	assert.Equal(t, NewSpeciesEvent{
+		ScientificName: "Turdus merula",
+		CommonName:     "Eurasian Blackbird",
+		Confidence:     0.9,
+		Source:         "Garden",
+		DetectedAt:     at,
+	}, events[0])
+
+	// Still missing from the list, as in review mode until the species is confirmed
+	p.alertNewSpecies(newSpeciesDetection("Turdus Merula", "Eurasian Blackbird", at.Add(time.Minute)), 0.95)
+	assert.Len(t, events, 1, "a repeat detection doesn't alert again")
+
+	p.alertNewSpecies(newSpeciesDetection("Parus major", "Great Tit", at), 0.9)
+	recordHeardSpecies(p.Settings, "Erithacus rubecula", "European Robin", at)
+	p.alertNewSpecies(newSpeciesDetection("Erithacus rubecula", "European Robin", at), 0.9)
+	assert.Len(t, events, 1, "species on the list, seen or heard, aren't new")
+}
+
+func TestAlertNewSpecies_ConfidenceThreshold(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.SoundId.LifeListAlertThreshold = 0.8
+	var alerted []float64
+	unsubscribe := p.SubscribeNewSpecies(func(event NewSpeciesEvent) { alerted = append(alerted, event.Confidence) })
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+
+	p.alertNewSpecies(newSpeciesDetection("Turdus merula", "Eurasian Blackbird", at), 0.6)
+	assert.Empty(t, alerted, "detections below the threshold don't alert")
+	p.alertNewSpecies(newSpeciesDetection("Turdus merula", "Eurasian Blackbird", at), 0.8)
+	assert.Equal(t, []float64{0.8}, alerted, "a low-confidence detection doesn't use up the species' alert")
+
+	unsubscribe()
+	unsubscribe()
+	p.alertNewSpecies(newSpeciesDetection("Sitta europaea", "Eurasian Nuthatch", at), 0.9)
+	assert.Len(t, alerted, 1, "unsubscribed functions aren't called")
+}
+
+func TestAlertNewSpecies_NoListLoaded(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	loadedLifeList.Load(nil)
+	alerts := 0
+	p.SubscribeNewSpecies(func(NewSpeciesEvent) { alerts++ })
+	p.alertNewSpecies(newSpeciesDetection("Turdus merula", "Eurasian Blackbird", time.Now()), 0.9)
+	assert.Zero(t, alerts, "without a life list no species is known to be new")
+}
@@ -0,0 +1,270 @@
+// life_list_append.go: adding species marked as seen to the life list and its file
+package processor
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// AddLifeListSpecies adds a species the user reports having seen to the loaded life list,
+// first seen at the given time. When the configured life list is a local file, its row, or
+// for a JSON list its array entry, is added to the file, so it stays on the list after the
+// file is reloaded; a list fetched
+// from a URL keeps it in the status file instead, like merged species. A species already
+// on the list is returned unchanged with added false, and nothing is written. The list in
+// memory only changes once the file was written.
+func (p *Processor) AddLifeListSpecies(scientificName, commonName string, at time.Time) (entry LifeListEntry, added bool, err error) {
+	scientificName = strings.TrimSpace(scientificName)
+	if scientificName == "" {
+		return LifeListEntry{}, false, errors.Newf("scientific name is required").
+			Component("life_list").
+			Category(errors.CategoryValidation).
+			Context("operation", "add").
+			Build()
+	}
+	key := lifeListKey(scientificName)
+
+	// Deferred before the lock, so the callbacks run once the list is unlocked
+	defer func() {
+		if added {
+			notifyLifeListAdd(entry.ScientificName, entry.FirstSeen)
+		}
+	}()
+	lifeListStatusMu.Lock()
+	defer lifeListStatusMu.Unlock()
+
+	current := loadedLifeList.Snapshot()
+	if current == nil {
+		return LifeListEntry{}, false, errors.Newf("no life list is loaded to add to").
+			Component("life_list").
+			Category(errors.CategoryState).
+			Context("operation", "add").
+			Build()
+	}
+	if existing, exists := current[key]; exists {
+		return existing, false, nil
+	}
+
+	entry = LifeListEntry{
+		ScientificName: scientificName,
+		CommonName:     strings.TrimSpace(commonName),
+		FirstSeen:      at,
+		Status:         LifeListStatusSeen,
+	}
+	path := p.Settings.SoundId.LifeListPath
+	appendToFile := path != "" && !isLifeListURL(path)
+	resolved := resolveLifeListPath(path, p.Settings.SoundId.DataDir)
+	if appendToFile {
+		appendEntry := appendLifeListRow
+		if isLifeListJSON(path, p.Settings.SoundId.LifeListFormat) {
+			appendEntry = appendLifeListJSONEntry
+		}
+		if err := appendEntry(resolved, &p.Settings.SoundId, &entry); err != nil {
+			return LifeListEntry{}, false, err
+		}
+	} else {
+		entry.merged = true
+	}
+
+	list := maps.Clone(current)
+	list[key] = entry
+	loadedLifeList.Load(list)
+
+	if !appendToFile {
+		persistLifeListStatuses(p.Settings.SoundId.LifeListStatusPath, list)
+		return entry, true, nil
+	}
+	GetLogger().Info("Appended species to life list file",
+		logger.String("scientific_name", entry.ScientificName),
+		logger.String("path", resolved),
+		logger.String("operation", "life_list_add"))
+	return entry, true, nil
+}
+
+// appendLifeListRow rewrites the life list file at path with a row for entry added at the
+// end, laid out in the configured format and written in the configured encoding. When the
+// format is detected, the columns follow the file's header row if it has one.
+func appendLifeListRow(path string, settings *conf.SoundIdConfig, entry *LifeListEntry) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "append_read").
+			Build()
+	}
+
+	layout, detect := lifeListLayoutForFormat(settings.LifeListFormat, settings.LifeListScientificNameColumn, settings.LifeListCommonNameColumn)
+	if detect {
+		if header, ok := lifeListHeaderLayout(data, settings.LifeListEncoding); ok {
+			layout = header
+		}
+	}
+
+	row := make([]string, max(layout.commonName, layout.scientificName, layout.date, layout.family)+1)
+	row[layout.scientificName] = entry.ScientificName
+	if layout.commonName >= 0 {
+		row[layout.commonName] = entry.CommonName
+	}
+	if layout.date >= 0 {
+		row[layout.date] = entry.FirstSeen.Format(lifeListDateLayouts[0])
+	}
+	if layout.family >= 0 {
+		row[layout.family] = entry.Family
+	}
+
+	crlf := bytes.Contains(data, []byte("\r\n"))
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.UseCRLF = crlf
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+	writer.Flush()
+	encoded, err := encodeLifeList(buf.Bytes(), settings.LifeListEncoding)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		if crlf {
+			data = append(data, '\r')
+		}
+		data = append(data, '\n')
+	}
+	if err := writeFileAtomic(path, append(data, encoded...)); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "append_write").
+			Build()
+	}
+	return nil
+}
+
+// appendLifeListJSONEntry rewrites the JSON life list file at path with entry added at the
+// end of its array, keeping the rest of the file as written. A list of plain scientific
+// names gets another name; any other list gets an object with the names and first-seen date.
+func appendLifeListJSONEntry(path string, settings *conf.SoundIdConfig, entry *LifeListEntry) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "append_read").
+			Build()
+	}
+	decoded, err := decodeLifeList(bytes.NewReader(data), settings.LifeListEncoding)
+	if err != nil {
+		return err
+	}
+	text, err := io.ReadAll(decoded)
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "append_read").
+			Build()
+	}
+	bom := bytes.HasPrefix(text, []byte(utf8BOM))
+	text = bytes.TrimPrefix(text, []byte(utf8BOM))
+
+	var items []json.RawMessage
+	decoder := json.NewDecoder(bytes.NewReader(text))
+	if err := decoder.Decode(&items); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "append_read").
+			Context("format", conf.LifeListFormatJSON).
+			Build()
+	}
+
+	var value any = entry.ScientificName
+	plainNames := len(items) > 0 && !slices.ContainsFunc(items, func(item json.RawMessage) bool { return !isJSONString(item) })
+	if !plainNames {
+		value = lifeListJSONEntry{
+			ScientificName: entry.ScientificName,
+			CommonName:     entry.CommonName,
+			FirstSeen:      entry.FirstSeen.Format(lifeListDateLayouts[0]),
+			Family:         entry.Family,
+		}
+	}
+	encodedEntry, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	// Insert before the array's closing bracket, in the layout of the array
+	end := int(decoder.InputOffset()) - 1
+	head := bytes.TrimRightFunc(text[:end], unicode.IsSpace)
+	var buf bytes.Buffer
+	if bom {
+		buf.WriteString(utf8BOM)
+	}
+	buf.Write(head)
+	switch {
+	case len(items) == 0:
+	case bytes.ContainsRune(head, '\n'):
+		buf.WriteString(",\n  ")
+	default:
+		buf.WriteString(", ")
+	}
+	buf.Write(encodedEntry)
+	if bytes.ContainsRune(head, '\n') {
+		buf.WriteByte('\n')
+	}
+	buf.Write(text[end:])
+
+	encoded, err := encodeLifeList(buf.Bytes(), settings.LifeListEncoding)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, encoded); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "append_write").
+			Build()
+	}
+	return nil
+}
+
+// isJSONString reports whether a raw JSON value is a string.
+func isJSONString(item json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(item)
+	return len(trimmed) > 0 && trimmed[0] == '"'
+}
+
+// lifeListHeaderLayout returns the layout given by the header row of a life list file, if
+// its first non-blank row is one.
+func lifeListHeaderLayout(data []byte, encodingName string) (lifeListLayout, bool) {
+	decoded, err := decodeLifeList(bytes.NewReader(data), encodingName)
+	if err != nil {
+		return lifeListLayout{}, false
+	}
+	reader := csv.NewReader(skipLifeListBOM(decoded))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			return lifeListLayout{}, false // Also io.EOF, for a file of blank rows
+		}
+		if !isBlankLifeListRow(record) {
+			return lifeListLayoutFromHeader(record)
+		}
+	}
+}
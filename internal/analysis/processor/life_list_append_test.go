@@ -0,0 +1,166 @@
+// life_list_append_test.go: Tests for adding species to the life list and its file
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestAddLifeListSpecies_PersistsToFile(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	seenAt := time.Date(2026, 6, 2, 7, 0, 0, 0, time.Local)
+
+	entry, added, err := p.AddLifeListSpecies("Apus apus", "Common Swift", seenAt)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, LifeListStatusSeen, entry.Status)
+	assert.True(t, isInLifeList("Apus apus"))
+
+	data, err := os.ReadFile(p.Settings.SoundId.LifeListPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1,1,species,Great Tit,Parus major,1,Home,,2001-06-01\n,,,Common Swift,Apus apus,,,,2026-06-02\n", string(data))
+
+	// The appended row is read back when the file is loaded again
+	loadedLifeList.Load(nil)
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	swift, ok := lookupLifeList("Apus apus")
+	require.True(t, ok)
+	assert.Equal(t, "Common Swift", swift.CommonName)
+	assert.True(t, swift.FirstSeen.Equal(time.Date(2026, 6, 2, 0, 0, 0, 0, time.Local)))
+}
+
+func TestAddLifeListSpecies_DuplicateKeepsFile(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	before, err := os.ReadFile(p.Settings.SoundId.LifeListPath)
+	require.NoError(t, err)
+
+	entry, added, err := p.AddLifeListSpecies("parus MAJOR", "", time.Now())
+	require.NoError(t, err)
+	assert.False(t, added, "a species already on the list isn't added again")
+	assert.Equal(t, "Parus major", entry.ScientificName)
+
+	after, err := os.ReadFile(p.Settings.SoundId.LifeListPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestAddLifeListSpecies_ConcurrentAppends(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+
+	const species = 8
+	var wg sync.WaitGroup
+	for i := range species {
+		for range 2 {
+			wg.Go(func() {
+				_, _, err := p.AddLifeListSpecies(fmt.Sprintf("Genus species%d", i), "", time.Now())
+				assert.NoError(t, err)
+			})
+		}
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(p.Settings.SoundId.LifeListPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, species+1, "each species is appended exactly once")
+	assert.Len(t, p.LifeListEntries(), species+1)
+}
+
+func TestAddLifeListSpecies_FollowsHeaderLayout(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "MyEBirdData.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Common Name,Scientific Name,Date\r\nGreat Tit,Parus major,2001-06-01"), 0o644))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+
+	_, added, err := p.AddLifeListSpecies("Apus apus", "Common Swift", time.Date(2026, 6, 2, 7, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.True(t, added)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Common Name,Scientific Name,Date\r\nGreat Tit,Parus major,2001-06-01\r\nCommon Swift,Apus apus,2026-06-02\r\n", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm(), "the rewritten file keeps its permissions")
+}
+
+func TestAddLifeListSpecies_JSONList(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{"names", `["Parus major"]`, `["Parus major", "Apus apus"]`},
+		{"empty", "[]\n", "[{\"scientificName\":\"Apus apus\",\"commonName\":\"Common Swift\",\"firstSeen\":\"2026-06-02\"}]\n"},
+		{
+			"objects",
+			"[\n  {\"scientificName\": \"Parus major\"}\n]\n",
+			"[\n  {\"scientificName\": \"Parus major\"},\n  {\"scientificName\":\"Apus apus\",\"commonName\":\"Common Swift\",\"firstSeen\":\"2026-06-02\"}\n]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := loadedLifeList.Snapshot()
+			t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+			path := filepath.Join(t.TempDir(), "lifelist.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+			p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+			require.NoError(t, p.ReloadLifeList(t.Context()))
+
+			_, added, err := p.AddLifeListSpecies("Apus apus", "Common Swift", time.Date(2026, 6, 2, 7, 0, 0, 0, time.Local))
+			require.NoError(t, err)
+			require.True(t, added)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+
+			// The added species is read back when the file is loaded again
+			loadedLifeList.Load(nil)
+			require.NoError(t, p.ReloadLifeList(t.Context()))
+			assert.True(t, isInLifeList("Apus apus"))
+			assert.Equal(t, strings.Count(tt.contents, "Parus major")+1, LifeListCount())
+		})
+	}
+}
+
+func TestAddLifeListSpecies_URLListKeepsSpeciesInStatusFile(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	before, err := os.ReadFile(p.Settings.SoundId.LifeListPath)
+	require.NoError(t, err)
+	p.Settings.SoundId.LifeListPath = "https://example.com/lifelist.csv"
+
+	entry, added, err := p.AddLifeListSpecies("Apus apus", "Common Swift", time.Now())
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.True(t, entry.merged, "a species that can't be appended is persisted like a merged one")
+	assert.FileExists(t, p.Settings.SoundId.LifeListStatusPath)
+
+	after, err := os.ReadFile(filepath.Join(filepath.Dir(p.Settings.SoundId.LifeListStatusPath), "lifelist.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestAddLifeListSpecies_RequiresName(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+
+	_, _, err := p.AddLifeListSpecies(" ", "", time.Now())
+	var enhancedErr *errors.EnhancedError
+	require.ErrorAs(t, err, &enhancedErr)
+	assert.Equal(t, errors.CategoryValidation, enhancedErr.Category)
+}
@@ -0,0 +1,92 @@
+// life_list_audit.go: audit trail of life list lookups for potential lifers
+package processor
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLifeListAuditEntries bounds the audit log; the oldest entries are dropped first
+const maxLifeListAuditEntries = 200
+
+// Life list audit results
+const (
+	LifeListAuditNotOnList = "not_on_list"    // No entry under the queried name, so the detection is a lifer
+	LifeListAuditHeardOnly = "heard_only"     // On the list as heard only, so not a new lifer
+	LifeListAuditNoList    = "no_list_loaded" // No life list was loaded to match against
+)
+
+// LifeListAuditEntry records how an approved detection missing from the seen life list was
+// matched, to explain why a lifer alert did or didn't fire.
+type LifeListAuditEntry struct {
+	Time           time.Time
+	ScientificName string // Name as detected
+	CommonName     string
+	QueriedName    string // Normalized name looked up in the life list
+	Result         string // One of the LifeListAudit* results
+	FuzzyAttempted bool   // Whether fuzzy or species group matching was tried
+}
+
+// lifeListAuditLog keeps the most recent audit entries. The zero value is ready to use.
+type lifeListAuditLog struct {
+	mu      sync.Mutex
+	entries []LifeListAuditEntry
+}
+
+// add stores an entry, evicting the oldest once the limit is reached.
+func (l *lifeListAuditLog) add(entry LifeListAuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) >= maxLifeListAuditEntries {
+		l.entries = l.entries[len(l.entries)-maxLifeListAuditEntries+1:]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+// list returns a copy of the entries, newest first.
+func (l *lifeListAuditLog) list() []LifeListAuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]LifeListAuditEntry, len(l.entries))
+	for i, entry := range l.entries {
+		out[len(out)-1-i] = entry
+	}
+	return out
+}
+
+// auditLifeListMatch records the life list lookup for an approved detection when the audit
+// log is enabled. Species already seen aren't potential lifers and aren't recorded. It must
+// run before the detection is added to the list as heard, so it sees the list the lifer
+// check saw.
+func (p *Processor) auditLifeListMatch(scientificName, commonName string, at time.Time) {
+	if !p.Settings.SoundId.LifeListAuditEnabled || scientificName == "" {
+		return
+	}
+
+	entry := LifeListAuditEntry{
+		Time:           at,
+		ScientificName: scientificName,
+		CommonName:     commonName,
+		QueriedName:    strings.ToLower(scientificName),
+		FuzzyAttempted: lifeListFuzzy.Load() || p.Settings.SoundId.LifeListGroupMatching,
+	}
+	switch existing, found, loaded := loadedLifeList.Lookup(scientificName); {
+	case !loaded:
+		entry.Result = LifeListAuditNoList
+	case !found:
+		entry.Result = LifeListAuditNotOnList
+	case existing.Status == LifeListStatusHeard:
+		entry.Result = LifeListAuditHeardOnly
+	default:
+		return
+	}
+	p.lifeListAudit.add(entry)
+}
+
+// LifeListAudit returns the recorded life list audit entries, newest first.
+func (p *Processor) LifeListAudit() []LifeListAuditEntry {
+	return p.lifeListAudit.list()
+}
@@ -0,0 +1,62 @@
+// life_list_audit_test.go: Tests for the life list matching audit log
+package processor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLifeListMatch_RecordsPotentialLifers(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.SoundId.LifeListAuditEnabled = true
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+
+	// A first detection of a species missing from the list, approved as the pipeline does
+	p.auditLifeListMatch("Turdus Merula", "Eurasian Blackbird", at)
+	recordHeardSpecies(p.Settings, "Turdus Merula", "Eurasian Blackbird", at)
+	// Heard again: on the list now, but still not seen
+	p.auditLifeListMatch("Turdus Merula", "Eurasian Blackbird", at.Add(time.Minute))
+	// Already seen species aren't potential lifers
+	p.auditLifeListMatch("Parus major", "Great Tit", at)
+
+	entries := p.LifeListAudit()
+	require.Len(t, entries, 2)
+	assert.Equal(t, LifeListAuditHeardOnly, entries[0].Result, "newest entry first")
+
+	first := entries[1]
+	assert.Equal(t, "Turdus Merula", first.ScientificName)
+	assert.Equal(t, "turdus merula", first.QueriedName, "the normalized name that was looked up")
+	assert.Equal(t, LifeListAuditNotOnList, first.Result)
+	assert.False(t, first.FuzzyAttempted)
+	assert.Equal(t, at, first.Time)
+}
+
+func TestAuditLifeListMatch_RecordsFuzzyAttempted(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.SoundId.LifeListAuditEnabled = true
+	p.Settings.SoundId.LifeListGroupMatching = true
+
+	p.auditLifeListMatch("Turdus merula", "Eurasian Blackbird", time.Now())
+	entries := p.LifeListAudit()
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].FuzzyAttempted, "group matching was tried for the lookup")
+}
+
+func TestAuditLifeListMatch_DisabledAndBounded(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+
+	p.auditLifeListMatch("Turdus merula", "Eurasian Blackbird", time.Now())
+	assert.Empty(t, p.LifeListAudit(), "nothing is recorded unless enabled")
+
+	p.Settings.SoundId.LifeListAuditEnabled = true
+	for i := range maxLifeListAuditEntries + 5 {
+		p.auditLifeListMatch(fmt.Sprintf("Species %d", i), "", time.Now())
+	}
+	entries := p.LifeListAudit()
+	require.Len(t, entries, maxLifeListAuditEntries)
+	assert.Equal(t, fmt.Sprintf("Species %d", maxLifeListAuditEntries+4), entries[0].ScientificName)
+}
@@ -0,0 +1,107 @@
+// life_list_authority.go: canonicalization of life list names against a taxonomy authority
+package processor
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lifeListAuthority maps scientific names to the authority's preferred name. Each row of an
+// authority file holds a preferred scientific name followed by any number of its synonyms.
+type lifeListAuthority struct {
+	preferred map[string]string // Key of a preferred name or synonym to the preferred name
+	fuzzy     bool              // Keys are fuzzy life list keys
+}
+
+// loadLifeListAuthority reads the configured authority file or URL.
+func loadLifeListAuthority(ctx context.Context, settings *conf.Settings) (*lifeListAuthority, error) {
+	path := settings.SoundId.LifeListAuthorityPath
+	if path == "" {
+		return nil, errors.Newf("Life list authority is enabled but no authority path is set").
+			Component("life_list").
+			Category(errors.CategoryConfiguration).
+			Build()
+	}
+
+	reader, err := openLifeList(ctx, resolveLifeListPath(path, settings.SoundId.DataDir), lifeListMaxBytes(settings))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return parseLifeListAuthority(reader, settings.SoundId.LifeListFuzzy)
+}
+
+// parseLifeListAuthority reads authority CSV records keyed the way a life list read with
+// fuzzy matching, or without, is. Blank fields are ignored, and a row whose first field is
+// "scientific name" is taken as a header.
+func parseLifeListAuthority(r io.Reader, fuzzy bool) (*lifeListAuthority, error) {
+	authority := &lifeListAuthority{preferred: map[string]string{}, fuzzy: fuzzy}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Species have different numbers of synonyms
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(err).
+				Component("life_list").
+				Category(errors.CategoryFileIO).
+				Context("operation", "read_authority").
+				Build()
+		}
+
+		preferred := strings.TrimSpace(record[0])
+		if preferred == "" || strings.EqualFold(preferred, "scientific name") {
+			continue
+		}
+		for _, name := range record {
+			if name = strings.TrimSpace(name); name != "" {
+				authority.preferred[lifeListNameKey(name, fuzzy)] = preferred
+			}
+		}
+	}
+
+	return authority, nil
+}
+
+// canonicalize returns list with each entry renamed to the authority's preferred scientific
+// name. Entries the authority doesn't know are kept as they are. When a synonym and its
+// preferred name are both on the list, the entries are merged.
+func (a *lifeListAuthority) canonicalize(list map[string]LifeListEntry) map[string]LifeListEntry {
+	canonical := make(map[string]LifeListEntry, len(list))
+	log := GetLogger()
+
+	for key, entry := range list {
+		preferred, known := a.preferred[key]
+		if !known {
+			preferred = entry.ScientificName
+		} else if !strings.EqualFold(preferred, entry.ScientificName) {
+			log.Info("Remapped life list entry to its preferred name",
+				logger.String("scientific_name", entry.ScientificName),
+				logger.String("preferred_name", preferred),
+				logger.String("common_name", entry.CommonName))
+		}
+		entry.ScientificName = preferred
+
+		canonicalKey := lifeListNameKey(preferred, a.fuzzy)
+		if !known {
+			canonicalKey = key // Keep entries stored under their original name by a collision policy
+		}
+		if existing, exists := canonical[canonicalKey]; exists {
+			entry = mergeLifeListEntries(existing, entry)
+			entry.ScientificName = preferred
+		}
+		canonical[canonicalKey] = entry
+	}
+
+	return canonical
+}
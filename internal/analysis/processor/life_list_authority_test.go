@@ -0,0 +1,80 @@
+// life_list_authority_test.go: Tests for canonicalizing life list names against an authority
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLoadLifeList_CanonicalizesSynonymsAgainstAuthority(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "lifelist.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte(
+		"1,1,species,Gray Jay,Perisoreus canadensis\n"+
+			"2,2,species,Common Raven,Corvus corax\n"+
+			"3,3,species,Black-capped Chickadee,Parus atricapillus\n"), 0o600))
+	authorityPath := filepath.Join(dir, "authority.csv")
+	require.NoError(t, os.WriteFile(authorityPath, []byte(
+		"scientific name,synonyms\n"+
+			"Poecile atricapillus,Parus atricapillus\n"+
+			"Corvus corax\n"), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:             listPath,
+		LifeListAuthorityEnabled: true,
+		LifeListAuthorityPath:    authorityPath,
+	}}
+	require.NoError(t, loadLifeList(settings))
+
+	list := loadedLifeList.Snapshot()
+	assert.Len(t, list, 3)
+	assert.Equal(t, "Poecile atricapillus", list["poecile atricapillus"].ScientificName)
+	assert.Equal(t, "Black-capped Chickadee", list["poecile atricapillus"].CommonName)
+	assert.True(t, isInLifeList("Poecile atricapillus"), "the current name must match after canonicalization")
+	assert.False(t, isInLifeList("Parus atricapillus"))
+	assert.True(t, isInLifeList("Perisoreus canadensis"), "names the authority doesn't know are kept")
+}
+
+func TestLifeListAuthority_MergesSynonymWithPreferredEntry(t *testing.T) {
+	t.Parallel()
+
+	authority, err := parseLifeListAuthority(strings.NewReader("Poecile atricapillus,Parus atricapillus\n"), false)
+	require.NoError(t, err)
+
+	list, _, err := parseLifeList(strings.NewReader(
+		"1,1,species,Black-capped Chickadee,Parus atricapillus,,,,1998-04-02\n"+
+			"2,2,species,Black-capped Chickadee,Poecile atricapillus,,,,2012-06-10\n"), lifeListParseOptions{collisionPolicy: conf.LifeListCollisionWarn})
+	require.NoError(t, err)
+
+	canonical := authority.canonicalize(list)
+	require.Len(t, canonical, 1)
+	entry := canonical["poecile atricapillus"]
+	assert.Equal(t, "Poecile atricapillus", entry.ScientificName)
+	assert.Equal(t, 1998, entry.FirstSeen.Year(), "the merged entry keeps the earliest sighting")
+}
+
+func TestLifeListAuthority_KeysFuzzyLists(t *testing.T) {
+	t.Parallel()
+
+	authority, err := parseLifeListAuthority(strings.NewReader("Poecile  atricapillus,Parus atricapillus turneri\n"), true)
+	require.NoError(t, err)
+
+	list, _, err := parseLifeList(strings.NewReader(
+		"1,1,species,Black-capped Chickadee,Parus atricapillus\n"), lifeListParseOptions{fuzzy: true})
+	require.NoError(t, err)
+
+	canonical := authority.canonicalize(list)
+	require.Len(t, canonical, 1)
+	entry, ok := canonical["poecile atricapillus"]
+	require.True(t, ok, "the entry is stored under the fuzzy key of the preferred name")
+	assert.Equal(t, "Poecile  atricapillus", entry.ScientificName)
+}
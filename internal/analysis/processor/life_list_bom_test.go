@@ -0,0 +1,58 @@
+// life_list_bom_test.go: Tests for byte order marks, padding and quotes around life list names
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestParseLifeList_LeadingBOM(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		opts  lifeListParseOptions
+	}{
+		{
+			name:  "name in first column",
+			input: "\ufeffTurdus migratorius\nCardinalis cardinalis\n",
+			opts:  lifeListParseOptions{format: conf.LifeListFormatLegacy, scientificNameColumn: 0},
+		},
+		{
+			name:  "quoted first field",
+			input: "\ufeff\"1\",\"1\",\"species\",\"American Robin\",\"Turdus migratorius\"\n",
+			opts:  lifeListParseOptions{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			list, _, err := parseLifeList(strings.NewReader(tt.input), tt.opts)
+			require.NoError(t, err)
+			require.Contains(t, list, "turdus migratorius")
+			assert.Equal(t, "Turdus migratorius", list["turdus migratorius"].ScientificName)
+		})
+	}
+}
+
+func TestParseLifeList_PaddedAndQuotedNames(t *testing.T) {
+	t.Parallel()
+
+	input := "1,1,species,  American Robin ,  Turdus migratorius  \n" +
+		"2,2,species, \"Northern Cardinal\", \"Cardinalis cardinalis\"\n" +
+		"3,3,species,'Blue Jay',\"'Cyanocitta cristata'\"\n"
+	list, _, err := parseLifeList(strings.NewReader(input), lifeListParseOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, LifeListEntry{ScientificName: "Turdus migratorius", CommonName: "American Robin", Status: LifeListStatusSeen},
+		list["turdus migratorius"])
+	assert.Equal(t, "Northern Cardinal", list["cardinalis cardinalis"].CommonName)
+	assert.Equal(t, "Cardinalis cardinalis", list["cardinalis cardinalis"].ScientificName)
+	assert.Equal(t, "Blue Jay", list["cyanocitta cristata"].CommonName)
+}
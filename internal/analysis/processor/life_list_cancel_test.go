@@ -0,0 +1,81 @@
+// life_list_cancel_test.go: Tests for giving up on a life list load when its context ends
+package processor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// cancellingLifeListReader serves rows of a synthetic life list, cancelling the load once
+// cancelAfter rows were served
+type cancellingLifeListReader struct {
+	rows, cancelAfter, served int
+	cancel                    context.CancelFunc
+	pending                   strings.Reader
+}
+
+func (r *cancellingLifeListReader) Read(p []byte) (int, error) {
+	if r.pending.Len() == 0 {
+		if r.served == r.rows {
+			return 0, io.EOF
+		}
+		if r.served == r.cancelAfter {
+			r.cancel()
+		}
+		r.pending.Reset(fmt.Sprintf("%d,%d,species,Species %d,Genus species%d\n", r.served, r.served, r.served, r.served))
+		r.served++
+	}
+	return r.pending.Read(p)
+}
+
+func TestParseLifeList_StopsWhenCancelledMidLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	source := &cancellingLifeListReader{rows: 100000, cancelAfter: 1000, cancel: cancel}
+
+	list, _, err := parseLifeList(&lifeListContextReader{ctx: ctx, r: source}, lifeListParseOptions{})
+	require.Error(t, err)
+	assert.Nil(t, list)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, errors.IsCategory(err, errors.CategoryCancellation))
+	assert.Greater(t, source.served, source.cancelAfter, "rows were read before the cancellation")
+	assert.Less(t, source.served, source.rows, "the rest of the file isn't read")
+}
+
+func TestLoadLifeList_CancelledLoadKeepsList(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "life_list.csv"), []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "life_list.csv", DataDir: dir}}
+	require.NoError(t, loadLifeList(settings))
+
+	var rows strings.Builder
+	for i := range 50000 {
+		fmt.Fprintf(&rows, "%d,%d,species,Species %d,Genus species%d\n", i, i, i, i)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "life_list.csv"), []byte(rows.String()), 0o600))
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	err := loadLifeListCtx(ctx, settings)
+	require.Error(t, err)
+	assert.True(t, errors.IsCategory(err, errors.CategoryTimeout), "a passed deadline is reported as a timeout")
+	assert.True(t, isInLifeList("Parus major"), "the list loaded before stays in use")
+	assert.False(t, isInLifeList("Genus species1"))
+}
@@ -0,0 +1,85 @@
+// life_list_collision_test.go: Tests for life list entries that normalize to the same name
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// collidingLifeList has two entries that only differ by case and surrounding whitespace
+const collidingLifeList = "1,1,species,Great Tit,Parus major,,,,2020-05-01\n" +
+	"2,2,species,Kohlmeise, Parus Major ,,,,2019-04-01\n"
+
+func TestParseLifeList_CollisionPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policy   string
+		wantKeys []string
+		keptBoth bool
+	}{
+		{conf.LifeListCollisionMergeSilently, []string{"parus major"}, false},
+		{conf.LifeListCollisionWarn, []string{"parus major"}, false},
+		{conf.LifeListCollisionKeepBoth, []string{"parus major", " parus major "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Parallel()
+
+			list, collisions, err := parseLifeList(strings.NewReader(collidingLifeList), lifeListParseOptions{collisionPolicy: tt.policy})
+			require.NoError(t, err)
+
+			assert.Len(t, list, len(tt.wantKeys))
+			for _, key := range tt.wantKeys {
+				assert.Contains(t, list, key)
+			}
+
+			require.Len(t, collisions, 1, "the collision is reported under every policy")
+			assert.Equal(t, "parus major", collisions[0].Key)
+			assert.Equal(t, "Parus major", collisions[0].First)
+			assert.Equal(t, " Parus Major ", collisions[0].Second)
+			assert.Equal(t, tt.keptBoth, collisions[0].KeptBoth)
+
+			kept := list["parus major"]
+			assert.Equal(t, "Great Tit", kept.CommonName, "the first entry's names are kept")
+			if !tt.keptBoth {
+				assert.Equal(t, 2019, kept.FirstSeen.Year(), "a merge keeps the earliest first-seen date")
+				return
+			}
+			second, found := findLifeListEntry(list, " Parus Major ")
+			require.True(t, found, "the kept entry is found by a lookup of its original name")
+			assert.Equal(t, "Kohlmeise", second.CommonName)
+		})
+	}
+}
+
+func TestParseLifeList_DistinctNamesDoNotCollide(t *testing.T) {
+	t.Parallel()
+
+	input := "1,1,species,Great Tit,Parus major\n2,2,species,Blue Tit,Cyanistes caeruleus\n"
+	list, collisions, err := parseLifeList(strings.NewReader(input), lifeListParseOptions{collisionPolicy: conf.LifeListCollisionWarn})
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Empty(t, collisions)
+}
+
+func TestParseLifeList_ExactRepeatsMergeWithoutCollision(t *testing.T) {
+	t.Parallel()
+
+	// My eBird Data exports list every observation, so a species repeats row after row
+	input := "1,1,species,Great Tit,Parus major,,,,2020-05-01\n" +
+		"2,2,species,Great Tit,Parus major,,,,2018-03-01\n" +
+		"3,3,species,Great Tit,Parus major ,,,,2021-07-01\n"
+	file, err := parseLifeListRows(strings.NewReader(input), lifeListParseOptions{collisionPolicy: conf.LifeListCollisionWarn})
+	require.NoError(t, err)
+
+	require.Len(t, file.list, 1)
+	assert.Equal(t, 2018, file.list["parus major"].FirstSeen.Year(), "repeats keep the earliest first-seen date")
+	assert.Empty(t, file.collisions, "a repeated name isn't a collision")
+	assert.Equal(t, []string{"Parus major", "Parus major"}, file.duplicates)
+}
@@ -0,0 +1,73 @@
+// life_list_column_test.go: Tests for the configurable scientific name column of legacy life lists
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestParseLifeList_ScientificNameColumn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		column int
+	}{
+		{"first column", "Parus major,seen in the garden\nCyanistes caeruleus,\n", 0},
+		{"custom column", "2023,Garden,Parus major,Great Tit\n2024,Park,Cyanistes caeruleus,Blue Tit\n", 2},
+		{"default column", "1,1,species,Great Tit,Parus major\n2,2,species,Eurasian Blue Tit,Cyanistes caeruleus\n", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := lifeListParseOptions{format: conf.LifeListFormatLegacy, scientificNameColumn: tt.column}
+			list, _, err := parseLifeList(strings.NewReader(tt.input), opts)
+			require.NoError(t, err)
+			assert.Len(t, list, 2)
+			assert.Equal(t, "Parus major", list["parus major"].ScientificName)
+			assert.Contains(t, list, "cyanistes caeruleus")
+		})
+	}
+}
+
+func TestParseLifeList_ScientificNameColumnOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	opts := lifeListParseOptions{format: conf.LifeListFormatLegacy, scientificNameColumn: 7}
+	_, _, err := parseLifeList(strings.NewReader("1,1,species,Great Tit,Parus major\n"), opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the scientific name is in column 8")
+
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, 1, enhanced.GetContext()["line"])
+}
+
+func TestLoadLifeList_NegativeScientificNameColumn(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:                 path,
+		LifeListFormat:               conf.LifeListFormatLegacy,
+		LifeListScientificNameColumn: -1,
+	}}
+	err := loadLifeList(settings)
+	require.Error(t, err)
+
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategoryConfiguration), enhanced.GetCategory())
+}
@@ -0,0 +1,62 @@
+// life_list_common_name_test.go: Tests for common names kept alongside life list species
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLifeList_CommonNameBothDirections(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Great Tit,Parus major\nEurasian Blue Tit,Cyanistes caeruleus\n"), 0o600))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:                 path,
+		LifeListFormat:               conf.LifeListFormatLegacy,
+		LifeListScientificNameColumn: 1,
+		LifeListCommonNameColumn:     0,
+	}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+
+	assert.True(t, isInLifeList("Parus major"))
+	common, ok := p.CommonName("cyanistes CAERULEUS")
+	require.True(t, ok)
+	assert.Equal(t, "Eurasian Blue Tit", common)
+
+	scientific, ok := p.ScientificName("great tit")
+	require.True(t, ok)
+	assert.Equal(t, "Parus major", scientific)
+
+	_, ok = p.CommonName("Strix varia")
+	assert.False(t, ok)
+	_, ok = p.ScientificName("Barred Owl")
+	assert.False(t, ok)
+}
+
+func TestLifeList_WithoutCommonNameColumn(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Parus major,2001-06-01\n"), 0o600))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:                 path,
+		LifeListFormat:               conf.LifeListFormatLegacy,
+		LifeListScientificNameColumn: 0,
+		LifeListCommonNameColumn:     -1,
+	}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+
+	common, ok := p.CommonName("Parus major")
+	require.True(t, ok, "the species is on the list even without a common name")
+	assert.Empty(t, common)
+	_, ok = p.ScientificName("")
+	assert.False(t, ok, "an empty common name never matches")
+}
@@ -0,0 +1,35 @@
+// life_list_duplicates_test.go: Tests for reporting duplicate life list rows on load
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLoadLifeListWithResult_CountsDuplicates(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	rows := strings.Join([]string{
+		"1,1,species,Great Tit,Parus major",
+		"2,2,species,Eurasian Blackbird,Turdus merula",
+		"3,3,species,Great Tit,Parus major",
+		"4,4,species,Common Swift,Apus apus",
+		"5,5,species,Great Tit, parus major ",
+		"6,6,species,Eurasian Blackbird,Turdus merula",
+	}, "\n")
+	require.NoError(t, os.WriteFile(path, []byte(rows), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}
+	result, err := loadLifeListWithResult(t.Context(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Loaded)
+	assert.Equal(t, []string{"Parus major", "parus major", "Turdus merula"}, result.Duplicates)
+}
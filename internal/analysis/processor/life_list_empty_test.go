@@ -0,0 +1,58 @@
+// life_list_empty_test.go: Tests for life list files without any species rows
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLoadLifeList_EmptyFileLoadsWithWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"empty file", ""},
+		{"header only", "Row #,Taxon Order,Category,Common Name,Scientific Name,Count,Location,S/P,Date\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := loadedLifeList.Snapshot()
+			t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "life_list.csv"), []byte(tt.contents), 0o600))
+			settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "life_list.csv", DataDir: dir}}
+
+			result, err := loadLifeListWithResult(t.Context(), settings)
+			require.NoError(t, err, "an empty life list is a warning, not an error")
+			assert.Zero(t, result.Loaded)
+			assert.Equal(t, []string{"life_list.csv"}, result.Empty)
+			require.NotNil(t, loadedLifeList.Snapshot())
+			assert.Empty(t, loadedLifeList.Snapshot())
+		})
+	}
+}
+
+func TestLoadLifeList_ReportsEmptyFileAmongSeveral(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "home.csv"), []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blank.csv"), nil, 0o600))
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:  "home.csv",
+		LifeListPaths: []string{"blank.csv"},
+		DataDir:       dir,
+	}}
+
+	result, err := loadLifeListWithResult(t.Context(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Loaded)
+	assert.Equal(t, []string{"blank.csv"}, result.Empty)
+}
@@ -0,0 +1,42 @@
+// life_list_encoding_test.go: Tests for transcoding non-UTF-8 life list files
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLoadLifeList_Latin1Encoding(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	// "Mésange charbonnière" and "Pic épeiche" encoded as ISO-8859-1
+	latin1 := []byte("1,1,species,M\xe9sange charbonni\xe8re,Parus major\n" +
+		"2,2,species,Pic \xe9peiche,Dendrocopos major\n")
+	path := filepath.Join(t.TempDir(), "lifelist-latin1.csv")
+	require.NoError(t, os.WriteFile(path, latin1, 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path, LifeListEncoding: "latin1"}}
+	require.NoError(t, loadLifeList(settings))
+
+	list := loadedLifeList.Snapshot()
+	assert.Equal(t, "Mésange charbonnière", list["parus major"].CommonName)
+	assert.Equal(t, "Pic épeiche", list["dendrocopos major"].CommonName)
+	assert.True(t, isInLifeList("Dendrocopos major"))
+}
+
+func TestLoadLifeList_UnsupportedEncoding(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path, LifeListEncoding: "ebcdic"}}
+	require.Error(t, loadLifeList(settings))
+}
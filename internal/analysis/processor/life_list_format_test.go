@@ -0,0 +1,69 @@
+// life_list_format_test.go: Tests for life list CSV format detection
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// parseLifeListFixture parses a life list file from testdata with the given format and the
+// default name columns.
+func parseLifeListFixture(t *testing.T, name, format string) map[string]LifeListEntry {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	list, _, err := parseLifeList(file, lifeListParseOptions{format: format, scientificNameColumn: 4, commonNameColumn: 3})
+	require.NoError(t, err)
+	return list
+}
+
+func TestParseLifeList_Formats(t *testing.T) {
+	t.Parallel()
+
+	march := time.Date(2023, 3, 2, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		fixture string
+		format  string
+	}{
+		{"ebird detected", "lifelist_ebird.csv", conf.LifeListFormatAuto},
+		{"ebird forced", "lifelist_ebird.csv", conf.LifeListFormatEBird},
+		{"merlin detected", "lifelist_merlin.csv", conf.LifeListFormatAuto},
+		{"merlin forced", "lifelist_merlin.csv", conf.LifeListFormatMerlin},
+		{"headerless detected", "lifelist_legacy.csv", ""},
+		{"legacy forced", "lifelist_legacy.csv", conf.LifeListFormatLegacy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			list := parseLifeListFixture(t, tt.fixture, tt.format)
+
+			require.Len(t, list, 2)
+			robin := list["turdus migratorius"]
+			assert.Equal(t, "Turdus migratorius", robin.ScientificName)
+			assert.Equal(t, "American Robin", robin.CommonName)
+			assert.True(t, robin.FirstSeen.Equal(march), "the earliest observation is the first-seen date, got %v", robin.FirstSeen)
+			assert.Equal(t, "Northern Cardinal", list["cardinalis cardinalis"].CommonName)
+		})
+	}
+}
+
+func TestLifeListLayoutFromHeader_MissingColumns(t *testing.T) {
+	t.Parallel()
+
+	layout, ok := lifeListLayoutFromHeader([]string{"Scientific Name", "Notes"})
+	require.True(t, ok)
+	assert.Equal(t, lifeListLayout{commonName: -1, scientificName: 0, date: -1, family: -1}, layout)
+
+	_, ok = lifeListLayoutFromHeader([]string{"Species", "Date"})
+	assert.False(t, ok, "a header without a scientific name column leaves the default layout")
+}
@@ -0,0 +1,44 @@
+// life_list_fuzzy.go: normalized matching of life list names
+package processor
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// lifeListFuzzy is true while the loaded life list was read with fuzzy matching, so lookups
+// normalize names the same way its entries were
+var lifeListFuzzy atomic.Bool
+
+// lifeListKey returns the key scientificName is looked up and stored under on the loaded
+// life list, that of its current name when the synonyms list an old one.
+func lifeListKey(scientificName string) string {
+	return lifeListSynonyms.Load().key(lifeListNameKey(scientificName, lifeListFuzzy.Load()))
+}
+
+// lifeListNameKey returns the life list key of scientificName. Exact keys are the lowercased
+// name. Fuzzy keys also collapse runs of whitespace, and shorten a trinomial such as "Turdus
+// migratorius propinquus" to its species, so sources naming subspecies match lists that
+// don't and vice versa. Hybrids and slashes keep every part, since their first two words
+// don't name one species.
+func lifeListNameKey(scientificName string, fuzzy bool) string {
+	if !fuzzy {
+		return strings.ToLower(scientificName)
+	}
+	fields := strings.Fields(strings.ToLower(scientificName))
+	if len(fields) > 2 && !isLifeListCompoundName(fields) {
+		fields = fields[:2]
+	}
+	return strings.Join(fields, " ")
+}
+
+// isLifeListCompoundName reports whether a name spans several taxa, like the hybrid "Larus
+// argentatus x fuscus" or the slash "Accipiter striatus/cooperii"
+func isLifeListCompoundName(fields []string) bool {
+	for _, field := range fields {
+		if field == "x" || field == "×" || strings.Contains(field, "/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+// life_list_fuzzy_test.go: Tests for exact and fuzzy life list name matching
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// loadFuzzyTestLifeList loads a life list of rows with the given fuzzy setting
+func loadFuzzyTestLifeList(t *testing.T, fuzzy bool, rows string) {
+	t.Helper()
+	saved, savedFuzzy := loadedLifeList.Snapshot(), lifeListFuzzy.Load()
+	t.Cleanup(func() {
+		loadedLifeList.Load(saved)
+		lifeListFuzzy.Store(savedFuzzy)
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "life_list.csv"), []byte(rows), 0o600))
+	require.NoError(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:  "life_list.csv",
+		DataDir:       dir,
+		LifeListFuzzy: fuzzy,
+	}}))
+}
+
+func TestIsInLifeList_ExactVersusFuzzy(t *testing.T) {
+	const rows = "1,1,species,American Robin,Turdus migratorius\n" +
+		"2,2,species,Song Sparrow,Melospiza  melodia   maxima\n" +
+		"3,3,hybrid,Herring x Lesser Black-backed Gull,Larus argentatus x fuscus\n"
+
+	tests := []struct {
+		name      string
+		detection string
+		exact     bool
+		fuzzy     bool
+	}{
+		{"listed species", "Turdus migratorius", true, true},
+		{"subspecies of a listed species", "Turdus migratorius propinquus", false, true},
+		{"stray whitespace", " Turdus   migratorius ", false, true},
+		{"species of a listed subspecies", "Melospiza melodia", false, true},
+		{"another species of the genus", "Turdus merula", false, false},
+		{"parent of a listed hybrid", "Larus argentatus", false, false},
+	}
+	for _, mode := range []struct {
+		name  string
+		fuzzy bool
+	}{{"exact", false}, {"fuzzy", true}} {
+		t.Run(mode.name, func(t *testing.T) {
+			loadFuzzyTestLifeList(t, mode.fuzzy, rows)
+			for _, tt := range tests {
+				want := tt.exact
+				if mode.fuzzy {
+					want = tt.fuzzy
+				}
+				assert.Equal(t, want, isInLifeList(tt.detection), tt.name)
+			}
+		})
+	}
+}
+
+func TestLifeListNameKey(t *testing.T) {
+	assert.Equal(t, "turdus migratorius propinquus", lifeListNameKey("Turdus migratorius propinquus", false))
+	assert.Equal(t, "turdus migratorius", lifeListNameKey("Turdus  migratorius propinquus", true))
+	assert.Equal(t, "larus argentatus x fuscus", lifeListNameKey("Larus argentatus x fuscus", true),
+		"hybrids keep every part")
+	assert.Equal(t, "accipiter striatus/cooperii", lifeListNameKey("Accipiter striatus/cooperii", true))
+}
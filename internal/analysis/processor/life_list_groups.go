@@ -0,0 +1,66 @@
+// life_list_groups.go: genus-level and hybrid life list entries
+package processor
+
+import (
+	"strings"
+)
+
+// Normalized suffixes of group life list keys
+const (
+	lifeListGenusSuffix  = " sp."
+	lifeListHybridSuffix = " x"
+)
+
+// lifeListRankMarkers maps trailing taxonomic rank markers to the suffix of the group key
+// they normalize to.
+var lifeListRankMarkers = map[string]string{
+	"sp.":  lifeListGenusSuffix,
+	"sp":   lifeListGenusSuffix,
+	"spp.": lifeListGenusSuffix,
+	"spp":  lifeListGenusSuffix,
+	"x":    lifeListHybridSuffix,
+}
+
+// lifeListGroupKey reports whether scientificName ends in a rank marker, such as
+// "Accipiter sp." or the hybrid tracker "Larus x", and returns the normalized group key of
+// a single-genus entry. Entries with a marker but no single genus, like
+// "Accipiter/Buteo sp.", return an empty key.
+func lifeListGroupKey(scientificName string) (key string, marked bool) {
+	fields := strings.Fields(strings.ToLower(scientificName))
+	if len(fields) < 2 {
+		return "", false
+	}
+	suffix, marked := lifeListRankMarkers[fields[len(fields)-1]]
+	if !marked {
+		return "", false
+	}
+	if len(fields) != 2 || strings.ContainsAny(fields[0], "/()") {
+		return "", true
+	}
+	return fields[0] + suffix, true
+}
+
+// findLifeListEntry looks up scientificName in list. Without an entry for the species
+// itself, a group entry of its genus matches: a genus entry matches any species of the genus,
+// and a hybrid entry any hybrid within it. Group entries are only on the list when group
+// matching is enabled.
+func findLifeListEntry(list map[string]LifeListEntry, scientificName string) (LifeListEntry, bool) {
+	key := lifeListKey(scientificName)
+	if entry, ok := list[key]; ok {
+		return entry, true
+	}
+
+	genus, rest, ok := strings.Cut(key, " ")
+	if !ok {
+		return LifeListEntry{}, false
+	}
+	if entry, ok := list[genus+lifeListGenusSuffix]; ok {
+		return entry, true
+	}
+	if strings.Contains(" "+rest+" ", " x ") {
+		if entry, ok := list[genus+lifeListHybridSuffix]; ok {
+			return entry, true
+		}
+	}
+	return LifeListEntry{}, false
+}
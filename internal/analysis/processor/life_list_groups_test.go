@@ -0,0 +1,69 @@
+// life_list_groups_test.go: Tests for life list entries with taxonomic rank markers
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rankMarkedLifeList = "1,1,spuh,Accipiter sp.,Accipiter sp.\n" +
+	"2,2,hybrid,Gull hybrid,Larus x\n" +
+	"3,3,species,Common Raven,Corvus corax\n"
+
+func TestParseLifeList_RankMarkedEntries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("group matching enabled", func(t *testing.T) {
+		t.Parallel()
+		list, _, err := parseLifeList(strings.NewReader(rankMarkedLifeList), lifeListParseOptions{groupMatching: true})
+		require.NoError(t, err)
+
+		entry, ok := findLifeListEntry(list, "Accipiter cooperii")
+		require.True(t, ok, "a genus entry must match any species of the genus")
+		assert.Equal(t, "Accipiter sp.", entry.ScientificName)
+
+		_, ok = findLifeListEntry(list, "Larus argentatus x Larus marinus")
+		assert.True(t, ok, "a hybrid entry must match hybrids within the genus")
+		_, ok = findLifeListEntry(list, "Larus argentatus")
+		assert.False(t, ok, "a hybrid entry must not match a pure species")
+		_, ok = findLifeListEntry(list, "Buteo jamaicensis")
+		assert.False(t, ok)
+	})
+
+	t.Run("group matching disabled", func(t *testing.T) {
+		t.Parallel()
+		list, _, err := parseLifeList(strings.NewReader(rankMarkedLifeList), lifeListParseOptions{})
+		require.NoError(t, err)
+
+		assert.Len(t, list, 1, "rank-marked entries must be skipped")
+		_, ok := findLifeListEntry(list, "Accipiter cooperii")
+		assert.False(t, ok)
+		_, ok = findLifeListEntry(list, "Corvus corax")
+		assert.True(t, ok)
+	})
+}
+
+func TestLifeListGroupKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		key    string
+		marked bool
+	}{
+		{"Accipiter sp.", "accipiter sp.", true},
+		{"Empidonax spp", "empidonax sp.", true},
+		{"Larus x", "larus x", true},
+		{"Accipiter/Buteo sp.", "", true},
+		{"Corvus corax", "", false},
+		{"Spizella", "", false},
+	}
+	for _, tt := range tests {
+		key, marked := lifeListGroupKey(tt.name)
+		assert.Equal(t, tt.key, key, tt.name)
+		assert.Equal(t, tt.marked, marked, tt.name)
+	}
+}
@@ -0,0 +1,55 @@
+// life_list_hooks.go: callbacks for species added to the life list
+package processor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lifeListAddHooks holds the callbacks registered with OnLifeListAdd, in registration order.
+var lifeListAddHooks struct {
+	mu    sync.Mutex
+	hooks []func(scientificName string, addedAt time.Time)
+}
+
+// OnLifeListAdd registers fn to be called each time a species is added to the life list,
+// whether appended through the API or added as heard from a detection. Species brought in
+// by an import merge don't count. fn runs synchronously once the species is in the list and
+// its file, after the list is unlocked, so it may read the list; it must hand slow work off
+// rather than block. Callbacks run in registration order, and a panic in one is logged and
+// doesn't keep the others from running.
+func OnLifeListAdd(fn func(scientificName string, addedAt time.Time)) {
+	if fn == nil {
+		return
+	}
+	lifeListAddHooks.mu.Lock()
+	defer lifeListAddHooks.mu.Unlock()
+	lifeListAddHooks.hooks = append(lifeListAddHooks.hooks, fn)
+}
+
+// notifyLifeListAdd calls every callback registered with OnLifeListAdd for an added species.
+func notifyLifeListAdd(scientificName string, addedAt time.Time) {
+	lifeListAddHooks.mu.Lock()
+	hooks := lifeListAddHooks.hooks[:len(lifeListAddHooks.hooks):len(lifeListAddHooks.hooks)]
+	lifeListAddHooks.mu.Unlock()
+
+	for _, fn := range hooks {
+		runLifeListAddHook(fn, scientificName, addedAt)
+	}
+}
+
+// runLifeListAddHook calls fn, logging rather than propagating a panic.
+func runLifeListAddHook(fn func(string, time.Time), scientificName string, addedAt time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			GetLogger().Error("Life list add callback panicked",
+				logger.String("scientific_name", scientificName),
+				logger.String("panic", fmt.Sprintf("%v", r)),
+				logger.String("operation", "life_list_add_hook"))
+		}
+	}()
+	fn(scientificName, addedAt)
+}
@@ -0,0 +1,92 @@
+// life_list_hooks_test.go: Tests for callbacks of species added to the life list
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifeListAddRecorder records the species a life list add callback was called with
+type lifeListAddRecorder struct {
+	name  string
+	order *[]string
+	added map[string]time.Time
+}
+
+func (r *lifeListAddRecorder) hook(scientificName string, addedAt time.Time) {
+	*r.order = append(*r.order, r.name)
+	r.added[scientificName] = addedAt
+}
+
+// resetLifeListAddHooks drops the callbacks registered during the test
+func resetLifeListAddHooks(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		lifeListAddHooks.mu.Lock()
+		defer lifeListAddHooks.mu.Unlock()
+		lifeListAddHooks.hooks = nil
+	})
+}
+
+func TestOnLifeListAdd_CallsEachCallbackOncePerNewSpecies(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	resetLifeListAddHooks(t)
+
+	var order []string
+	first := &lifeListAddRecorder{name: "first", order: &order, added: make(map[string]time.Time)}
+	second := &lifeListAddRecorder{name: "second", order: &order, added: make(map[string]time.Time)}
+	OnLifeListAdd(first.hook)
+	OnLifeListAdd(second.hook)
+	seenAt := time.Date(2026, 6, 2, 7, 0, 0, 0, time.Local)
+	heardAt := seenAt.Add(time.Hour)
+
+	_, added, err := p.AddLifeListSpecies("Apus apus", "Common Swift", seenAt)
+	require.NoError(t, err)
+	require.True(t, added)
+	_, added, err = p.AddLifeListSpecies("Apus apus", "Common Swift", seenAt)
+	require.NoError(t, err)
+	require.False(t, added)
+	require.True(t, recordHeardSpecies(p.Settings, "Turdus merula", "Eurasian Blackbird", heardAt))
+	require.False(t, recordHeardSpecies(p.Settings, "Turdus merula", "Eurasian Blackbird", heardAt))
+	require.False(t, recordHeardSpecies(p.Settings, "Parus major", "Great Tit", heardAt))
+
+	want := map[string]time.Time{"Apus apus": seenAt, "Turdus merula": heardAt}
+	assert.Equal(t, want, first.added)
+	assert.Equal(t, want, second.added)
+	assert.Equal(t, []string{"first", "second", "first", "second"}, order, "callbacks run once per species, in registration order")
+}
+
+func TestOnLifeListAdd_IsolatesPanics(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	resetLifeListAddHooks(t)
+
+	var calls []string
+	OnLifeListAdd(func(string, time.Time) { panic("webhook down") })
+	OnLifeListAdd(func(scientificName string, _ time.Time) { calls = append(calls, scientificName) })
+
+	_, added, err := p.AddLifeListSpecies("Apus apus", "Common Swift", time.Now())
+	require.NoError(t, err)
+	assert.True(t, added, "a panicking callback doesn't fail the add")
+	assert.Equal(t, []string{"Apus apus"}, calls, "callbacks after a panicking one still run")
+	assert.True(t, isInLifeList("Apus apus"))
+}
+
+func TestOnLifeListAdd_CallbackMayReadList(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	resetLifeListAddHooks(t)
+
+	var listed bool
+	OnLifeListAdd(func(scientificName string, _ time.Time) {
+		_, listed = p.LifeListEntry(scientificName)
+		// Adding from a callback would deadlock if the list were still locked
+		_, _, _ = p.AddLifeListSpecies("Apus pallidus", "", time.Now())
+	})
+
+	_, _, err := p.AddLifeListSpecies("Apus apus", "Common Swift", time.Now())
+	require.NoError(t, err)
+	assert.True(t, listed, "the species is on the list by the time the callbacks run")
+	assert.True(t, isInLifeList("Apus pallidus"))
+}
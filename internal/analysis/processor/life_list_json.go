@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// lifeListJSONEntry is one object of a JSON life list. Only the scientific name is required.
+type lifeListJSONEntry struct {
+	ScientificName string `json:"scientificName"`
+	CommonName     string `json:"commonName,omitempty"`
+	FirstSeen      string `json:"firstSeen,omitempty"` // In any of the lifeListDateLayouts
+	Family         string `json:"family,omitempty"`
+}
+
+// isLifeListJSON reports whether the life list at location is read as JSON: when the format
+// says so, or when it is detected and the file or URL path ends in .json.
+func isLifeListJSON(location, format string) bool {
+	switch format {
+	case conf.LifeListFormatJSON:
+		return true
+	case "", conf.LifeListFormatAuto:
+		if isLifeListURL(location) {
+			if u, err := url.Parse(location); err == nil {
+				location = u.Path
+			}
+		}
+		return strings.EqualFold(path.Ext(location), ".json")
+	default:
+		return false
+	}
+}
+
+// parseLifeListJSON reads a JSON life list, either an array of scientific names such as
+// ["Turdus migratorius"] or an array of objects such as [{"scientificName": "Turdus
+// migratorius", "commonName": "American Robin", "firstSeen": "2020-04-01"}]; the two may be
+// mixed. Entries are added like CSV rows, so the collision policy and group matching apply.
+func parseLifeListJSON(r io.Reader, opts lifeListParseOptions) (map[string]LifeListEntry, []lifeListCollision, error) {
+	file, err := parseLifeListJSONFile(r, opts)
+	return file.list, file.collisions, err
+}
+
+// parseLifeListJSONFile parses like parseLifeListJSON and also reports how many entries
+// were read.
+func parseLifeListJSONFile(r io.Reader, opts lifeListParseOptions) (lifeListFile, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(skipLifeListBOM(r)).Decode(&items); err != nil {
+		if isLifeListCancelled(err) {
+			return lifeListFile{}, err
+		}
+		return lifeListFile{}, errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read").
+			Context("format", conf.LifeListFormatJSON).
+			Retryable(false).
+			Build()
+	}
+
+	builder := newLifeListBuilder(opts)
+	for i, item := range items {
+		var raw lifeListJSONEntry
+		var err error
+		if trimmed := bytes.TrimSpace(item); len(trimmed) > 0 && trimmed[0] == '"' {
+			err = json.Unmarshal(trimmed, &raw.ScientificName)
+		} else {
+			err = json.Unmarshal(trimmed, &raw)
+		}
+		if err != nil {
+			return lifeListFile{}, errors.Newf("life list entry %d is neither a scientific name nor an object with one: %w", i, err).
+				Component("life_list").
+				Category(errors.CategoryFileIO).
+				Context("operation", "read").
+				Context("format", conf.LifeListFormatJSON).
+				Context("entry", i).
+				Retryable(false).
+				Build()
+		}
+
+		scientificName := strings.TrimSpace(raw.ScientificName)
+		if scientificName == "" {
+			continue // An entry without a name must not match every unnamed detection
+		}
+		if err := builder.add(raw.ScientificName, LifeListEntry{
+			ScientificName: scientificName,
+			CommonName:     strings.TrimSpace(raw.CommonName),
+			FirstSeen:      parseLifeListDate(raw.FirstSeen),
+			Family:         strings.TrimSpace(raw.Family),
+			Status:         LifeListStatusSeen,
+		}); err != nil {
+			return lifeListFile{}, err
+		}
+	}
+	return lifeListFile{list: builder.list, collisions: builder.collisions, duplicates: builder.duplicates, rows: builder.rows}, nil
+}
@@ -0,0 +1,87 @@
+// life_list_json_test.go: Tests for life lists given as JSON
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestParseLifeListJSON_ArrayOfStrings(t *testing.T) {
+	t.Parallel()
+
+	list, collisions, err := parseLifeListJSON(strings.NewReader(`["Turdus migratorius", " Parus major ", "", "turdus migratorius"]`), lifeListParseOptions{})
+	require.NoError(t, err)
+	assert.Len(t, list, 2, "blank names are skipped")
+	assert.Equal(t, "Parus major", list["parus major"].ScientificName)
+	assert.Equal(t, LifeListStatusSeen, list["turdus migratorius"].Status)
+	require.Len(t, collisions, 1, "names differing in case collide like CSV rows")
+	assert.Equal(t, "turdus migratorius", collisions[0].Second)
+}
+
+func TestParseLifeListJSON_ArrayOfObjects(t *testing.T) {
+	t.Parallel()
+
+	list, _, err := parseLifeListJSON(strings.NewReader(`[
+		{"scientificName": "Turdus migratorius", "commonName": "American Robin", "firstSeen": "2020-04-01"},
+		{"scientificName": "Parus major"},
+		"Apus apus"
+	]`), lifeListParseOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	robin := list["turdus migratorius"]
+	assert.Equal(t, "American Robin", robin.CommonName)
+	assert.Equal(t, time.Date(2020, 4, 1, 0, 0, 0, 0, time.Local), robin.FirstSeen)
+	assert.True(t, list["parus major"].FirstSeen.IsZero(), "the date is optional")
+	assert.Contains(t, list, "apus apus", "names and objects can be mixed")
+}
+
+func TestParseLifeListJSON_Malformed(t *testing.T) {
+	t.Parallel()
+
+	for name, input := range map[string]string{
+		"truncated":  `["Turdus migratorius"`,
+		"not array":  `{"scientificName": "Turdus migratorius"}`,
+		"bad entry":  `["Parus major", 42]`,
+		"wrong type": `[{"scientificName": 7}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := parseLifeListJSON(strings.NewReader(input), lifeListParseOptions{})
+			require.Error(t, err)
+			var enhanced *errors.EnhancedError
+			require.True(t, errors.As(err, &enhanced))
+			assert.Equal(t, string(errors.CategoryFileIO), enhanced.GetCategory())
+		})
+	}
+}
+
+func TestLoadLifeList_DetectsJSONFormat(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lifelist.json"), []byte(`["Turdus migratorius"]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lifelist.txt"), []byte(`[{"scientificName": "Parus major"}]`), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "lifelist.json", DataDir: dir}}
+	require.NoError(t, loadLifeList(settings), "a .json file is read as JSON")
+	assert.True(t, isInLifeList("Turdus migratorius"))
+
+	settings.SoundId.LifeListPath = "lifelist.txt"
+	settings.SoundId.LifeListFormat = conf.LifeListFormatJSON
+	require.NoError(t, loadLifeList(settings), "the format setting selects JSON for any file name")
+	assert.True(t, isInLifeList("Parus major"))
+
+	assert.True(t, isLifeListJSON("https://example.com/lists/mine.JSON?token=1", conf.LifeListFormatAuto))
+	assert.False(t, isLifeListJSON("lifelist.json", conf.LifeListFormatMerlin), "a CSV format setting wins over the extension")
+	assert.False(t, isLifeListJSON("lifelist.csv", ""))
+}
@@ -0,0 +1,148 @@
+// life_list_last_heard.go: most recent detection of each species
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lastHeardSuffix replaces the extension of a local life list file to name its last-heard
+// sidecar, so lifelist.csv keeps its timestamps in lifelist.lastheard.json.
+const lastHeardSuffix = ".lastheard.json"
+
+// lastHeardRecord is one species in the persisted last-heard sidecar.
+type lastHeardRecord struct {
+	ScientificName string    `json:"scientificName"`
+	LastHeard      time.Time `json:"lastHeard"`
+}
+
+// lastHeardStore keeps the time of the most recent approved detection of each species. Only
+// detections update it; loading the life list file leaves it alone. It is persisted to a
+// sidecar next to the life list, or kept in memory only when there is no local life list
+// file. The zero value is ready to use.
+type lastHeardStore struct {
+	mu      sync.Mutex
+	path    string                     // Sidecar file, empty to keep the records in memory only
+	records map[string]lastHeardRecord // Keyed by life list key
+}
+
+// load replaces the records with those of the sidecar at path, and persists later records
+// there. A missing file holds no records; on a failed read the store starts empty.
+func (s *lastHeardStore) load(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+	s.records = make(map[string]lastHeardRecord)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_last_heard").
+			Build()
+	}
+
+	var records []lastHeardRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileParsing).
+			Context("operation", "parse_last_heard").
+			Build()
+	}
+	for _, r := range records {
+		if key := lifeListKey(r.ScientificName); key != "" {
+			s.records[key] = r
+		}
+	}
+	return nil
+}
+
+// lastHeard returns when scientificName was last detected, reporting false when it never was.
+func (s *lastHeardStore) lastHeard(scientificName string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[lifeListKey(scientificName)]
+	return record.LastHeard, ok
+}
+
+// record stores a detection at at unless a later one is already recorded, persisting the
+// records, and reports whether it did.
+func (s *lastHeardStore) record(scientificName string, at time.Time) bool {
+	key := lifeListKey(scientificName)
+	if key == "" || at.IsZero() {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, exists := s.records[key]; exists && !at.After(record.LastHeard) {
+		return false
+	}
+	if s.records == nil {
+		s.records = make(map[string]lastHeardRecord)
+	}
+	s.records[key] = lastHeardRecord{ScientificName: scientificName, LastHeard: at}
+	s.persistLocked()
+	return true
+}
+
+// persistLocked writes the records to the sidecar, sorted by scientific name. Failures are
+// logged; the records in memory stay authoritative. Callers hold mu.
+func (s *lastHeardStore) persistLocked() {
+	if s.path == "" {
+		return
+	}
+	records := make([]lastHeardRecord, 0, len(s.records))
+	for _, r := range s.records {
+		records = append(records, r)
+	}
+	slices.SortFunc(records, func(a, b lastHeardRecord) int {
+		return strings.Compare(strings.ToLower(a.ScientificName), strings.ToLower(b.ScientificName))
+	})
+	if err := writeJSONFile(s.path, records); err != nil {
+		GetLogger().Warn("Failed to persist last heard times",
+			logger.String("path", s.path),
+			logger.Error(err),
+			logger.String("operation", "last_heard_persist"))
+	}
+}
+
+// LastHeard returns when scientificName was last detected, reporting false when it hasn't
+// been since tracking began.
+func (p *Processor) LastHeard(scientificName string) (time.Time, bool) {
+	return p.lastHeard.lastHeard(scientificName)
+}
+
+// recordLastHeard records an approved detection as its species' most recent one.
+func (p *Processor) recordLastHeard(scientificName string, at time.Time) {
+	p.lastHeard.record(scientificName, at)
+}
+
+// loadLastHeard loads the last-heard times kept next to the configured life list.
+func (p *Processor) loadLastHeard(settings *conf.Settings) {
+	path := lifeListSidecarPath(settings, lastHeardSuffix)
+	if err := p.lastHeard.load(path); err != nil {
+		GetLogger().Warn("Failed to load last heard times, starting over",
+			logger.String("path", path),
+			logger.Error(err),
+			logger.String("operation", "last_heard_load"))
+	}
+}
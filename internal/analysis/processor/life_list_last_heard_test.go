@@ -0,0 +1,91 @@
+// life_list_last_heard_test.go: Tests for tracking the most recent detection of each species
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLastHeard_RecordsLatestDetection(t *testing.T) {
+	p := &Processor{Settings: &conf.Settings{}}
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+
+	_, ok := p.LastHeard("Turdus merula")
+	assert.False(t, ok, "a species never detected has no last heard time")
+
+	p.recordLastHeard("Turdus merula", at)
+	p.recordLastHeard("Turdus merula", at.Add(time.Hour))
+	p.recordLastHeard("Turdus merula", at.Add(-time.Hour))
+
+	heard, ok := p.LastHeard("turdus MERULA")
+	require.True(t, ok, "names are matched like life list entries")
+	assert.True(t, heard.Equal(at.Add(time.Hour)), "an older detection arriving late doesn't move the time back")
+
+	_, ok = p.LastHeard("Parus major")
+	assert.False(t, ok)
+}
+
+func TestLastHeard_IgnoresLifeListLoad(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Eurasian Blackbird,Turdus merula,1,Home,,2001-06-01\n"), 0o600))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+
+	assert.True(t, isInLifeList("Turdus merula"))
+	_, ok := p.LastHeard("Turdus merula")
+	assert.False(t, ok, "being on the life list isn't being heard")
+}
+
+func TestLastHeard_PersistedNextToLifeList(t *testing.T) {
+	dir := t.TempDir()
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "lifelist.csv", DataDir: dir}}
+	sidecar := filepath.Join(dir, "lifelist"+lastHeardSuffix)
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+
+	p := &Processor{Settings: settings}
+	p.loadLastHeard(settings)
+	p.recordLastHeard("Turdus merula", at)
+	p.recordLastHeard("Parus major", at.Add(time.Minute))
+
+	data, err := os.ReadFile(sidecar)
+	require.NoError(t, err)
+	var records []lastHeardRecord
+	require.NoError(t, json.Unmarshal(data, &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, "Parus major", records[0].ScientificName, "records are sorted by scientific name")
+
+	restarted := &Processor{Settings: settings}
+	restarted.loadLastHeard(settings)
+	heard, ok := restarted.LastHeard("Turdus merula")
+	require.True(t, ok, "last heard times survive a restart")
+	assert.True(t, heard.Equal(at))
+}
+
+func TestLastHeard_ConcurrentDetections(t *testing.T) {
+	p := &Processor{Settings: &conf.Settings{}}
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			p.recordLastHeard("Turdus merula", at.Add(time.Duration(i)*time.Second))
+			p.LastHeard("Turdus merula")
+		})
+	}
+	wg.Wait()
+
+	heard, ok := p.LastHeard("Turdus merula")
+	require.True(t, ok)
+	assert.True(t, heard.Equal(at.Add(49*time.Second)), "the latest detection wins whatever order they arrive in")
+}
@@ -0,0 +1,95 @@
+// life_list_malformed_test.go: Tests for life list rows of differing widths, and rows too short to hold a scientific name
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const truncatedLifeList = "1,1,species,Great Tit,Parus major\n" +
+	"2,2,species\n" +
+	"3,3,species,Eurasian Blue Tit,Cyanistes caeruleus\n"
+
+func TestParseLifeList_TruncatedRowFailsWithLine(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := parseLifeList(strings.NewReader(truncatedLifeList), lifeListParseOptions{})
+	require.Error(t, err)
+
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, "life_list", enhanced.GetComponent())
+	assert.Equal(t, string(errors.CategoryFileIO), enhanced.GetCategory())
+	assert.Equal(t, 2, enhanced.GetContext()["line"])
+}
+
+func TestParseLifeList_SkipMalformedCountsBadRows(t *testing.T) {
+	t.Parallel()
+
+	list, _, err := parseLifeList(strings.NewReader(truncatedLifeList), lifeListParseOptions{skipMalformed: true})
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Contains(t, list, "parus major")
+	assert.Contains(t, list, "cyanistes caeruleus")
+}
+
+func TestParseLifeList_EmptyTrailingLine(t *testing.T) {
+	t.Parallel()
+
+	for name, input := range map[string]string{
+		"empty":      "1,1,species,Great Tit,Parus major\n\n",
+		"whitespace": "1,1,species,Great Tit,Parus major\n   \n",
+	} {
+		list, _, err := parseLifeList(strings.NewReader(input), lifeListParseOptions{})
+		require.NoError(t, err, name)
+		assert.Len(t, list, 1, name)
+	}
+}
+
+// Rows of the Merlin layout with and without the optional trailing columns, and wider than
+// the first row
+const raggedLifeList = "1,1,species,Great Tit,Parus major,1,Home,,2010-05-01\n" +
+	"2,2,species,Eurasian Blue Tit,Cyanistes caeruleus\n" +
+	"3,3,species,Common Swift,Apus apus,1,Park,,2024-06-01,extra,columns\n"
+
+func TestLoadLifeList_RowsOfDifferingWidths(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	tests := []struct {
+		name          string
+		contents      string
+		skipMalformed bool
+		loaded        int
+		malformed     int
+	}{
+		{"rows holding a scientific name", raggedLifeList, false, 3, 0},
+		{"row too short is skipped", raggedLifeList + "4,4,species\n", true, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "life_list.csv"), []byte(tt.contents), 0o600))
+			settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+				LifeListPath:          "life_list.csv",
+				LifeListSkipMalformed: tt.skipMalformed,
+				DataDir:               dir,
+			}}
+
+			result, err := loadLifeListWithResult(t.Context(), settings)
+			require.NoError(t, err)
+			assert.Equal(t, tt.loaded, result.Loaded)
+			assert.Equal(t, tt.malformed, result.Malformed)
+			for _, name := range []string{"Parus major", "Cyanistes caeruleus", "Apus apus"} {
+				assert.True(t, isInLifeList(name), "%s is loaded", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package processor
+
+import (
+	"io"
+	"maps"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// LifeListMergeResult reports the outcome of merging an imported life list.
+type LifeListMergeResult struct {
+	Added     int // Species that were not on the life list and were added
+	Preserved int // Species already on the life list, left with their existing metadata
+}
+
+// MergeLifeList merges a life list CSV in the configured layout and encoding into the loaded
+// list. Only species missing from the list are added; species already on it keep their
+// first-seen date, common name and heard/seen status. Added species are recorded in the
+// status file so they survive reloads of the configured list.
+func (p *Processor) MergeLifeList(r io.Reader) (LifeListMergeResult, error) {
+	decoded, err := decodeLifeList(r, p.Settings.SoundId.LifeListEncoding)
+	if err != nil {
+		return LifeListMergeResult{}, err
+	}
+	imported, collisions, err := parseLifeList(decoded, newLifeListParseOptions(&p.Settings.SoundId))
+	if err != nil {
+		return LifeListMergeResult{}, err
+	}
+	logLifeListCollisions(p.Settings.SoundId.LifeListCollisionPolicy, collisions)
+	imported = lifeListSynonyms.Load().canonicalize(imported)
+
+	lifeListStatusMu.Lock()
+	defer lifeListStatusMu.Unlock()
+
+	current := loadedLifeList.Snapshot()
+	if current == nil {
+		return LifeListMergeResult{}, errors.Newf("no life list is loaded to merge into").
+			Component("life_list").
+			Category(errors.CategoryState).
+			Context("operation", "merge").
+			Build()
+	}
+
+	var result LifeListMergeResult
+	list := maps.Clone(current)
+	for key, entry := range imported {
+		if _, exists := list[key]; exists {
+			result.Preserved++
+			continue
+		}
+		entry.merged = true
+		list[key] = entry
+		result.Added++
+	}
+
+	if result.Added > 0 {
+		loadedLifeList.Load(list)
+		persistLifeListStatuses(p.Settings.SoundId.LifeListStatusPath, list)
+	}
+
+	GetLogger().Info("Merged imported life list",
+		logger.Int("added", result.Added),
+		logger.Int("preserved", result.Preserved),
+		logger.String("operation", "life_list_merge"))
+	return result, nil
+}
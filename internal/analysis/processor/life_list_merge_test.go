@@ -0,0 +1,68 @@
+// life_list_merge_test.go: Tests for merging an imported life list into the loaded one
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestMergeLifeList_PreservesExistingAddsNew(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	heardAt := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+	require.True(t, recordHeardSpecies(p.Settings, "Turdus merula", "Eurasian Blackbird", heardAt))
+
+	// An updated export that lists both known species with different dates, plus one new one
+	export := strings.Join([]string{
+		"Row #,Taxon Order,Category,Common Name,Scientific Name,Count,Location,S/P,Date",
+		"1,1,species,Great Tit,Parus major,1,Home,,2024-01-01",
+		"2,2,species,Blackbird,Turdus merula,1,Home,,2026-06-01",
+		"3,3,species,Common Swift,Apus apus,1,Home,,2026-06-02",
+	}, "\n")
+
+	result, err := p.MergeLifeList(strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, LifeListMergeResult{Added: 1, Preserved: 2}, result)
+
+	tit, ok := lookupLifeList("Parus major")
+	require.True(t, ok)
+	assert.Equal(t, 2001, tit.FirstSeen.Year(), "existing first-seen date is kept")
+	assert.Equal(t, LifeListStatusSeen, tit.Status)
+
+	blackbird, ok := lookupLifeList("Turdus merula")
+	require.True(t, ok)
+	assert.True(t, blackbird.FirstSeen.Equal(heardAt), "existing first-seen date is kept")
+	assert.Equal(t, LifeListStatusHeard, blackbird.Status, "existing status is kept")
+	assert.Equal(t, "Eurasian Blackbird", blackbird.CommonName, "existing common name is kept")
+
+	swift, ok := lookupLifeList("Apus apus")
+	require.True(t, ok)
+	assert.Equal(t, "Common Swift", swift.CommonName)
+	assert.Equal(t, LifeListStatusSeen, swift.Status)
+	assert.Len(t, p.LifeListEntries(), 3)
+
+	// Merged species survive a reload of the configured list
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	swift, ok = lookupLifeList("Apus apus")
+	require.True(t, ok)
+	assert.Equal(t, 2026, swift.FirstSeen.Year())
+
+	// Merging the same file again adds nothing
+	result, err = p.MergeLifeList(strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, LifeListMergeResult{Added: 0, Preserved: 3}, result)
+}
+
+func TestMergeLifeList_NoListLoaded(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	loadedLifeList.Load(nil)
+
+	_, err := p.MergeLifeList(strings.NewReader("1,1,species,Common Swift,Apus apus,1,Home,,2026-06-02\n"))
+	var enhancedErr *errors.EnhancedError
+	require.ErrorAs(t, err, &enhancedErr)
+	assert.Equal(t, errors.CategoryState, enhancedErr.Category)
+}
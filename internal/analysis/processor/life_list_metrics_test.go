@@ -0,0 +1,79 @@
+// life_list_metrics_test.go: Tests for counting life list hits, misses and failed loads
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+func TestIsInLifeList_RecordsLookupMetrics(t *testing.T) {
+	savedList, savedMetrics := loadedLifeList.Snapshot(), lifeListMetrics.Load()
+	t.Cleanup(func() {
+		loadedLifeList.Load(savedList)
+		lifeListMetrics.Store(savedMetrics)
+	})
+
+	birdnetMetrics, err := metrics.NewBirdNETMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	initLifeListMetrics(&observability.Metrics{BirdNET: birdnetMetrics})
+	hits := birdnetMetrics.LifeListLookups.WithLabelValues("true")
+	misses := birdnetMetrics.LifeListLookups.WithLabelValues("false")
+
+	loadedLifeList.Load(nil)
+	assert.False(t, isInLifeList("Parus major"))
+	assert.InDelta(t, 0, testutil.ToFloat64(misses), 0, "lookups without a loaded list aren't counted")
+
+	list := map[string]LifeListEntry{"parus major": {ScientificName: "Parus major"}}
+	loadedLifeList.Load(list)
+	assert.True(t, isInLifeList("Parus major"))
+	assert.False(t, isInLifeList("Troglodytes troglodytes"))
+	assert.False(t, isInLifeList("Erithacus rubecula"))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(hits), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(misses), 0)
+
+	initLifeListMetrics(nil)
+	assert.True(t, isInLifeList("Parus major"), "lookups work without metrics")
+	assert.InDelta(t, 1, testutil.ToFloat64(hits), 0)
+}
+
+func TestLoadLifeList_RecordsErrorMetrics(t *testing.T) {
+	savedList, savedMetrics, savedErrors := loadedLifeList.Snapshot(), lifeListMetrics.Load(), lifeListErrorMetrics.Load()
+	t.Cleanup(func() {
+		loadedLifeList.Load(savedList)
+		lifeListMetrics.Store(savedMetrics)
+		lifeListErrorMetrics.Store(savedErrors)
+	})
+
+	errorMetrics, err := metrics.NewErrorMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	initLifeListMetrics(&observability.Metrics{Errors: errorMetrics})
+
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home.csv")
+	require.NoError(t, os.WriteFile(home, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	require.Error(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: filepath.Join(dir, "missing.csv")}}))
+	require.Error(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:     home,
+		LifeListEncoding: "klingon",
+	}}))
+	require.Error(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:  filepath.Join(dir, "missing.csv"),
+		LifeListPaths: []string{filepath.Join(dir, "gone.csv")},
+	}}))
+
+	fileIO := errorMetrics.ErrorsTotal.WithLabelValues("life_list", string(errors.CategoryFileIO))
+	configuration := errorMetrics.ErrorsTotal.WithLabelValues("life_list", string(errors.CategoryConfiguration))
+	assert.InDelta(t, 3, testutil.ToFloat64(fileIO), 0, "each file of a multi-file load is counted")
+	assert.InDelta(t, 1, testutil.ToFloat64(configuration), 0)
+}
@@ -0,0 +1,112 @@
+// life_list_multi_test.go: Tests for merging several life list files into one list
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestLoadLifeList_MergesMultipleFiles(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "europe.csv"), []byte(
+		"1,1,species,Great Tit,Parus major,1,Home,,2010-05-01\n"+
+			"2,2,species,Eurasian Wren,Troglodytes troglodytes,1,Home,,2012-03-04\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2024.csv"), []byte(
+		"1,1,species,Great Tit,Parus major,1,Park,,2024-01-02\n"+
+			"2,2,species,Eurasian Wren,Troglodytes troglodytes,1,Park,,2008-07-08\n"+
+			"3,3,species,Common Swift,Apus apus,1,Park,,2024-06-01\n"), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:  "europe.csv",
+		LifeListPaths: []string{"2024.csv", "europe.csv"},
+		DataDir:       dir,
+	}}
+	result, err := loadLifeListWithResult(t.Context(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Loaded, "species on both lists are kept once")
+	assert.Empty(t, result.Duplicates, "overlap between files isn't reported as duplicate rows")
+
+	list := loadedLifeList.Snapshot()
+	assert.Equal(t, time.Date(2010, 5, 1, 0, 0, 0, 0, time.Local), list["parus major"].FirstSeen)
+	assert.Equal(t, time.Date(2008, 7, 8, 0, 0, 0, 0, time.Local), list["troglodytes troglodytes"].FirstSeen,
+		"the earliest first-seen date of any list is kept")
+	assert.True(t, isInLifeList("Apus apus"))
+}
+
+func TestLoadLifeList_ReportsUnreadableFile(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "home.csv"), []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:  "home.csv",
+		LifeListPaths: []string{"missing.csv"},
+		DataDir:       dir,
+	}}
+
+	loadedLifeList.Load(nil)
+	err := loadLifeList(settings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.csv", "the error names the file that failed")
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategoryFileIO), enhanced.GetCategory())
+	assert.Nil(t, loadedLifeList.Snapshot(), "a failed load keeps the current list")
+
+	settings.SoundId.LifeListSkipUnreadable = true
+	require.NoError(t, loadLifeList(settings), "the readable files are loaded")
+	assert.True(t, isInLifeList("Parus major"))
+
+	settings.SoundId.LifeListPath = "gone.csv"
+	require.Error(t, loadLifeList(settings), "a load with no readable file fails")
+}
+
+func TestLoadLifeList_ReportsEveryUnreadableFile(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "home.csv"), []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"not": "a list"}`), 0o600))
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:  "missing.csv",
+		LifeListPaths: []string{"home.csv", "broken.json"},
+		DataDir:       dir,
+	}}
+
+	err := loadLifeList(settings)
+	require.Error(t, err)
+	var multi *errors.MultiError
+	require.ErrorAs(t, err, &multi, "the failures of all files are reported together")
+	children := multi.Unwrap()
+	require.Len(t, children, 2)
+	assert.Contains(t, children[0].Error(), "missing.csv")
+	assert.Contains(t, children[1].Error(), "broken.json")
+	for _, child := range children {
+		var enhanced *errors.EnhancedError
+		require.ErrorAs(t, child, &enhanced)
+		assert.Equal(t, "life_list", enhanced.GetComponent())
+		assert.Equal(t, errors.CategoryFileIO, enhanced.Category)
+	}
+	assert.True(t, errors.IsRetryable(children[0]), "a missing file may appear later")
+	assert.True(t, errors.IsPermanent(children[1]))
+
+	settings.SoundId.LifeListSkipUnreadable = true
+	settings.SoundId.LifeListPaths = []string{"broken.json"}
+	err = loadLifeList(settings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "none of the 2 configured life list files could be read")
+	require.ErrorAs(t, err, &multi, "a load with no readable file still names every file")
+	assert.Equal(t, 2, multi.Len())
+}
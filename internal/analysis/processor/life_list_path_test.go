@@ -0,0 +1,41 @@
+// life_list_path_test.go: Tests for resolving relative life list paths
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLoadLifeList_RelativePathResolvesAgainstDataDir(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dataDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "lists"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "lists", "lifelist.csv"), []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+
+	// A different list at the same relative path under the working directory must be ignored
+	workDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "lists"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "lists", "lifelist.csv"), []byte("1,1,species,Eurasian Wren,Troglodytes troglodytes\n"), 0o600))
+	t.Chdir(workDir)
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "lists/lifelist.csv", DataDir: dataDir}}
+	require.NoError(t, loadLifeList(settings))
+
+	assert.True(t, isInLifeList("Parus major"))
+	assert.False(t, isInLifeList("Troglodytes troglodytes"))
+}
+
+func TestResolveLifeListPath_AbsoluteAndURLUnchanged(t *testing.T) {
+	t.Parallel()
+
+	absolute := filepath.Join(t.TempDir(), "lifelist.csv")
+	assert.Equal(t, absolute, resolveLifeListPath(absolute, "/srv/birdnet"))
+	assert.Equal(t, "https://example.com/lifelist.csv", resolveLifeListPath("https://example.com/lifelist.csv", "/srv/birdnet"))
+}
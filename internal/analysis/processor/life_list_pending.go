@@ -0,0 +1,137 @@
+// life_list_pending.go: review queue for life list additions awaiting user confirmation
+package processor
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// PendingLifeListSpecies is a detected species missing from the life list that waits for
+// the user to confirm it before it is added.
+type PendingLifeListSpecies struct {
+	ScientificName string
+	CommonName     string
+	FirstDetected  time.Time // When the first approved detection was made; becomes the first-seen date
+	LastDetected   time.Time
+	Detections     int // Approved detections since the species was queued
+}
+
+// lifeListPendingQueue holds the species awaiting confirmation, keyed by lowercase scientific
+// name. The zero value is ready to use.
+type lifeListPendingQueue struct {
+	mu      sync.Mutex
+	species map[string]PendingLifeListSpecies
+}
+
+// queueLifeListSpecies queues a detected species missing from the life list for review
+// instead of adding it. Repeat detections of a queued species only update its counts. It
+// reports whether the species was newly queued.
+func (p *Processor) queueLifeListSpecies(scientificName, commonName string, at time.Time) bool {
+	if _, exists, loaded := loadedLifeList.Lookup(scientificName); !loaded || exists || scientificName == "" {
+		return false
+	}
+	key := strings.ToLower(scientificName)
+
+	q := &p.lifeListPending
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if pending, exists := q.species[key]; exists {
+		pending.LastDetected = at
+		pending.Detections++
+		q.species[key] = pending
+		return false
+	}
+	if q.species == nil {
+		q.species = make(map[string]PendingLifeListSpecies)
+	}
+	q.species[key] = PendingLifeListSpecies{
+		ScientificName: scientificName,
+		CommonName:     commonName,
+		FirstDetected:  at,
+		LastDetected:   at,
+		Detections:     1,
+	}
+
+	GetLogger().Info("Queued species for life list confirmation",
+		logger.String("species", commonName),
+		logger.String("scientific_name", scientificName),
+		logger.String("operation", "life_list_queue"))
+	return true
+}
+
+// PendingLifeListSpecies returns the species awaiting confirmation, oldest first.
+func (p *Processor) PendingLifeListSpecies() []PendingLifeListSpecies {
+	q := &p.lifeListPending
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := slices.Collect(maps.Values(q.species))
+	slices.SortFunc(pending, func(a, b PendingLifeListSpecies) int {
+		return a.FirstDetected.Compare(b.FirstDetected)
+	})
+	return pending
+}
+
+// ConfirmLifeListSpecies adds a pending species to the life list as heard, first seen when
+// it was first detected, and removes it from the queue.
+func (p *Processor) ConfirmLifeListSpecies(scientificName string) (LifeListEntry, error) {
+	pending, err := p.takePendingLifeListSpecies(scientificName)
+	if err != nil {
+		return LifeListEntry{}, err
+	}
+
+	recordHeardSpecies(p.Settings, pending.ScientificName, pending.CommonName, pending.FirstDetected)
+	entry, ok := lookupLifeList(pending.ScientificName)
+	if !ok {
+		return LifeListEntry{}, errors.Newf("no life list is loaded").
+			Component("life_list").
+			Category(errors.CategoryState).
+			Context("scientific_name", pending.ScientificName).
+			Build()
+	}
+	return entry, nil
+}
+
+// RejectLifeListSpecies drops a pending species without adding it to the life list. A later
+// detection queues it again.
+func (p *Processor) RejectLifeListSpecies(scientificName string) error {
+	_, err := p.takePendingLifeListSpecies(scientificName)
+	return err
+}
+
+// takePendingLifeListSpecies removes and returns a species from the queue.
+func (p *Processor) takePendingLifeListSpecies(scientificName string) (PendingLifeListSpecies, error) {
+	key := strings.ToLower(strings.TrimSpace(scientificName))
+
+	q := &p.lifeListPending
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending, exists := q.species[key]
+	if !exists {
+		return PendingLifeListSpecies{}, errors.Newf("species %q is not awaiting confirmation", scientificName).
+			Component("life_list").
+			Category(errors.CategoryNotFound).
+			Context("scientific_name", scientificName).
+			Build()
+	}
+	delete(q.species, key)
+	return pending, nil
+}
+
+// addDetectedLifeListSpecies adds an approved detection's species to the life list as heard,
+// or queues it for confirmation in review mode.
+func (p *Processor) addDetectedLifeListSpecies(scientificName, commonName string, at time.Time) {
+	if p.Settings.SoundId.LifeListReviewMode {
+		p.queueLifeListSpecies(scientificName, commonName, at)
+		return
+	}
+	recordHeardSpecies(p.Settings, scientificName, commonName, at)
+}
@@ -0,0 +1,50 @@
+// life_list_pending_test.go: Tests for confirming life list additions in review mode
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestLifeListReviewMode_QueuesUntilConfirmed(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.SoundId.LifeListReviewMode = true
+	detectedAt := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+
+	p.addDetectedLifeListSpecies("Turdus merula", "Eurasian Blackbird", detectedAt)
+	p.addDetectedLifeListSpecies("Turdus merula", "Eurasian Blackbird", detectedAt.Add(time.Minute))
+	p.addDetectedLifeListSpecies("Parus major", "Great Tit", detectedAt)
+
+	_, listed := lookupLifeList("Turdus merula")
+	assert.False(t, listed, "review mode keeps new species off the life list")
+	pending := p.PendingLifeListSpecies()
+	require.Len(t, pending, 1, "species already on the list aren't queued")
+	assert.Equal(t, "Turdus merula", pending[0].ScientificName)
+	assert.Equal(t, 2, pending[0].Detections)
+
+	entry, err := p.ConfirmLifeListSpecies("turdus merula")
+	require.NoError(t, err)
+	assert.Equal(t, LifeListStatusHeard, entry.Status)
+	assert.True(t, entry.FirstSeen.Equal(detectedAt), "first seen is the first detection, not the confirmation")
+	assert.Empty(t, p.PendingLifeListSpecies())
+
+	_, err = p.ConfirmLifeListSpecies("Turdus merula")
+	var enhancedErr *errors.EnhancedError
+	require.ErrorAs(t, err, &enhancedErr)
+	assert.Equal(t, errors.CategoryNotFound, enhancedErr.Category)
+}
+
+func TestLifeListReviewMode_RejectDropsSpecies(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	p.Settings.SoundId.LifeListReviewMode = true
+
+	p.addDetectedLifeListSpecies("Apus apus", "Common Swift", time.Now())
+	require.NoError(t, p.RejectLifeListSpecies("Apus apus"))
+	assert.Empty(t, p.PendingLifeListSpecies())
+	_, listed := lookupLifeList("Apus apus")
+	assert.False(t, listed)
+}
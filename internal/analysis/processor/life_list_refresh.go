@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lifeListRefresher periodically reloads a URL-backed life list. At most one fetch runs
+// at a time: a tick that fires while the previous fetch is still in flight is skipped
+// instead of piling another request onto a slow server.
+type lifeListRefresher struct {
+	load     func(ctx context.Context) error
+	interval time.Duration
+	running  atomic.Bool
+	wg       sync.WaitGroup
+}
+
+// newLifeListRefresher creates a refresher that reloads the life list from settings.
+func newLifeListRefresher(settings *conf.Settings) *lifeListRefresher {
+	return &lifeListRefresher{
+		load: func(ctx context.Context) error {
+			return lifeListReloadError(loadLifeListCtx(ctx, settings))
+		},
+		interval: time.Duration(settings.SoundId.LifeListRefreshInterval) * time.Second,
+	}
+}
+
+// refresh runs one fetch unless another is already in progress. It reports whether
+// a fetch was run.
+func (r *lifeListRefresher) refresh(ctx context.Context) bool {
+	if !r.running.CompareAndSwap(false, true) {
+		GetLogger().Info("Skipping life list refresh, previous fetch still running",
+			logger.Duration("interval", r.interval),
+			logger.String("operation", "life_list_refresh"))
+		return false
+	}
+	defer r.running.Store(false)
+
+	if err := r.load(ctx); err != nil {
+		GetLogger().Warn("Failed to refresh life list, keeping current list",
+			logger.Error(err),
+			logger.Bool("retryable", errors.IsRetryable(err)),
+			logger.String("operation", "life_list_refresh"))
+	}
+	return true
+}
+
+// start refreshes the life list every interval until ctx is cancelled. Each fetch runs
+// in its own goroutine so a slow fetch doesn't hold back the schedule.
+func (r *lifeListRefresher) start(ctx context.Context) {
+	r.wg.Go(func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.wg.Go(func() { r.refresh(ctx) })
+			}
+		}
+	})
+}
+
+// wait blocks until the schedule and any in-flight fetch have returned.
+func (r *lifeListRefresher) wait() {
+	r.wg.Wait()
+}
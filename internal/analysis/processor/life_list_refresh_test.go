@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLifeListRefresher_SkipsWhileFetchRunning(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	r := &lifeListRefresher{
+		interval: time.Minute,
+		load: func(ctx context.Context) error {
+			calls.Add(1)
+			close(started)
+			<-release
+			return nil
+		},
+	}
+
+	ctx := t.Context()
+	done := make(chan bool)
+	go func() { done <- r.refresh(ctx) }()
+	<-started
+
+	// The next scheduled fetch fires while the slow one is still running
+	assert.False(t, r.refresh(ctx), "second fetch should be skipped")
+
+	close(release)
+	assert.True(t, <-done)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLifeListRefresher_ScheduleRunsOneFetchAtATime(t *testing.T) {
+	var calls, active, maxActive atomic.Int32
+
+	r := &lifeListRefresher{
+		interval: 5 * time.Millisecond,
+		load: func(ctx context.Context) error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			for {
+				peak := maxActive.Load()
+				if n <= peak || maxActive.CompareAndSwap(peak, n) {
+					break
+				}
+			}
+			calls.Add(1)
+			select {
+			case <-time.After(50 * time.Millisecond):
+			case <-ctx.Done():
+			}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	r.start(ctx)
+	time.Sleep(120 * time.Millisecond)
+	cancel()
+	r.wait()
+
+	assert.Equal(t, int32(1), maxActive.Load(), "fetches must not overlap")
+	assert.Less(t, calls.Load(), int32(10), "ticks during a running fetch should be skipped")
+}
+
+func TestLoadLifeList_FromURL(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("a,b,c,d,Turdus merula\n"))
+	}))
+	defer server.Close()
+
+	require.NoError(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: server.URL}}))
+	assert.True(t, isInLifeList("turdus merula"))
+
+	// A failed fetch keeps the previously loaded list
+	server.Close()
+	require.Error(t, loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: server.URL}}))
+	assert.True(t, isInLifeList("Turdus merula"))
+}
@@ -0,0 +1,178 @@
+// life_list_reload_test.go: Tests that life list lookups stay consistent and lock-free during reloads
+package processor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Sizes of the two alternating life lists used by the reload tests
+const (
+	reloadListSmall = 50
+	reloadListLarge = 500
+)
+
+// writeReloadLifeList writes a life list of n species. Every list contains the shared
+// species; the "Only" species marks which of the two lists was loaded.
+func writeReloadLifeList(t testing.TB, dir string, n int) string {
+	t.Helper()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "0,0,species,Shared Bird,Shared species,1,Home,,2001-01-01\n")
+	fmt.Fprintf(&b, "0,0,species,Marker Bird,Only%d marker,1,Home,,2001-01-01\n", n)
+	for i := range n - 2 {
+		fmt.Fprintf(&b, "%d,%d,species,Bird %d,Genus species%d,1,Home,,2001-01-01\n", i, i, i, i)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("lifelist_%d.csv", n))
+	require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o600))
+	return path
+}
+
+// reloadSettings returns settings for the alternating small and large life lists.
+func reloadSettings(t testing.TB) (small, large *conf.Settings) {
+	t.Helper()
+	dir := t.TempDir()
+	small = &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: writeReloadLifeList(t, dir, reloadListSmall)}}
+	large = &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: writeReloadLifeList(t, dir, reloadListLarge)}}
+	return small, large
+}
+
+func TestLifeListLookups_ConsistentDuringReload(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	small, large := reloadSettings(t)
+	require.NoError(t, loadLifeList(small))
+
+	const (
+		readers = 4
+		reloads = 200
+	)
+
+	var (
+		stop     atomic.Bool
+		lookups  atomic.Int64
+		failures atomic.Int64
+		wg       sync.WaitGroup
+	)
+
+	for range readers {
+		wg.Go(func() {
+			for !stop.Load() {
+				shared := isInLifeList("Shared species")
+				list := loadedLifeList.Snapshot()
+				lookups.Add(1)
+
+				// A complete list is either the small or the large one, with its marker species
+				size := len(list)
+				_, smallMarker := list[strings.ToLower(fmt.Sprintf("Only%d marker", reloadListSmall))]
+				_, largeMarker := list[strings.ToLower(fmt.Sprintf("Only%d marker", reloadListLarge))]
+				complete := (size == reloadListSmall && smallMarker && !largeMarker) ||
+					(size == reloadListLarge && largeMarker && !smallMarker)
+				if !shared || !complete {
+					failures.Add(1)
+				}
+				runtime.Gosched()
+			}
+		})
+	}
+
+	for i := range reloads {
+		settings := small
+		if i%2 == 0 {
+			settings = large
+		}
+		require.NoError(t, loadLifeList(settings))
+	}
+	stop.Store(true)
+	wg.Wait()
+
+	assert.Zero(t, failures.Load(), "lookups must always see a complete old or new list")
+	assert.Positive(t, lookups.Load())
+}
+
+func TestLifeListLookups_DoNotBlockOnSlowReload(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	small, _ := reloadSettings(t)
+	require.NoError(t, loadLifeList(small))
+	body, err := os.ReadFile(small.SoundId.LifeListPath)
+	require.NoError(t, err)
+
+	// The server holds the reload mid-fetch until released
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(fetching)
+		<-release
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	reloaded := make(chan error, 1)
+	go func() {
+		reloaded <- loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: server.URL}})
+	}()
+	<-fetching
+
+	looked := make(chan bool, 1)
+	go func() { looked <- isInLifeList("Shared species") }()
+	select {
+	case found := <-looked:
+		assert.True(t, found, "the old list stays visible while the reload is in flight")
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup blocked behind an in-flight reload")
+	}
+
+	close(release)
+	require.NoError(t, <-reloaded)
+	assert.True(t, isInLifeList("Shared species"))
+}
+
+func BenchmarkIsInLifeList_DuringReload(b *testing.B) {
+	saved := loadedLifeList.Snapshot()
+	b.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	small, large := reloadSettings(b)
+	require.NoError(b, loadLifeList(small))
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 0; !stop.Load(); i++ {
+			settings := small
+			if i%2 == 0 {
+				settings = large
+			}
+			_ = loadLifeList(settings)
+		}
+	})
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if !isInLifeList("Shared species") {
+				b.Error("shared species missing from life list")
+			}
+		}
+	})
+	b.StopTimer()
+
+	stop.Store(true)
+	wg.Wait()
+}
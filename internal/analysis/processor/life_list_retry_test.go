@@ -0,0 +1,46 @@
+// life_list_retry_test.go: Tests for classifying life list load failures as retryable
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestLoadLifeList_RetryableFailures(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	malformed := filepath.Join(dir, "malformed.csv")
+	require.NoError(t, os.WriteFile(malformed, []byte("1,1,species,Great Tit,Parus major\n2,2,species\n"), 0o600))
+	badJSON := filepath.Join(dir, "lifelist.json")
+	require.NoError(t, os.WriteFile(badJSON, []byte(`{"not": "a list"}`), 0o600))
+
+	for _, tt := range []struct {
+		name      string
+		path      string
+		retryable bool
+	}{
+		{"missing file", filepath.Join(dir, "missing.csv"), true},
+		{"malformed row", malformed, false},
+		{"invalid JSON", badJSON, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: tt.path}})
+			require.Error(t, err)
+			assert.Equal(t, tt.retryable, errors.IsRetryable(err))
+			assert.Equal(t, !tt.retryable, errors.IsPermanent(err))
+		})
+	}
+
+	// Naming the file of a multi-file load keeps the classification of the failure
+	err := loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPaths: []string{malformed, filepath.Join(dir, "missing.csv")}}})
+	require.Error(t, err)
+	assert.True(t, errors.IsPermanent(err))
+}
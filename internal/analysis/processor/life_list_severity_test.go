@@ -0,0 +1,47 @@
+// life_list_severity_test.go: Tests for the severity of life list load failures
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestLoadLifeList_Severity(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	err := loadLifeList(&conf.Settings{})
+	require.Error(t, err)
+	assert.Equal(t, errors.SeverityFatal, errors.SeverityOf(err), "a missing path needs the configuration fixed")
+
+	err = loadLifeList(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: filepath.Join(t.TempDir(), "missing.csv")}})
+	require.Error(t, err)
+	assert.Equal(t, errors.SeverityError, errors.SeverityOf(err))
+}
+
+func TestReloadLifeList_FailureIsWarning(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+
+	require.NoError(t, os.Remove(path))
+	err := p.ReloadLifeList(t.Context())
+	require.Error(t, err)
+	assert.Equal(t, errors.SeverityWarning, errors.SeverityOf(err), "the loaded list stays in use")
+	assert.True(t, errors.IsRetryable(err), "marking the severity keeps the retry classification")
+	assert.True(t, isInLifeList("Parus major"))
+
+	p.Settings.SoundId.LifeListPath = ""
+	assert.Equal(t, errors.SeverityWarning, errors.SeverityOf(p.ReloadLifeList(t.Context())),
+		"a reload keeps the current list whatever failed")
+}
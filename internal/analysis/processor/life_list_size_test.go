@@ -0,0 +1,129 @@
+// life_list_size_test.go: Tests for the life list file size and entry limits
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// paddedLifeList returns a one-species life list of repeated rows, at least size bytes long.
+func paddedLifeList(size int) string {
+	row := "1,1,species,Great Tit,Parus major\n"
+	return strings.Repeat(row, size/len(row)+1)
+}
+
+func TestLoadLifeList_RejectsFileOverSizeLimit(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+	loadedLifeList.Load(nil)
+
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.csv")
+	large := filepath.Join(dir, "large.csv")
+	require.NoError(t, os.WriteFile(small, []byte(paddedLifeList(1<<19)), 0o600))
+	require.NoError(t, os.WriteFile(large, []byte(paddedLifeList(1<<20+1)), 0o600))
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: large, LifeListMaxSizeMB: 1}}
+	err := loadLifeList(settings)
+	var enhanced *errors.EnhancedError
+	require.ErrorAs(t, err, &enhanced)
+	assert.Equal(t, errors.CategoryValidation, enhanced.Category)
+	assert.Contains(t, err.Error(), "1 MB limit")
+	assert.Greater(t, enhanced.GetContext()["size_bytes"], int64(1<<20))
+	assert.Nil(t, loadedLifeList.Snapshot(), "nothing is loaded from an oversized file")
+
+	settings.SoundId.LifeListPath = small
+	require.NoError(t, loadLifeList(settings))
+	assert.True(t, isInLifeList("Parus major"), "a file under the limit loads normally")
+}
+
+func TestLoadLifeList_RejectsDownloadOverSizeLimit(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	body := paddedLifeList(1<<20 + 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// Flushing first makes the response chunked, so the length isn't known up front
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: server.URL + "/lifelist.csv", LifeListMaxSizeMB: 1}}
+	// With a deadline the HTTP client keeps the body readable after the request returns
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	err := loadLifeListCtx(ctx, settings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 MB limit")
+}
+
+// writeSpeciesLifeList writes a Merlin layout life list of the given species to a new file.
+func writeSpeciesLifeList(t *testing.T, name string, species ...string) string {
+	t.Helper()
+	var b strings.Builder
+	for i, s := range species {
+		fmt.Fprintf(&b, "%d,%d,species,,%s\n", i+1, i+1, s)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o600))
+	return path
+}
+
+func TestLoadLifeList_RejectsListOverEntryLimit(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:       writeSpeciesLifeList(t, "small.csv", "Parus major", "Turdus merula"),
+		LifeListMaxEntries: 3,
+	}}
+	require.NoError(t, loadLifeList(settings))
+
+	settings.SoundId.LifeListPath = writeSpeciesLifeList(t, "large.csv",
+		"Erithacus rubecula", "Fringilla coelebs", "Sitta europaea", "Troglodytes troglodytes", "Pica pica")
+	err := loadLifeList(settings)
+	var enhanced *errors.EnhancedError
+	require.ErrorAs(t, err, &enhanced)
+	assert.Equal(t, errors.CategoryValidation, enhanced.Category)
+	assert.Contains(t, err.Error(), "more than 3 species")
+	assert.Equal(t, 3, enhanced.GetContext()["limit"])
+
+	// The list loaded before stays in place, without any of the oversized file's species
+	assert.True(t, isInLifeList("Parus major"))
+	assert.True(t, isInLifeList("Turdus merula"))
+	assert.False(t, isInLifeList("Erithacus rubecula"))
+	assert.Len(t, loadedLifeList.Snapshot(), 2)
+}
+
+func TestLoadLifeList_EntryLimitAppliesToMergedFiles(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+	loadedLifeList.Load(nil)
+
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:       writeSpeciesLifeList(t, "first.csv", "Parus major", "Turdus merula"),
+		LifeListPaths:      []string{writeSpeciesLifeList(t, "second.csv", "Parus major", "Pica pica")},
+		LifeListMaxEntries: 3,
+	}}
+	require.NoError(t, loadLifeList(settings), "a species on both files counts once")
+	assert.Len(t, loadedLifeList.Snapshot(), 3)
+
+	settings.SoundId.LifeListMaxEntries = 2
+	err := loadLifeList(settings)
+	require.Error(t, err, "each file is under the limit but the merged list isn't")
+	assert.Contains(t, err.Error(), "more than 2 species")
+	assert.Len(t, loadedLifeList.Snapshot(), 3)
+}
@@ -0,0 +1,154 @@
+package processor
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LifeListStats summarizes the life list for the dashboard.
+type LifeListStats struct {
+	TotalSpecies    int
+	AddedThisWeek   int              // First seen since Monday of the current week
+	AddedThisMonth  int              // First seen since the first of the current month
+	AddedThisYear   int              // First seen since January 1 of the current year
+	SeenToday       int              // Distinct species with an approved detection today
+	MostRecentLifer *LifeListEntry   // Entry with the latest first-seen date, nil if none is dated
+	Taxonomy        LifeListTaxonomy // Taxonomy versions of the life list and the model
+}
+
+// dailySpeciesSet tracks the distinct species detected on the current day. It resets
+// itself on the first record or query after midnight.
+type dailySpeciesSet struct {
+	mu      sync.Mutex
+	day     string
+	species map[string]BigDaySpecies
+
+	// onDayEnd receives the finished day's species when the set rolls over to a new day;
+	// nil discards them. It is called without mu held.
+	onDayEnd func(BigDaySummary)
+}
+
+// setOnDayEnd sets the function that receives each finished day.
+func (s *dailySpeciesSet) setOnDayEnd(fn func(BigDaySummary)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onDayEnd = fn
+}
+
+// roll starts a new day's set when now falls on a different day. It returns a function
+// handing the finished day to onDayEnd, for the caller to run after releasing mu, or nil
+// when there is nothing to hand over. Callers hold mu.
+func (s *dailySpeciesSet) roll(now time.Time) (notify func()) {
+	day := now.Format(time.DateOnly)
+	if day == s.day {
+		return nil
+	}
+
+	if onDayEnd := s.onDayEnd; onDayEnd != nil && len(s.species) > 0 {
+		summary := s.summary(now)
+		notify = func() { onDayEnd(summary) }
+	}
+	s.day = day
+	s.species = make(map[string]BigDaySpecies)
+	return notify
+}
+
+// add records a detection of scientificName at the given time. The first detection of
+// each species on a day is kept.
+func (s *dailySpeciesSet) add(scientificName, commonName string, at time.Time) {
+	if scientificName == "" {
+		return
+	}
+	s.mu.Lock()
+	notify := s.roll(at)
+	key := strings.ToLower(scientificName)
+	if _, exists := s.species[key]; !exists {
+		s.species[key] = BigDaySpecies{ScientificName: scientificName, CommonName: commonName, FirstDetected: at}
+	}
+	s.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// count returns the number of species seen on the day of now.
+func (s *dailySpeciesSet) count(now time.Time) int {
+	s.mu.Lock()
+	notify := s.roll(now)
+	count := len(s.species)
+	s.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+	return count
+}
+
+// take returns the current day's summary as of now and starts the day's set over.
+func (s *dailySpeciesSet) take(now time.Time) BigDaySummary {
+	s.mu.Lock()
+	notify := s.roll(now)
+	summary := s.summary(now)
+	s.species = make(map[string]BigDaySpecies)
+	s.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+	return summary
+}
+
+// summary builds a summary of the current set, species in detection order. Callers hold mu.
+func (s *dailySpeciesSet) summary(now time.Time) BigDaySummary {
+	species := slices.Collect(maps.Values(s.species))
+	slices.SortFunc(species, func(a, b BigDaySpecies) int {
+		return a.FirstDetected.Compare(b.FirstDetected)
+	})
+	day := s.day
+	if day == "" {
+		day = now.Format(time.DateOnly)
+	}
+	return BigDaySummary{Day: day, CreatedAt: now, Species: species}
+}
+
+// LifeListStats aggregates life list statistics as of now.
+func (p *Processor) LifeListStats(now time.Time) LifeListStats {
+	stats := LifeListStats{SeenToday: p.seenToday.count(now), Taxonomy: p.LifeListTaxonomy()}
+
+	list := loadedLifeList.Snapshot()
+	if list == nil {
+		return stats
+	}
+	stats.TotalSpecies = len(list)
+
+	year, month, day := now.Date()
+	startOfYear := time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+	startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	startOfWeek := time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, now.Location())
+
+	for _, entry := range list {
+		if entry.FirstSeen.IsZero() || entry.FirstSeen.After(now) {
+			continue
+		}
+		if !entry.FirstSeen.Before(startOfYear) {
+			stats.AddedThisYear++
+		}
+		if !entry.FirstSeen.Before(startOfMonth) {
+			stats.AddedThisMonth++
+		}
+		if !entry.FirstSeen.Before(startOfWeek) {
+			stats.AddedThisWeek++
+		}
+		if stats.MostRecentLifer == nil || entry.FirstSeen.After(stats.MostRecentLifer.FirstSeen) {
+			lifer := entry
+			stats.MostRecentLifer = &lifer
+		}
+	}
+
+	return stats
+}
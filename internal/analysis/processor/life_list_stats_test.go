@@ -0,0 +1,62 @@
+// life_list_stats_test.go: Tests for life list statistics aggregation
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestLifeListStats_PeriodCounts(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	// Wednesday; the week started on Monday the 13th
+	now := time.Date(2026, 5, 15, 12, 0, 0, 0, time.Local)
+	csv := "Row #,Taxon Order,Category,Common Name,Scientific Name,Count,Location,S/P,Date\n" +
+		"1,1,species,Eurasian Blackbird,Turdus merula,1,Home,,2026-05-14\n" + // this week
+		"2,2,species,European Robin,Erithacus rubecula,1,Home,,2026-05-13\n" + // this week (Monday)
+		"3,3,species,Great Tit,Parus major,1,Home,,2026-05-02\n" + // this month
+		"4,4,species,Common Chaffinch,Fringilla coelebs,1,Home,,02 Feb 2026\n" + // this year
+		"5,5,species,Eurasian Wren,Troglodytes troglodytes,1,Home,,2025-12-31\n" + // last year
+		"6,6,species,Common Swift,Apus apus,1,Home,,\n" // undated
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte(csv), 0o600))
+
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+
+	p.seenToday.add("Turdus merula", "Eurasian Blackbird", now)
+	p.seenToday.add("turdus merula", "Eurasian Blackbird", now)
+	p.seenToday.add("Parus major", "Great Tit", now)
+
+	stats := p.LifeListStats(now)
+	assert.Equal(t, 6, stats.TotalSpecies, "header row must not count as a species")
+	assert.Equal(t, 2, stats.AddedThisWeek)
+	assert.Equal(t, 3, stats.AddedThisMonth)
+	assert.Equal(t, 4, stats.AddedThisYear)
+	assert.Equal(t, 2, stats.SeenToday)
+
+	require.NotNil(t, stats.MostRecentLifer)
+	assert.Equal(t, "Turdus merula", stats.MostRecentLifer.ScientificName)
+	assert.Equal(t, "Eurasian Blackbird", stats.MostRecentLifer.CommonName)
+}
+
+func TestDailySpeciesSet_CountsDistinctSpeciesToday(t *testing.T) {
+	t.Parallel()
+
+	var set dailySpeciesSet
+	now := time.Date(2026, 5, 15, 8, 0, 0, 0, time.Local)
+	set.add("Turdus merula", "", now)
+	set.add("TURDUS MERULA", "", now.Add(time.Hour))
+	set.add("Parus major", "", now.Add(2*time.Hour))
+	set.add("", "", now)
+
+	assert.Equal(t, 2, set.count(now.Add(3*time.Hour)))
+	assert.Zero(t, set.count(now.AddDate(0, 0, 1)))
+}
@@ -0,0 +1,300 @@
+package processor
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// LifeListStatus records how a life list species was observed.
+type LifeListStatus string
+
+// Life list statuses. Species added from detections start as heard only, since the
+// system only has audio; imported species are taken as seen.
+const (
+	LifeListStatusHeard LifeListStatus = "heard"
+	LifeListStatusSeen  LifeListStatus = "seen"
+	LifeListStatusBoth  LifeListStatus = "both"
+)
+
+// lifeListStatusMu serializes changes to the life list and writes of the status file.
+var lifeListStatusMu sync.Mutex
+
+// lifeListStatusRecord is one species in the persisted status file.
+type lifeListStatusRecord struct {
+	ScientificName string         `json:"scientificName"`
+	CommonName     string         `json:"commonName,omitempty"`
+	FirstSeen      time.Time      `json:"firstSeen"`
+	Status         LifeListStatus `json:"status"`
+	Merged         bool           `json:"merged,omitempty"`
+}
+
+// lookupLifeList returns the life list entry for scientificName.
+func lookupLifeList(scientificName string) (LifeListEntry, bool) {
+	entry, found, _ := loadedLifeList.Lookup(scientificName)
+	return entry, found
+}
+
+// recordHeardSpecies adds a detected species missing from the loaded life list as heard
+// only. Nothing is added while no life list is loaded. It reports whether the species
+// was added.
+func recordHeardSpecies(settings *conf.Settings, scientificName, commonName string, at time.Time) (added bool) {
+	if scientificName == "" {
+		return false
+	}
+	key := lifeListKey(scientificName)
+
+	// Deferred before the lock, so the callbacks run once the list is unlocked
+	defer func() {
+		if added {
+			notifyLifeListAdd(scientificName, at)
+		}
+	}()
+	lifeListStatusMu.Lock()
+	defer lifeListStatusMu.Unlock()
+
+	current := loadedLifeList.Snapshot()
+	if current == nil {
+		return false
+	}
+	if _, exists := findLifeListEntry(current, scientificName); exists {
+		return false // Already listed, possibly through a genus entry
+	}
+
+	list := maps.Clone(current)
+	list[key] = LifeListEntry{
+		ScientificName: scientificName,
+		CommonName:     commonName,
+		FirstSeen:      at,
+		Status:         LifeListStatusHeard,
+	}
+	loadedLifeList.Load(list)
+
+	GetLogger().Info("Added heard species to life list",
+		logger.String("species", commonName),
+		logger.String("scientific_name", scientificName),
+		logger.String("operation", "life_list_add"))
+
+	persistLifeListStatuses(settings.SoundId.LifeListStatusPath, list)
+	return true
+}
+
+// PromoteLifeListSpecies marks a heard-only life list species as also seen. Species that
+// are already seen are returned unchanged.
+func (p *Processor) PromoteLifeListSpecies(scientificName string) (LifeListEntry, error) {
+	key := strings.ToLower(strings.TrimSpace(scientificName))
+
+	lifeListStatusMu.Lock()
+	defer lifeListStatusMu.Unlock()
+
+	var (
+		entry  LifeListEntry
+		exists bool
+	)
+	current := loadedLifeList.Snapshot()
+	if current != nil {
+		entry, exists = current[key]
+	}
+	if !exists {
+		return LifeListEntry{}, errors.Newf("species %q is not on the life list", scientificName).
+			Component("life_list").
+			Category(errors.CategoryNotFound).
+			Context("scientific_name", scientificName).
+			Build()
+	}
+	if entry.Status != LifeListStatusHeard {
+		return entry, nil
+	}
+
+	entry.Status = LifeListStatusBoth
+	list := maps.Clone(current)
+	list[key] = entry
+	loadedLifeList.Load(list)
+
+	persistLifeListStatuses(p.Settings.SoundId.LifeListStatusPath, list)
+	return entry, nil
+}
+
+// LifeListEntry returns the life list entry for scientificName, if the species is on the list.
+func (p *Processor) LifeListEntry(scientificName string) (LifeListEntry, bool) {
+	return lookupLifeList(scientificName)
+}
+
+// CommonName returns the common name the life list records for scientificName. It reports
+// false when the species isn't on the list, and an empty name when the file has no common
+// name column.
+func (p *Processor) CommonName(scientificName string) (string, bool) {
+	entry, ok := lookupLifeList(scientificName)
+	return entry.CommonName, ok
+}
+
+// ScientificName returns the scientific name of the life list species with the given common
+// name, compared case-insensitively. Entries without a common name never match.
+func (p *Processor) ScientificName(commonName string) (string, bool) {
+	commonName = strings.TrimSpace(commonName)
+	list := loadedLifeList.Snapshot()
+	if list == nil || commonName == "" {
+		return "", false
+	}
+	for _, entry := range list {
+		if strings.EqualFold(entry.CommonName, commonName) {
+			return entry.ScientificName, true
+		}
+	}
+	return "", false
+}
+
+// ScientificNames returns the scientific names of every life list species with the given
+// common name, compared case-insensitively and sorted. A common name may be shared by species
+// the list keeps apart, so more than one can match; none match an empty name.
+func (p *Processor) ScientificNames(commonName string) []string {
+	commonName = strings.TrimSpace(commonName)
+	list := loadedLifeList.Snapshot()
+	if list == nil || commonName == "" {
+		return nil
+	}
+	var names []string
+	for _, entry := range list {
+		if strings.EqualFold(entry.CommonName, commonName) {
+			names = append(names, entry.ScientificName)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LifeListEntries returns the life list sorted by scientific name.
+func (p *Processor) LifeListEntries() []LifeListEntry {
+	list := loadedLifeList.Snapshot()
+	if list == nil {
+		return nil
+	}
+	entries := slices.Collect(maps.Values(list))
+	slices.SortFunc(entries, func(a, b LifeListEntry) int {
+		return strings.Compare(strings.ToLower(a.ScientificName), strings.ToLower(b.ScientificName))
+	})
+	return entries
+}
+
+// LifeListCount returns the number of entries on the loaded life list, 0 when none is loaded.
+func LifeListCount() int {
+	return loadedLifeList.Len()
+}
+
+// applyLifeListStatuses merges the persisted status file into a freshly parsed list, so
+// heard species and promotions survive reloads and restarts. A missing file is not an error.
+func applyLifeListStatuses(path string, list map[string]LifeListEntry) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_status").
+			Build()
+	}
+
+	var records []lifeListStatusRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileParsing).
+			Context("operation", "parse_status").
+			Build()
+	}
+
+	for _, r := range records {
+		key := strings.ToLower(r.ScientificName)
+		if key == "" {
+			continue
+		}
+		entry, exists := list[key]
+		if !exists {
+			entry = LifeListEntry{ScientificName: r.ScientificName, CommonName: r.CommonName, FirstSeen: r.FirstSeen, merged: r.Merged}
+		}
+		entry.Status = r.Status
+		list[key] = entry
+	}
+	return nil
+}
+
+// persistLifeListStatuses writes the species whose status differs from an imported one, and
+// those added by an import merge, to path. Failures are logged; the in-memory list stays authoritative. Callers hold lifeListStatusMu.
+func persistLifeListStatuses(path string, list map[string]LifeListEntry) {
+	if path == "" {
+		return
+	}
+
+	records := make([]lifeListStatusRecord, 0)
+	for _, entry := range list {
+		if entry.merged || entry.Status == LifeListStatusHeard || entry.Status == LifeListStatusBoth {
+			records = append(records, lifeListStatusRecord{
+				ScientificName: entry.ScientificName,
+				CommonName:     entry.CommonName,
+				FirstSeen:      entry.FirstSeen,
+				Status:         entry.Status,
+				Merged:         entry.merged,
+			})
+		}
+	}
+	slices.SortFunc(records, func(a, b lifeListStatusRecord) int {
+		return strings.Compare(strings.ToLower(a.ScientificName), strings.ToLower(b.ScientificName))
+	})
+
+	if err := writeLifeListStatusFile(path, records); err != nil {
+		GetLogger().Warn("Failed to persist life list status",
+			logger.String("path", path),
+			logger.Error(err),
+			logger.String("operation", "life_list_persist"))
+	}
+}
+
+// writeLifeListStatusFile replaces the status file with the given records.
+func writeLifeListStatusFile(path string, records []lifeListStatusRecord) error {
+	return writeJSONFile(path, records)
+}
+
+// writeJSONFile replaces path with v as indented JSON.
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic replaces path with data through a temporary file, so a crash mid-write
+// leaves the previous file intact. An existing file keeps its permissions.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		_ = tmp.Chmod(info.Mode().Perm())
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
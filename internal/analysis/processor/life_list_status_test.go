@@ -0,0 +1,100 @@
+// life_list_status_test.go: Tests for heard/seen life list status
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// newLifeListStatusProcessor loads a one-species life list with a status file in a temp dir.
+func newLifeListStatusProcessor(t *testing.T) *Processor {
+	t.Helper()
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Great Tit,Parus major,1,Home,,2001-06-01\n"), 0o600))
+
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:       path,
+		LifeListStatusPath: filepath.Join(dir, "lifelist_status.json"),
+	}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	return p
+}
+
+func TestRecordHeardSpecies_AddsHeardOnlyAndPromotes(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	heardAt := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+
+	imported, ok := lookupLifeList("Parus major")
+	require.True(t, ok)
+	assert.Equal(t, LifeListStatusSeen, imported.Status, "imported species are seen")
+
+	assert.True(t, recordHeardSpecies(p.Settings, "Turdus merula", "Eurasian Blackbird", heardAt))
+	assert.False(t, recordHeardSpecies(p.Settings, "Turdus merula", "Eurasian Blackbird", heardAt.Add(time.Hour)))
+	assert.False(t, recordHeardSpecies(p.Settings, "Parus major", "Great Tit", heardAt), "known species are not re-added")
+
+	heard, ok := lookupLifeList("turdus merula")
+	require.True(t, ok)
+	assert.Equal(t, LifeListStatusHeard, heard.Status)
+	assert.True(t, heard.FirstSeen.Equal(heardAt))
+
+	// The heard species survives a reload from the persisted status file
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	heard, ok = lookupLifeList("Turdus merula")
+	require.True(t, ok)
+	assert.Equal(t, LifeListStatusHeard, heard.Status)
+
+	promoted, err := p.PromoteLifeListSpecies("Turdus merula")
+	require.NoError(t, err)
+	assert.Equal(t, LifeListStatusBoth, promoted.Status)
+
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	heard, _ = lookupLifeList("Turdus merula")
+	assert.Equal(t, LifeListStatusBoth, heard.Status)
+
+	seen, err := p.PromoteLifeListSpecies("Parus major")
+	require.NoError(t, err)
+	assert.Equal(t, LifeListStatusSeen, seen.Status, "seen species are left unchanged")
+}
+
+func TestPromoteLifeListSpecies_UnknownSpecies(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+
+	_, err := p.PromoteLifeListSpecies("Apus apus")
+	require.Error(t, err)
+	var enhancedErr *errors.EnhancedError
+	require.ErrorAs(t, err, &enhancedErr)
+	assert.Equal(t, errors.CategoryNotFound, enhancedErr.Category)
+}
+
+func TestRecordHeardSpecies_NoLifeListLoaded(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+	loadedLifeList.Load(nil)
+
+	assert.False(t, recordHeardSpecies(&conf.Settings{}, "Turdus merula", "Eurasian Blackbird", time.Now()))
+	assert.Nil(t, loadedLifeList.Snapshot())
+}
+
+func TestLifeListCount(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	loadedLifeList.Load(nil)
+	assert.Zero(t, LifeListCount(), "nothing is loaded")
+
+	newLifeListStatusProcessor(t)
+	assert.Equal(t, 1, LifeListCount())
+	require.True(t, recordHeardSpecies(&conf.Settings{}, "Turdus merula", "Eurasian Blackbird", time.Now()))
+	assert.Equal(t, 2, LifeListCount(), "a heard species counts once added")
+}
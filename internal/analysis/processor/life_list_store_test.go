@@ -0,0 +1,81 @@
+// life_list_store_test.go: Tests for the lifeList store type
+package processor
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifeList_EmptyUntilLoaded(t *testing.T) {
+	var list lifeList
+	assert.False(t, list.Contains("Turdus merula"))
+	assert.Zero(t, list.Len())
+
+	list.Load(map[string]LifeListEntry{"turdus merula": {ScientificName: "Turdus merula"}})
+	assert.True(t, list.Contains("Turdus merula"))
+	assert.True(t, list.Contains("TURDUS MERULA"), "lookups ignore case")
+	assert.False(t, list.Contains("Parus major"))
+	assert.False(t, list.Contains(""))
+	assert.Equal(t, 1, list.Len())
+}
+
+func TestLifeList_SnapshotAndUnload(t *testing.T) {
+	var list lifeList
+	assert.False(t, list.Loaded())
+	assert.Nil(t, list.Snapshot())
+	_, found, loaded := list.Lookup("Turdus merula")
+	assert.False(t, found)
+	assert.False(t, loaded)
+
+	list.Load(map[string]LifeListEntry{})
+	assert.True(t, list.Loaded(), "an empty list is still a loaded list")
+	assert.NotNil(t, list.Snapshot())
+	_, found, loaded = list.Lookup("Turdus merula")
+	assert.False(t, found)
+	assert.True(t, loaded)
+
+	entries := map[string]LifeListEntry{"turdus merula": {ScientificName: "Turdus merula"}}
+	list.Load(entries)
+	assert.Equal(t, entries, list.Snapshot())
+	entry, found, _ := list.Lookup("Turdus Merula")
+	assert.True(t, found)
+	assert.Equal(t, "Turdus merula", entry.ScientificName)
+
+	list.Load(nil)
+	assert.False(t, list.Loaded(), "loading nil unloads the list")
+	assert.Zero(t, list.Len())
+}
+
+func TestLifeList_ConcurrentLoadAndContains(t *testing.T) {
+	var list lifeList
+	small := map[string]LifeListEntry{"turdus merula": {ScientificName: "Turdus merula"}}
+	large := map[string]LifeListEntry{
+		"turdus merula": {ScientificName: "Turdus merula"},
+		"parus major":   {ScientificName: "Parus major"},
+	}
+	list.Load(small)
+
+	const iterations = 1000
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range iterations {
+			if i%2 == 0 {
+				list.Load(large)
+			} else {
+				list.Load(small)
+			}
+		}
+	})
+	for range 4 {
+		wg.Go(func() {
+			for range iterations {
+				assert.True(t, list.Contains("Turdus merula"), "a species on both lists is always found")
+				n := list.Len()
+				assert.True(t, n == len(small) || n == len(large), "Len sees a whole list, got %d", n)
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,65 @@
+// life_list_suppressed.go: detections raising no new species alert because the species is already on the life list
+package processor
+
+import (
+	"sync"
+	"time"
+)
+
+// maxSuppressedLifeListDetections bounds the recent suppressed detections kept; the oldest
+// are dropped first while the total keeps counting
+const maxSuppressedLifeListDetections = 100
+
+// SuppressedLifeListDetection is an approved detection that raised no new species alert
+// because its species is already on the life list.
+type SuppressedLifeListDetection struct {
+	ScientificName string
+	CommonName     string
+	Confidence     float64
+	Source         string // Display name of the audio source
+	DetectedAt     time.Time
+}
+
+// lifeListSuppressedLog counts suppressed detections and keeps the most recent ones. The
+// zero value is ready to use.
+type lifeListSuppressedLog struct {
+	mu      sync.Mutex
+	total   uint64
+	entries []SuppressedLifeListDetection
+}
+
+// add counts a suppressed detection and stores it, evicting the oldest once the limit is
+// reached.
+func (l *lifeListSuppressedLog) add(entry SuppressedLifeListDetection) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total++
+	if len(l.entries) >= maxSuppressedLifeListDetections {
+		l.entries = l.entries[len(l.entries)-maxSuppressedLifeListDetections+1:]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+// list returns the total and a copy of up to limit recent detections, newest first; a limit
+// of 0 or less returns all that are kept.
+func (l *lifeListSuppressedLog) list(limit int) (uint64, []SuppressedLifeListDetection) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit <= 0 || limit > len(l.entries) {
+		limit = len(l.entries)
+	}
+	out := make([]SuppressedLifeListDetection, limit)
+	for i := range out {
+		out[i] = l.entries[len(l.entries)-1-i]
+	}
+	return l.total, out
+}
+
+// SuppressedLifeListDetections returns how many approved detections raised no new species
+// alert because their species was already on the life list, and up to limit of the most
+// recent ones, newest first. A limit of 0 or less returns all that are kept.
+func (p *Processor) SuppressedLifeListDetections(limit int) (total uint64, recent []SuppressedLifeListDetection) {
+	return p.lifeListSuppressed.list(limit)
+}
@@ -0,0 +1,57 @@
+// life_list_suppressed_test.go: Tests for detections suppressed as already on the life list
+package processor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertNewSpecies_RecordsSuppressedDetections(t *testing.T) {
+	p := newLifeListStatusProcessor(t)
+	alerts := 0
+	p.SubscribeNewSpecies(func(NewSpeciesEvent) { alerts++ })
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.Local)
+
+	p.alertNewSpecies(newSpeciesDetection("Parus major", "Great Tit", at), 0.9)
+	p.alertNewSpecies(newSpeciesDetection("Turdus merula", "Eurasian Blackbird", at.Add(time.Minute)), 0.9)
+	recordHeardSpecies(p.Settings, "Erithacus rubecula", "European Robin", at)
+	p.alertNewSpecies(newSpeciesDetection("Erithacus rubecula", "European Robin", at.Add(2*time.Minute)), 0.8)
+	p.alertNewSpecies(newSpeciesDetection("Parus major", "Great Tit", at.Add(3*time.Minute)), 0.7)
+
+	assert.Equal(t, 1, alerts, "only the species missing from the list alerts")
+	total, recent := p.SuppressedLifeListDetections(0)
+	assert.Equal(t, uint64(3), total)
+	require.Len(t, recent, 3)
+	assert.Equal(t, SuppressedLifeListDetection{
+		ScientificName: "Parus major",
+		CommonName:     "Great Tit",
+		Confidence:     0.7,
+		Source:         "Garden",
+		DetectedAt:     at.Add(3 * time.Minute),
+	}, recent[0], "the newest detection comes first")
+	assert.Equal(t, "Erithacus rubecula", recent[1].ScientificName, "heard-only species are already on the list")
+	assert.Equal(t, "Parus major", recent[2].ScientificName)
+
+	_, recent = p.SuppressedLifeListDetections(1)
+	assert.Len(t, recent, 1)
+}
+
+func TestLifeListSuppressedLog_Bounded(t *testing.T) {
+	t.Parallel()
+
+	var log lifeListSuppressedLog
+	const added = maxSuppressedLifeListDetections + 25
+	for i := range added {
+		log.add(SuppressedLifeListDetection{ScientificName: fmt.Sprintf("Species %d", i)})
+	}
+
+	total, recent := log.list(0)
+	assert.Equal(t, uint64(added), total, "the total counts evicted detections too")
+	require.Len(t, recent, maxSuppressedLifeListDetections)
+	assert.Equal(t, fmt.Sprintf("Species %d", added-1), recent[0].ScientificName)
+	assert.Equal(t, fmt.Sprintf("Species %d", added-maxSuppressedLifeListDetections), recent[len(recent)-1].ScientificName)
+}
@@ -0,0 +1,120 @@
+// life_list_synonyms.go: matching life list names across taxonomic renames
+package processor
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lifeListSynonyms holds the synonyms of the loaded life list, nil when none are configured,
+// so lookups map names the same way its entries were
+var lifeListSynonyms atomic.Pointer[lifeListSynonymMap]
+
+// lifeListSynonymMap maps the keys of old scientific names to their current names. Each row
+// of a synonyms file holds an old scientific name followed by the name it was renamed to.
+// Names are mapped once, so a species renamed twice lists both old names against the
+// current one.
+type lifeListSynonymMap struct {
+	current map[string]string // Key of an old name to the current scientific name
+	fuzzy   bool              // Keys are fuzzy life list keys
+}
+
+// loadLifeListSynonyms reads the configured synonyms file or URL, returning nil when none is
+// set.
+func loadLifeListSynonyms(ctx context.Context, settings *conf.Settings) (*lifeListSynonymMap, error) {
+	path := settings.SoundId.LifeListSynonymsPath
+	if path == "" {
+		return nil, nil
+	}
+
+	reader, err := openLifeList(ctx, resolveLifeListPath(path, settings.SoundId.DataDir), lifeListMaxBytes(settings))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return parseLifeListSynonyms(skipLifeListBOM(reader), settings.SoundId.LifeListFuzzy)
+}
+
+// parseLifeListSynonyms reads synonym CSV records keyed the way a life list read with fuzzy
+// matching, or without, is. Rows missing either name are skipped, and a row whose first field
+// is "scientific name" is taken as a header.
+func parseLifeListSynonyms(r io.Reader, fuzzy bool) (*lifeListSynonymMap, error) {
+	synonyms := &lifeListSynonymMap{current: map[string]string{}, fuzzy: fuzzy}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Rows are checked for both names one by one
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(err).
+				Component("life_list").
+				Category(errors.CategoryFileParsing).
+				Context("operation", "read_synonyms").
+				Build()
+		}
+		if len(record) < 2 {
+			continue
+		}
+
+		old, current := cleanLifeListField(record[0]), cleanLifeListField(record[1])
+		if old == "" || current == "" || strings.EqualFold(old, "scientific name") {
+			continue
+		}
+		if key := lifeListNameKey(old, fuzzy); key != lifeListNameKey(current, fuzzy) {
+			synonyms.current[key] = current
+		}
+	}
+
+	return synonyms, nil
+}
+
+// key returns the key of the current name of the species with name key nameKey, or nameKey
+// itself when it isn't an old name. A nil map changes no key.
+func (s *lifeListSynonymMap) key(nameKey string) string {
+	if s == nil {
+		return nameKey
+	}
+	if current, renamed := s.current[nameKey]; renamed {
+		return lifeListNameKey(current, s.fuzzy)
+	}
+	return nameKey
+}
+
+// canonicalize returns list with the entries of old names renamed to the current name and
+// stored under its key. When an old name and the current one are both on the list, the
+// entries are merged. A nil map returns list unchanged.
+func (s *lifeListSynonymMap) canonicalize(list map[string]LifeListEntry) map[string]LifeListEntry {
+	if s == nil {
+		return list
+	}
+	canonical := make(map[string]LifeListEntry, len(list))
+	log := GetLogger()
+
+	for key, entry := range list {
+		if current, renamed := s.current[key]; renamed {
+			log.Debug("Renamed life list entry to its current name",
+				logger.String("scientific_name", entry.ScientificName),
+				logger.String("current_name", current),
+				logger.String("common_name", entry.CommonName))
+			entry.ScientificName = current
+			key = lifeListNameKey(current, s.fuzzy)
+		}
+		if existing, exists := canonical[key]; exists {
+			entry = mergeLifeListEntries(existing, entry)
+		}
+		canonical[key] = entry
+	}
+
+	return canonical
+}
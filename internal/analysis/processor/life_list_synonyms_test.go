@@ -0,0 +1,76 @@
+// life_list_synonyms_test.go: Tests for matching life list names across renames
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const testLifeListSynonyms = "Scientific Name,Current Name\n" +
+	"Carduelis tristis,Spinus tristis\n" +
+	"Parus atricapillus,Poecile atricapillus\n"
+
+// loadSynonymTestLifeList loads a life list of rows, with the given synonyms unless empty
+func loadSynonymTestLifeList(t *testing.T, rows, synonyms string) {
+	t.Helper()
+	saved, savedSynonyms := loadedLifeList.Snapshot(), lifeListSynonyms.Load()
+	t.Cleanup(func() {
+		loadedLifeList.Load(saved)
+		lifeListSynonyms.Store(savedSynonyms)
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "life_list.csv"), []byte(rows), 0o600))
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "life_list.csv", DataDir: dir}}
+	if synonyms != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "synonyms.csv"), []byte(synonyms), 0o600))
+		settings.SoundId.LifeListSynonymsPath = "synonyms.csv"
+	}
+	require.NoError(t, loadLifeList(settings))
+}
+
+func TestIsInLifeList_MatchesAcrossRenames(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+	}{
+		{"listed under the old name", "1,1,species,American Goldfinch,Carduelis tristis\n"},
+		{"listed under the current name", "1,1,species,American Goldfinch,Spinus tristis\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadSynonymTestLifeList(t, tt.row, testLifeListSynonyms)
+
+			assert.True(t, isInLifeList("Carduelis tristis"), "a detection under the old name matches")
+			assert.True(t, isInLifeList("Spinus tristis"), "a detection under the current name matches")
+			assert.False(t, isInLifeList("Poecile atricapillus"), "a synonym of an unlisted species doesn't match")
+
+			entry, found := lookupLifeList("carduelis tristis")
+			require.True(t, found)
+			assert.Equal(t, "Spinus tristis", entry.ScientificName, "entries are stored under the current name")
+		})
+	}
+}
+
+func TestIsInLifeList_SynonymsDisabledByDefault(t *testing.T) {
+	loadSynonymTestLifeList(t, "1,1,species,American Goldfinch,Carduelis tristis\n", "")
+
+	assert.True(t, isInLifeList("Carduelis tristis"))
+	assert.False(t, isInLifeList("Spinus tristis"), "without synonyms a renamed species doesn't match")
+}
+
+func TestLoadLifeList_MergesOldAndCurrentNames(t *testing.T) {
+	loadSynonymTestLifeList(t,
+		"1,1,species,American Goldfinch,Carduelis tristis,2019-05-01\n"+
+			"2,2,species,American Goldfinch,Spinus tristis,2021-06-01\n",
+		testLifeListSynonyms)
+
+	list := loadedLifeList.Snapshot()
+	require.NotNil(t, list)
+	assert.Len(t, list, 1, "an old and a current name of one species make one entry")
+}
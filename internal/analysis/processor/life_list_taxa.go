@@ -0,0 +1,64 @@
+// life_list_taxa.go: genus and family rollups of the life list
+package processor
+
+import (
+	"strings"
+)
+
+// LifeListUnknownTaxon is the rollup key of entries that aren't a single species of a known
+// genus or family, such as "Accipiter sp." or hybrids, so they don't add to a taxon's count
+const LifeListUnknownTaxon = "unknown"
+
+// lifeListGenus returns the genus of an entry naming a single species, capitalized as in
+// "Setophaga", or LifeListUnknownTaxon for single words, genus-level entries, hybrids and
+// slashes, which aren't one species of one genus.
+func lifeListGenus(scientificName string) string {
+	fields := strings.Fields(strings.ToLower(scientificName))
+	if len(fields) < 2 || strings.ContainsAny(fields[0], "/()") || isLifeListCompoundName(fields) {
+		return LifeListUnknownTaxon
+	}
+	if _, marked := lifeListGroupKey(scientificName); marked {
+		return LifeListUnknownTaxon
+	}
+	return strings.ToUpper(fields[0][:1]) + fields[0][1:]
+}
+
+// LifeListCountByGenus returns the number of life list species in each genus, with entries
+// that aren't a single species counted under LifeListUnknownTaxon. It is empty when no life
+// list is loaded.
+func (p *Processor) LifeListCountByGenus() map[string]int {
+	counts := make(map[string]int)
+	list := loadedLifeList.Snapshot()
+	if list == nil {
+		return counts
+	}
+	for _, entry := range list {
+		counts[lifeListGenus(entry.ScientificName)]++
+	}
+	return counts
+}
+
+// LifeListCountByFamily returns the number of life list species in each family of the list's
+// family column, with entries lacking a family or naming more than one species counted
+// under LifeListUnknownTaxon. It is empty when no life list is loaded or the list has no
+// family column.
+func (p *Processor) LifeListCountByFamily() map[string]int {
+	counts := make(map[string]int)
+	list := loadedLifeList.Snapshot()
+	if list == nil {
+		return counts
+	}
+	hasFamilies := false
+	for _, entry := range list {
+		family := entry.Family
+		hasFamilies = hasFamilies || family != ""
+		if family == "" || lifeListGenus(entry.ScientificName) == LifeListUnknownTaxon {
+			family = LifeListUnknownTaxon
+		}
+		counts[family]++
+	}
+	if !hasFamilies {
+		clear(counts)
+	}
+	return counts
+}
@@ -0,0 +1,67 @@
+// life_list_taxa_test.go: Tests for the genus and family rollups of the life list
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// loadTaxaTestLifeList loads csv as the life list, with group entries kept
+func loadTaxaTestLifeList(t *testing.T, csv string) *Processor {
+	t.Helper()
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte(csv), 0o600))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path, LifeListGroupMatching: true}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	return p
+}
+
+func TestLifeListCountByGenus(t *testing.T) {
+	p := loadTaxaTestLifeList(t, "Scientific Name,Common Name,Family\n"+
+		"Setophaga petechia,Yellow Warbler,Parulidae\n"+
+		"Setophaga coronata,Yellow-rumped Warbler,Parulidae\n"+
+		"setophaga ruticilla,American Redstart,Parulidae\n"+
+		"Vermivora cyanoptera,Blue-winged Warbler,Parulidae\n"+
+		"Turdus migratorius,American Robin,Turdidae\n"+
+		"Accipiter sp.,Accipiter sp.,Accipitridae\n"+
+		"Vermivora chrysoptera x cyanoptera,Brewster's Warbler,Parulidae\n"+
+		"Empidonax alnorum/traillii,Alder/Willow Flycatcher,\n")
+
+	assert.Equal(t, map[string]int{
+		"Setophaga":          3,
+		"Vermivora":          1,
+		"Turdus":             1,
+		LifeListUnknownTaxon: 3,
+	}, p.LifeListCountByGenus(), "sp., hybrid and slash entries don't count toward a genus")
+
+	assert.Equal(t, map[string]int{
+		"Parulidae":          4,
+		"Turdidae":           1,
+		LifeListUnknownTaxon: 3,
+	}, p.LifeListCountByFamily())
+}
+
+func TestLifeListCountByFamily_NoFamilyColumn(t *testing.T) {
+	p := loadTaxaTestLifeList(t, "1,1,species,Great Tit,Parus major\n2,2,species,Blue Tit,Cyanistes caeruleus\n")
+
+	assert.Equal(t, map[string]int{"Parus": 1, "Cyanistes": 1}, p.LifeListCountByGenus())
+	assert.Empty(t, p.LifeListCountByFamily(), "a list without families has no family rollup")
+}
+
+func TestLifeListCountByGenus_NoListLoaded(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+	loadedLifeList.Load(nil)
+
+	p := &Processor{}
+	assert.Empty(t, p.LifeListCountByGenus())
+	assert.Empty(t, p.LifeListCountByFamily())
+}
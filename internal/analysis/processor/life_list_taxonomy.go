@@ -0,0 +1,97 @@
+// life_list_taxonomy.go: taxonomy version of the life list compared with that of the model
+package processor
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// LifeListTaxonomy is the taxonomy version of the loaded life list and of the active model.
+// Species renamed between the two versions don't match unless synonyms map them.
+type LifeListTaxonomy struct {
+	ListVersion  string // From LifeListTaxonomyVersion or the file's "# taxonomy:" comment, empty when not annotated
+	ModelVersion string // Taxonomy the model's labels follow, empty when unknown
+}
+
+// Mismatch reports whether both versions are known and differ.
+func (t LifeListTaxonomy) Mismatch() bool {
+	return t.ListVersion != "" && t.ModelVersion != "" && !strings.EqualFold(t.ListVersion, t.ModelVersion)
+}
+
+// lifeListTaxonomy is the taxonomy of the loaded life list, nil until one is loaded
+var lifeListTaxonomy atomic.Pointer[LifeListTaxonomy]
+
+// readLifeListTaxonomyComment consumes the comment lines starting with "#" at the top of a
+// life list CSV, which would otherwise be read as malformed rows, and returns the version of
+// the first "# taxonomy: <version>" among them along with the rest of the file.
+func readLifeListTaxonomyComment(r io.Reader) (version string, rest io.Reader) {
+	buffered := bufio.NewReader(skipLifeListBOM(r))
+	for {
+		if next, err := buffered.Peek(1); err != nil || next[0] != '#' {
+			return version, buffered
+		}
+		line, err := buffered.ReadString('\n')
+		if found, ok := parseLifeListTaxonomyComment(line); ok && version == "" {
+			version = found
+		}
+		if err != nil {
+			return version, buffered
+		}
+	}
+}
+
+// parseLifeListTaxonomyComment returns the version of a "# taxonomy: <version>" or
+// "# taxonomy version: <version>" comment line, reporting false for other lines.
+func parseLifeListTaxonomyComment(line string) (string, bool) {
+	key, value, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "#"), ":")
+	if !ok {
+		return "", false
+	}
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "taxonomy", "taxonomy version":
+		value = strings.TrimSpace(value)
+		return value, value != ""
+	}
+	return "", false
+}
+
+// modelTaxonomyVersion returns the taxonomy version of the configured model, or "" when the
+// model or its taxonomy isn't known.
+func modelTaxonomyVersion(settings *conf.Settings) string {
+	identifier := settings.BirdNET.ModelPath
+	if identifier == "" {
+		identifier = birdnet.DefaultModelVersion
+	}
+	info, err := birdnet.DetermineModelInfo(identifier)
+	if err != nil {
+		return ""
+	}
+	return info.TaxonomyVersion
+}
+
+// checkLifeListTaxonomy returns the taxonomy of a life list annotated with listVersion
+// against a model following modelVersion, warning on log when they differ.
+func checkLifeListTaxonomy(listVersion, modelVersion string, log logger.Logger) LifeListTaxonomy {
+	taxonomy := LifeListTaxonomy{ListVersion: listVersion, ModelVersion: modelVersion}
+	if taxonomy.Mismatch() {
+		log.Warn("Life list and model follow different taxonomy versions, renamed species won't match the life list",
+			logger.String("life_list_taxonomy", listVersion),
+			logger.String("model_taxonomy", modelVersion),
+			logger.String("operation", "life_list_taxonomy_check"))
+	}
+	return taxonomy
+}
+
+// LifeListTaxonomy returns the taxonomy versions of the loaded life list and the model.
+func (p *Processor) LifeListTaxonomy() LifeListTaxonomy {
+	if taxonomy := lifeListTaxonomy.Load(); taxonomy != nil {
+		return *taxonomy
+	}
+	return LifeListTaxonomy{}
+}
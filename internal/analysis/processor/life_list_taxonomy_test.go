@@ -0,0 +1,76 @@
+// life_list_taxonomy_test.go: Tests for the taxonomy version check of the life list
+package processor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// loadTaxonomyTestLifeList loads a life list file of content with the given taxonomy setting
+func loadTaxonomyTestLifeList(t *testing.T, content, setting string) *Processor {
+	t.Helper()
+	saved, savedTaxonomy := loadedLifeList.Snapshot(), lifeListTaxonomy.Load()
+	t.Cleanup(func() {
+		loadedLifeList.Load(saved)
+		lifeListTaxonomy.Store(savedTaxonomy)
+	})
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	p := &Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:            path,
+		LifeListTaxonomyVersion: setting,
+	}}}
+	require.NoError(t, p.ReloadLifeList(t.Context()))
+	return p
+}
+
+func TestLoadLifeList_TaxonomyVersion(t *testing.T) {
+	const rows = "1,1,species,Great Tit,Parus major\n"
+	tests := []struct {
+		name     string
+		content  string
+		setting  string
+		version  string
+		mismatch bool
+	}{
+		{"not annotated", rows, "", "", false},
+		{"matching header comment", "# taxonomy: eBird 2021\n" + rows, "", "eBird 2021", false},
+		{"mismatching header comment", "# Exported from Merlin\n# Taxonomy Version: eBird 2023\n" + rows, "", "eBird 2023", true},
+		{"setting overrides the header", "# taxonomy: eBird 2023\n" + rows, "ebird 2021", "ebird 2021", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := loadTaxonomyTestLifeList(t, tt.content, tt.setting)
+
+			assert.True(t, isInLifeList("Parus major"), "comment lines aren't read as rows")
+			taxonomy := p.LifeListStats(time.Now()).Taxonomy
+			assert.Equal(t, tt.version, taxonomy.ListVersion)
+			assert.Equal(t, "eBird 2021", taxonomy.ModelVersion, "the default model follows eBird 2021")
+			assert.Equal(t, tt.mismatch, taxonomy.Mismatch())
+		})
+	}
+}
+
+func TestCheckLifeListTaxonomy_WarnsOnMismatch(t *testing.T) {
+	var logs bytes.Buffer
+	log := logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+
+	checkLifeListTaxonomy("eBird 2021", "eBird 2021", log)
+	checkLifeListTaxonomy("", "eBird 2021", log)
+	checkLifeListTaxonomy("eBird 2023", "", log)
+	assert.Empty(t, logs.String(), "matching or unknown versions aren't warned about")
+
+	taxonomy := checkLifeListTaxonomy("eBird 2023", "eBird 2021", log)
+	assert.True(t, taxonomy.Mismatch())
+	assert.Contains(t, logs.String(), "different taxonomy versions")
+	assert.Contains(t, logs.String(), "eBird 2023")
+}
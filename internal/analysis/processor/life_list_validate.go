@@ -0,0 +1,45 @@
+// life_list_validate.go: dry run of a life list file before it is configured
+package processor
+
+import (
+	"context"
+	"io"
+)
+
+// LifeListReport describes what loading a life list file would find, without loading it.
+type LifeListReport struct {
+	TotalRows  int      // Species rows, counting repeats and malformed rows but not blank or header rows
+	Species    int      // Distinct species the file would put on the list
+	Duplicates []string // Scientific names of rows repeating an earlier entry, once per row
+	Malformed  int      // Rows too short to hold a scientific name
+}
+
+// ValidateLifeListFile parses the life list at path, a file or http(s) URL, with the
+// configured encoding, columns and limits and reports what it holds. The loaded life list
+// is left alone. format overrides the configured LifeListFormat when given. Malformed rows
+// are counted rather than failing the check, so one run reports them all.
+func (p *Processor) ValidateLifeListFile(path string, format ...string) (LifeListReport, error) {
+	reader, err := openLifeList(context.Background(), resolveLifeListPath(path, p.Settings.SoundId.DataDir), lifeListMaxBytes(p.Settings))
+	if err != nil {
+		return LifeListReport{}, err
+	}
+	defer reader.Close()
+
+	return p.ValidateLifeList(reader, path, format...)
+}
+
+// ValidateLifeList checks life list data like ValidateLifeListFile, such as an uploaded
+// file. name is only used to detect JSON files by their extension.
+func (p *Processor) ValidateLifeList(r io.Reader, name string, format ...string) (LifeListReport, error) {
+	opts := newLifeListParseOptions(&p.Settings.SoundId)
+	opts.skipMalformed = true
+	if len(format) > 0 && format[0] != "" {
+		opts.format = format[0]
+	}
+
+	file, err := decodeLifeListFile(r, name, p.Settings.SoundId.LifeListEncoding, opts)
+	if err != nil {
+		return LifeListReport{}, err
+	}
+	return LifeListReport{TotalRows: file.rows, Species: len(file.list), Duplicates: file.duplicates, Malformed: file.malformed}, nil
+}
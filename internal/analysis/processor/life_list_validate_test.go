@@ -0,0 +1,84 @@
+// life_list_validate_test.go: Tests for the dry run of life list files
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestValidateLifeListFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		format   []string
+		want     LifeListReport
+	}{
+		{
+			name:     "clean",
+			file:     "lifelist.csv",
+			contents: "1,1,species,Great Tit,Parus major\n2,2,species,Eurasian Blackbird,Turdus merula\n\n",
+			want:     LifeListReport{TotalRows: 2, Species: 2},
+		},
+		{
+			name: "duplicate heavy",
+			file: "lifelist.csv",
+			contents: "Scientific Name,Common Name\n" +
+				"Parus major,Great Tit\nparus major,Great Tit\n Parus major ,Great Tit\nTurdus merula,Eurasian Blackbird\nTURDUS MERULA,Blackbird\n",
+			want: LifeListReport{TotalRows: 5, Species: 2, Duplicates: []string{"parus major", "Parus major", "TURDUS MERULA"}},
+		},
+		{
+			name:     "malformed",
+			file:     "lifelist.csv",
+			contents: "1,1,species,Great Tit,Parus major\n2,2\n3\n4,4,species,Eurasian Blackbird,Turdus merula\n",
+			want:     LifeListReport{TotalRows: 4, Species: 2, Malformed: 2},
+		},
+		{
+			name:     "json by extension",
+			file:     "lifelist.json",
+			contents: `["Parus major", {"scientificName": "Turdus merula"}, "parus major"]`,
+			want:     LifeListReport{TotalRows: 3, Species: 2, Duplicates: []string{"parus major"}},
+		},
+		{
+			name:     "format override",
+			file:     "lifelist.txt",
+			contents: `["Parus major"]`,
+			format:   []string{conf.LifeListFormatJSON},
+			want:     LifeListReport{TotalRows: 1, Species: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := loadedLifeList.Snapshot()
+			t.Cleanup(func() { loadedLifeList.Load(saved) })
+			loadedLifeList.Load(nil)
+
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+			// Malformed rows are counted even though the settings don't skip them
+			p := &Processor{Settings: &conf.Settings{}}
+
+			report, err := p.ValidateLifeListFile(path, tt.format...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, report)
+			assert.Nil(t, loadedLifeList.Snapshot(), "validating doesn't load the list")
+		})
+	}
+}
+
+func TestValidateLifeListFile_Failures(t *testing.T) {
+	p := &Processor{Settings: &conf.Settings{}}
+
+	_, err := p.ValidateLifeListFile(filepath.Join(t.TempDir(), "missing.csv"))
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "lifelist.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"not": "an array"}`), 0o600))
+	_, err = p.ValidateLifeListFile(path)
+	require.Error(t, err, "a file that can't be parsed at all fails the check")
+}
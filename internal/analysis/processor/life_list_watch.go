@@ -0,0 +1,120 @@
+// life_list_watch.go: reloading a local life list file when it changes on disk
+package processor
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lifeListWatchDebounce is how long the file must be quiet before it is reloaded, so an
+// editor writing the file in several steps triggers a single reload.
+const lifeListWatchDebounce = 500 * time.Millisecond
+
+// WatchLifeList reloads the life list whenever its file is written, replaced or renamed,
+// until ctx is cancelled. A failed reload keeps the previously loaded list. The file's
+// directory is watched rather than the file itself, since many editors and sync tools
+// save by writing a new file and renaming it over the old one, which ends a watch on the
+// file. It returns an error if the life list is not a local file or can't be watched.
+func WatchLifeList(ctx context.Context, settings *conf.Settings) error {
+	path := settings.SoundId.LifeListPath
+	if path == "" || isLifeListURL(path) {
+		return errors.Newf("life list watching needs a local life list file").
+			Component("life_list").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "watch").
+			Severity(errors.SeverityFatal).
+			Build()
+	}
+	path = filepath.Clean(resolveLifeListPath(path, settings.SoundId.DataDir))
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "watch").
+			Build()
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "watch").
+			Build()
+	}
+
+	GetLogger().Info("Watching life list file for changes",
+		logger.String("path", path),
+		logger.String("operation", "life_list_watch"))
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	debounce := time.NewTimer(lifeListWatchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			debounce.Reset(lifeListWatchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			GetLogger().Warn("Life list watcher error",
+				logger.Error(err),
+				logger.String("operation", "life_list_watch"))
+
+		case <-debounce.C:
+			wg.Go(func() { reloadWatchedLifeList(ctx, settings) })
+		}
+	}
+}
+
+// reloadWatchedLifeList reloads the life list after its file changed.
+func reloadWatchedLifeList(ctx context.Context, settings *conf.Settings) {
+	if err := loadLifeListCtx(ctx, settings); err != nil {
+		GetLogger().Warn("Failed to reload changed life list, keeping current list",
+			logger.Error(err),
+			logger.String("operation", "life_list_watch"))
+		return
+	}
+	GetLogger().Info("Reloaded life list after file change",
+		logger.String("operation", "life_list_watch"))
+}
+
+// startLifeListWatch watches a local life list file for changes when enabled.
+func (p *Processor) startLifeListWatch(settings *conf.Settings) {
+	if !settings.SoundId.LifeListWatch || settings.SoundId.LifeListPath == "" || isLifeListURL(settings.SoundId.LifeListPath) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.lifeListWatchCancel = cancel
+	p.lifeListWatchWg.Go(func() {
+		if err := WatchLifeList(ctx, settings); err != nil {
+			GetLogger().Warn("Failed to watch life list file",
+				logger.Error(err),
+				logger.String("operation", "life_list_watch"))
+		}
+	})
+}
@@ -0,0 +1,48 @@
+// life_list_watch_test.go: Tests for reloading the life list when its file changes
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestWatchLifeList_ReloadsRewrittenFile(t *testing.T) {
+	saved := loadedLifeList.Snapshot()
+	t.Cleanup(func() { loadedLifeList.Load(saved) })
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}
+	require.NoError(t, loadLifeList(settings))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- WatchLifeList(ctx, settings) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	time.Sleep(100 * time.Millisecond) // Let the watch be registered
+
+	require.NoError(t, os.WriteFile(path, []byte("1,1,species,Eurasian Wren,Troglodytes troglodytes\n"), 0o600))
+	assert.Eventually(t, func() bool { return isInLifeList("Troglodytes troglodytes") }, 5*time.Second, 50*time.Millisecond,
+		"a rewritten file must be reloaded")
+	assert.False(t, isInLifeList("Parus major"))
+
+	// A broken rewrite keeps the list loaded before it
+	require.NoError(t, os.WriteFile(path, []byte("1,1,\"unterminated\n"), 0o600))
+	time.Sleep(3 * lifeListWatchDebounce)
+	assert.True(t, isInLifeList("Troglodytes troglodytes"), "a failed reload must keep the current list")
+}
+
+func TestWatchLifeList_RejectsURL(t *testing.T) {
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "https://example.com/lifelist.csv"}}
+	require.Error(t, WatchLifeList(t.Context(), settings))
+}
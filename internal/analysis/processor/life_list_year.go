@@ -0,0 +1,187 @@
+// life_list_year.go: first detection of each species per calendar year
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// yearFirstsSuffix replaces the extension of a local life list file to name its year-first
+// sidecar, so lifelist.csv keeps its year firsts in lifelist.yearfirsts.json.
+const yearFirstsSuffix = ".yearfirsts.json"
+
+// YearFirst is the first detection of a species in one calendar year.
+type YearFirst struct {
+	ScientificName string    `json:"scientificName"`
+	CommonName     string    `json:"commonName,omitempty"`
+	Year           int       `json:"year"`
+	FirstDetected  time.Time `json:"firstDetected"`
+}
+
+// yearFirstStore keeps the first detection of each species in the latest year it was
+// detected; a detection in a later year replaces the species' record. It is persisted to a
+// sidecar next to the life list, or kept in memory only when there is no local life list
+// file. The zero value is ready to use.
+type yearFirstStore struct {
+	mu      sync.Mutex
+	path    string               // Sidecar file, empty to keep the records in memory only
+	records map[string]YearFirst // Keyed by life list key
+}
+
+// yearFirstsPath returns the year-first sidecar of the configured life list, or "" when the
+// life list isn't a local file.
+func yearFirstsPath(settings *conf.Settings) string {
+	return lifeListSidecarPath(settings, yearFirstsSuffix)
+}
+
+// lifeListSidecarPath returns the configured life list file with its extension replaced by
+// suffix, or "" when the life list isn't a local file.
+func lifeListSidecarPath(settings *conf.Settings, suffix string) string {
+	path := settings.SoundId.LifeListPath
+	if path == "" || isLifeListURL(path) {
+		return ""
+	}
+	path = resolveLifeListPath(path, settings.SoundId.DataDir)
+	return strings.TrimSuffix(path, filepath.Ext(path)) + suffix
+}
+
+// load replaces the records with those of the sidecar at path, and persists later records
+// there. A missing file holds no records; on a failed read the store starts empty.
+func (s *yearFirstStore) load(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+	s.records = make(map[string]YearFirst)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileIO).
+			Context("operation", "read_year_firsts").
+			Build()
+	}
+
+	var records []YearFirst
+	if err := json.Unmarshal(data, &records); err != nil {
+		return errors.New(err).
+			Component("life_list").
+			Category(errors.CategoryFileParsing).
+			Context("operation", "parse_year_firsts").
+			Build()
+	}
+	for _, r := range records {
+		if key := lifeListKey(r.ScientificName); key != "" {
+			s.records[key] = r
+		}
+	}
+	return nil
+}
+
+// isFirstOfYear reports whether a detection at t would be the species' first in t's year:
+// none was recorded in that year, or only later ones. A year before the recorded one is
+// never reported as a first, since its records were replaced.
+func (s *yearFirstStore) isFirstOfYear(scientificName string, t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, exists := s.records[lifeListKey(scientificName)]
+	return !exists || precedesYearFirst(&record, t)
+}
+
+// precedesYearFirst reports whether t comes before record as a first of the year: in a
+// later year, or earlier in the same one.
+func precedesYearFirst(record *YearFirst, t time.Time) bool {
+	if t.Year() != record.Year {
+		return t.Year() > record.Year
+	}
+	return t.Before(record.FirstDetected)
+}
+
+// record stores a detection at at when it is the species' first of its year, persisting
+// the records, and reports whether it was.
+func (s *yearFirstStore) record(scientificName, commonName string, at time.Time) bool {
+	key := lifeListKey(scientificName)
+	if key == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, exists := s.records[key]; exists && !precedesYearFirst(&record, at) {
+		return false
+	}
+	if s.records == nil {
+		s.records = make(map[string]YearFirst)
+	}
+	s.records[key] = YearFirst{ScientificName: scientificName, CommonName: commonName, Year: at.Year(), FirstDetected: at}
+	s.persistLocked()
+	return true
+}
+
+// persistLocked writes the records to the sidecar, sorted by scientific name. Failures are
+// logged; the records in memory stay authoritative. Callers hold mu.
+func (s *yearFirstStore) persistLocked() {
+	if s.path == "" {
+		return
+	}
+	records := make([]YearFirst, 0, len(s.records))
+	for _, r := range s.records {
+		records = append(records, r)
+	}
+	slices.SortFunc(records, func(a, b YearFirst) int {
+		return strings.Compare(strings.ToLower(a.ScientificName), strings.ToLower(b.ScientificName))
+	})
+	if err := writeJSONFile(s.path, records); err != nil {
+		GetLogger().Warn("Failed to persist year firsts",
+			logger.String("path", s.path),
+			logger.Error(err),
+			logger.String("operation", "year_firsts_persist"))
+	}
+}
+
+// IsFirstOfYear reports whether a detection of scientificName at t would be the species'
+// first of t's calendar year.
+func (p *Processor) IsFirstOfYear(scientificName string, t time.Time) bool {
+	return p.yearFirsts.isFirstOfYear(scientificName, t)
+}
+
+// recordYearFirst records an approved detection as its species' first of the year, when it
+// is, and logs it.
+func (p *Processor) recordYearFirst(scientificName, commonName string, at time.Time) {
+	if !p.yearFirsts.record(scientificName, commonName, at) {
+		return
+	}
+	GetLogger().Info("First detection of the year",
+		logger.String("species", commonName),
+		logger.String("scientific_name", scientificName),
+		logger.Int("year", at.Year()),
+		logger.String("operation", "life_list_year_first"))
+}
+
+// loadYearFirsts loads the year firsts kept next to the configured life list.
+func (p *Processor) loadYearFirsts(settings *conf.Settings) {
+	path := yearFirstsPath(settings)
+	if err := p.yearFirsts.load(path); err != nil {
+		GetLogger().Warn("Failed to load year firsts, starting over",
+			logger.String("path", path),
+			logger.Error(err),
+			logger.String("operation", "year_firsts_load"))
+	}
+}
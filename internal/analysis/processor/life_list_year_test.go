@@ -0,0 +1,72 @@
+// life_list_year_test.go: Tests for tracking the first detection of each species per year
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestYearFirstStore_SameYearRepeats(t *testing.T) {
+	var store yearFirstStore
+	first := time.Date(2026, 3, 10, 6, 0, 0, 0, time.Local)
+
+	assert.True(t, store.isFirstOfYear("Turdus merula", first), "a species never detected is a first")
+	assert.True(t, store.record("Turdus merula", "Eurasian Blackbird", first))
+	assert.False(t, store.isFirstOfYear("Turdus Merula", first.Add(time.Hour)), "names are matched like life list entries")
+	assert.False(t, store.record("Turdus merula", "Eurasian Blackbird", first.AddDate(0, 5, 0)), "repeats in the same year aren't firsts")
+	assert.True(t, store.isFirstOfYear("Turdus merula", first.Add(-time.Hour)), "an earlier detection would have been the first")
+	assert.True(t, store.isFirstOfYear("Parus major", first))
+}
+
+func TestYearFirstStore_YearBoundary(t *testing.T) {
+	var store yearFirstStore
+	newYearsEve := time.Date(2025, 12, 31, 23, 50, 0, 0, time.Local)
+	newYear := time.Date(2026, 1, 1, 0, 10, 0, 0, time.Local)
+
+	require.True(t, store.record("Turdus merula", "Eurasian Blackbird", newYearsEve))
+	assert.False(t, store.isFirstOfYear("Turdus merula", newYearsEve.Add(5*time.Minute)))
+	assert.True(t, store.isFirstOfYear("Turdus merula", newYear), "the species starts over in the new year")
+
+	require.True(t, store.record("Turdus merula", "Eurasian Blackbird", newYear))
+	assert.False(t, store.record("Turdus merula", "Eurasian Blackbird", newYear.Add(time.Hour)))
+	assert.False(t, store.record("Turdus merula", "Eurasian Blackbird", newYearsEve), "a late detection of the old year changes nothing")
+	assert.False(t, store.isFirstOfYear("Turdus merula", newYearsEve), "the replaced year isn't reported as a first")
+}
+
+func TestYearFirsts_PersistedNextToLifeList(t *testing.T) {
+	dir := t.TempDir()
+	settings := &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "lifelist.csv", DataDir: dir}}
+	sidecar := filepath.Join(dir, "lifelist"+yearFirstsSuffix)
+	require.Equal(t, sidecar, yearFirstsPath(settings))
+	assert.Empty(t, yearFirstsPath(&conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: "https://example.com/lifelist.csv"}}),
+		"a URL life list has no sidecar")
+
+	p := &Processor{Settings: settings}
+	p.loadYearFirsts(settings)
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	p.recordYearFirst("Turdus merula", "Eurasian Blackbird", at)
+	p.recordYearFirst("Parus major", "Great Tit", at.Add(time.Minute))
+
+	data, err := os.ReadFile(sidecar)
+	require.NoError(t, err)
+	var records []YearFirst
+	require.NoError(t, json.Unmarshal(data, &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, YearFirst{ScientificName: "Parus major", CommonName: "Great Tit", Year: 2026, FirstDetected: at.Add(time.Minute)}, records[0])
+
+	restarted := &Processor{Settings: settings}
+	restarted.loadYearFirsts(settings)
+	assert.False(t, restarted.IsFirstOfYear("Turdus merula", at.Add(time.Hour)), "year firsts survive a restart")
+	assert.True(t, restarted.IsFirstOfYear("Turdus merula", at.AddDate(1, 0, 0)))
+
+	require.NoError(t, os.WriteFile(sidecar, []byte("not json"), 0o600))
+	restarted.loadYearFirsts(settings)
+	assert.True(t, restarted.IsFirstOfYear("Turdus merula", at.Add(time.Hour)), "an unreadable sidecar starts over")
+}
@@ -0,0 +1,108 @@
+// new_species_notification_exclude_test.go: Tests for per-species opt-out of new species notifications
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/species"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/events"
+)
+
+// detectionEventRecorder collects published detection events.
+type detectionEventRecorder struct {
+	received chan events.DetectionEvent
+}
+
+func (r *detectionEventRecorder) Name() string                         { return "test-detection-recorder" }
+func (r *detectionEventRecorder) ProcessEvent(events.ErrorEvent) error { return nil }
+func (r *detectionEventRecorder) ProcessBatch([]events.ErrorEvent) error {
+	return nil
+}
+func (r *detectionEventRecorder) SupportsBatching() bool { return false }
+func (r *detectionEventRecorder) ProcessDetectionEvent(event events.DetectionEvent) error {
+	r.received <- event
+	return nil
+}
+
+func TestIsNewSpeciesNotificationExcluded(t *testing.T) {
+	t.Parallel()
+
+	exclude := []string{"house sparrow", "Columba livia", "  corvus ", ""}
+
+	assert.True(t, isNewSpeciesNotificationExcluded(exclude, "House Sparrow", "Passer domesticus"), "common name")
+	assert.True(t, isNewSpeciesNotificationExcluded(exclude, "Rock Pigeon", "Columba livia"), "scientific name")
+	assert.True(t, isNewSpeciesNotificationExcluded(exclude, "Common Raven", "Corvus corax"), "genus")
+	assert.False(t, isNewSpeciesNotificationExcluded(exclude, "Snowy Owl", "Bubo scandiacus"))
+	assert.False(t, isNewSpeciesNotificationExcluded(nil, "House Sparrow", "Passer domesticus"))
+}
+
+// Uses the global event bus, so it must not run in parallel with other event tests
+func TestDatabaseAction_ExcludedSpeciesTrackedWithoutNotification(t *testing.T) {
+	events.ResetForTesting()
+	t.Cleanup(events.ResetForTesting)
+
+	eb, err := events.Initialize(nil)
+	require.NoError(t, err)
+	recorder := &detectionEventRecorder{received: make(chan events.DetectionEvent, 4)}
+	require.NoError(t, eb.RegisterConsumer(recorder))
+
+	mockDS := setupMockDatastore(t)
+	trackingSettings := &conf.SpeciesTrackingSettings{
+		Enabled:              true,
+		NewSpeciesWindowDays: 7,
+		SyncIntervalMinutes:  60,
+	}
+	tracker := species.NewTrackerFromSettings(mockDS, trackingSettings)
+	require.NoError(t, tracker.InitFromDatabase())
+
+	settings := &conf.Settings{
+		Realtime: conf.RealtimeSettings{
+			SpeciesTracking: conf.SpeciesTrackingSettings{NotificationExclude: []string{"House Sparrow"}},
+		},
+	}
+	now := time.Now()
+	newAction := func(commonName, scientificName string) *DatabaseAction {
+		return &DatabaseAction{
+			Settings: settings,
+			Ds:       mockDS,
+			Result: detection.Result{
+				Timestamp:  now,
+				BeginTime:  now,
+				Species:    detection.Species{CommonName: commonName, ScientificName: scientificName},
+				Confidence: 0.9,
+				AudioSource: detection.AudioSource{
+					ID: "test-source", SafeString: "test-source", DisplayName: "Test Source",
+				},
+			},
+			EventTracker:      NewEventTracker(0),
+			NewSpeciesTracker: tracker,
+			CorrelationID:     "test-" + scientificName,
+		}
+	}
+
+	require.NoError(t, newAction("House Sparrow", "Passer domesticus").Execute(context.Background(), nil))
+	require.NoError(t, newAction("Snowy Owl", "Bubo scandiacus").Execute(context.Background(), nil))
+
+	// The excluded species still lands in the tracker
+	assert.True(t, tracker.GetSpeciesStatus("Passer domesticus", now).IsNew)
+	assert.True(t, tracker.GetSpeciesStatus("Bubo scandiacus", now).IsNew)
+
+	select {
+	case event := <-recorder.received:
+		assert.Equal(t, "Snowy Owl", event.GetSpeciesName())
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "expected a new species event for the non-excluded species")
+	}
+	select {
+	case event := <-recorder.received:
+		assert.Failf(t, "unexpected event", "excluded species fired an event: %s", event.GetSpeciesName())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
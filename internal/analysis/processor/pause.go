@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// SetDetectionPaused pauses or resumes detection processing. While paused, results from
+// the queue are consumed and dropped: no pending detections are created, nothing is
+// flushed to actions, and no Sound ID events are broadcast. Audio capture and the UI
+// spectrogram are upstream of the processor and keep running.
+//
+// Pausing discards detections that are still pending so nothing stale is finalized on
+// resume. It reports whether the state changed.
+func (p *Processor) SetDetectionPaused(paused bool) bool {
+	if !p.detectionPaused.CompareAndSwap(!paused, paused) {
+		return false
+	}
+
+	if !paused {
+		GetLogger().Info("Detection processing resumed",
+			logger.String("operation", "detection_resume"))
+		return true
+	}
+
+	p.pendingMutex.Lock()
+	discarded := len(p.pendingDetections)
+	clear(p.pendingDetections)
+	p.pendingMutex.Unlock()
+
+	GetLogger().Info("Detection processing paused",
+		logger.Int("discarded_pending", discarded),
+		logger.String("operation", "detection_pause"))
+	return true
+}
+
+// IsDetectionPaused reports whether detection processing is paused.
+func (p *Processor) IsDetectionPaused() bool {
+	return p.detectionPaused.Load()
+}
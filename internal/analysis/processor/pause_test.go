@@ -0,0 +1,59 @@
+// pause_test.go: Tests for pausing detection processing
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+)
+
+func TestSetDetectionPaused_NoEventsWhilePaused(t *testing.T) {
+	p := newEmptyNameTestProcessor("")
+	p.pendingDetections = make(map[string]PendingDetection)
+
+	var broadcasts int
+	p.SetSoundIdSseBroadcaster(func([]birdnet.SoundIdPrediction) error {
+		broadcasts++
+		return nil
+	})
+
+	// A detection already pending when the pause starts is discarded, not finalized later
+	p.processDetections(emptyNameResults())
+	require.Len(t, p.pendingDetections, 1)
+	require.Equal(t, 1, broadcasts)
+
+	require.True(t, p.SetDetectionPaused(true))
+	assert.False(t, p.SetDetectionPaused(true), "pausing twice should not report a change")
+	assert.True(t, p.IsDetectionPaused())
+	assert.Empty(t, p.pendingDetections)
+
+	for range 3 {
+		p.processDetections(emptyNameResults())
+	}
+	assert.Empty(t, p.pendingDetections, "no detections should be held while paused")
+	assert.Equal(t, 1, broadcasts, "no Sound ID events should be emitted while paused")
+
+	pending, flushed := p.flushPendingDetections(1)
+	assert.Zero(t, pending)
+	assert.Zero(t, flushed)
+
+	require.True(t, p.SetDetectionPaused(false))
+	p.processDetections(emptyNameResults())
+	assert.Len(t, p.pendingDetections, 1)
+	assert.Equal(t, 2, broadcasts)
+}
+
+func TestFlushPendingDetections_SkippedWhilePaused(t *testing.T) {
+	p := newEmptyNameTestProcessor("")
+	p.pendingDetections = map[string]PendingDetection{
+		"eurasian blackbird": {Count: 5, FlushDeadline: time.Now().Add(-time.Second)},
+	}
+	p.detectionPaused.Store(true) // Simulate a result that landed after the pause cleared the map
+
+	pending, flushed := p.flushPendingDetections(1)
+	assert.Equal(t, 1, pending)
+	assert.Zero(t, flushed)
+}
@@ -42,6 +42,12 @@ const (
 	speciesHuman = "human"
 )
 
+// Placeholder names for detections kept under conf.EmptyNamePolicyTag
+const (
+	unknownScientificName = "Unknown"
+	unknownCommonName     = "Unknown species"
+)
+
 // DefaultFlushInterval is the interval for checking and flushing pending detections
 const DefaultFlushInterval = 1 * time.Second
 
@@ -64,29 +70,47 @@ type Processor struct {
 	lastSyncAttempt     time.Time               // Last time sync was attempted
 	syncMutex           sync.Mutex              // Mutex to protect sync operations
 	syncInProgress      atomic.Bool             // Flag to prevent overlapping syncs
+	detectionPaused     atomic.Bool             // Drops results instead of finalizing detections while set
 	LastDogDetection    map[string]time.Time    // keep track of dog barks per audio source
 	LastHumanDetection  map[string]time.Time    // keep track of human vocal per audio source
 	Metrics             *observability.Metrics
 	DynamicThresholds   map[string]*DynamicThreshold
 	thresholdsMutex     sync.RWMutex // Mutex to protect access to DynamicThresholds
+	globalThresholdMu   sync.RWMutex // Mutex to protect runtime changes of Settings.BirdNET.Threshold
 	pendingDetections   map[string]PendingDetection
 	pendingMutex        sync.Mutex // Mutex to protect access to pendingDetections
 	lastDogDetectionLog map[string]time.Time
 	dogDetectionMutex   sync.Mutex
 	detectionMutex      sync.RWMutex // Mutex to protect LastDogDetection and LastHumanDetection maps
 	controlChan         chan string
-	JobQueue            *jobqueue.JobQueue // Queue for managing job retries
-	workerCancel        context.CancelFunc // Function to cancel worker goroutines
-	thresholdsCtx       context.Context    // Context for threshold persistence/cleanup goroutines
-	thresholdsCancel    context.CancelFunc // Function to cancel threshold persistence/cleanup goroutines
-	flusherCtx          context.Context    // Context for pending detections flusher goroutine
-	flusherCancel       context.CancelFunc // Function to cancel flusher goroutine
-	preRenderer         PreRendererSubmit  // Spectrogram pre-renderer for background generation
-	preRendererOnce     sync.Once          // Ensures pre-renderer is initialized only once
+	JobQueue            *jobqueue.JobQueue            // Queue for managing job retries
+	workerCancel        context.CancelFunc            // Function to cancel worker goroutines
+	thresholdsCtx       context.Context               // Context for threshold persistence/cleanup goroutines
+	thresholdsCancel    context.CancelFunc            // Function to cancel threshold persistence/cleanup goroutines
+	flusherCtx          context.Context               // Context for pending detections flusher goroutine
+	flusherCancel       context.CancelFunc            // Function to cancel flusher goroutine
+	preRenderer         PreRendererSubmit             // Spectrogram pre-renderer for background generation
+	preRendererOnce     sync.Once                     // Ensures pre-renderer is initialized only once
+	spectrogramCleaner  *spectrogram.RetentionCleaner // Removes saved spectrograms beyond the retention limits, nil when disabled
+	lifeListRefresher   *lifeListRefresher            // Periodic reload of a URL life list, nil when disabled
+	lifeListCancel      context.CancelFunc            // Function to cancel the life list refresh schedule
+	lifeListWatchCancel context.CancelFunc            // Function to stop watching the life list file
+	lifeListWatchWg     sync.WaitGroup                // Tracks the life list file watcher
+	seenToday           dailySpeciesSet               // Species with an approved detection today
+	yearFirsts          yearFirstStore                // First detection of each species in the current year
+	lastHeard           lastHeardStore                // Most recent approved detection of each species
+	lifeListAudit       lifeListAuditLog              // Life list lookups of potential lifers, when enabled
+	newSpeciesAlerts    newSpeciesAlerts              // Species alerted as missing from the life list this session
+	lifeListSuppressed  lifeListSuppressedLog         // Detections raising no new species alert because the species is on the life list
+	digest              *detectionDigest              // Periodic digest of approved detections, nil when disabled
+	digestCancel        context.CancelFunc            // Function to stop the digest schedule
+	announcements       *recentAnnouncements          // New species announced within the dedup window, nil when disabled
+	sinks               detectionSinks                // Destinations approved detections fan out to
+	lifeListPending     lifeListPendingQueue          // Detected species awaiting confirmation before joining the life list, in review mode
 	// SSE related fields
-	SSEBroadcaster      func(note *datastore.Note, birdImage *imageprovider.BirdImage) error // Function to broadcast detection via SSE
-	soundIdSseBroadcaster func([]birdnet.SoundIdPrediction) error                            // Function to broadcast Sound ID via SSE
-	sseBroadcasterMutex sync.RWMutex                                                         // Mutex to protect SSE broadcaster access
+	SSEBroadcaster        func(note *datastore.Note, birdImage *imageprovider.BirdImage) error // Function to broadcast detection via SSE
+	soundIdSseBroadcaster func([]birdnet.SoundIdPrediction) error                              // Function to broadcast Sound ID via SSE
+	sseBroadcasterMutex   sync.RWMutex                                                         // Mutex to protect SSE broadcaster access
 
 	// Backup system fields (optional)
 	backupManager   any // Use interface{} to avoid import cycle
@@ -102,6 +126,7 @@ type Detections struct {
 	pcmData3s     []byte                       // 3s PCM data containing the detection
 	Result        detection.Result             // Detection result containing highest match
 	Results       []detection.AdditionalResult // Additional BirdNET prediction results
+	nameTagged    bool                         // Species names are placeholders set by the empty name policy
 }
 
 // PendingDetection struct represents a single detection held in memory,
@@ -365,6 +390,9 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 	// Initialize log deduplicator with configuration from settings
 	p.logDedup = initLogDeduplicator(settings)
 
+	// Count life list hits and misses of detections
+	initLifeListMetrics(metrics)
+
 	// Validate detection window configuration
 	captureLength := time.Duration(settings.Realtime.Audio.Export.Length) * time.Second
 	preCaptureLength := time.Duration(settings.Realtime.Audio.Export.PreCapture) * time.Second
@@ -386,6 +414,7 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 
 	// Initialize species tracker if enabled
 	p.NewSpeciesTracker = initSpeciesTracker(settings, ds)
+	p.announcements = loadRecentAnnouncements(&settings.Realtime.SpeciesTracking, time.Now())
 
 	// Start the detection processor
 	p.startDetectionProcessor()
@@ -415,16 +444,37 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 	if settings.Realtime.Dashboard.Spectrogram.IsPreRenderEnabled() {
 		p.initPreRenderer()
 	}
-	
+
+	p.startSpectrogramRetention(settings)
+	p.startBigDay(settings)
+
 	if err := loadLifeList(settings); err != nil {
 		GetLogger().Error("Failed to load life list",
 			logger.String("component", "analysis.processor"),
 			logger.Error(err))
 	}
-	
+	p.loadYearFirsts(settings)
+	p.loadLastHeard(settings)
+	p.startLifeListRefresh(settings)
+	p.startLifeListWatch(settings)
+	p.startDetectionDigest(settings)
+	p.registerConfiguredSinks(settings.Realtime.DetectionSinks)
+
 	return p
 }
 
+// startLifeListRefresh schedules periodic reloads when the life list is fetched from a URL.
+func (p *Processor) startLifeListRefresh(settings *conf.Settings) {
+	if !isLifeListURL(settings.SoundId.LifeListPath) || settings.SoundId.LifeListRefreshInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.lifeListRefresher = newLifeListRefresher(settings)
+	p.lifeListCancel = cancel
+	p.lifeListRefresher.start(ctx)
+}
+
 // Start goroutine to process detections from the queue
 func (p *Processor) startDetectionProcessor() {
 	// Add structured logging for detection processor startup
@@ -455,6 +505,10 @@ func (p *Processor) processDetections(item birdnet.Results) {
 		logger.Int64("elapsed_time_ms", item.ElapsedTime.Milliseconds()),
 		logger.String("operation", "process_detections_entry"))
 
+	if p.IsDetectionPaused() {
+		return
+	}
+
 	// Detection window sets wait time before a detection is considered final and is flushed.
 	// This represents the duration to wait from NOW (detection creation time) before flushing,
 	// allowing overlapping analyses to accumulate confirmations for false positive filtering.
@@ -467,16 +521,16 @@ func (p *Processor) processDetections(item birdnet.Results) {
 	// detections are put into pendingDetections map where they are held until flush deadline is reached
 	// once deadline is reached detections are delivered to workers for actions (save to db etc) processing
 	detectionResults, soundIdResults := p.processResults(item)
-	
+
 	if soundIdSseBroadcaster := p.GetSoundIdSseBroadcaster(); soundIdSseBroadcaster != nil {
 		predictions := make([]birdnet.SoundIdPrediction, len(soundIdResults))
 		for i := range soundIdResults {
 			det := soundIdResults[i]
 			predictions[i] = birdnet.SoundIdPrediction{
-				CommonName: det.Result.Species.CommonName,
+				CommonName:     det.Result.Species.CommonName,
 				ScientificName: det.Result.Species.ScientificName,
-				Confidence: det.Result.Confidence,
-				InLifeList: isInLifeList(det.Result.Species.ScientificName),
+				Confidence:     det.Result.Confidence,
+				InLifeList:     isInLifeList(det.Result.Species.ScientificName),
 			}
 		}
 		if err := soundIdSseBroadcaster(predictions); err != nil {
@@ -543,6 +597,11 @@ func (p *Processor) processDetections(item birdnet.Results) {
 		// Unlock the mutex to allow other goroutines to access shared resources
 		p.pendingMutex.Unlock()
 	}
+
+	// A detection opens the live spectrogram when it only shows the audio around detections
+	if len(detectionResults) > 0 {
+		myaudio.TriggerUiSpectrogram(item.Source.ID)
+	}
 }
 
 // processResults processes the results from the BirdNET prediction and returns a list of detections.
@@ -564,7 +623,7 @@ func (p *Processor) processResults(item birdnet.Results) ([]Detections, []Detect
 	// Process each result in item.Results
 	for _, result := range item.Results {
 		// Parse and validate species information
-		scientificName, commonName, speciesCode, speciesLowercase := p.parseAndValidateSpecies(result, item)
+		scientificName, commonName, speciesCode, speciesLowercase, tagged := p.parseAndValidateSpecies(result, item)
 		// Skip if either scientific or common name is missing (partial/invalid parsing)
 		if scientificName == "" || commonName == "" {
 			if p.Settings.Debug {
@@ -597,6 +656,7 @@ func (p *Processor) processResults(item birdnet.Results) ([]Detections, []Detect
 
 		// Create the detection
 		det := p.createDetection(item, result, scientificName, commonName, speciesCode)
+		det.nameTagged = tagged
 		if !shouldSkip {
 			detections = append(detections, det)
 		}
@@ -608,22 +668,32 @@ func (p *Processor) processResults(item birdnet.Results) ([]Detections, []Detect
 	return detections, soundIdDetections
 }
 
-// parseAndValidateSpecies parses species information and validates it
+// parseAndValidateSpecies parses species information and validates it, reporting whether
+// a missing name was replaced by a placeholder
 //
 //nolint:gocritic // hugeParam: Pass by value is intentional - avoids pointer dereferencing in hot path
-func (p *Processor) parseAndValidateSpecies(result datastore.Results, item birdnet.Results) (scientificName, commonName, speciesCode, speciesLowercase string) {
+func (p *Processor) parseAndValidateSpecies(result datastore.Results, item birdnet.Results) (scientificName, commonName, speciesCode, speciesLowercase string, tagged bool) {
 	// Use BirdNET's EnrichResultWithTaxonomy to get species information
 	scientificName, commonName, speciesCode = p.Bn.EnrichResultWithTaxonomy(result.Species)
 
-	// Skip processing if we couldn't parse the species properly (either name missing)
+	// Apply the configured policy if we couldn't parse the species properly (either name missing)
 	if commonName == "" || scientificName == "" {
-		if p.Settings.Debug {
-			GetLogger().Debug("Skipping species with invalid format",
-				logger.String("species", result.Species),
-				logger.Float32("confidence", result.Confidence),
-				logger.String("operation", "species_format_validation"))
+		if p.Settings.SoundId.EmptyNamePolicy != conf.EmptyNamePolicyTag {
+			if p.Settings.Debug {
+				GetLogger().Debug("Skipping species with invalid format",
+					logger.String("species", result.Species),
+					logger.Float32("confidence", result.Confidence),
+					logger.String("operation", "species_format_validation"))
+			}
+			return "", "", "", "", false
 		}
-		return "", "", "", ""
+		scientificName, commonName = tagEmptySpeciesNames(scientificName, commonName)
+		tagged = true
+		GetLogger().Debug("Tagged species with missing name",
+			logger.String("species", result.Species),
+			logger.String("scientific_name", scientificName),
+			logger.String("common_name", commonName),
+			logger.String("operation", "species_format_validation"))
 	}
 
 	// Log placeholder taxonomy codes if using custom model
@@ -646,6 +716,19 @@ func (p *Processor) parseAndValidateSpecies(result datastore.Results, item birdn
 	return
 }
 
+// tagEmptySpeciesNames replaces blank species names with placeholders so a detection
+// kept under the "tag" policy never carries an empty key into life-list lookups or
+// per-species metric labels.
+func tagEmptySpeciesNames(scientificName, commonName string) (taggedScientific, taggedCommon string) {
+	if scientificName == "" {
+		scientificName = unknownScientificName
+	}
+	if commonName == "" {
+		commonName = unknownCommonName
+	}
+	return scientificName, commonName
+}
+
 // shouldFilterDetection checks if a detection should be filtered out
 func (p *Processor) shouldFilterDetection(result datastore.Results, commonName, scientificName, speciesLowercase string, baseThreshold float32, source string) (shouldFilter bool, confidenceThreshold float32) {
 	// Check human detection privacy filter
@@ -695,7 +778,7 @@ func (p *Processor) shouldFilterDetection(result datastore.Results, commonName,
 // shouldFilterDetectionForSoundId checks if a detection should be filtered out
 func (p *Processor) shouldFilterDetectionForSoundId(result datastore.Results) (shouldFilter bool) {
 	confidenceThreshold := float32(p.Settings.SoundId.UnlockedThreshold)
-	
+
 	// Check confidence threshold
 	if result.Confidence < confidenceThreshold {
 		if p.Settings.Debug {
@@ -711,7 +794,7 @@ func (p *Processor) shouldFilterDetectionForSoundId(result datastore.Results) (s
 	if result.Species == "Aves sp._bird sp._bird1" {
 		return false
 	}
-	
+
 	// Check species inclusion filter
 	if !p.Settings.IsSpeciesIncluded(result.Species) {
 		if p.Settings.Debug {
@@ -795,11 +878,11 @@ func (p *Processor) createDetectionResult(
 	audioSource := p.resolveAudioSource(source)
 
 	return detection.Result{
-		Timestamp:      detectionTime,
-		SourceNode:     p.Settings.Main.Name,
-		AudioSource:    audioSource,
-		BeginTime:      beginTime,
-		EndTime:        endTime,
+		Timestamp:   detectionTime,
+		SourceNode:  p.Settings.Main.Name,
+		AudioSource: audioSource,
+		BeginTime:   beginTime,
+		EndTime:     endTime,
 		Species: detection.Species{
 			ScientificName: scientificName,
 			CommonName:     commonName,
@@ -808,7 +891,7 @@ func (p *Processor) createDetectionResult(
 		Confidence:     math.Round(confidence*100) / 100,
 		Latitude:       p.Settings.BirdNET.Latitude,
 		Longitude:      p.Settings.BirdNET.Longitude,
-		Threshold:      p.Settings.BirdNET.Threshold,
+		Threshold:      p.ConfidenceThreshold(),
 		Sensitivity:    p.Settings.BirdNET.Sensitivity,
 		ClipName:       clipName,
 		ProcessingTime: elapsedTime,
@@ -942,7 +1025,7 @@ func (p *Processor) getBaseConfidenceThreshold(commonName, scientificName string
 	}
 
 	// Fall back to global threshold
-	return float32(p.Settings.BirdNET.Threshold)
+	return float32(p.ConfidenceThreshold())
 }
 
 // generateClipName generates a clip name for the given scientific name and confidence.
@@ -1051,8 +1134,27 @@ func (p *Processor) processApprovedDetection(item *PendingDetection, speciesName
 	// not pending detections that may later be discarded as false positives.
 	// Note: speciesName is already lowercase (from pendingDetections map key)
 	p.LearnFromApprovedDetection(speciesName, item.Detection.Result.Species.ScientificName, confidence)
-
+	// Placeholder names don't identify a species, so they stay out of the life list,
+	// its alerts, the year and last-heard records and the digest
+	tracked := !item.Detection.nameTagged
+	if tracked {
+		p.seenToday.add(item.Detection.Result.Species.ScientificName, item.Detection.Result.Species.CommonName, time.Now())
+		p.recordYearFirst(item.Detection.Result.Species.ScientificName,
+			item.Detection.Result.Species.CommonName, item.FirstDetected)
+		p.recordLastHeard(item.Detection.Result.Species.ScientificName, item.FirstDetected)
+		p.auditLifeListMatch(item.Detection.Result.Species.ScientificName,
+			item.Detection.Result.Species.CommonName, item.FirstDetected)
+		p.addToDetectionDigest(item.Detection.Result.Species.ScientificName,
+			item.Detection.Result.Species.CommonName)
+	}
 	item.Detection.Result.BeginTime = item.FirstDetected
+	p.dispatchToSinks(&item.Detection)
+	if tracked {
+		p.alertNewSpecies(&item.Detection, item.Confidence)
+		p.addDetectedLifeListSpecies(item.Detection.Result.Species.ScientificName,
+			item.Detection.Result.Species.CommonName, item.FirstDetected)
+	}
+
 	actionList := p.getActionsForItem(&item.Detection)
 	for _, action := range actionList {
 		task := &Task{Type: TaskTypeAction, Detection: item.Detection, Action: action}
@@ -1193,6 +1295,11 @@ func (p *Processor) flushPendingDetections(minDetections int) (pendingCount, flu
 
 	pendingCount = len(p.pendingDetections)
 
+	// A result that was mid-processing when the pause started may still land here
+	if p.IsDetectionPaused() {
+		return pendingCount, 0
+	}
+
 	for species := range p.pendingDetections {
 		item := p.pendingDetections[species]
 		if !now.After(item.FlushDeadline) {
@@ -1433,6 +1540,7 @@ func (p *Processor) getDefaultActions(det *Detections) []Action {
 				Result:         det.Result,   // Domain model (single source of truth)
 				BirdImageCache: p.BirdImageCache,
 				RetryConfig:    mqttRetryConfig,
+				Candidates:     detectionCandidates(det.Results, p.Settings.BirdNET.TopK),
 				CorrelationID:  det.CorrelationID,
 			}
 		}
@@ -1700,6 +1808,27 @@ func (p *Processor) Shutdown() error {
 		p.flusherCancel()
 	}
 
+	// Stop the life list refresh schedule; cancelling also aborts an in-flight fetch
+	if p.lifeListCancel != nil {
+		p.lifeListCancel()
+		p.lifeListRefresher.wait()
+	}
+
+	// Stop watching the life list file
+	if p.lifeListWatchCancel != nil {
+		p.lifeListWatchCancel()
+		p.lifeListWatchWg.Wait()
+	}
+
+	// Stop the digest schedule, which sends the digest of the detections collected so far
+	if p.digestCancel != nil {
+		p.digestCancel()
+		p.digest.wait()
+	}
+
+	// Let detection sink deliveries in flight finish; each is bounded by its sink timeout
+	p.sinks.wait()
+
 	// Flush dynamic thresholds to database before shutting down with timeout
 	if p.Settings.Realtime.DynamicThreshold.Enabled {
 		// Use context-based timeout for cleaner cancellation handling
@@ -1735,6 +1864,11 @@ func (p *Processor) Shutdown() error {
 		p.preRenderer.Stop()
 	}
 
+	// Stop the spectrogram retention cleanup
+	if p.spectrogramCleaner != nil {
+		p.spectrogramCleaner.Stop()
+	}
+
 	// Stop the job queue with a timeout
 	if err := p.JobQueue.StopWithTimeout(30 * time.Second); err != nil {
 		GetLogger().Warn("Job queue shutdown timed out",
@@ -1855,6 +1989,24 @@ func generateRandomHex(length int) string {
 	return hex
 }
 
+// startSpectrogramRetention starts removing saved spectrograms beyond the configured count
+// or age limits. Nothing is started when no limit is set.
+func (p *Processor) startSpectrogramRetention(settings *conf.Settings) {
+	cleaner, err := spectrogram.NewRetentionCleaner(settings, nil)
+	if err != nil {
+		GetLogger().Error("Invalid spectrogram retention settings, disabling spectrogram cleanup",
+			logger.Error(err),
+			logger.String("operation", "spectrogram_retention_init"))
+		return
+	}
+	if !cleaner.Enabled() {
+		return
+	}
+
+	cleaner.Start()
+	p.spectrogramCleaner = cleaner
+}
+
 // initPreRenderer initializes the spectrogram pre-renderer if enabled.
 // This is called during processor initialization if spectrogram pre-rendering is enabled in settings.
 func (p *Processor) initPreRenderer() {
@@ -0,0 +1,109 @@
+// recent_announcements.go: persisted short-term memory of announced new species
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// recentAnnouncements remembers which new species were announced within the dedup window
+// and keeps them in a file, so a species announced just before a crash or restart isn't
+// announced again right after it because species tracking hadn't persisted it yet.
+type recentAnnouncements struct {
+	path   string
+	window time.Duration
+
+	mu        sync.Mutex
+	announced map[string]time.Time // Keyed by lowercase scientific name
+}
+
+// loadRecentAnnouncements creates the cache configured in settings and reads the species
+// announced within the window before now from its file. It returns nil when the dedup
+// window is disabled. A missing or unreadable file starts an empty cache.
+func loadRecentAnnouncements(settings *conf.SpeciesTrackingSettings, now time.Time) *recentAnnouncements {
+	if settings.AnnouncementDedupMinutes <= 0 {
+		return nil
+	}
+
+	r := &recentAnnouncements{
+		path:      settings.AnnouncementCachePath,
+		window:    time.Duration(settings.AnnouncementDedupMinutes) * time.Minute,
+		announced: make(map[string]time.Time),
+	}
+	if r.path == "" {
+		return r
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			GetLogger().Warn("Failed to read recent announcements",
+				logger.String("path", r.path),
+				logger.Error(err),
+				logger.String("operation", "recent_announcements_load"))
+		}
+		return r
+	}
+	var stored map[string]time.Time
+	if err := json.Unmarshal(data, &stored); err != nil {
+		GetLogger().Warn("Ignoring malformed recent announcements file",
+			logger.String("path", r.path),
+			logger.Error(err),
+			logger.String("operation", "recent_announcements_load"))
+		return r
+	}
+	for key, at := range stored {
+		if now.Sub(at) < r.window {
+			r.announced[key] = at
+		}
+	}
+	return r
+}
+
+// announcedRecently reports whether scientificName was announced within the window before
+// at. A nil cache never suppresses.
+func (r *recentAnnouncements) announcedRecently(scientificName string, at time.Time) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last, ok := r.announced[strings.ToLower(scientificName)]
+	return ok && at.Sub(last) < r.window
+}
+
+// record remembers that scientificName was announced at the given time and writes the cache
+// file right away, since the point is to survive a crash that follows the announcement.
+// Expired entries are dropped. Write failures are logged.
+func (r *recentAnnouncements) record(scientificName string, at time.Time) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.announced[strings.ToLower(scientificName)] = at
+	for key, last := range r.announced {
+		if at.Sub(last) >= r.window {
+			delete(r.announced, key)
+		}
+	}
+
+	if r.path == "" {
+		return
+	}
+	if err := writeJSONFile(r.path, r.announced); err != nil {
+		GetLogger().Warn("Failed to persist recent announcements",
+			logger.String("path", r.path),
+			logger.Error(err),
+			logger.String("operation", "recent_announcements_persist"))
+	}
+}
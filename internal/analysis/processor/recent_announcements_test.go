@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestRecentAnnouncements_SurviveRestartWithinWindow(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.SpeciesTrackingSettings{
+		AnnouncementDedupMinutes: 60,
+		AnnouncementCachePath:    filepath.Join(t.TempDir(), "recent_announcements.json"),
+	}
+	announced := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	before := loadRecentAnnouncements(settings, announced)
+	require.NotNil(t, before)
+	assert.False(t, before.announcedRecently("Setophaga kirtlandii", announced))
+	before.record("Setophaga kirtlandii", announced)
+
+	// The process restarts shortly after the announcement
+	restarted := announced.Add(5 * time.Minute)
+	after := loadRecentAnnouncements(settings, restarted)
+	assert.True(t, after.announcedRecently("setophaga kirtlandii", restarted),
+		"a species announced before the restart must not fire again within the window")
+	assert.False(t, after.announcedRecently("Strix varia", restarted))
+	assert.False(t, after.announcedRecently("Setophaga kirtlandii", announced.Add(time.Hour)),
+		"the species can be announced again once the window has passed")
+
+	// Entries outside the window are dropped on load
+	assert.False(t, loadRecentAnnouncements(settings, announced.Add(2*time.Hour)).announcedRecently("Setophaga kirtlandii", announced.Add(2*time.Hour)))
+}
+
+func TestRecentAnnouncements_DisabledOrUnreadable(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, loadRecentAnnouncements(&conf.SpeciesTrackingSettings{}, time.Now()), "a zero window disables the cache")
+	var disabled *recentAnnouncements
+	disabled.record("Strix varia", time.Now())
+	assert.False(t, disabled.announcedRecently("Strix varia", time.Now()))
+
+	path := filepath.Join(t.TempDir(), "recent_announcements.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+	cache := loadRecentAnnouncements(&conf.SpeciesTrackingSettings{AnnouncementDedupMinutes: 10, AnnouncementCachePath: path}, time.Now())
+	require.NotNil(t, cache, "a malformed file starts an empty cache")
+	assert.False(t, cache.announcedRecently("Strix varia", time.Now()))
+}
@@ -508,6 +508,22 @@ func startAudioCapture(wg *sync.WaitGroup, settings *conf.Settings, quitChan, re
 	go func() {
 		defer audioDemuxManager.Done()
 
+		// stop is closed on either exit signal, ending a blocking spectrogram send
+		exited := make(chan struct{})
+		defer close(exited)
+		stop := make(chan struct{})
+		go func() {
+			defer close(stop)
+			select {
+			case <-doneChan:
+			case <-quitChan:
+			case <-exited:
+			}
+		}()
+
+		// Sources that stop producing audio are reported on the spectrogram stream
+		go myaudio.MonitorUiSpectrogramDropouts(stop, &conf.Setting().SoundId.UiSpectrogram)
+
 		// Convert unified audio data back to separate channels for existing handlers
 		for {
 			select {
@@ -534,15 +550,12 @@ func startAudioCapture(wg *sync.WaitGroup, settings *conf.Settings, quitChan, re
 					// Channel full, drop data
 				}
 				
-				select {
-				case <-doneChan:
-					return
-				case <-quitChan:
-					return
-				case spectrogramChan <- unifiedData.SpectrogramData:
-				default:
-					// Channel full, drop data
-				}				
+				// The configured strategy decides what happens when the channel is full
+				uiSettings := &conf.Setting().SoundId.UiSpectrogram
+				myaudio.ObserveUiSpectrogramFrame(&unifiedData.SpectrogramData, uiSettings)
+				for _, frame := range myaudio.GateUiSpectrogramFrame(&unifiedData.SpectrogramData, uiSettings) {
+					sendUiSpectrogramFrame(spectrogramChan, &frame, uiSettings, stop)
+				}
 
 				// Send sound level data to existing sound level channel if present
 				if unifiedData.SoundLevel != nil {
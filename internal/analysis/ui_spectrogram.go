@@ -3,42 +3,155 @@ package analysis
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	apiv2 "github.com/tphakala/birdnet-go/internal/api/v2"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
 )
 
-// startUiSpectrogramPublishers starts all UI spectrogram publishers with the given done channel
-func startUiSpectrogramPublishers(wg *sync.WaitGroup, doneChan chan struct{}, proc *processor.Processor, spectrogramChan chan myaudio.UiSpectrogramData, apiController *apiv2.Controller) {
-	// Create a merged quit channel that responds to both the done channel and global quit
+// startUiSpectrogramPublishers starts all UI spectrogram publishers with the given done channel,
+// counting published frames in stats, keeping the latest in recent, offering them to frameRecorder,
+// if given, and logging to log, or to the package logger when it is nil. No frames are broadcast while paused is set, nor frames handoff
+// saw broadcast before a restart, nor for a while after consecutive broadcasts failed. The SSE
+// publisher serves flushRequests as its loop does. ready is closed once the SSE publisher
+// consumes frames, or right away when there is no API to publish to, in which case nothing is
+// started. Every goroutine started is tracked by wg and returns once doneChan is closed or ctx is done.
+func startUiSpectrogramPublishers(wg *sync.WaitGroup, ctx context.Context, doneChan chan struct{}, proc *processor.Processor, spectrogramChan chan myaudio.UiSpectrogramData, apiController *apiv2.Controller, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, paused *atomic.Bool, handoff *uiSpectrogramHandoff, recent *uiSpectrogramFrameRing, frameRecorder *uiSpectrogramFrameRecorder, audioMetrics *metrics.MyAudioMetrics, ready chan struct{}, flushRequests <-chan chan struct{}, shutdownErrs *uiSpectrogramShutdownErrors, log logger.Logger) {
+	if log == nil {
+		log = GetLogger()
+	}
+	// Without an API there is nothing to publish to, nor anyone to wait for the quit channel
+	if apiController == nil {
+		if ready != nil {
+			close(ready)
+		}
+		return
+	}
+
+	// Create a merged quit channel that responds to both the done channel and the caller's context
 	mergedQuitChan := make(chan struct{})
-	go func() {
-		<-doneChan
+	wg.Go(func() {
+		select {
+		case <-doneChan:
+		case <-ctx.Done():
+		}
 		close(mergedQuitChan)
-	}()
+		myaudio.SetUiSpectrogramSourceStatusHandler(nil)
+	})
 
-	// Start SSE publisher if API is available
-	if apiController != nil {
-		startUiSpectrogramSSEPublisherWithDone(wg, mergedQuitChan, apiController, spectrogramChan)
+	// Start SSE publisher, feeding the MQTT summary publisher and video recorder when enabled.
+	// Source stall and resume events go to the same stream until done.
+	myaudio.SetUiSpectrogramSourceStatusHandler(apiController.BroadcastSpectrogramSourceStatus)
+	settings := conf.Setting()
+	filters := newUiSpectrogramFilters(&settings.SoundId.UiSpectrogram, stats, log)
+	mqttPublisher := startUiSpectrogramMQTTPublisher(wg, mergedQuitChan, proc, settings, log)
+	skipper := newUiSpectrogramFrameSkipper(&settings.SoundId.UiSpectrogram, log)
+	batcher := newUiSpectrogramBatcher(&settings.SoundId.UiSpectrogram)
+	collapser := newUiSpectrogramFrameCollapser(&settings.SoundId.UiSpectrogram)
+	throttler := newUiSpectrogramFrameThrottler(&settings.SoundId.UiSpectrogram)
+	latest := newUiSpectrogramLatestSlot(&settings.SoundId.UiSpectrogram)
+	heartbeatInterval := time.Duration(settings.SoundId.UiSpectrogram.HeartbeatInterval) * time.Second
+	var drainTimeout time.Duration
+	if settings.SoundId.UiSpectrogram.DrainOnStop {
+		drainTimeout = uiSpectrogramDrainTimeout
+	}
+	videoRecorder := startUiSpectrogramVideoRecorder(wg, mergedQuitChan, settings, shutdownErrs, log)
+	broadcaster := &pausableSpectrogramBroadcaster{
+		uiSpectrogramBroadcaster: newBackoffSpectrogramBroadcaster(&handoffSpectrogramBroadcaster{uiSpectrogramBroadcaster: apiController, handoff: handoff}, audioMetrics, log),
+		paused:                   paused,
 	}
+	startUiSpectrogramSSEPublisherWithDone(wg, ctx, mergedQuitChan, broadcaster, spectrogramChan, filters, supervisor, stats, mqttPublisher, videoRecorder, frameRecorder, skipper, batcher, collapser, throttler, latest, heartbeatInterval, drainTimeout, recent, audioMetrics, ready, flushRequests, log)
 }
 
 // startUiSpectrogramSSEPublisherWithDone starts SSE publisher with a custom done channel
-// This is a compatibility wrapper that converts done channel to context for the refactored function
-func startUiSpectrogramSSEPublisherWithDone(wg *sync.WaitGroup, doneChan chan struct{}, apiController *apiv2.Controller, spectrogramChan chan myaudio.UiSpectrogramData) {
-	// Create context that gets canceled when done channel is closed
-	ctx, cancel := context.WithCancel(context.Background())
+// This is a compatibility wrapper that converts done channel to a context derived from parent
+func startUiSpectrogramSSEPublisherWithDone(wg *sync.WaitGroup, parent context.Context, doneChan chan struct{}, broadcaster uiSpectrogramBroadcaster, spectrogramChan chan myaudio.UiSpectrogramData, filters []uiSpectrogramFilter, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, mqttPublisher *uiSpectrogramMQTTPublisher, videoRecorder *uiSpectrogramVideoRecorder, frameRecorder *uiSpectrogramFrameRecorder, skipper *uiSpectrogramFrameSkipper, batcher *uiSpectrogramBatcher, collapser *uiSpectrogramFrameCollapser, throttler *uiSpectrogramFrameThrottler, latest *uiSpectrogramLatestSlot, heartbeatInterval, drainTimeout time.Duration, recent *uiSpectrogramFrameRing, audioMetrics *metrics.MyAudioMetrics, ready chan struct{}, flushRequests <-chan chan struct{}, log logger.Logger) {
+	// Create context that gets canceled when done channel is closed or parent is cancelled
+	ctx, cancel := context.WithCancelCause(parent)
 
-	// Convert done channel to context cancellation
-	go func() {
+	// Convert done channel to context cancellation, with a cause the publisher logs as a stop.
+	// Tracked by wg like the publisher, it returns as soon as either ends the session.
+	wg.Go(func() {
 		select {
 		case <-doneChan:
-			cancel()
+			cancel(errUiSpectrogramSessionDone)
 		case <-ctx.Done():
+			cancel(nil) // Releases the context; the parent's cause is kept
 		}
-	}()
+	})
 
 	// Call the refactored function with context and receive-only channel
-	startUiSpectrogramSSEPublisher(wg, ctx, apiController, spectrogramChan)
+	startUiSpectrogramSSEPublisher(wg, ctx, broadcaster, spectrogramChan, filters, supervisor, stats, mqttPublisher, videoRecorder, frameRecorder, skipper, batcher, collapser, throttler, latest, heartbeatInterval, drainTimeout, recent, audioMetrics, ready, flushRequests, log)
+}
+
+// pausableSpectrogramBroadcaster reports no clients while paused is set, which makes the
+// publisher drain frames without broadcasting them, as it does when nobody is watching.
+type pausableSpectrogramBroadcaster struct {
+	uiSpectrogramBroadcaster
+	paused *atomic.Bool
+}
+
+// SpectrogramClientCount returns zero while paused and the clients of the wrapped broadcaster
+// otherwise.
+func (b *pausableSpectrogramBroadcaster) SpectrogramClientCount() int {
+	if b.paused != nil && b.paused.Load() {
+		return 0
+	}
+	return b.uiSpectrogramBroadcaster.SpectrogramClientCount()
+}
+
+// startUiSpectrogramVideoRecorder starts the video recorder when enabled and stops it, finishing
+// the current file, when quitChan is closed. It returns nil when recording is disabled or
+// can't start, which offer treats as a no-op. An error finishing the file goes to shutdownErrs.
+func startUiSpectrogramVideoRecorder(wg *sync.WaitGroup, quitChan <-chan struct{}, settings *conf.Settings, shutdownErrs *uiSpectrogramShutdownErrors, log logger.Logger) *uiSpectrogramVideoRecorder {
+	recorder, err := newUiSpectrogramVideoRecorder(&settings.SoundId.UiSpectrogram.Video, settings.Realtime.Audio.FfmpegPath, log)
+	if err != nil {
+		log.Warn("UI spectrogram video recording disabled", logger.Error(err))
+		return nil
+	}
+	if recorder == nil {
+		return nil
+	}
+
+	recorder.Start()
+	wg.Go(func() {
+		<-quitChan
+		if err := recorder.Stop(); err != nil {
+			log.Warn("UI spectrogram video recorder stopped with an error", logger.Error(err))
+			shutdownErrs.add(err)
+		}
+	})
+	return recorder
+}
+
+// uiSpectrogramShutdownErrors collects the errors the publishers of one session stop with.
+// Publishers that outlive a shutdown timeout may still add theirs while Stop reads them.
+type uiSpectrogramShutdownErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// add records err; nil errors and a nil collector are ignored
+func (s *uiSpectrogramShutdownErrors) add(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs = append(s.errs, err)
+}
+
+// all returns the errors recorded so far
+func (s *uiSpectrogramShutdownErrors) all() []error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]error(nil), s.errs...)
 }
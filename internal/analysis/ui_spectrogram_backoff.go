@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// Backoff policy of broadcasting after consecutive failed broadcasts
+const (
+	uiSpectrogramBroadcastBackoffFailures = 5                      // Consecutive failures that pause broadcasting
+	uiSpectrogramBroadcastBackoff         = 100 * time.Millisecond // First pause, doubled for each failure after it
+	uiSpectrogramBroadcastMaxBackoff      = 10 * time.Second
+)
+
+// backoffSpectrogramBroadcaster pauses broadcasting after consecutive broadcasts fail, such as
+// while the API controller restarts, rather than failing every frame in a hot loop. While
+// paused it reports no clients, which makes the publisher drain frames without broadcasting
+// them, as it does when nobody is watching. Each failure after a pause doubles the next one up
+// to maxBackoff, and the first success resumes broadcasting right away. Failures marked as not
+// retryable are about the frame rather than the controller and don't count. It is safe for
+// concurrent use, as the latest slot broadcasts from a goroutine of its own.
+type backoffSpectrogramBroadcaster struct {
+	uiSpectrogramBroadcaster
+	failures       int           // Consecutive failures that pause broadcasting
+	initialBackoff time.Duration // First pause
+	maxBackoff     time.Duration
+	now            func() time.Time
+	audioMetrics   *metrics.MyAudioMetrics // Records the time spent paused, if given
+	log            logger.Logger
+
+	mu          sync.Mutex
+	consecutive int           // Failures since the last success
+	backoff     time.Duration // Length of the next pause
+	until       time.Time     // When the current pause ends
+}
+
+// newBackoffSpectrogramBroadcaster wraps broadcaster with the default backoff policy, logging
+// to log, or to the package logger when it is nil.
+func newBackoffSpectrogramBroadcaster(broadcaster uiSpectrogramBroadcaster, audioMetrics *metrics.MyAudioMetrics, log logger.Logger) *backoffSpectrogramBroadcaster {
+	if log == nil {
+		log = GetLogger()
+	}
+	return &backoffSpectrogramBroadcaster{
+		uiSpectrogramBroadcaster: broadcaster,
+		failures:                 uiSpectrogramBroadcastBackoffFailures,
+		initialBackoff:           uiSpectrogramBroadcastBackoff,
+		maxBackoff:               uiSpectrogramBroadcastMaxBackoff,
+		now:                      time.Now,
+		audioMetrics:             audioMetrics,
+		log:                      log,
+		backoff:                  uiSpectrogramBroadcastBackoff,
+	}
+}
+
+// BroadcastSpectrogram broadcasts frame, recording whether it failed.
+func (b *backoffSpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	err := b.uiSpectrogramBroadcaster.BroadcastSpectrogram(frame)
+	b.observe(err)
+	return err
+}
+
+// BroadcastSpectrogramBatch broadcasts frames as one batch, recording whether it failed.
+func (b *backoffSpectrogramBroadcaster) BroadcastSpectrogramBatch(frames []*myaudio.UiSpectrogramData) error {
+	err := b.uiSpectrogramBroadcaster.BroadcastSpectrogramBatch(frames)
+	b.observe(err)
+	return err
+}
+
+// SpectrogramClientCount returns zero while broadcasting is paused and the clients of the
+// wrapped broadcaster otherwise.
+func (b *backoffSpectrogramBroadcaster) SpectrogramClientCount() int {
+	if b.pausing() {
+		return 0
+	}
+	return b.uiSpectrogramBroadcaster.SpectrogramClientCount()
+}
+
+// pausing reports whether broadcasting is paused
+func (b *backoffSpectrogramBroadcaster) pausing() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.now().Before(b.until)
+}
+
+// observe records the result of one broadcast, pausing broadcasting once enough failed in a row
+func (b *backoffSpectrogramBroadcaster) observe(err error) {
+	if errors.IsPermanent(err) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		if b.consecutive >= b.failures {
+			b.log.Info("UI spectrogram broadcasts recovered, resuming broadcasting",
+				logger.Int("failures", b.consecutive))
+		}
+		b.consecutive = 0
+		b.backoff = b.initialBackoff
+		return
+	}
+
+	b.consecutive++
+	if b.consecutive < b.failures {
+		return
+	}
+	pause := b.backoff
+	b.until = b.now().Add(pause)
+	b.backoff = min(2*b.backoff, b.maxBackoff)
+	if b.audioMetrics != nil {
+		b.audioMetrics.RecordUiSpectrogramBroadcastBackoff(pause.Seconds())
+	}
+	b.log.Warn("UI spectrogram broadcasts failing, pausing broadcasting",
+		logger.Error(err),
+		logger.Int("failures", b.consecutive),
+		logger.Duration("backoff", pause))
+}
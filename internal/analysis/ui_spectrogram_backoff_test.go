@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// flakySpectrogramBroadcaster has one client and fails its first failures broadcasts.
+type flakySpectrogramBroadcaster struct {
+	*fakeSpectrogramBroadcaster
+	failures int
+	attempts int
+}
+
+func (f *flakySpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return errors.NewStd("controller restarting")
+	}
+	return f.fakeSpectrogramBroadcaster.BroadcastSpectrogram(frame)
+}
+
+func TestBackoffSpectrogramBroadcaster_PausesAfterConsecutiveFailures(t *testing.T) {
+	inner := &flakySpectrogramBroadcaster{fakeSpectrogramBroadcaster: &fakeSpectrogramBroadcaster{}, failures: 6}
+	inner.clients.Store(1)
+	registry := prometheus.NewRegistry()
+	audioMetrics, err := metrics.NewMyAudioMetrics(registry)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	broadcaster := newBackoffSpectrogramBroadcaster(inner, audioMetrics, GetLogger())
+	broadcaster.failures = 3
+	broadcaster.initialBackoff, broadcaster.backoff = time.Second, time.Second
+	broadcaster.maxBackoff = 4 * time.Second
+	broadcaster.now = func() time.Time { return now }
+
+	var stats uiSpectrogramPublishStats
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	publish := func(source string) {
+		frame := myaudio.UiSpectrogramData{Source: source}
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &stats, nil, nil, nil, nil, nil, nil, nil, nil, nil, errorLog, GetLogger())
+	}
+
+	for range 3 {
+		publish("failing")
+	}
+	publish("paused")
+	publish("paused")
+	assert.Equal(t, 3, inner.attempts, "frames are dropped once enough broadcasts failed in a row")
+	assert.Zero(t, broadcaster.SpectrogramClientCount())
+
+	// Each failure after a pause doubles the next one, up to the cap
+	for _, pause := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		now = now.Add(pause - time.Millisecond)
+		publish("paused")
+		now = now.Add(time.Millisecond)
+		publish("failing")
+	}
+	assert.Equal(t, 6, inner.attempts)
+
+	now = now.Add(4 * time.Second)
+	publish("recovered")
+	publish("recovered")
+	assert.Equal(t, []string{"recovered", "recovered"}, inner.broadcast(), "the first success resumes broadcasting")
+	assert.Equal(t, 1, broadcaster.SpectrogramClientCount())
+
+	inner.failures = inner.attempts + 1
+	publish("failing")
+	publish("after")
+	assert.Equal(t, []string{"recovered", "recovered", "after"}, inner.broadcast(), "a success starts counting failures over")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var paused float64
+	for _, family := range families {
+		if family.GetName() == "spectrogram_broadcast_backoff_seconds_total" {
+			paused = family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	assert.InDelta(t, (1 + 2 + 4 + 4), paused, 0, "the time spent paused is recorded")
+}
@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	"math"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// defaultUiSpectrogramCollapseKeepalive is how often an unchanged frame is sent anyway when
+// no keepalive is configured
+const defaultUiSpectrogramCollapseKeepalive = 5 * time.Second
+
+// uiSpectrogramFrameCollapser skips broadcasting frames that look the same as the previous
+// frame of their source, which during long silences saves sending identical background
+// frames. Frames are compared by the mean level of each column, which is cheap and tells a
+// quiet frame from one with any sound in it. It is used by a single publisher goroutine and
+// isn't safe for concurrent use.
+type uiSpectrogramFrameCollapser struct {
+	tolerance float64       // Largest difference of a column's mean level for frames to count as the same
+	keepalive time.Duration // Longest time a source goes without a broadcast frame
+	now       func() time.Time
+	last      map[string]uiSpectrogramFrameSignature // Last broadcast frame of each source
+}
+
+// uiSpectrogramFrameSignature summarizes a broadcast frame for comparing later ones to it
+type uiSpectrogramFrameSignature struct {
+	levels []float64 // Mean level of each column
+	sent   time.Time
+}
+
+// newUiSpectrogramFrameCollapser returns a collapser when collapsing silent frames is
+// enabled, nil otherwise.
+func newUiSpectrogramFrameCollapser(settings *conf.UiSpectrogramSettings) *uiSpectrogramFrameCollapser {
+	if !settings.CollapseFrames {
+		return nil
+	}
+	keepalive := time.Duration(settings.CollapseKeepalive) * time.Second
+	if keepalive <= 0 {
+		keepalive = defaultUiSpectrogramCollapseKeepalive
+	}
+	return &uiSpectrogramFrameCollapser{
+		tolerance: float64(max(settings.CollapseTolerance, 0)),
+		keepalive: keepalive,
+		now:       time.Now,
+		last:      make(map[string]uiSpectrogramFrameSignature),
+	}
+}
+
+// collapse reports whether frame should be skipped because it matches the last broadcast
+// frame of its source, which was sent less than the keepalive ago. Frames that aren't
+// skipped become the reference for the next ones. A nil collapser skips nothing.
+func (c *uiSpectrogramFrameCollapser) collapse(frame *myaudio.UiSpectrogramData) bool {
+	if c == nil {
+		return false
+	}
+	levels := uiSpectrogramColumnLevels(frame)
+	now := c.now()
+	// Skipped frames leave the reference alone, so a slow drift is sent once it adds up
+	if last, ok := c.last[frame.Source]; ok && now.Sub(last.sent) < c.keepalive && c.similar(last.levels, levels) {
+		return true
+	}
+	c.last[frame.Source] = uiSpectrogramFrameSignature{levels: levels, sent: now}
+	return false
+}
+
+// reset forgets the previous frames, so the next frame of each source is broadcast. The
+// publisher calls it while nobody watches, so a client connecting after a silence gets a
+// frame right away. A nil collapser ignores it.
+func (c *uiSpectrogramFrameCollapser) reset() {
+	if c == nil || len(c.last) == 0 {
+		return
+	}
+	clear(c.last)
+}
+
+// similar reports whether two frames' column levels differ by no more than the tolerance.
+// Frames of different lengths are never similar.
+func (c *uiSpectrogramFrameCollapser) similar(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > c.tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// uiSpectrogramColumnLevels returns the mean bin value of each column of a frame
+func uiSpectrogramColumnLevels(frame *myaudio.UiSpectrogramData) []float64 {
+	bins := frame.ColumnBins()
+	if bins <= 0 {
+		return nil
+	}
+	columns := len(frame.Spectrogram) / bins
+	levels := make([]float64, columns)
+	for col := range columns {
+		sum := 0
+		for _, v := range frame.Spectrogram[col*bins : (col+1)*bins] {
+			sum += int(v)
+		}
+		levels[col] = float64(sum) / float64(bins)
+	}
+	return levels
+}
@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// collapseTestFrame returns a four-column frame of source with every bin at level
+func collapseTestFrame(source string, level byte) myaudio.UiSpectrogramData {
+	spectrogram := make([]byte, 4*myaudio.UiSpectrogramBins)
+	for i := range spectrogram {
+		spectrogram[i] = level
+	}
+	return myaudio.UiSpectrogramData{Source: source, Spectrogram: spectrogram}
+}
+
+// newTestCollapser returns a collapser reading its time from now
+func newTestCollapser(t *testing.T, tolerance, keepalive int, now *time.Time) *uiSpectrogramFrameCollapser {
+	t.Helper()
+	collapser := newUiSpectrogramFrameCollapser(&conf.UiSpectrogramSettings{
+		CollapseFrames:    true,
+		CollapseTolerance: tolerance,
+		CollapseKeepalive: keepalive,
+	})
+	require.NotNil(t, collapser)
+	collapser.now = func() time.Time { return *now }
+	return collapser
+}
+
+func TestNewUiSpectrogramFrameCollapser_Disabled(t *testing.T) {
+	assert.Nil(t, newUiSpectrogramFrameCollapser(&conf.UiSpectrogramSettings{CollapseTolerance: 4}))
+	assert.False(t, (*uiSpectrogramFrameCollapser)(nil).collapse(&myaudio.UiSpectrogramData{}),
+		"a nil collapser skips nothing")
+}
+
+func TestPublishUiSpectrogramFrame_CollapsesIdenticalFrames(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	var stats uiSpectrogramPublishStats
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	collapser := newTestCollapser(t, 0, 5, &now)
+	publish := func(source string) {
+		frame := collapseTestFrame(source, 10)
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &stats, nil, nil, nil, nil, nil, collapser, nil, nil, nil, errorLog, GetLogger())
+	}
+
+	for range 4 {
+		publish("mic")
+		now = now.Add(time.Second)
+	}
+	assert.Equal(t, []string{"mic"}, broadcaster.broadcast(), "repeats of the first frame are skipped")
+
+	publish("other")
+	assert.Equal(t, []string{"mic", "other"}, broadcaster.broadcast(), "each source is compared with its own frames")
+
+	now = now.Add(time.Second)
+	publish("mic")
+	assert.Equal(t, []string{"mic", "other", "mic"}, broadcaster.broadcast(),
+		"an unchanged frame is sent once the keepalive passed")
+
+	snapshot := stats.snapshot()
+	assert.Equal(t, uint64(3), snapshot.FramesBroadcast)
+	assert.Equal(t, uint64(3), snapshot.FramesCollapsed)
+}
+
+func TestUiSpectrogramFrameCollapser_NearSilentThenLoud(t *testing.T) {
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	collapser := newTestCollapser(t, 2, 5, &now)
+
+	silent := collapseTestFrame("mic", 10)
+	require.False(t, collapser.collapse(&silent), "the first frame is always sent")
+
+	// Background noise within the tolerance counts as the same silence
+	for _, level := range []byte{11, 9, 12, 8} {
+		noise := collapseTestFrame("mic", level)
+		assert.True(t, collapser.collapse(&noise), "level %d is within the tolerance", level)
+	}
+
+	loud := collapseTestFrame("mic", 10)
+	for i := range myaudio.UiSpectrogramBins / 4 {
+		loud.Spectrogram[2*myaudio.UiSpectrogramBins+i] = 200 // A call in the third column
+	}
+	assert.False(t, collapser.collapse(&loud), "a frame with sound in it is sent")
+
+	again := collapseTestFrame("mic", 10)
+	assert.False(t, collapser.collapse(&again), "silence after the call is sent once")
+	assert.True(t, collapser.collapse(&again))
+
+	collapser.reset()
+	assert.False(t, collapser.collapse(&again), "a reset collapser sends the next frame")
+}
@@ -0,0 +1,373 @@
+package analysis
+
+import (
+	"encoding/base64"
+	"math"
+	"slices"
+	"sync/atomic"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// defaultDifferenceAdaptRate is used when the configured adaptation rate is outside (0, 1].
+	defaultDifferenceAdaptRate = 0.02
+
+	// defaultMaxFrameBytes caps the encoded frame size when none is configured. A normal
+	// two-column frame encodes to under 1 KiB, so this only trips on misconfiguration.
+	defaultMaxFrameBytes = 64 * 1024
+
+	// minDownResolvedBins is how far bins are reduced before columns are merged instead
+	minDownResolvedBins = 64
+
+	// frameCapLogInterval logs every Nth down-resolved frame to avoid log spam
+	frameCapLogInterval = 100
+)
+
+// uiSpectrogramFilter transforms a UI spectrogram frame in place before it is broadcast.
+// Filters may keep state across frames and are only used from the publisher goroutine.
+type uiSpectrogramFilter interface {
+	Apply(data *myaudio.UiSpectrogramData)
+}
+
+// newUiSpectrogramFilters builds the filter chain for the configured UI spectrogram settings,
+// counting down-resolved frames in stats, if given, and logging to the given session logger.
+func newUiSpectrogramFilters(settings *conf.UiSpectrogramSettings, stats *uiSpectrogramPublishStats, log logger.Logger) []uiSpectrogramFilter {
+	var filters []uiSpectrogramFilter
+
+	// Cropping runs first so the other filters only work on the band that is broadcast
+	if settings.SpectrogramMinFreqHz > 0 || settings.SpectrogramMaxFreqHz > 0 {
+		filters = append(filters, newSpectrogramFrequencyBandFilter(settings.SpectrogramMinFreqHz, settings.SpectrogramMaxFreqHz))
+	}
+	// Aggregation runs next so the other filters work on the display resolution
+	if settings.BinAggregation > 1 {
+		filters = append(filters, newSpectrogramBinAggregationFilter(settings.BinAggregation, settings.BinAggregationMode, log))
+	}
+	if settings.SpectrogramBins > 0 {
+		filters = append(filters, newSpectrogramBinDownsampleFilter(settings.SpectrogramBins))
+	}
+
+	switch settings.Mode {
+	case "", conf.UiSpectrogramModeNormal:
+	case conf.UiSpectrogramModeDifference:
+		filters = append(filters, newSpectrogramDifferenceFilter(settings.DifferenceAdaptRate))
+	default:
+		log.Warn("Unknown UI spectrogram mode, using normal mode",
+			logger.String("mode", settings.Mode))
+	}
+
+	// The size cap runs last so it bounds whatever the other filters produced
+	capFilter := newSpectrogramFrameCapFilter(settings.MaxFrameBytes)
+	capFilter.log = log
+	capFilter.stats = stats
+	filters = append(filters, capFilter)
+
+	return filters
+}
+
+// applyUiSpectrogramFilters runs every filter in order on the frame.
+func applyUiSpectrogramFilters(filters []uiSpectrogramFilter, data *myaudio.UiSpectrogramData) {
+	for _, f := range filters {
+		f.Apply(data)
+	}
+}
+
+// spectrogramBinAggregationFilter combines each group of adjacent bins into one display bin.
+// High-sample-rate sources produce far more frequency detail than a UI canvas can show, and
+// aggregating on the server shrinks every frame instead of leaving the browser to decimate.
+type spectrogramBinAggregationFilter struct {
+	factor int
+	sum    bool
+}
+
+// newSpectrogramBinAggregationFilter creates an aggregation filter merging factor bins into
+// one, combining them by maximum or by saturating sum. Unknown modes fall back to maximum,
+// with a warning logged to log, or to the package logger when it is nil.
+func newSpectrogramBinAggregationFilter(factor int, mode string, log logger.Logger) *spectrogramBinAggregationFilter {
+	switch mode {
+	case "", conf.UiSpectrogramAggregateMax, conf.UiSpectrogramAggregateSum:
+	default:
+		if log == nil {
+			log = GetLogger()
+		}
+		log.Warn("Unknown UI spectrogram bin aggregation mode, using max",
+			logger.String("mode", mode))
+	}
+	return &spectrogramBinAggregationFilter{
+		factor: max(factor, 1),
+		sum:    mode == conf.UiSpectrogramAggregateSum,
+	}
+}
+
+// Apply aggregates every column of the frame and updates the bin count and width to match.
+// A trailing partial group is aggregated on its own.
+func (f *spectrogramBinAggregationFilter) Apply(data *myaudio.UiSpectrogramData) {
+	bins := data.ColumnBins()
+	if f.factor <= 1 || bins <= 1 {
+		return
+	}
+
+	binHz := data.ColumnBinHz()
+	columns := len(data.Spectrogram) / bins
+	newBins := (bins + f.factor - 1) / f.factor
+	merged := make([]byte, columns*newBins)
+	for c := range columns {
+		column := data.Spectrogram[c*bins : (c+1)*bins]
+		for j := range newBins {
+			group := column[j*f.factor : min((j+1)*f.factor, bins)]
+			merged[c*newBins+j] = f.combine(group)
+		}
+	}
+
+	data.Spectrogram = merged
+	data.Bins = newBins
+	data.BinHz = binHz * float64(f.factor)
+}
+
+// combine reduces one group of bins to a single value.
+func (f *spectrogramBinAggregationFilter) combine(group []byte) byte {
+	if !f.sum {
+		return slices.Max(group)
+	}
+	total := 0
+	for _, v := range group {
+		total += int(v)
+	}
+	return byte(min(total, math.MaxUint8))
+}
+
+// spectrogramFrequencyBandFilter crops frames to the bins of a frequency band of interest,
+// such as 2-8 kHz for a species group, which shrinks frames and leaves the client's canvas to
+// the frequencies that matter.
+type spectrogramFrequencyBandFilter struct {
+	minHz float64
+	maxHz float64 // 0 for the top of the frame
+}
+
+// newSpectrogramFrequencyBandFilter creates a filter keeping the bins from minHz to maxHz
+func newSpectrogramFrequencyBandFilter(minHz, maxHz float64) *spectrogramFrequencyBandFilter {
+	return &spectrogramFrequencyBandFilter{minHz: minHz, maxHz: maxHz}
+}
+
+// Apply keeps the bins of every column that overlap the band, clamped to the bins the frame
+// has, and moves the frame's lowest frequency up to the first bin kept. Frames the band
+// doesn't overlap are left unchanged rather than sent empty.
+func (f *spectrogramFrequencyBandFilter) Apply(data *myaudio.UiSpectrogramData) {
+	bins := data.ColumnBins()
+	binHz := data.ColumnBinHz()
+	first := max(int(math.Floor((f.minHz-data.MinHz)/binHz)), 0)
+	last := bins
+	if f.maxHz > 0 {
+		last = min(int(math.Ceil((f.maxHz-data.MinHz)/binHz)), bins)
+	}
+	if first >= last || (first == 0 && last == bins) {
+		return
+	}
+
+	columns := len(data.Spectrogram) / bins
+	newBins := last - first
+	cropped := make([]byte, columns*newBins)
+	for c := range columns {
+		copy(cropped[c*newBins:(c+1)*newBins], data.Spectrogram[c*bins+first:c*bins+last])
+	}
+
+	data.Spectrogram = cropped
+	data.Bins = newBins
+	data.MinHz += float64(first) * binHz
+}
+
+// spectrogramBinDownsampleFilter averages adjacent bins down to a fixed bin count, so
+// clients on slow links get smaller frames covering the same frequency range. Unlike
+// aggregation it targets a bin count rather than a factor, and averages rather than keeping
+// peaks, since it trades detail for size rather than adapting to the display.
+type spectrogramBinDownsampleFilter struct {
+	bins int
+}
+
+// newSpectrogramBinDownsampleFilter creates a filter reducing frames to bins per column
+func newSpectrogramBinDownsampleFilter(bins int) *spectrogramBinDownsampleFilter {
+	return &spectrogramBinDownsampleFilter{bins: bins}
+}
+
+// Apply averages every column of the frame down to the target bin count and widens the bin
+// width to match, leaving frames with no more bins than the target unchanged. Output bin j
+// averages input bins j*bins/target up to (j+1)*bins/target, so groups differ by at most
+// one bin when the counts don't divide.
+func (f *spectrogramBinDownsampleFilter) Apply(data *myaudio.UiSpectrogramData) {
+	bins := data.ColumnBins()
+	if f.bins <= 0 || f.bins >= bins {
+		return
+	}
+
+	binHz := data.ColumnBinHz()
+	columns := len(data.Spectrogram) / bins
+	downsampled := make([]byte, columns*f.bins)
+	for c := range columns {
+		column := data.Spectrogram[c*bins : (c+1)*bins]
+		for j := range f.bins {
+			group := column[j*bins/f.bins : (j+1)*bins/f.bins]
+			total := 0
+			for _, v := range group {
+				total += int(v)
+			}
+			downsampled[c*f.bins+j] = byte((total + len(group)/2) / len(group))
+		}
+	}
+
+	data.Spectrogram = downsampled
+	data.Bins = f.bins
+	data.BinHz = binHz * float64(bins) / float64(f.bins)
+}
+
+// spectrogramDifferenceFilter implements a simple spectral background subtraction: each
+// bin is reduced by a slowly adapting exponential moving average of that bin, so steady
+// background fades out and only deviations from it remain visible. Each source keeps its
+// own baseline, since every microphone hears a different background.
+type spectrogramDifferenceFilter struct {
+	rate      float64
+	baselines map[string][]float64 // Per-bin moving average, by frame source
+}
+
+// newSpectrogramDifferenceFilter creates a difference filter with the given per-column
+// adaptation rate. Higher rates follow the background faster.
+func newSpectrogramDifferenceFilter(rate float64) *spectrogramDifferenceFilter {
+	if rate <= 0 || rate > 1 {
+		rate = defaultDifferenceAdaptRate
+	}
+	return &spectrogramDifferenceFilter{rate: rate, baselines: make(map[string][]float64)}
+}
+
+// Apply subtracts the source's baseline from every column of the frame and then folds the
+// column into it. The source's first column seeds its baseline.
+func (f *spectrogramDifferenceFilter) Apply(data *myaudio.UiSpectrogramData) {
+	bins := data.ColumnBins()
+	baseline := f.baselines[data.Source]
+	for start := 0; start+bins <= len(data.Spectrogram); start += bins {
+		column := data.Spectrogram[start : start+bins]
+
+		if len(baseline) != bins {
+			baseline = make([]float64, bins)
+			for i, v := range column {
+				baseline[i] = float64(v)
+			}
+		}
+
+		for i, v := range column {
+			value := float64(v)
+			column[i] = byte(max(value-baseline[i], 0))
+			baseline[i] += f.rate * (value - baseline[i])
+		}
+	}
+	if baseline != nil {
+		f.baselines[data.Source] = baseline
+	}
+}
+
+// spectrogramFrameCapFilter down-resolves frames whose encoded size exceeds a cap. Bins are
+// merged pairwise first, down to minDownResolvedBins, then columns; merging keeps the
+// louder of each pair so short calls are not averaged away.
+type spectrogramFrameCapFilter struct {
+	maxBytes  int
+	truncated atomic.Uint64
+	stats     *uiSpectrogramPublishStats // Also counts down-resolved frames, nil when not published
+	log       logger.Logger              // Session logger, GetLogger() when nil
+}
+
+// newSpectrogramFrameCapFilter creates a cap filter; non-positive values use the default.
+func newSpectrogramFrameCapFilter(maxBytes int) *spectrogramFrameCapFilter {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxFrameBytes
+	}
+	return &spectrogramFrameCapFilter{maxBytes: maxBytes}
+}
+
+// Truncated returns how many frames have been down-resolved.
+func (f *spectrogramFrameCapFilter) Truncated() uint64 {
+	return f.truncated.Load()
+}
+
+// Apply down-resolves the frame until it fits under the cap.
+func (f *spectrogramFrameCapFilter) Apply(data *myaudio.UiSpectrogramData) {
+	if base64.StdEncoding.EncodedLen(len(data.Spectrogram)) <= f.maxBytes {
+		return
+	}
+
+	originalBytes := len(data.Spectrogram)
+	binHz := data.ColumnBinHz()
+	bins := data.ColumnBins()
+	columns := len(data.Spectrogram) / bins
+	pixels := data.Spectrogram[:columns*bins]
+
+	for base64.StdEncoding.EncodedLen(len(pixels)) > f.maxBytes {
+		switch {
+		case bins > minDownResolvedBins:
+			pixels, bins = mergeSpectrogramBins(pixels, columns, bins)
+			binHz *= 2
+		case columns > 1:
+			pixels, columns = mergeSpectrogramColumns(pixels, columns, bins)
+			data.MsPerColumn *= 2
+		case bins > 1:
+			pixels, bins = mergeSpectrogramBins(pixels, columns, bins)
+			binHz *= 2
+		default:
+			pixels = pixels[:0]
+		}
+	}
+
+	data.Spectrogram = pixels
+	if bins != myaudio.UiSpectrogramBins {
+		data.Bins = bins
+		data.BinHz = binHz
+	}
+
+	count := f.truncated.Add(1)
+	f.stats.truncated()
+	if count == 1 || count%frameCapLogInterval == 0 {
+		log := f.log
+		if log == nil {
+			log = GetLogger()
+		}
+		log.Warn("UI spectrogram frame exceeded size cap and was down-resolved",
+			logger.Int("original_bytes", originalBytes),
+			logger.Int("max_encoded_bytes", f.maxBytes),
+			logger.Int("columns", columns),
+			logger.Int("bins", bins),
+			logger.Uint64("truncated_frames", count))
+	}
+}
+
+// mergeSpectrogramBins halves the bins per column, keeping the louder of each pair.
+func mergeSpectrogramBins(pixels []byte, columns, bins int) (merged []byte, newBins int) {
+	newBins = (bins + 1) / 2
+	merged = make([]byte, columns*newBins)
+	for c := range columns {
+		column := pixels[c*bins : (c+1)*bins]
+		for j := range newBins {
+			v := column[2*j]
+			if 2*j+1 < bins {
+				v = max(v, column[2*j+1])
+			}
+			merged[c*newBins+j] = v
+		}
+	}
+	return merged, newBins
+}
+
+// mergeSpectrogramColumns halves the number of columns, keeping the louder of each pair.
+func mergeSpectrogramColumns(pixels []byte, columns, bins int) (merged []byte, newColumns int) {
+	newColumns = (columns + 1) / 2
+	merged = make([]byte, newColumns*bins)
+	for c := range newColumns {
+		out := merged[c*bins : (c+1)*bins]
+		copy(out, pixels[2*c*bins:(2*c+1)*bins])
+		if 2*c+1 < columns {
+			next := pixels[(2*c+1)*bins : (2*c+2)*bins]
+			for b := range bins {
+				out[b] = max(out[b], next[b])
+			}
+		}
+	}
+	return merged, newColumns
+}
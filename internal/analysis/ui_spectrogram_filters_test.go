@@ -0,0 +1,310 @@
+package analysis
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// spectrogramFrame builds a two-column UI spectrogram frame with the given bins set.
+func spectrogramFrame(bins map[int]byte) myaudio.UiSpectrogramData {
+	data := make([]byte, myaudio.UiSpectrogramBins*2)
+	for col := range 2 {
+		for bin, v := range bins {
+			data[col*myaudio.UiSpectrogramBins+bin] = v
+		}
+	}
+	return myaudio.UiSpectrogramData{Spectrogram: data}
+}
+
+func TestSpectrogramDifferenceFilter_SteadyToneFadesNewToneStandsOut(t *testing.T) {
+	t.Parallel()
+
+	const (
+		steadyBin = 40
+		newBin    = 120
+		level     = 200
+	)
+
+	filter := newSpectrogramDifferenceFilter(0.2)
+
+	// A steady tone fades as the baseline adapts to it
+	var last myaudio.UiSpectrogramData
+	for range 50 {
+		last = spectrogramFrame(map[int]byte{steadyBin: level})
+		filter.Apply(&last)
+	}
+	assert.LessOrEqual(t, last.Spectrogram[steadyBin], byte(1), "steady tone should fade out")
+
+	// A newly-appearing tone stands out while the steady tone stays suppressed
+	frame := spectrogramFrame(map[int]byte{steadyBin: level, newBin: level})
+	filter.Apply(&frame)
+	assert.LessOrEqual(t, frame.Spectrogram[steadyBin], byte(1))
+	assert.Greater(t, frame.Spectrogram[newBin], byte(level/2), "new tone should stand out")
+}
+
+func TestSpectrogramDifferenceFilter_AdaptRate(t *testing.T) {
+	t.Parallel()
+
+	slow := newSpectrogramDifferenceFilter(0.01)
+	fast := newSpectrogramDifferenceFilter(0.5)
+
+	seed := spectrogramFrame(nil)
+	slow.Apply(&seed)
+	seed = spectrogramFrame(nil)
+	fast.Apply(&seed)
+
+	var slowOut, fastOut myaudio.UiSpectrogramData
+	for range 5 {
+		slowOut = spectrogramFrame(map[int]byte{10: 100})
+		slow.Apply(&slowOut)
+		fastOut = spectrogramFrame(map[int]byte{10: 100})
+		fast.Apply(&fastOut)
+	}
+
+	assert.Greater(t, slowOut.Spectrogram[10], fastOut.Spectrogram[10],
+		"a slower adaptation rate should keep a new tone visible longer")
+}
+
+func TestSpectrogramDifferenceFilter_BaselinePerSource(t *testing.T) {
+	t.Parallel()
+
+	const (
+		bin   = 40
+		level = 200
+	)
+
+	filter := newSpectrogramDifferenceFilter(0.2)
+
+	// A steady tone on one source fades into that source's background
+	for range 50 {
+		frame := spectrogramFrame(map[int]byte{bin: level})
+		frame.Source = "garden"
+		filter.Apply(&frame)
+	}
+
+	// A quiet source seeds its own baseline, so the first source's tone still stands out
+	// when it appears there
+	quiet := spectrogramFrame(nil)
+	quiet.Source = "pond"
+	filter.Apply(&quiet)
+	frame := spectrogramFrame(map[int]byte{bin: level})
+	frame.Source = "pond"
+	filter.Apply(&frame)
+	assert.Greater(t, frame.Spectrogram[myaudio.UiSpectrogramBins+bin], byte(level/2),
+		"a tone new to a source is not hidden by another source's background")
+
+	garden := spectrogramFrame(map[int]byte{bin: level})
+	garden.Source = "garden"
+	filter.Apply(&garden)
+	assert.LessOrEqual(t, garden.Spectrogram[bin], byte(1), "the first source's baseline is kept")
+}
+
+func TestNewUiSpectrogramFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		mode  string
+		count int
+	}{
+		{"empty mode", "", 1},
+		{"normal", conf.UiSpectrogramModeNormal, 1},
+		{"difference", conf.UiSpectrogramModeDifference, 2},
+		{"unknown falls back to normal", "psychedelic", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			filters := newUiSpectrogramFilters(&conf.UiSpectrogramSettings{Mode: tt.mode}, nil, GetLogger())
+			require.Len(t, filters, tt.count)
+		})
+	}
+
+	// Out of range rates fall back to the default
+	f := newSpectrogramDifferenceFilter(0)
+	assert.InDelta(t, defaultDifferenceAdaptRate, f.rate, 0)
+}
+
+func TestSpectrogramFrameCapFilter_DownResolvesOversizedFrame(t *testing.T) {
+	t.Parallel()
+
+	const (
+		columns  = 100
+		maxBytes = 4096
+		loudBin  = 200
+	)
+
+	// An oversized frame, e.g. from a misconfigured column batch size
+	data := make([]byte, columns*myaudio.UiSpectrogramBins)
+	data[7*myaudio.UiSpectrogramBins+loudBin] = 255
+	frame := myaudio.UiSpectrogramData{Spectrogram: data}
+
+	filter := newSpectrogramFrameCapFilter(maxBytes)
+	filter.stats = &uiSpectrogramPublishStats{}
+	filter.Apply(&frame)
+
+	assert.LessOrEqual(t, base64.StdEncoding.EncodedLen(len(frame.Spectrogram)), maxBytes)
+	require.Positive(t, frame.Bins)
+	assert.Less(t, frame.Bins, myaudio.UiSpectrogramBins)
+	assert.Zero(t, len(frame.Spectrogram)%frame.Bins, "frame must still hold whole columns")
+	assert.Contains(t, frame.Spectrogram, byte(255), "merging must keep loud pixels")
+	assert.Equal(t, uint64(1), filter.Truncated())
+
+	// Frames under the cap pass through untouched
+	small := spectrogramFrame(map[int]byte{10: 100})
+	filter.Apply(&small)
+	assert.Len(t, small.Spectrogram, 2*myaudio.UiSpectrogramBins)
+	assert.Zero(t, small.Bins)
+	assert.Equal(t, uint64(1), filter.Truncated())
+	assert.Equal(t, uint64(1), filter.stats.snapshot().FramesTruncated, "down-resolved frames are reported in the publisher stats")
+}
+
+func TestSpectrogramBinAggregationFilter_ReducesBinsAndKeepsPeakBand(t *testing.T) {
+	t.Parallel()
+
+	const peakBin = 130
+
+	for _, factor := range []int{2, 4, 8} {
+		for _, mode := range []string{conf.UiSpectrogramAggregateMax, conf.UiSpectrogramAggregateSum} {
+			frame := spectrogramFrame(map[int]byte{peakBin: 200, peakBin + 1: 40, 20: 30})
+			newSpectrogramBinAggregationFilter(factor, mode, nil).Apply(&frame)
+
+			wantBins := (myaudio.UiSpectrogramBins + factor - 1) / factor
+			require.Equal(t, wantBins, frame.Bins, "factor %d, mode %s", factor, mode)
+			require.Len(t, frame.Spectrogram, 2*wantBins, "both columns are aggregated")
+
+			column := frame.Spectrogram[:frame.Bins]
+			peak := 0
+			for i, v := range column {
+				if v > column[peak] {
+					peak = i
+				}
+			}
+			assert.Equal(t, peakBin/factor, peak, "factor %d, mode %s: peak must stay in its band", factor, mode)
+
+			// The aggregated band still covers the peak's original frequency
+			peakHz := float64(peakBin) * float64(conf.SampleRate) / 512
+			assert.InDelta(t, float64(conf.SampleRate)/512*float64(factor), frame.BinHz, 1e-9)
+			assert.LessOrEqual(t, float64(peak)*frame.ColumnBinHz(), peakHz)
+			assert.Greater(t, float64(peak+1)*frame.ColumnBinHz(), peakHz)
+		}
+	}
+}
+
+func TestSpectrogramBinAggregationFilter_SumSaturates(t *testing.T) {
+	t.Parallel()
+
+	frame := spectrogramFrame(map[int]byte{10: 200, 11: 200, 12: 3, 13: 4})
+	newSpectrogramBinAggregationFilter(2, conf.UiSpectrogramAggregateSum, nil).Apply(&frame)
+
+	assert.Equal(t, byte(255), frame.Spectrogram[5])
+	assert.Equal(t, byte(7), frame.Spectrogram[6])
+}
+
+func TestNewUiSpectrogramFilters_BinAggregation(t *testing.T) {
+	t.Parallel()
+
+	// Aggregation is added ahead of the mode filter, and a factor of one adds nothing
+	filters := newUiSpectrogramFilters(&conf.UiSpectrogramSettings{BinAggregation: 4, Mode: conf.UiSpectrogramModeDifference}, nil, GetLogger())
+	require.Len(t, filters, 3)
+	assert.IsType(t, &spectrogramBinAggregationFilter{}, filters[0])
+
+	filters = newUiSpectrogramFilters(&conf.UiSpectrogramSettings{BinAggregation: 1}, nil, GetLogger())
+	assert.Len(t, filters, 1)
+}
+
+func TestSpectrogramBinDownsampleFilter_AveragesToTargetBins(t *testing.T) {
+	t.Parallel()
+
+	native := myaudio.UiSpectrogramBins
+	frame := spectrogramFrame(map[int]byte{0: 100, 1: 50, 2: 0})
+	nativeRange := frame.ColumnBinHz() * float64(native)
+	newSpectrogramBinDownsampleFilter(128).Apply(&frame)
+
+	require.Equal(t, 128, frame.Bins)
+	require.Len(t, frame.Spectrogram, 2*128, "both columns are downsampled")
+	// 257 bins into 128 puts bins 0 and 1 in the first group and 2 and 3 in the second
+	assert.Equal(t, byte(75), frame.Spectrogram[0])
+	assert.Zero(t, frame.Spectrogram[1])
+	assert.Equal(t, byte(75), frame.Spectrogram[128], "the second column matches the first")
+	assert.InDelta(t, nativeRange, frame.ColumnBinHz()*float64(frame.ColumnBins()), 1e-9,
+		"the frames still cover the native frequency range")
+
+	// The last group ends on the native frame's last bin
+	last := spectrogramFrame(map[int]byte{native - 1: 90})
+	newSpectrogramBinDownsampleFilter(64).Apply(&last)
+	assert.Positive(t, last.Spectrogram[63])
+}
+
+func TestSpectrogramBinDownsampleFilter_Passthrough(t *testing.T) {
+	t.Parallel()
+
+	for _, bins := range []int{0, myaudio.UiSpectrogramBins, 1000} {
+		frame := spectrogramFrame(map[int]byte{40: 200})
+		original := append([]byte(nil), frame.Spectrogram...)
+		newSpectrogramBinDownsampleFilter(bins).Apply(&frame)
+		assert.Zero(t, frame.Bins, "target %d", bins)
+		assert.Zero(t, frame.BinHz, "target %d", bins)
+		assert.Equal(t, original, frame.Spectrogram, "target %d", bins)
+	}
+
+	filters := newUiSpectrogramFilters(&conf.UiSpectrogramSettings{}, nil, GetLogger())
+	assert.Len(t, filters, 1, "no downsampling filter without a target")
+	filters = newUiSpectrogramFilters(&conf.UiSpectrogramSettings{SpectrogramBins: 64}, nil, GetLogger())
+	assert.IsType(t, &spectrogramBinDownsampleFilter{}, filters[0])
+}
+
+func TestSpectrogramFrequencyBandFilter_KeepsBandBins(t *testing.T) {
+	t.Parallel()
+
+	frame := spectrogramFrame(map[int]byte{45: 10, 46: 20, 185: 30, 186: 40})
+	binHz := frame.ColumnBinHz()
+	// 2 kHz falls within bin 46 and 8 kHz within bin 185 at the native bin width
+	require.Equal(t, 46, int(2000/binHz))
+	require.Equal(t, 185, int(8000/binHz))
+	newSpectrogramFrequencyBandFilter(2000, 8000).Apply(&frame)
+
+	require.Equal(t, 140, frame.Bins)
+	require.Len(t, frame.Spectrogram, 2*140, "both columns are cropped")
+	assert.Equal(t, byte(20), frame.Spectrogram[0], "the bin holding the lower edge is kept")
+	assert.Equal(t, byte(30), frame.Spectrogram[139], "the bin holding the upper edge is kept")
+	assert.Equal(t, byte(20), frame.Spectrogram[140], "the second column matches the first")
+	assert.NotContains(t, frame.Spectrogram, byte(10))
+	assert.NotContains(t, frame.Spectrogram, byte(40))
+	assert.InDelta(t, 46*binHz, frame.MinHz, 1e-9, "the frame starts at the first bin kept")
+	assert.InDelta(t, binHz, frame.ColumnBinHz(), 1e-9, "cropping keeps the bin width")
+	assert.LessOrEqual(t, frame.MinHz, 2000.0)
+	assert.GreaterOrEqual(t, frame.MinHz+float64(frame.Bins)*frame.ColumnBinHz(), 8000.0)
+}
+
+func TestSpectrogramFrequencyBandFilter_ClampsToFrame(t *testing.T) {
+	t.Parallel()
+
+	native := myaudio.UiSpectrogramBins
+	frame := spectrogramFrame(map[int]byte{native - 1: 90})
+	newSpectrogramFrequencyBandFilter(4000, 0).Apply(&frame)
+	upper := frame.MinHz + float64(frame.Bins)*frame.ColumnBinHz()
+	assert.InDelta(t, float64(native)*frame.ColumnBinHz(), upper, 1e-9, "without an upper edge the band reaches the top bin")
+	assert.Equal(t, byte(90), frame.Spectrogram[frame.Bins-1])
+
+	// Beyond the top of the frame, and a band the frame doesn't overlap, leave it unchanged
+	for _, band := range [][2]float64{{0, 1e6}, {1e6, 2e6}} {
+		frame := spectrogramFrame(map[int]byte{40: 200})
+		original := append([]byte(nil), frame.Spectrogram...)
+		newSpectrogramFrequencyBandFilter(band[0], band[1]).Apply(&frame)
+		assert.Zero(t, frame.Bins, "band %v", band)
+		assert.Zero(t, frame.MinHz, "band %v", band)
+		assert.Equal(t, original, frame.Spectrogram, "band %v", band)
+	}
+
+	// Cropping runs ahead of aggregation
+	filters := newUiSpectrogramFilters(&conf.UiSpectrogramSettings{SpectrogramMinFreqHz: 2000, BinAggregation: 2}, nil, GetLogger())
+	require.Len(t, filters, 3)
+	assert.IsType(t, &spectrogramFrequencyBandFilter{}, filters[0])
+	assert.IsType(t, &spectrogramBinAggregationFilter{}, filters[1])
+}
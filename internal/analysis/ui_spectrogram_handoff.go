@@ -0,0 +1,87 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// uiSpectrogramHandoff remembers the newest frame broadcast of each source, so the session a
+// Restart starts doesn't broadcast a frame the session before it already sent. The guard only
+// holds at the boundary: once a source's frames move past the old session's mark it is
+// dropped, so a later clock correction moving timestamps back doesn't hide frames. Both the
+// publisher of the old session and that of the new one may use it, so it is safe for
+// concurrent use. The zero value is ready to use.
+type uiSpectrogramHandoff struct {
+	mu       sync.Mutex
+	sent     map[string]time.Time // Timestamp of the newest frame broadcast of each source this session
+	previous map[string]time.Time // Marks of the session before a restart, for sources that haven't moved past them yet
+}
+
+// begin starts a new session's marks. A restart carries the marks of the ending session
+// over, any other start forgets them.
+func (h *uiSpectrogramHandoff) begin(restart bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.previous = nil
+	if restart {
+		h.previous = h.sent
+	}
+	h.sent = make(map[string]time.Time)
+}
+
+// admit reports whether frame is new to the stream, recording it as its source's newest
+// broadcast frame when it is. A nil handoff admits every frame.
+func (h *uiSpectrogramHandoff) admit(frame *myaudio.UiSpectrogramData) bool {
+	if h == nil {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if mark, ok := h.previous[frame.Source]; ok {
+		if !frame.Timestamp.After(mark) {
+			return false
+		}
+		delete(h.previous, frame.Source)
+	}
+	if h.sent == nil {
+		h.sent = make(map[string]time.Time)
+	}
+	if frame.Timestamp.After(h.sent[frame.Source]) {
+		h.sent[frame.Source] = frame.Timestamp
+	}
+	return true
+}
+
+// handoffSpectrogramBroadcaster leaves out the frames its handoff has seen broadcast before a
+// restart. Left out frames aren't errors; a batch left empty isn't broadcast at all.
+type handoffSpectrogramBroadcaster struct {
+	uiSpectrogramBroadcaster
+	handoff *uiSpectrogramHandoff
+}
+
+// BroadcastSpectrogram broadcasts frame unless it was already broadcast before a restart.
+func (b *handoffSpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	if !b.handoff.admit(frame) {
+		return nil
+	}
+	return b.uiSpectrogramBroadcaster.BroadcastSpectrogram(frame)
+}
+
+// BroadcastSpectrogramBatch broadcasts the frames of the batch not already broadcast before a
+// restart.
+func (b *handoffSpectrogramBroadcaster) BroadcastSpectrogramBatch(frames []*myaudio.UiSpectrogramData) error {
+	fresh := make([]*myaudio.UiSpectrogramData, 0, len(frames))
+	for _, frame := range frames {
+		if b.handoff.admit(frame) {
+			fresh = append(fresh, frame)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	return b.uiSpectrogramBroadcaster.BroadcastSpectrogramBatch(fresh)
+}
@@ -0,0 +1,44 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestUiSpectrogramHandoff_SkipsFramesBroadcastBeforeRestart(t *testing.T) {
+	base := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	frame := func(source string, offset time.Duration) *myaudio.UiSpectrogramData {
+		return &myaudio.UiSpectrogramData{Source: source, Timestamp: base.Add(offset)}
+	}
+
+	var handoff uiSpectrogramHandoff
+	handoff.begin(false)
+	assert.True(t, handoff.admit(frame("mic", 0)))
+	assert.True(t, handoff.admit(frame("mic", time.Second)))
+
+	handoff.begin(true)
+	assert.False(t, handoff.admit(frame("mic", time.Second)), "the old session already broadcast this frame")
+	assert.True(t, handoff.admit(frame("other", 0)), "a source the old session didn't broadcast isn't held back")
+	assert.True(t, handoff.admit(frame("mic", 2*time.Second)))
+	assert.True(t, handoff.admit(frame("mic", 0)), "past the boundary, earlier timestamps are no longer taken for duplicates")
+
+	handoff.begin(false)
+	assert.True(t, handoff.admit(frame("mic", 0)), "a fresh start forgets the marks")
+	assert.True(t, (*uiSpectrogramHandoff)(nil).admit(frame("mic", 0)))
+}
+
+func TestHandoffSpectrogramBroadcaster_FiltersBatches(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	var handoff uiSpectrogramHandoff
+	handoff.begin(false)
+	wrapped := &handoffSpectrogramBroadcaster{uiSpectrogramBroadcaster: broadcaster, handoff: &handoff}
+
+	first := &myaudio.UiSpectrogramData{Source: "mic", Timestamp: time.Now()}
+	assert.NoError(t, wrapped.BroadcastSpectrogram(first))
+	handoff.begin(true)
+	assert.NoError(t, wrapped.BroadcastSpectrogramBatch([]*myaudio.UiSpectrogramData{first}))
+	assert.Len(t, broadcaster.broadcast(), 1, "a batch of frames already broadcast is dropped")
+}
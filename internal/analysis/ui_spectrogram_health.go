@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// Thresholds the health of a running UI spectrogram session is judged by
+const (
+	uiSpectrogramHealthWindow       = 30 * time.Second // Broadcast results of the current and the previous window make up the error rate
+	uiSpectrogramDegradedErrorRate  = 0.5              // Error rate at or above which the feed is degraded
+	uiSpectrogramHealthStaleAfter   = 10 * time.Second // Time without a received frame after which the feed is degraded
+	uiSpectrogramHealthMinBroadcast = 5                // Broadcasts needed before the error rate counts, so one early failure isn't a degraded feed
+)
+
+// uiSpectrogramHealthTracker follows the broadcast results and received frames of the
+// running session to tell a healthy feed from one that runs but fails. Broadcasts are
+// counted in fixed windows; the error rate covers the current and the previous one, so a
+// burst of failures is forgotten within two windows once broadcasts succeed again. The zero
+// value is ready to use.
+type uiSpectrogramHealthTracker struct {
+	mu  sync.Mutex
+	now func() time.Time // time.Now when nil
+
+	started      time.Time // Start of the running session, which stands in for the last frame until one arrives
+	windowStart  time.Time
+	attempts     int
+	failures     int
+	prevAttempts int // Broadcasts of the previous window
+	prevFailures int // Failed broadcasts of the previous window
+	lastFrame    time.Time
+	lastError    time.Time
+}
+
+func (h *uiSpectrogramHealthTracker) clock() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
+
+// start forgets the results of earlier sessions, so a restarted session begins healthy
+func (h *uiSpectrogramHealthTracker) start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.clock()
+	h.started, h.windowStart = now, now
+	h.attempts, h.failures, h.prevAttempts, h.prevFailures = 0, 0, 0, 0
+	h.lastFrame, h.lastError = time.Time{}, time.Time{}
+}
+
+// frameReceived records that the publisher read a frame from the spectrogram channel
+func (h *uiSpectrogramHealthTracker) frameReceived() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastFrame = h.clock()
+}
+
+// broadcastResult records the result of broadcasting one frame
+func (h *uiSpectrogramHealthTracker) broadcastResult(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.clock()
+	h.rollLocked(now)
+	h.attempts++
+	if err != nil {
+		h.failures++
+		h.lastError = now
+	}
+}
+
+// rollLocked starts a new window once the current one is over. A window without any
+// broadcast in between leaves nothing for the previous one. The caller must hold h.mu.
+func (h *uiSpectrogramHealthTracker) rollLocked(now time.Time) {
+	elapsed := now.Sub(h.windowStart)
+	if elapsed < uiSpectrogramHealthWindow {
+		return
+	}
+	if elapsed < 2*uiSpectrogramHealthWindow {
+		h.prevAttempts, h.prevFailures = h.attempts, h.failures
+	} else {
+		h.prevAttempts, h.prevFailures = 0, 0
+	}
+	h.attempts, h.failures = 0, 0
+	h.windowStart = now
+}
+
+// report returns the health of a running session: degraded while the recent error rate is
+// at or above uiSpectrogramDegradedErrorRate, or when no frame was received for
+// uiSpectrogramHealthStaleAfter, and healthy otherwise.
+func (h *uiSpectrogramHealthTracker) report() myaudio.UiSpectrogramHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.clock()
+	h.rollLocked(now)
+
+	health := myaudio.UiSpectrogramHealth{
+		Status:    myaudio.UiSpectrogramHealthy,
+		LastFrame: h.lastFrame,
+		LastError: h.lastError,
+	}
+	attempts := h.attempts + h.prevAttempts
+	if attempts > 0 {
+		health.ErrorRate = float64(h.failures+h.prevFailures) / float64(attempts)
+	}
+
+	lastActivity := h.lastFrame
+	if lastActivity.IsZero() {
+		lastActivity = h.started
+	}
+	switch {
+	case attempts >= uiSpectrogramHealthMinBroadcast && health.ErrorRate >= uiSpectrogramDegradedErrorRate:
+		health.Status = myaudio.UiSpectrogramDegraded
+	case !lastActivity.IsZero() && now.Sub(lastActivity) >= uiSpectrogramHealthStaleAfter:
+		health.Status = myaudio.UiSpectrogramDegraded
+	}
+	return health
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// newTestHealthStats returns stats whose health tracker runs on a fake clock advanced by the
+// returned function, with a session started.
+func newTestHealthStats() (stats *uiSpectrogramPublishStats, advance func(time.Duration)) {
+	now := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	stats = &uiSpectrogramPublishStats{}
+	stats.health.now = func() time.Time { return now }
+	stats.health.start()
+	return stats, func(d time.Duration) { now = now.Add(d) }
+}
+
+// feedFrames receives and broadcasts one frame every 100ms for the given duration.
+func feedFrames(stats *uiSpectrogramPublishStats, advance func(time.Duration), d time.Duration, err error) {
+	const step = 100 * time.Millisecond
+	for elapsed := time.Duration(0); elapsed < d; elapsed += step {
+		stats.received(1)
+		stats.broadcastResult(err)
+		advance(step)
+	}
+}
+
+func TestUiSpectrogramHealth_ErrorBurstDegradesAndRecovers(t *testing.T) {
+	stats, advance := newTestHealthStats()
+	errBroadcast := errors.New("client write failed")
+
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, stats.health.report().Status, "a new session starts healthy")
+
+	feedFrames(stats, advance, 10*time.Second, nil)
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, stats.health.report().Status)
+
+	feedFrames(stats, advance, 2*time.Second, errBroadcast)
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, stats.health.report().Status, "a short burst stays below the error rate")
+
+	feedFrames(stats, advance, 18*time.Second, errBroadcast)
+	health := stats.health.report()
+	assert.Equal(t, myaudio.UiSpectrogramDegraded, health.Status, "most broadcasts failed")
+	assert.GreaterOrEqual(t, health.ErrorRate, uiSpectrogramDegradedErrorRate)
+	assert.False(t, health.LastError.IsZero())
+	lastError := health.LastError
+
+	// The burst counts until a whole window of successful broadcasts replaced its window
+	feedFrames(stats, advance, time.Second, nil)
+	assert.Equal(t, myaudio.UiSpectrogramDegraded, stats.health.report().Status, "the burst still counts in the previous window")
+	feedFrames(stats, advance, uiSpectrogramHealthWindow, nil)
+	health = stats.health.report()
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, health.Status)
+	assert.Zero(t, health.ErrorRate)
+	assert.Equal(t, lastError, health.LastError, "the last error is still reported after recovering")
+}
+
+func TestUiSpectrogramHealth_StaleFeedIsDegraded(t *testing.T) {
+	stats, advance := newTestHealthStats()
+
+	advance(uiSpectrogramHealthStaleAfter)
+	assert.Equal(t, myaudio.UiSpectrogramDegraded, stats.health.report().Status, "no frame arrived since the session started")
+
+	stats.received(1)
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, stats.health.report().Status, "frames flow again")
+
+	// Received frames keep the feed healthy while nobody watches and nothing is broadcast
+	advance(uiSpectrogramHealthStaleAfter / 2)
+	stats.received(1)
+	advance(uiSpectrogramHealthStaleAfter / 2)
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, stats.health.report().Status)
+
+	advance(uiSpectrogramHealthStaleAfter)
+	assert.Equal(t, myaudio.UiSpectrogramDegraded, stats.health.report().Status)
+
+	stats.health.start()
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, stats.health.report().Status, "a restarted session begins healthy")
+}
+
+func TestUiSpectrogramManager_Health(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+	assert.Equal(t, myaudio.UiSpectrogramStopped, manager.Health())
+
+	require.NoError(t, manager.Start(t.Context()))
+	assert.Equal(t, myaudio.UiSpectrogramHealthy, manager.Health())
+
+	require.NoError(t, manager.Stop())
+	assert.Equal(t, myaudio.UiSpectrogramStopped, manager.Health())
+}
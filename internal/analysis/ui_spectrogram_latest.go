@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// uiSpectrogramLatestSlot is a one-slot buffer between the publisher loop and the broadcast,
+// for a live view where only the current audio matters. The loop puts each frame in the slot
+// and goes back to receiving, while run broadcasts from the slot on a goroutine of its own.
+// While a broadcast waits for slow clients, each new frame replaces the one in the slot, so
+// once the clients catch up they get the newest frame rather than a backlog. put and close
+// may be called while run is broadcasting.
+type uiSpectrogramLatestSlot struct {
+	mu      sync.Mutex
+	pending *myaudio.UiSpectrogramData // Newest frame not broadcast yet, if any
+	closed  bool
+	wake    chan struct{} // Signals run that a frame was put or the slot was closed
+}
+
+// newUiSpectrogramLatestSlot returns a slot when latest-wins broadcasting is configured, nil
+// otherwise.
+func newUiSpectrogramLatestSlot(settings *conf.UiSpectrogramSettings) *uiSpectrogramLatestSlot {
+	if !settings.LatestWins {
+		return nil
+	}
+	return &uiSpectrogramLatestSlot{wake: make(chan struct{}, 1)}
+}
+
+// put makes frame the next one broadcast, replacing a frame still waiting, which is counted
+// in stats as coalesced.
+func (s *uiSpectrogramLatestSlot) put(frame *myaudio.UiSpectrogramData, stats *uiSpectrogramPublishStats) {
+	s.mu.Lock()
+	if s.pending != nil {
+		stats.coalesced()
+	}
+	s.pending = frame
+	s.mu.Unlock()
+	s.signal()
+}
+
+// close makes run return once the frame still waiting, if any, is broadcast. A nil slot
+// ignores it.
+func (s *uiSpectrogramLatestSlot) close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.signal()
+}
+
+// signal wakes run unless a wake-up is already due.
+func (s *uiSpectrogramLatestSlot) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take empties the slot, returning the waiting frame, if any, and whether the slot is closed.
+func (s *uiSpectrogramLatestSlot) take() (frame *myaudio.UiSpectrogramData, closed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	frame, s.pending = s.pending, nil
+	return frame, s.closed
+}
+
+// run broadcasts the frames put in the slot until it is closed, reporting each result to the
+// supervisor, stats and audioMetrics like the publisher loop does. A frame waiting for
+// clients that have all disconnected since is dropped.
+func (s *uiSpectrogramLatestSlot) run(apiController uiSpectrogramBroadcaster, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, audioMetrics *metrics.MyAudioMetrics, log logger.Logger) {
+	errorLog := newUiSpectrogramErrorThrottle(uiSpectrogramBroadcastErrorLogInterval)
+	for range s.wake {
+		frame, closed := s.take()
+		if frame != nil && apiController.SpectrogramClientCount() > 0 {
+			start := time.Now()
+			err := apiController.BroadcastSpectrogram(frame)
+			reportUiSpectrogramBroadcast(err, 1, time.Since(start), supervisor, stats, audioMetrics, errorLog, log)
+		}
+		// The loop puts no frames once it closes the slot, so the last one was just taken
+		if closed {
+			return
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// stallingSpectrogramBroadcaster blocks its first broadcast until release is closed, as a
+// client that stopped reading would, closing stalled once that broadcast has started.
+type stallingSpectrogramBroadcaster struct {
+	*fakeSpectrogramBroadcaster
+	stalled chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (b *stallingSpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	b.once.Do(func() {
+		close(b.stalled)
+		<-b.release
+	})
+	return b.fakeSpectrogramBroadcaster.BroadcastSpectrogram(frame)
+}
+
+func TestUiSpectrogramSSEPublisher_LatestWinsWhileStalled(t *testing.T) {
+	broadcaster := &stallingSpectrogramBroadcaster{
+		fakeSpectrogramBroadcaster: &fakeSpectrogramBroadcaster{},
+		stalled:                    make(chan struct{}),
+		release:                    make(chan struct{}),
+	}
+	broadcaster.clients.Store(1)
+	latest := newUiSpectrogramLatestSlot(&conf.UiSpectrogramSettings{LatestWins: true})
+	require.NotNil(t, latest)
+	var stats uiSpectrogramPublishStats
+	ctx, cancel := context.WithCancel(t.Context())
+	spectrogramChan := make(chan myaudio.UiSpectrogramData)
+	var wg sync.WaitGroup
+	startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, &stats, nil, nil, nil, nil, nil, nil, nil, latest, 0, 0, nil, nil, nil, nil, GetLogger())
+
+	spectrogramChan <- myaudio.UiSpectrogramData{Source: "first"}
+	<-broadcaster.stalled
+
+	// The channel is unbuffered, so each send only returns once the stalled publisher took it
+	const burst = 50
+	for i := range burst {
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: fmt.Sprintf("burst-%d", i)}
+	}
+	spectrogramChan <- myaudio.UiSpectrogramData{Source: "final"}
+	// Each frame of the burst, and only those, was replaced by the one after it
+	require.Eventually(t, func() bool { return stats.snapshot().FramesCoalesced == burst }, 2*time.Second, time.Millisecond)
+
+	close(broadcaster.release)
+	require.Eventually(t, func() bool { return len(broadcaster.broadcast()) == 2 }, 2*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"first", "final"}, broadcaster.broadcast(), "only the final frame of the burst is broadcast")
+
+	cancel()
+	wg.Wait()
+	snapshot := stats.snapshot()
+	assert.Equal(t, uint64(burst+2), snapshot.FramesReceived)
+	assert.Equal(t, uint64(2), snapshot.FramesBroadcast)
+	assert.Equal(t, uint64(burst), snapshot.FramesCoalesced)
+}
+
+func TestNewUiSpectrogramLatestSlot_Disabled(t *testing.T) {
+	assert.Nil(t, newUiSpectrogramLatestSlot(&conf.UiSpectrogramSettings{}))
+	(*uiSpectrogramLatestSlot)(nil).close()
+}
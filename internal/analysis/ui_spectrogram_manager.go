@@ -1,74 +1,418 @@
 package analysis
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	apiv2 "github.com/tphakala/birdnet-go/internal/api/v2"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
 )
 
+// defaultUiSpectrogramShutdownTimeout is how long Stop waits for the monitoring goroutines
+// before forcing cleanup, unless changed with SetShutdownTimeout
+const defaultUiSpectrogramShutdownTimeout = 30 * time.Second
+
+// defaultUiSpectrogramDepthSampleInterval is how often the depth of the spectrogram channel
+// is sampled while monitoring runs
+const defaultUiSpectrogramDepthSampleInterval = time.Second
+
 // UiSpectrogramManager manages the lifecycle of UI spectrogram monitoring components
 type UiSpectrogramManager struct {
-	mutex          sync.Mutex
-	isRunning      bool
-	doneChan       chan struct{}
-	wg             sync.WaitGroup
-	spectrogramChan chan myaudio.UiSpectrogramData
-	proc           *processor.Processor
-	apiController  *apiv2.Controller
-	metrics        *observability.Metrics
+	mutex               sync.Mutex
+	isRunning           atomic.Bool // Read without the mutex; changed only while holding it
+	doneChan            chan struct{}
+	wg                  sync.WaitGroup
+	spectrogramChan     chan myaudio.UiSpectrogramData // Channel the manager was created with, which producers hold to send frames
+	queueMu             sync.RWMutex                   // Held by Send while it uses the queue, and by SetBufferSize to replace it
+	queue               chan myaudio.UiSpectrogramData // Buffer the publisher reads, spectrogramChan until SetBufferSize replaces it
+	proc                *processor.Processor
+	apiController       *apiv2.Controller
+	metrics             *observability.Metrics
+	supervisor          *uiSpectrogramSupervisor               // Restarts monitoring on sustained broadcast failures; kept across restarts for its backoff
+	sessionID           string                                 // Correlation ID of the running session, logged with every line of that session
+	baseLog             logger.Logger                          // Logger of the manager, which session loggers are derived from; GetLogger() when nil
+	strictStart         bool                                   // Start fails rather than succeeding when monitoring is already running
+	applied             myaudio.UiSpectrogramConfig            // Configuration the running session was started with
+	shutdownTimeout     time.Duration                          // How long Stop waits for the goroutines before forcing cleanup, 0 to wait indefinitely
+	stats               uiSpectrogramPublishStats              // Frame counters of all sessions of this manager
+	overflowStrategy    atomic.Pointer[string]                 // Overflow strategy of Send, nil to follow the settings; read lock-free on every frame
+	paused              atomic.Bool                            // The publisher drains frames without broadcasting them; checked on every frame
+	recent              atomic.Pointer[uiSpectrogramFrameRing] // Most recent frames of the running session for newly connected clients, nil when disabled
+	frameRecorder       *uiSpectrogramFrameRecorder            // Keeps the most recent frames of the running session on disk, nil when disabled
+	ctx                 context.Context                        // Context of the last Start, which Restart starts the next session with
+	ready               chan struct{}                          // Closed once the publisher of the running session consumes frames
+	flushRequests       chan chan struct{}                     // Flush requests the publisher of the running session serves, closing each once done
+	shutdownErrs        *uiSpectrogramShutdownErrors           // Errors the publishers of the running session stopped with, which Stop returns
+	handoff             uiSpectrogramHandoff                   // Newest frames broadcast per source, so a Restart doesn't broadcast them again
+	depthSampleInterval time.Duration                          // How often the running session samples the channel depth into the stats and metrics
 }
 
-// NewUiSpectrogramManager creates a new UI spectrogram manager
-func NewUiSpectrogramManager(spectrogramChan chan myaudio.UiSpectrogramData, proc *processor.Processor, apiController *apiv2.Controller, metrics *observability.Metrics) *UiSpectrogramManager {
-	return &UiSpectrogramManager{
-		spectrogramChan: spectrogramChan,
-		proc:           proc,
-		apiController:  apiController,
-		metrics:        metrics,
+// activeUiSpectrogramManager is the manager audio capture sends spectrogram frames through,
+// nil until the control monitor created one
+var activeUiSpectrogramManager atomic.Pointer[UiSpectrogramManager]
+
+// sendUiSpectrogramFrame hands a frame from audio capture to the spectrogram channel, through
+// the active manager when there is one so its overflow strategy applies and drops are counted
+func sendUiSpectrogramFrame(ch chan myaudio.UiSpectrogramData, frame *myaudio.UiSpectrogramData, settings *conf.UiSpectrogramSettings, stop <-chan struct{}) {
+	if m := activeUiSpectrogramManager.Load(); m != nil && m.spectrogramChan == ch {
+		m.Send(frame, stop)
+		return
 	}
+	myaudio.SendUiSpectrogramFrame(ch, frame, settings.OverflowStrategy, stop)
 }
 
-// Start starts UI spectrogram monitoring if enabled in settings
-func (m *UiSpectrogramManager) Start() error {
+// NewUiSpectrogramManager creates a new UI spectrogram manager, reporting its applied
+// configuration and frame counters through the API controller when one is given. The
+// processor may be nil, as in minimal setups; features publishing through it, currently
+// the MQTT summaries, are then skipped with a warning when they are enabled. The manager and
+// its publishers log to log, or to the package logger when it is nil.
+func NewUiSpectrogramManager(spectrogramChan chan myaudio.UiSpectrogramData, proc *processor.Processor, apiController *apiv2.Controller, metrics *observability.Metrics, log logger.Logger) *UiSpectrogramManager {
+	m := &UiSpectrogramManager{
+		baseLog:             log,
+		spectrogramChan:     spectrogramChan,
+		queue:               spectrogramChan,
+		proc:                proc,
+		apiController:       apiController,
+		metrics:             metrics,
+		shutdownTimeout:     defaultUiSpectrogramShutdownTimeout,
+		depthSampleInterval: defaultUiSpectrogramDepthSampleInterval,
+	}
+	if metrics != nil {
+		m.stats.errorMetrics = metrics.Errors
+	}
+	if apiController != nil {
+		apiController.SetSpectrogramConfigProvider(m.Config)
+		apiController.SetSpectrogramStatsProvider(m.Stats)
+		apiController.SetSpectrogramHealthProvider(m.healthReport)
+		apiController.SetSpectrogramRecentFramesProvider(m.RecentFrames)
+	}
+	return m
+}
+
+// SetStrictStart sets whether a Start while monitoring is already running returns an error.
+// By default such a Start is ignored, which can hide a caller starting the manager twice.
+func (m *UiSpectrogramManager) SetStrictStart(strict bool) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	m.strictStart = strict
+}
 
-	log := GetLogger()
-	if m.isRunning {
+// SetShutdownTimeout sets how long Stop waits for the monitoring goroutines to finish before
+// forcing cleanup. Zero waits indefinitely. Constrained devices can shorten it so a restart
+// isn't held up, and setups with slow sinks can lengthen it.
+func (m *UiSpectrogramManager) SetShutdownTimeout(timeout time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.shutdownTimeout = timeout
+}
+
+// Start starts UI spectrogram monitoring if enabled in settings. The publishers run under a
+// context derived from ctx: cancelling it stops the session as Stop would, and later
+// restarts start under the same ctx. A manager created without a spectrogram channel has no
+// frames to publish and fails to start.
+func (m *UiSpectrogramManager) Start(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.startLocked(ctx, false)
+}
+
+// startLocked starts a session under ctx, carrying the broadcast frames of the session before
+// it over when restart is set; the caller must hold the mutex
+func (m *UiSpectrogramManager) startLocked(ctx context.Context, restart bool) error {
+	log := m.logger()
+	if m.isRunning.Load() {
+		if m.strictStart {
+			return errors.Newf("UI spectrogram monitoring is already running").
+				Component("analysis.uispectrogram").
+				Category(errors.CategoryState).
+				Context("operation", "start").
+				Context("session_id", m.sessionID).
+				Severity(errors.SeverityWarning).
+				Build()
+		}
 		log.Debug("UI spectrogram monitoring is already running")
 		return nil
 	}
-	
+	if err := ctx.Err(); err != nil {
+		return errors.New(err).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryState).
+			Context("operation", "start").
+			Build()
+	}
+	// Receiving from a nil channel blocks forever, so such a publisher would never get a frame
+	if m.spectrogramChan == nil {
+		log.Warn("UI spectrogram manager has no spectrogram channel, not starting monitoring")
+		err := errors.Newf("UI spectrogram manager was created without a spectrogram channel").
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "start").
+			Severity(errors.SeverityFatal).
+			Build()
+		m.stats.errorMetrics.RecordError(err)
+		return err
+	}
+	m.ctx = ctx
+
 	// Create done channel for this session
 	m.doneChan = make(chan struct{})
 
+	// A fresh correlation ID per session lets its log lines be told apart across restarts
+	m.sessionID = uuid.New().String()
+	log = m.sessionLoggerLocked()
+
+	if m.supervisor == nil {
+		m.supervisor = newUiSpectrogramSupervisor(&conf.Setting().SoundId.UiSpectrogram, func() {
+			// Restart from a new goroutine: it stops the publisher that reported the failures
+			go func() {
+				// The supervisor outlives the session it was created in
+				log := m.sessionLogger()
+				if err := m.Restart(); err != nil {
+					log.Warn("supervised UI spectrogram restart failed", logger.Error(err))
+				}
+			}()
+		})
+	} else {
+		// Restarts keep the supervisor for its backoff, but not the thresholds it started with
+		m.supervisor.updateSettings(&conf.Setting().SoundId.UiSpectrogram)
+	}
+
+	m.supervisor.setLogger(log)
+
+	// Settings can change in place while running; remember what this session applied
+	m.applied = myaudio.NewUiSpectrogramConfig(&conf.Setting().SoundId.UiSpectrogram)
+
+	// The depth can change between sessions, so each one starts with an empty buffer
+	recent := newUiSpectrogramFrameRing(conf.Setting().SoundId.UiSpectrogram.RecentFrames)
+	m.recent.Store(recent)
+	frameRecorder, err := newUiSpectrogramFrameRecorder(&conf.Setting().SoundId.UiSpectrogram, log)
+	if err != nil {
+		log.Warn("UI spectrogram frame recording disabled", logger.Error(err))
+	}
+	frameRecorder.Start()
+	m.frameRecorder = frameRecorder
+
 	// Start publishers
-	startUiSpectrogramPublishers(&m.wg, m.doneChan, m.proc, m.spectrogramChan, m.apiController)
+	m.handoff.begin(restart)
+	m.stats.health.start()
+	m.ready = make(chan struct{})
+	m.flushRequests = make(chan chan struct{})
+	m.shutdownErrs = &uiSpectrogramShutdownErrors{}
+	queue := m.currentQueue()
+	startUiSpectrogramPublishers(&m.wg, ctx, m.doneChan, m.proc, queue, m.apiController, m.supervisor, &m.stats, &m.paused, &m.handoff, recent, frameRecorder, m.audioMetrics(), m.ready, m.flushRequests, m.shutdownErrs, log)
+	m.startForwarder(queue, m.doneChan)
+	m.startDepthSampler(queue, m.doneChan)
 
-	m.isRunning = true
+	m.isRunning.Store(true)
+	go m.stopOnCancel(ctx, m.doneChan)
 	log.Info("UI spectrogram monitoring started")
 	return nil
 }
 
-// Stop stops all UI spectrogram monitoring components
-func (m *UiSpectrogramManager) Stop() {
+// currentQueue returns the buffer the publisher of the next session reads
+func (m *UiSpectrogramManager) currentQueue() chan myaudio.UiSpectrogramData {
+	m.queueMu.RLock()
+	defer m.queueMu.RUnlock()
+	return m.queue
+}
+
+// startForwarder moves the frames sent straight to the channel the manager was created with,
+// as replays do, into the buffer SetBufferSize replaced it with, until doneChan is closed.
+// Nothing needs forwarding while the publisher reads the original channel.
+func (m *UiSpectrogramManager) startForwarder(queue chan myaudio.UiSpectrogramData, doneChan chan struct{}) {
+	if queue == m.spectrogramChan {
+		return
+	}
+	m.wg.Go(func() {
+		for {
+			select {
+			case <-doneChan:
+				return
+			case frame, ok := <-m.spectrogramChan:
+				if !ok {
+					return
+				}
+				m.Send(&frame, doneChan)
+			}
+		}
+	})
+}
+
+// startDepthSampler records the depth of the session's buffer queue in the stats and the audio
+// metrics right away and then every depthSampleInterval, until doneChan is closed, so
+// backpressure on the publisher shows over time
+func (m *UiSpectrogramManager) startDepthSampler(queue chan myaudio.UiSpectrogramData, doneChan <-chan struct{}) {
+	interval := m.depthSampleInterval
+	if interval <= 0 {
+		return
+	}
+	audioMetrics := m.audioMetrics()
+	sample := func() {
+		depth, capacity := len(queue), cap(queue)
+		m.stats.channelSampled(depth, capacity)
+		if audioMetrics != nil {
+			audioMetrics.RecordUiSpectrogramChannelDepth(depth, capacity)
+		}
+	}
+
+	m.wg.Go(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		sample()
+		for {
+			select {
+			case <-doneChan:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	})
+}
+
+// WaitUntilReady blocks until the publisher of the running session has started consuming
+// frames, so frames sent afterwards aren't missed. It returns an error when monitoring isn't
+// running, when the session stops first, or when ctx is done.
+func (m *UiSpectrogramManager) WaitUntilReady(ctx context.Context) error {
 	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	running := m.isRunning.Load()
+	ready, doneChan, sessionID := m.ready, m.doneChan, m.sessionID
+	m.mutex.Unlock()
 
-	log := GetLogger()
-	if !m.isRunning {
-		log.Debug("UI spectrogram monitoring is not running")
+	if !running {
+		return errors.Newf("UI spectrogram monitoring is not running").
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryState).
+			Context("operation", "wait_until_ready").
+			Build()
+	}
+
+	// A ready publisher returns at once, even when ctx is already done
+	select {
+	case <-ready:
+		return nil
+	default:
+	}
+	select {
+	case <-ready:
+		return nil
+	case <-doneChan:
+		return errors.Newf("UI spectrogram monitoring stopped before its publisher was ready").
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryState).
+			Context("operation", "wait_until_ready").
+			Context("session_id", sessionID).
+			Build()
+	case <-ctx.Done():
+		return errors.New(ctx.Err()).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryTimeout).
+			Context("operation", "wait_until_ready").
+			Context("session_id", sessionID).
+			Build()
+	}
+}
+
+// Flush makes the publisher of the running session broadcast the frames it holds for a batch
+// or a throttle window now rather than when their interval is up, returning once they are
+// sent. Nothing is sent when no frames are held, and a stopped manager has nothing to flush.
+// It returns an error only when ctx is done first.
+func (m *UiSpectrogramManager) Flush(ctx context.Context) error {
+	m.mutex.Lock()
+	running := m.isRunning.Load() && m.apiController != nil
+	requests, doneChan, sessionID := m.flushRequests, m.doneChan, m.sessionID
+	m.mutex.Unlock()
+
+	if !running {
+		return nil
+	}
+
+	done := make(chan struct{})
+	select {
+	case requests <- done:
+	case <-doneChan:
+		// Stopping drains the held frames anyway
+		return nil
+	case <-ctx.Done():
+		return uiSpectrogramFlushError(ctx, sessionID)
+	}
+	select {
+	case <-done:
+		return nil
+	case <-doneChan:
+		return nil
+	case <-ctx.Done():
+		return uiSpectrogramFlushError(ctx, sessionID)
+	}
+}
+
+// uiSpectrogramFlushError reports a Flush that ctx cut short
+func uiSpectrogramFlushError(ctx context.Context, sessionID string) error {
+	return errors.New(ctx.Err()).
+		Component("analysis.uispectrogram").
+		Category(errors.CategoryTimeout).
+		Context("operation", "flush").
+		Context("session_id", sessionID).
+		Build()
+}
+
+// stopOnCancel stops the session with the given done channel once ctx is cancelled. It
+// returns without stopping anything when that session was stopped first.
+func (m *UiSpectrogramManager) stopOnCancel(ctx context.Context, doneChan chan struct{}) {
+	select {
+	case <-doneChan:
+		return
+	case <-ctx.Done():
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// Stop may have ended the session, and Start begun a new one, while waiting for the lock
+	if m.doneChan != doneChan {
 		return
 	}
+	m.sessionLoggerLocked().Info("UI spectrogram monitoring context cancelled")
+	if err := m.stopLocked(); err != nil {
+		m.logger().Warn("UI spectrogram monitoring did not stop cleanly", logger.Error(err))
+	}
+}
+
+// Stop stops all UI spectrogram monitoring components. When the goroutines don't finish
+// within the shutdown timeout, cleanup is forced and an error is returned, since goroutines
+// of the old session may still be running. Whether the stop was clean or forced, and how long
+// it waited, is recorded in the manager's metrics. Frames queued for the frame recorder are
+// written to disk before it returns.
+func (m *UiSpectrogramManager) Stop() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.stopLocked()
+}
+
+// stopLocked stops the running session; the caller must hold the mutex
+func (m *UiSpectrogramManager) stopLocked() error {
+	if !m.isRunning.Load() {
+		m.logger().Debug("UI spectrogram monitoring is not running")
+		return nil
+	}
+	log := m.sessionLoggerLocked()
 
 	log.Info("stopping UI spectrogram monitoring")
 
+	// Report not running before the goroutines are told to stop, so IsRunning never
+	// returns true once the done channel is closed
+	m.isRunning.Store(false)
+
 	// Signal all goroutines to stop
+	stopStart := time.Now()
 	if m.doneChan != nil {
 		close(m.doneChan)
 	}
@@ -80,35 +424,306 @@ func (m *UiSpectrogramManager) Stop() {
 		close(done)
 	}()
 
+	// A nil channel never fires, so a zero timeout waits for the goroutines indefinitely
+	var timeout <-chan time.Time
+	if m.shutdownTimeout > 0 {
+		timer := time.NewTimer(m.shutdownTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var timeoutErr error
 	select {
 	case <-done:
 		// All goroutines finished cleanly
 		log.Debug("all UI spectrogram monitoring goroutines stopped cleanly")
-	case <-time.After(30 * time.Second):
+	case <-timeout:
 		// Timeout occurred - force shutdown
 		log.Warn("UI spectrogram monitoring shutdown timed out, forcing cleanup",
-			logger.Duration("timeout", 30*time.Second))
+			logger.Duration("timeout", m.shutdownTimeout))
 		// Continue with cleanup anyway - don't hang the system
+		timeoutErr = errors.Newf("UI spectrogram monitoring goroutines did not stop within %s", m.shutdownTimeout).
+			Component("analysis.uispectrogram").
+			Category(errors.CategorySystem).
+			Context("session_id", m.sessionID).
+			Context("timeout", m.shutdownTimeout.String()).
+			Build()
+	}
+	// The publisher has stopped offering frames unless it timed out, so the record is complete
+	m.shutdownErrs.add(m.frameRecorder.Stop())
+	m.frameRecorder = nil
+	if audioMetrics := m.audioMetrics(); audioMetrics != nil {
+		audioMetrics.RecordUiSpectrogramShutdown(timeoutErr != nil, time.Since(stopStart).Seconds())
 	}
 
 	// Note: With the centralized logger, file handle cleanup is managed by the central logger
 	// No explicit close is needed here
 
-	m.isRunning = false
+	// Publishers that stopped with an error are reported along with a timeout of the rest
+	stopErr := errors.Aggregate(append(m.shutdownErrs.all(), timeoutErr)...)
+	m.stats.errorMetrics.RecordError(stopErr)
+
 	m.doneChan = nil
+	m.ready = nil
+	m.shutdownErrs = nil
+	m.sessionID = ""
+	m.applied = myaudio.UiSpectrogramConfig{}
 	log.Info("UI spectrogram monitoring stopped")
+	return stopErr
 }
 
-// Restart stops and starts UI spectrogram monitoring with current settings
+// Restart stops and starts UI spectrogram monitoring with current settings, under the
+// context of the last Start. It doesn't start a new session when the old one failed to
+// stop, so the two never run side by side. The spectrogram channel is kept, so frames queued
+// during the restart are published by the new session, and the mutex is held throughout, so
+// no Start or Stop lands in between. Frames the old session broadcast are not broadcast again.
 func (m *UiSpectrogramManager) Restart() error {
-	GetLogger().Info("restarting UI spectrogram monitoring")
-	m.Stop()
-	return m.Start()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.logger().Info("restarting UI spectrogram monitoring")
+	if err := m.stopLocked(); err != nil {
+		return err
+	}
+	ctx := m.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return m.startLocked(ctx, true)
 }
 
-// IsRunning returns whether UI spectrogram monitoring is currently active
-func (m *UiSpectrogramManager) IsRunning() bool {
+// UpdateSettings applies changed UI spectrogram settings to the running session in place,
+// without restarting it: Config reports them and the supervisor restarts by their thresholds.
+// They are copied under the manager's lock rather than written to the shared settings, which
+// audio capture reads on every frame. A stopped manager ignores them and applies the current
+// settings when it next starts.
+func (m *UiSpectrogramManager) UpdateSettings(settings *conf.UiSpectrogramSettings) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning.Load() {
+		return
+	}
+	m.applied = myaudio.NewUiSpectrogramConfig(settings)
+	m.supervisor.updateSettings(settings)
+}
+
+// Config returns the spectrogram configuration the running session applied, with the audio
+// sources registered now. A stopped manager reports only that it isn't running.
+func (m *UiSpectrogramManager) Config() myaudio.UiSpectrogramConfig {
+	m.mutex.Lock()
+	config := m.applied
+	config.Running = m.isRunning.Load()
+	config.SessionID = m.sessionID
+	m.mutex.Unlock()
+
+	config.Sources = []string{}
+	if config.Running {
+		for _, source := range myaudio.GetRegistry().ListSources() {
+			config.Sources = append(config.Sources, source.ID)
+		}
+	}
+	return config
+}
+
+// SetOverflowStrategy sets what Send does when the spectrogram channel is full, one of the
+// conf.UiSpectrogramOverflow* strategies. With drop-oldest the producer never blocks: the
+// oldest queued frames are discarded to make room for the new one. With block the producer
+// waits for the publisher. An empty strategy follows the UI spectrogram settings again.
+func (m *UiSpectrogramManager) SetOverflowStrategy(strategy string) {
+	if strategy == "" {
+		m.overflowStrategy.Store(nil)
+		return
+	}
+	m.overflowStrategy.Store(&strategy)
+}
+
+// Send queues a frame for the publisher, handling a full channel with the overflow strategy,
+// and reports whether the frame was queued. Frames the strategy discards are counted as
+// overflowed in the stats. A blocking send gives up when stop is closed.
+func (m *UiSpectrogramManager) Send(frame *myaudio.UiSpectrogramData, stop <-chan struct{}) bool {
+	var strategy string
+	if override := m.overflowStrategy.Load(); override != nil {
+		strategy = *override
+	} else {
+		strategy = conf.Setting().SoundId.UiSpectrogram.OverflowStrategy
+	}
+	m.queueMu.RLock()
+	defer m.queueMu.RUnlock()
+	delivered, dropped := myaudio.SendUiSpectrogramFrame(m.queue, frame, strategy, stop)
+	m.stats.overflowed(dropped)
+	return delivered
+}
+
+// minUiSpectrogramBufferSize is the smallest spectrogram buffer SetBufferSize accepts, so a
+// short stall of the publisher doesn't overflow it at once
+const minUiSpectrogramBufferSize = 8
+
+// SetBufferSize replaces the spectrogram buffer with one holding n frames, so it can be tuned
+// to absorb bursts without restarting the analyzer. Queued frames move to the new buffer; when
+// more are queued than it holds, the oldest are discarded and counted as overflowed. A running
+// session is restarted in place so its publisher reads the new buffer, which is paused for no
+// longer than the restart takes. Sizes below minUiSpectrogramBufferSize are rejected. While
+// monitoring is stopped, a producer blocked on a full buffer holds the resize up until it gives
+// up.
+func (m *UiSpectrogramManager) SetBufferSize(n int) error {
+	if n < minUiSpectrogramBufferSize {
+		return errors.Newf("UI spectrogram buffer size must be at least %d, got %d", minUiSpectrogramBufferSize, n).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryValidation).
+			Context("operation", "set_buffer_size").
+			Context("size", n).
+			Build()
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	return m.isRunning
+	if m.spectrogramChan == nil {
+		return errors.Newf("UI spectrogram manager was created without a spectrogram channel").
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "set_buffer_size").
+			Build()
+	}
+
+	// Sends in progress finish first, so no frame lands in the old buffer after the move
+	m.queueMu.Lock()
+	old := m.queue
+	if cap(old) == n {
+		m.queueMu.Unlock()
+		return nil
+	}
+	queue := make(chan myaudio.UiSpectrogramData, n)
+	moved, discarded := moveUiSpectrogramFrames(old, queue)
+	m.queue = queue
+	m.queueMu.Unlock()
+
+	m.stats.overflowed(discarded)
+	m.logger().Info("resized UI spectrogram buffer",
+		logger.Int("from", cap(old)),
+		logger.Int("to", n),
+		logger.Int("moved", moved),
+		logger.Int("discarded", discarded))
+
+	// The running publisher still reads the old buffer, which only the original channel's
+	// producers write to until the new session forwards them
+	if !m.isRunning.Load() {
+		return nil
+	}
+	if err := m.stopLocked(); err != nil {
+		return err
+	}
+	return m.startLocked(m.ctx, true)
+}
+
+// moveUiSpectrogramFrames moves the frames queued in from to to, oldest first, discarding the
+// oldest moved frames for newer ones once to is full. Nothing else may send to to meanwhile.
+func moveUiSpectrogramFrames(from, to chan myaudio.UiSpectrogramData) (moved, discarded int) {
+	for {
+		select {
+		case frame := <-from:
+			select {
+			case to <- frame:
+				moved++
+			default:
+				<-to
+				to <- frame
+				discarded++
+			}
+		default:
+			return moved, discarded
+		}
+	}
+}
+
+// Stats returns the frame counters of the SSE publisher, summed over every session this
+// manager ran, with the depth and capacity of the spectrogram channel when last sampled
+func (m *UiSpectrogramManager) Stats() myaudio.UiSpectrogramStats {
+	return m.stats.snapshot()
+}
+
+// Health returns whether the running session is healthy, degraded because its broadcasts
+// keep failing or no frames arrive, or stopped. Unlike IsRunning it tells a working feed from
+// one that only runs.
+func (m *UiSpectrogramManager) Health() myaudio.UiSpectrogramHealthStatus {
+	return m.healthReport().Status
+}
+
+// healthReport returns the health of the feed with the error rate and the times of the last
+// frame and broadcast error it was judged on
+func (m *UiSpectrogramManager) healthReport() myaudio.UiSpectrogramHealth {
+	health := m.stats.health.report()
+	if !m.isRunning.Load() {
+		health.Status = myaudio.UiSpectrogramStopped
+	}
+	return health
+}
+
+// SessionID returns the correlation ID of the running session, or "" when not running
+func (m *UiSpectrogramManager) SessionID() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sessionID
+}
+
+// sessionLogger returns a logger that tags every line with the ID of the running session.
+func (m *UiSpectrogramManager) sessionLogger() logger.Logger {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sessionLoggerLocked()
+}
+
+// sessionLoggerLocked returns a logger that tags every line with the session ID.
+// The caller must hold m.mutex.
+func (m *UiSpectrogramManager) sessionLoggerLocked() logger.Logger {
+	return m.logger().With(logger.String("session_id", m.sessionID))
+}
+
+// logger returns the logger the manager was created with, or the package logger
+func (m *UiSpectrogramManager) logger() logger.Logger {
+	if m.baseLog != nil {
+		return m.baseLog
+	}
+	return GetLogger()
+}
+
+// Pause stops broadcasting frames without stopping the publisher goroutines, for example
+// while the page showing the spectrogram is hidden. The publisher keeps draining the
+// spectrogram channel so producers aren't held up. It lasts until Resume, across restarts.
+func (m *UiSpectrogramManager) Pause() {
+	if !m.paused.Swap(true) {
+		m.logger().Info("UI spectrogram feed paused")
+	}
+}
+
+// Resume broadcasts frames again after Pause, starting with the next frame received.
+func (m *UiSpectrogramManager) Resume() {
+	if m.paused.Swap(false) {
+		m.logger().Info("UI spectrogram feed resumed")
+	}
+}
+
+// IsPaused returns whether the feed is paused. IsRunning stays true while paused.
+func (m *UiSpectrogramManager) IsPaused() bool {
+	return m.paused.Load()
+}
+
+// audioMetrics returns the metrics the publishers record to, nil when the manager has none.
+func (m *UiSpectrogramManager) audioMetrics() *metrics.MyAudioMetrics {
+	if m.metrics == nil {
+		return nil
+	}
+	return m.metrics.MyAudio
+}
+
+// RecentFrames returns the most recent frames of the running or last session, oldest first,
+// or nil when no frames are kept.
+func (m *UiSpectrogramManager) RecentFrames() []myaudio.UiSpectrogramData {
+	return m.recent.Load().frames()
+}
+
+// IsRunning returns whether UI spectrogram monitoring is currently active. It doesn't wait
+// for a Start or Stop in progress, so status probes aren't held up by a slow shutdown.
+func (m *UiSpectrogramManager) IsRunning() bool {
+	return m.isRunning.Load()
 }
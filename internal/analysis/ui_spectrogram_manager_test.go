@@ -0,0 +1,812 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/tphakala/birdnet-go/internal/api/v2"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+	"go.uber.org/goleak"
+)
+
+func TestUiSpectrogramManager_RedundantStart(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+
+	for _, strict := range []bool{false, true} {
+		manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+		manager.SetStrictStart(strict)
+
+		require.NoError(t, manager.Start(t.Context()))
+		session := manager.SessionID()
+
+		err := manager.Start(t.Context())
+		if strict {
+			require.Error(t, err, "a strict manager must report a redundant Start")
+			var enhanced *errors.EnhancedError
+			require.True(t, errors.As(err, &enhanced))
+			assert.Equal(t, string(errors.CategoryState), enhanced.GetCategory())
+			assert.Equal(t, errors.SeverityWarning, errors.SeverityOf(err), "the running session is unaffected")
+		} else {
+			assert.NoError(t, err, "a lenient manager ignores a redundant Start")
+		}
+		assert.True(t, manager.IsRunning())
+		assert.Equal(t, session, manager.SessionID(), "a redundant Start must leave the running session alone")
+
+		require.NoError(t, manager.Stop())
+		assert.False(t, manager.IsRunning())
+	}
+}
+
+func TestUiSpectrogramManager_StartStopLeavesNoGoroutines(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	for _, tt := range []struct {
+		name       string
+		controller *apiv2.Controller
+	}{
+		{"with API", controller},
+		{"without API", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Goroutines of the stream server and of earlier tests aren't the manager's
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+			manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, tt.controller, nil, nil)
+			require.NoError(t, manager.Start(t.Context()))
+			require.NoError(t, manager.WaitUntilReady(t.Context()))
+			require.NoError(t, manager.Restart())
+			require.NoError(t, manager.Stop())
+		})
+	}
+}
+
+func TestUiSpectrogramManager_StopsWhenContextCancelled(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+	ctx, cancel := context.WithCancel(t.Context())
+	require.NoError(t, manager.Start(ctx))
+	require.True(t, manager.IsRunning())
+
+	cancel()
+	require.Eventually(t, func() bool { return !manager.IsRunning() }, 5*time.Second, 10*time.Millisecond,
+		"cancelling the parent context stops monitoring without Stop")
+
+	exited := make(chan struct{})
+	go func() {
+		manager.wg.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the publishers did not exit after the context was cancelled")
+	}
+
+	assert.Error(t, manager.Start(ctx), "a cancelled context can't start a session")
+	assert.False(t, manager.IsRunning())
+}
+
+func TestUiSpectrogramManager_LogsToInjectedLogger(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	var logs syncBuffer
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil,
+		logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC))
+
+	require.NoError(t, manager.Start(t.Context()))
+	session := manager.SessionID()
+	require.NoError(t, manager.Stop())
+
+	messages := logs.sessionIDsByMessage(t)
+	for _, message := range []string{
+		"UI spectrogram monitoring started",
+		"Started UI spectrogram SSE publisher",
+		"stopping UI spectrogram monitoring",
+		"UI spectrogram monitoring stopped",
+	} {
+		assert.Equal(t, session, messages[message], "%q goes to the manager's logger", message)
+	}
+}
+
+func TestUiSpectrogramManager_ConfigFollowsUpdateSettings(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+	conf.Setting().SoundId.UiSpectrogram.Palette = conf.DefaultUiSpectrogramPalette
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+	require.NoError(t, manager.Start(t.Context()))
+	t.Cleanup(func() { _ = manager.Stop() })
+
+	config := manager.Config()
+	assert.True(t, config.Running)
+	assert.Equal(t, conf.DefaultUiSpectrogramPalette, config.Palette)
+	assert.Equal(t, conf.UiSpectrogramWindowSize, config.WindowSize)
+	assert.InDelta(t, conf.UiSpectrogramMaxMsPerColumn, config.MsPerColumn, 1e-9)
+
+	// An in-place change the session hasn't applied isn't reported as in effect
+	conf.Setting().SoundId.UiSpectrogram.Palette = "grayscale"
+	assert.Equal(t, conf.DefaultUiSpectrogramPalette, manager.Config().Palette)
+
+	updated := conf.Setting().SoundId.UiSpectrogram
+	updated.Palette = "grayscale"
+	updated.MsPerColumn = conf.UiSpectrogramMaxMsPerColumn / 2
+	updated.Video.Enabled = true
+	updated.Video.FPS = 24
+	session := manager.SessionID()
+	manager.UpdateSettings(&updated)
+	assert.Equal(t, session, manager.SessionID(), "an in-place update must not restart the session")
+
+	config = manager.Config()
+	assert.True(t, config.Running)
+	assert.Equal(t, "grayscale", config.Palette)
+	assert.InDelta(t, conf.UiSpectrogramMaxMsPerColumn/2, config.MsPerColumn, 0.05)
+	assert.InDelta(t, 1000/config.MsPerColumn, config.ColumnsPerSecond, 1e-9)
+	assert.Equal(t, 24, config.VideoFPS)
+
+	// The API reports the same configuration
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetSpectrogramConfig(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v2/spectrogram/config", http.NoBody), rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var reported myaudio.UiSpectrogramConfig
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reported))
+	assert.Equal(t, config.Palette, reported.Palette)
+	assert.Equal(t, config.SessionID, reported.SessionID)
+}
+
+func TestUiSpectrogramManager_ShutdownTimeout(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	var logs syncBuffer
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil,
+		logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC))
+	manager.SetShutdownTimeout(50 * time.Millisecond)
+	require.NoError(t, manager.Start(t.Context()))
+
+	// A publisher stuck on a slow sink that ignores the stop signal
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	manager.wg.Go(func() { <-release })
+
+	start := time.Now()
+	err := manager.Stop()
+	assert.Less(t, time.Since(start), 5*time.Second, "Stop must give up on the stuck publisher after the timeout")
+	assert.False(t, manager.IsRunning())
+	assert.Contains(t, logs.sessionIDsByMessage(t), "UI spectrogram monitoring shutdown timed out, forcing cleanup")
+
+	require.Error(t, err, "a forced shutdown is reported to the caller")
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategorySystem), enhanced.GetCategory())
+}
+
+func TestUiSpectrogramManager_RecordsShutdownMetrics(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	registry := prometheus.NewRegistry()
+	audioMetrics, err := metrics.NewMyAudioMetrics(registry)
+	require.NoError(t, err)
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, &observability.Metrics{MyAudio: audioMetrics}, nil)
+	const timeout = 100 * time.Millisecond
+	manager.SetShutdownTimeout(timeout)
+
+	// A publisher that finishes right after the stop signal
+	require.NoError(t, manager.Start(t.Context()))
+	require.NoError(t, manager.Stop())
+
+	// One stuck on a slow sink that ignores the stop signal
+	require.NoError(t, manager.Start(t.Context()))
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	manager.wg.Go(func() { <-release })
+	require.Error(t, manager.Stop())
+
+	expected := `
+		# HELP ui_spectrogram_shutdowns_total Total number of UI spectrogram monitoring stops by result (clean, forced after the shutdown timeout)
+		# TYPE ui_spectrogram_shutdowns_total counter
+		ui_spectrogram_shutdowns_total{result="clean"} 1
+		ui_spectrogram_shutdowns_total{result="forced"} 1
+	`
+	assert.NoError(t, testutil.CollectAndCompare(audioMetrics, strings.NewReader(expected), "ui_spectrogram_shutdowns_total"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var histogram *dto.Histogram
+	for _, family := range families {
+		if family.GetName() == "ui_spectrogram_shutdown_duration_seconds" {
+			histogram = family.GetMetric()[0].GetHistogram()
+		}
+	}
+	require.NotNil(t, histogram)
+	assert.Equal(t, uint64(2), histogram.GetSampleCount(), "both stops are timed")
+	assert.GreaterOrEqual(t, histogram.GetSampleSum(), timeout.Seconds(), "the forced stop waited out the timeout")
+	assert.Less(t, histogram.GetSampleSum(), 2*timeout.Seconds(), "the clean stop barely waited")
+}
+
+func TestUiSpectrogramManager_DrainOnStop(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	settings := conf.GetTestSettings()
+	settings.SoundId.UiSpectrogram.DrainOnStop = true
+	conf.SetTestSettings(settings)
+
+	controller, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	openSpectrogramStream(t, ctx, server.URL)
+
+	const frames = 8
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, frames)
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, controller, nil, nil)
+	require.NoError(t, manager.Start(t.Context()))
+	require.NoError(t, manager.WaitUntilReady(ctx))
+
+	// Frames still queued when Stop is called are broadcast rather than left in the channel
+	for i := range frames {
+		spectrogramChan <- myaudio.UiSpectrogramData{
+			Spectrogram: make([]byte, myaudio.UiSpectrogramBins*2),
+			Source:      "mic",
+			Timestamp:   time.Now().Add(time.Duration(i) * time.Millisecond),
+		}
+	}
+	require.NoError(t, manager.Stop())
+
+	assert.Empty(t, spectrogramChan, "the queue is drained before Stop returns")
+	assert.Equal(t, uint64(frames), manager.Stats().FramesBroadcast)
+}
+
+func TestUiSpectrogramManager_RestartKeepsFramesFlowing(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	scanner := openSpectrogramStream(t, ctx, server.URL)
+
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 4), nil, controller, nil, nil)
+	manager.SetOverflowStrategy(conf.UiSpectrogramOverflowBlock)
+	require.NoError(t, manager.Start(t.Context()))
+	t.Cleanup(func() { _ = manager.Stop() })
+	require.NoError(t, manager.WaitUntilReady(ctx))
+
+	// Read the timestamps of the frames the client receives
+	const frames = 40
+	received := make(chan time.Time, frames*2)
+	go func() {
+		for scanner.Scan() {
+			if scanner.Text() != "event: ui_spectrogram" || !scanner.Scan() {
+				continue
+			}
+			var frame myaudio.UiSpectrogramData
+			if json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data: ")), &frame) == nil {
+				received <- frame.Timestamp
+			}
+		}
+	}()
+
+	// Frames keep coming while the manager restarts halfway through
+	base := time.Now()
+	for i := range frames {
+		if i == frames/2 {
+			require.NoError(t, manager.Restart())
+		}
+		frame := myaudio.UiSpectrogramData{
+			Spectrogram: make([]byte, myaudio.UiSpectrogramBins*2),
+			Source:      "mic",
+			Timestamp:   base.Add(time.Duration(i) * 100 * time.Millisecond),
+		}
+		require.True(t, manager.Send(&frame, ctx.Done()))
+		time.Sleep(time.Millisecond)
+	}
+
+	seen := make(map[time.Time]int)
+	for len(seen) < frames {
+		select {
+		case ts := <-received:
+			seen[ts.UTC()]++
+		case <-ctx.Done():
+			require.Failf(t, "frames were lost across the restart", "received %d of %d frames", len(seen), frames)
+		}
+	}
+	for ts, count := range seen {
+		assert.Equal(t, 1, count, "frame %s was broadcast more than once", ts)
+	}
+	assert.Empty(t, received, "no frame is broadcast twice")
+}
+
+func TestUiSpectrogramManager_SetBufferSizeUnderLoad(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	scanner := openSpectrogramStream(t, ctx, server.URL)
+
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 8), nil, controller, nil, nil)
+	manager.SetOverflowStrategy(conf.UiSpectrogramOverflowBlock)
+	manager.depthSampleInterval = 10 * time.Millisecond
+	require.NoError(t, manager.Start(t.Context()))
+	t.Cleanup(func() { _ = manager.Stop() })
+	require.NoError(t, manager.WaitUntilReady(ctx))
+
+	err := manager.SetBufferSize(minUiSpectrogramBufferSize - 1)
+	require.Error(t, err, "a buffer below the minimum is rejected")
+
+	const frames = 40
+	received := make(chan time.Time, frames*2)
+	go func() {
+		for scanner.Scan() {
+			if scanner.Text() != "event: ui_spectrogram" || !scanner.Scan() {
+				continue
+			}
+			var frame myaudio.UiSpectrogramData
+			if json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data: ")), &frame) == nil {
+				received <- frame.Timestamp
+			}
+		}
+	}()
+
+	// The buffer grows while frames keep coming
+	base := time.Now()
+	for i := range frames {
+		if i == frames/2 {
+			require.NoError(t, manager.SetBufferSize(64))
+		}
+		frame := myaudio.UiSpectrogramData{
+			Spectrogram: make([]byte, myaudio.UiSpectrogramBins*2),
+			Source:      "mic",
+			Timestamp:   base.Add(time.Duration(i) * 100 * time.Millisecond),
+		}
+		require.True(t, manager.Send(&frame, ctx.Done()))
+		time.Sleep(time.Millisecond)
+	}
+
+	seen := make(map[time.Time]int)
+	for len(seen) < frames {
+		select {
+		case ts := <-received:
+			seen[ts.UTC()]++
+		case <-ctx.Done():
+			require.Failf(t, "frames were lost across the resize", "received %d of %d frames", len(seen), frames)
+		}
+	}
+	for ts, count := range seen {
+		assert.Equal(t, 1, count, "frame %s was broadcast more than once", ts)
+	}
+	assert.Zero(t, manager.Stats().FramesOverflowed, "growing the buffer discards nothing")
+	assert.Eventually(t, func() bool { return manager.Stats().ChannelCapacity == 64 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestMoveUiSpectrogramFrames_KeepsNewestWhenShrinking(t *testing.T) {
+	from := make(chan myaudio.UiSpectrogramData, 4)
+	for i := range 4 {
+		from <- myaudio.UiSpectrogramData{Timestamp: time.Unix(int64(i), 0)}
+	}
+	to := make(chan myaudio.UiSpectrogramData, 2)
+
+	moved, discarded := moveUiSpectrogramFrames(from, to)
+	assert.Equal(t, 2, moved)
+	assert.Equal(t, 2, discarded)
+	assert.Equal(t, time.Unix(2, 0), (<-to).Timestamp)
+	assert.Equal(t, time.Unix(3, 0), (<-to).Timestamp)
+}
+
+func TestUiSpectrogramManager_SamplesChannelDepth(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	audioMetrics, err := metrics.NewMyAudioMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 8)
+	// Without an API there's no publisher, so queued frames stay in the channel
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, nil, &observability.Metrics{MyAudio: audioMetrics}, nil)
+	manager.depthSampleInterval = 10 * time.Millisecond
+	require.NoError(t, manager.Start(t.Context()))
+
+	for range 3 {
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic"}
+	}
+	require.Eventually(t, func() bool { return manager.Stats().ChannelDepth == 3 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 8, manager.Stats().ChannelCapacity)
+
+	expected := `
+		# HELP ui_spectrogram_channel_capacity Frames the UI spectrogram channel can hold
+		# TYPE ui_spectrogram_channel_capacity gauge
+		ui_spectrogram_channel_capacity 8
+		# HELP ui_spectrogram_channel_depth Frames queued in the UI spectrogram channel when last sampled
+		# TYPE ui_spectrogram_channel_depth gauge
+		ui_spectrogram_channel_depth 3
+	`
+	assert.NoError(t, testutil.CollectAndCompare(audioMetrics, strings.NewReader(expected),
+		"ui_spectrogram_channel_depth", "ui_spectrogram_channel_capacity"))
+
+	// The sampler is part of the session and stops with it
+	require.NoError(t, manager.Stop())
+	spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic"}
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 3, manager.Stats().ChannelDepth, "a stopped manager samples no more")
+}
+
+func TestUiSpectrogramManager_StopAggregatesPublisherErrors(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+	manager.SetShutdownTimeout(50 * time.Millisecond)
+
+	// A publisher that fails to finish its output when told to stop
+	failOnStop := func(message string) {
+		done, shutdownErrs := manager.doneChan, manager.shutdownErrs
+		manager.wg.Go(func() {
+			<-done
+			shutdownErrs.add(errors.Newf("%s", message).
+				Component("analysis.uispectrogram").
+				Category(errors.CategoryFileIO).
+				Build())
+		})
+	}
+
+	require.NoError(t, manager.Start(t.Context()))
+	failOnStop("video file not finished")
+	err := manager.Stop()
+	require.Error(t, err)
+	assert.Equal(t, "video file not finished", err.Error(), "a single failure is returned as it is")
+
+	require.NoError(t, manager.Start(t.Context()))
+	assert.NoError(t, manager.Stop(), "errors of an earlier session aren't reported again")
+
+	require.NoError(t, manager.Start(t.Context()))
+	failOnStop("first sink failed")
+	failOnStop("second sink failed")
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	manager.wg.Go(func() { <-release })
+
+	err = manager.Stop()
+	var multi *errors.MultiError
+	require.ErrorAs(t, err, &multi, "every publisher failure is reported along with the timeout")
+	require.Equal(t, 3, multi.Len())
+	assert.ElementsMatch(t, []string{"first sink failed", "second sink failed"},
+		[]string{multi.Unwrap()[0].Error(), multi.Unwrap()[1].Error()})
+	assert.True(t, errors.IsCategory(multi.Unwrap()[2], errors.CategorySystem), "the timeout comes last")
+	assert.True(t, errors.IsCategory(err, errors.CategoryFileIO))
+}
+
+func TestUiSpectrogramManager_RestartStopsOnForcedShutdown(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+	manager.SetShutdownTimeout(20 * time.Millisecond)
+	require.NoError(t, manager.Start(t.Context()))
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	manager.wg.Go(func() { <-release })
+
+	require.Error(t, manager.Restart())
+	assert.False(t, manager.IsRunning(), "no new session starts beside goroutines that didn't stop")
+}
+
+func TestUiSpectrogramManager_IsRunningDuringStartStop(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+
+	stop := make(chan struct{})
+	var probes sync.WaitGroup
+	for range 4 {
+		probes.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_ = manager.IsRunning()
+				}
+			}
+		})
+	}
+
+	for range 20 {
+		require.NoError(t, manager.Start(t.Context()))
+		assert.True(t, manager.IsRunning())
+		require.NoError(t, manager.Stop())
+		assert.False(t, manager.IsRunning())
+	}
+	close(stop)
+	probes.Wait()
+}
+
+func TestUiSpectrogramManager_SendDropOldestNeverBlocks(t *testing.T) {
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 4)
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, nil, nil, nil)
+	manager.SetOverflowStrategy(conf.UiSpectrogramOverflowDropOldest)
+
+	// Nothing consumes the channel, so every frame past the first four has to evict one
+	const frames = 100
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range frames {
+			frame := myaudio.UiSpectrogramData{Source: "mic", MsPerColumn: float64(i)}
+			assert.True(t, manager.Send(&frame, nil))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "the producer blocked on a full channel under drop-oldest")
+	}
+
+	require.Len(t, spectrogramChan, 4)
+	assert.InDelta(t, frames-4, (<-spectrogramChan).MsPerColumn, 0, "the newest frames are kept")
+	assert.Equal(t, uint64(frames-4), manager.Stats().FramesOverflowed)
+
+	// Under block the producer waits for room instead
+	manager.SetOverflowStrategy(conf.UiSpectrogramOverflowBlock)
+	spectrogramChan <- myaudio.UiSpectrogramData{}
+	stop := make(chan struct{})
+	sent := make(chan bool)
+	go func() { sent <- manager.Send(&myaudio.UiSpectrogramData{}, stop) }()
+	select {
+	case <-sent:
+		require.Fail(t, "block must wait while the channel is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(stop)
+	assert.False(t, <-sent)
+	assert.Equal(t, uint64(frames-4), manager.Stats().FramesOverflowed, "blocking drops nothing")
+}
+
+func TestUiSpectrogramManager_PauseKeepsRunning(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 1)
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, controller, nil, nil)
+	require.NoError(t, manager.Start(t.Context()))
+	t.Cleanup(func() { _ = manager.Stop() })
+
+	manager.Pause()
+	assert.True(t, manager.IsRunning())
+	assert.True(t, manager.IsPaused())
+
+	// The publisher keeps draining the channel while paused
+	for range 3 {
+		select {
+		case spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic"}:
+		case <-time.After(2 * time.Second):
+			require.Fail(t, "the paused publisher stopped draining the channel")
+		}
+	}
+
+	manager.Resume()
+	assert.False(t, manager.IsPaused())
+	assert.True(t, manager.IsRunning())
+}
+
+func TestUiSpectrogramManager_NilProcessorSkipsMQTT(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	settings := conf.GetTestSettings()
+	settings.Realtime.MQTT.Enabled = true
+	settings.SoundId.UiSpectrogram.MQTTEnabled = true
+	conf.SetTestSettings(settings)
+
+	controller, _ := newSpectrogramStreamServer(t)
+	var logs syncBuffer
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil,
+		logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC))
+
+	require.NoError(t, manager.Start(t.Context()), "a nil processor doesn't prevent monitoring")
+	assert.True(t, manager.IsRunning())
+	require.NoError(t, manager.Stop())
+
+	messages := logs.sessionIDsByMessage(t)
+	assert.Contains(t, messages, "UI spectrogram MQTT summaries are enabled but no processor is available, skipping them")
+	assert.Contains(t, messages, "Started UI spectrogram SSE publisher", "the stream still runs")
+	assert.NotContains(t, messages, "Started UI spectrogram MQTT publisher")
+}
+
+func TestUiSpectrogramManager_WaitUntilReady(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	settings := conf.GetTestSettings()
+	settings.SoundId.UiSpectrogram.RecentFrames = 4
+	conf.SetTestSettings(settings)
+
+	controller, _ := newSpectrogramStreamServer(t)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 1)
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, controller, nil, nil)
+
+	err := manager.WaitUntilReady(t.Context())
+	require.Error(t, err, "a stopped manager has no publisher to wait for")
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategoryState), enhanced.GetCategory())
+
+	require.NoError(t, manager.Start(t.Context()))
+	t.Cleanup(func() { _ = manager.Stop() })
+
+	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, manager.WaitUntilReady(ctx))
+
+	// The first frame sent after the publisher is ready reaches it
+	spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{1, 2, 3}}
+	require.Eventually(t, func() bool { return len(manager.RecentFrames()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "mic", manager.RecentFrames()[0].Source)
+
+	cancelled, cancelNow := context.WithCancel(t.Context())
+	cancelNow()
+	assert.NoError(t, manager.WaitUntilReady(cancelled), "a ready publisher doesn't wait on the context")
+}
+
+func TestUiSpectrogramManager_WaitUntilReadyRespectsContext(t *testing.T) {
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, nil, nil, nil)
+	// A running session whose publisher never gets ready
+	manager.isRunning.Store(true)
+	manager.ready = make(chan struct{})
+	manager.doneChan = make(chan struct{})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	err := manager.WaitUntilReady(ctx)
+	require.Error(t, err)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategoryTimeout), enhanced.GetCategory())
+}
+
+func TestUiSpectrogramManager_FlushSendsHeldBatch(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	settings := conf.GetTestSettings()
+	settings.SoundId.UiSpectrogram.BatchSize = 10
+	settings.SoundId.UiSpectrogram.BatchInterval = 60000
+	conf.SetTestSettings(settings)
+
+	controller, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	openSpectrogramStream(t, ctx, server.URL)
+
+	const frames = 4
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, frames)
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, controller, nil, nil)
+	require.NoError(t, manager.Flush(ctx), "a stopped manager has nothing to flush")
+	require.NoError(t, manager.Start(t.Context()))
+	t.Cleanup(func() { _ = manager.Stop() })
+	require.NoError(t, manager.WaitUntilReady(ctx))
+	require.NoError(t, manager.Flush(ctx), "flushing with nothing held is a no-op")
+	assert.Zero(t, manager.Stats().FramesBroadcast)
+
+	// Too few frames to fill a batch, which would otherwise wait a minute
+	for i := range frames {
+		spectrogramChan <- myaudio.UiSpectrogramData{
+			Spectrogram: make([]byte, myaudio.UiSpectrogramBins*2),
+			Source:      "mic",
+			Timestamp:   time.Now().Add(time.Duration(i) * time.Millisecond),
+		}
+	}
+	require.NoError(t, manager.Flush(ctx))
+	assert.Equal(t, uint64(frames), manager.Stats().FramesBroadcast, "the held frames are broadcast before Flush returns")
+}
+
+func TestUiSpectrogramManager_FlushRespectsContext(t *testing.T) {
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, &apiv2.Controller{}, nil, nil)
+	// A running session whose publisher never serves the request
+	manager.isRunning.Store(true)
+	manager.flushRequests = make(chan chan struct{})
+	manager.doneChan = make(chan struct{})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	err := manager.Flush(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategoryTimeout), enhanced.GetCategory())
+}
+
+func TestUiSpectrogramManager_StartWithoutChannel(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	var logs syncBuffer
+	manager := NewUiSpectrogramManager(nil, nil, controller, nil,
+		logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC))
+
+	err := manager.Start(t.Context())
+	require.Error(t, err, "a manager without a channel would start a feed no frame ever reaches")
+	var enhanced *errors.EnhancedError
+	require.True(t, errors.As(err, &enhanced))
+	assert.Equal(t, string(errors.CategoryConfiguration), enhanced.GetCategory())
+	assert.Equal(t, errors.SeverityFatal, errors.SeverityOf(err))
+	assert.False(t, manager.IsRunning())
+	assert.Empty(t, manager.SessionID())
+	assert.Contains(t, logs.String(), "UI spectrogram manager has no spectrogram channel, not starting monitoring")
+	assert.NoError(t, manager.Stop(), "stopping a manager that never started is a no-op")
+}
+
+func TestUiSpectrogramManager_RecordsErrorMetrics(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	errorMetrics, err := metrics.NewErrorMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(nil, nil, controller, &observability.Metrics{Errors: errorMetrics}, nil)
+	require.Error(t, manager.Start(t.Context()))
+
+	// A broadcast failure of the publisher, counted under the component that reported it
+	broadcaster := &fakeSpectrogramBroadcaster{err: errors.Newf("frame rejected").
+		Component("api").
+		Category(errors.CategoryValidation).
+		Build()}
+	broadcaster.clients.Store(1)
+	frame := myaudio.UiSpectrogramData{Source: "mic"}
+	publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &manager.stats, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		newUiSpectrogramErrorThrottle(time.Minute), GetLogger())
+
+	assert.InDelta(t, 1, testutil.ToFloat64(errorMetrics.ErrorsTotal.WithLabelValues("analysis.uispectrogram", string(errors.CategoryConfiguration))), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(errorMetrics.ErrorsTotal.WithLabelValues("api", string(errors.CategoryValidation))), 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(errorMetrics))
+}
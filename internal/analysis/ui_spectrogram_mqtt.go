@@ -0,0 +1,181 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// uiSpectrogramMQTTQueueSize bounds the summaries waiting on a slow broker; more are dropped
+	uiSpectrogramMQTTQueueSize = 16
+	// uiSpectrogramMQTTPublishTimeout is the timeout for publishing one summary
+	uiSpectrogramMQTTPublishTimeout = 5 * time.Second
+)
+
+// uiSpectrogramSummary is the downsampled metadata of a spectrogram frame published to MQTT.
+type uiSpectrogramSummary struct {
+	Source    string                      `json:"source,omitempty"`
+	Timestamp time.Time                   `json:"timestamp"`
+	PeakHz    float64                     `json:"peakHz"`             // Frequency of the loudest bin in Hz
+	PeakLevel int                         `json:"peakLevel"`          // Level of the loudest bin, 0-255
+	RMS       float64                     `json:"rms"`                // Root mean square of all bin levels, normalized to 0-1
+	Features  *myaudio.UiSpectralFeatures `json:"features,omitempty"` // Spectral centroid and bandwidth, when enabled
+}
+
+// summarizeUiSpectrogramFrame computes the MQTT summary of a frame. ok is false for an empty frame.
+func summarizeUiSpectrogramFrame(frame *myaudio.UiSpectrogramData) (summary uiSpectrogramSummary, ok bool) {
+	if len(frame.Spectrogram) == 0 {
+		return summary, false
+	}
+
+	bins := frame.ColumnBins()
+	peakBin, peak := 0, byte(0)
+	var sumSquares float64
+	for i, value := range frame.Spectrogram {
+		if value > peak {
+			peakBin, peak = i%bins, value
+		}
+		level := float64(value) / math.MaxUint8
+		sumSquares += level * level
+	}
+
+	return uiSpectrogramSummary{
+		Source:    frame.Source,
+		Timestamp: frame.Timestamp,
+		PeakHz:    frame.MinHz + float64(peakBin)*frame.ColumnBinHz(),
+		PeakLevel: int(peak),
+		RMS:       roundToDecimalPlaces(math.Sqrt(sumSquares/float64(len(frame.Spectrogram))), 3),
+		Features:  frame.Features,
+	}, true
+}
+
+// uiSpectrogramMQTTTopic returns the topic spectrogram summaries are published to.
+func uiSpectrogramMQTTTopic(settings *conf.Settings) string {
+	if topic := settings.SoundId.UiSpectrogram.MQTTTopic; topic != "" {
+		return topic
+	}
+	return fmt.Sprintf("%s/spectrogram", strings.TrimSuffix(settings.Realtime.MQTT.Topic, "/"))
+}
+
+// uiSpectrogramMQTTPublisher publishes spectrogram frame summaries to the processor's MQTT
+// client, at most one per source per interval. Frames are offered from the SSE publisher's
+// loop and published from a separate goroutine so a slow broker never stalls the stream.
+type uiSpectrogramMQTTPublisher struct {
+	proc     *processor.Processor
+	topic    string
+	interval time.Duration
+	queue    chan uiSpectrogramSummary
+	last     map[string]time.Time // Timestamp of each source's last queued summary, owned by offer's caller
+}
+
+// startUiSpectrogramMQTTPublisher starts publishing spectrogram summaries until quitChan is closed.
+// It returns nil when summaries are disabled or there is no processor to publish through,
+// which offer treats as a no-op.
+func startUiSpectrogramMQTTPublisher(wg *sync.WaitGroup, quitChan <-chan struct{}, proc *processor.Processor, settings *conf.Settings, log logger.Logger) *uiSpectrogramMQTTPublisher {
+	if !settings.Realtime.MQTT.Enabled || !settings.SoundId.UiSpectrogram.MQTTEnabled {
+		return nil
+	}
+	if proc == nil {
+		log.Warn("UI spectrogram MQTT summaries are enabled but no processor is available, skipping them")
+		return nil
+	}
+
+	p := &uiSpectrogramMQTTPublisher{
+		proc:     proc,
+		topic:    uiSpectrogramMQTTTopic(settings),
+		interval: time.Duration(settings.SoundId.UiSpectrogram.MQTTInterval) * time.Second,
+		queue:    make(chan uiSpectrogramSummary, uiSpectrogramMQTTQueueSize),
+		last:     make(map[string]time.Time),
+	}
+
+	wg.Go(func() {
+		log.Info("Started UI spectrogram MQTT publisher", logger.String("topic", p.topic))
+
+		connected := true
+		for {
+			select {
+			case <-quitChan:
+				log.Info("Stopping UI spectrogram MQTT publisher")
+				return
+			case summary := <-p.queue:
+				err := p.publish(summary)
+				// The MQTT client reconnects on its own; log only the transitions so an
+				// unreachable broker doesn't produce one error per summary
+				switch {
+				case err != nil && connected:
+					connected = false
+					log.Warn("UI spectrogram MQTT publishing failed, skipping summaries until the broker is reachable",
+						logger.Error(err),
+						logger.String("topic", p.topic))
+				case err == nil && !connected:
+					connected = true
+					log.Info("UI spectrogram MQTT publishing resumed", logger.String("topic", p.topic))
+				}
+			}
+		}
+	})
+
+	return p
+}
+
+// offer queues a summary of the frame when the source's interval has passed. It never blocks,
+// and a nil publisher ignores the frame.
+func (p *uiSpectrogramMQTTPublisher) offer(frame *myaudio.UiSpectrogramData) {
+	if p == nil {
+		return
+	}
+	if last, ok := p.last[frame.Source]; ok && frame.Timestamp.Sub(last) < p.interval {
+		return
+	}
+	summary, ok := summarizeUiSpectrogramFrame(frame)
+	if !ok {
+		return
+	}
+
+	select {
+	case p.queue <- summary:
+		p.last[frame.Source] = frame.Timestamp
+	default:
+		// Broker is backed up; try again with the source's next frame
+	}
+}
+
+// publish sends one summary through the processor's current MQTT client, so a client
+// replaced by an MQTT reconfiguration is picked up without restarting the publisher.
+func (p *uiSpectrogramMQTTPublisher) publish(summary uiSpectrogramSummary) error {
+	payload, err := json.Marshal(summary)
+	if err != nil {
+		return errors.New(err).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryMQTTPublish).
+			Context("operation", "marshal_spectrogram_summary").
+			Context("source", summary.Source).
+			Build()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), uiSpectrogramMQTTPublishTimeout)
+	defer cancel()
+
+	if err := p.proc.PublishMQTT(ctx, p.topic, string(payload)); err != nil {
+		return errors.New(err).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryMQTTPublish).
+			Context("operation", "publish_spectrogram_summary").
+			Context("topic", p.topic).
+			Context("source", summary.Source).
+			Context("retryable", true).
+			Build()
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+type publishedMessage struct {
+	topic   string
+	payload string
+}
+
+func TestUiSpectrogramMQTTPublisher_PublishesSummaries(t *testing.T) {
+	t.Parallel()
+
+	published := make(chan publishedMessage, 4)
+	var brokerDown sync.Once
+	proc := createMockProcessor(func(_ context.Context, topic, payload string) error {
+		failed := false
+		brokerDown.Do(func() { failed = true })
+		if failed {
+			return errors.New("broker unreachable")
+		}
+		published <- publishedMessage{topic, payload}
+		return nil
+	})
+	proc.Settings.SoundId.UiSpectrogram.MQTTEnabled = true
+	proc.Settings.SoundId.UiSpectrogram.MQTTInterval = 5
+
+	quit := make(chan struct{})
+	var wg sync.WaitGroup
+	p := startUiSpectrogramMQTTPublisher(&wg, quit, proc, proc.Settings, GetLogger())
+	require.NotNil(t, p)
+	t.Cleanup(func() {
+		close(quit)
+		wg.Wait()
+	})
+
+	begin := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	frame := func(at time.Time) *myaudio.UiSpectrogramData {
+		spectrogram := make([]byte, 2*myaudio.UiSpectrogramBins)
+		spectrogram[myaudio.UiSpectrogramBins+10] = 255
+		return &myaudio.UiSpectrogramData{Spectrogram: spectrogram, Source: "mic", Timestamp: at}
+	}
+
+	// The first summary hits an unreachable broker; the publisher keeps going and later
+	// summaries are delivered once it is reachable again
+	p.offer(frame(begin))
+	require.Eventually(t, func() bool { return len(p.queue) == 0 }, time.Second, 10*time.Millisecond)
+	p.offer(frame(begin.Add(5 * time.Second)))
+	p.offer(frame(begin.Add(6 * time.Second))) // Inside the interval, not published
+
+	select {
+	case msg := <-published:
+		assert.Equal(t, "birdnet/test/spectrogram", msg.topic)
+
+		var summary uiSpectrogramSummary
+		require.NoError(t, json.Unmarshal([]byte(msg.payload), &summary))
+		assert.Equal(t, "mic", summary.Source)
+		assert.True(t, summary.Timestamp.Equal(begin.Add(5*time.Second)))
+		assert.InDelta(t, 10*float64(conf.SampleRate)/512, summary.PeakHz, 0.001)
+		assert.Equal(t, 255, summary.PeakLevel)
+		assert.InDelta(t, 0.044, summary.RMS, 0.001)
+	case <-time.After(time.Second):
+		t.Fatal("expected a spectrogram summary to be published")
+	}
+
+	select {
+	case msg := <-published:
+		t.Fatalf("unexpected summary inside the interval: %s", msg.payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestUiSpectrogramMQTTPublisher_Disabled(t *testing.T) {
+	t.Parallel()
+
+	proc := createMockProcessor(nil)
+	var wg sync.WaitGroup
+	assert.Nil(t, startUiSpectrogramMQTTPublisher(&wg, make(chan struct{}), proc, proc.Settings, GetLogger()))
+
+	var p *uiSpectrogramMQTTPublisher
+	assert.NotPanics(t, func() { p.offer(&myaudio.UiSpectrogramData{Spectrogram: []byte{1}}) })
+}
+
+func TestUiSpectrogramMQTTTopic(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Realtime.MQTT.Topic = "birdnet/"
+	assert.Equal(t, "birdnet/spectrogram", uiSpectrogramMQTTTopic(settings))
+
+	settings.SoundId.UiSpectrogram.MQTTTopic = "home/garden/spectrum"
+	assert.Equal(t, "home/garden/spectrum", uiSpectrogramMQTTTopic(settings))
+}
@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// maxUiSpectrogramRecentFrames caps the frames kept for newly connected clients, since each
+// one is sent to every client when it connects
+const maxUiSpectrogramRecentFrames = 600
+
+// uiSpectrogramFrameRing keeps the most recent frames of all sources so a client connecting
+// mid-stream can be shown them right away. The publisher adds frames while HTTP handlers read
+// them, so it is safe for concurrent use.
+type uiSpectrogramFrameRing struct {
+	mu   sync.Mutex
+	buf  []myaudio.UiSpectrogramData // Ring storage, full once it reaches its capacity
+	next int                         // Index the next frame is written to once full
+}
+
+// newUiSpectrogramFrameRing returns a ring keeping up to depth frames, capped at
+// maxUiSpectrogramRecentFrames, or nil when depth is not positive.
+func newUiSpectrogramFrameRing(depth int) *uiSpectrogramFrameRing {
+	if depth <= 0 {
+		return nil
+	}
+	return &uiSpectrogramFrameRing{buf: make([]myaudio.UiSpectrogramData, 0, min(depth, maxUiSpectrogramRecentFrames))}
+}
+
+// add keeps a frame, evicting the oldest when the ring is full. A nil ring ignores it.
+func (r *uiSpectrogramFrameRing) add(frame *myaudio.UiSpectrogramData) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.buf) < cap(r.buf) {
+		r.buf = append(r.buf, *frame)
+		return
+	}
+	r.buf[r.next] = *frame
+	r.next = (r.next + 1) % len(r.buf)
+}
+
+// frames returns a copy of the kept frames, oldest first. A nil ring has none.
+func (r *uiSpectrogramFrameRing) frames() []myaudio.UiSpectrogramData {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]myaudio.UiSpectrogramData, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
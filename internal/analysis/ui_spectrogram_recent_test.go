@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// recentFrameSources returns the sources of the ring's frames, oldest first
+func recentFrameSources(r *uiSpectrogramFrameRing) []string {
+	var sources []string
+	for _, frame := range r.frames() {
+		sources = append(sources, frame.Source)
+	}
+	return sources
+}
+
+func TestUiSpectrogramFrameRing_EvictsOldest(t *testing.T) {
+	ring := newUiSpectrogramFrameRing(3)
+	for _, source := range []string{"a", "b"} {
+		ring.add(&myaudio.UiSpectrogramData{Source: source})
+	}
+	assert.Equal(t, []string{"a", "b"}, recentFrameSources(ring))
+
+	for _, source := range []string{"c", "d", "e"} {
+		ring.add(&myaudio.UiSpectrogramData{Source: source})
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, recentFrameSources(ring), "the newest frames are kept, oldest first")
+
+	assert.Nil(t, newUiSpectrogramFrameRing(0), "a depth of 0 disables the ring")
+	assert.Empty(t, newUiSpectrogramFrameRing(0).frames())
+	assert.Equal(t, maxUiSpectrogramRecentFrames, cap(newUiSpectrogramFrameRing(1_000_000).buf))
+}
+
+func TestPublishUiSpectrogramFrame_KeepsRecentFramesWithoutClients(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	ring := newUiSpectrogramFrameRing(2)
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+
+	frame := myaudio.UiSpectrogramData{Source: "quiet"}
+	publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, ring, errorLog, GetLogger())
+
+	assert.Empty(t, broadcaster.broadcast(), "nobody is watching")
+	require.Equal(t, []string{"quiet"}, recentFrameSources(ring), "the frame is kept for the next client")
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestHandleReconfigureUiSpectrogram_TogglesManager(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+
+	settings := conf.GetTestSettings()
+	conf.SetTestSettings(settings)
+
+	cm := &ControlMonitor{ctx: t.Context(), spectrogramChan: make(chan myaudio.UiSpectrogramData, 1)}
+
+	// Disabled with no manager yet: nothing to stop, and nothing gets created
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	assert.Nil(t, cm.uiSpectrogramManager)
+
+	settings.SoundId.Enabled = true
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	require.NotNil(t, cm.uiSpectrogramManager)
+	assert.True(t, cm.uiSpectrogramManager.IsRunning(), "enabling must start the manager")
+
+	manager := cm.uiSpectrogramManager
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	assert.Same(t, manager, cm.uiSpectrogramManager, "repeated signals must reuse the manager")
+	assert.True(t, manager.IsRunning())
+
+	settings.SoundId.Enabled = false
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	assert.False(t, manager.IsRunning(), "disabling must stop the manager")
+
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	assert.False(t, manager.IsRunning())
+
+	settings.SoundId.Enabled = true
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	assert.True(t, manager.IsRunning(), "the manager must start again after being stopped")
+	require.NoError(t, manager.Stop())
+}
+
+func TestControlMonitorStop_ClearsActiveUiSpectrogramManager(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+
+	settings := conf.GetTestSettings()
+	settings.SoundId.Enabled = true
+	conf.SetTestSettings(settings)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cm := &ControlMonitor{ctx: ctx, cancel: cancel, spectrogramChan: make(chan myaudio.UiSpectrogramData, 1)}
+	cm.handleControlSignal("reconfigure_ui_spectrogram")
+	require.NotNil(t, cm.uiSpectrogramManager)
+	assert.Same(t, cm.uiSpectrogramManager, activeUiSpectrogramManager.Load())
+
+	cm.Stop()
+	assert.False(t, cm.uiSpectrogramManager.IsRunning())
+	assert.Nil(t, activeUiSpectrogramManager.Load(), "the stopped manager must no longer be active")
+}
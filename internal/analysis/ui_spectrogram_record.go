@@ -0,0 +1,289 @@
+package analysis
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// uiSpectrogramRecordQueueSize bounds the frames waiting on a slow disk; more are dropped
+	uiSpectrogramRecordQueueSize = 256
+	// uiSpectrogramRecordSegments is how many files the size limit is split over, so rolling
+	// over deletes the oldest part of the window rather than all of it
+	uiSpectrogramRecordSegments = 8
+	// uiSpectrogramRecordFlushInterval is how often buffered frames are written out, so little
+	// is lost when the process dies
+	uiSpectrogramRecordFlushInterval = time.Second
+	// uiSpectrogramRecordFileTimeLayout is the start timestamp in each file name, which sorts
+	// the files oldest first by name
+	uiSpectrogramRecordFileTimeLayout = "20060102T150405.000000000"
+	uiSpectrogramRecordFilePrefix     = "spectrogram-"
+	uiSpectrogramRecordFileExt        = ".jsonl"
+)
+
+// uiSpectrogramRecordFile is one file of recorded frames in the record directory.
+type uiSpectrogramRecordFile struct {
+	path string
+	size int64
+}
+
+// uiSpectrogramFrameRecorder keeps a rolling window of the most recent frames on disk, for
+// looking back at what the spectrogram showed around a missed detection. Frames are written
+// as JSON lines to files in a directory, a new file started each time one reaches its share of
+// the size limit and the oldest files deleted once the directory exceeds it. Frames are offered
+// from the SSE publisher's loop and written from a separate goroutine, so a slow disk never
+// stalls the stream.
+type uiSpectrogramFrameRecorder struct {
+	dir          string
+	maxBytes     int64 // Most bytes kept in dir over all files
+	segmentBytes int64 // Size after which a new file is started
+	now          func() time.Time
+	log          logger.Logger
+
+	queue   chan myaudio.UiSpectrogramData
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	dropped atomic.Uint64 // Frames not recorded because the queue was full or writing failed
+	err     error         // First error writing frames, returned by Stop
+
+	// Owned by the recording goroutine
+	files  []uiSpectrogramRecordFile // Files in dir, oldest first; the last is the open one while file is set
+	total  int64                     // Size of files
+	file   *os.File
+	writer *bufio.Writer
+	seq    int
+}
+
+// newUiSpectrogramFrameRecorder returns a recorder writing to the configured record directory,
+// which is created if missing, or nil when no directory is configured, which offer treats as a
+// no-op. Files a previous recorder left in the directory count toward the size limit.
+func newUiSpectrogramFrameRecorder(settings *conf.UiSpectrogramSettings, log logger.Logger) (*uiSpectrogramFrameRecorder, error) {
+	if settings.SpectrogramRecordDir == "" {
+		return nil, nil
+	}
+	if log == nil {
+		log = GetLogger()
+	}
+	maxBytes := settings.SpectrogramRecordMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = conf.DefaultUiSpectrogramRecordMaxBytes
+	}
+
+	dir := settings.SpectrogramRecordDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, uiSpectrogramRecordError(err, "create_record_dir", dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, uiSpectrogramRecordError(err, "read_record_dir", dir)
+	}
+
+	r := &uiSpectrogramFrameRecorder{
+		dir:          dir,
+		maxBytes:     maxBytes,
+		segmentBytes: max(maxBytes/uiSpectrogramRecordSegments, 1),
+		now:          time.Now,
+		log:          log,
+		queue:        make(chan myaudio.UiSpectrogramData, uiSpectrogramRecordQueueSize),
+		quit:         make(chan struct{}),
+	}
+	// ReadDir sorts by name, which is oldest first for the recorder's files
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, uiSpectrogramRecordFilePrefix) || !strings.HasSuffix(name, uiSpectrogramRecordFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Deleted since it was listed
+		}
+		r.files = append(r.files, uiSpectrogramRecordFile{path: filepath.Join(dir, name), size: info.Size()})
+		r.total += info.Size()
+	}
+	return r, nil
+}
+
+// Start starts recording offered frames. A nil recorder does nothing.
+func (r *uiSpectrogramFrameRecorder) Start() {
+	if r == nil {
+		return
+	}
+	r.wg.Go(func() {
+		r.log.Info("Started UI spectrogram frame recorder",
+			logger.String("dir", r.dir),
+			logger.Int64("max_bytes", r.maxBytes))
+		// The limit may have been lowered since the files were written
+		r.enforceLimit()
+
+		ticker := time.NewTicker(uiSpectrogramRecordFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.quit:
+				r.drain()
+				r.closeFile()
+				r.log.Info("Stopped UI spectrogram frame recorder",
+					logger.Uint64("frames_dropped", r.dropped.Load()))
+				return
+			case <-ticker.C:
+				r.flush()
+			case frame := <-r.queue:
+				r.record(&frame)
+			}
+		}
+	})
+}
+
+// Stop records the frames still queued, writes out the open file and stops recording. It
+// returns the first error writing frames, if any. A nil recorder does nothing.
+func (r *uiSpectrogramFrameRecorder) Stop() error {
+	if r == nil {
+		return nil
+	}
+	close(r.quit)
+	r.wg.Wait()
+	return r.err
+}
+
+// offer queues a frame for recording. It never blocks, and a nil recorder ignores the frame.
+func (r *uiSpectrogramFrameRecorder) offer(frame *myaudio.UiSpectrogramData) {
+	if r == nil {
+		return
+	}
+	select {
+	case r.queue <- *frame:
+	default:
+		r.dropped.Add(1) // Disk is backed up; better a gap in the record than a stalled stream
+	}
+}
+
+// drain records the frames queued when the recorder was stopped.
+func (r *uiSpectrogramFrameRecorder) drain() {
+	for {
+		select {
+		case frame := <-r.queue:
+			r.record(&frame)
+		default:
+			return
+		}
+	}
+}
+
+// record appends a frame to the open file, starting a new file when it would grow past its
+// share of the limit, and deletes the oldest files that no longer fit.
+func (r *uiSpectrogramFrameRecorder) record(frame *myaudio.UiSpectrogramData) {
+	line, err := json.Marshal(frame)
+	if err != nil {
+		r.dropped.Add(1)
+		r.fail(err, "encode_frame", r.dir)
+		return
+	}
+	line = append(line, '\n')
+
+	if r.file != nil && r.current().size > 0 && r.current().size+int64(len(line)) > r.segmentBytes {
+		r.closeFile()
+	}
+	if r.file == nil && !r.openFile() {
+		r.dropped.Add(1)
+		return
+	}
+	if _, err := r.writer.Write(line); err != nil {
+		r.dropped.Add(1)
+		r.fail(err, "write_frame", r.file.Name())
+		r.closeFile()
+		return
+	}
+	r.current().size += int64(len(line))
+	r.total += int64(len(line))
+	r.enforceLimit()
+}
+
+// current returns the open file's entry in files
+func (r *uiSpectrogramFrameRecorder) current() *uiSpectrogramRecordFile {
+	return &r.files[len(r.files)-1]
+}
+
+// openFile starts a new file named after the current time, reporting whether it could.
+func (r *uiSpectrogramFrameRecorder) openFile() bool {
+	r.seq++
+	name := fmt.Sprintf("%s%s-%04d%s", uiSpectrogramRecordFilePrefix, r.now().Format(uiSpectrogramRecordFileTimeLayout), r.seq, uiSpectrogramRecordFileExt)
+	path := filepath.Join(r.dir, name)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644) //nolint:gosec // G304: path is built from the configured record dir
+	if err != nil {
+		r.fail(err, "create_record_file", path)
+		return false
+	}
+	r.file = file
+	r.writer = bufio.NewWriter(file)
+	r.files = append(r.files, uiSpectrogramRecordFile{path: path})
+	return true
+}
+
+// flush writes out the frames buffered for the open file, if any.
+func (r *uiSpectrogramFrameRecorder) flush() {
+	if r.file == nil {
+		return
+	}
+	if err := r.writer.Flush(); err != nil {
+		r.fail(err, "write_frame", r.file.Name())
+	}
+}
+
+// closeFile writes out and closes the open file, if any.
+func (r *uiSpectrogramFrameRecorder) closeFile() {
+	if r.file == nil {
+		return
+	}
+	r.flush()
+	if err := r.file.Close(); err != nil {
+		r.fail(err, "close_record_file", r.file.Name())
+	}
+	r.file, r.writer = nil, nil
+}
+
+// enforceLimit deletes the oldest files until the directory fits the size limit, keeping at
+// least the newest file, so a single frame larger than the limit is still recorded.
+func (r *uiSpectrogramFrameRecorder) enforceLimit() {
+	for r.total > r.maxBytes && len(r.files) > 1 {
+		oldest := r.files[0]
+		if err := os.Remove(oldest.path); err != nil && !os.IsNotExist(err) {
+			r.fail(err, "delete_record_file", oldest.path)
+			return
+		}
+		r.files = r.files[1:]
+		r.total -= oldest.size
+	}
+}
+
+// fail records a write failure, logging only the first so a full disk doesn't flood the log.
+func (r *uiSpectrogramFrameRecorder) fail(err error, operation, path string) {
+	if r.err != nil {
+		return
+	}
+	r.err = uiSpectrogramRecordError(err, operation, path)
+	r.log.Warn("UI spectrogram frame recorder failed",
+		logger.Error(r.err),
+		logger.String("path", path))
+}
+
+// uiSpectrogramRecordError wraps an error of the frame recorder working on path.
+func uiSpectrogramRecordError(err error, operation, path string) error {
+	return errors.New(err).
+		Component("analysis.uispectrogram").
+		Category(errors.CategoryFileIO).
+		Context("operation", operation).
+		Context("path", path).
+		Build()
+}
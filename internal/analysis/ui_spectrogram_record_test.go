@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// recordedFrame returns the i-th frame of a test recording
+func recordedFrame(i int) myaudio.UiSpectrogramData {
+	return myaudio.UiSpectrogramData{
+		Source:      fmt.Sprintf("frame-%03d", i),
+		Spectrogram: make([]byte, 16),
+		Timestamp:   time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC).Add(time.Duration(i) * time.Second),
+	}
+}
+
+// recordedLineSize returns the bytes one test frame takes in a record file
+func recordedLineSize(t *testing.T) int64 {
+	t.Helper()
+	line, err := json.Marshal(recordedFrame(0))
+	require.NoError(t, err)
+	return int64(len(line)) + 1
+}
+
+// readRecordedFrames returns the sources of the frames recorded in dir, oldest first, with the
+// size of each file
+func readRecordedFrames(t *testing.T, dir string) (sources []string, sizes []int64) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var frame myaudio.UiSpectrogramData
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &frame))
+			sources = append(sources, frame.Source)
+		}
+		require.NoError(t, scanner.Err())
+		require.NoError(t, file.Close())
+		info, err := entry.Info()
+		require.NoError(t, err)
+		sizes = append(sizes, info.Size())
+	}
+	return sources, sizes
+}
+
+// startTestFrameRecorder starts a recorder in a new directory with room for maxFrames test
+// frames, a new file started every fileFrames of them
+func startTestFrameRecorder(t *testing.T, dir string, maxFrames, fileFrames int64) *uiSpectrogramFrameRecorder {
+	t.Helper()
+	lineSize := recordedLineSize(t)
+	recorder, err := newUiSpectrogramFrameRecorder(&conf.UiSpectrogramSettings{
+		SpectrogramRecordDir:      dir,
+		SpectrogramRecordMaxBytes: maxFrames * lineSize,
+	}, GetLogger())
+	require.NoError(t, err)
+	require.NotNil(t, recorder)
+	recorder.segmentBytes = fileFrames * lineSize
+	recorder.Start()
+	return recorder
+}
+
+func TestNewUiSpectrogramFrameRecorder_Disabled(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := newUiSpectrogramFrameRecorder(&conf.UiSpectrogramSettings{}, GetLogger())
+	require.NoError(t, err)
+	assert.Nil(t, recorder, "no recorder without a record directory")
+
+	// A nil recorder is a no-op, like the other optional publishers
+	frame := recordedFrame(0)
+	recorder.Start()
+	recorder.offer(&frame)
+	assert.NoError(t, recorder.Stop())
+}
+
+func TestUiSpectrogramFrameRecorder_RollsOverFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "frames")
+	recorder := startTestFrameRecorder(t, dir, 100, 4)
+	var want []string
+	for i := range 10 {
+		frame := recordedFrame(i)
+		recorder.offer(&frame)
+		want = append(want, frame.Source)
+	}
+	require.NoError(t, recorder.Stop())
+	assert.Zero(t, recorder.dropped.Load())
+
+	sources, sizes := readRecordedFrames(t, dir)
+	assert.Equal(t, want, sources, "every frame is written, in order, once Stop returns")
+	lineSize := recordedLineSize(t)
+	assert.Equal(t, []int64{4 * lineSize, 4 * lineSize, 2 * lineSize}, sizes, "a new file starts once one is full")
+}
+
+func TestUiSpectrogramFrameRecorder_DeletesOldestOverLimit(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	recorder := startTestFrameRecorder(t, dir, 10, 3)
+	for i := range 25 {
+		frame := recordedFrame(i)
+		recorder.offer(&frame)
+	}
+	require.NoError(t, recorder.Stop())
+
+	sources, sizes := readRecordedFrames(t, dir)
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	assert.LessOrEqual(t, total, recorder.maxBytes, "the directory stays within the limit")
+	assert.Equal(t, []string{"frame-015", "frame-016", "frame-017", "frame-018", "frame-019", "frame-020", "frame-021", "frame-022", "frame-023", "frame-024"}, sources,
+		"the oldest files are deleted, keeping the newest frames")
+
+	// A later recorder counts the files left behind toward a lowered limit
+	recorder = startTestFrameRecorder(t, dir, 4, 3)
+	frame := recordedFrame(25)
+	recorder.offer(&frame)
+	require.NoError(t, recorder.Stop())
+	sources, _ = readRecordedFrames(t, dir)
+	assert.Equal(t, []string{"frame-024", "frame-025"}, sources)
+}
+
+func TestUiSpectrogramSSEPublisher_RecordsFramesWithoutClients(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	recorder := startTestFrameRecorder(t, dir, 100, 100)
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	spectrogramChan := make(chan myaudio.UiSpectrogramData)
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, nil, nil, nil, recorder, nil, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+
+	for i := range 3 {
+		spectrogramChan <- recordedFrame(i)
+	}
+	cancel()
+	wg.Wait()
+	require.NoError(t, recorder.Stop())
+
+	sources, _ := readRecordedFrames(t, dir)
+	assert.Equal(t, []string{"frame-000", "frame-001", "frame-002"}, sources, "frames are recorded while nobody watches")
+	assert.Empty(t, broadcaster.broadcast())
+}
+
+func TestUiSpectrogramManager_StopFlushesFrameRecorder(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	settings := conf.GetTestSettings()
+	dir := t.TempDir()
+	settings.SoundId.UiSpectrogram.SpectrogramRecordDir = dir
+	conf.SetTestSettings(settings)
+
+	controller, _ := newSpectrogramStreamServer(t)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData)
+	manager := NewUiSpectrogramManager(spectrogramChan, nil, controller, nil, nil)
+	require.NoError(t, manager.Start(t.Context()))
+	require.NoError(t, manager.WaitUntilReady(t.Context()))
+	for i := range 3 {
+		spectrogramChan <- recordedFrame(i)
+	}
+	require.NoError(t, manager.Stop())
+
+	sources, _ := readRecordedFrames(t, dir)
+	assert.Equal(t, []string{"frame-000", "frame-001", "frame-002"}, sources, "the frames sent before Stop are on disk once it returns")
+}
@@ -0,0 +1,189 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of session goroutines.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// sessionIDsByMessage returns the session ID logged with each message, failing on lines without one.
+func (b *syncBuffer) sessionIDsByMessage(t *testing.T) map[string]string {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make(map[string]string)
+	scanner := bufio.NewScanner(&b.buf)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		id, _ := entry["session_id"].(string)
+		require.NotEmpty(t, id, "every session log line must carry the session ID: %s", scanner.Text())
+		ids[entry["msg"].(string)] = id
+	}
+	return ids
+}
+
+// entry returns the first log line with message msg, or nil when there is none.
+func (b *syncBuffer) entry(t *testing.T, msg string) map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		if entry["msg"] == msg {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestUiSpectrogramManager_SessionCorrelationID(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil, nil)
+
+	runSession := func() (string, map[string]string) {
+		var logs syncBuffer
+		manager.baseLog = logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+
+		require.NoError(t, manager.Start(t.Context()))
+		id := manager.SessionID()
+		require.NotEmpty(t, id)
+		require.NoError(t, manager.Stop())
+		assert.Empty(t, manager.SessionID(), "a stopped manager has no session")
+		return id, logs.sessionIDsByMessage(t)
+	}
+
+	first, firstLogs := runSession()
+	for _, msg := range []string{
+		"UI spectrogram monitoring started",
+		"Started UI spectrogram SSE publisher",
+		"Stopping UI spectrogram SSE publisher",
+		"UI spectrogram monitoring stopped",
+	} {
+		assert.Equal(t, first, firstLogs[msg], "%q must carry the session ID", msg)
+	}
+
+	second, secondLogs := runSession()
+	assert.NotEqual(t, first, second, "a new session gets a fresh ID")
+	assert.Equal(t, second, secondLogs["Started UI spectrogram SSE publisher"])
+}
+
+func TestUiSpectrogramManager_PublisherLogsWhySessionStopped(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	tests := []struct {
+		name   string
+		reason string
+		start  func(t *testing.T, manager *UiSpectrogramManager) (stop func())
+	}{
+		{"stop", uiSpectrogramStopRequested, func(t *testing.T, manager *UiSpectrogramManager) func() {
+			require.NoError(t, manager.Start(t.Context()))
+			return func() { require.NoError(t, manager.Stop()) }
+		}},
+		{"context cancel", uiSpectrogramStopCancelled, func(t *testing.T, manager *UiSpectrogramManager) func() {
+			ctx, cancel := context.WithCancel(t.Context())
+			require.NoError(t, manager.Start(ctx))
+			return cancel
+		}},
+		{"timeout", uiSpectrogramStopTimeout, func(t *testing.T, manager *UiSpectrogramManager) func() {
+			ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+			t.Cleanup(cancel)
+			require.NoError(t, manager.Start(ctx))
+			return func() {}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, _ := newSpectrogramStreamServer(t)
+			manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 4), nil, controller, nil, nil)
+			var logs syncBuffer
+			manager.baseLog = logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+			t.Cleanup(func() { _ = manager.Stop() })
+
+			stop := tt.start(t, manager)
+			id := manager.SessionID()
+			for range 3 {
+				require.True(t, manager.Send(&myaudio.UiSpectrogramData{Source: "mic", Timestamp: time.Now()}, t.Context().Done()))
+			}
+			require.Eventually(t, func() bool { return manager.Stats().FramesReceived == 3 }, 2*time.Second, 10*time.Millisecond)
+			stop()
+
+			const msg = "Stopping UI spectrogram SSE publisher"
+			require.Eventually(t, func() bool { return logs.entry(t, msg) != nil }, 2*time.Second, 10*time.Millisecond)
+			entry := logs.entry(t, msg)
+			assert.Equal(t, id, entry["session_id"])
+			assert.Equal(t, tt.reason, entry["reason"])
+			assert.InDelta(t, 3, entry["frames_received"], 0)
+			assert.NotEmpty(t, entry["started_at"])
+			assert.Equal(t, logs.entry(t, "Started UI spectrogram SSE publisher")["started_at"], entry["started_at"],
+				"the stop line carries the start time of the same publisher")
+		})
+	}
+}
+
+func TestUiSpectrogramManager_SupervisedRestartLogsCurrentSession(t *testing.T) {
+	original := conf.GetSettings()
+	t.Cleanup(func() { conf.SetTestSettings(original) })
+	conf.SetTestSettings(conf.GetTestSettings())
+
+	controller, _ := newSpectrogramStreamServer(t)
+	var logs syncBuffer
+	manager := NewUiSpectrogramManager(make(chan myaudio.UiSpectrogramData, 1), nil, controller, nil,
+		logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC))
+	manager.SetShutdownTimeout(50 * time.Millisecond)
+	require.NoError(t, manager.Start(t.Context()))
+	first := manager.SessionID()
+	require.NoError(t, manager.Restart())
+	current := manager.SessionID()
+	require.NotEqual(t, first, current)
+
+	// A publisher stuck on a slow sink makes the supervised restart fail to stop the session
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	manager.wg.Go(func() { <-release })
+	manager.supervisor.restart()
+
+	require.Eventually(t, func() bool {
+		return logs.entry(t, "supervised UI spectrogram restart failed") != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, current, logs.entry(t, "supervised UI spectrogram restart failed")["session_id"],
+		"the failed restart is logged under the session it restarted")
+}
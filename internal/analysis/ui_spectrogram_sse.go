@@ -2,39 +2,562 @@ package analysis
 
 import (
 	"context"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	apiv2 "github.com/tphakala/birdnet-go/internal/api/v2"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
 )
 
-// startUiSpectrogramSSEPublisher starts a goroutine to consume UI spectrogram data and publish via SSE
-func startUiSpectrogramSSEPublisher(wg *sync.WaitGroup, ctx context.Context, apiController *apiv2.Controller, spectrogramChan <-chan myaudio.UiSpectrogramData) {
+// uiSpectrogramBroadcaster is where the SSE publisher sends frames, the API controller in
+// production.
+type uiSpectrogramBroadcaster interface {
+	BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error
+	BroadcastSpectrogramBatch(frames []*myaudio.UiSpectrogramData) error
+	BroadcastSpectrogramHeartbeat() error
+	SpectrogramClientCount() int
+}
+
+// The publisher only sees the API controller through uiSpectrogramBroadcaster, so tests can
+// drive it with a fake instead of a running API
+var _ uiSpectrogramBroadcaster = (*apiv2.Controller)(nil)
+
+// closingSpectrogramBroadcaster closes closed the first time a broadcast fails with
+// apiv2.ErrBroadcasterClosed, telling the publisher the controller shut down whichever of its
+// goroutines broadcast first.
+type closingSpectrogramBroadcaster struct {
+	uiSpectrogramBroadcaster
+	closed chan struct{}
+	once   sync.Once
+}
+
+// newClosingSpectrogramBroadcaster wraps broadcaster to watch for it shutting down
+func newClosingSpectrogramBroadcaster(broadcaster uiSpectrogramBroadcaster) *closingSpectrogramBroadcaster {
+	return &closingSpectrogramBroadcaster{uiSpectrogramBroadcaster: broadcaster, closed: make(chan struct{})}
+}
+
+// BroadcastSpectrogram broadcasts frame, watching for the controller having shut down.
+func (b *closingSpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	return b.observe(b.uiSpectrogramBroadcaster.BroadcastSpectrogram(frame))
+}
+
+// BroadcastSpectrogramBatch broadcasts frames as one batch, watching for the controller having shut down.
+func (b *closingSpectrogramBroadcaster) BroadcastSpectrogramBatch(frames []*myaudio.UiSpectrogramData) error {
+	return b.observe(b.uiSpectrogramBroadcaster.BroadcastSpectrogramBatch(frames))
+}
+
+// BroadcastSpectrogramHeartbeat broadcasts a heartbeat, watching for the controller having shut down.
+func (b *closingSpectrogramBroadcaster) BroadcastSpectrogramHeartbeat() error {
+	return b.observe(b.uiSpectrogramBroadcaster.BroadcastSpectrogramHeartbeat())
+}
+
+// observe closes closed if err says the controller shut down, and returns err
+func (b *closingSpectrogramBroadcaster) observe(err error) error {
+	if errors.Is(err, apiv2.ErrBroadcasterClosed) {
+		b.once.Do(func() { close(b.closed) })
+	}
+	return err
+}
+
+// startUiSpectrogramSSEPublisher starts a goroutine to consume UI spectrogram data and publish via SSE.
+// Each frame is passed through filters before it is broadcast, and each broadcast result is
+// reported to the supervisor when one is given and counted in stats. Filtered frames are also offered to the MQTT
+// summary publisher, the video recorder and the frame recorder, if any. When a skipper is given, a backlog of queued frames is skipped
+// down to the newest frame of each source. When a batcher is given, frames are broadcast in batches, and a partial
+// batch is sent when its interval expires and when the publisher stops. When a collapser is given, frames matching
+// the previous frame of their source aren't broadcast, counted in stats. When a throttler is given, held frames are
+// broadcast as each source's interval comes up. When latest is given and frames aren't batched, frames are broadcast
+// from a goroutine of its own, keeping only the newest while a broadcast is in progress; it stops once the loop has
+// stopped and the last pending frame is sent. A heartbeat is broadcast after each heartbeatInterval without a
+// frame sent, unless it is 0. When drainTimeout is positive, the frames still queued when the context is done are
+// published for up to drainTimeout before the publisher stops, rather than left behind. Filtered frames are kept in recent, if given, for clients
+// that connect later. Each channel received from flushRequests, if given, is closed once the frames queued, those the
+// throttler holds and a partial batch are broadcast. ready, if given, is closed once the loop first waits for frames, or right away when publishing
+// is disabled. Once a broadcast fails because the API controller shut down, the publisher stops without draining, as
+// nothing can be broadcast anymore. A panic in the loop is logged and the loop is restarted after a backoff, counted in stats and
+// metrics when given, up to uiSpectrogramPublisherMaxRestarts times in a row. Log lines go to log, or to the
+// package logger when it is nil; the stop line reports the frames the publisher received and why it stopped.
+func startUiSpectrogramSSEPublisher(wg *sync.WaitGroup, ctx context.Context, apiController uiSpectrogramBroadcaster, spectrogramChan <-chan myaudio.UiSpectrogramData, filters []uiSpectrogramFilter, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, mqttPublisher *uiSpectrogramMQTTPublisher, videoRecorder *uiSpectrogramVideoRecorder, frameRecorder *uiSpectrogramFrameRecorder, skipper *uiSpectrogramFrameSkipper, batcher *uiSpectrogramBatcher, collapser *uiSpectrogramFrameCollapser, throttler *uiSpectrogramFrameThrottler, latest *uiSpectrogramLatestSlot, heartbeatInterval, drainTimeout time.Duration, recent *uiSpectrogramFrameRing, audioMetrics *metrics.MyAudioMetrics, ready chan struct{}, flushRequests <-chan chan struct{}, log logger.Logger) {
+	if log == nil {
+		log = GetLogger()
+	}
+	// signalReady closes ready once; run calls it again after every restart
+	var readyOnce sync.Once
+	signalReady := func() {
+		if ready != nil {
+			readyOnce.Do(func() { close(ready) })
+		}
+	}
 	if apiController == nil {
-		GetLogger().Warn("SSE API controller not available, UI spectrogram SSE publishing disabled")
+		log.Warn("SSE API controller not available, UI spectrogram SSE publishing disabled")
+		signalReady()
 		return
 	}
+	closing := newClosingSpectrogramBroadcaster(apiController)
+	apiController = closing
 
-	wg.Go(func() {
-		GetLogger().Info("Started UI spectrogram SSE publisher")
+	// received counts the frames consumed over all runs, reported when the publisher stops
+	var received uint64
+
+	// run consumes frames until the context is done or the channel is closed, returning why it
+	// stopped, or the recovered panic as an error if it panicked
+	run := func() (reason string, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.Newf("UI spectrogram SSE publisher panicked: %v", r).
+					Component("analysis.uispectrogram").
+					Category(errors.CategorySystem).
+					Context("operation", "publish").
+					Context("stack", string(debug.Stack())).
+					Build()
+			}
+		}()
+
+		errorLog := newUiSpectrogramErrorThrottle(uiSpectrogramBroadcastErrorLogInterval)
+		var batchTick <-chan time.Time // Never fires without a batcher
+		if batcher != nil {
+			ticker := time.NewTicker(batcher.interval)
+			defer ticker.Stop()
+			batchTick = ticker.C
+		}
+		var throttleTick <-chan time.Time // Never fires without a throttler
+		if throttler != nil {
+			ticker := time.NewTicker(throttler.interval)
+			defer ticker.Stop()
+			throttleTick = ticker.C
+		}
+		var heartbeatTimer *time.Timer
+		var heartbeatTick <-chan time.Time // Never fires with heartbeats disabled
+		if heartbeatInterval > 0 {
+			heartbeatTimer = time.NewTimer(heartbeatInterval)
+			defer heartbeatTimer.Stop()
+			heartbeatTick = heartbeatTimer.C
+		}
+		// sent restarts the heartbeat interval after frames went out
+		sent := func(ok bool) {
+			if ok && heartbeatTimer != nil {
+				heartbeatTimer.Reset(heartbeatInterval)
+			}
+		}
+		flushBatch := func() { sent(batcher.flush(apiController, supervisor, stats, audioMetrics, errorLog, log)) }
+		// publishQueued publishes the frames already queued in the channel, for up to timeout
+		publishQueued := func(timeout time.Duration) int {
+			return drainUiSpectrogramFrames(spectrogramChan, timeout, func(frame *myaudio.UiSpectrogramData) {
+				stats.received(1)
+				received++
+				sent(publishUiSpectrogramFrame(apiController, frame, filters, supervisor, stats, audioMetrics, mqttPublisher, videoRecorder, frameRecorder, batcher, collapser, throttler, latest, recent, errorLog, log))
+			})
+		}
 
+		// broadcasterClosed logs the controller having shut down. Frames still queued or batched
+		// can't go out anymore, so they are left behind.
+		broadcasterClosed := func() string {
+			log.Info("SSE API controller shut down, stopping UI spectrogram SSE publisher")
+			return uiSpectrogramStopBroadcasterClosed
+		}
+
+		signalReady()
 		for {
+			// Checked first, as select would otherwise keep picking the frames queued meanwhile
+			select {
+			case <-closing.closed:
+				return broadcasterClosed(), nil
+			default:
+			}
 			select {
+			case <-closing.closed:
+				return broadcasterClosed(), nil
 			case <-ctx.Done():
-				GetLogger().Info("Stopping UI spectrogram SSE publisher")
-				return
-			case spectrogramData := <-spectrogramChan:
-				// Publish spectrogram data via SSE
-				if err := apiController.BroadcastSpectrogram(&spectrogramData); err != nil {
-					// Only log errors occasionally to avoid spam
-					if time.Now().Unix()%60 == 0 { // Log once per minute at most
-						GetLogger().Warn("Error broadcasting UI spectrogram data via SSE",
-							logger.Error(err))
+				if drainTimeout > 0 {
+					drained := publishQueued(drainTimeout)
+					log.Debug("Drained queued UI spectrogram frames", logger.Int("frames", drained))
+				}
+				flushBatch()
+				return uiSpectrogramStopReason(ctx), nil
+			case done := <-flushRequests:
+				// The frames queued were produced before the flush was asked for, so they go out too
+				publishQueued(uiSpectrogramDrainTimeout)
+				if apiController.SpectrogramClientCount() > 0 {
+					for _, frame := range throttler.flush() {
+						sent(broadcastUiSpectrogramFrame(apiController, frame, supervisor, stats, audioMetrics, batcher, latest, errorLog, log))
 					}
 				}
+				flushBatch()
+				close(done)
+			case <-batchTick:
+				flushBatch()
+			case <-throttleTick:
+				if apiController.SpectrogramClientCount() == 0 {
+					throttler.reset()
+					break
+				}
+				for _, frame := range throttler.due() {
+					sent(broadcastUiSpectrogramFrame(apiController, frame, supervisor, stats, audioMetrics, batcher, latest, errorLog, log))
+				}
+			case <-heartbeatTick:
+				if err := apiController.BroadcastSpectrogramHeartbeat(); err != nil {
+					log.Debug("Error broadcasting UI spectrogram heartbeat", logger.Error(err))
+				}
+				heartbeatTimer.Reset(heartbeatInterval)
+			case spectrogramData, ok := <-spectrogramChan:
+				if !ok {
+					// A closed channel would otherwise yield zero-value frames in a tight loop
+					flushBatch()
+					log.Warn("UI spectrogram channel closed, stopping SSE publisher")
+					return uiSpectrogramStopChannelClosed, nil
+				}
+				skippedBefore := skipper.Skipped()
+				frames := skipper.latest(spectrogramData, spectrogramChan)
+				count := uint64(len(frames)) + skipper.Skipped() - skippedBefore
+				stats.received(count)
+				received += count
+				for _, frame := range frames {
+					sent(publishUiSpectrogramFrame(apiController, &frame, filters, supervisor, stats, audioMetrics, mqttPublisher, videoRecorder, frameRecorder, batcher, collapser, throttler, latest, recent, errorLog, log))
+				}
+			}
+		}
+	}
+
+	if latest != nil {
+		wg.Go(func() { latest.run(apiController, supervisor, stats, audioMetrics, log) })
+	}
+	wg.Go(func() {
+		startedAt := time.Now()
+		log.Info("Started UI spectrogram SSE publisher", logger.Time("started_at", startedAt))
+		stopped := func(reason string) {
+			latest.close()
+			log.Info("Stopping UI spectrogram SSE publisher",
+				logger.Time("started_at", startedAt),
+				logger.Duration("uptime", time.Since(startedAt)),
+				logger.Uint64("frames_received", received),
+				logger.String("reason", reason))
+		}
+		restarts := 0
+		for {
+			started := time.Now()
+			reason, err := run()
+			if err == nil {
+				stopped(reason)
+				return
+			}
+			// A loop that ran for a while before panicking starts a fresh series of restarts
+			if time.Since(started) >= uiSpectrogramPublisherStableRun {
+				restarts = 0
+			}
+			restarts++
+			if restarts > uiSpectrogramPublisherMaxRestarts {
+				log.Error("UI spectrogram SSE publisher keeps panicking, giving up",
+					logger.Error(err),
+					logger.Int("restarts", restarts-1))
+				stopped(uiSpectrogramStopPanicked)
+				return
+			}
+
+			backoff := min(uiSpectrogramPublisherRestartBackoff<<(restarts-1), uiSpectrogramPublisherMaxRestartBackoff)
+			log.Error("UI spectrogram SSE publisher panicked, restarting",
+				logger.Error(err),
+				logger.Int("attempt", restarts),
+				logger.Duration("backoff", backoff))
+			stats.publisherRestarted()
+			if audioMetrics != nil {
+				audioMetrics.RecordUiSpectrogramPublisherRestart()
+			}
+
+			select {
+			case <-ctx.Done():
+				stopped(uiSpectrogramStopReason(ctx))
+				return
+			case <-time.After(backoff):
 			}
 		}
 	})
 }
+
+// Restart policy of the SSE publisher after a panic
+const (
+	uiSpectrogramPublisherMaxRestarts       = 5
+	uiSpectrogramPublisherRestartBackoff    = 100 * time.Millisecond // Before the first restart, doubled for each consecutive one
+	uiSpectrogramPublisherMaxRestartBackoff = 10 * time.Second
+	uiSpectrogramPublisherStableRun         = time.Minute // Time a loop must run for its panic not to count as consecutive
+)
+
+// Reasons the SSE publisher logs for stopping
+const (
+	uiSpectrogramStopRequested         = "stopped"            // The session was stopped or restarted
+	uiSpectrogramStopCancelled         = "context_cancel"     // The context the session was started with was cancelled
+	uiSpectrogramStopTimeout           = "timeout"            // The context the session was started with timed out
+	uiSpectrogramStopChannelClosed     = "channel_closed"     // The spectrogram channel was closed
+	uiSpectrogramStopPanicked          = "panic"              // The loop kept panicking and was given up on
+	uiSpectrogramStopBroadcasterClosed = "broadcaster_closed" // The API controller shut down
+)
+
+// errUiSpectrogramSessionDone is the cause the publisher's context is cancelled with when its
+// session's done channel closes, telling a stop apart from the caller's context ending
+var errUiSpectrogramSessionDone = errors.NewStd("UI spectrogram session done")
+
+// uiSpectrogramStopReason returns why the done ctx of a publisher ended
+func uiSpectrogramStopReason(ctx context.Context) string {
+	switch cause := context.Cause(ctx); {
+	case errors.Is(cause, errUiSpectrogramSessionDone):
+		return uiSpectrogramStopRequested
+	case errors.Is(cause, context.DeadlineExceeded):
+		return uiSpectrogramStopTimeout
+	default:
+		return uiSpectrogramStopCancelled
+	}
+}
+
+// uiSpectrogramDrainTimeout bounds how long a stopping publisher keeps publishing queued
+// frames when draining on stop is enabled, so a producer that keeps sending can't hold up Stop
+const uiSpectrogramDrainTimeout = time.Second
+
+// drainUiSpectrogramFrames hands the frames queued in spectrogramChan to publish until the
+// channel is empty or closed or timeout has passed, and returns how many it handed over.
+func drainUiSpectrogramFrames(spectrogramChan <-chan myaudio.UiSpectrogramData, timeout time.Duration, publish func(frame *myaudio.UiSpectrogramData)) int {
+	deadline := time.Now().Add(timeout)
+	drained := 0
+	for time.Now().Before(deadline) {
+		select {
+		case frame, ok := <-spectrogramChan:
+			if !ok {
+				return drained
+			}
+			publish(&frame)
+			drained++
+		default:
+			return drained
+		}
+	}
+	return drained
+}
+
+// publishUiSpectrogramFrame filters one frame and broadcasts it, or adds it to the batch when
+// a batcher is given and broadcasts the batch once full. Broadcast errors are logged as often
+// as errorLog allows, and broadcasts are recorded in audioMetrics when given. Filtered frames
+// are kept in recent and offered to frameRecorder when given. A frame without a timestamp is stamped with the time it is
+// published; the capture time of other frames is kept. Frames the collapser,
+// if any, finds unchanged are counted in stats instead of broadcast, and so are frames the
+// throttler, if any, discards to keep a source under its rate. It reports whether a
+// broadcast was attempted. While no client watches the spectrogram the broadcast is skipped,
+// and so is filtering when neither MQTT, a recorder nor recent wants the frame; the
+// count is checked per frame, so broadcasting resumes with the first frame after a client
+// connects.
+func publishUiSpectrogramFrame(apiController uiSpectrogramBroadcaster, frame *myaudio.UiSpectrogramData, filters []uiSpectrogramFilter, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, audioMetrics *metrics.MyAudioMetrics, mqttPublisher *uiSpectrogramMQTTPublisher, videoRecorder *uiSpectrogramVideoRecorder, frameRecorder *uiSpectrogramFrameRecorder, batcher *uiSpectrogramBatcher, collapser *uiSpectrogramFrameCollapser, throttler *uiSpectrogramFrameThrottler, latest *uiSpectrogramLatestSlot, recent *uiSpectrogramFrameRing, errorLog *uiSpectrogramErrorThrottle, log logger.Logger) bool {
+	watched := apiController.SpectrogramClientCount() > 0
+	if !watched && mqttPublisher == nil && videoRecorder == nil && frameRecorder == nil && recent == nil {
+		return false
+	}
+
+	// Frames are stamped when produced; stamp only those that weren't, such as test frames
+	if frame.Timestamp.IsZero() {
+		frame.Timestamp = time.Now()
+	}
+	applyUiSpectrogramFilters(filters, frame)
+	recent.add(frame)
+	mqttPublisher.offer(frame)
+	videoRecorder.offer(frame)
+	frameRecorder.offer(frame)
+	if !watched {
+		collapser.reset()
+		throttler.reset()
+		return false
+	}
+	if collapser.collapse(frame) {
+		stats.collapsed()
+		return false
+	}
+	if send, discarded := throttler.admit(frame); !send {
+		stats.throttled(discarded)
+		return false
+	}
+	return broadcastUiSpectrogramFrame(apiController, frame, supervisor, stats, audioMetrics, batcher, latest, errorLog, log)
+}
+
+// broadcastUiSpectrogramFrame broadcasts a filtered frame, or adds it to the batch when a
+// batcher is given and broadcasts the batch once full, reporting whether a broadcast was
+// attempted. Without a batcher, a latest slot, if given, takes the frame for its own
+// goroutine to broadcast.
+func broadcastUiSpectrogramFrame(apiController uiSpectrogramBroadcaster, frame *myaudio.UiSpectrogramData, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, audioMetrics *metrics.MyAudioMetrics, batcher *uiSpectrogramBatcher, latest *uiSpectrogramLatestSlot, errorLog *uiSpectrogramErrorThrottle, log logger.Logger) bool {
+	if batcher != nil {
+		return batcher.add(frame) && batcher.flush(apiController, supervisor, stats, audioMetrics, errorLog, log)
+	}
+	if latest != nil {
+		latest.put(frame, stats)
+		return true
+	}
+
+	// Publish spectrogram data via SSE
+	start := time.Now()
+	err := apiController.BroadcastSpectrogram(frame)
+	reportUiSpectrogramBroadcast(err, 1, time.Since(start), supervisor, stats, audioMetrics, errorLog, log)
+	return true
+}
+
+// reportUiSpectrogramBroadcast records the result of broadcasting frames to the supervisor
+// and stats, once per frame, and to audioMetrics, if given, once per broadcast that took
+// elapsed. A failure is logged as often as errorLog allows.
+func reportUiSpectrogramBroadcast(err error, frames int, elapsed time.Duration, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, audioMetrics *metrics.MyAudioMetrics, errorLog *uiSpectrogramErrorThrottle, log logger.Logger) {
+	for range frames {
+		supervisor.observe(err)
+		stats.broadcastResult(err)
+	}
+	if audioMetrics != nil {
+		audioMetrics.RecordUiSpectrogramBroadcast(frames, elapsed.Seconds(), err != nil)
+	}
+	// The publisher logs the controller shutting down once as it stops
+	if err != nil && !errors.Is(err, apiv2.ErrBroadcasterClosed) {
+		if suppressed, ok := errorLog.allow(); ok {
+			log.Warn("Error broadcasting UI spectrogram data via SSE",
+				logger.Error(err),
+				logger.Int("frames", frames),
+				logger.Int("suppressed", suppressed))
+		}
+	}
+}
+
+// defaultUiSpectrogramBatchInterval is how long a partial batch waits when no interval is configured
+const defaultUiSpectrogramBatchInterval = 100 * time.Millisecond
+
+// uiSpectrogramBatcher coalesces frames into batches broadcast as one SSE event. It is used
+// by a single publisher goroutine and isn't safe for concurrent use.
+type uiSpectrogramBatcher struct {
+	size     int           // Frames that make a full batch
+	interval time.Duration // How often a partial batch is sent
+	frames   []*myaudio.UiSpectrogramData
+}
+
+// newUiSpectrogramBatcher returns a batcher when batches of more than one frame are
+// configured, nil otherwise.
+func newUiSpectrogramBatcher(settings *conf.UiSpectrogramSettings) *uiSpectrogramBatcher {
+	if settings.BatchSize <= 1 {
+		return nil
+	}
+	interval := time.Duration(settings.BatchInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultUiSpectrogramBatchInterval
+	}
+	return &uiSpectrogramBatcher{size: settings.BatchSize, interval: interval}
+}
+
+// add appends a frame to the batch and reports whether the batch is full.
+func (b *uiSpectrogramBatcher) add(frame *myaudio.UiSpectrogramData) bool {
+	b.frames = append(b.frames, frame)
+	return len(b.frames) >= b.size
+}
+
+// flush broadcasts the frames batched so far, if any, and reports whether it did. A batch
+// gathered for clients that have all disconnected since is dropped. A nil batcher has
+// nothing to flush.
+func (b *uiSpectrogramBatcher) flush(apiController uiSpectrogramBroadcaster, supervisor *uiSpectrogramSupervisor, stats *uiSpectrogramPublishStats, audioMetrics *metrics.MyAudioMetrics, errorLog *uiSpectrogramErrorThrottle, log logger.Logger) bool {
+	if b == nil || len(b.frames) == 0 {
+		return false
+	}
+	frames := b.frames
+	b.frames = make([]*myaudio.UiSpectrogramData, 0, b.size)
+	if apiController.SpectrogramClientCount() == 0 {
+		return false
+	}
+
+	start := time.Now()
+	err := apiController.BroadcastSpectrogramBatch(frames)
+	reportUiSpectrogramBroadcast(err, len(frames), time.Since(start), supervisor, stats, audioMetrics, errorLog, log)
+	return true
+}
+
+// uiSpectrogramBroadcastErrorLogInterval is the least time between two logged broadcast errors
+const uiSpectrogramBroadcastErrorLogInterval = time.Minute
+
+// uiSpectrogramErrorThrottle limits repeated errors to one log line per interval, so a
+// steady failure at the frame rate doesn't flood the log. It is used by a single publisher
+// goroutine and isn't safe for concurrent use.
+type uiSpectrogramErrorThrottle struct {
+	interval   time.Duration
+	now        func() time.Time
+	lastLogged time.Time
+	suppressed int
+}
+
+// newUiSpectrogramErrorThrottle returns a throttle allowing one log per interval.
+func newUiSpectrogramErrorThrottle(interval time.Duration) *uiSpectrogramErrorThrottle {
+	return &uiSpectrogramErrorThrottle{interval: interval, now: time.Now}
+}
+
+// allow reports whether an error may be logged now and, if so, how many were suppressed
+// since the last logged one. The first error is always logged.
+func (t *uiSpectrogramErrorThrottle) allow() (suppressed int, ok bool) {
+	now := t.now()
+	if !t.lastLogged.IsZero() && now.Sub(t.lastLogged) < t.interval {
+		t.suppressed++
+		return 0, false
+	}
+	suppressed, t.suppressed = t.suppressed, 0
+	t.lastLogged = now
+	return suppressed, true
+}
+
+// uiSpectrogramFrameSkipper keeps the live view current when the publisher falls behind, by
+// skipping frames that were queued behind a newer frame of the same source.
+type uiSpectrogramFrameSkipper struct {
+	skipped atomic.Uint64
+	log     logger.Logger
+}
+
+// newUiSpectrogramFrameSkipper returns a skipper when frame skipping is enabled, nil otherwise.
+func newUiSpectrogramFrameSkipper(settings *conf.UiSpectrogramSettings, log logger.Logger) *uiSpectrogramFrameSkipper {
+	if !settings.SkipStaleFrames {
+		return nil
+	}
+	return &uiSpectrogramFrameSkipper{log: log}
+}
+
+// Skipped returns how many stale frames have been skipped.
+func (s *uiSpectrogramFrameSkipper) Skipped() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.skipped.Load()
+}
+
+// latest drains the frames already queued behind first and returns the newest frame of each
+// source, in the order the sources were first seen. The frames are multiplexed, so a
+// backlog of one source must not drop another's only frame. A nil skipper returns first.
+func (s *uiSpectrogramFrameSkipper) latest(first myaudio.UiSpectrogramData, queued <-chan myaudio.UiSpectrogramData) []myaudio.UiSpectrogramData {
+	frames := []myaudio.UiSpectrogramData{first}
+	if s == nil {
+		return frames
+	}
+
+	skipped := 0
+drain:
+	for {
+		select {
+		case frame, ok := <-queued:
+			if !ok {
+				break drain // The publisher notices the closed channel on its next receive
+			}
+			if i := slices.IndexFunc(frames, func(f myaudio.UiSpectrogramData) bool { return f.Source == frame.Source }); i >= 0 {
+				frames[i] = frame
+				skipped++
+				continue
+			}
+			frames = append(frames, frame)
+		default:
+			break drain
+		}
+	}
+
+	if skipped > 0 {
+		total := s.skipped.Add(uint64(skipped))
+		s.log.Debug("UI spectrogram publisher fell behind, skipped stale frames",
+			logger.Int("skipped", skipped),
+			logger.Uint64("total_skipped", total))
+	}
+	return frames
+}
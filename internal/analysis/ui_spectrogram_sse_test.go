@@ -0,0 +1,595 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	apiv2 "github.com/tphakala/birdnet-go/internal/api/v2"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// newSpectrogramStreamServer starts an API controller serving only the spectrogram SSE stream.
+func newSpectrogramStreamServer(t *testing.T) (*apiv2.Controller, *httptest.Server) {
+	t.Helper()
+
+	settings := &conf.Settings{
+		Realtime: conf.RealtimeSettings{
+			Audio: conf.AudioSettings{Export: conf.ExportSettings{Path: t.TempDir()}},
+		},
+	}
+	e := echo.New()
+	controller, err := apiv2.NewWithOptions(e, nil, settings, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+	e.GET("/stream", controller.StreamSpectrogram)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(func() {
+		server.Close()
+		controller.Shutdown()
+	})
+	return controller, server
+}
+
+// openSpectrogramStream connects to the stream and returns once the client is registered
+// and the stream's metadata event was read, so the next data line is a frame.
+func openSpectrogramStream(t *testing.T, ctx context.Context, url string) *bufio.Scanner {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/stream", http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "Connected") {
+			break
+		}
+	}
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "event: ui_spectrogram_metadata") {
+			scanner.Scan() // Its data line
+			break
+		}
+	}
+	return scanner
+}
+
+func TestUiSpectrogramManager_FramesContinueWhileDetectionPaused(t *testing.T) {
+	proc := &processor.Processor{}
+	require.True(t, proc.SetDetectionPaused(true))
+
+	controller, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	scanner := openSpectrogramStream(t, ctx, server.URL)
+
+	// The manager runs its publishers with the paused processor, as realtime analysis does
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 1)
+	manager := NewUiSpectrogramManager(spectrogramChan, proc, controller, nil, nil)
+	require.NoError(t, manager.Start(ctx))
+	t.Cleanup(func() { _ = manager.Stop() })
+	require.NoError(t, manager.WaitUntilReady(ctx))
+
+	spectrogramChan <- myaudio.UiSpectrogramData{Spectrogram: make([]byte, myaudio.UiSpectrogramBins*2), Source: "test"}
+
+	received := false
+	for scanner.Scan() {
+		if scanner.Text() == "event: ui_spectrogram" {
+			received = true
+			break
+		}
+	}
+	assert.True(t, received, "spectrogram frames must keep flowing while detection is paused")
+	assert.True(t, proc.IsDetectionPaused())
+}
+
+func TestUiSpectrogramSSEPublisher_ExitsWhenChannelClosed(t *testing.T) {
+	controller, _ := newSpectrogramStreamServer(t)
+
+	spectrogramChan := make(chan myaudio.UiSpectrogramData)
+	var wg sync.WaitGroup
+	startUiSpectrogramSSEPublisher(&wg, t.Context(), controller, spectrogramChan, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+
+	close(spectrogramChan)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "publisher kept running after its channel was closed")
+	}
+}
+
+func TestUiSpectrogramSSEPublisher_StopsWhenBroadcasterClosed(t *testing.T) {
+	var logs bytes.Buffer
+	log := logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 8)
+	var wg sync.WaitGroup
+	startUiSpectrogramSSEPublisher(&wg, t.Context(), broadcaster, spectrogramChan, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, log)
+
+	spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic"}
+	require.Eventually(t, func() bool { return len(broadcaster.broadcast()) == 1 }, 2*time.Second, time.Millisecond)
+
+	// The controller shuts down mid-stream, with frames still coming in
+	broadcaster.mu.Lock()
+	broadcaster.err = fmt.Errorf("broadcast: %w", apiv2.ErrBroadcasterClosed)
+	broadcaster.mu.Unlock()
+	for range 3 {
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic"}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "publisher kept running after the controller shut down")
+	}
+	assert.Less(t, len(broadcaster.broadcast()), 4, "the publisher stops at the first failed broadcast")
+	assert.Equal(t, 1, strings.Count(logs.String(), "SSE API controller shut down"), "the shutdown is logged once")
+	assert.NotContains(t, logs.String(), "Error broadcasting UI spectrogram data")
+	assert.Contains(t, logs.String(), uiSpectrogramStopBroadcasterClosed)
+}
+
+func TestUiSpectrogramSSEPublisher_DeliversToBroadcasterUntilStopped(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	ctx, cancel := context.WithCancel(t.Context())
+	spectrogramChan := make(chan myaudio.UiSpectrogramData)
+	var wg sync.WaitGroup
+	startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+
+	for _, source := range []string{"mic", "rtsp", "mic"} {
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: source}
+	}
+	require.Eventually(t, func() bool { return len(broadcaster.broadcast()) == 3 }, 2*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"mic", "rtsp", "mic"}, broadcaster.broadcast(), "frames are delivered in order")
+
+	cancel()
+	wg.Wait()
+	select {
+	case spectrogramChan <- myaudio.UiSpectrogramData{Source: "late"}:
+		require.Fail(t, "a stopped publisher must not read frames")
+	case <-time.After(20 * time.Millisecond):
+	}
+	assert.Len(t, broadcaster.broadcast(), 3)
+}
+
+func TestUiSpectrogramSSEPublisher_SkipsToNewestQueuedFrame(t *testing.T) {
+	controller, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	scanner := openSpectrogramStream(t, ctx, server.URL)
+
+	// Queue a backlog before the publisher starts, as when broadcasting fell behind
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 8)
+	for i := range 5 {
+		spectrogramChan <- myaudio.UiSpectrogramData{
+			Spectrogram: make([]byte, myaudio.UiSpectrogramBins),
+			Source:      "backlog",
+			Timestamp:   start.Add(time.Duration(i) * time.Second),
+		}
+	}
+
+	skipper := newUiSpectrogramFrameSkipper(&conf.UiSpectrogramSettings{SkipStaleFrames: true}, GetLogger())
+	var wg sync.WaitGroup
+	startUiSpectrogramSSEPublisher(&wg, ctx, controller, spectrogramChan, nil, nil, nil, nil, nil, nil, skipper, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+
+	// A later frame marks the end of what the backlog produced
+	spectrogramChan <- myaudio.UiSpectrogramData{Spectrogram: make([]byte, myaudio.UiSpectrogramBins), Source: "end"}
+
+	var broadcast []myaudio.UiSpectrogramData
+	for scanner.Scan() {
+		payload, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var frame myaudio.UiSpectrogramData
+		require.NoError(t, json.Unmarshal([]byte(payload), &frame))
+		if frame.Source == "end" {
+			break
+		}
+		broadcast = append(broadcast, frame)
+	}
+
+	require.Len(t, broadcast, 1, "only the newest queued frame may be broadcast")
+	assert.True(t, broadcast[0].Timestamp.Equal(start.Add(4*time.Second)))
+	assert.Equal(t, uint64(4), skipper.Skipped())
+
+	cancel()
+	wg.Wait()
+}
+
+func TestUiSpectrogramFrameSkipper_KeepsNewestFramePerSource(t *testing.T) {
+	t.Parallel()
+
+	queued := make(chan myaudio.UiSpectrogramData, 4)
+	queued <- myaudio.UiSpectrogramData{Source: "b", MsPerColumn: 1}
+	queued <- myaudio.UiSpectrogramData{Source: "a", MsPerColumn: 2}
+	queued <- myaudio.UiSpectrogramData{Source: "b", MsPerColumn: 3}
+
+	skipper := &uiSpectrogramFrameSkipper{log: GetLogger()}
+	frames := skipper.latest(myaudio.UiSpectrogramData{Source: "a", MsPerColumn: 0}, queued)
+
+	require.Len(t, frames, 2)
+	assert.Equal(t, myaudio.UiSpectrogramData{Source: "a", MsPerColumn: 2}, frames[0])
+	assert.Equal(t, myaudio.UiSpectrogramData{Source: "b", MsPerColumn: 3}, frames[1])
+	assert.Equal(t, uint64(2), skipper.Skipped())
+
+	var disabled *uiSpectrogramFrameSkipper
+	assert.Len(t, disabled.latest(myaudio.UiSpectrogramData{}, queued), 1)
+}
+
+// fakeSpectrogramBroadcaster reports a settable client count and records broadcast frames.
+type fakeSpectrogramBroadcaster struct {
+	clients atomic.Int64
+	err     error
+
+	mu      sync.Mutex
+	sources []string
+	batches []int // Size of each batch broadcast
+
+	heartbeats atomic.Int64
+}
+
+func (f *fakeSpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sources = append(f.sources, frame.Source)
+	return f.err
+}
+
+func (f *fakeSpectrogramBroadcaster) BroadcastSpectrogramBatch(frames []*myaudio.UiSpectrogramData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, frame := range frames {
+		f.sources = append(f.sources, frame.Source)
+	}
+	f.batches = append(f.batches, len(frames))
+	return f.err
+}
+
+func (f *fakeSpectrogramBroadcaster) BroadcastSpectrogramHeartbeat() error {
+	f.heartbeats.Add(1)
+	return nil
+}
+
+func (f *fakeSpectrogramBroadcaster) SpectrogramClientCount() int {
+	return int(f.clients.Load())
+}
+
+func (f *fakeSpectrogramBroadcaster) broadcast() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.sources)
+}
+
+// failingSpectrogramBroadcaster has one client and fails every broadcast.
+func failingSpectrogramBroadcaster() *fakeSpectrogramBroadcaster {
+	f := &fakeSpectrogramBroadcaster{err: errors.New("broadcast failed")}
+	f.clients.Store(1)
+	return f
+}
+
+func TestUiSpectrogramSSEPublisher_CountsFrames(t *testing.T) {
+	connected, server := newSpectrogramStreamServer(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	openSpectrogramStream(t, ctx, server.URL)
+
+	tests := []struct {
+		name       string
+		controller uiSpectrogramBroadcaster
+		broadcast  uint64
+		dropped    uint64
+	}{
+		{"broadcast", connected, 5, 0},
+		{"broadcast fails", failingSpectrogramBroadcaster(), 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(t.Context())
+			var stats uiSpectrogramPublishStats
+			spectrogramChan := make(chan myaudio.UiSpectrogramData)
+			var wg sync.WaitGroup
+			startUiSpectrogramSSEPublisher(&wg, ctx, tt.controller, spectrogramChan, nil, nil, &stats, nil, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+
+			for range 5 {
+				spectrogramChan <- myaudio.UiSpectrogramData{Spectrogram: make([]byte, myaudio.UiSpectrogramBins), Source: "mic"}
+			}
+			require.Eventually(t, func() bool {
+				s := stats.snapshot()
+				return s.FramesBroadcast+s.FramesDropped == 5
+			}, 2*time.Second, time.Millisecond)
+			cancel()
+			wg.Wait()
+
+			assert.Equal(t, myaudio.UiSpectrogramStats{FramesReceived: 5, FramesBroadcast: tt.broadcast, FramesDropped: tt.dropped}, stats.snapshot())
+		})
+	}
+}
+
+func TestPublishUiSpectrogramFrame_ThrottlesBroadcastErrors(t *testing.T) {
+	var logs bytes.Buffer
+	log := logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+
+	clock := time.Date(2026, 5, 15, 6, 0, 0, 0, time.UTC)
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	errorLog.now = func() time.Time { return clock }
+
+	// One failing frame every 10s for 5 minutes
+	controller := failingSpectrogramBroadcaster()
+	for range 30 {
+		frame := myaudio.UiSpectrogramData{Spectrogram: make([]byte, myaudio.UiSpectrogramBins), Source: "mic"}
+		publishUiSpectrogramFrame(controller, &frame, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, errorLog, log)
+		clock = clock.Add(10 * time.Second)
+	}
+
+	var suppressed []float64
+	scanner := bufio.NewScanner(&logs)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		if entry["msg"] == "Error broadcasting UI spectrogram data via SSE" {
+			suppressed = append(suppressed, entry["suppressed"].(float64))
+		}
+	}
+	assert.Equal(t, []float64{0, 5, 5, 5, 5}, suppressed,
+		"the first error is logged at once, then one per minute with the errors suppressed in between")
+}
+
+func TestPublishUiSpectrogramFrame_SkipsBroadcastWithoutClients(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	var stats uiSpectrogramPublishStats
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	publish := func(source string) {
+		frame := myaudio.UiSpectrogramData{Source: source}
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &stats, nil, nil, nil, nil, nil, nil, nil, nil, nil, errorLog, GetLogger())
+	}
+
+	publish("unwatched")
+	publish("unwatched")
+	assert.Empty(t, broadcaster.broadcast(), "nothing is broadcast without clients")
+
+	broadcaster.clients.Store(1)
+	publish("watched")
+	assert.Equal(t, []string{"watched"}, broadcaster.broadcast(),
+		"frames are broadcast again as soon as a client connects")
+	assert.Equal(t, uint64(1), stats.snapshot().FramesBroadcast)
+}
+
+func TestPublishUiSpectrogramFrame_RecordsBroadcastMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	audioMetrics, err := metrics.NewMyAudioMetrics(registry)
+	require.NoError(t, err)
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	publish := func(broadcaster uiSpectrogramBroadcaster) {
+		frame := myaudio.UiSpectrogramData{Source: "mic"}
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, nil, audioMetrics, nil, nil, nil, nil, nil, nil, nil, nil, errorLog, GetLogger())
+	}
+
+	working := &fakeSpectrogramBroadcaster{}
+	working.clients.Store(1)
+	for range 3 {
+		publish(working)
+	}
+	publish(failingSpectrogramBroadcaster())
+
+	gathered := map[string]float64{}
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if histogram := metric.GetHistogram(); histogram != nil {
+				gathered[family.GetName()] = float64(histogram.GetSampleCount())
+			} else {
+				gathered[family.GetName()] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+	assert.InDelta(t, 3, gathered["spectrogram_frames_broadcast_total"], 0)
+	assert.InDelta(t, 1, gathered["spectrogram_broadcast_errors_total"], 0)
+	assert.InDelta(t, 4, gathered["spectrogram_broadcast_duration_seconds"], 0, "every broadcast is timed")
+}
+
+func TestPublishUiSpectrogramFrame_DropsFramesWhilePaused(t *testing.T) {
+	inner := &fakeSpectrogramBroadcaster{}
+	inner.clients.Store(1)
+	var paused atomic.Bool
+	broadcaster := &pausableSpectrogramBroadcaster{uiSpectrogramBroadcaster: inner, paused: &paused}
+	var stats uiSpectrogramPublishStats
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	publish := func(source string) {
+		frame := myaudio.UiSpectrogramData{Source: source}
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &stats, nil, nil, nil, nil, nil, nil, nil, nil, nil, errorLog, GetLogger())
+	}
+
+	paused.Store(true)
+	publish("paused")
+	publish("paused")
+	assert.Empty(t, inner.broadcast(), "nothing is broadcast while paused")
+
+	paused.Store(false)
+	publish("resumed")
+	assert.Equal(t, []string{"resumed"}, inner.broadcast(), "frames are delivered again after resume")
+	assert.Equal(t, uint64(1), stats.snapshot().FramesBroadcast)
+}
+
+func (f *fakeSpectrogramBroadcaster) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.batches)
+}
+
+func TestPublishUiSpectrogramFrame_FlushesFullBatches(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	batcher := newUiSpectrogramBatcher(&conf.UiSpectrogramSettings{BatchSize: 3, BatchInterval: 1000})
+	var stats uiSpectrogramPublishStats
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+
+	for i := range 7 {
+		frame := myaudio.UiSpectrogramData{Source: string(rune('a' + i))}
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &stats, nil, nil, nil, nil, batcher, nil, nil, nil, nil, errorLog, GetLogger())
+	}
+	assert.Equal(t, []int{3, 3}, broadcaster.batchSizes(), "each full batch is sent as one event")
+	assert.Equal(t, uint64(6), stats.snapshot().FramesBroadcast)
+
+	batcher.flush(broadcaster, nil, &stats, nil, errorLog, GetLogger())
+	assert.Equal(t, []int{3, 3, 1}, broadcaster.batchSizes())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g"}, broadcaster.broadcast(), "frames keep their order")
+}
+
+func TestUiSpectrogramSSEPublisher_FlushesPartialBatches(t *testing.T) {
+	t.Run("on interval", func(t *testing.T) {
+		broadcaster := &fakeSpectrogramBroadcaster{}
+		broadcaster.clients.Store(1)
+		batcher := newUiSpectrogramBatcher(&conf.UiSpectrogramSettings{BatchSize: 100, BatchInterval: 20})
+		spectrogramChan := make(chan myaudio.UiSpectrogramData, 2)
+		ctx, cancel := context.WithCancel(t.Context())
+		var wg sync.WaitGroup
+		t.Cleanup(func() { cancel(); wg.Wait() })
+
+		startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, nil, nil, nil, nil, nil, batcher, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "a"}
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "b"}
+
+		assert.Eventually(t, func() bool { return len(broadcaster.broadcast()) == 2 }, 2*time.Second, 5*time.Millisecond,
+			"a partial batch is sent when the interval expires")
+	})
+
+	t.Run("on shutdown", func(t *testing.T) {
+		broadcaster := &fakeSpectrogramBroadcaster{}
+		broadcaster.clients.Store(1)
+		batcher := newUiSpectrogramBatcher(&conf.UiSpectrogramSettings{BatchSize: 100, BatchInterval: int(time.Hour / time.Millisecond)})
+		spectrogramChan := make(chan myaudio.UiSpectrogramData, 2)
+		var stats uiSpectrogramPublishStats
+		ctx, cancel := context.WithCancel(t.Context())
+		var wg sync.WaitGroup
+
+		startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, &stats, nil, nil, nil, nil, batcher, nil, nil, nil, 0, 0, nil, nil, nil, nil, GetLogger())
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "a"}
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "b"}
+		require.Eventually(t, func() bool { return stats.snapshot().FramesReceived == 2 }, 2*time.Second, 5*time.Millisecond)
+		assert.Empty(t, broadcaster.broadcast(), "the batch waits for its interval")
+
+		cancel()
+		wg.Wait()
+		assert.Equal(t, []int{2}, broadcaster.batchSizes(), "stopping sends the partial batch")
+	})
+}
+
+func TestUiSpectrogramSSEPublisher_HeartbeatWhileIdle(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 1)
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	t.Cleanup(func() { cancel(); wg.Wait() })
+
+	startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, interval, 0, nil, nil, nil, nil, GetLogger())
+
+	// Frames sent well within the interval keep resetting the heartbeat timer
+	for range 20 {
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: "mic"}
+		time.Sleep(interval / 10)
+	}
+	assert.Zero(t, broadcaster.heartbeats.Load(), "no heartbeat while frames flow")
+
+	assert.Eventually(t, func() bool { return broadcaster.heartbeats.Load() >= 2 }, 2*time.Second, 5*time.Millisecond,
+		"heartbeats repeat while the stream is idle")
+}
+
+// panickingSpectrogramBroadcaster panics on its first panics broadcasts
+type panickingSpectrogramBroadcaster struct {
+	*fakeSpectrogramBroadcaster
+	panics atomic.Int64
+}
+
+func (p *panickingSpectrogramBroadcaster) BroadcastSpectrogram(frame *myaudio.UiSpectrogramData) error {
+	if p.panics.Add(-1) >= 0 {
+		panic("broadcast exploded")
+	}
+	return p.fakeSpectrogramBroadcaster.BroadcastSpectrogram(frame)
+}
+
+func TestUiSpectrogramSSEPublisher_RecoversFromPanics(t *testing.T) {
+	broadcaster := &panickingSpectrogramBroadcaster{fakeSpectrogramBroadcaster: &fakeSpectrogramBroadcaster{}}
+	broadcaster.clients.Store(1)
+	broadcaster.panics.Store(2)
+	var logs syncBuffer
+	log := logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+	var stats uiSpectrogramPublishStats
+	spectrogramChan := make(chan myaudio.UiSpectrogramData, 4)
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	t.Cleanup(func() { cancel(); wg.Wait() })
+
+	startUiSpectrogramSSEPublisher(&wg, ctx, broadcaster, spectrogramChan, nil, nil, &stats, nil, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil, nil, nil, nil, log)
+	for _, source := range []string{"first", "second", "third"} {
+		spectrogramChan <- myaudio.UiSpectrogramData{Source: source}
+	}
+
+	assert.Eventually(t, func() bool { return slices.Equal(broadcaster.broadcast(), []string{"third"}) },
+		2*time.Second, 5*time.Millisecond, "the publisher keeps consuming after recovering")
+	assert.Equal(t, uint64(2), stats.snapshot().PublisherRestarts)
+	assert.Contains(t, logs.String(), "UI spectrogram SSE publisher panicked, restarting")
+	assert.Contains(t, logs.String(), "broadcast exploded")
+}
+
+func TestPublishUiSpectrogramFrame_KeepsCaptureTimestamp(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	recent := newUiSpectrogramFrameRing(2)
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+
+	captured := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	stamped := myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{1}, Timestamp: captured, SampleRate: conf.SampleRate}
+	publishUiSpectrogramFrame(broadcaster, &stamped, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, recent, errorLog, GetLogger())
+
+	before := time.Now()
+	unstamped := myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{2}}
+	publishUiSpectrogramFrame(broadcaster, &unstamped, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, recent, errorLog, GetLogger())
+
+	frames := recent.frames()
+	require.Len(t, frames, 2)
+	assert.Equal(t, captured, frames[0].Timestamp, "the capture time isn't replaced by the publish time")
+	assert.Equal(t, conf.SampleRate, frames[0].SampleRate)
+	assert.False(t, frames[1].Timestamp.Before(before), "a frame produced without a timestamp gets one")
+}
@@ -0,0 +1,122 @@
+package analysis
+
+import (
+	"sync/atomic"
+
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// uiSpectrogramPublishStats counts the frames the SSE publisher receives and broadcasts.
+// A nil value counts nothing.
+type uiSpectrogramPublishStats struct {
+	framesReceived    atomic.Uint64
+	framesBroadcast   atomic.Uint64
+	framesDropped     atomic.Uint64
+	framesOverflowed  atomic.Uint64
+	framesCollapsed   atomic.Uint64
+	framesThrottled   atomic.Uint64
+	framesCoalesced   atomic.Uint64
+	framesTruncated   atomic.Uint64
+	publisherRestarts atomic.Uint64
+	channelDepth      atomic.Int64 // Frames queued in the spectrogram channel when last sampled
+	channelCapacity   atomic.Int64
+	health            uiSpectrogramHealthTracker // Broadcast results of the running session, for Health
+	errorMetrics      *metrics.ErrorMetrics      // Counts failed broadcasts and sessions by category, nil when metrics are disabled
+}
+
+// channelSampled keeps the latest sampled depth and capacity of the spectrogram channel.
+func (s *uiSpectrogramPublishStats) channelSampled(depth, capacity int) {
+	if s == nil {
+		return
+	}
+	s.channelDepth.Store(int64(depth))
+	s.channelCapacity.Store(int64(capacity))
+}
+
+// received counts frames read from the spectrogram channel, including skipped stale ones.
+func (s *uiSpectrogramPublishStats) received(frames uint64) {
+	if s == nil {
+		return
+	}
+	s.framesReceived.Add(frames)
+	s.health.frameReceived()
+}
+
+// broadcastResult counts a frame as broadcast, or as dropped when err is set.
+func (s *uiSpectrogramPublishStats) broadcastResult(err error) {
+	if s == nil {
+		return
+	}
+	s.health.broadcastResult(err)
+	if err != nil {
+		s.framesDropped.Add(1)
+		s.errorMetrics.RecordError(err)
+		return
+	}
+	s.framesBroadcast.Add(1)
+}
+
+// overflowed counts frames discarded because the spectrogram channel was full.
+func (s *uiSpectrogramPublishStats) overflowed(frames int) {
+	if s == nil || frames <= 0 {
+		return
+	}
+	s.framesOverflowed.Add(uint64(frames))
+}
+
+// collapsed counts a frame left out of the broadcast because it matched the previous one.
+func (s *uiSpectrogramPublishStats) collapsed() {
+	if s == nil {
+		return
+	}
+	s.framesCollapsed.Add(1)
+}
+
+// throttled counts frames discarded to keep broadcasts under the maximum frame rate.
+func (s *uiSpectrogramPublishStats) throttled(frames int) {
+	if s == nil || frames <= 0 {
+		return
+	}
+	s.framesThrottled.Add(uint64(frames))
+}
+
+// coalesced counts a frame replaced by a newer one before a slow broadcast could send it.
+func (s *uiSpectrogramPublishStats) coalesced() {
+	if s == nil {
+		return
+	}
+	s.framesCoalesced.Add(1)
+}
+
+// truncated counts a frame down-resolved to fit under the frame size cap.
+func (s *uiSpectrogramPublishStats) truncated() {
+	if s == nil {
+		return
+	}
+	s.framesTruncated.Add(1)
+}
+
+// publisherRestarted counts a restart of the SSE publisher loop after a panic.
+func (s *uiSpectrogramPublishStats) publisherRestarted() {
+	if s == nil {
+		return
+	}
+	s.publisherRestarts.Add(1)
+}
+
+func (s *uiSpectrogramPublishStats) snapshot() myaudio.UiSpectrogramStats {
+	return myaudio.UiSpectrogramStats{
+		FramesReceived:    s.framesReceived.Load(),
+		FramesBroadcast:   s.framesBroadcast.Load(),
+		FramesDropped:     s.framesDropped.Load(),
+		FramesOverflowed:  s.framesOverflowed.Load(),
+		FramesCollapsed:   s.framesCollapsed.Load(),
+		FramesThrottled:   s.framesThrottled.Load(),
+		FramesCoalesced:   s.framesCoalesced.Load(),
+		FramesTruncated:   s.framesTruncated.Load(),
+		PublisherRestarts: s.publisherRestarts.Load(),
+		ChannelDepth:      int(s.channelDepth.Load()),
+		ChannelCapacity:   int(s.channelCapacity.Load()),
+	}
+}
@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// uiSpectrogramMaxRestartBackoff caps the doubling backoff between supervised restarts
+const uiSpectrogramMaxRestartBackoff = 30 * time.Minute
+
+// uiSpectrogramSupervisor watches the SSE publisher's broadcast results and restarts the
+// spectrogram manager when the failure rate stays at or above the threshold for a whole
+// window. Restarts back off exponentially so a fault a restart can't fix doesn't turn into
+// a restart storm; the backoff resets after a healthy window.
+type uiSpectrogramSupervisor struct {
+	mu sync.Mutex
+
+	errorRate      float64       // Failure rate (0-1] that triggers a restart, 0 disables supervision
+	window         time.Duration // How long the failure rate must be sustained
+	initialBackoff time.Duration // Minimum time between restarts, doubled after each restart
+	restart        func()        // Must not block; the publisher calling observe is stopped by a restart
+	now            func() time.Time
+	log            logger.Logger // Logger of the current monitoring session, GetLogger() when nil
+
+	windowStart time.Time
+	attempts    int
+	failures    int
+	backoff     time.Duration
+	lastRestart time.Time
+}
+
+// newUiSpectrogramSupervisor creates a supervisor from the UI spectrogram settings.
+func newUiSpectrogramSupervisor(settings *conf.UiSpectrogramSettings, restart func()) *uiSpectrogramSupervisor {
+	backoff := time.Duration(settings.RestartBackoff) * time.Second
+	return &uiSpectrogramSupervisor{
+		errorRate:      settings.RestartErrorRate,
+		window:         time.Duration(settings.RestartErrorWindow) * time.Second,
+		initialBackoff: backoff,
+		backoff:        backoff,
+		restart:        restart,
+		now:            time.Now,
+	}
+}
+
+// updateSettings switches the supervisor to the restart thresholds of changed settings. The
+// window in progress is kept, and so is a longer backoff from earlier restarts until the
+// next healthy window resets it.
+func (s *uiSpectrogramSupervisor) updateSettings(settings *conf.UiSpectrogramSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorRate = settings.RestartErrorRate
+	s.window = time.Duration(settings.RestartErrorWindow) * time.Second
+	s.initialBackoff = time.Duration(settings.RestartBackoff) * time.Second
+	s.backoff = max(s.backoff, s.initialBackoff)
+}
+
+// setLogger switches the supervisor to the logger of a new monitoring session.
+func (s *uiSpectrogramSupervisor) setLogger(log logger.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log = log
+}
+
+// observe records the result of one broadcast. A nil supervisor ignores it, and so are
+// failures marked as not retryable, since a restart would fail the same way.
+func (s *uiSpectrogramSupervisor) observe(err error) {
+	if s == nil || errors.IsPermanent(err) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.errorRate <= 0 || s.window <= 0 {
+		return
+	}
+
+	now := s.now()
+	if s.windowStart.IsZero() {
+		s.windowStart = now
+	}
+	s.attempts++
+	if err != nil {
+		s.failures++
+	}
+	if now.Sub(s.windowStart) < s.window {
+		return
+	}
+
+	rate := float64(s.failures) / float64(s.attempts)
+	failures := s.failures
+	s.windowStart, s.attempts, s.failures = now, 0, 0
+
+	if rate < s.errorRate {
+		s.backoff = s.initialBackoff
+		return
+	}
+	if !s.lastRestart.IsZero() && now.Sub(s.lastRestart) < s.backoff {
+		return // Still backing off from the previous restart
+	}
+	if !s.lastRestart.IsZero() {
+		s.backoff = min(2*s.backoff, uiSpectrogramMaxRestartBackoff)
+	}
+	s.lastRestart = now
+
+	log := s.log
+	if log == nil {
+		log = GetLogger()
+	}
+	log.Warn("UI spectrogram broadcasts failing, restarting spectrogram monitoring",
+		logger.Float64("error_rate", rate),
+		logger.Int("failures", failures),
+		logger.Duration("window", s.window),
+		logger.Duration("next_backoff", s.backoff),
+		logger.String("operation", "ui_spectrogram_supervised_restart"))
+	s.restart()
+}
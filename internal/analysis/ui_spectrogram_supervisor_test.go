@@ -0,0 +1,111 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// newTestSupervisor returns a supervisor on a fake clock advanced by the returned function.
+func newTestSupervisor(restarts *int) (s *uiSpectrogramSupervisor, advance func(time.Duration)) {
+	now := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	s = newUiSpectrogramSupervisor(&conf.UiSpectrogramSettings{
+		RestartErrorRate:   0.9,
+		RestartErrorWindow: 10,
+		RestartBackoff:     60,
+	}, func() { *restarts++ })
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+// feed reports one broadcast result every 100ms for the given duration.
+func feed(s *uiSpectrogramSupervisor, advance func(time.Duration), d time.Duration, err error) {
+	const step = 100 * time.Millisecond
+	for elapsed := time.Duration(0); elapsed < d; elapsed += step {
+		s.observe(err)
+		advance(step)
+	}
+}
+
+func TestUiSpectrogramSupervisor_SustainedErrorsRestartOnce(t *testing.T) {
+	t.Parallel()
+
+	var restarts int
+	s, advance := newTestSupervisor(&restarts)
+	broadcastErr := errors.NewStd("SSE manager not initialized")
+
+	// Five failing windows inside the 60s backoff trigger one restart, not five
+	feed(s, advance, 50*time.Second, broadcastErr)
+	assert.Equal(t, 1, restarts)
+
+	// Once the backoff has passed, a continuing fault may restart again
+	feed(s, advance, 30*time.Second, broadcastErr)
+	assert.Equal(t, 2, restarts)
+
+	// The backoff has doubled to 120s, so the next minute of failures restarts nothing
+	feed(s, advance, 60*time.Second, broadcastErr)
+	assert.Equal(t, 2, restarts)
+}
+
+func TestUiSpectrogramSupervisor_PermanentErrorsDoNotRestart(t *testing.T) {
+	t.Parallel()
+
+	var restarts int
+	s, advance := newTestSupervisor(&restarts)
+	permanent := errors.Newf("uiSpectrogram is nil").Category(errors.CategoryValidation).Retryable(false).Build()
+
+	feed(s, advance, time.Minute, permanent)
+	assert.Zero(t, restarts, "a restart can't fix a failure marked as not retryable")
+
+	// Unclassified and retryable failures still count
+	feed(s, advance, 20*time.Second, errors.Newf("client gone").Retryable(true).Build())
+	assert.Equal(t, 1, restarts)
+}
+
+func TestUiSpectrogramSupervisor_OccasionalErrorsIgnored(t *testing.T) {
+	t.Parallel()
+
+	var restarts int
+	s, advance := newTestSupervisor(&restarts)
+
+	for range 100 {
+		feed(s, advance, 900*time.Millisecond, nil)
+		feed(s, advance, 100*time.Millisecond, errors.NewStd("dropped"))
+	}
+	assert.Zero(t, restarts)
+}
+
+func TestUiSpectrogramSupervisor_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var restarts int
+	s, advance := newTestSupervisor(&restarts)
+	s.errorRate = 0
+
+	feed(s, advance, time.Minute, errors.NewStd("failing"))
+	assert.Zero(t, restarts)
+
+	var nilSupervisor *uiSpectrogramSupervisor
+	assert.NotPanics(t, func() { nilSupervisor.observe(errors.NewStd("failing")) })
+}
+
+func TestUiSpectrogramSupervisor_UpdateSettings(t *testing.T) {
+	t.Parallel()
+
+	var restarts int
+	s, advance := newTestSupervisor(&restarts)
+	broadcastErr := errors.NewStd("SSE manager not initialized")
+
+	// Disabling supervision in place stops restarts
+	s.updateSettings(&conf.UiSpectrogramSettings{RestartErrorRate: 0, RestartErrorWindow: 10, RestartBackoff: 60})
+	feed(s, advance, time.Minute, broadcastErr)
+	assert.Zero(t, restarts)
+
+	// A shorter window restarts sooner than the one the supervisor was created with
+	s.updateSettings(&conf.UiSpectrogramSettings{RestartErrorRate: 0.9, RestartErrorWindow: 2, RestartBackoff: 60})
+	feed(s, advance, 3*time.Second, broadcastErr)
+	assert.Equal(t, 1, restarts)
+}
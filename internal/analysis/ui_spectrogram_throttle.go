@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"slices"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// uiSpectrogramFrameThrottler caps how many frames of each source are broadcast per second.
+// A source may send one frame per interval; frames arriving sooner are held, each replacing
+// the one held before it, and the newest is sent once the interval is up, so clients see the
+// latest audio at the capped rate. It is used by a single publisher goroutine and isn't safe
+// for concurrent use.
+type uiSpectrogramFrameThrottler struct {
+	interval time.Duration // Least time between two broadcast frames of a source
+	now      func() time.Time
+	sources  map[string]*uiSpectrogramThrottleState
+}
+
+// uiSpectrogramThrottleState is the throttle window of one source
+type uiSpectrogramThrottleState struct {
+	sent    time.Time                  // When the source's last frame was broadcast
+	pending *myaudio.UiSpectrogramData // Newest frame held for the end of the window, if any
+}
+
+// newUiSpectrogramFrameThrottler returns a throttler when a maximum frame rate is
+// configured, nil otherwise.
+func newUiSpectrogramFrameThrottler(settings *conf.UiSpectrogramSettings) *uiSpectrogramFrameThrottler {
+	if settings.SpectrogramMaxFPS <= 0 {
+		return nil
+	}
+	return &uiSpectrogramFrameThrottler{
+		interval: time.Second / time.Duration(settings.SpectrogramMaxFPS),
+		now:      time.Now,
+		sources:  make(map[string]*uiSpectrogramThrottleState),
+	}
+}
+
+// admit reports whether frame may be broadcast now. If not, it is held until due returns it,
+// and discarded tells how many held frames it replaced. A nil throttler admits every frame.
+func (t *uiSpectrogramFrameThrottler) admit(frame *myaudio.UiSpectrogramData) (send bool, discarded int) {
+	if t == nil {
+		return true, 0
+	}
+	now := t.now()
+	state, ok := t.sources[frame.Source]
+	if !ok {
+		state = &uiSpectrogramThrottleState{}
+		t.sources[frame.Source] = state
+	}
+	if state.pending == nil && now.Sub(state.sent) >= t.interval {
+		state.sent = now
+		return true, 0
+	}
+	if state.pending != nil {
+		discarded = 1
+	}
+	state.pending = frame
+	return false, discarded
+}
+
+// due returns the held frames whose source's interval is up, starting a new window for each.
+// A nil throttler holds nothing.
+func (t *uiSpectrogramFrameThrottler) due() []*myaudio.UiSpectrogramData {
+	return t.release(false)
+}
+
+// flush returns every held frame, whether or not its source's interval is up, starting a new
+// window for each. A nil throttler holds nothing.
+func (t *uiSpectrogramFrameThrottler) flush() []*myaudio.UiSpectrogramData {
+	return t.release(true)
+}
+
+// release returns the held frames of the sources whose interval is up, or of all sources
+// when all is set, starting a new window for each.
+func (t *uiSpectrogramFrameThrottler) release(all bool) []*myaudio.UiSpectrogramData {
+	if t == nil {
+		return nil
+	}
+	now := t.now()
+	var frames []*myaudio.UiSpectrogramData
+	for _, state := range t.sources {
+		if state.pending == nil || (!all && now.Sub(state.sent) < t.interval) {
+			continue
+		}
+		frames = append(frames, state.pending)
+		state.pending = nil
+		state.sent = now
+	}
+	// Sources share the stream, so send their frames in capture order
+	slices.SortFunc(frames, func(a, b *myaudio.UiSpectrogramData) int { return a.Timestamp.Compare(b.Timestamp) })
+	return frames
+}
+
+// reset drops the held frames and windows, so the next frame of each source is broadcast
+// right away. The publisher calls it while nobody watches. A nil throttler ignores it.
+func (t *uiSpectrogramFrameThrottler) reset() {
+	if t == nil || len(t.sources) == 0 {
+		return
+	}
+	clear(t.sources)
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// newTestThrottler returns a throttler capped at maxFPS that reads its time from now
+func newTestThrottler(t *testing.T, maxFPS int, now *time.Time) *uiSpectrogramFrameThrottler {
+	t.Helper()
+	throttler := newUiSpectrogramFrameThrottler(&conf.UiSpectrogramSettings{SpectrogramMaxFPS: maxFPS})
+	require.NotNil(t, throttler)
+	throttler.now = func() time.Time { return *now }
+	return throttler
+}
+
+func TestNewUiSpectrogramFrameThrottler_Disabled(t *testing.T) {
+	assert.Nil(t, newUiSpectrogramFrameThrottler(&conf.UiSpectrogramSettings{}))
+	send, discarded := (*uiSpectrogramFrameThrottler)(nil).admit(&myaudio.UiSpectrogramData{})
+	assert.True(t, send, "a nil throttler admits every frame")
+	assert.Zero(t, discarded)
+	assert.Empty(t, (*uiSpectrogramFrameThrottler)(nil).due())
+}
+
+func TestPublishUiSpectrogramFrame_ThrottlesToMaxFPS(t *testing.T) {
+	broadcaster := &fakeSpectrogramBroadcaster{}
+	broadcaster.clients.Store(1)
+	var stats uiSpectrogramPublishStats
+	errorLog := newUiSpectrogramErrorThrottle(time.Minute)
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	throttler := newTestThrottler(t, 10, &now)
+
+	// Frames arrive every 10ms, ten times the cap, and the publisher ticks every 100ms
+	const frames = 200
+	for i := range frames {
+		frame := myaudio.UiSpectrogramData{Source: "mic", Timestamp: now}
+		publishUiSpectrogramFrame(broadcaster, &frame, nil, nil, &stats, nil, nil, nil, nil, nil, nil, throttler, nil, nil, errorLog, GetLogger())
+		now = now.Add(10 * time.Millisecond)
+		if i%10 == 9 {
+			for _, held := range throttler.due() {
+				broadcastUiSpectrogramFrame(broadcaster, held, nil, &stats, nil, nil, nil, errorLog, GetLogger())
+			}
+		}
+	}
+
+	elapsed := time.Duration(frames) * 10 * time.Millisecond
+	sent := len(broadcaster.broadcast())
+	assert.InDelta(t, 10*elapsed.Seconds(), sent, 1, "broadcasts are capped at 10 per second")
+	snapshot := stats.snapshot()
+	assert.Equal(t, uint64(sent), snapshot.FramesBroadcast)
+	assert.Equal(t, uint64(frames-sent), snapshot.FramesThrottled, "the frames not sent are counted as throttled")
+}
+
+func TestUiSpectrogramFrameThrottler_SourcesHaveOwnWindows(t *testing.T) {
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	throttler := newTestThrottler(t, 2, &now)
+
+	send, _ := throttler.admit(&myaudio.UiSpectrogramData{Source: "mic"})
+	assert.True(t, send, "the first frame of a source is sent right away")
+	send, _ = throttler.admit(&myaudio.UiSpectrogramData{Source: "other"})
+	assert.True(t, send, "another source isn't held back by the first")
+
+	held := &myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{1}}
+	send, discarded := throttler.admit(&myaudio.UiSpectrogramData{Source: "mic"})
+	assert.False(t, send)
+	assert.Zero(t, discarded)
+	_, discarded = throttler.admit(held)
+	assert.Equal(t, 1, discarded, "a newer frame replaces the held one")
+	assert.Empty(t, throttler.due(), "the held frame waits for the end of the window")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, []*myaudio.UiSpectrogramData{held}, throttler.due())
+	send, _ = throttler.admit(&myaudio.UiSpectrogramData{Source: "mic"})
+	assert.False(t, send, "releasing the held frame starts a new window")
+
+	throttler.reset()
+	send, _ = throttler.admit(&myaudio.UiSpectrogramData{Source: "mic"})
+	assert.True(t, send, "a reset throttler sends the next frame")
+}
+
+func TestUiSpectrogramFrameThrottler_FlushReleasesHeldFrames(t *testing.T) {
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	throttler := newTestThrottler(t, 1, &now)
+
+	for _, source := range []string{"mic", "other"} {
+		throttler.admit(&myaudio.UiSpectrogramData{Source: source})
+	}
+	first := &myaudio.UiSpectrogramData{Source: "other", Timestamp: now}
+	second := &myaudio.UiSpectrogramData{Source: "mic", Timestamp: now.Add(time.Millisecond)}
+	throttler.admit(second)
+	throttler.admit(first)
+
+	assert.Empty(t, throttler.due())
+	assert.Equal(t, []*myaudio.UiSpectrogramData{first, second}, throttler.flush(), "held frames are released in capture order")
+	assert.Empty(t, throttler.flush(), "nothing is held once flushed")
+	assert.Empty(t, (*uiSpectrogramFrameThrottler)(nil).flush())
+}
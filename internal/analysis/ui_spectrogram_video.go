@@ -0,0 +1,384 @@
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// uiSpectrogramVideoQueueSize bounds the frames waiting on a slow encoder; more are dropped
+	uiSpectrogramVideoQueueSize = 64
+	// uiSpectrogramVideoStopTimeout is how long ffmpeg gets to finish a file before it is killed
+	uiSpectrogramVideoStopTimeout = 10 * time.Second
+	// uiSpectrogramVideoRetryDelay is the wait before starting a new file after the encoder failed
+	uiSpectrogramVideoRetryDelay = 30 * time.Second
+	// uiSpectrogramVideoFileTimeLayout is the start timestamp added to each output file name
+	uiSpectrogramVideoFileTimeLayout = "20060102T150405"
+)
+
+// uiSpectrogramVideoCodecArgs are the ffmpeg output arguments for each supported extension.
+// The MP4 is fragmented so a file cut short by a crash still plays up to the last fragment.
+var uiSpectrogramVideoCodecArgs = map[string][]string{
+	".mp4":  {"-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p", "-movflags", "+frag_keyframe+empty_moov"},
+	".webm": {"-c:v", "libvpx-vp9", "-deadline", "realtime", "-b:v", "0", "-crf", "40", "-pix_fmt", "yuv420p"},
+}
+
+// uiSpectrogramVideoWindow holds the newest columns of one source's spectrogram, which each
+// video frame shows with the oldest column on the left and the lowest frequency at the bottom.
+type uiSpectrogramVideoWindow struct {
+	width   int
+	bins    int
+	columns [][]byte // Ring of the newest columns, next is the oldest once full
+	next    int
+	palette *myaudio.UiSpectrogramPalette
+}
+
+// newUiSpectrogramVideoWindow returns an empty window of width columns with bins rows.
+func newUiSpectrogramVideoWindow(width, bins int) *uiSpectrogramVideoWindow {
+	return &uiSpectrogramVideoWindow{width: width, bins: bins, columns: make([][]byte, 0, width)}
+}
+
+// add appends the columns of a frame with the window's bin count.
+func (w *uiSpectrogramVideoWindow) add(frame *myaudio.UiSpectrogramData) {
+	if palette, ok := myaudio.LookupUiSpectrogramPalette(frame.Palette); ok {
+		w.palette = palette
+	}
+	for start := 0; start+w.bins <= len(frame.Spectrogram); start += w.bins {
+		column := frame.Spectrogram[start : start+w.bins]
+		if len(w.columns) < w.width {
+			w.columns = append(w.columns, slices.Clone(column))
+			continue
+		}
+		copy(w.columns[w.next], column)
+		w.next = (w.next + 1) % w.width
+	}
+}
+
+// render draws the window as packed 24-bit RGB into buf, reusing its storage. Columns not
+// filled yet are drawn in the palette's color for silence.
+func (w *uiSpectrogramVideoWindow) render(buf []byte) []byte {
+	palette := w.palette
+	if palette == nil {
+		palette, _ = myaudio.LookupUiSpectrogramPalette(conf.DefaultUiSpectrogramPalette)
+	}
+	size := w.width * w.bins * 3
+	if cap(buf) < size {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+
+	blank := len(w.columns) < w.width
+	for x := range w.width {
+		var column []byte
+		switch {
+		case blank && x >= w.width-len(w.columns):
+			column = w.columns[x-(w.width-len(w.columns))] // Fill from the right while the window is filling
+		case !blank:
+			column = w.columns[(w.next+x)%w.width]
+		}
+		for bin := range w.bins {
+			var v byte
+			if column != nil {
+				v = column[bin]
+			}
+			r, g, b := palette.Color(v)
+			i := ((w.bins-1-bin)*w.width + x) * 3
+			buf[i], buf[i+1], buf[i+2] = r, g, b
+		}
+	}
+	return buf
+}
+
+// uiSpectrogramVideoEncoder is one ffmpeg process encoding raw RGB frames into a video file.
+type uiSpectrogramVideoEncoder struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stderr  bytes.Buffer
+	path    string
+	started time.Time
+}
+
+// startUiSpectrogramVideoEncoder starts ffmpeg writing a width x height video to path.
+func startUiSpectrogramVideoEncoder(ffmpegPath, path string, fps, width, height int, codecArgs []string, now time.Time) (*uiSpectrogramVideoEncoder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.New(err).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryFileIO).
+			Context("operation", "create_video_dir").
+			Context("path", path).
+			Build()
+	}
+
+	args := []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-f", "rawvideo", "-pix_fmt", "rgb24",
+		"-s", fmt.Sprintf("%dx%d", width, height),
+		"-r", strconv.Itoa(fps),
+		"-i", "pipe:0",
+		// yuv420p needs even dimensions, so pad odd sizes with one blank row or column
+		"-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2",
+	}
+	args = append(args, codecArgs...)
+	args = append(args, path)
+
+	e := &uiSpectrogramVideoEncoder{path: path, started: now}
+	e.cmd = exec.Command(ffmpegPath, args...) //nolint:gosec // G204: ffmpegPath is from validated settings, args built internally
+	e.cmd.Stderr = &e.stderr
+	stdin, err := e.cmd.StdinPipe()
+	if err == nil {
+		e.stdin = stdin
+		err = e.cmd.Start()
+	}
+	if err != nil {
+		return nil, errors.New(err).
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryCommandExecution).
+			Context("operation", "start_video_encoder").
+			Context("path", path).
+			Build()
+	}
+	return e, nil
+}
+
+// write sends one raw frame to the encoder. A failed write means ffmpeg has exited; the
+// error close returns then carries its output.
+func (e *uiSpectrogramVideoEncoder) write(frame []byte) error {
+	_, err := e.stdin.Write(frame)
+	return err
+}
+
+// close ends the input so ffmpeg finishes the file, killing it if it doesn't exit in time.
+func (e *uiSpectrogramVideoEncoder) close() error {
+	_ = e.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- e.cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return e.failure(err, "finish_video_file")
+		}
+		return nil
+	case <-time.After(uiSpectrogramVideoStopTimeout):
+		_ = e.cmd.Process.Kill()
+		<-done
+		return e.failure(fmt.Errorf("ffmpeg did not exit within %s", uiSpectrogramVideoStopTimeout), "finish_video_file")
+	}
+}
+
+// failure wraps an encoder error with ffmpeg's error output.
+func (e *uiSpectrogramVideoEncoder) failure(err error, operation string) error {
+	return errors.New(err).
+		Component("analysis.uispectrogram").
+		Category(errors.CategoryCommandExecution).
+		Context("operation", operation).
+		Context("path", e.path).
+		Context("ffmpeg_output", strings.TrimSpace(e.stderr.String())).
+		Build()
+}
+
+// uiSpectrogramVideoRecorder pipes the spectrogram of one source to ffmpeg as a time-lapse
+// video. Frames are offered from the SSE publisher's loop and encoded from a separate
+// goroutine, so a slow encoder never stalls the stream. A new file is started whenever a
+// rollover limit is reached or the frame's bin count changes.
+type uiSpectrogramVideoRecorder struct {
+	settings   conf.UiSpectrogramVideoSettings
+	ffmpegPath string
+	codecArgs  []string
+	log        logger.Logger
+
+	queue chan myaudio.UiSpectrogramData
+	quit  chan struct{}
+	wg    sync.WaitGroup
+	err   error // Error finishing the last file, returned by Stop
+
+	// Owned by the recording goroutine
+	source      string
+	window      *uiSpectrogramVideoWindow
+	encoder     *uiSpectrogramVideoEncoder
+	buf         []byte
+	lastCapture time.Time
+	retryAt     time.Time
+}
+
+// newUiSpectrogramVideoRecorder returns a recorder when video recording is enabled, nil
+// otherwise, which offer treats as a no-op.
+func newUiSpectrogramVideoRecorder(settings *conf.UiSpectrogramVideoSettings, ffmpegPath string, log logger.Logger) (*uiSpectrogramVideoRecorder, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+	if ffmpegPath == "" {
+		return nil, errors.Newf("spectrogram video recording needs ffmpeg, which was not found").
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryConfiguration).
+			Build()
+	}
+	codecArgs, ok := uiSpectrogramVideoCodecArgs[strings.ToLower(filepath.Ext(settings.Path))]
+	if !ok || settings.FPS <= 0 || settings.Width <= 0 {
+		return nil, errors.Newf("spectrogram video needs an .mp4 or .webm path and a positive fps and width").
+			Component("analysis.uispectrogram").
+			Category(errors.CategoryConfiguration).
+			Context("path", settings.Path).
+			Context("fps", settings.FPS).
+			Context("width", settings.Width).
+			Build()
+	}
+
+	return &uiSpectrogramVideoRecorder{
+		settings:   *settings,
+		ffmpegPath: ffmpegPath,
+		codecArgs:  codecArgs,
+		log:        log,
+		source:     settings.Source,
+		queue:      make(chan myaudio.UiSpectrogramData, uiSpectrogramVideoQueueSize),
+		quit:       make(chan struct{}),
+	}, nil
+}
+
+// Start starts recording offered frames.
+func (r *uiSpectrogramVideoRecorder) Start() {
+	r.wg.Go(func() {
+		r.log.Info("Started UI spectrogram video recorder", logger.String("path", r.settings.Path))
+		for {
+			select {
+			case <-r.quit:
+				r.finishFile()
+				r.log.Info("Stopped UI spectrogram video recorder")
+				return
+			case frame := <-r.queue:
+				r.record(&frame)
+			}
+		}
+	})
+}
+
+// Stop finishes the current file and stops recording. It returns the error finishing the
+// file, if any.
+func (r *uiSpectrogramVideoRecorder) Stop() error {
+	close(r.quit)
+	r.wg.Wait()
+	return r.err
+}
+
+// offer queues a frame for recording. It never blocks, and a nil recorder ignores the frame.
+func (r *uiSpectrogramVideoRecorder) offer(frame *myaudio.UiSpectrogramData) {
+	if r == nil {
+		return
+	}
+	select {
+	case r.queue <- *frame:
+	default:
+		// Encoder is backed up; a time-lapse can do without this frame
+	}
+}
+
+// record adds a frame to the window and writes a video frame once the capture interval
+// has passed since the last one.
+func (r *uiSpectrogramVideoRecorder) record(frame *myaudio.UiSpectrogramData) {
+	if r.source == "" {
+		r.source = frame.Source
+	}
+	bins := frame.ColumnBins()
+	if frame.Source != r.source || bins == 0 || len(frame.Spectrogram) == 0 {
+		return
+	}
+
+	if r.window == nil || r.window.bins != bins {
+		// A video's frame size is fixed, so a change in bins starts a new file
+		r.finishFile()
+		r.window = newUiSpectrogramVideoWindow(r.settings.Width, bins)
+	}
+	r.window.add(frame)
+
+	interval := time.Duration(r.settings.CaptureInterval) * time.Millisecond
+	if !r.lastCapture.IsZero() && frame.Timestamp.Sub(r.lastCapture) < interval {
+		return
+	}
+	r.lastCapture = frame.Timestamp
+
+	now := time.Now()
+	if r.encoder != nil && r.shouldRollOver(now) {
+		r.finishFile()
+	}
+	if r.encoder == nil {
+		if now.Before(r.retryAt) {
+			return
+		}
+		path := uiSpectrogramVideoFilePath(r.settings.Path, now)
+		encoder, err := startUiSpectrogramVideoEncoder(r.ffmpegPath, path, r.settings.FPS, r.window.width, r.window.bins, r.codecArgs, now)
+		if err != nil {
+			r.fail(err, now)
+			return
+		}
+		r.encoder = encoder
+		r.log.Info("Started UI spectrogram video file", logger.String("path", path))
+	}
+
+	r.buf = r.window.render(r.buf)
+	if err := r.encoder.write(r.buf); err != nil {
+		// The process has died; reap it so its error output is logged with the failure
+		if closeErr := r.encoder.close(); closeErr != nil {
+			err = closeErr
+		}
+		r.encoder = nil
+		r.fail(err, now)
+	}
+}
+
+// shouldRollOver reports whether the current file reached its time or size limit.
+func (r *uiSpectrogramVideoRecorder) shouldRollOver(now time.Time) bool {
+	if m := r.settings.RolloverMinutes; m > 0 && now.Sub(r.encoder.started) >= time.Duration(m)*time.Minute {
+		return true
+	}
+	if mb := r.settings.RolloverSizeMB; mb > 0 {
+		if info, err := os.Stat(r.encoder.path); err == nil && info.Size() >= int64(mb)<<20 {
+			return true
+		}
+	}
+	return false
+}
+
+// finishFile closes the current file, if any.
+func (r *uiSpectrogramVideoRecorder) finishFile() {
+	if r.encoder == nil {
+		return
+	}
+	path := r.encoder.path
+	r.err = r.encoder.close()
+	r.encoder = nil
+	if r.err != nil {
+		r.log.Warn("Failed to finish UI spectrogram video file", logger.Error(r.err), logger.String("path", path))
+		return
+	}
+	r.log.Info("Finished UI spectrogram video file", logger.String("path", path))
+}
+
+// fail logs an encoder failure and holds off starting a new file for the retry delay.
+func (r *uiSpectrogramVideoRecorder) fail(err error, now time.Time) {
+	r.err = err
+	r.retryAt = now.Add(uiSpectrogramVideoRetryDelay)
+	r.log.Warn("UI spectrogram video encoder failed, retrying later",
+		logger.Error(err),
+		logger.Duration("retry_in", uiSpectrogramVideoRetryDelay))
+}
+
+// uiSpectrogramVideoFilePath returns the output path with start added to the file name,
+// so each rollover gets its own file.
+func uiSpectrogramVideoFilePath(path string, start time.Time) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(path, ext), start.Format(uiSpectrogramVideoFileTimeLayout), ext)
+}
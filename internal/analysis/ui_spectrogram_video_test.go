@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestUiSpectrogramVideoWindow_ScrollsOldestLeft(t *testing.T) {
+	t.Parallel()
+
+	palette, ok := myaudio.LookupUiSpectrogramPalette("grayscale")
+	require.True(t, ok)
+	window := newUiSpectrogramVideoWindow(3, 2)
+
+	// bottomRow returns the palette colors expected along the bottom row, the lowest bin
+	bottomRow := func(levels ...byte) []byte {
+		var row []byte
+		for _, v := range levels {
+			r, g, b := palette.Color(v)
+			row = append(row, r, g, b)
+		}
+		return row
+	}
+
+	// Two columns of 2 bins per frame; the window fills from the right
+	window.add(&myaudio.UiSpectrogramData{Spectrogram: []byte{10, 11, 20, 21}, Palette: palette.Name})
+	rgb := window.render(nil)
+	require.Len(t, rgb, 3*2*3)
+	assert.Equal(t, bottomRow(0, 10, 20), rgb[3*3:], "an unfilled column is blank")
+	assert.Equal(t, bottomRow(0, 11, 21), rgb[:3*3], "higher bins are drawn above lower ones")
+
+	// Once full, the oldest column scrolls off the left
+	window.add(&myaudio.UiSpectrogramData{Spectrogram: []byte{30, 31, 40, 41}, Palette: palette.Name})
+	rgb = window.render(rgb)
+	assert.Equal(t, bottomRow(20, 30, 40), rgb[3*3:])
+}
+
+func TestNewUiSpectrogramVideoRecorder_Settings(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := newUiSpectrogramVideoRecorder(&conf.UiSpectrogramVideoSettings{}, "ffmpeg", GetLogger())
+	require.NoError(t, err)
+	assert.Nil(t, recorder, "recording is off unless enabled")
+	recorder.offer(&myaudio.UiSpectrogramData{}) // A nil recorder ignores frames
+
+	_, err = newUiSpectrogramVideoRecorder(&conf.UiSpectrogramVideoSettings{Enabled: true, Path: "night.avi", FPS: 30, Width: 64}, "ffmpeg", GetLogger())
+	require.Error(t, err, "only mp4 and webm are supported")
+
+	_, err = newUiSpectrogramVideoRecorder(&conf.UiSpectrogramVideoSettings{Enabled: true, Path: "night.mp4", FPS: 30, Width: 64}, "", GetLogger())
+	require.Error(t, err, "recording needs ffmpeg")
+}
+
+func TestUiSpectrogramVideoRecorder_EncodesFile(t *testing.T) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("ffmpeg not available")
+	}
+
+	dir := t.TempDir()
+	settings := conf.UiSpectrogramVideoSettings{
+		Enabled: true,
+		Path:    filepath.Join(dir, "video", "night.mp4"),
+		FPS:     10,
+		Width:   32,
+	}
+	recorder, err := newUiSpectrogramVideoRecorder(&settings, ffmpegPath, GetLogger())
+	require.NoError(t, err)
+	recorder.Start()
+
+	start := time.Now()
+	spectrogram := make([]byte, 4*33) // Four columns of an odd bin count, padded by the encoder
+	for i := range spectrogram {
+		spectrogram[i] = byte(i)
+	}
+	for i := range 30 {
+		frame := myaudio.UiSpectrogramData{Source: "mic", Timestamp: start.Add(time.Duration(i) * 50 * time.Millisecond), Spectrogram: spectrogram, Bins: 33}
+		recorder.queue <- frame // Block rather than drop so every frame is encoded
+	}
+	require.NoError(t, recorder.Stop())
+
+	files, err := filepath.Glob(filepath.Join(dir, "video", "night-*.mp4"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Positive(t, info.Size(), "the encoder must produce a non-empty video")
+}
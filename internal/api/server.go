@@ -59,8 +59,8 @@ type Server struct {
 	authMiddleware echo.MiddlewareFunc
 
 	// Channels
-	controlChan    chan string
-	audioLevelChan chan myaudio.AudioLevelData
+	controlChan     chan string
+	audioLevelChan  chan myaudio.AudioLevelData
 	spectrogramChan chan myaudio.UiSpectrogramData
 
 	// API controller
@@ -299,6 +299,11 @@ func (s *Server) setupRoutes() error {
 		s.apiController.SetAudioLevelChan(s.audioLevelChan)
 	}
 
+	// Set spectrogram channel for clip replays
+	if s.spectrogramChan != nil {
+		s.apiController.SetSpectrogramChan(s.spectrogramChan)
+	}
+
 	// Register SPA routes (after API controller for auth middleware access)
 	s.registerSPARoutes()
 
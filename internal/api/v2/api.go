@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -78,9 +79,33 @@ type Controller struct {
 
 	// Audio level channel for SSE streaming
 	// TODO: Consider moving to a dedicated audio manager
-	audioLevelChan chan myaudio.AudioLevelData
+	audioLevelChan  chan myaudio.AudioLevelData
 	spectrogramChan chan myaudio.UiSpectrogramData
 
+	// spectrogramReplayActive guards against concurrent WAV replays into spectrogramChan
+	spectrogramReplayActive atomic.Bool
+
+	// clipSpectrogram generates clip spectrogram frames; nil uses the live UI spectrogram model
+	clipSpectrogram myaudio.SpectrogramFunc
+
+	// spectrogramAnnotations holds the labeled regions shared on the spectrogram stream
+	spectrogramAnnotations spectrogramAnnotationStore
+
+	// spectrogramHistory holds the latest broadcast frames for debug bundles
+	spectrogramHistory spectrogramFrameHistory
+
+	// spectrogramPNGViewers counts the open PNG live streams, which render from spectrogramHistory
+	spectrogramPNGViewers atomic.Int64
+
+	// spectrogramConfig reports the running UI spectrogram manager's applied configuration
+	spectrogramConfig atomic.Pointer[func() myaudio.UiSpectrogramConfig]
+	// spectrogramStats reports the UI spectrogram publisher's frame counters
+	spectrogramStats atomic.Pointer[func() myaudio.UiSpectrogramStats]
+	// spectrogramHealth reports whether the UI spectrogram feed is healthy, degraded or stopped
+	spectrogramHealth atomic.Pointer[func() myaudio.UiSpectrogramHealth]
+	// spectrogramRecentFrames returns the frames sent to newly connected spectrogram clients
+	spectrogramRecentFrames atomic.Pointer[func() []myaudio.UiSpectrogramData]
+
 	// Test synchronization fields (only populated when initializeRoutes is true)
 	// goroutinesStarted signals when all background goroutines have successfully started.
 	// This is primarily used in testing to ensure proper setup before assertions.
@@ -466,6 +491,11 @@ func (c *Controller) initRoutes() {
 		{"debug routes", c.initDebugRoutes},
 		{"species routes", c.initSpeciesRoutes},
 		{"dynamic threshold routes", c.initDynamicThresholdRoutes},
+		{"life list routes", c.initLifeListRoutes},
+		{"cooldown routes", c.initCooldownRoutes},
+		{"detection sink routes", c.initDetectionSinkRoutes},
+		{"processor threshold routes", c.initProcessorThresholdRoutes},
+		{"config export routes", c.initConfigExportRoutes},
 	}
 
 	for _, initializer := range routeInitializers {
@@ -563,6 +593,11 @@ func (c *Controller) HealthCheck(ctx echo.Context) error {
 // Shutdown performs cleanup of all resources used by the API controller
 // This should be called when the application is shutting down
 func (c *Controller) Shutdown() {
+	// Fail spectrogram broadcasts from here on, so the publisher stops rather than retrying
+	if c.sseManager != nil {
+		c.sseManager.Close()
+	}
+
 	// Cancel context to stop all goroutines
 	if c.cancel != nil {
 		c.cancel()
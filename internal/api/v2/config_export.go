@@ -0,0 +1,94 @@
+// internal/api/v2/config_export.go
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// AnalysisConfigExport is the effective analysis configuration, with the settings
+// the analysis runs on rather than the raw config file: empty values are shown as the
+// defaults they resolve to, and paths and URLs are anonymized.
+type AnalysisConfigExport struct {
+	ExportedAt          time.Time                        `json:"exportedAt"`
+	BirdNET             conf.BirdNETConfig               `json:"birdnet"`
+	SoundId             conf.SoundIdConfig               `json:"soundId"`
+	DynamicThreshold    conf.DynamicThresholdSettings    `json:"dynamicThreshold"`
+	FalsePositiveFilter conf.FalsePositiveFilterSettings `json:"falsePositiveFilter"`
+	PrivacyFilter       conf.PrivacyFilterSettings       `json:"privacyFilter"`
+	DogBarkFilter       conf.DogBarkFilterSettings       `json:"dogBarkFilter"`
+	Species             conf.SpeciesSettings             `json:"species"`
+}
+
+// initConfigExportRoutes registers the configuration export endpoint
+func (c *Controller) initConfigExportRoutes() {
+	c.Group.GET("/config/analysis", c.GetAnalysisConfig, c.authMiddleware)
+}
+
+// GetAnalysisConfig handles GET /api/v2/config/analysis
+// Returns the effective analysis configuration for support requests and reproducing results
+func (c *Controller) GetAnalysisConfig(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, newAnalysisConfigExport(c.Settings, time.Now()))
+}
+
+// newAnalysisConfigExport builds the redacted export of settings.
+func newAnalysisConfigExport(settings *conf.Settings, now time.Time) AnalysisConfigExport {
+	base := newDebugBundleConfig(settings)
+	export := AnalysisConfigExport{
+		ExportedAt:          now,
+		BirdNET:             base.BirdNET,
+		SoundId:             base.SoundId,
+		DynamicThreshold:    settings.Realtime.DynamicThreshold,
+		FalsePositiveFilter: settings.Realtime.FalsePositiveFilter,
+		PrivacyFilter:       settings.Realtime.PrivacyFilter,
+		DogBarkFilter:       settings.Realtime.DogBarkFilter,
+		Species:             redactSpeciesActions(settings.Realtime.Species),
+	}
+	resolveSoundIdDefaults(&export.SoundId)
+	return export
+}
+
+// resolveSoundIdDefaults replaces empty Sound ID settings with the values the analysis
+// uses in their place.
+func resolveSoundIdDefaults(s *conf.SoundIdConfig) {
+	if s.LifeListEncoding == "" {
+		s.LifeListEncoding = "utf-8"
+	}
+	if s.LifeListCollisionPolicy == "" {
+		s.LifeListCollisionPolicy = conf.LifeListCollisionWarn
+	}
+	if s.EmptyNamePolicy == "" {
+		s.EmptyNamePolicy = conf.EmptyNamePolicyDrop
+	}
+	if s.UiSpectrogram.Mode == "" {
+		s.UiSpectrogram.Mode = conf.UiSpectrogramModeNormal
+	}
+	if s.UiSpectrogram.Palette == "" {
+		s.UiSpectrogram.Palette = conf.DefaultUiSpectrogramPalette
+	}
+}
+
+// redactSpeciesActions returns a copy of the species settings with the paths of action
+// commands anonymized. The config map is copied so the live settings aren't modified.
+func redactSpeciesActions(species conf.SpeciesSettings) conf.SpeciesSettings {
+	if len(species.Config) == 0 {
+		return species
+	}
+
+	configs := make(map[string]conf.SpeciesConfig, len(species.Config))
+	for name, cfg := range species.Config {
+		actions := make([]conf.SpeciesAction, len(cfg.Actions))
+		for i, action := range cfg.Actions {
+			action.Command = privacy.AnonymizePath(action.Command)
+			actions[i] = action
+		}
+		cfg.Actions = actions
+		configs[name] = cfg
+	}
+	species.Config = configs
+	return species
+}
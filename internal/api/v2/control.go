@@ -24,11 +24,19 @@ type ControlResult struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// DetectionStatus reports whether detection processing is paused
+type DetectionStatus struct {
+	Paused    bool      `json:"paused"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // Available control actions
 const (
 	ActionRestartAnalysis = "restart_analysis"
 	ActionReloadModel     = "reload_model"
 	ActionRebuildFilter   = "rebuild_filter"
+	ActionPauseDetection  = "pause_detection"
+	ActionResumeDetection = "resume_detection"
 )
 
 // Control channel signals
@@ -50,6 +58,9 @@ func (c *Controller) initControlRoutes() {
 	controlGroup.POST("/reload", c.ReloadModel)
 	controlGroup.POST("/rebuild-filter", c.RebuildFilter)
 	controlGroup.GET("/actions", c.GetAvailableActions)
+	controlGroup.POST("/detection/pause", c.PauseDetection)
+	controlGroup.POST("/detection/resume", c.ResumeDetection)
+	controlGroup.GET("/detection/status", c.GetDetectionStatus)
 
 	c.logInfoIfEnabled("Control routes initialized successfully")
 }
@@ -168,3 +179,63 @@ func (c *Controller) RebuildFilter(ctx echo.Context) error {
 	return c.handleControlSignal(ctx, SignalRebuildFilter, ActionRebuildFilter,
 		"Received request to rebuild species filter", "Filter rebuild signal sent")
 }
+
+// PauseDetection handles POST /api/v2/control/detection/pause
+// Stops finalizing detections and events while audio and the live spectrogram keep running
+func (c *Controller) PauseDetection(ctx echo.Context) error {
+	return c.setDetectionPaused(ctx, true, ActionPauseDetection)
+}
+
+// ResumeDetection handles POST /api/v2/control/detection/resume
+// Resumes detection processing after a pause
+func (c *Controller) ResumeDetection(ctx echo.Context) error {
+	return c.setDetectionPaused(ctx, false, ActionResumeDetection)
+}
+
+// GetDetectionStatus handles GET /api/v2/control/detection/status
+// Returns whether detection processing is currently paused
+func (c *Controller) GetDetectionStatus(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	return ctx.JSON(http.StatusOK, DetectionStatus{
+		Paused:    c.Processor.IsDetectionPaused(),
+		Timestamp: time.Now(),
+	})
+}
+
+// setDetectionPaused applies a pause or resume request to the processor
+func (c *Controller) setDetectionPaused(ctx echo.Context, paused bool, action string) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	changed := c.Processor.SetDetectionPaused(paused)
+	var message string
+	switch {
+	case paused && changed:
+		message = "Detection processing paused"
+	case paused:
+		message = "Detection processing already paused"
+	case changed:
+		message = "Detection processing resumed"
+	default:
+		message = "Detection processing already running"
+	}
+
+	c.logInfoIfEnabled(message,
+		logger.String("action", action),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	return ctx.JSON(http.StatusOK, ControlResult{
+		Success:   true,
+		Message:   message,
+		Action:    action,
+		Timestamp: time.Now(),
+	})
+}
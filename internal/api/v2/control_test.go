@@ -22,6 +22,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 )
 
 // runControlEndpointTest runs a control endpoint test with the given parameters
@@ -326,9 +327,43 @@ func TestInitControlRoutesRegistration(t *testing.T) {
 		"POST /api/v2/control/restart",
 		"POST /api/v2/control/reload",
 		"POST /api/v2/control/rebuild-filter",
+		"POST /api/v2/control/detection/pause",
+		"POST /api/v2/control/detection/resume",
+		"GET /api/v2/control/detection/status",
 	})
 }
 
+// TestDetectionPauseEndpoints verifies pausing and resuming is reflected in the status endpoint
+func TestDetectionPauseEndpoints(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Processor = &processor.Processor{}
+
+	getStatus := func() DetectionStatus {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/control/detection/status", http.NoBody)
+		require.NoError(t, controller.GetDetectionStatus(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var status DetectionStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		return status
+	}
+
+	assert.False(t, getStatus().Paused)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/control/detection/pause", http.NoBody)
+	require.NoError(t, controller.PauseDetection(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, getStatus().Paused)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/v2/control/detection/resume", http.NoBody)
+	require.NoError(t, controller.ResumeDetection(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, getStatus().Paused)
+}
+
 // TestControlResultStructure verifies the ControlResult struct works as expected
 func TestControlResultStructure(t *testing.T) {
 	// Create a ControlResult
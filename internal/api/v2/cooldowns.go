@@ -0,0 +1,90 @@
+// internal/api/v2/cooldowns.go
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// CooldownResponse represents a species whose events of one type are suppressed
+type CooldownResponse struct {
+	Species          string    `json:"species"`
+	Event            string    `json:"event"`
+	RemainingSeconds float64   `json:"remainingSeconds"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// initCooldownRoutes registers the processor cooldown endpoints
+func (c *Controller) initCooldownRoutes() {
+	c.Group.GET("/processor/cooldowns", c.GetCooldowns)
+	c.Group.DELETE("/processor/cooldowns", c.ResetCooldowns, c.authMiddleware)
+}
+
+// GetCooldowns handles GET /api/v2/processor/cooldowns
+// Lists the species currently in cooldown for each event type with the time remaining
+func (c *Controller) GetCooldowns(ctx echo.Context) error {
+	tracker := c.eventTracker()
+	if tracker == nil {
+		return c.eventTrackerUnavailable(ctx)
+	}
+
+	now := time.Now()
+	cooldowns := tracker.ActiveCooldowns(now)
+	result := make([]CooldownResponse, 0, len(cooldowns))
+	for _, cd := range cooldowns {
+		result = append(result, CooldownResponse{
+			Species:          cd.Species,
+			Event:            cd.EventType.String(),
+			RemainingSeconds: cd.Remaining.Seconds(),
+			ExpiresAt:        now.Add(cd.Remaining),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"data":  result,
+		"total": len(result),
+	})
+}
+
+// ResetCooldowns handles DELETE /api/v2/processor/cooldowns
+// Clears every cooldown, so the next detection of any species is processed
+func (c *Controller) ResetCooldowns(ctx echo.Context) error {
+	tracker := c.eventTracker()
+	if tracker == nil {
+		return c.eventTrackerUnavailable(ctx)
+	}
+
+	count := tracker.ResetAllEvents()
+
+	c.logInfoIfEnabled("Reset processor cooldowns",
+		logger.Int("count", count),
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"success": true,
+		"message": "All cooldowns reset successfully",
+		"count":   count,
+	})
+}
+
+// eventTracker returns the processor's event tracker, or nil when there is none.
+func (c *Controller) eventTracker() *processor.EventTracker {
+	if c.Processor == nil {
+		return nil
+	}
+	return c.Processor.GetEventTracker()
+}
+
+// eventTrackerUnavailable responds that cooldown state can't be accessed.
+func (c *Controller) eventTrackerUnavailable(ctx echo.Context) error {
+	return c.HandleError(ctx, errors.Newf("event tracker not available").
+		Category(errors.CategorySystem).
+		Component("api-cooldowns").
+		Build(), "Processor not available", http.StatusServiceUnavailable)
+}
@@ -0,0 +1,64 @@
+// cooldowns_test.go: Tests for the processor cooldown endpoints
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestCooldowns_ListAndReset(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	tracker := processor.NewEventTracker(time.Minute)
+	controller.Processor = &processor.Processor{Settings: &conf.Settings{}, EventTracker: tracker}
+	require.True(t, tracker.TrackEventWithNames("Great Tit", "Parus major", processor.DatabaseSave))
+	require.False(t, tracker.TrackEventWithNames("Great Tit", "Parus major", processor.DatabaseSave), "the species is in cooldown")
+
+	list := func() []CooldownResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/processor/cooldowns", http.NoBody)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.GetCooldowns(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body struct {
+			Data []CooldownResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	cooldowns := list()
+	require.Len(t, cooldowns, 1)
+	assert.Equal(t, "great tit", cooldowns[0].Species)
+	assert.Equal(t, "database_save", cooldowns[0].Event)
+	assert.InDelta(t, 60, cooldowns[0].RemainingSeconds, 5)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v2/processor/cooldowns", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ResetCooldowns(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Empty(t, list())
+	assert.True(t, tracker.TrackEventWithNames("Great Tit", "Parus major", processor.DatabaseSave),
+		"a detection right after the reset must be processed")
+}
+
+func TestCooldowns_NoProcessor(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Processor = nil
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/processor/cooldowns", http.NoBody)
+	rec := httptest.NewRecorder()
+	_ = controller.GetCooldowns(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -0,0 +1,163 @@
+// internal/api/v2/detection_bundle.go
+package api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Entry names inside a detection bundle zip (the audio entry keeps the clip's extension)
+const (
+	bundleAudioBaseName   = "audio"
+	bundleSpectrogramName = "spectrogram.png"
+	bundleMetadataName    = "metadata.json"
+)
+
+// DetectionBundleMetadata is the metadata.json entry of a detection bundle
+type DetectionBundleMetadata struct {
+	ID             uint      `json:"id"`
+	Date           string    `json:"date"`
+	Time           string    `json:"time"`
+	CommonName     string    `json:"common_name"`
+	ScientificName string    `json:"scientific_name"`
+	Confidence     float64   `json:"confidence"`
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	SourceNode     string    `json:"source_node"`
+	ClipName       string    `json:"clip_name"`
+	BeginTime      time.Time `json:"begin_time"`
+	EndTime        time.Time `json:"end_time"`
+	Verified       string    `json:"verified,omitempty"`
+	ExportedAt     time.Time `json:"exported_at"`
+}
+
+// ServeDetectionBundle streams a zip containing a detection's audio clip, its rendered
+// spectrogram PNG and a JSON metadata file.
+//
+// Route: GET /api/v2/media/bundle/:id
+//
+// Query parameters are the same as for GET /api/v2/spectrogram/:id (size, width, raw) and
+// select which spectrogram rendering is bundled. The spectrogram follows the spectrogram
+// generation mode like that endpoint: in user-requested mode it must have been generated
+// already, otherwise a missing one is generated through the shared generation queue.
+func (c *Controller) ServeDetectionBundle(ctx echo.Context) error {
+	noteID, clipPath, err := c.validateNoteIDAndGetClipPath(ctx)
+	if err != nil || ctx.Response().Committed {
+		return err // Error response already written and logged
+	}
+
+	note, err := c.DS.Get(noteID)
+	if err != nil {
+		return c.HandleError(ctx, err, "Detection not found", http.StatusNotFound)
+	}
+
+	relAudioPath, err := c.normalizeAndValidatePathWithLogger(clipPath, c.apiLogger)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid clip path", http.StatusBadRequest)
+	}
+	if err := c.checkAudioFileExists(relAudioPath); err != nil {
+		return c.HandleError(ctx, err, "No audio clip available for this note", http.StatusNotFound)
+	}
+
+	params := parseSpectrogramParameters(ctx)
+	if c.Settings.Realtime.Dashboard.Spectrogram.GetMode() == conf.SpectrogramModeUserRequested {
+		_, _, _, relSpectrogramPath := buildSpectrogramPaths(relAudioPath, params.width, params.raw)
+		if _, err := c.SFS.StatRel(relSpectrogramPath); err != nil {
+			_, err := c.returnSpectrogramNotGeneratedError(ctx)
+			return err
+		}
+	}
+	relSpectrogramPath, err := c.generateSpectrogram(ctx.Request().Context(), clipPath, params.width, params.raw)
+	if err != nil {
+		return c.spectrogramHTTPError(ctx, err)
+	}
+
+	metadata, err := json.MarshalIndent(DetectionBundleMetadata{
+		ID:             note.ID,
+		Date:           note.Date,
+		Time:           note.Time,
+		CommonName:     note.CommonName,
+		ScientificName: note.ScientificName,
+		Confidence:     note.Confidence,
+		Latitude:       note.Latitude,
+		Longitude:      note.Longitude,
+		SourceNode:     note.SourceNode,
+		ClipName:       note.ClipName,
+		BeginTime:      note.BeginTime,
+		EndTime:        note.EndTime,
+		Verified:       note.Verified,
+		ExportedAt:     time.Now(),
+	}, "", "  ")
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to encode detection metadata", http.StatusInternalServerError)
+	}
+
+	// Headers must be written before the first zip byte; errors after this point can only be logged
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "application/zip")
+	resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("detection-%s.zip", noteID)))
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(resp)
+	audioEntry := bundleAudioBaseName + filepath.Ext(relAudioPath)
+	if err := c.writeBundleFile(zw, audioEntry, relAudioPath); err != nil {
+		return c.logBundleError(ctx, noteID, audioEntry, err)
+	}
+	if err := c.writeBundleFile(zw, bundleSpectrogramName, relSpectrogramPath); err != nil {
+		return c.logBundleError(ctx, noteID, bundleSpectrogramName, err)
+	}
+	w, err := zw.Create(bundleMetadataName)
+	if err == nil {
+		_, err = w.Write(metadata)
+	}
+	if err != nil {
+		return c.logBundleError(ctx, noteID, bundleMetadataName, err)
+	}
+	if err := zw.Close(); err != nil {
+		return c.logBundleError(ctx, noteID, "", err)
+	}
+
+	c.logInfoIfEnabled("Served detection bundle",
+		logger.String("note_id", noteID),
+		logger.String("clip_path", relAudioPath),
+		logger.String("ip", ctx.RealIP()))
+	return nil
+}
+
+// writeBundleFile copies a file relative to the SecureFS root into the zip under name.
+func (c *Controller) writeBundleFile(zw *zip.Writer, name, relPath string) error {
+	file, err := c.SFS.Open(filepath.Join(c.SFS.BaseDir(), relPath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// logBundleError logs a failure that happened after the zip stream started. The response
+// is already committed at that point, so the truncated zip is left for the client to reject.
+func (c *Controller) logBundleError(ctx echo.Context, noteID, entry string, err error) error {
+	c.logErrorIfEnabled("Failed to write detection bundle",
+		logger.String("note_id", noteID),
+		logger.String("entry", entry),
+		logger.Error(err),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()))
+	return nil
+}
@@ -0,0 +1,123 @@
+// detection_bundle_test.go: Tests for the detection clip + spectrogram zip bundle endpoint
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+)
+
+func TestServeDetectionBundle(t *testing.T) {
+	e, controller, tempDir := setupMediaTestEnvironment(t)
+
+	clipName := "2024-01-15_14-30-45_Turdus_migratorius.wav"
+	audioContent := []byte("test audio content")
+	pngContent := []byte("\x89PNG test spectrogram")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, clipName), audioContent, 0o600))
+
+	// Pre-render the spectrogram the default parameters resolve to so no generator is needed
+	_, _, _, relSpectrogram := buildSpectrogramPaths(clipName, SpectrogramSizeMd, true)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, relSpectrogram), pngContent, 0o600))
+
+	mockDS := mocks.NewMockInterface(t)
+	mockDS.On("GetNoteClipPath", "42").Return(clipName, nil)
+	mockDS.On("Get", "42").Return(datastore.Note{
+		ID:             42,
+		CommonName:     "American Robin",
+		ScientificName: "Turdus migratorius",
+		Confidence:     0.87,
+		ClipName:       clipName,
+	}, nil)
+	controller.DS = mockDS
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/media/bundle/42", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+
+	require.NoError(t, controller.ServeDetectionBundle(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `attachment; filename="detection-42.zip"`)
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+
+	entries := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		entries[f.Name] = data
+	}
+
+	require.Len(t, entries, 3)
+	assert.Equal(t, audioContent, entries["audio.wav"])
+	assert.Equal(t, pngContent, entries[bundleSpectrogramName])
+	require.NotEmpty(t, entries[bundleMetadataName])
+
+	var meta DetectionBundleMetadata
+	require.NoError(t, json.Unmarshal(entries[bundleMetadataName], &meta))
+	assert.Equal(t, uint(42), meta.ID)
+	assert.Equal(t, "Turdus migratorius", meta.ScientificName)
+	assert.Equal(t, clipName, meta.ClipName)
+}
+
+func TestServeDetectionBundle_NoClip(t *testing.T) {
+	e, controller, _ := setupMediaTestEnvironment(t)
+
+	mockDS := mocks.NewMockInterface(t)
+	mockDS.On("GetNoteClipPath", "7").Return("", errors.New("record not found"))
+	controller.DS = mockDS
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/media/bundle/7", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	_ = controller.ServeDetectionBundle(c)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServeDetectionBundle_UserRequestedModeNotGenerated(t *testing.T) {
+	e, controller, tempDir := setupMediaTestEnvironment(t)
+	controller.Settings.Realtime.Dashboard.Spectrogram.Mode = conf.SpectrogramModeUserRequested
+
+	clipName := "2024-01-15_14-30-45_Turdus_migratorius.wav"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, clipName), []byte("test audio content"), 0o600))
+
+	mockDS := mocks.NewMockInterface(t)
+	mockDS.On("GetNoteClipPath", "42").Return(clipName, nil)
+	mockDS.On("Get", "42").Return(datastore.Note{ID: 42, ClipName: clipName}, nil)
+	controller.DS = mockDS
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/media/bundle/42", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+
+	require.NoError(t, controller.ServeDetectionBundle(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "user-requested mode doesn't generate spectrograms for bundles")
+	assert.Contains(t, rec.Body.String(), conf.SpectrogramModeUserRequested)
+	_, _, _, relSpectrogram := buildSpectrogramPaths(clipName, SpectrogramSizeMd, true)
+	assert.NoFileExists(t, filepath.Join(tempDir, relSpectrogram))
+}
@@ -0,0 +1,67 @@
+// internal/api/v2/detection_preview.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// DetectionPreviewRequest is the request body for POST /api/v2/detections/preview
+type DetectionPreviewRequest struct {
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName,omitempty"`
+	Confidence     float64 `json:"confidence"`
+	Time           string  `json:"time,omitempty"` // RFC3339, empty for now
+	Source         string  `json:"source,omitempty"`
+}
+
+// PreviewDetection handles POST /api/v2/detections/preview
+// Traces how a synthetic detection would pass through the current filters, thresholds,
+// cooldowns and life list without saving it or changing any detection state
+func (c *Controller) PreviewDetection(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	var req DetectionPreviewRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	var at time.Time
+	if req.Time != "" {
+		parsed, err := time.Parse(time.RFC3339, req.Time)
+		if err != nil {
+			return c.HandleError(ctx, err, "Invalid time, expected RFC3339", http.StatusBadRequest)
+		}
+		at = parsed
+	}
+
+	preview, err := c.Processor.PreviewDetection(processor.DetectionPreviewInput{
+		ScientificName: strings.TrimSpace(req.ScientificName),
+		CommonName:     strings.TrimSpace(req.CommonName),
+		Confidence:     req.Confidence,
+		Time:           at,
+		Source:         req.Source,
+	})
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	c.logInfoIfEnabled("Previewed synthetic detection",
+		logger.String("scientific_name", req.ScientificName),
+		logger.Float64("confidence", req.Confidence),
+		logger.Bool("passed", preview.Passed),
+		logger.String("dropped_by", preview.DroppedBy),
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	return ctx.JSON(http.StatusOK, preview)
+}
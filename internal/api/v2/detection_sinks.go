@@ -0,0 +1,60 @@
+// internal/api/v2/detection_sinks.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// DetectionSinkReplayRequest is the request body for POST /api/v2/processor/sinks/replay
+type DetectionSinkReplayRequest struct {
+	Count int `json:"count"` // Number of most recent stored detections to re-deliver
+}
+
+// initDetectionSinkRoutes registers the detection sink endpoints
+func (c *Controller) initDetectionSinkRoutes() {
+	c.Group.POST("/processor/sinks/replay", c.ReplayDetectionsToSinks, c.authMiddleware)
+}
+
+// ReplayDetectionsToSinks handles POST /api/v2/processor/sinks/replay
+// Re-delivers recent stored detections to the detection sinks, for example after a webhook
+// was fixed. Receivers see them flagged as replays
+func (c *Controller) ReplayDetectionsToSinks(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	var req DetectionSinkReplayRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	replayed, err := c.Processor.ReplayDetections(ctx.Request().Context(), req.Count)
+	if err != nil {
+		var enhancedErr *errors.EnhancedError
+		if errors.As(err, &enhancedErr) {
+			switch enhancedErr.Category {
+			case errors.CategoryValidation:
+				return c.HandleError(ctx, err, "Invalid replay count", http.StatusBadRequest)
+			case errors.CategoryState:
+				return c.HandleError(ctx, err, "No stored detections available", http.StatusServiceUnavailable)
+			}
+		}
+		return c.HandleError(ctx, err, "Failed to replay detections", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("Replayed detections to sinks",
+		logger.Int("requested", req.Count),
+		logger.Int("replayed", replayed),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"success":  true,
+		"replayed": replayed,
+	})
+}
@@ -117,6 +117,7 @@ func (c *Controller) initDetectionRoutes() {
 	c.Group.GET("/detections", c.GetDetections)
 	c.Group.GET("/detections/:id", c.GetDetection)
 	c.Group.GET("/detections/recent", c.GetRecentDetections)
+	c.Group.GET("/detections/species-counts.csv", c.ExportSpeciesCountsCSV)
 	c.Group.GET("/detections/:id/time-of-day", c.GetDetectionTimeOfDay)
 
 	// Protected detection management endpoints
@@ -126,6 +127,7 @@ func (c *Controller) initDetectionRoutes() {
 	detectionGroup.POST("/:id/lock", c.LockDetection)
 	detectionGroup.POST("/ignore", c.IgnoreSpecies)
 	detectionGroup.GET("/ignored", c.GetExcludedSpecies)
+	detectionGroup.POST("/preview", c.PreviewDetection)
 }
 
 // CommentResponse represents a comment on a detection in the API response
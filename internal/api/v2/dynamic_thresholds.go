@@ -215,7 +215,7 @@ func (c *Controller) addMemoryThresholds(thresholdMap map[string]*DynamicThresho
 		return
 	}
 	memoryData := c.Processor.GetDynamicThresholdData()
-	baseThreshold := c.Settings.BirdNET.Threshold
+	baseThreshold := c.Processor.ConfidenceThreshold()
 
 	for _, dt := range memoryData {
 		if existing, exists := thresholdMap[dt.SpeciesName]; exists {
@@ -0,0 +1,758 @@
+// internal/api/v2/lifelist.go
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Page size of GET /api/v2/lifelist/suppressed; the processor keeps at most 100 detections
+const (
+	defaultSuppressedLifeListLimit = 20
+	maxSuppressedLifeListLimit     = 100
+)
+
+// LifeListLifer identifies a life list species and when it was first seen
+type LifeListLifer struct {
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name,omitempty"`
+	FirstSeen      time.Time `json:"first_seen"`
+}
+
+// LifeListStatsResponse is returned by GET /api/v2/lifelist/stats
+type LifeListStatsResponse struct {
+	TotalSpecies    int            `json:"total_species"`
+	AddedThisWeek   int            `json:"added_this_week"`
+	AddedThisMonth  int            `json:"added_this_month"`
+	AddedThisYear   int            `json:"added_this_year"`
+	SeenToday       int            `json:"seen_today"`
+	MostRecentLifer *LifeListLifer `json:"most_recent_lifer,omitempty"`
+	// Taxonomy versions of the life list and the model, empty when unknown; renamed species
+	// don't match the life list when they differ
+	TaxonomyVersion      string    `json:"taxonomy_version,omitempty"`
+	ModelTaxonomyVersion string    `json:"model_taxonomy_version,omitempty"`
+	TaxonomyMismatch     bool      `json:"taxonomy_mismatch"`
+	Timestamp            time.Time `json:"timestamp"`
+}
+
+// LifeListEntryResponse is a life list species with its heard/seen status
+type LifeListEntryResponse struct {
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name,omitempty"`
+	FirstSeen      time.Time `json:"first_seen"`
+	Status         string    `json:"status"` // "heard", "seen" or "both"
+}
+
+// LifeListResponse is returned by GET /api/v2/lifelist
+type LifeListResponse struct {
+	ScientificNames []string             `json:"scientific_names"` // Sorted; empty when no life list is loaded
+	Total           int                  `json:"total"`
+	LastHeard       map[string]time.Time `json:"last_heard,omitempty"` // Latest detection of each listed species detected, by scientific name
+}
+
+// LifeListCountResponse is returned by GET /api/v2/lifelist/count
+type LifeListCountResponse struct {
+	Count int `json:"count"` // 0 when no life list is loaded
+}
+
+// LifeListTaxaResponse is returned by GET /api/v2/lifelist/taxa
+type LifeListTaxaResponse struct {
+	Genera   map[string]int `json:"genera"`   // Species per genus; sp., hybrid and slash entries under "unknown"
+	Families map[string]int `json:"families"` // Species per family, empty when the life list has no family column
+}
+
+// LifeListContainsResponse is returned by GET /api/v2/lifelist/contains
+type LifeListContainsResponse struct {
+	Name           string `json:"name"`
+	Contains       bool   `json:"contains"`
+	ScientificName string `json:"scientific_name,omitempty"` // Name of the matching entry, which may differ in case or be a group
+	Status         string `json:"status,omitempty"`
+}
+
+// LifeListResolveResponse is returned by GET /api/v2/lifelist/resolve
+type LifeListResolveResponse struct {
+	CommonName      string   `json:"common_name"`
+	ScientificNames []string `json:"scientific_names"` // Always an array; more than one when the common name is ambiguous
+}
+
+// LifeListPromoteRequest is the request body for POST /api/v2/lifelist/promote
+type LifeListPromoteRequest struct {
+	ScientificName string `json:"scientific_name"`
+}
+
+// LifeListImportResponse is returned by POST /api/v2/lifelist/import
+type LifeListImportResponse struct {
+	Added        int `json:"added"`     // Species new to the life list
+	Preserved    int `json:"preserved"` // Species already on the list, whose metadata was kept
+	TotalSpecies int `json:"total_species"`
+}
+
+// LifeListReloadResponse is returned by POST /api/v2/lifelist/reload
+type LifeListReloadResponse struct {
+	TotalSpecies int      `json:"total_species"`
+	Duplicates   []string `json:"duplicates"`  // Scientific names of rows repeating an earlier entry
+	Malformed    int      `json:"malformed"`   // Rows skipped as too short to hold a scientific name
+	EmptyFiles   []string `json:"empty_files"` // Configured files without a single species row
+}
+
+// LifeListValidateResponse is returned by POST /api/v2/lifelist/validate
+type LifeListValidateResponse struct {
+	TotalRows  int      `json:"total_rows"` // Species rows, counting repeats and malformed rows
+	Species    int      `json:"species"`    // Distinct species the file would load
+	Duplicates []string `json:"duplicates"` // Scientific names of rows repeating an earlier entry
+	Malformed  int      `json:"malformed"`  // Rows too short to hold a scientific name
+}
+
+// SuppressedLifeListDetectionResponse is a detection that raised no new species alert
+// because its species is already on the life list
+type SuppressedLifeListDetectionResponse struct {
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name,omitempty"`
+	Confidence     float64   `json:"confidence"`
+	Source         string    `json:"source,omitempty"`
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+// SuppressedLifeListResponse is returned by GET /api/v2/lifelist/suppressed
+type SuppressedLifeListResponse struct {
+	Total      uint64                                `json:"total"`      // Suppressed detections since startup, including those no longer kept
+	Detections []SuppressedLifeListDetectionResponse `json:"detections"` // Newest first
+}
+
+// BigDaySpeciesResponse is one species in a big day summary
+type BigDaySpeciesResponse struct {
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name,omitempty"`
+	FirstDetected  time.Time `json:"first_detected"`
+}
+
+// BigDaySummaryResponse lists the species detected on one day
+type BigDaySummaryResponse struct {
+	Day          string                  `json:"day"`
+	CreatedAt    time.Time               `json:"created_at"`
+	SpeciesCount int                     `json:"species_count"`
+	Species      []BigDaySpeciesResponse `json:"species"`
+}
+
+// LifeListAuditEntryResponse is one life list lookup of a potential lifer
+type LifeListAuditEntryResponse struct {
+	Time           time.Time `json:"time"`
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name,omitempty"`
+	QueriedName    string    `json:"queried_name"`    // Normalized name looked up in the life list
+	Result         string    `json:"result"`          // "not_on_list", "heard_only" or "no_list_loaded"
+	FuzzyAttempted bool      `json:"fuzzy_attempted"` // Whether fuzzy or species group matching was tried
+}
+
+// LifeListAuditResponse is returned by GET /api/v2/lifelist/audit
+type LifeListAuditResponse struct {
+	Enabled bool                         `json:"enabled"`
+	Entries []LifeListAuditEntryResponse `json:"entries"`
+}
+
+// PendingLifeListSpeciesResponse is a detected species awaiting confirmation before it is
+// added to the life list
+type PendingLifeListSpeciesResponse struct {
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name,omitempty"`
+	FirstDetected  time.Time `json:"first_detected"`
+	LastDetected   time.Time `json:"last_detected"`
+	Detections     int       `json:"detections"`
+}
+
+// PendingLifeListResponse is returned by GET /api/v2/lifelist/pending
+type PendingLifeListResponse struct {
+	ReviewMode bool                             `json:"review_mode"`
+	Species    []PendingLifeListSpeciesResponse `json:"species"`
+}
+
+// initLifeListRoutes registers life list endpoints. Reads that expose the whole list with its
+// dates, or the user's detections and queries, need authentication like the writes do
+func (c *Controller) initLifeListRoutes() {
+	lifeListGroup := c.Group.Group("/lifelist")
+	lifeListGroup.GET("", c.GetLifeList)
+	lifeListGroup.POST("", c.AddLifeListSpecies, c.authMiddleware)
+	lifeListGroup.GET("/count", c.GetLifeListCount)
+	lifeListGroup.GET("/taxa", c.GetLifeListTaxa)
+	lifeListGroup.GET("/contains", c.LifeListContains)
+	lifeListGroup.GET("/resolve", c.ResolveLifeListCommonName)
+	lifeListGroup.GET("/stats", c.GetLifeListStats)
+	lifeListGroup.GET("/export", c.ExportLifeList, c.authMiddleware)
+	lifeListGroup.POST("/promote", c.PromoteLifeListSpecies, c.authMiddleware)
+	lifeListGroup.POST("/import", c.ImportLifeList, c.authMiddleware)
+	lifeListGroup.POST("/reload", c.ReloadLifeList, c.authMiddleware)
+	lifeListGroup.POST("/validate", c.ValidateLifeList, c.authMiddleware)
+	lifeListGroup.GET("/model-species", c.CheckModelSpecies)
+	lifeListGroup.GET("/bigday", c.GetBigDaySummaries)
+	lifeListGroup.POST("/bigday", c.CompleteBigDay, c.authMiddleware)
+	lifeListGroup.GET("/audit", c.GetLifeListAudit, c.authMiddleware)
+	lifeListGroup.GET("/suppressed", c.GetSuppressedLifeListDetections, c.authMiddleware)
+	lifeListGroup.GET("/pending", c.GetPendingLifeListSpecies, c.authMiddleware)
+	lifeListGroup.POST("/pending/confirm", c.ConfirmLifeListSpecies, c.authMiddleware)
+	lifeListGroup.POST("/pending/reject", c.RejectLifeListSpecies, c.authMiddleware)
+}
+
+// GetLifeList handles GET /api/v2/lifelist
+// Returns the scientific names on the loaded life list, with when each species heard since
+// tracking began was last detected
+func (c *Controller) GetLifeList(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	entries := c.Processor.LifeListEntries()
+	response := LifeListResponse{ScientificNames: make([]string, 0, len(entries)), Total: len(entries)}
+	for i := range entries {
+		name := entries[i].ScientificName
+		response.ScientificNames = append(response.ScientificNames, name)
+		if at, ok := c.Processor.LastHeard(name); ok {
+			if response.LastHeard == nil {
+				response.LastHeard = make(map[string]time.Time)
+			}
+			response.LastHeard[name] = at
+		}
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetLifeListCount handles GET /api/v2/lifelist/count
+// Returns the number of species on the loaded life list
+func (c *Controller) GetLifeListCount(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, LifeListCountResponse{Count: processor.LifeListCount()})
+}
+
+// GetLifeListTaxa handles GET /api/v2/lifelist/taxa
+// Returns how many life list species there are in each genus and family
+func (c *Controller) GetLifeListTaxa(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+	return ctx.JSON(http.StatusOK, LifeListTaxaResponse{
+		Genera:   c.Processor.LifeListCountByGenus(),
+		Families: c.Processor.LifeListCountByFamily(),
+	})
+}
+
+// LifeListContains handles GET /api/v2/lifelist/contains?name=...
+// Reports whether a species is on the life list, matching names the way detections are
+// matched. Responds 404 with contains false when it isn't. Whether the model can detect a
+// species at all is GET /lifelist/model-species
+func (c *Controller) LifeListContains(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+	name := strings.TrimSpace(ctx.QueryParam("name"))
+	if name == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing name"), "Name is required", http.StatusBadRequest)
+	}
+
+	entry, found := c.Processor.LifeListEntry(name)
+	if !found {
+		return ctx.JSON(http.StatusNotFound, LifeListContainsResponse{Name: name})
+	}
+	return ctx.JSON(http.StatusOK, LifeListContainsResponse{
+		Name:           name,
+		Contains:       true,
+		ScientificName: entry.ScientificName,
+		Status:         string(entry.Status),
+	})
+}
+
+// ResolveLifeListCommonName handles GET /api/v2/lifelist/resolve?common=...
+// Returns the scientific names of the life list species recorded under a common name,
+// compared case-insensitively. Responds 404 with an empty array when none are
+func (c *Controller) ResolveLifeListCommonName(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+	common := strings.TrimSpace(ctx.QueryParam("common"))
+	if common == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing common name"), "Common name is required", http.StatusBadRequest)
+	}
+
+	names := c.Processor.ScientificNames(common)
+	if len(names) == 0 {
+		return ctx.JSON(http.StatusNotFound, LifeListResolveResponse{CommonName: common, ScientificNames: []string{}})
+	}
+	return ctx.JSON(http.StatusOK, LifeListResolveResponse{CommonName: common, ScientificNames: names})
+}
+
+// GetLifeListStats handles GET /api/v2/lifelist/stats
+// Returns the life list size, lifers added this week/month/year, species seen today, the
+// most recent lifer and the taxonomy versions of the life list and the model
+func (c *Controller) GetLifeListStats(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	now := time.Now()
+	stats := c.Processor.LifeListStats(now)
+	response := LifeListStatsResponse{
+		TotalSpecies:   stats.TotalSpecies,
+		AddedThisWeek:  stats.AddedThisWeek,
+		AddedThisMonth: stats.AddedThisMonth,
+		AddedThisYear:  stats.AddedThisYear,
+		SeenToday:      stats.SeenToday,
+
+		TaxonomyVersion:      stats.Taxonomy.ListVersion,
+		ModelTaxonomyVersion: stats.Taxonomy.ModelVersion,
+		TaxonomyMismatch:     stats.Taxonomy.Mismatch(),
+		Timestamp:            now,
+	}
+	if lifer := stats.MostRecentLifer; lifer != nil {
+		response.MostRecentLifer = &LifeListLifer{
+			ScientificName: lifer.ScientificName,
+			CommonName:     lifer.CommonName,
+			FirstSeen:      lifer.FirstSeen,
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// PromoteLifeListSpecies handles POST /api/v2/lifelist/promote
+// Marks a heard-only species as seen after the user confirmed it visually
+func (c *Controller) PromoteLifeListSpecies(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	var req LifeListPromoteRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if strings.TrimSpace(req.ScientificName) == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing scientific name"),
+			"Scientific name is required", http.StatusBadRequest)
+	}
+
+	entry, err := c.Processor.PromoteLifeListSpecies(req.ScientificName)
+	if err != nil {
+		return c.handleErrorWithNotFound(ctx, err, "Species is not on the life list", "Failed to promote species")
+	}
+
+	c.logInfoIfEnabled("Life list species promoted",
+		logger.String("scientific_name", entry.ScientificName),
+		logger.String("status", string(entry.Status)),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, newLifeListEntryResponse(&entry))
+}
+
+// ImportLifeList handles POST /api/v2/lifelist/import
+// Merges a life list CSV sent as the request body into the loaded list. Species already on
+// the list keep their first-seen date and status; only new species are added
+func (c *Controller) ImportLifeList(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	result, err := c.Processor.MergeLifeList(ctx.Request().Body)
+	if err != nil {
+		var enhancedErr *errors.EnhancedError
+		if errors.As(err, &enhancedErr) && enhancedErr.Category == errors.CategoryState {
+			return c.HandleError(ctx, err, "No life list is loaded", http.StatusConflict)
+		}
+		return c.HandleError(ctx, err, "Invalid life list file", http.StatusBadRequest)
+	}
+
+	c.logInfoIfEnabled("Life list imported",
+		logger.Int("added", result.Added),
+		logger.Int("preserved", result.Preserved),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, LifeListImportResponse{
+		Added:        result.Added,
+		Preserved:    result.Preserved,
+		TotalSpecies: len(c.Processor.LifeListEntries()),
+	})
+}
+
+// ReloadLifeList handles POST /api/v2/lifelist/reload
+// Loads the life list again from the configured files with the current settings, without
+// waiting for the file watcher. A failed reload keeps the list loaded before
+func (c *Controller) ReloadLifeList(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	result, err := c.Processor.ReloadLifeListWithResult(ctx.Request().Context())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to reload life list, the previous list is kept", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("Life list reloaded",
+		logger.Int("total_species", result.Loaded),
+		logger.Int("duplicates", len(result.Duplicates)),
+		logger.Int("malformed", result.Malformed),
+		logger.String("ip", ctx.RealIP()))
+
+	response := LifeListReloadResponse{
+		TotalSpecies: result.Loaded,
+		Duplicates:   result.Duplicates,
+		Malformed:    result.Malformed,
+		EmptyFiles:   result.Empty,
+	}
+	// Empty lists are sent as arrays, not null
+	if response.Duplicates == nil {
+		response.Duplicates = []string{}
+	}
+	if response.EmptyFiles == nil {
+		response.EmptyFiles = []string{}
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// ValidateLifeList handles POST /api/v2/lifelist/validate?format=...
+// Parses an uploaded life list, sent as the "file" field of a multipart form or as the
+// request body, and reports what it holds without touching the loaded list. format
+// overrides the configured life list format
+func (c *Controller) ValidateLifeList(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+	format := strings.ToLower(strings.TrimSpace(ctx.QueryParam("format")))
+	if format != "" && !slices.Contains(conf.LifeListFormats, format) {
+		return c.HandleError(ctx, fmt.Errorf("unknown life list format %q", format),
+			"Format must be one of "+strings.Join(conf.LifeListFormats, ", "), http.StatusBadRequest)
+	}
+
+	body, name := io.Reader(ctx.Request().Body), ""
+	if strings.HasPrefix(ctx.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		header, err := ctx.FormFile("file")
+		if err != nil {
+			return c.HandleError(ctx, err, "A life list file is required", http.StatusBadRequest)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to read the uploaded file", http.StatusBadRequest)
+		}
+		defer file.Close()
+		body, name = file, header.Filename
+	}
+
+	report, err := c.Processor.ValidateLifeList(body, name, format)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid life list file", http.StatusBadRequest)
+	}
+
+	response := LifeListValidateResponse{
+		TotalRows:  report.TotalRows,
+		Species:    report.Species,
+		Duplicates: report.Duplicates,
+		Malformed:  report.Malformed,
+	}
+	// Empty lists are sent as arrays, not null
+	if response.Duplicates == nil {
+		response.Duplicates = []string{}
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetBigDaySummaries handles GET /api/v2/lifelist/bigday
+// Returns the saved big day summaries, oldest first
+func (c *Controller) GetBigDaySummaries(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	summaries, err := c.Processor.BigDaySummaries()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to read big day summaries", http.StatusInternalServerError)
+	}
+
+	response := make([]BigDaySummaryResponse, 0, len(summaries))
+	for i := range summaries {
+		response = append(response, newBigDaySummaryResponse(&summaries[i]))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// CompleteBigDay handles POST /api/v2/lifelist/bigday
+// Saves a summary of the species detected so far today and starts today's list over
+func (c *Controller) CompleteBigDay(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	summary, err := c.Processor.CompleteBigDay(time.Now())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to save big day summary", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("Big day summary saved",
+		logger.String("day", summary.Day),
+		logger.Int("species", len(summary.Species)),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, newBigDaySummaryResponse(&summary))
+}
+
+// GetLifeListAudit handles GET /api/v2/lifelist/audit
+// Returns the recent life list lookups of potential lifers, newest first, so users can see
+// which name was queried and why a lifer alert did or didn't fire
+func (c *Controller) GetLifeListAudit(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	entries := c.Processor.LifeListAudit()
+	response := LifeListAuditResponse{
+		Enabled: c.Settings.SoundId.LifeListAuditEnabled,
+		Entries: make([]LifeListAuditEntryResponse, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, LifeListAuditEntryResponse{
+			Time:           entry.Time,
+			ScientificName: entry.ScientificName,
+			CommonName:     entry.CommonName,
+			QueriedName:    entry.QueriedName,
+			Result:         entry.Result,
+			FuzzyAttempted: entry.FuzzyAttempted,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetSuppressedLifeListDetections handles GET /api/v2/lifelist/suppressed?limit=20
+// Returns how many detections raised no new species alert because the species is already on
+// the life list, with the most recent of them
+func (c *Controller) GetSuppressedLifeListDetections(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	limit := c.parsePaginationLimit(ctx.QueryParam("limit"), defaultSuppressedLifeListLimit, maxSuppressedLifeListLimit)
+	total, recent := c.Processor.SuppressedLifeListDetections(limit)
+	response := SuppressedLifeListResponse{
+		Total:      total,
+		Detections: make([]SuppressedLifeListDetectionResponse, 0, len(recent)),
+	}
+	for _, detection := range recent {
+		response.Detections = append(response.Detections, SuppressedLifeListDetectionResponse{
+			ScientificName: detection.ScientificName,
+			CommonName:     detection.CommonName,
+			Confidence:     detection.Confidence,
+			Source:         detection.Source,
+			DetectedAt:     detection.DetectedAt,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetPendingLifeListSpecies handles GET /api/v2/lifelist/pending
+// Returns the detected species awaiting confirmation in review mode, oldest first
+func (c *Controller) GetPendingLifeListSpecies(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	pending := c.Processor.PendingLifeListSpecies()
+	response := PendingLifeListResponse{
+		ReviewMode: c.Settings.SoundId.LifeListReviewMode,
+		Species:    make([]PendingLifeListSpeciesResponse, 0, len(pending)),
+	}
+	for _, species := range pending {
+		response.Species = append(response.Species, PendingLifeListSpeciesResponse{
+			ScientificName: species.ScientificName,
+			CommonName:     species.CommonName,
+			FirstDetected:  species.FirstDetected,
+			LastDetected:   species.LastDetected,
+			Detections:     species.Detections,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// ConfirmLifeListSpecies handles POST /api/v2/lifelist/pending/confirm
+// Adds a pending species to the life list as heard
+func (c *Controller) ConfirmLifeListSpecies(ctx echo.Context) error {
+	scientificName, err := c.bindPendingLifeListSpecies(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry, confirmErr := c.Processor.ConfirmLifeListSpecies(scientificName)
+	if confirmErr != nil {
+		return c.handleErrorWithNotFound(ctx, confirmErr, "Species is not awaiting confirmation", "Failed to confirm species")
+	}
+
+	c.logInfoIfEnabled("Pending life list species confirmed",
+		logger.String("scientific_name", entry.ScientificName),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, newLifeListEntryResponse(&entry))
+}
+
+// RejectLifeListSpecies handles POST /api/v2/lifelist/pending/reject
+// Drops a pending species without adding it to the life list
+func (c *Controller) RejectLifeListSpecies(ctx echo.Context) error {
+	scientificName, err := c.bindPendingLifeListSpecies(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rejectErr := c.Processor.RejectLifeListSpecies(scientificName); rejectErr != nil {
+		return c.handleErrorWithNotFound(ctx, rejectErr, "Species is not awaiting confirmation", "Failed to reject species")
+	}
+
+	c.logInfoIfEnabled("Pending life list species rejected",
+		logger.String("scientific_name", scientificName),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// bindPendingLifeListSpecies reads the scientific name of a confirm or reject request. On
+// failure it returns the error response for the handler to return.
+func (c *Controller) bindPendingLifeListSpecies(ctx echo.Context) (string, error) {
+	if c.Processor == nil {
+		return "", c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	var req LifeListPromoteRequest
+	if err := ctx.Bind(&req); err != nil {
+		return "", c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if strings.TrimSpace(req.ScientificName) == "" {
+		return "", c.HandleError(ctx, fmt.Errorf("missing scientific name"),
+			"Scientific name is required", http.StatusBadRequest)
+	}
+	return req.ScientificName, nil
+}
+
+// lifeListExportEntry is one species of a JSON export. The keys are those the life list
+// loader reads, rather than the API's, so an export can be configured as the life list.
+type lifeListExportEntry struct {
+	ScientificName string `json:"scientificName"`
+	CommonName     string `json:"commonName,omitempty"`
+	FirstSeen      string `json:"firstSeen,omitempty"` // YYYY-MM-DD
+	Family         string `json:"family,omitempty"`
+	Status         string `json:"status"`
+}
+
+// ExportLifeList handles GET /api/v2/lifelist/export
+// Returns the life list, sorted by scientific name and with each species' heard/seen status,
+// as a CSV (format=csv, the default) or JSON (format=json) download. Both are laid out the
+// way the life list loader reads them, so an export round-trips through a reload
+func (c *Controller) ExportLifeList(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(ctx.QueryParam("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return c.HandleError(ctx, fmt.Errorf("unsupported export format %q", format),
+			"Format must be csv or json", http.StatusBadRequest)
+	}
+
+	entries := c.Processor.LifeListEntries()
+	exported := make([]lifeListExportEntry, 0, len(entries))
+	for i := range entries {
+		firstSeen := ""
+		if !entries[i].FirstSeen.IsZero() {
+			firstSeen = entries[i].FirstSeen.Format(time.DateOnly)
+		}
+		exported = append(exported, lifeListExportEntry{
+			ScientificName: entries[i].ScientificName,
+			CommonName:     entries[i].CommonName,
+			FirstSeen:      firstSeen,
+			Family:         entries[i].Family,
+			Status:         string(entries[i].Status),
+		})
+	}
+
+	var body []byte
+	contentType := "application/json; charset=utf-8"
+	if format == "json" {
+		data, err := json.MarshalIndent(exported, "", "  ")
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to generate JSON", http.StatusInternalServerError)
+		}
+		body = data
+	} else {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		// The loader maps the columns of this header row, including the first-seen "Date" and "Family"
+		rows := [][]string{{"Scientific Name", "Common Name", "Date", "Status", "Family"}}
+		for _, entry := range exported {
+			rows = append(rows, []string{
+				sanitizeCSVField(entry.ScientificName),
+				sanitizeCSVField(entry.CommonName),
+				entry.FirstSeen,
+				entry.Status,
+				sanitizeCSVField(entry.Family),
+			})
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return c.HandleError(ctx, err, "Failed to generate CSV", http.StatusInternalServerError)
+		}
+		body = buf.Bytes()
+		contentType = "text/csv; charset=utf-8"
+	}
+
+	filename := "lifelist_" + time.Now().Format("20060102_150405") + "." + format
+	ctx.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Response().Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	return ctx.Blob(http.StatusOK, contentType, body)
+}
+
+// newLifeListEntryResponse converts a processor life list entry to its API form
+func newLifeListEntryResponse(entry *processor.LifeListEntry) LifeListEntryResponse {
+	return LifeListEntryResponse{
+		ScientificName: entry.ScientificName,
+		CommonName:     entry.CommonName,
+		FirstSeen:      entry.FirstSeen,
+		Status:         string(entry.Status),
+	}
+}
+
+// newBigDaySummaryResponse converts a processor big day summary to its API form
+func newBigDaySummaryResponse(summary *processor.BigDaySummary) BigDaySummaryResponse {
+	species := make([]BigDaySpeciesResponse, 0, len(summary.Species))
+	for _, sp := range summary.Species {
+		species = append(species, BigDaySpeciesResponse{
+			ScientificName: sp.ScientificName,
+			CommonName:     sp.CommonName,
+			FirstDetected:  sp.FirstDetected,
+		})
+	}
+	return BigDaySummaryResponse{
+		Day:          summary.Day,
+		CreatedAt:    summary.CreatedAt,
+		SpeciesCount: len(species),
+		Species:      species,
+	}
+}
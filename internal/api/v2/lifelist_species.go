@@ -0,0 +1,175 @@
+// internal/api/v2/lifelist_species.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// lifeListSuggestionDistanceRatio bounds how different a suggested name may be from the
+// requested one, as edit operations per character of the requested name
+const lifeListSuggestionDistanceRatio = 0.34
+
+// LifeListSpeciesName names a species in the model's label set
+type LifeListSpeciesName struct {
+	ScientificName string `json:"scientific_name"`
+	CommonName     string `json:"common_name,omitempty"`
+}
+
+// ModelSpeciesCheckResponse is returned by GET /api/v2/lifelist/model-species
+type ModelSpeciesCheckResponse struct {
+	Name       string               `json:"name"`
+	Known      bool                 `json:"known"`                // Whether the model can detect the species
+	Species    *LifeListSpeciesName `json:"species,omitempty"`    // The matching label, when known
+	Suggestion *LifeListSpeciesName `json:"suggestion,omitempty"` // The closest label, when unknown
+}
+
+// LifeListAddRequest is the request body for POST /api/v2/lifelist
+type LifeListAddRequest struct {
+	ScientificName string `json:"scientific_name"`
+	CommonName     string `json:"common_name,omitempty"`
+	Force          bool   `json:"force,omitempty"` // Add the species even if the model doesn't know it
+}
+
+// LifeListAddResponse is returned by POST /api/v2/lifelist
+type LifeListAddResponse struct {
+	Added      bool                   `json:"added"`
+	Entry      *LifeListEntryResponse `json:"entry,omitempty"`
+	Warning    string                 `json:"warning,omitempty"`
+	Suggestion *LifeListSpeciesName   `json:"suggestion,omitempty"`
+}
+
+// CheckModelSpecies handles GET /api/v2/lifelist/model-species?name=...
+// Reports whether a scientific or common name is in the model's label set, suggesting the
+// closest label when it isn't. It always responds 200 and says nothing about the life list
+// itself; GET /lifelist/contains reports whether a species is on the list
+func (c *Controller) CheckModelSpecies(ctx echo.Context) error {
+	name := strings.TrimSpace(ctx.QueryParam("name"))
+	if name == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing name"), "Name is required", http.StatusBadRequest)
+	}
+
+	match, suggestion := matchModelSpecies(c.Settings.BirdNET.Labels, name)
+	return ctx.JSON(http.StatusOK, ModelSpeciesCheckResponse{
+		Name:       name,
+		Known:      match != nil,
+		Species:    match,
+		Suggestion: suggestion,
+	})
+}
+
+// AddLifeListSpecies handles POST /api/v2/lifelist
+// Marks a species as seen by adding it to the life list and its file. Names the model
+// doesn't know can never match a detection, so they are rejected with the closest known
+// name unless force is set. Adding a species already on the list responds 200 without
+// changing the file
+func (c *Controller) AddLifeListSpecies(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not available"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	var req LifeListAddRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	scientificName := strings.TrimSpace(req.ScientificName)
+	if scientificName == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing scientific name"),
+			"Scientific name is required", http.StatusBadRequest)
+	}
+
+	var response LifeListAddResponse
+	commonName := req.CommonName
+	match, suggestion := matchModelSpecies(c.Settings.BirdNET.Labels, scientificName)
+	if match != nil {
+		// Store the model's spelling so detections of the species match the entry
+		scientificName = match.ScientificName
+		if commonName == "" {
+			commonName = match.CommonName
+		}
+	} else {
+		response.Warning = fmt.Sprintf("%q is not a species the model can detect", scientificName)
+		response.Suggestion = suggestion
+		if !req.Force {
+			return ctx.JSON(http.StatusUnprocessableEntity, response)
+		}
+	}
+
+	entry, added, err := c.Processor.AddLifeListSpecies(scientificName, commonName, time.Now())
+	if err != nil {
+		var enhancedErr *errors.EnhancedError
+		if errors.As(err, &enhancedErr) && enhancedErr.Category == errors.CategoryState {
+			return c.HandleError(ctx, err, "No life list is loaded", http.StatusConflict)
+		}
+		return c.HandleError(ctx, err, "Failed to add species", http.StatusInternalServerError)
+	}
+
+	entryResponse := newLifeListEntryResponse(&entry)
+	response.Added = added
+	response.Entry = &entryResponse
+	if !added {
+		return ctx.JSON(http.StatusOK, response)
+	}
+
+	c.logInfoIfEnabled("Life list species added",
+		logger.String("scientific_name", entry.ScientificName),
+		logger.Bool("known_to_model", match != nil),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+// matchModelSpecies looks name up among the model labels by scientific or common name,
+// ignoring case. When there is no match it returns the closest label by edit distance,
+// or nil when no label is close.
+func matchModelSpecies(labels []string, name string) (match, suggestion *LifeListSpeciesName) {
+	needle := strings.ToLower(strings.TrimSpace(name))
+	maxDistance := int(float64(len([]rune(needle)))*lifeListSuggestionDistanceRatio) + 1
+
+	bestDistance := maxDistance + 1
+	for _, label := range labels {
+		sp := detection.ParseSpeciesString(label)
+		candidate := &LifeListSpeciesName{ScientificName: sp.ScientificName, CommonName: sp.CommonName}
+		for _, known := range []string{sp.ScientificName, sp.CommonName} {
+			known = strings.ToLower(known)
+			if known == needle {
+				return candidate, nil
+			}
+			if d := editDistance(needle, known); d < bestDistance {
+				bestDistance = d
+				suggestion = candidate
+			}
+		}
+	}
+	return nil, suggestion
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,112 @@
+// lifelist_species_test.go: Tests for validating life list species against the model labels
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// postLifeListSpecies sends an add request and decodes the response.
+func postLifeListSpecies(t *testing.T, controller *Controller, body string) (int, LifeListAddResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.AddLifeListSpecies(echo.New().NewContext(req, rec)))
+
+	var response LifeListAddResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response
+}
+
+func TestAddLifeListSpecies_ValidatesAgainstModelLabels(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.Settings.BirdNET.Labels = []string{
+		"Turdus merula_Eurasian Blackbird",
+		"Parus major_Great Tit",
+		"Apus apus_Common Swift",
+	}
+
+	listPath := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Great Tit,Parus major,1,Home,,2001-06-01\n"), 0o600))
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:       listPath,
+		LifeListStatusPath: filepath.Join(t.TempDir(), "lifelist_status.json"),
+	}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	// A misspelled name is rejected with the closest label
+	code, response := postLifeListSpecies(t, controller, `{"scientific_name":"Turdus merola"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.False(t, response.Added)
+	assert.NotEmpty(t, response.Warning)
+	require.NotNil(t, response.Suggestion)
+	assert.Equal(t, "Turdus merula", response.Suggestion.ScientificName)
+	_, onList := proc.LifeListEntry("Turdus merola")
+	assert.False(t, onList)
+
+	// A known name is added cleanly, with the common name taken from the label
+	code, response = postLifeListSpecies(t, controller, `{"scientific_name":"turdus merula"}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.True(t, response.Added)
+	assert.Empty(t, response.Warning)
+	assert.Nil(t, response.Suggestion)
+	require.NotNil(t, response.Entry)
+	assert.Equal(t, "Turdus merula", response.Entry.ScientificName)
+	assert.Equal(t, "Eurasian Blackbird", response.Entry.CommonName)
+	assert.Equal(t, "seen", response.Entry.Status)
+
+	// Forcing an unknown name adds it but still warns
+	code, response = postLifeListSpecies(t, controller, `{"scientific_name":"Avis imaginaria","force":true}`)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.True(t, response.Added)
+	assert.NotEmpty(t, response.Warning)
+}
+
+func TestCheckModelSpecies(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Settings.BirdNET.Labels = []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}
+
+	tests := []struct {
+		name           string
+		query          string
+		wantKnown      bool
+		wantSuggestion string
+	}{
+		{"scientific name", "Parus major", true, ""},
+		{"common name", "great tit", true, ""},
+		{"misspelled", "Eurasian Blackbrid", false, "Turdus merula"},
+		{"nothing close", "Struthio camelus", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/model-species?name="+strings.ReplaceAll(tt.query, " ", "+"), http.NoBody)
+			rec := httptest.NewRecorder()
+			require.NoError(t, controller.CheckModelSpecies(e.NewContext(req, rec)))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var response ModelSpeciesCheckResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantKnown, response.Known)
+			if tt.wantSuggestion == "" {
+				assert.Nil(t, response.Suggestion)
+			} else {
+				require.NotNil(t, response.Suggestion)
+				assert.Equal(t, tt.wantSuggestion, response.Suggestion.ScientificName)
+			}
+		})
+	}
+}
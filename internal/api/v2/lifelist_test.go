@@ -0,0 +1,529 @@
+// lifelist_test.go: Tests for the life list statistics endpoint
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestGetLifeListStats(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	today := time.Now().Format(time.DateOnly)
+	csv := fmt.Sprintf("1,1,species,Eurasian Blackbird,Turdus merula,1,Home,,%s\n", today) +
+		"2,2,species,Great Tit,Parus major,1,Home,,2001-06-01\n" +
+		"3,3,species,Common Swift,Apus apus,1,Home,,\n"
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte(csv), 0o600))
+
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/stats", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetLifeListStats(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var stats LifeListStatsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, 3, stats.TotalSpecies)
+	assert.Equal(t, 1, stats.AddedThisWeek)
+	assert.Equal(t, 1, stats.AddedThisMonth)
+	assert.Equal(t, 1, stats.AddedThisYear)
+	assert.Zero(t, stats.SeenToday)
+	require.NotNil(t, stats.MostRecentLifer)
+	assert.Equal(t, "Turdus merula", stats.MostRecentLifer.ScientificName)
+}
+
+func TestGetLifeListStats_TaxonomyVersions(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte("# taxonomy: eBird 2023\n1,1,species,Great Tit,Parus major\n"), 0o600))
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/stats", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetLifeListStats(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var stats LifeListStatsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, "eBird 2023", stats.TaxonomyVersion)
+	assert.Equal(t, "eBird 2021", stats.ModelTaxonomyVersion)
+	assert.True(t, stats.TaxonomyMismatch)
+}
+
+func TestGetLifeListStats_NoProcessor(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Processor = nil
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/stats", http.NoBody)
+	rec := httptest.NewRecorder()
+	_ = controller.GetLifeListStats(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestPromoteLifeListSpecies(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	// A heard-only species as persisted when the processor auto-adds a detection
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "lifelist.csv")
+	statusPath := filepath.Join(dir, "lifelist_status.json")
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Great Tit,Parus major,1,Home,,2001-06-01\n"), 0o600))
+	require.NoError(t, os.WriteFile(statusPath,
+		[]byte(`[{"scientificName":"Turdus merula","commonName":"Eurasian Blackbird","status":"heard"}]`), 0o600))
+
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:       listPath,
+		LifeListStatusPath: statusPath,
+	}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/promote",
+		strings.NewReader(`{"scientific_name":"Turdus merula"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.PromoteLifeListSpecies(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var entry LifeListEntryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entry))
+	assert.Equal(t, "Turdus merula", entry.ScientificName)
+	assert.Equal(t, "both", entry.Status)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/export", http.NoBody)
+	rec = httptest.NewRecorder()
+	require.NoError(t, controller.ExportLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Parus major,Great Tit,2001-06-01,seen")
+	assert.Contains(t, rec.Body.String(), "Turdus merula,Eurasian Blackbird,,both")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/promote",
+		strings.NewReader(`{"scientific_name":"Apus apus"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	_ = controller.PromoteLifeListSpecies(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestImportLifeList_MergesNewSpecies(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	listPath := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Great Tit,Parus major,1,Home,,2001-06-01\n"), 0o600))
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:       listPath,
+		LifeListStatusPath: filepath.Join(t.TempDir(), "lifelist_status.json"),
+	}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	body := "1,1,species,Great Tit,Parus major,1,Home,,2020-01-01\n2,2,species,Common Swift,Apus apus,1,Home,,2026-06-02\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ImportLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response LifeListImportResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, LifeListImportResponse{Added: 1, Preserved: 1, TotalSpecies: 2}, response)
+
+	entry, ok := proc.LifeListEntry("Parus major")
+	require.True(t, ok)
+	assert.Equal(t, 2001, entry.FirstSeen.Year())
+}
+
+func TestValidateLifeList_Upload(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major\n")
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "lifelist.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("1,1,species,Common Swift,Apus apus\n2,2,species,Swift,apus apus\n3,3\n4,4,species,Pica pica,Pica pica\n"))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/validate", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ValidateLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"total_rows":4,"species":2,"duplicates":["apus apus"],"malformed":1}`, rec.Body.String())
+
+	assert.Equal(t, []string{"Parus major"}, lifeListNames(controller), "the loaded list is left alone")
+}
+
+func TestValidateLifeList_RequestBody(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "")
+
+	validate := func(query, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/validate"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.ValidateLifeList(e.NewContext(req, rec)))
+		return rec
+	}
+
+	rec := validate("", "1,1,species,Great Tit,Parus major\n")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"total_rows":1,"species":1,"duplicates":[],"malformed":0}`, rec.Body.String())
+
+	rec = validate("?format=json", `["Parus major","Turdus merula"]`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"total_rows":2,"species":2,"duplicates":[],"malformed":0}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, validate("?format=json", "not json").Code)
+	assert.Equal(t, http.StatusBadRequest, validate("?format=xml", "").Code)
+}
+
+// lifeListNames returns the scientific names on the life list the controller's processor holds
+func lifeListNames(controller *Controller) []string {
+	var names []string
+	for _, entry := range controller.Processor.LifeListEntries() {
+		names = append(names, entry.ScientificName)
+	}
+	return names
+}
+
+func TestReloadLifeList_PicksUpAddedEntries(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	listPath := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{
+		LifeListPath:          listPath,
+		LifeListSkipMalformed: true,
+	}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Great Tit,Parus major\n"+
+		"2,2,species,Common Swift,Apus apus\n"+
+		"3,3,species,Common Swift,Apus apus\n"+
+		"4,4\n"), 0o600))
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/reload", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ReloadLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response LifeListReloadResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, LifeListReloadResponse{
+		TotalSpecies: 2,
+		Duplicates:   []string{"Apus apus"},
+		Malformed:    1,
+		EmptyFiles:   []string{},
+	}, response)
+	_, ok := proc.LifeListEntry("Apus apus")
+	assert.True(t, ok, "the added species is on the reloaded list")
+}
+
+func TestReloadLifeList_FailureKeepsPreviousList(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	listPath := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Great Tit,Parus major\n"), 0o600))
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: listPath}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	// A truncated row fails the load unless malformed rows are skipped
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,Common Swift,Apus apus\n2,2\n"), 0o600))
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/reload", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ReloadLifeList(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Error)
+	_, ok := proc.LifeListEntry("Parus major")
+	assert.True(t, ok, "the previous list stays loaded")
+	_, ok = proc.LifeListEntry("Apus apus")
+	assert.False(t, ok)
+}
+
+func TestGetSuppressedLifeListDetections_Empty(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/suppressed?limit=5", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetSuppressedLifeListDetections(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"total":0,"detections":[]}`, rec.Body.String())
+}
+
+// loadTestLifeList loads csv as the life list of a new processor assigned to controller.
+func loadTestLifeList(t *testing.T, controller *Controller, csv string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(path, []byte(csv), 0o600))
+
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+}
+
+func TestGetLifeList(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major\n2,2,species,Eurasian Blackbird,Turdus merula\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"scientific_names":["Parus major","Turdus merula"],"total":2}`, rec.Body.String())
+}
+
+func TestGetLifeList_EmptyIsArray(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"scientific_names":[],"total":0}`, rec.Body.String())
+}
+
+func TestGetLifeListCount(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want string
+	}{
+		{"empty", "", `{"count":0}`},
+		{"populated", "1,1,species,Great Tit,Parus major\n2,2,species,Eurasian Blackbird,Turdus merula\n", `{"count":2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _, controller := setupTestEnvironment(t)
+			loadTestLifeList(t, controller, tt.csv)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/count", http.NoBody)
+			rec := httptest.NewRecorder()
+			require.NoError(t, controller.GetLifeListCount(e.NewContext(req, rec)))
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.want, rec.Body.String())
+		})
+	}
+}
+
+func TestGetLifeListTaxa(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "Scientific Name,Common Name,Family\n"+
+		"Setophaga petechia,Yellow Warbler,Parulidae\n"+
+		"Setophaga coronata,Yellow-rumped Warbler,Parulidae\n"+
+		"Turdus migratorius,American Robin,Turdidae\n"+
+		"Vermivora chrysoptera x cyanoptera,Brewster's Warbler,Parulidae\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/taxa", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetLifeListTaxa(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"genera": {"Setophaga": 2, "Turdus": 1, "unknown": 1},
+		"families": {"Parulidae": 2, "Turdidae": 1, "unknown": 1}
+	}`, rec.Body.String())
+}
+
+func TestLifeListContains(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major\n")
+
+	contains := func(name string) (int, LifeListContainsResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/contains?name="+url.QueryEscape(name), http.NoBody)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.LifeListContains(e.NewContext(req, rec)))
+
+		var body LifeListContainsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+
+	code, body := contains("parus major")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, LifeListContainsResponse{Name: "parus major", Contains: true, ScientificName: "Parus major", Status: "seen"}, body)
+
+	code, body = contains("Turdus merula")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.False(t, body.Contains)
+	assert.Equal(t, "Turdus merula", body.Name)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/contains", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.LifeListContains(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestResolveLifeListCommonName(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "Scientific Name,Common Name\n"+
+		"Turdus migratorius,American Robin\n"+
+		"Cyanistes caeruleus,Blue Tit\n"+
+		"Cyanistes teneriffae,Blue Tit\n")
+
+	resolve := func(common string) (int, LifeListResolveResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/resolve?common="+url.QueryEscape(common), http.NoBody)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.ResolveLifeListCommonName(e.NewContext(req, rec)))
+
+		var body LifeListResolveResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+
+	tests := []struct {
+		name     string
+		common   string
+		wantCode int
+		want     []string
+	}{
+		{"exact", "American Robin", http.StatusOK, []string{"Turdus migratorius"}},
+		{"case insensitive", "american ROBIN", http.StatusOK, []string{"Turdus migratorius"}},
+		{"ambiguous", "Blue Tit", http.StatusOK, []string{"Cyanistes caeruleus", "Cyanistes teneriffae"}},
+		{"not found", "Barred Owl", http.StatusNotFound, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := resolve(tt.common)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.common, body.CommonName)
+			assert.Equal(t, tt.want, body.ScientificNames)
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/resolve", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ResolveLifeListCommonName(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAddLifeListSpecies_AppendsOnce(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.Settings.BirdNET.Labels = []string{"Parus major_Great Tit", "Apus apus_Common Swift"}
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major\n")
+	path := controller.Processor.Settings.SoundId.LifeListPath
+
+	code, response := postLifeListSpecies(t, controller, `{"scientific_name": "Apus apus"}`)
+	require.Equal(t, http.StatusCreated, code)
+	require.NotNil(t, response.Entry)
+	assert.Equal(t, "Apus apus", response.Entry.ScientificName)
+	assert.Equal(t, "seen", response.Entry.Status)
+
+	// Adding the species again succeeds without duplicating its row
+	code, response = postLifeListSpecies(t, controller, `{"scientific_name": "apus apus"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, response.Added)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "Apus apus"))
+	assert.Len(t, controller.Processor.LifeListEntries(), 2)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist", strings.NewReader(`{"scientific_name": " "}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.AddLifeListSpecies(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestExportLifeList_RoundTrips(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major,1,Home,,2001-06-01\n")
+	controller.Processor.Settings.SoundId.LifeListStatusPath = filepath.Join(t.TempDir(), "lifelist_status.json")
+
+	body := "2,2,species,Common Swift,Apus apus,1,Home,,2026-06-02\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/lifelist/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ImportLifeList(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	want := controller.Processor.LifeListEntries()
+	require.Len(t, want, 2)
+
+	for _, format := range []string{"csv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/export?format="+format, http.NoBody)
+			rec := httptest.NewRecorder()
+			require.NoError(t, controller.ExportLifeList(e.NewContext(req, rec)))
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+			assert.Contains(t, rec.Header().Get("Content-Disposition"), "."+format)
+			assert.Less(t, strings.Index(rec.Body.String(), "Apus apus"), strings.Index(rec.Body.String(), "Parus major"))
+
+			path := filepath.Join(t.TempDir(), "export."+format)
+			require.NoError(t, os.WriteFile(path, rec.Body.Bytes(), 0o600))
+			reloaded := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: path}}}
+			require.NoError(t, reloaded.ReloadLifeList(t.Context()))
+
+			got := reloaded.LifeListEntries()
+			require.Len(t, got, len(want))
+			for i := range want {
+				assert.Equal(t, want[i].ScientificName, got[i].ScientificName)
+				assert.Equal(t, want[i].CommonName, got[i].CommonName)
+				assert.True(t, want[i].FirstSeen.Equal(got[i].FirstSeen), "first seen of %s", want[i].ScientificName)
+			}
+		})
+	}
+}
+
+func TestExportLifeList_RejectsUnknownFormat(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	loadTestLifeList(t, controller, "1,1,species,Great Tit,Parus major\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/lifelist/export?format=xml", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ExportLifeList(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLifeListRoutes_UserDataReadsNeedAuth(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.authMiddleware = func(echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error { return ctx.NoContent(http.StatusUnauthorized) }
+	}
+	controller.Group = e.Group("/api/v2")
+	controller.initLifeListRoutes()
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/lifelist"+path, http.NoBody))
+		return rec.Code
+	}
+	for _, path := range []string{"/export", "/audit", "/suppressed", "/pending"} {
+		assert.Equal(t, http.StatusUnauthorized, serve(path), "GET %s exposes user data", path)
+	}
+	assert.NotEqual(t, http.StatusUnauthorized, serve("/count"), "counts stay public")
+}
@@ -131,8 +131,12 @@ func (c *Controller) initMediaRoutes() {
 	c.Echo.GET("/api/v2/audio/:id", c.ServeAudioByID)
 	c.Echo.GET("/api/v2/spectrogram/:id", c.ServeSpectrogramByID)
 	c.Echo.GET("/api/v2/spectrogram/:id/status", c.GetSpectrogramStatus)
+	c.Echo.GET("/api/v2/spectrogram/:id/frames", c.GetClipSpectrogramFrames)
 	c.Echo.POST("/api/v2/spectrogram/:id/generate", c.GenerateSpectrogramByID)
 
+	// Combined clip + spectrogram + metadata download for a detection
+	c.Group.GET("/media/bundle/:id", c.ServeDetectionBundle)
+
 	// Convenient combined endpoint (redirects to ID-based internally)
 	c.Group.GET("/media/audio", c.ServeAudioByQueryID)
 
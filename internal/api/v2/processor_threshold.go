@@ -0,0 +1,73 @@
+// internal/api/v2/processor_threshold.go
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// ConfidenceThresholdRequest is the body of a global confidence threshold change
+type ConfidenceThresholdRequest struct {
+	Threshold *float64 `json:"threshold"`
+}
+
+// ConfidenceThresholdResponse reports the global confidence threshold
+type ConfidenceThresholdResponse struct {
+	Threshold float64 `json:"threshold"`
+}
+
+// initProcessorThresholdRoutes registers the global confidence threshold endpoints
+func (c *Controller) initProcessorThresholdRoutes() {
+	c.Group.GET("/processor/threshold", c.GetConfidenceThreshold)
+	c.Group.PUT("/processor/threshold", c.UpdateConfidenceThreshold, c.authMiddleware)
+}
+
+// GetConfidenceThreshold handles GET /api/v2/processor/threshold
+// Returns the global confidence threshold the processor currently applies
+func (c *Controller) GetConfidenceThreshold(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.processorUnavailable(ctx)
+	}
+	return ctx.JSON(http.StatusOK, ConfidenceThresholdResponse{Threshold: c.Processor.ConfidenceThreshold()})
+}
+
+// UpdateConfidenceThreshold handles PUT /api/v2/processor/threshold
+// Changes the global confidence threshold for subsequent detections without a restart.
+// The change is not written to the config file.
+func (c *Controller) UpdateConfidenceThreshold(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.processorUnavailable(ctx)
+	}
+
+	var req ConfidenceThresholdRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if req.Threshold == nil {
+		return c.HandleError(ctx, errors.Newf("threshold is required").
+			Category(errors.CategoryValidation).
+			Component("api-processor").
+			Build(), "Threshold is required", http.StatusBadRequest)
+	}
+	if err := c.Processor.SetConfidenceThreshold(*req.Threshold); err != nil {
+		return c.HandleError(ctx, err, "Threshold must be between 0 and 1", http.StatusBadRequest)
+	}
+
+	c.logInfoIfEnabled("Updated global confidence threshold",
+		logger.Float64("threshold", *req.Threshold),
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	return ctx.JSON(http.StatusOK, ConfidenceThresholdResponse{Threshold: c.Processor.ConfidenceThreshold()})
+}
+
+// processorUnavailable responds that the request needs a running processor.
+func (c *Controller) processorUnavailable(ctx echo.Context) error {
+	return c.HandleError(ctx, errors.Newf("processor not available").
+		Category(errors.CategorySystem).
+		Component("api-processor").
+		Build(), "Processor not available", http.StatusServiceUnavailable)
+}
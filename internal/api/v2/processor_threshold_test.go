@@ -0,0 +1,71 @@
+// processor_threshold_test.go: Tests for the global confidence threshold endpoints
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestConfidenceThreshold_RaiseDropsDetectionBelowIt(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.5
+	controller.Processor = &processor.Processor{Settings: settings}
+
+	preview := func() *processor.DetectionPreview {
+		t.Helper()
+		result, err := controller.Processor.PreviewDetection(processor.DetectionPreviewInput{
+			CommonName:     "Great Tit",
+			ScientificName: "Parus major",
+			Confidence:     0.69,
+		})
+		require.NoError(t, err)
+		return result
+	}
+	assert.NotEqual(t, processor.PreviewStageConfidence, preview().DroppedBy, "the detection passes the confidence stage before the change")
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v2/processor/threshold", strings.NewReader(`{"threshold":0.7}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.UpdateConfidenceThreshold(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	after := preview()
+	assert.Equal(t, processor.PreviewStageConfidence, after.DroppedBy, "a detection just below the new threshold must be dropped")
+	assert.InDelta(t, 0.7, after.Threshold, 1e-6)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/processor/threshold", http.NoBody)
+	rec = httptest.NewRecorder()
+	require.NoError(t, controller.GetConfidenceThreshold(e.NewContext(req, rec)))
+	var body ConfidenceThresholdResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.InDelta(t, 0.7, body.Threshold, 1e-9)
+}
+
+func TestConfidenceThreshold_RejectsOutOfRange(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.5
+	controller.Processor = &processor.Processor{Settings: settings}
+
+	for _, body := range []string{`{"threshold":1.5}`, `{"threshold":-0.1}`, `{}`} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v2/processor/threshold", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, controller.UpdateConfidenceThreshold(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+	assert.InDelta(t, 0.5, controller.Processor.ConfidenceThreshold(), 1e-9, "a rejected value must leave the threshold unchanged")
+}
@@ -1461,6 +1461,7 @@ var settingsChangeChecks = []settingsChangeCheck{
 	{"Streams", "reconfigure_rtsp_sources", streamsSettingsChanged, "Reconfiguring audio streams...", "info", toastDurationMedium},
 	{"Telemetry", "reconfigure_telemetry", telemetrySettingsChanged, "Reconfiguring telemetry settings...", "info", toastDurationShort},
 	{"Species tracking", "reconfigure_species_tracking", speciesTrackingSettingsChanged, "Reconfiguring species tracking...", "info", toastDurationShort},
+	{"UI spectrogram", "reconfigure_ui_spectrogram", uiSpectrogramSettingsChanged, "Updating live spectrogram...", "info", toastDurationShort},
 	{"Web server", "", webserverSettingsChanged, "Web server settings changed. Restart required to apply.", "warning", toastDurationExtended},
 }
 
@@ -1732,6 +1733,12 @@ func speciesTrackingSettingsChanged(oldSettings, currentSettings *conf.Settings)
 		seasonalTrackingChanged(oldTracking.SeasonalTracking, newTracking.SeasonalTracking)
 }
 
+// uiSpectrogramSettingsChanged checks if the live spectrogram was enabled or disabled.
+// Other spectrogram settings are read per frame and don't need a restart.
+func uiSpectrogramSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
+	return oldSettings.SoundId.Enabled != currentSettings.SoundId.Enabled
+}
+
 // webserverSettingsChanged checks if web server settings have changed that require a restart
 func webserverSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 	oldWS := oldSettings.WebServer
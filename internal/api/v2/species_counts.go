@@ -0,0 +1,64 @@
+// internal/api/v2/species_counts.go
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// speciesCountsCSVHeader is the header row of the species counts export
+var speciesCountsCSVHeader = []string{"Scientific Name", "Common Name", "Count", "In Life List", "First Seen", "Last Seen"}
+
+// ExportSpeciesCountsCSV handles GET /api/v2/detections/species-counts.csv
+// Returns every detected species with its detection count, whether it is on the life list
+// and when it was first and last detected. The optional start_date and end_date query
+// parameters (YYYY-MM-DD) limit the counted detections.
+func (c *Controller) ExportSpeciesCountsCSV(ctx echo.Context) error {
+	startDate := ctx.QueryParam("start_date")
+	endDate := ctx.QueryParam("end_date")
+	if err := c.validateDateRangeWithResponse(ctx, startDate, endDate, "species counts export"); err != nil {
+		return err
+	}
+
+	summaryData, _, err := c.fetchSpeciesSummaryData(ctx, startDate, endDate)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get species counts", http.StatusInternalServerError)
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	rows := make([][]string, 0, len(summaryData)+1)
+	rows = append(rows, speciesCountsCSVHeader)
+	for i := range summaryData {
+		data := &summaryData[i]
+		inLifeList := false
+		if c.Processor != nil {
+			_, inLifeList = c.Processor.LifeListEntry(data.ScientificName)
+		}
+		rows = append(rows, []string{
+			sanitizeCSVField(data.ScientificName),
+			sanitizeCSVField(data.CommonName),
+			strconv.Itoa(data.Count),
+			strconv.FormatBool(inLifeList),
+			formatTimeIfNotZero(data.FirstSeen),
+			formatTimeIfNotZero(data.LastSeen),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return c.HandleError(ctx, err, "Failed to generate CSV", http.StatusInternalServerError)
+	}
+
+	filename := "species_counts_" + time.Now().Format("20060102_150405") + ".csv"
+	ctx.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Response().Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	c.logAPIRequest(ctx, logger.LogLevelInfo, "Species counts CSV exported", logger.Int("species_count", len(summaryData)))
+	return ctx.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,63 @@
+// species_counts_test.go: Tests for the per-species detection counts CSV export
+
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+func TestExportSpeciesCountsCSV(t *testing.T) {
+	e, mockDS, controller := setupTestEnvironment(t)
+
+	listPath := filepath.Join(t.TempDir(), "lifelist.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte("1,1,species,American Robin,Turdus migratorius,1,Home,,2001-06-01\n"), 0o600))
+	proc := &processor.Processor{Settings: &conf.Settings{SoundId: conf.SoundIdConfig{LifeListPath: listPath}}}
+	require.NoError(t, proc.ReloadLifeList(t.Context()))
+	controller.Processor = proc
+
+	first := time.Date(2026, 3, 1, 6, 15, 0, 0, time.UTC)
+	last := time.Date(2026, 5, 2, 19, 45, 30, 0, time.UTC)
+	mockDS.On("GetSpeciesSummaryData", mock.Anything, "", "").Return([]datastore.SpeciesSummaryData{
+		{ScientificName: "Turdus migratorius", CommonName: "American Robin", Count: 42, FirstSeen: first, LastSeen: last},
+		{ScientificName: "Cyanocitta cristata", CommonName: "Blue Jay", Count: 7, FirstSeen: first, LastSeen: first},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/detections/species-counts.csv", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.ExportSpeciesCountsCSV(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `attachment; filename="species_counts_`)
+
+	rows, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3, "header plus one row per species")
+	assert.Equal(t, speciesCountsCSVHeader, rows[0])
+	assert.Equal(t, []string{"Turdus migratorius", "American Robin", "42", "true", "2026-03-01 06:15:00", "2026-05-02 19:45:30"}, rows[1])
+	assert.Equal(t, []string{"Cyanocitta cristata", "Blue Jay", "7", "false", "2026-03-01 06:15:00", "2026-03-01 06:15:00"}, rows[2])
+
+	mockDS.AssertExpectations(t)
+}
+
+func TestExportSpeciesCountsCSV_InvalidDateRange(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/detections/species-counts.csv?start_date=2026-05-02&end_date=2026-05-01", http.NoBody)
+	rec := httptest.NewRecorder()
+	_ = controller.ExportSpeciesCountsCSV(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,125 @@
+// internal/api/v2/spectrogram_adaptive_rate.go
+package api
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Adaptive frame rate of spectrogram stream clients whose writes lag
+const (
+	spectrogramSlowWriteThreshold     = 100 * time.Millisecond // A write taking longer marks the client as lagging
+	spectrogramAdaptiveMinInterval    = 100 * time.Millisecond // Interval between a lagging client's frames after its first slow write
+	spectrogramAdaptiveMaxInterval    = 2 * time.Second        // Longest interval, so a lagging client still gets a frame this often
+	spectrogramAdaptiveRecoveryWrites = 10                     // Fast writes in a row after which the interval is halved
+)
+
+// spectrogramAdaptiveRate thins the spectrogram frames sent to one stream client whose
+// writes lag, so they don't pile up in its write buffer. Each slow write doubles the least
+// interval between the client's frames, from spectrogramAdaptiveMinInterval up to
+// spectrogramAdaptiveMaxInterval, and frames arriving sooner are skipped for that client
+// only. Every spectrogramAdaptiveRecoveryWrites fast writes in a row halve the interval,
+// back to full rate once it drops below the minimum. The stream's event loop reports writes
+// while broadcasts ask for admission, so it is safe for concurrent use. A nil rate sends
+// every frame.
+type spectrogramAdaptiveRate struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	interval    time.Duration // Least time between two frames, 0 at full rate
+	lastSent    time.Time     // When the last admitted frame was handed to the client
+	fastWrites  int           // Fast writes in a row since the last change of the interval
+	lastLatency time.Duration // Duration of the client's last write
+	skipped     uint64        // Frames skipped to keep the client at its interval
+}
+
+func newSpectrogramAdaptiveRate() *spectrogramAdaptiveRate {
+	return &spectrogramAdaptiveRate{now: time.Now}
+}
+
+// observe adapts the interval to a write to the client that took latency.
+func (r *spectrogramAdaptiveRate) observe(latency time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastLatency = latency
+	if latency > spectrogramSlowWriteThreshold {
+		r.fastWrites = 0
+		r.interval = min(max(2*r.interval, spectrogramAdaptiveMinInterval), spectrogramAdaptiveMaxInterval)
+		return
+	}
+	if r.interval == 0 {
+		return
+	}
+	r.fastWrites++
+	if r.fastWrites < spectrogramAdaptiveRecoveryWrites {
+		return
+	}
+	r.fastWrites = 0
+	r.interval /= 2
+	if r.interval < spectrogramAdaptiveMinInterval {
+		r.interval = 0
+	}
+}
+
+// admit reports whether a frame broadcast now may be sent to the client, counting it as
+// skipped when it may not.
+func (r *spectrogramAdaptiveRate) admit() bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if r.interval > 0 && now.Sub(r.lastSent) < r.interval {
+		r.skipped++
+		return false
+	}
+	r.lastSent = now
+	return true
+}
+
+// SpectrogramClientRate is the adaptive frame rate of one spectrogram stream client
+type SpectrogramClientRate struct {
+	ClientID      string  `json:"clientId"`
+	MaxFPS        float64 `json:"maxFps"`        // Frames per second the client is held to, 0 at full rate
+	LastWriteMs   float64 `json:"lastWriteMs"`   // Duration of the client's last write
+	FramesSkipped uint64  `json:"framesSkipped"` // Frames not sent to keep the client at its rate
+}
+
+// snapshot returns the current rate of the client with the given ID.
+func (r *spectrogramAdaptiveRate) snapshot(clientID string) SpectrogramClientRate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rate := SpectrogramClientRate{
+		ClientID:      clientID,
+		LastWriteMs:   float64(r.lastLatency) / float64(time.Millisecond),
+		FramesSkipped: r.skipped,
+	}
+	if r.interval > 0 {
+		rate.MaxFPS = float64(time.Second) / float64(r.interval)
+	}
+	return rate
+}
+
+// spectrogramClientRates returns the adaptive rates of the connected spectrogram stream
+// clients, sorted by client ID.
+func (m *SSEManager) spectrogramClientRates() []SpectrogramClientRate {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	rates := make([]SpectrogramClientRate, 0, len(m.clients))
+	for id, client := range m.clients {
+		if client.spectrogramRate != nil {
+			rates = append(rates, client.spectrogramRate.snapshot(id))
+		}
+	}
+	slices.SortFunc(rates, func(a, b SpectrogramClientRate) int { return cmp.Compare(a.ClientID, b.ClientID) })
+	return rates
+}
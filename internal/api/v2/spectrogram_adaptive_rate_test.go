@@ -0,0 +1,139 @@
+// spectrogram_adaptive_rate_test.go: Tests for lowering the frame rate of lagging spectrogram clients
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// adaptiveRateTestClient adds a spectrogram stream client whose adaptive rate reads its time
+// from now
+func adaptiveRateTestClient(m *SSEManager, now *time.Time) *SSEClient {
+	client := &SSEClient{
+		ID:              "slow",
+		StreamType:      streamTypeSpectrogram,
+		SpectrogramChan: make(chan SSEUiSpectrogramData, sseSpectrogramBufferSize),
+		Done:            make(chan struct{}, sseDoneChannelBuffer),
+		spectrogramRate: newSpectrogramAdaptiveRate(),
+	}
+	client.spectrogramRate.now = func() time.Time { return *now }
+	m.AddClient(client)
+	return client
+}
+
+// deliveredFrames broadcasts a frame every 10ms for 200ms and returns how many reached client
+func deliveredFrames(m *SSEManager, client *SSEClient, now *time.Time) int {
+	for range 20 {
+		m.BroadcastUiSpectrogram(&SSEUiSpectrogramData{})
+		*now = now.Add(10 * time.Millisecond)
+	}
+	delivered := len(client.SpectrogramChan)
+	for range delivered {
+		<-client.SpectrogramChan
+	}
+	return delivered
+}
+
+func TestSpectrogramAdaptiveRate_ReducesAndRecovers(t *testing.T) {
+	t.Parallel()
+
+	m := NewSSEManager()
+	now := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	client := adaptiveRateTestClient(m, &now)
+	require.Equal(t, 20, deliveredFrames(m, client, &now), "a client writing fast gets every frame")
+
+	client.spectrogramRate.observe(2 * spectrogramSlowWriteThreshold)
+	assert.Equal(t, 2, deliveredFrames(m, client, &now), "a slow write holds the client to one frame per 100ms")
+	rates := m.spectrogramClientRates()
+	require.Len(t, rates, 1)
+	assert.InDelta(t, 10, rates[0].MaxFPS, 0.001)
+	assert.Equal(t, uint64(18), rates[0].FramesSkipped)
+
+	client.spectrogramRate.observe(2 * spectrogramSlowWriteThreshold)
+	assert.Equal(t, 1, deliveredFrames(m, client, &now), "lagging on halves the rate again")
+	assert.Zero(t, client.consecutiveDrops.Load(), "skipped frames aren't drops that disconnect the client")
+
+	for range 2 * spectrogramAdaptiveRecoveryWrites {
+		client.spectrogramRate.observe(time.Millisecond)
+	}
+	assert.Equal(t, 20, deliveredFrames(m, client, &now), "fast writes restore the full rate")
+	assert.Zero(t, m.spectrogramClientRates()[0].MaxFPS)
+}
+
+func TestSpectrogramAdaptiveRate_MaxInterval(t *testing.T) {
+	t.Parallel()
+
+	rate := newSpectrogramAdaptiveRate()
+	for range 10 {
+		rate.observe(time.Second)
+	}
+	assert.Equal(t, spectrogramAdaptiveMaxInterval, rate.interval, "a lagging client still gets a frame every few seconds")
+	assert.True(t, (*spectrogramAdaptiveRate)(nil).admit(), "a nil rate sends every frame")
+}
+
+// slowResponseWriter is the response of a client whose writes take delay each
+type slowResponseWriter struct {
+	*httptest.ResponseRecorder
+	delay atomic.Int64
+}
+
+func (w *slowResponseWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Duration(w.delay.Load()))
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestRunSSEEventLoop_SlowWritesLowerSpectrogramRate(t *testing.T) {
+	t.Parallel()
+
+	c := &Controller{Settings: &conf.Settings{}}
+	writer := &slowResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+	writer.delay.Store(int64(2 * spectrogramSlowWriteThreshold))
+	reqCtx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, spectrogramStreamEndpoint, http.NoBody).WithContext(reqCtx)
+	ctx := echo.New().NewContext(req, writer)
+
+	client := &SSEClient{
+		ID:              "slow",
+		StreamType:      streamTypeSpectrogram,
+		SpectrogramChan: make(chan SSEUiSpectrogramData, sseSpectrogramBufferSize),
+		Done:            make(chan struct{}, sseDoneChannelBuffer),
+		spectrogramRate: newSpectrogramAdaptiveRate(),
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- c.runSSEEventLoop(ctx, client, client.ID, spectrogramStreamEndpoint, func() (any, bool) {
+			select {
+			case frame := <-client.SpectrogramChan:
+				return frame, true
+			default:
+				return nil, false
+			}
+		}, "ui_spectrogram", streamTypeSpectrogram)
+	}()
+	maxFPS := func() float64 { return client.spectrogramRate.snapshot(client.ID).MaxFPS }
+
+	client.SpectrogramChan <- SSEUiSpectrogramData{}
+	require.Eventually(t, func() bool { return maxFPS() > 0 }, 2*time.Second, 5*time.Millisecond,
+		"a slow write lowers the client's rate")
+
+	writer.delay.Store(0)
+	for range spectrogramAdaptiveRecoveryWrites {
+		client.SpectrogramChan <- SSEUiSpectrogramData{}
+	}
+	require.Eventually(t, func() bool { return maxFPS() == 0 }, 2*time.Second, 5*time.Millisecond,
+		"the client recovers its full rate once writes are fast again")
+
+	cancel()
+	require.NoError(t, <-done)
+}
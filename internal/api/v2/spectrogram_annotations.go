@@ -0,0 +1,139 @@
+// internal/api/v2/spectrogram_annotations.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Spectrogram annotation limits
+const (
+	maxActiveSpectrogramAnnotations = 50  // Oldest annotations are evicted beyond this
+	maxAnnotationTextLength         = 200 // Characters
+	maxAnnotationAuthorLength       = 64  // Characters
+
+	// spectrogramAnnotationEventType is the SSE event name annotations are sent under on
+	// the spectrogram stream, distinct from the "ui_spectrogram" frame events
+	spectrogramAnnotationEventType = "spectrogram_annotation"
+)
+
+// SpectrogramAnnotationRequest is the request body for POST /api/v2/spectrogram/annotations
+type SpectrogramAnnotationRequest struct {
+	Text   string    `json:"text"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Author string    `json:"author,omitempty"`
+}
+
+// SpectrogramAnnotation is a labeled time region shared with spectrogram stream viewers
+type SpectrogramAnnotation struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SSESpectrogramAnnotation represents an annotation event sent via SSE
+type SSESpectrogramAnnotation struct {
+	SpectrogramAnnotation
+	EventType string `json:"eventType"`
+}
+
+// sseEventName sends annotations under their own event type on the spectrogram stream.
+func (SSESpectrogramAnnotation) sseEventName() string {
+	return spectrogramAnnotationEventType
+}
+
+// spectrogramAnnotationStore keeps the most recent annotations so clients that connect
+// later can fetch them. The zero value is ready to use.
+type spectrogramAnnotationStore struct {
+	mu    sync.Mutex
+	items []SpectrogramAnnotation
+}
+
+// add stores an annotation, evicting the oldest once the limit is reached.
+func (s *spectrogramAnnotationStore) add(a SpectrogramAnnotation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.items) >= maxActiveSpectrogramAnnotations {
+		s.items = s.items[len(s.items)-maxActiveSpectrogramAnnotations+1:]
+	}
+	s.items = append(s.items, a)
+}
+
+// list returns a copy of the active annotations, oldest first.
+func (s *spectrogramAnnotationStore) list() []SpectrogramAnnotation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SpectrogramAnnotation, len(s.items))
+	copy(out, s.items)
+	return out
+}
+
+// CreateSpectrogramAnnotation handles POST /api/v2/spectrogram/annotations
+// It stores a labeled time region and broadcasts it to all spectrogram stream clients
+// as a "spectrogram_annotation" event.
+func (c *Controller) CreateSpectrogramAnnotation(ctx echo.Context) error {
+	var req SpectrogramAnnotationRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+
+	req.Text = strings.TrimSpace(req.Text)
+	req.Author = strings.TrimSpace(req.Author)
+	switch {
+	case req.Text == "":
+		return c.HandleError(ctx, fmt.Errorf("missing text"), "Text is required", http.StatusBadRequest)
+	case len([]rune(req.Text)) > maxAnnotationTextLength:
+		return c.HandleError(ctx, fmt.Errorf("text too long"),
+			fmt.Sprintf("Text must be at most %d characters", maxAnnotationTextLength), http.StatusBadRequest)
+	case len([]rune(req.Author)) > maxAnnotationAuthorLength:
+		return c.HandleError(ctx, fmt.Errorf("author too long"),
+			fmt.Sprintf("Author must be at most %d characters", maxAnnotationAuthorLength), http.StatusBadRequest)
+	case req.Start.IsZero() || req.End.IsZero():
+		return c.HandleError(ctx, fmt.Errorf("missing time range"), "Start and end are required", http.StatusBadRequest)
+	case req.End.Before(req.Start):
+		return c.HandleError(ctx, fmt.Errorf("end before start"), "End must not be before start", http.StatusBadRequest)
+	}
+
+	annotation := SpectrogramAnnotation{
+		ID:        uuid.New().String(),
+		Text:      req.Text,
+		Start:     req.Start,
+		End:       req.End,
+		Author:    req.Author,
+		CreatedAt: time.Now(),
+	}
+	c.spectrogramAnnotations.add(annotation)
+
+	if c.sseManager != nil {
+		c.sseManager.BroadcastSpectrogramAnnotation(&SSESpectrogramAnnotation{
+			SpectrogramAnnotation: annotation,
+			EventType:             spectrogramAnnotationEventType,
+		})
+	}
+
+	c.logInfoIfEnabled("Spectrogram annotation added",
+		logger.String("annotation_id", annotation.ID),
+		logger.String("author", annotation.Author),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusCreated, annotation)
+}
+
+// GetSpectrogramAnnotations handles GET /api/v2/spectrogram/annotations
+// It returns the active annotations so newly connected viewers can catch up.
+func (c *Controller) GetSpectrogramAnnotations(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, c.spectrogramAnnotations.list())
+}
@@ -0,0 +1,102 @@
+// spectrogram_annotations_test.go: Tests for labeled spectrogram regions broadcast over SSE
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSpectrogramAnnotation_DeliveredAsDistinctEvent(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v2/spectrogram/stream", http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Wait until the stream client is registered before posting
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "Connected") {
+			break
+		}
+	}
+
+	body := `{"text":"call starts here","start":"2026-05-01T06:00:00Z","end":"2026-05-01T06:00:02Z","author":"alice"}`
+	postReq := httptest.NewRequest(http.MethodPost, "/api/v2/spectrogram/annotations", strings.NewReader(body))
+	postReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.CreateSpectrogramAnnotation(controller.Echo.NewContext(postReq, rec)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			event = after
+		}
+		if after, ok := strings.CutPrefix(line, "data: "); ok && event == spectrogramAnnotationEventType {
+			data = after
+			break
+		}
+	}
+	require.Equal(t, spectrogramAnnotationEventType, event)
+	assert.Contains(t, data, `"text":"call starts here"`)
+	assert.Contains(t, data, `"author":"alice"`)
+}
+
+func TestCreateSpectrogramAnnotation_Validation(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing text", `{"start":"2026-05-01T06:00:00Z","end":"2026-05-01T06:00:02Z"}`},
+		{"missing range", `{"text":"x"}`},
+		{"end before start", `{"text":"x","start":"2026-05-01T06:00:02Z","end":"2026-05-01T06:00:00Z"}`},
+		{"text too long", `{"text":"` + strings.Repeat("a", maxAnnotationTextLength+1) + `","start":"2026-05-01T06:00:00Z","end":"2026-05-01T06:00:02Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/spectrogram/annotations", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			_ = controller.CreateSpectrogramAnnotation(e.NewContext(req, rec))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestSpectrogramAnnotationStore_Bounded(t *testing.T) {
+	t.Parallel()
+
+	var store spectrogramAnnotationStore
+	for i := range maxActiveSpectrogramAnnotations + 5 {
+		store.add(SpectrogramAnnotation{ID: string(rune('A' + i))})
+	}
+
+	items := store.list()
+	require.Len(t, items, maxActiveSpectrogramAnnotations)
+	assert.Equal(t, string(rune('A'+5)), items[0].ID, "oldest annotations should be evicted first")
+}
@@ -0,0 +1,90 @@
+// internal/api/v2/spectrogram_binary.go
+package api
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// SSE event names of spectrogram frames sent in the binary encoding
+const (
+	spectrogramBinaryEventType      = "ui_spectrogram_binary"
+	spectrogramBinaryBatchEventType = "ui_spectrogram_batch_binary"
+)
+
+// spectrogramBinaryRecent flags a binary frame buffered for clients on connect
+const spectrogramBinaryRecent byte = 1
+
+// sseRawEvent is implemented by payloads sent as their own text rather than as JSON
+type sseRawEvent interface {
+	sseRawData() string
+}
+
+// sseBinaryEvent is a binary payload sent base64 encoded under its own event name
+type sseBinaryEvent struct {
+	event   string
+	payload []byte
+}
+
+func (e sseBinaryEvent) sseEventName() string { return e.event }
+func (e sseBinaryEvent) sseRawData() string   { return base64.StdEncoding.EncodeToString(e.payload) }
+
+// spectrogramFrameEncoder turns the frames of one spectrogram stream into the payloads sent
+// to its client, leaving them as JSON objects unless the binary encoding is configured
+type spectrogramFrameEncoder struct {
+	binary bool
+}
+
+// newSpectrogramFrameEncoder returns the encoder for the configured stream encoding
+func newSpectrogramFrameEncoder(settings *conf.Settings) spectrogramFrameEncoder {
+	return spectrogramFrameEncoder{
+		binary: settings != nil && settings.SoundId.UiSpectrogram.SpectrogramEncoding == conf.UiSpectrogramEncodingBinary,
+	}
+}
+
+// encoding returns the SpectrogramEncoding value the encoder sends frames in
+func (e spectrogramFrameEncoder) encoding() string {
+	if e.binary {
+		return conf.UiSpectrogramEncodingBinary
+	}
+	return conf.UiSpectrogramEncodingJSON
+}
+
+// frame returns the payload of one frame. A binary frame is a flags byte, where bit 0 marks
+// a recent frame, the interpolated column count as u16 and the frame's EncodeBinary layout.
+func (e spectrogramFrameEncoder) frame(frame SSEUiSpectrogramData) any {
+	if !e.binary {
+		return frame
+	}
+	return sseBinaryEvent{event: spectrogramBinaryEventType, payload: appendSpectrogramBinaryFrame(nil, &frame)}
+}
+
+// batch returns the payload of a batch of frames. A binary batch is the frame count as u16
+// followed by each frame's binary payload, prefixed with its length as u32.
+func (e spectrogramFrameEncoder) batch(batch SSEUiSpectrogramBatch) any {
+	if !e.binary {
+		return batch
+	}
+	count := min(len(batch.Frames), math.MaxUint16)
+	payload := binary.LittleEndian.AppendUint16(nil, uint16(count)) //nolint:gosec // G115: capped above
+	for i := range count {
+		frame := appendSpectrogramBinaryFrame(nil, &batch.Frames[i])
+		payload = binary.LittleEndian.AppendUint32(payload, uint32(len(frame))) //nolint:gosec // G115: frames are far below 4 GiB
+		payload = append(payload, frame...)
+	}
+	return sseBinaryEvent{event: spectrogramBinaryBatchEventType, payload: payload}
+}
+
+// appendSpectrogramBinaryFrame appends the binary payload of one frame to out
+func appendSpectrogramBinaryFrame(out []byte, frame *SSEUiSpectrogramData) []byte {
+	var flags byte
+	if frame.Recent {
+		flags |= spectrogramBinaryRecent
+	}
+	out = append(out, flags)
+	out = binary.LittleEndian.AppendUint16(out, uint16(min(max(frame.InterpolatedColumns, 0), math.MaxUint16))) //nolint:gosec // G115: clamped above
+	return append(out, frame.EncodeBinary()...)
+}
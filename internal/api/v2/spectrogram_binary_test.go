@@ -0,0 +1,70 @@
+// spectrogram_binary_test.go: Tests for the binary encoding of spectrogram stream frames
+
+package api
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// decodeSpectrogramBinaryFrame reads one frame payload written by appendSpectrogramBinaryFrame
+func decodeSpectrogramBinaryFrame(t *testing.T, payload []byte) SSEUiSpectrogramData {
+	t.Helper()
+	require.GreaterOrEqual(t, len(payload), 3)
+	frame := SSEUiSpectrogramData{
+		Recent:              payload[0]&spectrogramBinaryRecent != 0,
+		InterpolatedColumns: int(binary.LittleEndian.Uint16(payload[1:3])),
+	}
+	require.NoError(t, frame.DecodeBinary(payload[3:]))
+	return frame
+}
+
+func TestSpectrogramFrameEncoder(t *testing.T) {
+	frame := SSEUiSpectrogramData{
+		UiSpectrogramData: myaudio.UiSpectrogramData{
+			Spectrogram: []byte{1, 2, 3},
+			Source:      "mic",
+			MsPerColumn: 32,
+			Timestamp:   time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC),
+		},
+		InterpolatedColumns: 2,
+		Recent:              true,
+	}
+
+	jsonEncoder := newSpectrogramFrameEncoder(&conf.Settings{})
+	assert.Equal(t, conf.UiSpectrogramEncodingJSON, jsonEncoder.encoding())
+	assert.Equal(t, frame, jsonEncoder.frame(frame), "JSON frames are sent as they are")
+
+	settings := &conf.Settings{}
+	settings.SoundId.UiSpectrogram.SpectrogramEncoding = conf.UiSpectrogramEncodingBinary
+	encoder := newSpectrogramFrameEncoder(settings)
+	assert.Equal(t, conf.UiSpectrogramEncodingBinary, encoder.encoding())
+
+	event, ok := encoder.frame(frame).(sseBinaryEvent)
+	require.True(t, ok)
+	assert.Equal(t, spectrogramBinaryEventType, event.sseEventName())
+	payload, err := base64.StdEncoding.DecodeString(event.sseRawData())
+	require.NoError(t, err)
+	decoded := decodeSpectrogramBinaryFrame(t, payload)
+	frame.EventType = "" // The event name says what the payload is
+	assert.Equal(t, frame, decoded)
+
+	batch, ok := encoder.batch(SSEUiSpectrogramBatch{Frames: []SSEUiSpectrogramData{frame, frame}}).(sseBinaryEvent)
+	require.True(t, ok)
+	assert.Equal(t, spectrogramBinaryBatchEventType, batch.sseEventName())
+	require.Equal(t, uint16(2), binary.LittleEndian.Uint16(batch.payload))
+	rest := batch.payload[2:]
+	for range 2 {
+		size := binary.LittleEndian.Uint32(rest)
+		assert.Equal(t, frame, decodeSpectrogramBinaryFrame(t, rest[4:4+size]))
+		rest = rest[4+size:]
+	}
+	assert.Empty(t, rest)
+}
@@ -0,0 +1,231 @@
+// internal/api/v2/spectrogram_bundle.go
+package api
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+const (
+	// maxDebugBundleFrames is the number of recent spectrogram frames kept for debug bundles
+	maxDebugBundleFrames = 64
+	// debugBundleDetections is the number of recent detections included in a debug bundle
+	debugBundleDetections = 50
+	// debugBundleLogLines is the number of trailing lines included from each log file
+	debugBundleLogLines = 200
+)
+
+// debugBundleLogPaths lists the log files whose tails go into a debug bundle.
+// It is a variable so tests can point it at temporary files.
+var debugBundleLogPaths = []string{
+	logger.DefaultLogPath,
+	logger.DefaultAudioLogPath,
+	logger.DefaultSpectrogramLogPath,
+	logger.DefaultActionsLogPath,
+}
+
+// debugBundlePathPattern matches absolute Unix or Windows paths in log lines, with the
+// character before the path in group 1 so URLs aren't mistaken for paths.
+var debugBundlePathPattern = regexp.MustCompile(`(^|[\s"'=(\[])((?:[A-Za-z]:\\|/)[^\s"',)\]]+)`)
+
+// spectrogramFrameHistory keeps the most recently broadcast spectrogram frames.
+// The zero value is ready to use.
+type spectrogramFrameHistory struct {
+	mu     sync.Mutex
+	frames []myaudio.UiSpectrogramData
+	added  uint64 // Frames added since startup, so readers can tell when new frames arrived
+}
+
+// add stores a frame, evicting the oldest once the limit is reached.
+func (h *spectrogramFrameHistory) add(frame *myaudio.UiSpectrogramData) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.frames) >= maxDebugBundleFrames {
+		h.frames = h.frames[len(h.frames)-maxDebugBundleFrames+1:]
+	}
+	h.frames = append(h.frames, *frame)
+	h.added++
+}
+
+// list returns a copy of the stored frames, oldest first.
+func (h *spectrogramFrameHistory) list() []myaudio.UiSpectrogramData {
+	frames, _ := h.snapshot()
+	return frames
+}
+
+// snapshot returns a copy of the stored frames along with the number of frames added so far.
+func (h *spectrogramFrameHistory) snapshot() ([]myaudio.UiSpectrogramData, uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make([]myaudio.UiSpectrogramData, len(h.frames))
+	copy(out, h.frames)
+	return out, h.added
+}
+
+// debugBundleConfig is the analysis configuration included in a debug bundle, with
+// file paths anonymized and the station location removed.
+type debugBundleConfig struct {
+	BirdNET conf.BirdNETConfig `json:"birdnet"`
+	SoundId conf.SoundIdConfig `json:"soundId"`
+}
+
+// newDebugBundleConfig copies the analysis settings and redacts what identifies the station.
+func newDebugBundleConfig(settings *conf.Settings) debugBundleConfig {
+	cfg := debugBundleConfig{BirdNET: settings.BirdNET, SoundId: settings.SoundId}
+
+	cfg.BirdNET.Latitude, cfg.BirdNET.Longitude = 0, 0
+	cfg.BirdNET.ModelPath = privacy.AnonymizePath(cfg.BirdNET.ModelPath)
+	cfg.BirdNET.LabelPath = privacy.AnonymizePath(cfg.BirdNET.LabelPath)
+	cfg.BirdNET.RangeFilter.ModelPath = privacy.AnonymizePath(cfg.BirdNET.RangeFilter.ModelPath)
+	cfg.BirdNET.RangeFilter.LabelPath = privacy.AnonymizePath(cfg.BirdNET.RangeFilter.LabelPath)
+
+	cfg.SoundId.UiModelPath = privacy.AnonymizePath(cfg.SoundId.UiModelPath)
+	cfg.SoundId.DataDir = privacy.AnonymizePath(cfg.SoundId.DataDir)
+	cfg.SoundId.LifeListStatusPath = privacy.AnonymizePath(cfg.SoundId.LifeListStatusPath)
+	cfg.SoundId.BigDayPath = privacy.AnonymizePath(cfg.SoundId.BigDayPath)
+	cfg.SoundId.UiSpectrogram.Video.Path = privacy.AnonymizePath(cfg.SoundId.UiSpectrogram.Video.Path)
+	cfg.SoundId.UiSpectrogram.SpectrogramRecordDir = privacy.AnonymizePath(cfg.SoundId.UiSpectrogram.SpectrogramRecordDir)
+	cfg.SoundId.LifeListPath = anonymizePathOrURL(cfg.SoundId.LifeListPath)
+	if len(cfg.SoundId.LifeListPaths) > 0 {
+		// The copy shares the slice with the live settings, so anonymize a new one
+		paths := make([]string, len(cfg.SoundId.LifeListPaths))
+		for i, path := range cfg.SoundId.LifeListPaths {
+			paths[i] = anonymizePathOrURL(path)
+		}
+		cfg.SoundId.LifeListPaths = paths
+	}
+	cfg.SoundId.LifeListAuthorityPath = anonymizePathOrURL(cfg.SoundId.LifeListAuthorityPath)
+	cfg.SoundId.LifeListSynonymsPath = anonymizePathOrURL(cfg.SoundId.LifeListSynonymsPath)
+	return cfg
+}
+
+// anonymizePathOrURL anonymizes a setting that holds either a file path or an http(s) URL.
+func anonymizePathOrURL(value string) string {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return privacy.AnonymizeURL(value)
+	}
+	return privacy.AnonymizePath(value)
+}
+
+// tailDebugBundleLogs returns the scrubbed trailing lines of each configured log file.
+// Missing files are skipped, since not every module logs to its own file.
+func tailDebugBundleLogs() string {
+	var out strings.Builder
+	for _, path := range debugBundleLogPaths {
+		lines, err := tailFileLines(path, debugBundleLogLines)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&out, "==> %s <==\n", filepath.Base(path))
+		for _, line := range lines {
+			out.WriteString(scrubDebugBundleLogLine(line))
+			out.WriteByte('\n')
+		}
+	}
+	return out.String()
+}
+
+// scrubDebugBundleLogLine removes URLs, addresses, tokens and local paths from a log line.
+func scrubDebugBundleLogLine(line string) string {
+	return debugBundlePathPattern.ReplaceAllStringFunc(privacy.ScrubMessage(line), func(match string) string {
+		groups := debugBundlePathPattern.FindStringSubmatch(match)
+		return groups[1] + privacy.AnonymizePath(groups[2])
+	})
+}
+
+// tailFileLines reads a file and returns up to its last n lines.
+func tailFileLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// GetSpectrogramDebugBundle handles GET /api/v2/spectrogram/debug-bundle
+// It returns a zip archive with the recent spectrogram frames, the last detections,
+// the effective analysis configuration and the tails of the relevant logs, so a
+// misdetection can be shared and reproduced.
+func (c *Controller) GetSpectrogramDebugBundle(ctx echo.Context) error {
+	var detections []datastore.Note
+	if c.DS != nil {
+		notes, err := c.DS.GetLastDetections(debugBundleDetections)
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to get recent detections", http.StatusInternalServerError)
+		}
+		detections = notes
+	}
+
+	entries := []struct {
+		name  string
+		value any
+	}{
+		{"frames.json", c.spectrogramHistory.list()},
+		{"detections.json", detections},
+		{"config.json", newDebugBundleConfig(c.Settings)},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to create debug bundle", http.StatusInternalServerError)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entry.value); err != nil {
+			return c.HandleError(ctx, err, "Failed to create debug bundle", http.StatusInternalServerError)
+		}
+	}
+	w, err := zw.Create("logs.txt")
+	if err == nil {
+		_, err = w.Write([]byte(tailDebugBundleLogs()))
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to create debug bundle", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("Generated spectrogram debug bundle",
+		logger.Int("detections", len(detections)),
+		logger.Int("size", buf.Len()),
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	filename := fmt.Sprintf("spectrogram-debug-%s.zip", time.Now().Format("20060102-150405"))
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return ctx.Blob(http.StatusOK, "application/zip", buf.Bytes())
+}
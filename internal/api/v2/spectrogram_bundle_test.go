@@ -0,0 +1,79 @@
+// spectrogram_bundle_test.go: Tests for the spectrogram debug bundle download
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestGetSpectrogramDebugBundle_ContainsAllComponents(t *testing.T) {
+	e, mockDS, controller := setupTestEnvironment(t)
+	if controller.sseManager == nil {
+		controller.sseManager = NewSSEManager()
+	}
+
+	logPath := filepath.Join(t.TempDir(), "application.log")
+	require.NoError(t, os.WriteFile(logPath, []byte("analysis started\nloaded model from /home/alice/models/custom.tflite\n"), 0o600))
+	saved := debugBundleLogPaths
+	debugBundleLogPaths = []string{logPath, filepath.Join(t.TempDir(), "missing.log")}
+	t.Cleanup(func() { debugBundleLogPaths = saved })
+
+	controller.Settings.BirdNET.Latitude = 60.1699
+	controller.Settings.BirdNET.ModelPath = "/home/alice/models/custom.tflite"
+	controller.Settings.SoundId.UiSpectrogram.Video.Path = "/home/alice/videos/spectrogram.mp4"
+	controller.Settings.SoundId.UiSpectrogram.SpectrogramRecordDir = "/home/alice/frames"
+
+	require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{
+		Spectrogram: []byte{1, 2, 3},
+		Source:      "mic",
+		Timestamp:   time.Now(),
+	}))
+	mockDS.On("GetLastDetections", debugBundleDetections).Return([]datastore.Note{
+		{ScientificName: "Parus major", CommonName: "Great Tit", Confidence: 0.91},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/spectrogram/debug-bundle", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetSpectrogramDebugBundle(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+		contents[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"frames.json", "detections.json", "config.json", "logs.txt"} {
+		assert.NotEmpty(t, contents[name], "%s must be present and non-empty", name)
+	}
+	assert.Contains(t, contents["frames.json"], `"source": "mic"`)
+	assert.Contains(t, contents["detections.json"], "Parus major")
+	assert.Contains(t, contents["logs.txt"], "analysis started")
+
+	for name, content := range contents {
+		assert.NotContains(t, content, "/home/alice", "%s must not leak local paths", name)
+	}
+	assert.NotContains(t, contents["config.json"], "60.1699", "station location must be removed")
+
+	mockDS.AssertExpectations(t)
+}
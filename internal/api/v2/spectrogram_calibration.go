@@ -0,0 +1,113 @@
+// internal/api/v2/spectrogram_calibration.go
+package api
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// Calibration capture limits
+const (
+	defaultCalibrationDuration = 3 * time.Second
+	maxCalibrationDuration     = 30 * time.Second
+	// calibrationCaptureGrace is how long past the capture duration the request waits for
+	// samples before giving up on a source that isn't delivering audio
+	calibrationCaptureGrace = 5 * time.Second
+)
+
+// SpectrogramCalibrationRequest is the request body for POST /api/v2/spectrogram/calibration
+type SpectrogramCalibrationRequest struct {
+	// Source is the ID of the audio source the calibration tone is played into
+	Source string `json:"source"`
+	// ToneLevel is the known level of the tone, e.g. 94 for a 94 dB SPL calibrator
+	ToneLevel float64 `json:"toneLevel"`
+	// DurationMs is how long the tone is captured (defaults to 3000)
+	DurationMs int `json:"durationMs,omitempty"`
+}
+
+// CaptureSpectrogramCalibration handles POST /api/v2/spectrogram/calibration
+// It measures a known-level tone on a source and stores the gain for that source that
+// displays the tone at the configured reference level, so spectrogram colors mean the same
+// level on every device
+func (c *Controller) CaptureSpectrogramCalibration(ctx echo.Context) error {
+	var req SpectrogramCalibrationRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if req.Source == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing source"), "Source is required", http.StatusBadRequest)
+	}
+	duration := time.Duration(req.DurationMs) * time.Millisecond
+	if req.DurationMs == 0 {
+		duration = defaultCalibrationDuration
+	}
+	if duration <= 0 || duration > maxCalibrationDuration {
+		return c.HandleError(ctx, fmt.Errorf("invalid duration %dms", req.DurationMs),
+			fmt.Sprintf("Duration must be between 1 and %d ms", maxCalibrationDuration.Milliseconds()), http.StatusBadRequest)
+	}
+
+	settings := c.Settings
+	if settings == nil {
+		settings = conf.Setting()
+	}
+
+	captureCtx, cancel := context.WithTimeout(ctx.Request().Context(), duration+calibrationCaptureGrace)
+	defer cancel()
+	measured, err := myaudio.CaptureUiSpectrogramCalibration(captureCtx, req.Source, duration)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var enhancedErr *errors.EnhancedError
+		if errors.As(err, &enhancedErr) {
+			switch enhancedErr.Category {
+			case errors.CategoryState:
+				status = http.StatusConflict
+			case errors.CategoryValidation:
+				status = http.StatusUnprocessableEntity
+			case errors.CategoryTimeout:
+				status = http.StatusGatewayTimeout
+			}
+		}
+		return c.HandleError(ctx, err, "Failed to capture calibration tone", status)
+	}
+
+	c.settingsMutex.Lock()
+	defer c.settingsMutex.Unlock()
+
+	uiSettings := &settings.SoundId.UiSpectrogram
+	calibration := myaudio.NewUiSpectrogramCalibration(req.Source, measured, req.ToneLevel, uiSettings)
+	previousGains, previousTone := uiSettings.CalibrationGains, uiSettings.CalibrationToneLevel
+	// Capture reads the map while frames are built, so it is replaced rather than changed
+	gains := maps.Clone(previousGains)
+	if gains == nil {
+		gains = make(map[string]float64)
+	}
+	gains[req.Source] = calibration.GainDB
+	uiSettings.CalibrationGains = gains
+	uiSettings.CalibrationToneLevel = calibration.ToneLevel
+
+	if !c.DisableSaveSettings {
+		if err := conf.SaveSettings(); err != nil {
+			uiSettings.CalibrationGains, uiSettings.CalibrationToneLevel = previousGains, previousTone
+			return c.HandleError(ctx, err, "Failed to save calibration", http.StatusInternalServerError)
+		}
+	}
+
+	c.logInfoIfEnabled("Calibrated spectrogram amplitude reference",
+		logger.String("source", req.Source),
+		logger.Float64("measured_dbfs", calibration.MeasuredDBFS),
+		logger.Float64("gain_db", calibration.GainDB),
+		logger.Float64("tone_level", calibration.ToneLevel),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	return ctx.JSON(http.StatusOK, calibration)
+}
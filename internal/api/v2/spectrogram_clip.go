@@ -0,0 +1,82 @@
+// internal/api/v2/spectrogram_clip.go
+package api
+
+import (
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// ClipSpectrogramFrameResponse is one spectrogram frame positioned within a detection's clip
+type ClipSpectrogramFrameResponse struct {
+	OffsetMs    float64 `json:"offsetMs"` // Where the frame's samples start within the clip
+	Spectrogram []byte  `json:"spectrogram"`
+	Bins        int     `json:"bins,omitempty"`
+	BinHz       float64 `json:"binHz,omitempty"`
+	MinHz       float64 `json:"minHz,omitempty"`
+	MsPerColumn float64 `json:"msPerColumn"`
+}
+
+// ClipSpectrogramResponse is the spectrogram of a detection's clip on the clip's own
+// timeline, for rendering a fixed-width spectrogram of exactly that clip.
+type ClipSpectrogramResponse struct {
+	ID           string                         `json:"id"`
+	ClipName     string                         `json:"clipName"`
+	ClipLengthMs float64                        `json:"clipLengthMs"`
+	Frames       []ClipSpectrogramFrameResponse `json:"frames"` // Ordered by offset
+}
+
+// GetClipSpectrogramFrames handles GET /api/v2/spectrogram/:id/frames
+// It generates the live UI spectrogram frames of a detection's WAV clip, with each frame's
+// offset from the start of the clip in place of a wall clock timestamp.
+func (c *Controller) GetClipSpectrogramFrames(ctx echo.Context) error {
+	noteID, clipPath, err := c.validateNoteIDAndGetClipPath(ctx)
+	if err != nil || ctx.Response().Committed {
+		return err // Error response already written and logged
+	}
+
+	relPath, err := c.normalizeAndValidatePathWithLogger(clipPath, c.apiLogger)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid clip path", http.StatusBadRequest)
+	}
+	file, err := c.SFS.Open(filepath.Join(c.SFS.BaseDir(), relPath))
+	if err != nil {
+		return c.HandleError(ctx, err, "No audio clip available for this note", http.StatusNotFound)
+	}
+	defer func() { _ = file.Close() }()
+
+	frames, length, err := myaudio.ClipSpectrogram(file, c.clipSpectrogram)
+	if err != nil {
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.GetCategory() == string(errors.CategoryValidation) {
+			return c.HandleError(ctx, err, "Clip spectrogram frames are only available for WAV clips", http.StatusUnsupportedMediaType)
+		}
+		return c.HandleError(ctx, err, "Failed to generate clip spectrogram", http.StatusInternalServerError)
+	}
+
+	resp := ClipSpectrogramResponse{
+		ID:           noteID,
+		ClipName:     relPath,
+		ClipLengthMs: float64(length.Microseconds()) / 1000,
+		Frames:       make([]ClipSpectrogramFrameResponse, 0, len(frames)),
+	}
+	for _, frame := range frames {
+		resp.Frames = append(resp.Frames, ClipSpectrogramFrameResponse{
+			OffsetMs:    float64(frame.Offset.Microseconds()) / 1000,
+			Spectrogram: frame.Data.Spectrogram,
+			Bins:        frame.Data.Bins,
+			BinHz:       frame.Data.BinHz,
+			MinHz:       frame.Data.MinHz,
+			MsPerColumn: frame.Data.MsPerColumn,
+		})
+	}
+
+	c.logDebugIfEnabled("Served clip spectrogram frames",
+		logger.String("note_id", noteID),
+		logger.Int("frames", len(resp.Frames)))
+	return ctx.JSON(http.StatusOK, resp)
+}
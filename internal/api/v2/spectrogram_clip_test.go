@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/mocks"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestGetClipSpectrogramFrames_ClipTimeline(t *testing.T) {
+	e, controller, tempDir := setupMediaTestEnvironment(t)
+
+	// 8.5 frames of audio: the trailing half frame counts towards the length but has no frame
+	const frameSamples = 1024
+	clipName := "2024-01-15_14-30-45_Turdus_migratorius.wav"
+	pcm := make([]byte, (8*frameSamples+frameSamples/2)*2)
+	require.NoError(t, myaudio.SavePCMDataToWAV(filepath.Join(tempDir, clipName), pcm))
+
+	mockDS := mocks.NewMockInterface(t)
+	mockDS.On("GetNoteClipPath", "42").Return(clipName, nil)
+	controller.DS = mockDS
+	controller.clipSpectrogram = func(_ []byte, _, _ string) (myaudio.UiSpectrogramData, error) {
+		return myaudio.UiSpectrogramData{Spectrogram: []byte{1, 2, 3}, Bins: 3, MsPerColumn: 46.4}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/spectrogram/42/frames", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+
+	require.NoError(t, controller.GetClipSpectrogramFrames(c))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp ClipSpectrogramResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "42", resp.ID)
+
+	frameMs := float64(frameSamples) * 1000 / float64(conf.SampleRate)
+	assert.InDelta(t, 8.5*frameMs, resp.ClipLengthMs, 0.01)
+	require.Len(t, resp.Frames, 8)
+
+	assert.InDelta(t, 0, resp.Frames[0].OffsetMs, 0.001, "the first frame starts the clip")
+	for i := 1; i < len(resp.Frames); i++ {
+		assert.Greater(t, resp.Frames[i].OffsetMs, resp.Frames[i-1].OffsetMs, "frames must be in clip order")
+		assert.InDelta(t, float64(i)*frameMs, resp.Frames[i].OffsetMs, 0.01)
+	}
+	assert.LessOrEqual(t, resp.Frames[len(resp.Frames)-1].OffsetMs+frameMs, resp.ClipLengthMs)
+	assert.Equal(t, []byte{1, 2, 3}, resp.Frames[0].Spectrogram)
+}
+
+func TestGetClipSpectrogramFrames_RejectsNonWAVClip(t *testing.T) {
+	e, controller, tempDir := setupMediaTestEnvironment(t)
+
+	clipName := "2024-01-15_14-30-45_Turdus_migratorius.mp3"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, clipName), []byte("ID3 not a wav"), 0o600))
+
+	mockDS := mocks.NewMockInterface(t)
+	mockDS.On("GetNoteClipPath", "42").Return(clipName, nil)
+	controller.DS = mockDS
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/spectrogram/42/frames", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+
+	require.NoError(t, controller.GetClipSpectrogramFrames(c))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
@@ -0,0 +1,203 @@
+// internal/api/v2/spectrogram_compare.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// Layouts of the compare stream
+const (
+	// spectrogramCompareLayoutStack puts both sources in each column: source A's bins
+	// followed by source B's bins, so a column has twice the bins of either source
+	spectrogramCompareLayoutStack = "stack"
+	// spectrogramCompareLayoutDiff stores 128 + (A - B) / 2 for each bin, so 128 means both
+	// sources had the same energy, higher values mean A was louder and lower values B
+	spectrogramCompareLayoutDiff = "diff"
+)
+
+// Compare stream event names
+const (
+	spectrogramCompareEventType         = "ui_spectrogram_compare"
+	spectrogramCompareMetadataEventType = "ui_spectrogram_compare_metadata"
+)
+
+// maxSpectrogramCompareSkew is how far apart the capture times of two frames may be for
+// them to be combined; the older frame of a pair further apart is dropped so the sources
+// resynchronize
+const maxSpectrogramCompareSkew = 250 * time.Millisecond
+
+// spectrogramCompareDiffZero is the diff layout value of a bin with equal energy in both sources
+const spectrogramCompareDiffZero = 128
+
+// SSESpectrogramCompareSegment locates one source's bins within each column of a stacked frame
+type SSESpectrogramCompareSegment struct {
+	Source string `json:"source"`
+	Offset int    `json:"offset"` // Index of the segment's first bin within a column
+	Bins   int    `json:"bins"`
+}
+
+// SSESpectrogramCompareMetadata is sent once when a compare stream client connects and
+// describes how the combined frames are laid out. Segments is empty for the diff layout,
+// whose columns have one bin per frequency of either source.
+type SSESpectrogramCompareMetadata struct {
+	SchemaVersion int                            `json:"schemaVersion"`
+	Layout        string                         `json:"layout"`
+	Sources       []string                       `json:"sources"` // Sources A and B, in that order
+	Segments      []SSESpectrogramCompareSegment `json:"segments,omitempty"`
+	DiffZero      int                            `json:"diffZero,omitempty"` // Value of a bin with no difference, diff layout only
+	Timestamp     time.Time                      `json:"timestamp"`
+}
+
+// spectrogramCompositor pairs frames of two sources by capture time and combines each pair
+// into one frame. Only the client's event loop uses it, so it needs no locking.
+type spectrogramCompositor struct {
+	sourceA, sourceB string
+	layout           string
+	pendingA         *SSEUiSpectrogramData // Latest frame of each source still waiting for its partner
+	pendingB         *SSEUiSpectrogramData
+}
+
+func newSpectrogramCompositor(sourceA, sourceB, layout string) *spectrogramCompositor {
+	return &spectrogramCompositor{sourceA: sourceA, sourceB: sourceB, layout: layout}
+}
+
+// metadata describes the frames the compositor produces, assuming the default bin count
+func (s *spectrogramCompositor) metadata() SSESpectrogramCompareMetadata {
+	metadata := SSESpectrogramCompareMetadata{
+		SchemaVersion: myaudio.UiSpectrogramSchemaVersion,
+		Layout:        s.layout,
+		Sources:       []string{s.sourceA, s.sourceB},
+		Timestamp:     time.Now(),
+	}
+	switch s.layout {
+	case spectrogramCompareLayoutStack:
+		bins := myaudio.UiSpectrogramBins
+		metadata.Segments = []SSESpectrogramCompareSegment{
+			{Source: s.sourceA, Offset: 0, Bins: bins},
+			{Source: s.sourceB, Offset: bins, Bins: bins},
+		}
+	case spectrogramCompareLayoutDiff:
+		metadata.DiffZero = spectrogramCompareDiffZero
+	}
+	return metadata
+}
+
+// add takes a frame of any source and returns a combined frame once it completes a pair.
+// Frames of other sources are ignored.
+func (s *spectrogramCompositor) add(frame SSEUiSpectrogramData) (SSEUiSpectrogramData, bool) {
+	switch frame.Source {
+	case s.sourceA:
+		s.pendingA = &frame
+	case s.sourceB:
+		s.pendingB = &frame
+	default:
+		return SSEUiSpectrogramData{}, false
+	}
+	if s.pendingA == nil || s.pendingB == nil {
+		return SSEUiSpectrogramData{}, false
+	}
+
+	a, b := s.pendingA, s.pendingB
+	skew := a.Timestamp.Sub(b.Timestamp)
+	if skew > maxSpectrogramCompareSkew || -skew > maxSpectrogramCompareSkew || a.ColumnBins() != b.ColumnBins() {
+		// Keep the newer frame waiting for a partner from the other source
+		if a.Timestamp.Before(b.Timestamp) {
+			s.pendingA = nil
+		} else {
+			s.pendingB = nil
+		}
+		return SSEUiSpectrogramData{}, false
+	}
+	s.pendingA, s.pendingB = nil, nil
+	return s.combine(a, b), true
+}
+
+// combine builds one frame from a pair, covering the columns both frames have
+func (s *spectrogramCompositor) combine(a, b *SSEUiSpectrogramData) SSEUiSpectrogramData {
+	bins := a.ColumnBins()
+	columns := min(len(a.Spectrogram), len(b.Spectrogram)) / bins
+
+	combined := SSEUiSpectrogramData{
+		UiSpectrogramData: myaudio.UiSpectrogramData{
+			Source:      s.sourceA + "|" + s.sourceB,
+			Bins:        a.Bins,
+			BinHz:       a.BinHz,
+			MinHz:       a.MinHz,
+			Palette:     a.Palette,
+			MsPerColumn: a.MsPerColumn,
+			Timestamp:   a.Timestamp,
+		},
+		EventType: spectrogramCompareEventType,
+	}
+
+	switch s.layout {
+	case spectrogramCompareLayoutStack:
+		combined.Bins = 2 * bins
+		out := make([]byte, 0, 2*columns*bins)
+		for col := range columns {
+			out = append(out, a.Spectrogram[col*bins:(col+1)*bins]...)
+			out = append(out, b.Spectrogram[col*bins:(col+1)*bins]...)
+		}
+		combined.Spectrogram = out
+	case spectrogramCompareLayoutDiff:
+		out := make([]byte, columns*bins)
+		for i := range out {
+			out[i] = byte(spectrogramCompareDiffZero + (int(a.Spectrogram[i])-int(b.Spectrogram[i]))/2)
+		}
+		combined.Spectrogram = out
+	}
+	return combined
+}
+
+// StreamSpectrogramCompare handles GET /api/v2/spectrogram/compare?a=<source>&b=<source>&layout=stack|diff
+// It streams one spectrogram combining two sources, for A/B comparing microphones. The
+// first event describes the layout; frames are only sent when both sources produced one
+// captured at about the same time.
+func (c *Controller) StreamSpectrogramCompare(ctx echo.Context) error {
+	sourceA, sourceB := ctx.QueryParam("a"), ctx.QueryParam("b")
+	if sourceA == "" || sourceB == "" || sourceA == sourceB {
+		return c.HandleError(ctx, fmt.Errorf("compare needs two different sources, got %q and %q", sourceA, sourceB),
+			"Parameters a and b must name two different sources", http.StatusBadRequest)
+	}
+	layout := ctx.QueryParam("layout")
+	if layout == "" {
+		layout = spectrogramCompareLayoutStack
+	}
+	if layout != spectrogramCompareLayoutStack && layout != spectrogramCompareLayoutDiff {
+		return c.HandleError(ctx, fmt.Errorf("unknown compare layout %q", layout),
+			fmt.Sprintf("Layout must be %s or %s", spectrogramCompareLayoutStack, spectrogramCompareLayoutDiff),
+			http.StatusBadRequest)
+	}
+	compositor := newSpectrogramCompositor(sourceA, sourceB, layout)
+
+	return c.handleSSEStream(ctx, streamTypeSpectrogram, "Connected to spectrogram compare stream", "ui_spectrogram_compare",
+		func(client *SSEClient) {
+			client.Channel = make(chan SSEDetectionData, sseMinimalBufferSize)
+			client.SpectrogramChan = make(chan SSEUiSpectrogramData, sseSpectrogramBufferSize)
+		},
+		func(ctx echo.Context, client *SSEClient, clientID string) error {
+			if err := c.sendSSEMessage(ctx, spectrogramCompareMetadataEventType, compositor.metadata()); err != nil {
+				return err
+			}
+			return c.runSSEEventLoop(ctx, client, clientID, spectrogramStreamEndpoint,
+				func() (any, bool) {
+					select {
+					case frame, ok := <-client.SpectrogramChan:
+						if !ok {
+							return nil, false
+						}
+						return compositor.add(frame)
+					default:
+						return nil, false
+					}
+				},
+				spectrogramCompareEventType,
+				streamTypeSpectrogram,
+			)
+		})
+}
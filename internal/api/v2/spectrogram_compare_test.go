@@ -0,0 +1,81 @@
+// spectrogram_compare_test.go: Tests for combining two sources into one compare stream frame
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// compareTestFrame returns a frame of two columns, with toneLevel at toneBin in each column
+func compareTestFrame(source string, at time.Time, toneBin int, toneLevel byte) SSEUiSpectrogramData {
+	bins := myaudio.UiSpectrogramBins
+	spectrogram := make([]byte, 2*bins)
+	for col := range 2 {
+		spectrogram[col*bins+toneBin] = toneLevel
+	}
+	return SSEUiSpectrogramData{UiSpectrogramData: myaudio.UiSpectrogramData{
+		Source:      source,
+		Spectrogram: spectrogram,
+		Timestamp:   at,
+	}}
+}
+
+func TestSpectrogramCompositor_ToneInOneSource(t *testing.T) {
+	const toneBin = 40
+	bins := myaudio.UiSpectrogramBins
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+
+	t.Run("stack", func(t *testing.T) {
+		compositor := newSpectrogramCompositor("mic-a", "mic-b", spectrogramCompareLayoutStack)
+		_, ok := compositor.add(compareTestFrame("mic-a", at, toneBin, 200))
+		require.False(t, ok, "a frame waits for its partner")
+		_, ok = compositor.add(compareTestFrame("other", at, toneBin, 90))
+		require.False(t, ok, "other sources are ignored")
+
+		combined, ok := compositor.add(compareTestFrame("mic-b", at.Add(10*time.Millisecond), toneBin, 0))
+		require.True(t, ok)
+		assert.Equal(t, spectrogramCompareEventType, combined.EventType)
+		require.Equal(t, 2*bins, combined.ColumnBins())
+		require.Len(t, combined.Spectrogram, 2*2*bins)
+
+		segments := compositor.metadata().Segments
+		require.Len(t, segments, 2)
+		for col := range 2 {
+			column := combined.Spectrogram[col*2*bins : (col+1)*2*bins]
+			a := column[segments[0].Offset : segments[0].Offset+segments[0].Bins]
+			b := column[segments[1].Offset : segments[1].Offset+segments[1].Bins]
+			assert.Equal(t, byte(200), a[toneBin], "source A carries the tone")
+			assert.Equal(t, byte(0), b[toneBin], "source B stays silent")
+		}
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		compositor := newSpectrogramCompositor("mic-a", "mic-b", spectrogramCompareLayoutDiff)
+		compositor.add(compareTestFrame("mic-a", at, toneBin, 200))
+		combined, ok := compositor.add(compareTestFrame("mic-b", at, toneBin, 0))
+		require.True(t, ok)
+		require.Equal(t, bins, combined.ColumnBins())
+
+		diffZero := byte(compositor.metadata().DiffZero)
+		assert.Equal(t, diffZero+100, combined.Spectrogram[toneBin], "A louder than B is above zero")
+		assert.Equal(t, diffZero, combined.Spectrogram[toneBin+1], "bins silent in both are zero")
+	})
+}
+
+func TestSpectrogramCompositor_DropsUnsynchronizedFrames(t *testing.T) {
+	at := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	compositor := newSpectrogramCompositor("mic-a", "mic-b", spectrogramCompareLayoutStack)
+
+	compositor.add(compareTestFrame("mic-a", at, 0, 1))
+	_, ok := compositor.add(compareTestFrame("mic-b", at.Add(time.Second), 0, 2))
+	require.False(t, ok, "frames captured too far apart aren't combined")
+
+	combined, ok := compositor.add(compareTestFrame("mic-a", at.Add(time.Second), 0, 3))
+	require.True(t, ok, "the newer frame waited for a partner")
+	assert.Equal(t, byte(3), combined.Spectrogram[0])
+}
@@ -0,0 +1,100 @@
+// spectrogram_compression_test.go: Tests for gzip compression of the spectrogram stream
+
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// getEncodedSpectrogramStream opens an SSE request for url advertising acceptEncoding. The
+// header is set explicitly, so the transport leaves the body compressed.
+func getEncodedSpectrogramStream(t *testing.T, url, acceptEncoding string) *http.Response {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+// readSpectrogramFrame reads SSE events from r until the first spectrogram frame
+func readSpectrogramFrame(t *testing.T, r io.Reader) SSEUiSpectrogramData {
+	t.Helper()
+	scanner := bufio.NewScanner(r)
+	event := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			event = after
+			continue
+		}
+		after, ok := strings.CutPrefix(line, "data: ")
+		if !ok || event != conf.DefaultUiSpectrogramSSEEventName {
+			continue
+		}
+		var frame SSEUiSpectrogramData
+		require.NoError(t, json.Unmarshal([]byte(after), &frame))
+		return frame
+	}
+	require.Fail(t, "stream ended before the frame event", scanner.Err())
+	return SSEUiSpectrogramData{}
+}
+
+func TestStreamSpectrogram_GzipCompression(t *testing.T) {
+	server, controller := setupSSETestServerWithSettings(t, func(settings *conf.Settings) {
+		settings.SoundId.UiSpectrogram.SpectrogramSSECompress = true
+	})
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	resp := getEncodedSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream", "gzip, deflate")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	gz, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return controller.SpectrogramClientCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+	require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{1, 2, 3}}))
+
+	frame := readSpectrogramFrame(t, gz)
+	assert.Equal(t, "mic", frame.Source)
+	assert.Equal(t, []byte{1, 2, 3}, frame.Spectrogram)
+}
+
+func TestStreamSpectrogram_GzipOnlyWhenAccepted(t *testing.T) {
+	server, controller := setupSSETestServerWithSettings(t, func(settings *conf.Settings) {
+		settings.SoundId.UiSpectrogram.SpectrogramSSECompress = true
+	})
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	resp := getEncodedSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream", "gzip;q=0, identity")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+
+	require.Eventually(t, func() bool { return controller.SpectrogramClientCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+	require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{4}}))
+	assert.Equal(t, []byte{4}, readSpectrogramFrame(t, resp.Body).Spectrogram)
+}
@@ -0,0 +1,28 @@
+// internal/api/v2/spectrogram_config.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// SetSpectrogramConfigProvider connects the function reporting the UI spectrogram
+// configuration currently in effect.
+func (c *Controller) SetSpectrogramConfigProvider(provider func() myaudio.UiSpectrogramConfig) {
+	c.spectrogramConfig.Store(&provider)
+}
+
+// GetSpectrogramConfig handles GET /api/v2/spectrogram/config
+// It returns the spectrogram parameters the running session applied, which can differ from
+// the saved settings after they were changed without restarting the spectrogram
+func (c *Controller) GetSpectrogramConfig(ctx echo.Context) error {
+	provider := c.spectrogramConfig.Load()
+	if provider == nil || *provider == nil {
+		return c.HandleError(ctx, fmt.Errorf("spectrogram manager not connected"),
+			"Spectrogram pipeline not available", http.StatusServiceUnavailable)
+	}
+	return ctx.JSON(http.StatusOK, (*provider)())
+}
@@ -0,0 +1,28 @@
+// internal/api/v2/spectrogram_health.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// SetSpectrogramHealthProvider connects the function reporting the health of the UI
+// spectrogram feed.
+func (c *Controller) SetSpectrogramHealthProvider(provider func() myaudio.UiSpectrogramHealth) {
+	c.spectrogramHealth.Store(&provider)
+}
+
+// GetSpectrogramHealth handles GET /api/v2/spectrogram/health
+// It returns whether the spectrogram feed is healthy, degraded or stopped, with the recent
+// broadcast error rate and when the last frame arrived and the last broadcast failed
+func (c *Controller) GetSpectrogramHealth(ctx echo.Context) error {
+	provider := c.spectrogramHealth.Load()
+	if provider == nil || *provider == nil {
+		return c.HandleError(ctx, fmt.Errorf("spectrogram manager not connected"),
+			"Spectrogram pipeline not available", http.StatusServiceUnavailable)
+	}
+	return ctx.JSON(http.StatusOK, (*provider)())
+}
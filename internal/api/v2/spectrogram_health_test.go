@@ -0,0 +1,47 @@
+// spectrogram_health_test.go: Tests for the spectrogram feed health endpoint
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestGetSpectrogramHealth(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+	get := func() *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/api/v2/spectrogram/health", http.NoBody)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, get().StatusCode, "no spectrogram manager is connected")
+
+	lastError := time.Date(2026, 5, 15, 6, 30, 0, 0, time.UTC)
+	controller.SetSpectrogramHealthProvider(func() myaudio.UiSpectrogramHealth {
+		return myaudio.UiSpectrogramHealth{Status: myaudio.UiSpectrogramDegraded, ErrorRate: 0.75, LastError: lastError}
+	})
+
+	resp := get()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "degraded", body["status"])
+	assert.InDelta(t, 0.75, body["errorRate"], 1e-9)
+	assert.Equal(t, "2026-05-15T06:30:00Z", body["lastError"])
+	assert.NotContains(t, body, "lastFrame", "a frame that never arrived is left out")
+}
@@ -0,0 +1,61 @@
+// internal/api/v2/spectrogram_heartbeat.go
+package api
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// spectrogramHeartbeatEventType is the SSE event name the publisher sends on the spectrogram
+// stream while no frames flow
+const spectrogramHeartbeatEventType = "ui_spectrogram_heartbeat"
+
+// sseSpectrogramHeartbeatBufferSize holds one pending heartbeat; a newer one adds nothing
+const sseSpectrogramHeartbeatBufferSize = 1
+
+// SSESpectrogramHeartbeat tells spectrogram clients the publisher is alive while it has no
+// frames to send, for example during silence. Unlike the per-connection keepalive it comes
+// from the spectrogram pipeline, so it also keeps proxies from closing an idle stream.
+type SSESpectrogramHeartbeat struct {
+	Timestamp time.Time `json:"timestamp"`
+	EventType string    `json:"eventType"`
+}
+
+func (SSESpectrogramHeartbeat) sseEventName() string { return spectrogramHeartbeatEventType }
+
+// BroadcastSpectrogramHeartbeat sends a heartbeat event to all spectrogram stream clients
+func (c *Controller) BroadcastSpectrogramHeartbeat() error {
+	if c.sseManager == nil {
+		return spectrogramBroadcastError(errors.CategorySystem, "SSE manager not initialized")
+	}
+	if c.sseManager.closed.Load() {
+		return spectrogramBroadcasterClosedError()
+	}
+	c.sseManager.BroadcastSpectrogramHeartbeat(&SSESpectrogramHeartbeat{
+		Timestamp: time.Now(),
+		EventType: spectrogramHeartbeatEventType,
+	})
+	return nil
+}
+
+// BroadcastSpectrogramHeartbeat sends a heartbeat to all spectrogram stream clients. A client
+// with a heartbeat still pending gets nothing more, without counting against its health.
+func (m *SSEManager) BroadcastSpectrogramHeartbeat(heartbeat *SSESpectrogramHeartbeat) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for clientID, client := range m.clients {
+		if client.StreamType != streamTypeSpectrogram || client.HeartbeatChan == nil {
+			continue
+		}
+		select {
+		case client.HeartbeatChan <- *heartbeat:
+		default:
+			GetLogger().Debug("SSE spectrogram heartbeat skipped, one is still pending",
+				logger.String("client_id", clientID),
+			)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// spectrogram_heartbeat_test.go: Tests for publisher heartbeats on the spectrogram stream
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastSpectrogramHeartbeat_KeepsOnePending(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.sseManager = NewSSEManager()
+
+	client := &SSEClient{
+		ID:            "spectrogram-client",
+		StreamType:    streamTypeSpectrogram,
+		HeartbeatChan: make(chan SSESpectrogramHeartbeat, sseSpectrogramHeartbeatBufferSize),
+		Done:          make(chan struct{}, 1),
+	}
+	controller.sseManager.AddClient(client)
+	t.Cleanup(func() { controller.sseManager.RemoveClient(client.ID) })
+
+	require.NoError(t, controller.BroadcastSpectrogramHeartbeat())
+	require.NoError(t, controller.BroadcastSpectrogramHeartbeat())
+
+	require.Len(t, client.HeartbeatChan, 1, "a pending heartbeat isn't queued twice")
+	heartbeat := <-client.HeartbeatChan
+	assert.Equal(t, spectrogramHeartbeatEventType, heartbeat.sseEventName())
+	assert.False(t, heartbeat.Timestamp.IsZero())
+}
@@ -0,0 +1,66 @@
+// internal/api/v2/spectrogram_interpolation.go
+package api
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// maxSpectrogramInterpolation caps the intermediate columns a client can ask for between
+// frames, since each one adds a full column to every frame it receives
+const maxSpectrogramInterpolation = 8
+
+// spectrogramInterpolator blends intermediate columns between consecutive frames of a
+// source for one client, so clients receiving few frames per second can animate smoothly.
+// Only the client's event loop uses it, so it needs no locking.
+type spectrogramInterpolator struct {
+	columns int               // Intermediate columns inserted before each frame
+	last    map[string][]byte // Last real column of each source's previous frame
+}
+
+// parseSpectrogramInterpolation reads the interpolate query parameter, the number of
+// intermediate columns a client wants between frames. Empty means none.
+func parseSpectrogramInterpolation(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > maxSpectrogramInterpolation {
+		return 0, fmt.Errorf("interpolate must be between 0 and %d, got %q", maxSpectrogramInterpolation, value)
+	}
+	return n, nil
+}
+
+func newSpectrogramInterpolator(columns int) *spectrogramInterpolator {
+	return &spectrogramInterpolator{columns: columns, last: make(map[string][]byte)}
+}
+
+// apply returns the frame with the intermediate columns between the source's previous frame
+// and this one prepended, and InterpolatedColumns set to their count. The first frame of a
+// source, or one whose bin count changed, is returned as is. The frame's spectrogram is
+// shared with other clients, so it is copied rather than modified.
+func (s *spectrogramInterpolator) apply(frame SSEUiSpectrogramData) SSEUiSpectrogramData {
+	bins := frame.ColumnBins()
+	if s == nil || s.columns == 0 || len(frame.Spectrogram) < bins {
+		return frame
+	}
+
+	prev := s.last[frame.Source]
+	s.last[frame.Source] = frame.Spectrogram[len(frame.Spectrogram)-bins:]
+	if len(prev) != bins {
+		return frame
+	}
+
+	first := frame.Spectrogram[:bins]
+	out := make([]byte, 0, s.columns*bins+len(frame.Spectrogram))
+	for k := 1; k <= s.columns; k++ {
+		t := float64(k) / float64(s.columns+1)
+		for bin := range bins {
+			from, to := float64(prev[bin]), float64(first[bin])
+			out = append(out, byte(from+(to-from)*t+0.5))
+		}
+	}
+	frame.Spectrogram = append(out, frame.Spectrogram...)
+	frame.InterpolatedColumns = s.columns
+	return frame
+}
@@ -0,0 +1,90 @@
+// spectrogram_interpolation_test.go: Tests for per-client intermediate spectrogram columns
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestStreamSpectrogram_InterpolatesBetweenFrames(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v2/spectrogram/stream?interpolate=3", http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "Connected") {
+			break
+		}
+	}
+
+	frame := func(level byte) *myaudio.UiSpectrogramData {
+		column := make([]byte, myaudio.UiSpectrogramBins)
+		for i := range column {
+			column[i] = level
+		}
+		return &myaudio.UiSpectrogramData{Spectrogram: append(column, column...), Source: "mic", Timestamp: time.Now()}
+	}
+	next := func() SSEUiSpectrogramData {
+		var event string
+		for scanner.Scan() {
+			line := scanner.Text()
+			if after, ok := strings.CutPrefix(line, "event: "); ok {
+				event = after
+			}
+			if after, ok := strings.CutPrefix(line, "data: "); ok && event == "ui_spectrogram" {
+				var data SSEUiSpectrogramData
+				require.NoError(t, json.Unmarshal([]byte(after), &data))
+				return data
+			}
+		}
+		require.Fail(t, "stream ended before a spectrogram frame", scanner.Err())
+		return SSEUiSpectrogramData{}
+	}
+
+	require.NoError(t, controller.BroadcastSpectrogram(frame(0)))
+	first := next()
+	assert.Zero(t, first.InterpolatedColumns, "nothing to blend from before the first frame")
+	assert.Len(t, first.Spectrogram, 2*myaudio.UiSpectrogramBins)
+
+	require.NoError(t, controller.BroadcastSpectrogram(frame(200)))
+	second := next()
+	require.Equal(t, 3, second.InterpolatedColumns)
+	require.Len(t, second.Spectrogram, (3+2)*myaudio.UiSpectrogramBins, "intermediate columns precede the real ones")
+	for k, want := range []byte{50, 100, 150, 200, 200} {
+		assert.Equal(t, want, second.Spectrogram[k*myaudio.UiSpectrogramBins], "column %d", k)
+	}
+}
+
+func TestStreamSpectrogram_RejectsInvalidInterpolation(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/spectrogram/stream?interpolate=99", http.NoBody)
+	rec := httptest.NewRecorder()
+	_ = controller.StreamSpectrogram(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,115 @@
+// internal/api/v2/spectrogram_markers.go
+package api
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+const (
+	// spectrogramDetectionEventType is the SSE event name detection markers are sent under on
+	// the spectrogram stream
+	spectrogramDetectionEventType = "spectrogram_detection"
+	// spectrogramCombinedDetectionEventType is the SSE event name of markers that carry their
+	// detection inline, sent when combined markers are enabled
+	spectrogramCombinedDetectionEventType = "spectrogram_detection_combined"
+)
+
+// SSESpectrogramDetectionMarker marks the time region of a detection on the spectrogram
+// stream. Label and the species names are only set for confident detections, so the
+// client can label the region directly without cluttering it with uncertain guesses.
+type SSESpectrogramDetectionMarker struct {
+	NoteID         uint      `json:"noteId"`
+	Source         string    `json:"source,omitempty"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Confidence     float64   `json:"confidence"`
+	Label          string    `json:"label,omitempty"` // Species name formatted per the name display setting
+	CommonName     string    `json:"commonName,omitempty"`
+	ScientificName string    `json:"scientificName,omitempty"`
+	EventType      string    `json:"eventType"`
+
+	// Detection is only set on combined markers, so clients get the detection and its
+	// region in one event instead of matching a marker to a separate detection event
+	Detection *SSEDetectionSummary `json:"detection,omitempty"`
+}
+
+// SSEDetectionSummary is the detection metadata carried inline by combined markers. It
+// leaves out the bird image and the rest of the note to keep spectrogram events small.
+type SSEDetectionSummary struct {
+	CommonName         string  `json:"commonName"`
+	ScientificName     string  `json:"scientificName"`
+	SpeciesCode        string  `json:"speciesCode,omitempty"`
+	Confidence         float64 `json:"confidence"`
+	Date               string  `json:"date"`
+	Time               string  `json:"time"`
+	IsNewSpecies       bool    `json:"isNewSpecies,omitempty"`
+	DaysSinceFirstSeen int     `json:"daysSinceFirstSeen,omitempty"`
+}
+
+// sseEventName sends detection markers under their own event type on the spectrogram stream.
+func (m SSESpectrogramDetectionMarker) sseEventName() string {
+	if m.Detection != nil {
+		return spectrogramCombinedDetectionEventType
+	}
+	return spectrogramDetectionEventType
+}
+
+// formatSpeciesLabel formats a species name according to the name display preference.
+// Unknown preferences fall back to the common name, and a missing name falls back to the other.
+func formatSpeciesLabel(commonName, scientificName, display string) string {
+	switch {
+	case commonName == "":
+		return scientificName
+	case scientificName == "":
+		return commonName
+	}
+
+	switch display {
+	case conf.NameDisplayScientific:
+		return scientificName
+	case conf.NameDisplayBoth:
+		return commonName + " (" + scientificName + ")"
+	default:
+		return commonName
+	}
+}
+
+// newSpectrogramDetectionMarker builds the spectrogram marker for a detection.
+func newSpectrogramDetectionMarker(note *datastore.Note, settings *conf.Settings) SSESpectrogramDetectionMarker {
+	marker := SSESpectrogramDetectionMarker{
+		NoteID:     note.ID,
+		Source:     note.Source.ID,
+		Start:      note.BeginTime,
+		End:        note.EndTime,
+		Confidence: note.Confidence,
+		EventType:  spectrogramDetectionEventType,
+	}
+
+	if settings != nil && note.Confidence >= settings.SoundId.UiSpectrogram.MarkerLabelThreshold {
+		marker.CommonName = note.CommonName
+		marker.ScientificName = note.ScientificName
+		marker.Label = formatSpeciesLabel(note.CommonName, note.ScientificName, settings.Realtime.Dashboard.NameDisplay)
+	}
+	return marker
+}
+
+// newCombinedSpectrogramDetectionMarker builds a marker carrying the detection's metadata,
+// sent in place of the plain marker when combined markers are enabled.
+func newCombinedSpectrogramDetectionMarker(detection *SSEDetectionData, settings *conf.Settings) SSESpectrogramDetectionMarker {
+	marker := newSpectrogramDetectionMarker(&detection.Note, settings)
+	marker.EventType = spectrogramCombinedDetectionEventType
+	marker.Detection = &SSEDetectionSummary{
+		CommonName:         detection.CommonName,
+		ScientificName:     detection.ScientificName,
+		SpeciesCode:        detection.SpeciesCode,
+		Confidence:         detection.Confidence,
+		Date:               detection.Date,
+		Time:               detection.Time,
+		IsNewSpecies:       detection.IsNewSpecies,
+		DaysSinceFirstSeen: detection.DaysSinceFirstSeen,
+	}
+	return marker
+}
@@ -0,0 +1,128 @@
+// spectrogram_markers_test.go: Tests for detection markers on the spectrogram stream
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+)
+
+func TestBroadcastDetection_MarkerLabelFollowsNameDisplay(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.sseManager = NewSSEManager()
+
+	client := &SSEClient{
+		ID:         "spectrogram-client",
+		StreamType: streamTypeSpectrogram,
+		MarkerChan: make(chan SSESpectrogramDetectionMarker, 1),
+		Done:       make(chan struct{}, 1),
+	}
+	controller.sseManager.AddClient(client)
+	t.Cleanup(func() { controller.sseManager.RemoveClient(client.ID) })
+
+	begin := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	note := &datastore.Note{
+		ID:             7,
+		Source:         datastore.AudioSource{ID: "mic"},
+		BeginTime:      begin,
+		EndTime:        begin.Add(3 * time.Second),
+		CommonName:     "Eurasian Blackbird",
+		ScientificName: "Turdus merula",
+	}
+
+	tests := []struct {
+		name       string
+		display    string
+		confidence float64
+		wantLabel  string
+	}{
+		{"common name", conf.NameDisplayCommon, 0.9, "Eurasian Blackbird"},
+		{"scientific name", conf.NameDisplayScientific, 0.9, "Turdus merula"},
+		{"both names", conf.NameDisplayBoth, 0.9, "Eurasian Blackbird (Turdus merula)"},
+		{"unset preference", "", 0.9, "Eurasian Blackbird"},
+		{"below label threshold", conf.NameDisplayBoth, 0.5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller.Settings.Realtime.Dashboard.NameDisplay = tt.display
+			controller.Settings.SoundId.UiSpectrogram.MarkerLabelThreshold = 0.8
+			note.Confidence = tt.confidence
+
+			require.NoError(t, controller.BroadcastDetection(note, &imageprovider.BirdImage{}))
+
+			select {
+			case marker := <-client.MarkerChan:
+				assert.Equal(t, tt.wantLabel, marker.Label)
+				assert.Equal(t, uint(7), marker.NoteID)
+				assert.Equal(t, "mic", marker.Source)
+				assert.True(t, marker.Start.Equal(begin))
+				assert.Equal(t, spectrogramDetectionEventType, marker.EventType)
+				if tt.wantLabel == "" {
+					assert.Empty(t, marker.ScientificName, "uncertain detections carry no species")
+				} else {
+					assert.Equal(t, "Turdus merula", marker.ScientificName)
+				}
+			default:
+				t.Fatal("expected a detection marker on the spectrogram stream")
+			}
+		})
+	}
+}
+
+func TestFormatSpeciesLabel_MissingName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Turdus merula", formatSpeciesLabel("", "Turdus merula", conf.NameDisplayBoth))
+	assert.Equal(t, "Eurasian Blackbird", formatSpeciesLabel("Eurasian Blackbird", "", conf.NameDisplayScientific))
+}
+
+func TestBroadcastDetection_CombinedMarkerCarriesDetection(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.sseManager = NewSSEManager()
+	controller.Settings.SoundId.UiSpectrogram.CombinedMarkers = true
+	controller.Settings.SoundId.UiSpectrogram.MarkerLabelThreshold = 0.8
+
+	client := &SSEClient{
+		ID:         "combined-client",
+		StreamType: streamTypeSpectrogram,
+		MarkerChan: make(chan SSESpectrogramDetectionMarker, 2),
+		Done:       make(chan struct{}, 1),
+	}
+	controller.sseManager.AddClient(client)
+	t.Cleanup(func() { controller.sseManager.RemoveClient(client.ID) })
+
+	begin := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	note := &datastore.Note{
+		ID:             9,
+		Source:         datastore.AudioSource{ID: "mic"},
+		Date:           "2026-05-01",
+		Time:           "06:00:00",
+		BeginTime:      begin,
+		EndTime:        begin.Add(3 * time.Second),
+		CommonName:     "Eurasian Blackbird",
+		ScientificName: "Turdus merula",
+		Confidence:     0.5, // Below the label threshold, the detection still carries the species
+	}
+	require.NoError(t, controller.BroadcastDetection(note, &imageprovider.BirdImage{}))
+
+	require.Len(t, client.MarkerChan, 1, "a detection must produce a single combined event")
+	marker := <-client.MarkerChan
+	assert.Equal(t, spectrogramCombinedDetectionEventType, marker.sseEventName())
+	assert.Equal(t, spectrogramCombinedDetectionEventType, marker.EventType)
+	assert.Equal(t, uint(9), marker.NoteID)
+	assert.True(t, marker.Start.Equal(begin))
+	assert.True(t, marker.End.Equal(begin.Add(3*time.Second)))
+	assert.Empty(t, marker.Label)
+
+	require.NotNil(t, marker.Detection)
+	assert.Equal(t, "Turdus merula", marker.Detection.ScientificName)
+	assert.Equal(t, "Eurasian Blackbird", marker.Detection.CommonName)
+	assert.InDelta(t, 0.5, marker.Detection.Confidence, 1e-9)
+	assert.Equal(t, "06:00:00", marker.Detection.Time)
+}
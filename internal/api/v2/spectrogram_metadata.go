@@ -0,0 +1,37 @@
+// internal/api/v2/spectrogram_metadata.go
+package api
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// spectrogramMetadataEventType is the SSE event name of the metadata sent once when a
+// spectrogram stream client connects, before any frames
+const spectrogramMetadataEventType = "ui_spectrogram_metadata"
+
+// SSESpectrogramMetadata describes the frames a spectrogram stream will carry. Clients
+// compare SchemaVersion against the versions they support and degrade gracefully, for
+// example by rendering only the columns, when it is newer than they know.
+type SSESpectrogramMetadata struct {
+	SchemaVersion       int       `json:"schemaVersion"`
+	Bins                int       `json:"bins"`                // Default bins per column; frames with their own bins field override it
+	InterpolatedColumns int       `json:"interpolatedColumns"` // Intermediate columns the client asked for with ?interpolate
+	Encoding            string    `json:"encoding"`            // "json" for ui_spectrogram events, "binary" for ui_spectrogram_binary events
+	Source              string    `json:"source,omitempty"`    // Source the client asked for with ?source, empty when it gets every source
+	Timestamp           time.Time `json:"timestamp"`
+}
+
+// newSpectrogramMetadata builds the metadata event for a stream opened with params,
+// sending frames through encoder.
+func newSpectrogramMetadata(params spectrogramStreamParams, encoder spectrogramFrameEncoder) SSESpectrogramMetadata {
+	return SSESpectrogramMetadata{
+		SchemaVersion:       myaudio.UiSpectrogramSchemaVersion,
+		Bins:                myaudio.UiSpectrogramBins,
+		InterpolatedColumns: params.interpolate,
+		Encoding:            encoder.encoding(),
+		Source:              params.source,
+		Timestamp:           time.Now(),
+	}
+}
@@ -0,0 +1,51 @@
+// spectrogram_metadata_test.go: Tests for the metadata event opening a spectrogram stream
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestStreamSpectrogram_SendsSchemaVersionFirst(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	resp := getSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream?interpolate=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	scanner := bufio.NewScanner(resp.Body)
+	var events []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, after)
+		}
+		after, ok := strings.CutPrefix(line, "data: ")
+		if !ok || events[len(events)-1] != spectrogramMetadataEventType {
+			continue
+		}
+
+		var metadata SSESpectrogramMetadata
+		require.NoError(t, json.Unmarshal([]byte(after), &metadata))
+		assert.Equal(t, myaudio.UiSpectrogramSchemaVersion, metadata.SchemaVersion)
+		assert.Equal(t, myaudio.UiSpectrogramBins, metadata.Bins)
+		assert.Equal(t, 2, metadata.InterpolatedColumns)
+		assert.Equal(t, conf.UiSpectrogramEncodingJSON, metadata.Encoding)
+		assert.Equal(t, []string{SSEStatusConnected, spectrogramMetadataEventType}, events,
+			"the metadata directly follows the connection message")
+		return
+	}
+	require.Fail(t, "stream ended before the metadata event", scanner.Err())
+}
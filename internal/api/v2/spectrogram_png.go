@@ -0,0 +1,183 @@
+// internal/api/v2/spectrogram_png.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// maxSpectrogramImageColumns caps the width of rendered spectrogram images; older
+	// columns are cut off on the left
+	maxSpectrogramImageColumns = 512
+	// defaultSpectrogramStreamFPS is the PNG stream frame rate when the client doesn't ask for one
+	defaultSpectrogramStreamFPS = 2
+	// maxSpectrogramStreamFPS caps the PNG stream frame rate, since each part is a full image
+	maxSpectrogramStreamFPS = 10
+)
+
+// errNoSpectrogramFrames is returned when there are no frames to render for the source.
+var errNoSpectrogramFrames = errors.New("no spectrogram frames available")
+
+// renderSpectrogramPNG renders the recent frames of a source as a PNG, oldest column on
+// the left and the lowest frequency at the bottom. An empty source selects the source of
+// the newest frame, and an empty paletteName the palette of the newest frame. Frames with a
+// different bin count than the newest are skipped, so a change in bin aggregation doesn't
+// distort the image.
+func renderSpectrogramPNG(frames []myaudio.UiSpectrogramData, source, paletteName string) ([]byte, error) {
+	if source == "" && len(frames) > 0 {
+		source = frames[len(frames)-1].Source
+	}
+
+	var columns [][]byte
+	bins, framePalette := 0, ""
+	for i := len(frames) - 1; i >= 0 && len(columns) < maxSpectrogramImageColumns; i-- {
+		frame := &frames[i]
+		if frame.Source != source {
+			continue
+		}
+		if bins == 0 {
+			bins, framePalette = frame.ColumnBins(), frame.Palette
+		}
+		if frame.ColumnBins() != bins {
+			continue
+		}
+		// Walk the frame's columns newest first; they are reversed below
+		for end := len(frame.Spectrogram); end >= bins && len(columns) < maxSpectrogramImageColumns; end -= bins {
+			columns = append(columns, frame.Spectrogram[end-bins:end])
+		}
+	}
+	if len(columns) == 0 {
+		return nil, errNoSpectrogramFrames
+	}
+
+	if paletteName == "" {
+		paletteName = framePalette
+	}
+	palette, ok := myaudio.LookupUiSpectrogramPalette(paletteName)
+	if !ok {
+		palette, _ = myaudio.LookupUiSpectrogramPalette(conf.DefaultUiSpectrogramPalette)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, len(columns), bins))
+	for i, column := range columns {
+		x := len(columns) - 1 - i
+		for bin, v := range column {
+			r, g, b := palette.Color(v)
+			img.SetRGBA(x, bins-1-bin, color.RGBA{R: r, G: g, B: b, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StreamSpectrogramPNG handles GET /api/v2/spectrogram/live
+// It streams the live spectrogram as a multipart/x-mixed-replace sequence of PNG images,
+// which an <img> element displays as a live view without any JavaScript. A new image is
+// sent only when new frames arrived, at most fps times per second.
+// Query parameters: source (defaults to the most recent source), fps (1-10, default 2).
+func (c *Controller) StreamSpectrogramPNG(ctx echo.Context) error {
+	params, paramErr := c.parseSpectrogramStreamParams(ctx)
+	if paramErr != nil {
+		return c.rejectStreamParam(ctx, paramErr)
+	}
+	fps, source := params.fps, params.source
+
+	streamCtx, cancel := context.WithTimeout(ctx.Request().Context(), maxSSEStreamDuration)
+	defer cancel()
+	var shutdown <-chan struct{}
+	if c.ctx != nil {
+		shutdown = c.ctx.Done()
+	}
+
+	resp := ctx.Response()
+	mw := multipart.NewWriter(resp)
+	resp.Header().Set(echo.HeaderContentType, "multipart/x-mixed-replace; boundary="+mw.Boundary())
+	resp.Header().Set("Cache-Control", "no-cache, no-store")
+	resp.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering so images arrive as they are sent
+	resp.WriteHeader(http.StatusOK)
+	resp.Flush()
+
+	c.spectrogramPNGViewers.Add(1)
+	defer c.spectrogramPNGViewers.Add(-1)
+
+	c.logInfoIfEnabled("Spectrogram PNG stream started",
+		logger.String("source", source),
+		logger.Int("fps", fps),
+		logger.String("ip", ctx.RealIP()),
+		logger.String("path", ctx.Request().URL.Path))
+
+	ticker := time.NewTicker(time.Second / time.Duration(fps))
+	defer ticker.Stop()
+
+	var sent uint64
+	for {
+		if frames, added := c.spectrogramHistory.snapshot(); added != sent {
+			if encoded, err := renderSpectrogramPNG(frames, source, ""); err == nil {
+				part, err := mw.CreatePart(textproto.MIMEHeader{
+					echo.HeaderContentType:   {"image/png"},
+					echo.HeaderContentLength: {strconv.Itoa(len(encoded))},
+				})
+				if err == nil {
+					_, err = part.Write(encoded)
+				}
+				if err != nil {
+					return nil // Client went away
+				}
+				resp.Flush()
+				sent = added
+			}
+		}
+
+		select {
+		case <-streamCtx.Done():
+			return nil
+		case <-shutdown:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetSpectrogramSnapshot handles GET /api/v2/spectrogram/snapshot.png
+// It renders the latest broadcast frames as one PNG image, up to maxSpectrogramImageColumns
+// columns wide. Responds 503 until a frame of the source was broadcast.
+// Query parameters: source (defaults to the most recent source), palette (defaults to the
+// palette the frames were sent with).
+func (c *Controller) GetSpectrogramSnapshot(ctx echo.Context) error {
+	source, palette := ctx.QueryParam("source"), ctx.QueryParam("palette")
+	if palette != "" {
+		if _, err := myaudio.ParseUiSpectrogramPalette(palette); err != nil {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	encoded, err := renderSpectrogramPNG(c.spectrogramHistory.list(), source, palette)
+	if errors.Is(err, errNoSpectrogramFrames) {
+		return c.HandleError(ctx, err, "No spectrogram frame available yet", http.StatusServiceUnavailable)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to render spectrogram snapshot", http.StatusInternalServerError)
+	}
+
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	return ctx.Blob(http.StatusOK, "image/png", encoded)
+}
@@ -0,0 +1,113 @@
+// spectrogram_png_test.go: Tests for the live spectrogram multipart PNG stream
+
+package api
+
+import (
+	"context"
+	"image/png"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestStreamSpectrogramPNG_SendsDecodablePNGParts(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	broadcast := func(level byte) {
+		column := make([]byte, myaudio.UiSpectrogramBins)
+		for i := range column {
+			column[i] = level
+		}
+		require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{
+			Spectrogram: append(column, column...),
+			Source:      "mic",
+			Timestamp:   time.Now(),
+		}))
+	}
+	broadcast(10)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v2/spectrogram/live?fps=10", http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/x-mixed-replace", mediaType)
+	reader := multipart.NewReader(resp.Body, params["boundary"])
+
+	for wantColumns := 2; wantColumns <= 4; wantColumns += 2 {
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", part.Header.Get("Content-Type"))
+
+		img, err := png.Decode(part)
+		require.NoError(t, err, "each part must be a complete PNG")
+		assert.Equal(t, wantColumns, img.Bounds().Dx(), "one pixel column per spectrogram column")
+		assert.Equal(t, myaudio.UiSpectrogramBins, img.Bounds().Dy(), "one pixel row per bin")
+
+		broadcast(200)
+	}
+}
+
+func TestRenderSpectrogramPNG_NoFramesForSource(t *testing.T) {
+	t.Parallel()
+
+	frames := []myaudio.UiSpectrogramData{{Spectrogram: make([]byte, myaudio.UiSpectrogramBins), Source: "mic"}}
+	_, err := renderSpectrogramPNG(frames, "other", "")
+	require.ErrorIs(t, err, errNoSpectrogramFrames)
+
+	_, err = renderSpectrogramPNG(nil, "", "")
+	require.ErrorIs(t, err, errNoSpectrogramFrames)
+}
+
+func TestGetSpectrogramSnapshot(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+	get := func(query string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/api/v2/spectrogram/snapshot.png"+query, http.NoBody)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("").StatusCode, "no frame was broadcast yet")
+
+	const columns = 3
+	require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{
+		Spectrogram: make([]byte, columns*myaudio.UiSpectrogramBins),
+		Source:      "mic",
+		Timestamp:   time.Now(),
+	}))
+
+	resp := get("?palette=viridis")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	img, err := png.Decode(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, columns, img.Bounds().Dx())
+	assert.Equal(t, myaudio.UiSpectrogramBins, img.Bounds().Dy())
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("?source=other").StatusCode)
+	assert.Equal(t, http.StatusBadRequest, get("?palette=rainbow").StatusCode)
+}
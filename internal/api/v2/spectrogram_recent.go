@@ -0,0 +1,43 @@
+// internal/api/v2/spectrogram_recent.go
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// SetSpectrogramRecentFramesProvider connects the function returning the most recent UI
+// spectrogram frames, which are sent to each newly connected spectrogram stream client.
+func (c *Controller) SetSpectrogramRecentFramesProvider(provider func() []myaudio.UiSpectrogramData) {
+	c.spectrogramRecentFrames.Store(&provider)
+}
+
+// sendRecentSpectrogramFrames sends the most recent frames to a client that just connected,
+// oldest first and flagged as recent, so its view fills in before the next live frame.
+// They pass through the client's source filter, interpolator and encoder like live frames,
+// and are sent under eventName unless encoded as their own event.
+func (c *Controller) sendRecentSpectrogramFrames(ctx echo.Context, params spectrogramStreamParams, interpolator *spectrogramInterpolator, encoder spectrogramFrameEncoder, eventName string) error {
+	provider := c.spectrogramRecentFrames.Load()
+	if provider == nil || *provider == nil {
+		return nil
+	}
+
+	for _, frame := range (*provider)() {
+		if !params.includesSource(frame.Source) {
+			continue
+		}
+		data := encoder.frame(interpolator.apply(SSEUiSpectrogramData{
+			UiSpectrogramData: frame,
+			EventType:         eventName,
+			Recent:            true,
+		}))
+		event := eventName
+		if named, ok := data.(sseNamedEvent); ok {
+			event = named.sseEventName()
+		}
+		if err := c.sendSSEMessage(ctx, event, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// spectrogram_recent_test.go: Tests for sending recent frames to newly connected spectrogram clients
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestStreamSpectrogram_SendsRecentFramesOnConnect(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+	controller.SetSpectrogramRecentFramesProvider(func() []myaudio.UiSpectrogramData {
+		return []myaudio.UiSpectrogramData{
+			{Source: "mic", Spectrogram: []byte{1}},
+			{Source: "mic", Spectrogram: []byte{2}},
+		}
+	})
+
+	resp := getSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	scanner := bufio.NewScanner(resp.Body)
+	event := ""
+	var frames []SSEUiSpectrogramData
+	for len(frames) < 2 && scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			event = after
+			continue
+		}
+		after, ok := strings.CutPrefix(line, "data: ")
+		if !ok || event != "ui_spectrogram" {
+			continue
+		}
+		var frame SSEUiSpectrogramData
+		require.NoError(t, json.Unmarshal([]byte(after), &frame))
+		frames = append(frames, frame)
+	}
+
+	require.Len(t, frames, 2, "the recent frames arrive without any live frame")
+	assert.Equal(t, []byte{1}, frames[0].Spectrogram, "oldest first")
+	assert.Equal(t, []byte{2}, frames[1].Spectrogram)
+	assert.True(t, frames[0].Recent && frames[1].Recent, "recent frames are flagged")
+}
@@ -0,0 +1,118 @@
+// internal/api/v2/spectrogram_replay.go
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// Spectrogram replay limits
+const (
+	defaultReplaySpeed = 1.0  // Real-time playback
+	maxReplaySpeed     = 16.0 // Upper bound to keep the SSE stream consumable
+)
+
+// SpectrogramReplayRequest is the request body for POST /api/v2/spectrogram/replay
+type SpectrogramReplayRequest struct {
+	// Path is the clip path relative to the audio export directory
+	Path string `json:"path"`
+	// Speed is the playback rate relative to real time (defaults to 1.0)
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// SpectrogramReplayResponse is returned when a replay has been started
+type SpectrogramReplayResponse struct {
+	Path     string  `json:"path"`
+	Speed    float64 `json:"speed"`
+	SourceID string  `json:"source_id"`
+}
+
+// SetSpectrogramChan connects the UI spectrogram channel used for WAV replays.
+func (c *Controller) SetSpectrogramChan(ch chan myaudio.UiSpectrogramData) {
+	c.spectrogramChan = ch
+}
+
+// ReplaySpectrogram handles POST /api/v2/spectrogram/replay
+// It replays a saved WAV clip through the UI spectrogram pipeline in the background
+// so developers can reproduce exactly what a user saw. Frames are tagged with
+// myaudio.ReplaySourceID and never touch live source buffers. Only one replay may
+// run at a time.
+func (c *Controller) ReplaySpectrogram(ctx echo.Context) error {
+	if c.spectrogramChan == nil {
+		return c.HandleError(ctx, fmt.Errorf("spectrogram channel not initialized"),
+			"Spectrogram pipeline not available", http.StatusServiceUnavailable)
+	}
+
+	var req SpectrogramReplayRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request body", http.StatusBadRequest)
+	}
+	if req.Path == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing path"), "Path is required", http.StatusBadRequest)
+	}
+	if req.Speed == 0 {
+		req.Speed = defaultReplaySpeed
+	}
+	if req.Speed < 0 || req.Speed > maxReplaySpeed {
+		return c.HandleError(ctx, fmt.Errorf("invalid speed %v", req.Speed),
+			fmt.Sprintf("Speed must be between 0 and %v", maxReplaySpeed), http.StatusBadRequest)
+	}
+
+	relPath, err := c.normalizeAndValidatePathWithLogger(req.Path, c.apiLogger)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid file path", http.StatusBadRequest)
+	}
+
+	file, err := c.SFS.Open(filepath.Join(c.SFS.BaseDir(), relPath))
+	if err != nil {
+		return c.HandleError(ctx, err, "Audio file not found", http.StatusNotFound)
+	}
+
+	if !c.spectrogramReplayActive.CompareAndSwap(false, true) {
+		_ = file.Close()
+		return c.HandleError(ctx, fmt.Errorf("replay already running"),
+			"A spectrogram replay is already running", http.StatusConflict)
+	}
+
+	c.logInfoIfEnabled("Starting spectrogram replay",
+		logger.String("file", relPath),
+		logger.Float64("speed", req.Speed),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	speed := req.Speed
+	c.wg.Go(func() {
+		defer c.spectrogramReplayActive.Store(false)
+		defer func() { _ = file.Close() }()
+
+		err := myaudio.ReplayWAV(c.ctx, file, myaudio.ReplayOptions{Speed: speed}, func(data myaudio.UnifiedAudioData) error {
+			select {
+			case c.spectrogramChan <- data.SpectrogramData:
+				return nil
+			case <-c.ctx.Done():
+				return c.ctx.Err()
+			}
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			c.logErrorIfEnabled("Spectrogram replay failed",
+				logger.String("file", relPath),
+				logger.Error(err),
+			)
+			return
+		}
+		c.logInfoIfEnabled("Spectrogram replay finished", logger.String("file", relPath))
+	})
+
+	return ctx.JSON(http.StatusAccepted, SpectrogramReplayResponse{
+		Path:     relPath,
+		Speed:    speed,
+		SourceID: myaudio.ReplaySourceID,
+	})
+}
@@ -0,0 +1,86 @@
+// spectrogram_replay_test.go: Tests for the spectrogram WAV replay endpoint
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// replayRequest invokes ReplaySpectrogram with the given JSON body.
+func replayRequest(t *testing.T, controller *Controller, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/spectrogram/replay", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	ctx := controller.Echo.NewContext(req, rec)
+
+	require.NoError(t, controller.ReplaySpectrogram(ctx))
+	return rec
+}
+
+func TestReplaySpectrogram_StreamsFramesWithReplaySource(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+
+	// 4 frames worth of 16-bit silence
+	clip := filepath.Join(controller.Settings.Realtime.Audio.Export.Path, "clip.wav")
+	require.NoError(t, myaudio.SavePCMDataToWAV(clip, make([]byte, 4*1024*2)))
+
+	ch := make(chan myaudio.UiSpectrogramData, 10)
+	controller.SetSpectrogramChan(ch)
+
+	rec := replayRequest(t, controller, `{"path":"clip.wav","speed":16}`)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var resp SpectrogramReplayResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, myaudio.ReplaySourceID, resp.SourceID)
+	assert.InDelta(t, 16.0, resp.Speed, 0)
+
+	for i := range 4 {
+		select {
+		case frame := <-ch:
+			assert.Equal(t, myaudio.ReplaySourceID, frame.Source, "frame %d", i)
+		case <-time.After(2 * time.Second):
+			require.Fail(t, "timed out waiting for replayed frame", "frame %d", i)
+		}
+	}
+}
+
+func TestReplaySpectrogram_Validation(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+
+	// Without a channel the pipeline is unavailable
+	rec := replayRequest(t, controller, `{"path":"clip.wav"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	controller.SetSpectrogramChan(make(chan myaudio.UiSpectrogramData, 1))
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"missing path", `{}`, http.StatusBadRequest},
+		{"negative speed", `{"path":"clip.wav","speed":-1}`, http.StatusBadRequest},
+		{"speed too high", `{"path":"clip.wav","speed":100}`, http.StatusBadRequest},
+		{"path traversal", `{"path":"../../etc/passwd"}`, http.StatusBadRequest},
+		{"missing file", `{"path":"missing.wav"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := replayRequest(t, controller, tt.body)
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// internal/api/v2/spectrogram_source_status.go
+package api
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// spectrogramSourceStalledEventType is the SSE event name sent on the spectrogram stream
+	// when a source stops producing audio
+	spectrogramSourceStalledEventType = "spectrogram_source_stalled"
+	// spectrogramSourceResumedEventType is the SSE event name sent when a stalled source's
+	// audio returns
+	spectrogramSourceResumedEventType = "spectrogram_source_resumed"
+)
+
+// SSESpectrogramSourceStatus tells spectrogram clients that a source stalled or resumed, so a
+// gap in its frames can be shown as a dropout rather than silence.
+type SSESpectrogramSourceStatus struct {
+	Source       string    `json:"source"`
+	Status       string    `json:"status"` // "stalled" or "resumed"
+	LastSample   time.Time `json:"lastSample"`
+	StalledForMs int64     `json:"stalledForMs,omitempty"` // Length of the stall, set on resumed events
+	Timestamp    time.Time `json:"timestamp"`
+	EventType    string    `json:"eventType"`
+}
+
+// sseEventName sends stalls and resumes under their own event types on the spectrogram stream.
+func (s SSESpectrogramSourceStatus) sseEventName() string {
+	return s.EventType
+}
+
+// BroadcastSpectrogramSourceStatus sends a source stall or resume event to spectrogram stream
+// clients. It is registered as the myaudio source status handler.
+func (c *Controller) BroadcastSpectrogramSourceStatus(status myaudio.UiSpectrogramSourceStatus) {
+	if c.sseManager == nil {
+		return
+	}
+
+	eventType := spectrogramSourceStalledEventType
+	if status.Status == myaudio.UiSpectrogramSourceResumed {
+		eventType = spectrogramSourceResumedEventType
+	}
+
+	c.logInfoIfEnabled("Spectrogram source "+status.Status,
+		logger.String("source", status.Source),
+		logger.Time("last_sample", status.LastSample))
+
+	c.sseManager.BroadcastSpectrogramSourceStatus(&SSESpectrogramSourceStatus{
+		Source:       status.Source,
+		Status:       status.Status,
+		LastSample:   status.LastSample,
+		StalledForMs: status.StalledFor.Milliseconds(),
+		Timestamp:    status.Timestamp,
+		EventType:    eventType,
+	})
+}
@@ -0,0 +1,45 @@
+// spectrogram_source_status_test.go: Tests for source stall events on the spectrogram stream
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestBroadcastSpectrogramSourceStatus_StalledAndResumedEvents(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.sseManager = NewSSEManager()
+
+	client := &SSEClient{
+		ID:         "spectrogram-client",
+		StreamType: streamTypeSpectrogram,
+		StatusChan: make(chan SSESpectrogramSourceStatus, 2),
+		Done:       make(chan struct{}, 1),
+	}
+	controller.sseManager.AddClient(client)
+	t.Cleanup(func() { controller.sseManager.RemoveClient(client.ID) })
+
+	lastSample := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	controller.BroadcastSpectrogramSourceStatus(myaudio.UiSpectrogramSourceStatus{
+		Source: "mic", Status: myaudio.UiSpectrogramSourceStalled, LastSample: lastSample, Timestamp: lastSample.Add(5 * time.Second),
+	})
+	controller.BroadcastSpectrogramSourceStatus(myaudio.UiSpectrogramSourceStatus{
+		Source: "mic", Status: myaudio.UiSpectrogramSourceResumed, LastSample: lastSample,
+		StalledFor: 8 * time.Second, Timestamp: lastSample.Add(8 * time.Second),
+	})
+
+	require.Len(t, client.StatusChan, 2)
+	stalled := <-client.StatusChan
+	assert.Equal(t, spectrogramSourceStalledEventType, stalled.sseEventName())
+	assert.Equal(t, "mic", stalled.Source)
+	assert.True(t, stalled.LastSample.Equal(lastSample))
+
+	resumed := <-client.StatusChan
+	assert.Equal(t, spectrogramSourceResumedEventType, resumed.sseEventName())
+	assert.Equal(t, int64(8000), resumed.StalledForMs)
+}
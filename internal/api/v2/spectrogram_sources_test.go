@@ -0,0 +1,80 @@
+// spectrogram_sources_test.go: Tests for streaming the spectrograms of several audio sources
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// readSpectrogramSources reads the stream until n frames arrived, single or batched, and
+// returns the source of each in order.
+func readSpectrogramSources(t *testing.T, scanner *bufio.Scanner, n int) []string {
+	t.Helper()
+	event := ""
+	var sources []string
+	for len(sources) < n && scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			event = after
+			continue
+		}
+		after, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		switch event {
+		case "ui_spectrogram":
+			var frame SSEUiSpectrogramData
+			require.NoError(t, json.Unmarshal([]byte(after), &frame))
+			sources = append(sources, frame.Source)
+		case spectrogramBatchEventType:
+			var batch SSEUiSpectrogramBatch
+			require.NoError(t, json.Unmarshal([]byte(after), &batch))
+			for i := range batch.Frames {
+				sources = append(sources, batch.Frames[i].Source)
+			}
+		}
+	}
+	require.Len(t, sources, n, "stream ended early: %v", scanner.Err())
+	return sources
+}
+
+func TestStreamSpectrogram_MultipleSources(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	all := getSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream")
+	require.Equal(t, http.StatusOK, all.StatusCode)
+	yard := getSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream?source=yard")
+	require.Equal(t, http.StatusOK, yard.StatusCode)
+	require.Eventually(t, func() bool { return controller.SpectrogramClientCount() == 2 }, 2*time.Second, 5*time.Millisecond)
+	allEvents, yardEvents := bufio.NewScanner(all.Body), bufio.NewScanner(yard.Body)
+
+	for _, source := range []string{"mic", "yard", "mic", "yard"} {
+		require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{Source: source, Spectrogram: []byte{1}}))
+	}
+	assert.Equal(t, []string{"mic", "yard", "mic", "yard"}, readSpectrogramSources(t, allEvents, 4),
+		"a stream without a source gets every source, each frame naming its own")
+	assert.Equal(t, []string{"yard", "yard"}, readSpectrogramSources(t, yardEvents, 2),
+		"a stream for one source gets only its frames")
+
+	// Batches travel on their own channel, so they are sent once the single frames were read
+	require.NoError(t, controller.BroadcastSpectrogramBatch([]*myaudio.UiSpectrogramData{
+		{Source: "mic", Spectrogram: []byte{2}},
+		{Source: "yard", Spectrogram: []byte{2}},
+	}))
+	assert.Equal(t, []string{"mic", "yard"}, readSpectrogramSources(t, allEvents, 2))
+	assert.Equal(t, []string{"yard"}, readSpectrogramSources(t, yardEvents, 1), "frames of other sources are left out of mixed batches")
+}
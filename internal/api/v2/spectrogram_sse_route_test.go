@@ -0,0 +1,62 @@
+// spectrogram_sse_route_test.go: Tests for the configurable spectrogram stream route and event name
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestStreamSpectrogram_ConfiguredPathAndEventName(t *testing.T) {
+	server, controller := setupSSETestServerWithSettings(t, func(settings *conf.Settings) {
+		settings.SoundId.UiSpectrogram.SpectrogramSSEPath = "/birds/spectrogram"
+		settings.SoundId.UiSpectrogram.SpectrogramSSEEventName = "spectrogram_frame"
+	})
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	routes := make(map[string]bool)
+	for _, route := range controller.Echo.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	assert.True(t, routes["GET /api/v2/birds/spectrogram"], "the stream is served on the configured path")
+	assert.False(t, routes["GET /api/v2/spectrogram/stream"], "the default path is no longer registered")
+
+	resp := getSpectrogramStream(t, server.URL+"/api/v2/birds/spectrogram")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return controller.SpectrogramClientCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+	require.NoError(t, controller.BroadcastSpectrogram(&myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{7}}))
+
+	scanner := bufio.NewScanner(resp.Body)
+	event := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			event = after
+			continue
+		}
+		after, ok := strings.CutPrefix(line, "data: ")
+		if !ok || event == SSEStatusConnected || event == spectrogramMetadataEventType {
+			continue
+		}
+
+		require.Equal(t, "spectrogram_frame", event)
+		var frame SSEUiSpectrogramData
+		require.NoError(t, json.Unmarshal([]byte(after), &frame))
+		assert.Equal(t, []byte{7}, frame.Spectrogram)
+		assert.Equal(t, "spectrogram_frame", frame.EventType)
+		return
+	}
+	require.Fail(t, "stream ended before the frame event", scanner.Err())
+}
@@ -0,0 +1,38 @@
+// internal/api/v2/spectrogram_stats.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// SetSpectrogramStatsProvider connects the function reporting the UI spectrogram publisher's
+// frame counters.
+func (c *Controller) SetSpectrogramStatsProvider(provider func() myaudio.UiSpectrogramStats) {
+	c.spectrogramStats.Store(&provider)
+}
+
+// SpectrogramStatsResponse is returned by GET /api/v2/spectrogram/stats
+type SpectrogramStatsResponse struct {
+	myaudio.UiSpectrogramStats
+	ClientRates []SpectrogramClientRate `json:"clientRates"` // Adaptive frame rate of each connected stream client
+}
+
+// GetSpectrogramStats handles GET /api/v2/spectrogram/stats
+// It returns how many frames the spectrogram publisher received, broadcast and dropped, and
+// the frame rate each stream client is held to while its writes lag
+func (c *Controller) GetSpectrogramStats(ctx echo.Context) error {
+	provider := c.spectrogramStats.Load()
+	if provider == nil || *provider == nil {
+		return c.HandleError(ctx, fmt.Errorf("spectrogram manager not connected"),
+			"Spectrogram pipeline not available", http.StatusServiceUnavailable)
+	}
+	response := SpectrogramStatsResponse{UiSpectrogramStats: (*provider)(), ClientRates: []SpectrogramClientRate{}}
+	if c.sseManager != nil {
+		response.ClientRates = c.sseManager.spectrogramClientRates()
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
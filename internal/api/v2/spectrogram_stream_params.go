@@ -0,0 +1,113 @@
+// internal/api/v2/spectrogram_stream_params.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// spectrogramStreamParams are the query parameters of the spectrogram stream handlers
+type spectrogramStreamParams struct {
+	interpolate int    // Intermediate columns between frames
+	fps         int    // Images per second of the PNG stream
+	source      string // Source the stream is limited to; when empty the PNG stream renders the most recent and the SSE stream sends every source
+}
+
+// includesSource reports whether frames of source go to the client of the stream
+func (p spectrogramStreamParams) includesSource(source string) bool {
+	return p.source == "" || p.source == source
+}
+
+// streamParamError is a malformed stream query parameter
+type streamParamError struct {
+	param string
+	value string
+	err   error
+}
+
+func (e *streamParamError) Error() string {
+	return e.err.Error()
+}
+
+// StreamParamErrorResponse is the 400 body for a malformed stream query parameter, naming
+// the parameter so clients can tell which one to fix
+type StreamParamErrorResponse struct {
+	*ErrorResponse
+	Parameter string `json:"parameter"`
+	Value     string `json:"value"`
+}
+
+// spectrogramStreamParamParsers validate each spectrogram stream query parameter and store
+// it in the params, in the order they are checked
+var spectrogramStreamParamParsers = []struct {
+	name  string
+	parse func(value string, params *spectrogramStreamParams) error
+}{
+	{"interpolate", func(value string, params *spectrogramStreamParams) error {
+		n, err := parseSpectrogramInterpolation(value)
+		params.interpolate = n
+		return err
+	}},
+	{"fps", func(value string, params *spectrogramStreamParams) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("fps must be a positive integer, got %q", value)
+		}
+		params.fps = min(n, maxSpectrogramStreamFPS)
+		return nil
+	}},
+	{"keepalive", func(value string, _ *spectrogramStreamParams) error {
+		// The stream negotiates the interval itself; only reject values it would ignore
+		_, err := parseSSEKeepalive(value)
+		return err
+	}},
+}
+
+// parseSpectrogramStreamParams reads the query parameters of a spectrogram stream request.
+// A malformed parameter is returned as an error unless the configured policy is to ignore
+// it, in which case it is logged and keeps its default.
+func (c *Controller) parseSpectrogramStreamParams(ctx echo.Context) (spectrogramStreamParams, *streamParamError) {
+	params := spectrogramStreamParams{
+		fps:    defaultSpectrogramStreamFPS,
+		source: ctx.QueryParam("source"),
+	}
+	ignore := c.Settings != nil && c.Settings.WebServer.InvalidStreamParams == conf.InvalidStreamParamsIgnore
+
+	for _, parser := range spectrogramStreamParamParsers {
+		value := ctx.QueryParam(parser.name)
+		if value == "" {
+			continue
+		}
+		defaults := params
+		if err := parser.parse(value, &params); err != nil {
+			if !ignore {
+				return spectrogramStreamParams{}, &streamParamError{param: parser.name, value: value, err: err}
+			}
+			params = defaults
+			c.logDebugIfEnabled("Ignoring malformed stream query parameter",
+				logger.String("parameter", parser.name),
+				logger.String("value", value),
+				logger.String("path", ctx.Request().URL.Path))
+		}
+	}
+	return params, nil
+}
+
+// rejectStreamParam responds with a 400 naming the malformed parameter
+func (c *Controller) rejectStreamParam(ctx echo.Context, paramErr *streamParamError) error {
+	c.logDebugIfEnabled("Rejecting malformed stream query parameter",
+		logger.String("parameter", paramErr.param),
+		logger.String("value", paramErr.value),
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusBadRequest, StreamParamErrorResponse{
+		ErrorResponse: NewErrorResponse(paramErr, fmt.Sprintf("Invalid %s parameter", paramErr.param), http.StatusBadRequest),
+		Parameter:     paramErr.param,
+		Value:         paramErr.value,
+	})
+}
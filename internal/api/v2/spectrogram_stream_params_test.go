@@ -0,0 +1,74 @@
+// spectrogram_stream_params_test.go: Tests for validating spectrogram stream query parameters
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// getSpectrogramStream opens a spectrogram stream and returns the response, leaving the
+// body open for the caller to close.
+func getSpectrogramStream(t *testing.T, url string) *http.Response {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestSpectrogramStreams_RejectMalformedParams(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	invalid := []struct {
+		query string
+		param string
+	}{
+		{"interpolate=lots", "interpolate"},
+		{"interpolate=99", "interpolate"},
+		{"fps=fast", "fps"},
+		{"fps=0", "fps"},
+		{"keepalive=soon", "keepalive"},
+	}
+	for _, path := range []string{"/api/v2/spectrogram/stream", "/api/v2/spectrogram/live"} {
+		for _, tt := range invalid {
+			resp := getSpectrogramStream(t, server.URL+path+"?"+tt.query)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode, "%s?%s", path, tt.query)
+
+			var body StreamParamErrorResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.param, body.Parameter, "%s?%s", path, tt.query)
+			assert.NotEmpty(t, body.Error)
+		}
+
+		resp := getSpectrogramStream(t, server.URL+path+"?interpolate=2&fps=5&keepalive=15")
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "valid parameters connect to %s", path)
+	}
+}
+
+func TestSpectrogramStreams_IgnoreMalformedParams(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+	controller.Settings.WebServer.InvalidStreamParams = conf.InvalidStreamParamsIgnore
+
+	resp := getSpectrogramStream(t, server.URL+"/api/v2/spectrogram/live?fps=fast")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "the ignore policy falls back to the default fps")
+}
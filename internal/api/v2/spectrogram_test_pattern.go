@@ -0,0 +1,80 @@
+// internal/api/v2/spectrogram_test_pattern.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// Shape of the synthetic test pattern frame
+const (
+	// spectrogramTestPatternSource is the source ID of test pattern frames, so clients can
+	// tell them apart from live audio
+	spectrogramTestPatternSource = "spectrogram_test_pattern"
+	// spectrogramTestPatternColumns is the number of columns the sweep spans
+	spectrogramTestPatternColumns = 64
+	// spectrogramTestPatternDuration is the time the sweep's columns cover
+	spectrogramTestPatternDuration = time.Second
+	// spectrogramTestPatternLevel is the value of the swept bin; all other bins are 0
+	spectrogramTestPatternLevel = 255
+)
+
+// SpectrogramTestPatternResponse describes the test pattern frame that was broadcast
+type SpectrogramTestPatternResponse struct {
+	Source  string `json:"source"`
+	Columns int    `json:"columns"`
+	Bins    int    `json:"bins"`
+	Clients int    `json:"clients"` // Spectrogram clients connected when the frame was sent
+}
+
+// newSpectrogramTestPattern returns a frame sweeping linearly from the lowest bin in the
+// first column to the highest bin in the last. The frame depends only on its timestamp.
+func newSpectrogramTestPattern(at time.Time) myaudio.UiSpectrogramData {
+	bins := myaudio.UiSpectrogramBins
+	spectrogram := make([]byte, spectrogramTestPatternColumns*bins)
+	for col := range spectrogramTestPatternColumns {
+		bin := col * (bins - 1) / (spectrogramTestPatternColumns - 1)
+		spectrogram[col*bins+bin] = spectrogramTestPatternLevel
+	}
+
+	return myaudio.UiSpectrogramData{
+		Spectrogram: spectrogram,
+		Source:      spectrogramTestPatternSource,
+		MsPerColumn: float64(spectrogramTestPatternDuration.Milliseconds()) / spectrogramTestPatternColumns,
+		Timestamp:   at,
+	}
+}
+
+// BroadcastSpectrogramTestPattern handles POST /api/v2/spectrogram/test-pattern
+// It sends one synthetic frequency sweep frame to all connected spectrogram clients, so
+// frontend developers can check their rendering without live audio. The frame isn't kept
+// in the frame history used by debug bundles and the PNG stream.
+func (c *Controller) BroadcastSpectrogramTestPattern(ctx echo.Context) error {
+	if c.sseManager == nil {
+		return c.HandleError(ctx, fmt.Errorf("SSE manager not initialized"),
+			"Spectrogram streaming not available", http.StatusServiceUnavailable)
+	}
+
+	frame := newSpectrogramTestPattern(time.Now())
+	clients := c.SpectrogramClientCount()
+	c.sseManager.BroadcastUiSpectrogram(&SSEUiSpectrogramData{
+		UiSpectrogramData: frame,
+		EventType:         "ui_spectrogram",
+	})
+
+	c.logInfoIfEnabled("Broadcast spectrogram test pattern",
+		logger.Int("clients", clients),
+		logger.String("ip", ctx.RealIP()))
+
+	return ctx.JSON(http.StatusOK, SpectrogramTestPatternResponse{
+		Source:  frame.Source,
+		Columns: spectrogramTestPatternColumns,
+		Bins:    myaudio.UiSpectrogramBins,
+		Clients: clients,
+	})
+}
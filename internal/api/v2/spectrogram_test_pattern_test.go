@@ -0,0 +1,59 @@
+// spectrogram_test_pattern_test.go: Tests for the synthetic spectrogram test pattern endpoint
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestBroadcastSpectrogramTestPattern_SendsOneSweep(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.sseManager = NewSSEManager()
+
+	client := &SSEClient{
+		ID:              "spectrogram-client",
+		StreamType:      streamTypeSpectrogram,
+		SpectrogramChan: make(chan SSEUiSpectrogramData, 4),
+		Done:            make(chan struct{}, 1),
+	}
+	controller.sseManager.AddClient(client)
+	t.Cleanup(func() { controller.sseManager.RemoveClient(client.ID) })
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/spectrogram/test-pattern", http.NoBody)
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.BroadcastSpectrogramTestPattern(controller.Echo.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp SpectrogramTestPatternResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Clients)
+
+	require.Len(t, client.SpectrogramChan, 1, "exactly one frame is broadcast")
+	frame := <-client.SpectrogramChan
+	assert.Equal(t, spectrogramTestPatternSource, frame.Source)
+
+	bins := myaudio.UiSpectrogramBins
+	require.Len(t, frame.Spectrogram, spectrogramTestPatternColumns*bins)
+	peak := -1
+	for col := range spectrogramTestPatternColumns {
+		column := frame.Spectrogram[col*bins : (col+1)*bins]
+		var energy []int
+		for bin, level := range column {
+			if level > 0 {
+				energy = append(energy, bin)
+			}
+		}
+		require.Len(t, energy, 1, "column %d has energy in one bin", col)
+		assert.Greater(t, energy[0], peak, "the sweep rises in column %d", col)
+		peak = energy[0]
+	}
+	assert.Equal(t, bins-1, peak, "the sweep ends at the highest bin")
+	assert.Empty(t, controller.spectrogramHistory.list(), "test frames aren't kept in the history")
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -14,7 +15,9 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
@@ -26,22 +29,28 @@ const (
 	// Connection timeouts
 	maxSSEStreamDuration = 30 * time.Minute      // Maximum stream duration to prevent resource leaks
 	sseHeartbeatInterval = 30 * time.Second      // Heartbeat interval for keep-alive
+	sseMinKeepalive      = 5 * time.Second       // Shortest heartbeat interval a client may request, when not configured
+	sseMaxKeepalive      = 5 * time.Minute       // Longest heartbeat interval a client may request, when not configured
 	sseEventLoopSleep    = 10 * time.Millisecond // Sleep duration when no events
 	sseWriteDeadline     = 10 * time.Second      // Write deadline for SSE messages
 
 	// Endpoints
-	detectionStreamEndpoint  = "/api/v2/detections/stream"
-	soundIdStreamEndpoint  = "/api/v2/soundid/stream"
-	spectrogramStreamEndpoint  = "/api/v2/spectrogram/stream"
-	soundLevelStreamEndpoint = "/api/v2/soundlevels/stream"
+	detectionStreamEndpoint   = "/api/v2/detections/stream"
+	soundIdStreamEndpoint     = "/api/v2/soundid/stream"
+	spectrogramStreamEndpoint = "/api/v2/spectrogram/stream"
+	soundLevelStreamEndpoint  = "/api/v2/soundlevels/stream"
 
 	// Buffer sizes
-	sseDetectionBufferSize  = 100 // Buffer size for detection channels (high volume)
-	sseSoundIdBufferSize    = 100 // Buffer size for Sound ID channels (high volume)
-	sseSpectrogramBufferSize = 100 // Buffer size for spectrogram channels
-	sseSoundLevelBufferSize = 100 // Buffer size for sound level channels
-	sseMinimalBufferSize    = 1   // Minimal buffer for unused channels
-	sseDoneChannelBuffer    = 1   // Buffer for Done channels to prevent blocking
+	sseDetectionBufferSize        = 100 // Buffer size for detection channels (high volume)
+	sseSoundIdBufferSize          = 100 // Buffer size for Sound ID channels (high volume)
+	sseSpectrogramBufferSize      = 100 // Buffer size for spectrogram channels
+	sseSpectrogramBatchBufferSize = 10  // Buffer size for spectrogram batch channels, each batch holding several frames
+	sseAnnotationBufferSize       = 10  // Buffer size for spectrogram annotation channels
+	sseMarkerBufferSize           = 10  // Buffer size for spectrogram detection marker channels
+	sseSourceStatusBufferSize     = 10  // Buffer size for spectrogram source status channels
+	sseSoundLevelBufferSize       = 100 // Buffer size for sound level channels
+	sseMinimalBufferSize          = 1   // Minimal buffer for unused channels
+	sseDoneChannelBuffer          = 1   // Buffer for Done channels to prevent blocking
 
 	// Rate limits
 	sseRateLimitRequests = 10              // SSE rate limit requests per window
@@ -77,16 +86,30 @@ type SSEDetectionData struct {
 
 // SSESoundIdData represents the Sound ID data sent via SSE
 type SSESoundIdData struct {
-	Predictions        []birdnet.SoundIdPrediction   `json:"predictions"`
-	Timestamp          time.Time               		 `json:"timestamp"`
+	Predictions []birdnet.SoundIdPrediction `json:"predictions"`
+	Timestamp   time.Time                   `json:"timestamp"`
 }
 
 // SSEUiSpectrogramData represents spectrogram data sent via SSE
 type SSEUiSpectrogramData struct {
 	myaudio.UiSpectrogramData
-	EventType string `json:"eventType"`
+	EventType           string `json:"eventType"`
+	InterpolatedColumns int    `json:"interpolatedColumns,omitempty"` // Synthetic columns at the start of the frame, blended from the previous frame
+	Recent              bool   `json:"recent,omitempty"`              // Buffered frame sent on connect, older than the live frames that follow
 }
 
+// spectrogramBatchEventType is the SSE event name of several spectrogram frames sent together
+const spectrogramBatchEventType = "ui_spectrogram_batch"
+
+// SSEUiSpectrogramBatch carries frames the publisher coalesced into one event, oldest first,
+// to cut per-event overhead at high frame rates
+type SSEUiSpectrogramBatch struct {
+	Frames    []SSEUiSpectrogramData `json:"frames"`
+	EventType string                 `json:"eventType"`
+}
+
+func (SSEUiSpectrogramBatch) sseEventName() string { return spectrogramBatchEventType }
+
 // SSESoundLevelData represents sound level data sent via SSE
 type SSESoundLevelData struct {
 	myaudio.SoundLevelData
@@ -102,25 +125,33 @@ type SSEEvent struct {
 
 // SSEClient represents a connected SSE client
 type SSEClient struct {
-	ID              string
-	Channel         chan SSEDetectionData
-	SoundIdChan      chan SSESoundIdData
-	SpectrogramChan chan SSEUiSpectrogramData
-	SoundLevelChan  chan SSESoundLevelData
-	Request         *http.Request
-	Response        http.ResponseWriter
-	Done            chan struct{} // Signal-only buffered channel to prevent blocking
-	StreamType      string        // streamTypeDetections, streamTypeSoundId, streamTypeSpectrogram, or streamTypeSoundLevels
+	ID                   string
+	Channel              chan SSEDetectionData
+	SoundIdChan          chan SSESoundIdData
+	SpectrogramChan      chan SSEUiSpectrogramData
+	SpectrogramBatchChan chan SSEUiSpectrogramBatch         // Spectrogram stream only; clients without one get batched frames one by one
+	AnnotationChan       chan SSESpectrogramAnnotation      // Spectrogram stream only
+	MarkerChan           chan SSESpectrogramDetectionMarker // Spectrogram stream only
+	StatusChan           chan SSESpectrogramSourceStatus    // Spectrogram stream only
+	HeartbeatChan        chan SSESpectrogramHeartbeat       // Spectrogram stream only
+	SoundLevelChan       chan SSESoundLevelData
+	Request              *http.Request
+	Response             http.ResponseWriter
+	Done                 chan struct{} // Signal-only buffered channel to prevent blocking
+	StreamType           string        // streamTypeDetections, streamTypeSoundId, streamTypeSpectrogram, or streamTypeSoundLevels
 
 	// Health tracking for auto-disconnect of slow/blocked clients
 	// Uses atomic operations for thread-safe access during concurrent broadcasts
 	consecutiveDrops atomic.Int32 // Count of consecutive failed message sends
+
+	spectrogramRate *spectrogramAdaptiveRate // Spectrogram stream only; lowers the frame rate while writes lag, nil to send every frame
 }
 
 // SSEManager manages SSE connections and broadcasts
 type SSEManager struct {
 	clients map[string]*SSEClient
 	mutex   sync.RWMutex
+	closed  atomic.Bool // Set once the controller shut down
 }
 
 // NewSSEManager creates a new SSE manager
@@ -130,6 +161,12 @@ func NewSSEManager() *SSEManager {
 	}
 }
 
+// Close marks the manager as shut down, after which the spectrogram broadcasts of the
+// controller fail with ErrBroadcasterClosed. Connected clients are left to their handlers.
+func (m *SSEManager) Close() {
+	m.closed.Store(true)
+}
+
 // AddClient adds a new SSE client
 func (m *SSEManager) AddClient(client *SSEClient) {
 	m.mutex.Lock()
@@ -158,6 +195,21 @@ func (m *SSEManager) RemoveClient(clientID string) {
 		if client.SpectrogramChan != nil {
 			close(client.SpectrogramChan)
 		}
+		if client.SpectrogramBatchChan != nil {
+			close(client.SpectrogramBatchChan)
+		}
+		if client.AnnotationChan != nil {
+			close(client.AnnotationChan)
+		}
+		if client.MarkerChan != nil {
+			close(client.MarkerChan)
+		}
+		if client.StatusChan != nil {
+			close(client.StatusChan)
+		}
+		if client.HeartbeatChan != nil {
+			close(client.HeartbeatChan)
+		}
 		close(client.Done)
 		delete(m.clients, clientID)
 		GetLogger().Debug("SSE client disconnected",
@@ -267,6 +319,46 @@ func (m *SSEManager) BroadcastSoundId(soundId *SSESoundIdData) {
 // Uses non-blocking send to prevent slow clients from blocking fast clients.
 // Clients are automatically disconnected after maxConsecutiveDrops failed sends.
 func (m *SSEManager) BroadcastUiSpectrogram(uiSpectrogram *SSEUiSpectrogramData) {
+	m.broadcastToSpectrogramClients("ui_spectrogram", func(client *SSEClient) bool {
+		select {
+		case client.SpectrogramChan <- *uiSpectrogram:
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// BroadcastUiSpectrogramBatch sends a batch of spectrogram frames to all spectrogram stream
+// clients as one event. Clients without a batch channel, such as compare streams, get the
+// frames one by one instead. Slow clients are handled as in BroadcastUiSpectrogram.
+func (m *SSEManager) BroadcastUiSpectrogramBatch(batch *SSEUiSpectrogramBatch) {
+	m.broadcastToSpectrogramClients("ui_spectrogram_batch", func(client *SSEClient) bool {
+		if client.SpectrogramBatchChan == nil {
+			sent := true
+			for i := range batch.Frames {
+				select {
+				case client.SpectrogramChan <- batch.Frames[i]:
+				default:
+					sent = false
+				}
+			}
+			return sent
+		}
+		select {
+		case client.SpectrogramBatchChan <- *batch:
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// broadcastToSpectrogramClients calls send for each spectrogram stream client with a
+// spectrogram channel. send reports whether the client took the data without blocking;
+// clients that didn't for maxConsecutiveDrops broadcasts in a row are disconnected. Clients
+// whose writes lag skip the data while they are held to a lower frame rate.
+func (m *SSEManager) broadcastToSpectrogramClients(channel string, send func(client *SSEClient) bool) {
 	m.mutex.RLock()
 
 	if len(m.clients) == 0 {
@@ -281,12 +373,13 @@ func (m *SSEManager) BroadcastUiSpectrogram(uiSpectrogram *SSEUiSpectrogramData)
 		// Only send to clients that want ui spectrogram data
 		if client.StreamType == streamTypeSpectrogram {
 			if client.SpectrogramChan != nil {
-				select {
-				case client.SpectrogramChan <- *uiSpectrogram:
+				if !client.spectrogramRate.admit() {
+					continue // Held to a lower rate while its writes lag, which isn't a drop
+				}
+				if send(client) {
 					// Successfully sent to client - reset health counter atomically
 					client.consecutiveDrops.Store(0)
-
-				default:
+				} else {
 					// Channel full - drop this update, increment counter atomically
 					drops := client.consecutiveDrops.Add(1)
 
@@ -294,7 +387,7 @@ func (m *SSEManager) BroadcastUiSpectrogram(uiSpectrogram *SSEUiSpectrogramData)
 					if drops >= maxConsecutiveDrops {
 						GetLogger().Info("SSE client disconnected after consecutive drops",
 							logger.String("client_id", clientID),
-							logger.String("channel", "ui_spectrogram"),
+							logger.String("channel", channel),
 							logger.Int("consecutive_drops", int(drops)),
 						)
 						blockedClients = append(blockedClients, clientID)
@@ -314,6 +407,71 @@ func (m *SSEManager) BroadcastUiSpectrogram(uiSpectrogram *SSEUiSpectrogramData)
 	}
 }
 
+// BroadcastSpectrogramAnnotation sends an annotation to all spectrogram stream clients.
+// Annotations are infrequent, so a full channel just drops the event without counting
+// against the client's health; the annotation can still be fetched from the list endpoint.
+func (m *SSEManager) BroadcastSpectrogramAnnotation(annotation *SSESpectrogramAnnotation) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for clientID, client := range m.clients {
+		if client.StreamType != streamTypeSpectrogram || client.AnnotationChan == nil {
+			continue
+		}
+		select {
+		case client.AnnotationChan <- *annotation:
+		default:
+			GetLogger().Debug("SSE annotation dropped for slow client",
+				logger.String("client_id", clientID),
+				logger.String("annotation_id", annotation.ID),
+			)
+		}
+	}
+}
+
+// BroadcastSpectrogramDetectionMarker sends a detection marker to all spectrogram stream
+// clients. Like annotations, a full channel drops the marker without counting against the
+// client's health.
+func (m *SSEManager) BroadcastSpectrogramDetectionMarker(marker *SSESpectrogramDetectionMarker) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for clientID, client := range m.clients {
+		if client.StreamType != streamTypeSpectrogram || client.MarkerChan == nil {
+			continue
+		}
+		select {
+		case client.MarkerChan <- *marker:
+		default:
+			GetLogger().Debug("SSE detection marker dropped for slow client",
+				logger.String("client_id", clientID),
+				logger.Uint64("note_id", uint64(marker.NoteID)),
+			)
+		}
+	}
+}
+
+// BroadcastSpectrogramSourceStatus sends a source stall or resume event to all spectrogram
+// stream clients. A full channel drops the event without counting against the client's health.
+func (m *SSEManager) BroadcastSpectrogramSourceStatus(status *SSESpectrogramSourceStatus) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for clientID, client := range m.clients {
+		if client.StreamType != streamTypeSpectrogram || client.StatusChan == nil {
+			continue
+		}
+		select {
+		case client.StatusChan <- *status:
+		default:
+			GetLogger().Debug("SSE source status dropped for slow client",
+				logger.String("client_id", clientID),
+				logger.String("source", status.Source),
+			)
+		}
+	}
+}
+
 // BroadcastSoundLevel sends sound level data to all connected clients
 // Uses non-blocking send to prevent slow clients from blocking fast clients.
 // Clients are automatically disconnected after maxConsecutiveDrops failed sends.
@@ -372,6 +530,19 @@ func (m *SSEManager) GetClientCount() int {
 	return len(m.clients)
 }
 
+// clientCountByType returns the number of connected clients of one stream type
+func (m *SSEManager) clientCountByType(streamType string) int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	count := 0
+	for _, client := range m.clients {
+		if client.StreamType == streamType {
+			count++
+		}
+	}
+	return count
+}
+
 // initSSERoutes registers SSE-related API endpoints
 func (c *Controller) initSSERoutes() {
 	// Initialize SSE manager if not already done
@@ -406,8 +577,43 @@ func (c *Controller) initSSERoutes() {
 	// SSE endpoint for Sound ID detection stream
 	c.Group.GET("/soundid/stream", c.StreamSoundId) //, middleware.RateLimiterWithConfig(rateLimiterConfig))
 
-	c.Group.GET("/spectrogram/stream", c.StreamSpectrogram) //, middleware.RateLimiterWithConfig(rateLimiterConfig))
-	
+	// The route is configurable for deployments that already use it behind a proxy
+	c.Group.GET(c.spectrogramSSEPath(), c.StreamSpectrogram) //, middleware.RateLimiterWithConfig(rateLimiterConfig))
+
+	// One stream combining two sources, for A/B comparing microphones
+	c.Group.GET("/spectrogram/compare", c.StreamSpectrogramCompare)
+
+	// Replay a saved clip through the spectrogram pipeline (developer tool, requires auth)
+	c.Group.POST("/spectrogram/replay", c.ReplaySpectrogram, c.authMiddleware)
+
+	// Send one synthetic sweep frame to check client rendering (developer tool, requires auth)
+	c.Group.POST("/spectrogram/test-pattern", c.BroadcastSpectrogramTestPattern, c.authMiddleware)
+
+	// Labeled time regions broadcast on the spectrogram stream
+	c.Group.GET("/spectrogram/annotations", c.GetSpectrogramAnnotations)
+	c.Group.POST("/spectrogram/annotations", c.CreateSpectrogramAnnotation, c.authMiddleware)
+
+	// Configuration the running spectrogram session has applied
+	c.Group.GET("/spectrogram/config", c.GetSpectrogramConfig)
+
+	// Frames the spectrogram publisher received, broadcast and dropped
+	c.Group.GET("/spectrogram/stats", c.GetSpectrogramStats)
+
+	// Whether the spectrogram feed is healthy, degraded or stopped
+	c.Group.GET("/spectrogram/health", c.GetSpectrogramHealth)
+
+	// Capture a known-level tone to calibrate the spectrogram amplitude reference
+	c.Group.POST("/spectrogram/calibration", c.CaptureSpectrogramCalibration, c.authMiddleware)
+
+	// Live spectrogram as a multipart PNG stream for clients without JavaScript
+	c.Group.GET("/spectrogram/live", c.StreamSpectrogramPNG)
+
+	// Still PNG of the latest frames, for sharing without opening a stream
+	c.Group.GET("/spectrogram/snapshot.png", c.GetSpectrogramSnapshot)
+
+	// Recent frames, detections, config and logs as a zip for reporting misdetections
+	c.Group.GET("/spectrogram/debug-bundle", c.GetSpectrogramDebugBundle, c.authMiddleware)
+
 	// SSE endpoint for sound level stream with rate limiting
 	c.Group.GET("/soundlevels/stream", c.StreamSoundLevels, middleware.RateLimiterWithConfig(rateLimiterConfig))
 
@@ -481,6 +687,50 @@ func (c *Controller) sendSSEHeartbeat(ctx echo.Context, clientID, streamType str
 	return nil
 }
 
+// sseKeepaliveInterval returns the heartbeat interval for a connection. Clients behind proxies
+// with short idle timeouts can request their own with ?keepalive=<seconds> or a duration like
+// "15s", which is clamped to the configured bounds; others get the configured default.
+func (c *Controller) sseKeepaliveInterval(ctx echo.Context, clientID string) time.Duration {
+	defaultInterval, minInterval, maxInterval := sseHeartbeatInterval, sseMinKeepalive, sseMaxKeepalive
+	if c.Settings != nil {
+		cfg := c.Settings.WebServer.SSEKeepalive
+		if cfg.Default > 0 {
+			defaultInterval = time.Duration(cfg.Default) * time.Second
+		}
+		if cfg.Min > 0 {
+			minInterval = time.Duration(cfg.Min) * time.Second
+		}
+		if cfg.Max > 0 {
+			maxInterval = time.Duration(cfg.Max) * time.Second
+		}
+	}
+
+	requested := ctx.QueryParam("keepalive")
+	if requested == "" {
+		return defaultInterval
+	}
+	interval, err := parseSSEKeepalive(requested)
+	if err != nil {
+		c.logDebugIfEnabled("Ignoring invalid SSE keepalive interval",
+			logger.String("client_id", clientID),
+			logger.String("keepalive", requested))
+		return defaultInterval
+	}
+	return min(max(interval, minInterval), maxInterval)
+}
+
+// parseSSEKeepalive reads a keepalive query parameter, either whole seconds or a Go duration.
+func parseSSEKeepalive(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if seconds, convErr := strconv.Atoi(value); convErr == nil {
+		interval, err = time.Duration(seconds)*time.Second, nil
+	}
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("keepalive must be a positive number of seconds or a duration, got %q", value)
+	}
+	return interval, nil
+}
+
 // handleSSEStream handles the common SSE stream setup and teardown with timeout protection
 func (c *Controller) handleSSEStream(ctx echo.Context, streamType, message, logPrefix string, setupFunc func(*SSEClient), eventLoop func(echo.Context, *SSEClient, string) error) error {
 	// Track connection start time for metrics
@@ -605,27 +855,87 @@ func (c *Controller) StreamSoundId(ctx echo.Context) error {
 		})
 }
 
-// StreamSpectrogram handles the SSE connection for real-time spectrogram streaming
+// StreamSpectrogram handles the SSE connection for real-time spectrogram streaming. Frames
+// of every audio source are sent, each naming its source, unless the client asks for one
+// with ?source, so a view per source can open a stream per source.
 func (c *Controller) StreamSpectrogram(ctx echo.Context) error {
-	return c.handleSSEStream(ctx, streamTypeSpectrogram, "Connected to spectrogram stream", "ui_spectrogram",
+	params, paramErr := c.parseSpectrogramStreamParams(ctx)
+	if paramErr != nil {
+		return c.rejectStreamParam(ctx, paramErr)
+	}
+	// Clients opt in to intermediate columns with ?interpolate=N, since they cost bandwidth
+	interpolator := newSpectrogramInterpolator(params.interpolate)
+	encoder := newSpectrogramFrameEncoder(c.Settings)
+	eventName := c.spectrogramSSEEventName()
+	defer c.compressSpectrogramStream(ctx)()
+
+	return c.handleSSEStream(ctx, streamTypeSpectrogram, "Connected to spectrogram stream", eventName,
 		func(client *SSEClient) {
-			client.Channel = make(chan SSEDetectionData, sseMinimalBufferSize)            // Minimal buffer, not used for spectrograms
+			client.Channel = make(chan SSEDetectionData, sseMinimalBufferSize)                 // Minimal buffer, not used for spectrograms
 			client.SpectrogramChan = make(chan SSEUiSpectrogramData, sseSpectrogramBufferSize) // Buffer for ui spectrogram data
+			client.SpectrogramBatchChan = make(chan SSEUiSpectrogramBatch, sseSpectrogramBatchBufferSize)
+			client.AnnotationChan = make(chan SSESpectrogramAnnotation, sseAnnotationBufferSize)
+			client.MarkerChan = make(chan SSESpectrogramDetectionMarker, sseMarkerBufferSize)
+			client.StatusChan = make(chan SSESpectrogramSourceStatus, sseSourceStatusBufferSize)
+			client.HeartbeatChan = make(chan SSESpectrogramHeartbeat, sseSpectrogramHeartbeatBufferSize)
+			client.spectrogramRate = newSpectrogramAdaptiveRate()
 		},
 		func(ctx echo.Context, client *SSEClient, clientID string) error {
+			if err := c.sendSSEMessage(ctx, spectrogramMetadataEventType, newSpectrogramMetadata(params, encoder)); err != nil {
+				return err
+			}
+			if err := c.sendRecentSpectrogramFrames(ctx, params, interpolator, encoder, eventName); err != nil {
+				return err
+			}
 			return c.runSSEEventLoop(ctx, client, clientID, spectrogramStreamEndpoint,
 				func() (any, bool) {
 					select {
 					case uiSpectrogram, ok := <-client.SpectrogramChan:
+						if !ok || !params.includesSource(uiSpectrogram.Source) {
+							return nil, false // Channel closed, or a frame of another source
+						}
+						return encoder.frame(interpolator.apply(uiSpectrogram)), true
+					case batch, ok := <-client.SpectrogramBatchChan:
 						if !ok {
-							return nil, false // Channel closed, no more data
+							return nil, false
+						}
+						// The frames are shared with other clients, so filter and interpolate into a copy
+						frames := make([]SSEUiSpectrogramData, 0, len(batch.Frames))
+						for i := range batch.Frames {
+							if params.includesSource(batch.Frames[i].Source) {
+								frames = append(frames, interpolator.apply(batch.Frames[i]))
+							}
+						}
+						if len(frames) == 0 {
+							return nil, false
+						}
+						batch.Frames = frames
+						return encoder.batch(batch), true
+					case annotation, ok := <-client.AnnotationChan:
+						if !ok {
+							return nil, false
 						}
-						return uiSpectrogram, true
+						return annotation, true
+					case marker, ok := <-client.MarkerChan:
+						if !ok {
+							return nil, false
+						}
+						return marker, true
+					case status, ok := <-client.StatusChan:
+						if !ok {
+							return nil, false
+						}
+						return status, true
+					case heartbeat, ok := <-client.HeartbeatChan:
+						if !ok {
+							return nil, false
+						}
+						return heartbeat, true
 					default:
 						return nil, false
 					}
 				},
-				"ui_spectrogram",
+				eventName,
 				streamTypeSpectrogram,
 			)
 		})
@@ -657,11 +967,17 @@ func (c *Controller) StreamSoundLevels(ctx echo.Context) error {
 		})
 }
 
+// sseNamedEvent is implemented by payloads that are sent under their own SSE event name
+// rather than the stream's default event type.
+type sseNamedEvent interface {
+	sseEventName() string
+}
+
 // runSSEEventLoop handles the common SSE event loop pattern for all stream types
 func (c *Controller) runSSEEventLoop(ctx echo.Context, client *SSEClient, clientID string, endpoint string,
 	dataReceiver func() (any, bool), eventType string, heartbeatType string) error {
 
-	ticker := time.NewTicker(sseHeartbeatInterval)
+	ticker := time.NewTicker(c.sseKeepaliveInterval(ctx, clientID))
 	defer ticker.Stop()
 
 	for {
@@ -685,17 +1001,24 @@ func (c *Controller) runSSEEventLoop(ctx echo.Context, client *SSEClient, client
 		default:
 			// Check for data on the channel (non-blocking)
 			if data, hasData := dataReceiver(); hasData {
-				if err := c.sendSSEMessage(ctx, eventType, data); err != nil {
+				event := eventType
+				if named, ok := data.(sseNamedEvent); ok {
+					event = named.sseEventName()
+				}
+				started := time.Now()
+				err := c.sendSSEMessage(ctx, event, data)
+				client.spectrogramRate.observe(time.Since(started))
+				if err != nil {
 					c.logErrorIfEnabled("Failed to send SSE message",
 						logger.String("client_id", clientID),
 						logger.String("endpoint", endpoint),
-						logger.String("event_type", eventType),
+						logger.String("event_type", event),
 						logger.Error(err),
 					)
 					c.recordSSEError(endpoint, "send_failed")
 					return err
 				}
-				c.recordSSEMessage(endpoint, eventType)
+				c.recordSSEMessage(endpoint, event)
 			} else {
 				// Small sleep to prevent busy-waiting when no data
 				time.Sleep(sseEventLoopSleep)
@@ -706,14 +1029,20 @@ func (c *Controller) runSSEEventLoop(ctx echo.Context, client *SSEClient, client
 
 // sendSSEMessage sends a Server-Sent Event message
 func (c *Controller) sendSSEMessage(ctx echo.Context, event string, data any) error {
-	// Convert data to JSON with panic recovery
-	jsonData, err := c.safeMarshalJSON(event, data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal SSE data: %w", err)
+	var payload string
+	if raw, ok := data.(sseRawEvent); ok {
+		payload = raw.sseRawData()
+	} else {
+		// Convert data to JSON with panic recovery
+		jsonData, err := c.safeMarshalJSON(event, data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal SSE data: %w", err)
+		}
+		payload = string(jsonData)
 	}
 
 	// Format SSE message
-	message := fmt.Sprintf("event: %s\ndata: %s\n\n", event, string(jsonData))
+	message := fmt.Sprintf("event: %s\ndata: %s\n\n", event, payload)
 
 	// Set write deadline to prevent hanging on slow/disconnected clients
 	if conn, ok := ctx.Response().Writer.(WriteDeadlineSetter); ok {
@@ -799,6 +1128,12 @@ func (c *Controller) BroadcastDetection(note *datastore.Note, birdImage *imagepr
 	}
 
 	c.sseManager.BroadcastDetection(&detection)
+
+	marker := newSpectrogramDetectionMarker(note, c.Settings)
+	if c.Settings != nil && c.Settings.SoundId.UiSpectrogram.CombinedMarkers {
+		marker = newCombinedSpectrogramDetectionMarker(&detection, c.Settings)
+	}
+	c.sseManager.BroadcastSpectrogramDetectionMarker(&marker)
 	return nil
 }
 
@@ -816,34 +1151,128 @@ func (c *Controller) BroadcastSoundId(predictions []birdnet.SoundIdPrediction) e
 
 	soundId := SSESoundIdData{
 		Predictions: predictions,
-		Timestamp: time.Now(),
+		Timestamp:   time.Now(),
 	}
 
 	c.sseManager.BroadcastSoundId(&soundId)
 	return nil
 }
 
+// SpectrogramClientCount returns the number of clients watching the live spectrogram, on the
+// SSE stream or the PNG stream. While it is zero, the publisher skips BroadcastSpectrogram.
+func (c *Controller) SpectrogramClientCount() int {
+	count := int(c.spectrogramPNGViewers.Load())
+	if c.sseManager != nil {
+		count += c.sseManager.clientCountByType(streamTypeSpectrogram)
+	}
+	return count
+}
+
 // BroadcastSpectrogram is a helper method to broadcast spectrogram data from the controller
 func (c *Controller) BroadcastSpectrogram(uiSpectrogram *myaudio.UiSpectrogramData) error {
 	if c.sseManager == nil {
-		return fmt.Errorf("SSE manager not initialized")
+		return spectrogramBroadcastError(errors.CategorySystem, "SSE manager not initialized")
+	}
+	if c.sseManager.closed.Load() {
+		return spectrogramBroadcasterClosedError()
 	}
 
 	// Add nil check to prevent panic
 	if uiSpectrogram == nil {
 		c.logErrorIfEnabled("SSE broadcast skipped: uiSpectrogram is nil")
-		return fmt.Errorf("uiSpectrogram is nil")
+		return spectrogramBroadcastError(errors.CategoryValidation, "uiSpectrogram is nil")
 	}
 
+	c.spectrogramHistory.add(uiSpectrogram)
+
 	sseData := SSEUiSpectrogramData{
 		UiSpectrogramData: *uiSpectrogram,
-		EventType:      "ui_spectrogram",
+		EventType:         c.spectrogramSSEEventName(),
 	}
 
 	c.sseManager.BroadcastUiSpectrogram(&sseData)
 	return nil
 }
 
+// spectrogramSSEPath returns the configured route of the spectrogram stream within the API
+// group, or the default route when none is configured
+func (c *Controller) spectrogramSSEPath() string {
+	if c.Settings != nil && c.Settings.SoundId.UiSpectrogram.SpectrogramSSEPath != "" {
+		return c.Settings.SoundId.UiSpectrogram.SpectrogramSSEPath
+	}
+	return conf.DefaultUiSpectrogramSSEPath
+}
+
+// spectrogramSSEEventName returns the configured SSE event name of single spectrogram
+// frames, or the default name when none is configured
+func (c *Controller) spectrogramSSEEventName() string {
+	if c.Settings != nil && c.Settings.SoundId.UiSpectrogram.SpectrogramSSEEventName != "" {
+		return c.Settings.SoundId.UiSpectrogram.SpectrogramSSEEventName
+	}
+	return conf.DefaultUiSpectrogramSSEEventName
+}
+
+// spectrogramBroadcastError returns a spectrogram broadcast failure that retrying can't fix,
+// so the publisher's supervisor doesn't restart it over one
+func spectrogramBroadcastError(category errors.ErrorCategory, message string) error {
+	return errors.Newf("%s", message).
+		Component("api").
+		Category(category).
+		Context("operation", "spectrogram_broadcast").
+		Retryable(false).
+		Build()
+}
+
+// ErrBroadcasterClosed is the cause of spectrogram broadcasts failing once the controller shut
+// down. Retrying can't succeed, so a publisher getting it should stop.
+var ErrBroadcasterClosed = errors.NewStd("spectrogram broadcaster closed")
+
+// spectrogramBroadcasterClosedError returns a spectrogram broadcast failure of a controller
+// that shut down, matching ErrBroadcasterClosed with errors.Is
+func spectrogramBroadcasterClosedError() error {
+	return errors.New(ErrBroadcasterClosed).
+		Component("api").
+		Category(errors.CategoryState).
+		Context("operation", "spectrogram_broadcast").
+		Retryable(false).
+		Build()
+}
+
+// BroadcastSpectrogramBatch broadcasts several spectrogram frames as one event, oldest first
+func (c *Controller) BroadcastSpectrogramBatch(frames []*myaudio.UiSpectrogramData) error {
+	if c.sseManager == nil {
+		return spectrogramBroadcastError(errors.CategorySystem, "SSE manager not initialized")
+	}
+	if c.sseManager.closed.Load() {
+		return spectrogramBroadcasterClosedError()
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+
+	batch := SSEUiSpectrogramBatch{
+		Frames:    make([]SSEUiSpectrogramData, 0, len(frames)),
+		EventType: spectrogramBatchEventType,
+	}
+	eventName := c.spectrogramSSEEventName()
+	for _, frame := range frames {
+		if frame == nil {
+			c.logErrorIfEnabled("SSE batch broadcast skipped: frame is nil")
+			return spectrogramBroadcastError(errors.CategoryValidation, "spectrogram batch contains a nil frame")
+		}
+		batch.Frames = append(batch.Frames, SSEUiSpectrogramData{
+			UiSpectrogramData: *frame,
+			EventType:         eventName,
+		})
+	}
+	for _, frame := range frames {
+		c.spectrogramHistory.add(frame)
+	}
+
+	c.sseManager.BroadcastUiSpectrogramBatch(&batch)
+	return nil
+}
+
 // BroadcastSoundLevel is a helper method to broadcast sound level data from the controller
 func (c *Controller) BroadcastSoundLevel(soundLevel *myaudio.SoundLevelData) error {
 	if c.sseManager == nil {
@@ -388,6 +388,13 @@ func TestSSERateLimiting(t *testing.T) {
 
 // setupSSETestServer creates a test server with SSE endpoints configured
 func setupSSETestServer(t *testing.T) (*httptest.Server, *Controller) {
+	t.Helper()
+	return setupSSETestServerWithSettings(t, nil)
+}
+
+// setupSSETestServerWithSettings is setupSSETestServer with configure, if given, applied to
+// the settings before the routes are registered
+func setupSSETestServerWithSettings(t *testing.T, configure func(*conf.Settings)) (*httptest.Server, *Controller) {
 	t.Helper()
 	// Create Echo instance
 	e := echo.New()
@@ -409,6 +416,10 @@ func setupSSETestServer(t *testing.T) (*httptest.Server, *Controller) {
 		},
 	}
 
+	if configure != nil {
+		configure(settings)
+	}
+
 	// Create control channel
 	controlChan := make(chan string, 10)
 
@@ -0,0 +1,89 @@
+// sse_keepalive_test.go: Tests for per-client SSE keepalive negotiation
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestSSEKeepalive_ClientNegotiatesInterval(t *testing.T) {
+	settings := &conf.Settings{
+		WebServer: conf.WebServerSettings{SSEKeepalive: conf.SSEKeepaliveSettings{Default: 30, Min: 1, Max: 60}},
+		Realtime:  conf.RealtimeSettings{Audio: conf.AudioSettings{Export: conf.ExportSettings{Path: t.TempDir()}}},
+	}
+	e := echo.New()
+	controller, err := NewWithOptions(e, nil, settings, nil, nil, nil, nil, false)
+	require.NoError(t, err)
+	e.GET("/stream", controller.StreamSoundLevels)
+	server := httptest.NewServer(e)
+	t.Cleanup(func() {
+		server.Close()
+		controller.Shutdown()
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 2500*time.Millisecond)
+	defer cancel()
+
+	countHeartbeats := func(query string) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream"+query, http.NoBody)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		heartbeats := 0
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == "event: heartbeat" {
+				heartbeats++
+			}
+		}
+		return heartbeats
+	}
+
+	var wg sync.WaitGroup
+	var negotiated, standard int
+	wg.Go(func() { negotiated = countHeartbeats("?keepalive=1") })
+	wg.Go(func() { standard = countHeartbeats("") })
+	wg.Wait()
+
+	assert.Equal(t, 2, negotiated, "a client requesting 1s must get a heartbeat every second")
+	assert.Zero(t, standard, "a client without a request keeps the 30s default")
+}
+
+func TestSSEKeepaliveInterval_Clamping(t *testing.T) {
+	controller := &Controller{Settings: &conf.Settings{
+		WebServer: conf.WebServerSettings{SSEKeepalive: conf.SSEKeepaliveSettings{Default: 30, Min: 10, Max: 60}},
+	}}
+	e := echo.New()
+
+	tests := []struct {
+		query string
+		want  time.Duration
+	}{
+		{"", 30 * time.Second},
+		{"?keepalive=15", 15 * time.Second},
+		{"?keepalive=20s", 20 * time.Second},
+		{"?keepalive=1", 10 * time.Second},
+		{"?keepalive=1h", 60 * time.Second},
+		{"?keepalive=soon", 30 * time.Second},
+		{"?keepalive=-5", 30 * time.Second},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/stream"+tt.query, http.NoBody)
+		ctx := e.NewContext(req, httptest.NewRecorder())
+		assert.Equal(t, tt.want, controller.sseKeepaliveInterval(ctx, "client"), tt.query)
+	}
+}
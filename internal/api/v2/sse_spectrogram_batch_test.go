@@ -0,0 +1,72 @@
+// sse_spectrogram_batch_test.go: Tests for broadcasting spectrogram frames in batches
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestBroadcastSpectrogramBatch_SendsOneEvent(t *testing.T) {
+	server, controller := setupSSETestServer(t)
+	t.Cleanup(func() {
+		controller.Shutdown()
+		server.Close()
+	})
+
+	resp := getSpectrogramStream(t, server.URL+"/api/v2/spectrogram/stream")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool { return controller.SpectrogramClientCount() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	frames := []*myaudio.UiSpectrogramData{
+		{Source: "mic", Spectrogram: []byte{1}},
+		{Source: "mic", Spectrogram: []byte{2}},
+	}
+	require.NoError(t, controller.BroadcastSpectrogramBatch(frames))
+
+	scanner := bufio.NewScanner(resp.Body)
+	event := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		if after, ok := strings.CutPrefix(line, "event: "); ok {
+			event = after
+			continue
+		}
+		after, ok := strings.CutPrefix(line, "data: ")
+		if !ok || event == SSEStatusConnected || event == spectrogramMetadataEventType {
+			continue
+		}
+
+		require.Equal(t, spectrogramBatchEventType, event, "the frames arrive as one batch event")
+		var batch SSEUiSpectrogramBatch
+		require.NoError(t, json.Unmarshal([]byte(after), &batch))
+		require.Len(t, batch.Frames, 2)
+		assert.Equal(t, []byte{1}, batch.Frames[0].Spectrogram)
+		assert.Equal(t, []byte{2}, batch.Frames[1].Spectrogram)
+		return
+	}
+	require.Fail(t, "stream ended before the batch event", scanner.Err())
+}
+
+func TestBroadcastSpectrogram_FailsAfterShutdown(t *testing.T) {
+	_, _, controller := setupTestEnvironment(t)
+	controller.sseManager = NewSSEManager()
+	frame := &myaudio.UiSpectrogramData{Source: "mic", Spectrogram: []byte{1}}
+	require.NoError(t, controller.BroadcastSpectrogram(frame))
+
+	controller.Shutdown()
+	require.ErrorIs(t, controller.BroadcastSpectrogram(frame), ErrBroadcasterClosed)
+	require.ErrorIs(t, controller.BroadcastSpectrogramBatch([]*myaudio.UiSpectrogramData{frame}), ErrBroadcasterClosed)
+	err := controller.BroadcastSpectrogramHeartbeat()
+	require.ErrorIs(t, err, ErrBroadcasterClosed)
+	assert.True(t, errors.IsPermanent(err), "retrying a closed broadcaster can't succeed")
+}
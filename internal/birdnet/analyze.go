@@ -18,6 +18,10 @@ type Filter struct {
 	Label string
 }
 
+// defaultPredictionResults is the number of top results a prediction returns unless
+// detection events ask for more candidates
+const defaultPredictionResults = 10
+
 // DetectionsMap maps species names to a list of their detection results.
 type DetectionsMap map[string][]datastore.Results
 
@@ -151,7 +155,7 @@ func (bn *BirdNET) PredictWithContext(ctx context.Context, sample [][]float32) (
 	}
 
 	// Use optimized top-k algorithm instead of full sort + trim
-	topResults := getTopKResults(results, 10)
+	topResults := getTopKResults(results, max(defaultPredictionResults, bn.Settings.BirdNET.TopK))
 
 	// Log prediction timing for performance monitoring
 	duration := time.Since(start)
@@ -164,7 +168,7 @@ func (bn *BirdNET) PredictWithContext(ctx context.Context, sample [][]float32) (
 
 	// The span.Finish() will automatically record the prediction metrics
 
-	// Return the top 10 results
+	// Return the top results
 	return topResults, nil
 }
 
@@ -18,6 +18,7 @@ type ModelInfo struct {
 	DefaultLocale    string   // Default locale if none is specified
 	NumSpecies       int      // Number of species in the model
 	CustomPath       string   // Path to custom model file, if any
+	TaxonomyVersion  string   // Taxonomy the species labels follow, such as "eBird 2021", empty when unknown
 }
 
 // Predefined supported models
@@ -29,8 +30,9 @@ var supportedModels = map[string]ModelInfo{
 		SupportedLocales: []string{"af", "ar", "bg", "ca", "cs", "da", "de", "el", "en-uk", "en-us", "es",
 			"et", "fi", "fr", "he", "hr", "hu", "id", "is", "it", "ja", "ko", "lt", "lv", "ml", "nl",
 			"no", "pl", "pt", "pt-br", "pt-pt", "ro", "ru", "sk", "sl", "sr", "sv", "th", "tr", "uk", "zh"},
-		DefaultLocale: conf.DefaultFallbackLocale,
-		NumSpecies:    6523,
+		DefaultLocale:   conf.DefaultFallbackLocale,
+		NumSpecies:      6523,
+		TaxonomyVersion: "eBird 2021",
 	},
 	"sound_id": {
 		ID:               "sound_id",
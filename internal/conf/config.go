@@ -96,6 +96,7 @@ type AudioSettings struct {
 	SoxPath         string             `yaml:"soxpath" mapstructure:"soxpath" json:"soxPath"`          // path to sox, runtime value
 	SoxAudioTypes   []string           `yaml:"-" json:"-"`                                             // supported audio types of sox, runtime value
 	StreamTransport string             `json:"streamTransport"`                                        // preferred transport for audio streaming: "auto", "sse", or "ws"
+	StartupSkip     int                `json:"startupSkip"`                                            // milliseconds of audio discarded after a capture device opens, to skip device warm-up noise
 	Export          ExportSettings     `json:"export"`                                                 // export settings
 	SoundLevel      SoundLevelSettings `json:"soundLevel"`                                             // sound level monitoring settings
 
@@ -137,8 +138,16 @@ type Dashboard struct {
 	Locale          string               `json:"locale,omitempty"` // UI locale setting
 	Spectrogram     SpectrogramPreRender `json:"spectrogram"`      // Spectrogram pre-rendering settings
 	TemperatureUnit string               `json:"temperatureUnit"`  // display unit for temperature: "celsius" or "fahrenheit"
+	NameDisplay     string               `json:"nameDisplay"`      // how species names are shown: "common", "scientific" or "both"
 }
 
+// Species name display preferences for Dashboard.NameDisplay
+const (
+	NameDisplayCommon     = "common"     // common name only
+	NameDisplayScientific = "scientific" // scientific name only
+	NameDisplayBoth       = "both"       // common name followed by the scientific name in parentheses
+)
+
 // Spectrogram generation mode constants
 const (
 	SpectrogramModeAuto          = "auto"
@@ -175,6 +184,16 @@ type SpectrogramPreRender struct {
 	Raw          bool   `json:"raw"          mapstructure:"raw"`          // Generate raw spectrogram without axes/legend (default: true)
 	Style        string `json:"style"        mapstructure:"style"`        // Visual style preset: "default", "scientific_dark", "high_contrast_dark", "scientific"
 	DynamicRange string `json:"dynamicRange" mapstructure:"dynamicRange"` // Dynamic range in dB: "80" (high contrast), "100" (standard), "120" (extended)
+
+	Retention SpectrogramRetention `json:"retention" mapstructure:"retention"` // Limits on saved spectrogram images
+}
+
+// SpectrogramRetention limits how many saved spectrogram images are kept. Removed images are
+// regenerated on demand; the clips themselves are managed by the audio retention policy.
+type SpectrogramRetention struct {
+	MaxCount      int    `json:"maxCount"      mapstructure:"maxCount"`      // Images to keep, newest first; 0 for no limit
+	MaxAge        string `json:"maxAge"        mapstructure:"maxAge"`        // Oldest image to keep, e.g. "30d"; empty for no limit
+	CheckInterval int    `json:"checkInterval" mapstructure:"checkInterval"` // Cleanup interval in minutes (default: 60)
 }
 
 // GetMode returns the effective spectrogram generation mode, handling backward compatibility.
@@ -271,6 +290,13 @@ type WeatherSettings struct {
 type NotificationConfig struct {
 	Push      PushSettings          `json:"push" yaml:"push"`
 	Templates NotificationTemplates `json:"templates" yaml:"templates"`
+	Digest    DigestSettings        `json:"digest" yaml:"digest"`
+}
+
+// DigestSettings controls periodic digest notifications summarizing recent detections.
+type DigestSettings struct {
+	Enabled  bool `json:"enabled" yaml:"enabled"`   // true to send a digest of the species detected in each interval instead of a notification per detection
+	Interval int  `json:"interval" yaml:"interval"` // minutes covered by each digest
 }
 
 // NotificationTemplates contains customizable notification message templates.
@@ -442,11 +468,11 @@ type StreamConfig struct {
 // RTSPSettings contains settings for audio streaming (supports multiple protocols).
 // Note: Struct name kept for backward compatibility with existing code.
 type RTSPSettings struct {
-	Streams          []StreamConfig     `yaml:"streams" json:"streams" mapstructure:"streams"`                                   // Stream configurations
-	URLs             []string           `yaml:"urls,omitempty" json:"urls,omitempty" mapstructure:"urls"`                        // Legacy: accepts old format, migrated on load
-	Transport        string             `yaml:"transport,omitempty" json:"transport,omitempty" mapstructure:"transport"`         // Legacy: global default, migrated on load
-	Health           RTSPHealthSettings `yaml:"health" json:"health" mapstructure:"health"`                                      // Health monitoring settings
-	FFmpegParameters []string           `yaml:"ffmpegParameters" json:"ffmpegParameters" mapstructure:"ffmpegParameters"`        // Custom FFmpeg parameters
+	Streams          []StreamConfig     `yaml:"streams" json:"streams" mapstructure:"streams"`                            // Stream configurations
+	URLs             []string           `yaml:"urls,omitempty" json:"urls,omitempty" mapstructure:"urls"`                 // Legacy: accepts old format, migrated on load
+	Transport        string             `yaml:"transport,omitempty" json:"transport,omitempty" mapstructure:"transport"`  // Legacy: global default, migrated on load
+	Health           RTSPHealthSettings `yaml:"health" json:"health" mapstructure:"health"`                               // Health monitoring settings
+	FFmpegParameters []string           `yaml:"ffmpegParameters" json:"ffmpegParameters" mapstructure:"ffmpegParameters"` // Custom FFmpeg parameters
 }
 
 // CRITICAL: Legacy fields (URLs, Transport) MUST include json tags to accept
@@ -455,15 +481,15 @@ type RTSPSettings struct {
 
 // MQTTSettings contains settings for MQTT integration.
 type MQTTSettings struct {
-	Enabled       bool                  `json:"enabled"`       // true to enable MQTT
-	Debug         bool                  `json:"debug"`         // true to enable MQTT debug
-	Broker        string                `json:"broker"`        // MQTT broker URL
-	Topic         string                `json:"topic"`         // MQTT topic
-	Username      string                `json:"username"`      // MQTT username
-	Password      string                `json:"password"`      // MQTT password
-	Retain        bool                  `json:"retain"`        // true to retain messages
-	RetrySettings RetrySettings         `json:"retrySettings"` // settings for retry mechanism
-	TLS           MQTTTLSSettings       `json:"tls"`           // TLS/SSL configuration
+	Enabled       bool                  `json:"enabled"`                                                         // true to enable MQTT
+	Debug         bool                  `json:"debug"`                                                           // true to enable MQTT debug
+	Broker        string                `json:"broker"`                                                          // MQTT broker URL
+	Topic         string                `json:"topic"`                                                           // MQTT topic
+	Username      string                `json:"username"`                                                        // MQTT username
+	Password      string                `json:"password"`                                                        // MQTT password
+	Retain        bool                  `json:"retain"`                                                          // true to retain messages
+	RetrySettings RetrySettings         `json:"retrySettings"`                                                   // settings for retry mechanism
+	TLS           MQTTTLSSettings       `json:"tls"`                                                             // TLS/SSL configuration
 	HomeAssistant HomeAssistantSettings `yaml:"homeassistant" mapstructure:"homeassistant" json:"homeAssistant"` // Home Assistant auto-discovery settings
 }
 
@@ -478,9 +504,9 @@ type MQTTTLSSettings struct {
 
 // HomeAssistantSettings contains settings for Home Assistant MQTT auto-discovery.
 type HomeAssistantSettings struct {
-	Enabled         bool   `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                                    // true to enable HA auto-discovery
-	DiscoveryPrefix string `yaml:"discovery_prefix" mapstructure:"discovery_prefix" json:"discoveryPrefix"`          // HA discovery topic prefix (default: homeassistant)
-	DeviceName      string `yaml:"device_name" mapstructure:"device_name" json:"deviceName"`                         // base name for devices (default: BirdNET-Go)
+	Enabled         bool   `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                           // true to enable HA auto-discovery
+	DiscoveryPrefix string `yaml:"discovery_prefix" mapstructure:"discovery_prefix" json:"discoveryPrefix"` // HA discovery topic prefix (default: homeassistant)
+	DeviceName      string `yaml:"device_name" mapstructure:"device_name" json:"deviceName"`                // base name for devices (default: BirdNET-Go)
 }
 
 // TelemetrySettings contains settings for telemetry.
@@ -562,6 +588,17 @@ type RealtimeSettings struct {
 	Species          SpeciesSettings          `json:"species"`          // Custom thresholds and actions for species
 	Weather          WeatherSettings          `json:"weather"`          // Weather provider related settings
 	SpeciesTracking  SpeciesTrackingSettings  `json:"speciesTracking"`  // New species tracking settings
+
+	DetectionSinks []DetectionSinkSettings `json:"detectionSinks"` // Extra destinations approved detections are sent to
+}
+
+// DetectionSinkSettings configures one destination approved detections are sent to
+type DetectionSinkSettings struct {
+	Name    string `json:"name"`    // unique name of the sink, used in logs
+	Type    string `json:"type"`    // sink type: "webhook"
+	URL     string `json:"url"`     // URL webhook sinks post detections to
+	Filter  string `json:"filter"`  // detections the sink receives: "all" or "lifers"
+	Timeout int    `json:"timeout"` // seconds one delivery may take, 0 for the default
 }
 
 // SpeciesAction represents a single action configuration
@@ -602,6 +639,9 @@ type SpeciesTrackingSettings struct {
 	NewSpeciesWindowDays         int                      `json:"newSpeciesWindowDays"`         // Days to consider a species "new" (default: 14)
 	SyncIntervalMinutes          int                      `json:"syncIntervalMinutes"`          // Interval to sync with database (default: 60)
 	NotificationSuppressionHours int                      `json:"notificationSuppressionHours"` // Hours to suppress duplicate notifications (default: 168)
+	NotificationExclude          []string                 `json:"notificationExclude"`          // Common names, scientific names or genera that never trigger new species notifications
+	AnnouncementDedupMinutes     int                      `json:"announcementDedupMinutes"`     // Minutes an announced new species isn't announced again, even across restarts, 0 to disable
+	AnnouncementCachePath        string                   `json:"announcementCachePath"`        // File that persists recently announced species for the dedup window
 	YearlyTracking               YearlyTrackingSettings   `json:"yearlyTracking"`               // Settings for yearly species tracking
 	SeasonalTracking             SeasonalTrackingSettings `json:"seasonalTracking"`             // Settings for seasonal species tracking
 }
@@ -926,20 +966,237 @@ type BirdNETConfig struct {
 	LabelPath   string              `json:"labelPath,omitempty" yaml:"labelPath,omitempty"` // path to external label file (empty for embedded)
 	Labels      []string            `yaml:"-" json:"-"`                                     // list of available species labels, runtime value
 	UseXNNPACK  bool                `json:"useXnnpack"`                                     // true to use XNNPACK delegate for inference acceleration
+	TopK        int                 `json:"topK"`                                           // number of top species candidates included in detection events, 0 or 1 for the winner only
 }
 
 type SoundIdConfig struct {
-	Enabled        			bool    `json:"enabled"`        		// true to enable Sound ID
-	UiModelPath 			string 	`json:"uiModelPath"` 			// path to external ui spectrogram model file
-	LifeListPath 			string 	`json:"lifelistPath"` 			// path to external life list CSV file
-	BirdSingingThreshold    float64	`json:"birdsingingthreshold"`	// minimum confidence that a bird is present. samples below this threshold will not be processed
-	InitialThreshold 		float64	`json:"initialthreshold"`       // threshold needed to display a bird for the first time
-	UnlockedThreshold   	float64	`json:"unlockedthreshold"`      // threshold needed to update a bird after it's been displayed
-	MinDetectionsToUnlock	int    	`json:"mindetectionstounlock"`  // number of consecutive detections needed to display a bird for the first time
+	Enabled                      bool     `json:"enabled"`                      // true to enable Sound ID
+	UiModelPath                  string   `json:"uiModelPath"`                  // path to external ui spectrogram model file
+	DataDir                      string   `json:"dataDir"`                      // base directory relative life list paths are resolved against, empty for the config directory
+	LifeListPath                 string   `json:"lifelistPath"`                 // path or http(s) URL of external life list CSV file, overridden by the BIRDNET_LIFE_LIST_PATH environment variable when set
+	LifeListPaths                []string `json:"lifelistPaths"`                // more paths or URLs of life list CSV files merged with the one at lifelistPath, such as lists kept per region or year; only lifelistPath is watched, refreshed and appended to
+	LifeListSkipUnreadable       bool     `json:"lifelistSkipUnreadable"`       // true to load the remaining life list files when one of several can't be read instead of failing the load
+	LifeListRefreshInterval      int      `json:"lifelistRefreshInterval"`      // seconds between reloads of a URL life list, 0 to disable
+	LifeListWatch                bool     `json:"lifelistWatch"`                // true to reload a local life list file when it changes on disk
+	LifeListEncoding             string   `json:"lifelistEncoding"`             // character encoding of the life list file: "utf-8", "latin1" or "windows-1252"
+	LifeListFormat               string   `json:"lifelistFormat"`               // column layout of the life list file: "auto" to detect it from the header, "ebird" for My eBird Data, "merlin" for a Merlin or eBird life list export, "legacy", or "json"
+	LifeListScientificNameColumn int      `json:"lifelistScientificNameColumn"` // zero-based column holding the scientific name in "legacy" format life lists; other formats reject a non-default value
+	LifeListCommonNameColumn     int      `json:"lifelistCommonNameColumn"`     // zero-based column holding the common name in "legacy" format life lists, -1 if the file has none
+	LifeListCollisionPolicy      string   `json:"lifelistCollisionPolicy"`      // what to do when two life list entries normalize to the same name: "merge-silently", "warn" or "keep-both-via-original"
+	LifeListStatusPath           string   `json:"lifelistStatusPath"`           // file that persists heard/seen status of life list species, empty to keep it in memory only
+	LifeListAuditEnabled         bool     `json:"lifelistAuditEnabled"`         // true to keep an in-memory log of life list lookups for potential lifers
+	LifeListAuthorityEnabled     bool     `json:"lifelistAuthorityEnabled"`     // true to canonicalize life list names against the authority file
+	LifeListAuthorityPath        string   `json:"lifelistAuthorityPath"`        // path or http(s) URL of the taxonomy authority CSV: preferred scientific name, then its synonyms
+	LifeListTaxonomyVersion      string   `json:"lifelistTaxonomyVersion"`      // taxonomy version the life list follows, such as "eBird 2023", compared with that of the model at load; overrides a "# taxonomy:" comment at the top of the file
+	LifeListSynonymsPath         string   `json:"lifelistSynonymsPath"`         // path or http(s) URL of a CSV mapping old scientific names to current ones, applied to life list entries and lookups alike, empty to disable
+	LifeListGroupMatching        bool     `json:"lifelistGroupMatching"`        // true to keep entries like "Accipiter sp." or "Larus x" as matchers for any species or hybrid of the genus, false to skip them
+	LifeListSkipMalformed        bool     `json:"lifelistSkipMalformed"`        // true to skip life list rows too short to hold a scientific name instead of failing the load
+	LifeListFuzzy                bool     `json:"lifelistFuzzy"`                // true to match life list names ignoring repeated whitespace and subspecies, false to match the lowercased name exactly
+	LifeListReviewMode           bool     `json:"lifelistReviewMode"`           // true to queue detected species missing from the life list for confirmation instead of adding them as heard
+	LifeListMaxSizeMB            int      `json:"lifelistMaxSizeMB"`            // largest life list or authority file accepted at load, in MB, 0 for no limit
+	LifeListMaxEntries           int      `json:"lifelistMaxEntries"`           // most species a life list may hold once its files are merged, 0 for no limit
+	LifeListAlertThreshold       float64  `json:"lifelistAlertThreshold"`       // lowest confidence of an approved detection that raises a new species alert for a species missing from the life list, 0 to alert on every one
+	BigDayEnabled                bool     `json:"bigDayEnabled"`                // true to save a summary of each day's species when the day ends
+	BigDayPath                   string   `json:"bigDayPath"`                   // file that stores the saved big day summaries
+	BirdSingingThreshold         float64  `json:"birdsingingthreshold"`         // minimum confidence that a bird is present. samples below this threshold will not be processed
+	InitialThreshold             float64  `json:"initialthreshold"`             // threshold needed to display a bird for the first time
+	UnlockedThreshold            float64  `json:"unlockedthreshold"`            // threshold needed to update a bird after it's been displayed
+	MinDetectionsToUnlock        int      `json:"mindetectionstounlock"`        // number of consecutive detections needed to display a bird for the first time
+	EmptyNamePolicy              string   `json:"emptyNamePolicy"`              // how to handle detections with a blank scientific or common name: "drop" or "tag"
+
+	UiSpectrogram UiSpectrogramSettings `json:"uiSpectrogram"` // live UI spectrogram post-processing
+}
+
+// UiSpectrogramSettings contains post-processing options applied to live UI spectrogram
+// frames before they are broadcast to clients.
+type UiSpectrogramSettings struct {
+	Mode                    string  `json:"mode"`                    // "normal" or "difference"
+	DifferenceAdaptRate     float64 `json:"differenceAdaptRate"`     // per-frame EMA rate (0-1] of the difference mode background baseline
+	MaxFrameBytes           int     `json:"maxFrameBytes"`           // cap on the base64-encoded frame size; larger frames are down-resolved
+	MinFrameInterval        int     `json:"minFrameInterval"`        // minimum milliseconds between a source's frame timestamps; earlier timestamps are nudged forward
+	SkipStaleFrames         bool    `json:"skipStaleFrames"`         // true to skip queued frames down to each source's newest when the publisher falls behind
+	BatchSize               int     `json:"batchSize"`               // frames coalesced into one stream event, 0 or 1 to send each frame on its own
+	BatchInterval           int     `json:"batchInterval"`           // milliseconds a partial batch of frames waits before it is sent anyway
+	HeartbeatInterval       int     `json:"heartbeatInterval"`       // seconds without frames before a heartbeat event is sent on the stream, 0 to disable
+	RecentFrames            int     `json:"recentFrames"`            // most recent frames sent to a newly connected stream client so the view isn't blank, 0 to disable
+	CollapseFrames          bool    `json:"collapseFrames"`          // true to skip broadcasting frames that match the previous frame of their source, such as during silence
+	CollapseTolerance       int     `json:"collapseTolerance"`       // largest difference of a column's mean level (0-255) for two frames to count as matching
+	CollapseKeepalive       int     `json:"collapseKeepalive"`       // seconds after which a matching frame is broadcast anyway, 0 for the default of 5
+	DetectionTriggered      bool    `json:"detectionTriggered"`      // true to produce frames only around detections instead of continuously
+	TriggerPreRoll          int     `json:"triggerPreRoll"`          // milliseconds of frames from before a detection that are sent when it starts
+	TriggerHold             int     `json:"triggerHold"`             // milliseconds frames keep flowing after the latest detection
+	ClockResyncInterval     int     `json:"clockResyncInterval"`     // seconds between resyncing sample-count frame timestamps to the wall clock, 0 to use the wall clock directly
+	ClockMaxCorrection      int     `json:"clockMaxCorrection"`      // maximum milliseconds one resync moves the frame timestamp base
+	MsPerColumn             float64 `json:"msPerColumn"`             // time between spectrogram columns in ms, 0 for columns that don't overlap
+	OverflowStrategy        string  `json:"overflowStrategy"`        // what the producer does when the spectrogram channel is full: "drop-newest", "drop-oldest" or "block"
+	StallTimeout            int     `json:"stallTimeout"`            // milliseconds without new samples before a source is reported stalled, 0 to disable
+	BinAggregation          int     `json:"binAggregation"`          // number of adjacent FFT bins merged into one before display, 0 or 1 to disable
+	BinAggregationMode      string  `json:"binAggregationMode"`      // how merged bins are combined: "max" or "sum"
+	SpectrogramBins         int     `json:"spectrogramBins"`         // bins per column broadcast to clients, averaged down from the native count for low-bandwidth links, 0 for the native count
+	SpectrogramMinFreqHz    float64 `json:"spectrogramMinFreqHz"`    // lower edge in Hz of the frequency band broadcast to clients, bins below it are cropped; 0 for the lowest bin
+	SpectrogramMaxFreqHz    float64 `json:"spectrogramMaxFreqHz"`    // upper edge in Hz of the band broadcast to clients, 0 for the Nyquist frequency
+	SpectrogramEncoding     string  `json:"spectrogramEncoding"`     // payload encoding of frames on the spectrogram stream: "json" or "binary"
+	SpectrogramSSEPath      string  `json:"spectrogramSSEPath"`      // route of the spectrogram SSE stream within /api/v2, starting with "/"
+	SpectrogramSSEEventName string  `json:"spectrogramSSEEventName"` // SSE event name of single frames on the spectrogram stream
+	SpectrogramSSECompress  bool    `json:"spectrogramSSECompress"`  // true to gzip the spectrogram stream for clients that accept it, at some CPU cost
+	SpectrogramMaxFPS       int     `json:"spectrogramMaxFPS"`       // most frames per second broadcast for each source, keeping the newest; 0 for no limit
+	DrainOnStop             bool    `json:"drainOnStop"`             // true to broadcast the frames still queued when monitoring stops, so a restart hands over cleanly
+	LatestWins              bool    `json:"latestWins"`              // true to keep only the newest frame while clients are behind, discarding those in between; ignored when frames are batched
+	Palette                 string  `json:"palette"`                 // display color palette: "grayscale", "viridis", "magma" or "inferno"
+
+	SourcePalettes map[string]string `json:"sourcePalettes"` // palette per source ID, overriding palette so sources can be told apart
+
+	PreEmphasisEnabled     bool    `json:"preEmphasisEnabled"`     // true to high-pass the audio before the spectrogram FFT
+	PreEmphasisCoefficient float64 `json:"preEmphasisCoefficient"` // filter coefficient (0-1), higher boosts high frequencies more
+
+	CalibrationGains     map[string]float64 `json:"calibrationGains"`     // dB applied to each source's samples before the FFT, by source ID, set by capturing a calibration tone on the source
+	CalibrationReference float64            `json:"calibrationReference"` // dBFS level a captured calibration tone is displayed at
+	CalibrationToneLevel float64            `json:"calibrationToneLevel"` // known level of the last calibration tone, e.g. in dB SPL, so displayed levels can be labeled
+
+	AudioStreamEnabled    bool `json:"audioStreamEnabled"`    // true to attach downsampled audio to each frame so clients can listen along
+	AudioStreamSampleRate int  `json:"audioStreamSampleRate"` // target sample rate of the attached audio in Hz, rounded to a whole fraction of the capture rate
+
+	MarkerLabelThreshold float64 `json:"markerLabelThreshold"` // minimum confidence for detection markers to carry the species label
+	CombinedMarkers      bool    `json:"combinedMarkers"`      // true to send each detection's metadata inline with its marker as one event on the spectrogram stream
+
+	SpectralFeaturesEnabled bool    `json:"spectralFeaturesEnabled"` // true to compute spectral centroid and bandwidth for each frame
+	SpectralFeaturesMinHz   float64 `json:"spectralFeaturesMinHz"`   // lower edge of the band the features are computed over
+	SpectralFeaturesMaxHz   float64 `json:"spectralFeaturesMaxHz"`   // upper edge of the band, 0 for the Nyquist frequency
+
+	MQTTEnabled  bool   `json:"mqttEnabled"`  // true to publish periodic frame summaries (peak frequency, RMS) to the realtime MQTT broker
+	MQTTTopic    string `json:"mqttTopic"`    // topic for the summaries, empty for "<realtime.mqtt.topic>/spectrogram"
+	MQTTInterval int    `json:"mqttInterval"` // minimum seconds between a source's published summaries
+
+	RestartErrorRate   float64 `json:"restartErrorRate"`   // broadcast failure rate (0-1) that restarts spectrogram monitoring, 0 to disable
+	RestartErrorWindow int     `json:"restartErrorWindow"` // seconds the failure rate must be sustained before a restart
+	RestartBackoff     int     `json:"restartBackoff"`     // minimum seconds between restarts, doubled after each consecutive restart
+
+	SpectrogramRecordDir      string `json:"spectrogramRecordDir"`      // directory the most recent frames are kept in on disk for debugging missed detections, empty to disable
+	SpectrogramRecordMaxBytes int64  `json:"spectrogramRecordMaxBytes"` // most bytes of frames kept in the record directory, deleting the oldest files first
+
+	Video UiSpectrogramVideoSettings `json:"video"` // time-lapse video recording of the spectrogram
+}
+
+// UiSpectrogramVideoSettings contains options for piping rendered spectrogram frames to
+// ffmpeg, producing a time-lapse video of the live spectrogram
+type UiSpectrogramVideoSettings struct {
+	Enabled         bool   `json:"enabled"`         // true to record the spectrogram to video files
+	Path            string `json:"path"`            // output file path; a start timestamp is added to each file name, and the extension (.mp4 or .webm) selects the codec
+	Source          string `json:"source"`          // source ID to record, empty for the first source that sends frames
+	FPS             int    `json:"fps"`             // playback frame rate of the video
+	CaptureInterval int    `json:"captureInterval"` // milliseconds of spectrogram between captured video frames, 0 to capture every frame
+	Width           int    `json:"width"`           // number of spectrogram columns in each video frame
+	RolloverMinutes int    `json:"rolloverMinutes"` // minutes after which a new file is started, 0 to disable
+	RolloverSizeMB  int    `json:"rolloverSizeMB"`  // file size in MB after which a new file is started, 0 to disable
+}
+
+// UI spectrogram column timing limits for UiSpectrogramSettings.MsPerColumn
+const (
+	// UiSpectrogramWindowSize is the number of samples behind each UI spectrogram column
+	UiSpectrogramWindowSize = 512
+
+	// UiSpectrogramMaxMsPerColumn is the duration of one column window; a longer hop would skip audio
+	UiSpectrogramMaxMsPerColumn = UiSpectrogramWindowSize * 1000.0 / SampleRate
+	// UiSpectrogramMinMsPerColumn overlaps windows eightfold, bounding the FFTs computed per frame
+	UiSpectrogramMinMsPerColumn = UiSpectrogramMaxMsPerColumn / 8
+)
+
+// UI spectrogram palettes for UiSpectrogramSettings.Palette
+const (
+	UiSpectrogramPaletteGrayscale = "grayscale"
+	UiSpectrogramPaletteViridis   = "viridis"
+	UiSpectrogramPaletteMagma     = "magma"
+	UiSpectrogramPaletteInferno   = "inferno"
+
+	// DefaultUiSpectrogramPalette is used when no palette or an unknown one is configured
+	DefaultUiSpectrogramPalette = UiSpectrogramPaletteGrayscale
+)
+
+// UiSpectrogramPalettes lists the valid UiSpectrogramSettings.Palette names.
+var UiSpectrogramPalettes = []string{UiSpectrogramPaletteGrayscale, UiSpectrogramPaletteViridis, UiSpectrogramPaletteMagma, UiSpectrogramPaletteInferno}
+
+// SourcePalette returns the palette configured for a source, or the global palette when
+// the source has none.
+func (s *UiSpectrogramSettings) SourcePalette(source string) string {
+	if palette := s.SourcePalettes[source]; palette != "" {
+		return palette
+	}
+	return s.Palette
 }
 
+// UI spectrogram overflow strategies for UiSpectrogramSettings.OverflowStrategy
+const (
+	UiSpectrogramOverflowDropNewest = "drop-newest" // discard the new frame, keeping the queued backlog
+	UiSpectrogramOverflowDropOldest = "drop-oldest" // discard the oldest queued frame to make room, favoring fresh frames
+	UiSpectrogramOverflowBlock      = "block"       // wait briefly for room, slowing the producer, then drop the oldest queued frame
+)
+
+// UI spectrogram bin aggregation modes for UiSpectrogramSettings.BinAggregationMode
+const (
+	UiSpectrogramAggregateMax = "max" // loudest bin of each group, keeps short calls visible
+	UiSpectrogramAggregateSum = "sum" // saturating sum of each group, emphasizes bands with broad energy
+)
+
+// UI spectrogram stream encodings for UiSpectrogramSettings.SpectrogramEncoding
+const (
+	UiSpectrogramEncodingJSON   = "json"   // each frame is a JSON object
+	UiSpectrogramEncodingBinary = "binary" // each frame is its compact binary layout, base64 encoded
+)
+
+// UiSpectrogramEncodings lists the accepted UiSpectrogramSettings.SpectrogramEncoding values
+var UiSpectrogramEncodings = []string{UiSpectrogramEncodingJSON, UiSpectrogramEncodingBinary}
+
+// Defaults of UiSpectrogramSettings.SpectrogramSSEPath and SpectrogramSSEEventName, used
+// when they are empty or invalid
+const (
+	DefaultUiSpectrogramSSEPath      = "/spectrogram/stream"
+	DefaultUiSpectrogramSSEEventName = "ui_spectrogram"
+)
+
+// DefaultUiSpectrogramRecordMaxBytes is the UiSpectrogramSettings.SpectrogramRecordMaxBytes
+// used when it isn't positive
+const DefaultUiSpectrogramRecordMaxBytes = 64 << 20
+
+// UI spectrogram display modes for UiSpectrogramSettings.Mode
+const (
+	UiSpectrogramModeNormal     = "normal"     // frames are broadcast as generated
+	UiSpectrogramModeDifference = "difference" // a rolling per-bin background baseline is subtracted
+)
+
+// Life list collision policies for SoundIdConfig.LifeListCollisionPolicy
+const (
+	LifeListCollisionMergeSilently = "merge-silently"         // merge the entries without logging
+	LifeListCollisionWarn          = "warn"                   // merge the entries and log both original names
+	LifeListCollisionKeepBoth      = "keep-both-via-original" // keep the later entry under its original, unnormalized name
+)
+
+// LifeListCollisionPolicies lists the accepted SoundIdConfig.LifeListCollisionPolicy values
+var LifeListCollisionPolicies = []string{LifeListCollisionMergeSilently, LifeListCollisionWarn, LifeListCollisionKeepBoth}
+
+// Life list file formats for SoundIdConfig.LifeListFormat
+const (
+	LifeListFormatAuto   = "auto"   // detect the columns from the header row, falling back to the Merlin layout
+	LifeListFormatEBird  = "ebird"  // eBird's "My eBird Data" download
+	LifeListFormatMerlin = "merlin" // Merlin's life list export, which shares eBird's life list layout
+	LifeListFormatLegacy = "legacy" // fixed columns ignoring any header, with the scientific and common names in LifeListScientificNameColumn and LifeListCommonNameColumn
+	LifeListFormatJSON   = "json"   // a JSON array of scientific names or of objects with a scientificName field; auto detects it from a .json extension
+)
+
+// LifeListFormats lists the accepted SoundIdConfig.LifeListFormat values
+var LifeListFormats = []string{LifeListFormatAuto, LifeListFormatEBird, LifeListFormatMerlin, LifeListFormatLegacy, LifeListFormatJSON}
+
+// DefaultLifeListScientificNameColumn is the scientific name column of Merlin life lists,
+// the default of SoundIdConfig.LifeListScientificNameColumn
+const DefaultLifeListScientificNameColumn = 4
+
+// Empty species name policies for SoundIdConfig.EmptyNamePolicy
+const (
+	EmptyNamePolicyDrop = "drop" // discard detections with a blank scientific or common name
+	EmptyNamePolicyTag  = "tag"  // keep them under placeholder names
+)
+
 type SpectrogramSettings struct {
-	ModelPath   string `json:"modelPath"`   // path to external spectrogram model file
+	ModelPath string `json:"modelPath"` // path to external spectrogram model file
 }
 
 // RangeFilterSettings contains settings for the range filter
@@ -1039,6 +1296,24 @@ type WebServerSettings struct {
 	Enabled    bool               `json:"enabled"`    // true to enable web server
 	Port       string             `json:"port"`       // port for web server
 	LiveStream LiveStreamSettings `json:"liveStream"` // live stream configuration
+
+	SSEKeepalive SSEKeepaliveSettings `json:"sseKeepalive"` // heartbeat cadence of SSE streams
+
+	InvalidStreamParams string `json:"invalidStreamParams"` // how spectrogram streams treat malformed query parameters: "reject" with a 400, or "ignore" and use defaults
+}
+
+// Treatments of malformed spectrogram stream query parameters
+const (
+	InvalidStreamParamsReject = "reject" // respond with 400 naming the parameter
+	InvalidStreamParamsIgnore = "ignore" // log the parameter and use its default
+)
+
+// SSEKeepaliveSettings bounds the heartbeat interval SSE clients can request with the
+// keepalive query parameter.
+type SSEKeepaliveSettings struct {
+	Default int `json:"default"` // seconds between heartbeats for clients that don't request an interval
+	Min     int `json:"min"`     // shortest interval in seconds a client may request
+	Max     int `json:"max"`     // longest interval in seconds a client may request
 }
 
 type LiveStreamSettings struct {
@@ -72,6 +72,7 @@ func setDefaultConfig() {
 	viper.SetDefault("birdnet.modelpath", "")
 	viper.SetDefault("birdnet.labelpath", "")
 	viper.SetDefault("birdnet.usexnnpack", true)
+	viper.SetDefault("birdnet.topk", 0)
 
 	// Range filter configuration
 	viper.SetDefault("birdnet.rangefilter.debug", false)
@@ -85,6 +86,7 @@ func setDefaultConfig() {
 	// Audio source configuration
 	viper.SetDefault("realtime.audio.source", "sysdefault")
 	viper.SetDefault("realtime.audio.streamtransport", "sse")
+	viper.SetDefault("realtime.audio.startupskip", 0)
 
 	// Sound level monitoring configuration
 	viper.SetDefault("realtime.audio.soundlevel.enabled", false)
@@ -130,16 +132,20 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.dashboard.thumbnails.imageprovider", "avicommons")
 	viper.SetDefault("realtime.dashboard.thumbnails.fallbackpolicy", "none")
 	viper.SetDefault("realtime.dashboard.summarylimit", 30)
-	viper.SetDefault("realtime.dashboard.locale", "en")               // Default UI locale
-	viper.SetDefault("realtime.dashboard.temperatureunit", "celsius") // Temperature display unit: "celsius" or "fahrenheit"
+	viper.SetDefault("realtime.dashboard.locale", "en")                   // Default UI locale
+	viper.SetDefault("realtime.dashboard.temperatureunit", "celsius")     // Temperature display unit: "celsius" or "fahrenheit"
+	viper.SetDefault("realtime.dashboard.namedisplay", NameDisplayCommon) // Species name display: "common", "scientific" or "both"
 
 	// Spectrogram pre-rendering configuration
-	viper.SetDefault("realtime.dashboard.spectrogram.enabled", false)                        // Opt-in for safety
-	viper.SetDefault("realtime.dashboard.spectrogram.mode", "auto")                          // Default to auto mode (generate on demand)
-	viper.SetDefault("realtime.dashboard.spectrogram.size", "sm")                            // 400px, matches frontend RecentDetectionsCard
-	viper.SetDefault("realtime.dashboard.spectrogram.raw", true)                             // Raw spectrogram (no axes/legend)
-	viper.SetDefault("realtime.dashboard.spectrogram.style", "default")                      // Visual style preset
+	viper.SetDefault("realtime.dashboard.spectrogram.enabled", false)                                // Opt-in for safety
+	viper.SetDefault("realtime.dashboard.spectrogram.mode", "auto")                                  // Default to auto mode (generate on demand)
+	viper.SetDefault("realtime.dashboard.spectrogram.size", "sm")                                    // 400px, matches frontend RecentDetectionsCard
+	viper.SetDefault("realtime.dashboard.spectrogram.raw", true)                                     // Raw spectrogram (no axes/legend)
+	viper.SetDefault("realtime.dashboard.spectrogram.style", "default")                              // Visual style preset
 	viper.SetDefault("realtime.dashboard.spectrogram.dynamicrange", SpectrogramDynamicRangeStandard) // Dynamic range in dB (100 = standard)
+	viper.SetDefault("realtime.dashboard.spectrogram.retention.maxcount", 0)                         // No count limit
+	viper.SetDefault("realtime.dashboard.spectrogram.retention.maxage", "")                          // No age limit
+	viper.SetDefault("realtime.dashboard.spectrogram.retention.checkinterval", 60)                   // Hourly cleanup
 
 	// Retention policy configuration
 	viper.SetDefault("realtime.audio.export.retention.enabled", true)
@@ -280,6 +286,9 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.speciestracking.newspecieswindowdays", 7)
 	viper.SetDefault("realtime.speciestracking.syncintervalminutes", 60)
 	viper.SetDefault("realtime.speciestracking.notificationsuppressionhours", 168) // 7 days
+	viper.SetDefault("realtime.speciestracking.notificationexclude", []string{})
+	viper.SetDefault("realtime.speciestracking.announcementdedupminutes", 60)
+	viper.SetDefault("realtime.speciestracking.announcementcachepath", "recent_announcements.json")
 
 	// Yearly tracking defaults
 	viper.SetDefault("realtime.speciestracking.yearlytracking.enabled", true)
@@ -312,6 +321,10 @@ func setDefaultConfig() {
 	viper.SetDefault("webserver.livestream.sampleRate", 22050)
 	viper.SetDefault("webserver.livestream.segmentLength", 2)
 	viper.SetDefault("webserver.livestream.ffmpegLogLevel", "warning")
+	viper.SetDefault("webserver.ssekeepalive.default", 30)
+	viper.SetDefault("webserver.ssekeepalive.min", 5)
+	viper.SetDefault("webserver.ssekeepalive.max", 300)
+	viper.SetDefault("webserver.invalidstreamparams", InvalidStreamParamsReject)
 
 	// File output configuration
 	viper.SetDefault("output.file.enabled", true)
@@ -392,9 +405,100 @@ func setDefaultConfig() {
 
 	viper.SetDefault("notification.push.providers", []map[string]any{})
 
+	// Detection digest configuration
+	viper.SetDefault("notification.digest.enabled", false)
+	viper.SetDefault("notification.digest.interval", 15)
+
 	// Notification templates
 	viper.SetDefault("notification.templates.newspecies.title", "New Species: {{.CommonName}}")
 	viper.SetDefault("notification.templates.newspecies.message", "{{.ImageURL}}\n\nFirst detection of {{.CommonName}} ({{.ScientificName}}) with {{.ConfidencePercent}}% confidence at {{.DetectionTime}}. \n{{.DetectionURL}}")
+
+	// Sound ID configuration
+	viper.SetDefault("soundid.datadir", "")
+	viper.SetDefault("soundid.lifelistpaths", []string{})
+	viper.SetDefault("soundid.lifelistskipunreadable", false)
+	viper.SetDefault("soundid.lifelistrefreshinterval", 0)
+	viper.SetDefault("soundid.lifelistwatch", false)
+	viper.SetDefault("soundid.lifelistencoding", "utf-8")
+	viper.SetDefault("soundid.lifelistformat", LifeListFormatAuto)
+	viper.SetDefault("soundid.lifelistscientificnamecolumn", DefaultLifeListScientificNameColumn)
+	viper.SetDefault("soundid.lifelistcommonnamecolumn", 3)
+	viper.SetDefault("soundid.lifelistcollisionpolicy", LifeListCollisionWarn)
+	viper.SetDefault("soundid.lifeliststatuspath", "lifelist_status.json")
+	viper.SetDefault("soundid.lifelistauditenabled", false)
+	viper.SetDefault("soundid.lifelistauthorityenabled", false)
+	viper.SetDefault("soundid.lifelistauthoritypath", "")
+	viper.SetDefault("soundid.lifelistsynonymspath", "")
+	viper.SetDefault("soundid.lifelisttaxonomyversion", "")
+	viper.SetDefault("soundid.lifelistgroupmatching", false)
+	viper.SetDefault("soundid.lifelistskipmalformed", false)
+	viper.SetDefault("soundid.lifelistfuzzy", false)
+	viper.SetDefault("soundid.lifelistmaxsizemb", 50)
+	viper.SetDefault("soundid.lifelistmaxentries", 500000)
+	viper.SetDefault("soundid.lifelistalertthreshold", 0.0)
+	viper.SetDefault("soundid.bigdayenabled", false)
+	viper.SetDefault("soundid.bigdaypath", "bigday_summaries.json")
+	viper.SetDefault("soundid.emptynamepolicy", EmptyNamePolicyDrop)
+	viper.SetDefault("soundid.uispectrogram.mode", UiSpectrogramModeNormal)
+	viper.SetDefault("soundid.uispectrogram.differenceadaptrate", 0.02)
+	viper.SetDefault("soundid.uispectrogram.maxframebytes", 65536)
+	viper.SetDefault("soundid.uispectrogram.minframeinterval", 1)
+	viper.SetDefault("soundid.uispectrogram.skipstaleframes", true)
+	viper.SetDefault("soundid.uispectrogram.batchsize", 0)
+	viper.SetDefault("soundid.uispectrogram.batchinterval", 100)
+	viper.SetDefault("soundid.uispectrogram.heartbeatinterval", 15)
+	viper.SetDefault("soundid.uispectrogram.recentframes", 0)
+	viper.SetDefault("soundid.uispectrogram.collapseframes", false)
+	viper.SetDefault("soundid.uispectrogram.collapsetolerance", 1)
+	viper.SetDefault("soundid.uispectrogram.collapsekeepalive", 5)
+	viper.SetDefault("soundid.uispectrogram.detectiontriggered", false)
+	viper.SetDefault("soundid.uispectrogram.triggerpreroll", 2000)
+	viper.SetDefault("soundid.uispectrogram.triggerhold", 5000)
+	viper.SetDefault("soundid.uispectrogram.clockresyncinterval", 0)
+	viper.SetDefault("soundid.uispectrogram.clockmaxcorrection", 100)
+	viper.SetDefault("soundid.uispectrogram.mspercolumn", 0)
+	viper.SetDefault("soundid.uispectrogram.overflowstrategy", UiSpectrogramOverflowDropNewest)
+	viper.SetDefault("soundid.uispectrogram.stalltimeout", 5000)
+	viper.SetDefault("soundid.uispectrogram.binaggregation", 0)
+	viper.SetDefault("soundid.uispectrogram.binaggregationmode", UiSpectrogramAggregateMax)
+	viper.SetDefault("soundid.uispectrogram.spectrogrambins", 0)
+	viper.SetDefault("soundid.uispectrogram.spectrogramminfreqhz", 0.0)
+	viper.SetDefault("soundid.uispectrogram.spectrogrammaxfreqhz", 0.0)
+	viper.SetDefault("soundid.uispectrogram.spectrogramencoding", UiSpectrogramEncodingJSON)
+	viper.SetDefault("soundid.uispectrogram.spectrogramssepath", DefaultUiSpectrogramSSEPath)
+	viper.SetDefault("soundid.uispectrogram.spectrogramsseeventname", DefaultUiSpectrogramSSEEventName)
+	viper.SetDefault("soundid.uispectrogram.spectrogramssecompress", false)
+	viper.SetDefault("soundid.uispectrogram.spectrogrammaxfps", 0)
+	viper.SetDefault("soundid.uispectrogram.drainonstop", false)
+	viper.SetDefault("soundid.uispectrogram.latestwins", false)
+	viper.SetDefault("soundid.uispectrogram.palette", DefaultUiSpectrogramPalette)
+	viper.SetDefault("soundid.uispectrogram.preemphasisenabled", false)
+	viper.SetDefault("soundid.uispectrogram.preemphasiscoefficient", 0.97)
+	viper.SetDefault("soundid.uispectrogram.calibrationreference", -20.0)
+	viper.SetDefault("soundid.uispectrogram.calibrationtonelevel", 0.0)
+	viper.SetDefault("soundid.uispectrogram.audiostreamenabled", false)
+	viper.SetDefault("soundid.uispectrogram.audiostreamsamplerate", 11025)
+	viper.SetDefault("soundid.uispectrogram.markerlabelthreshold", 0.8)
+	viper.SetDefault("soundid.uispectrogram.combinedmarkers", false)
+	viper.SetDefault("soundid.uispectrogram.spectralfeaturesenabled", false)
+	viper.SetDefault("soundid.uispectrogram.spectralfeaturesminhz", 0)
+	viper.SetDefault("soundid.uispectrogram.spectrogramrecorddir", "")
+	viper.SetDefault("soundid.uispectrogram.spectrogramrecordmaxbytes", DefaultUiSpectrogramRecordMaxBytes)
+	viper.SetDefault("soundid.uispectrogram.spectralfeaturesmaxhz", 0)
+	viper.SetDefault("soundid.uispectrogram.mqttenabled", false)
+	viper.SetDefault("soundid.uispectrogram.mqtttopic", "")
+	viper.SetDefault("soundid.uispectrogram.mqttinterval", 5)
+	viper.SetDefault("soundid.uispectrogram.restarterrorrate", 0.9)
+	viper.SetDefault("soundid.uispectrogram.restarterrorwindow", 30)
+	viper.SetDefault("soundid.uispectrogram.restartbackoff", 60)
+	viper.SetDefault("soundid.uispectrogram.video.enabled", false)
+	viper.SetDefault("soundid.uispectrogram.video.path", "spectrogram-video/spectrogram.mp4")
+	viper.SetDefault("soundid.uispectrogram.video.source", "")
+	viper.SetDefault("soundid.uispectrogram.video.fps", 30)
+	viper.SetDefault("soundid.uispectrogram.video.captureinterval", 1000)
+	viper.SetDefault("soundid.uispectrogram.video.width", 512)
+	viper.SetDefault("soundid.uispectrogram.video.rolloverminutes", 0)
+	viper.SetDefault("soundid.uispectrogram.video.rolloversizemb", 0)
 }
 
 // setModuleLogDefaults sets default values for a module log configuration
@@ -38,6 +38,9 @@ const (
 	ConfigKeyRangeFilterModelPath = "birdnet.rangefilter.modelpath"
 	ConfigKeyRangeFilterDebug     = "birdnet.rangefilter.debug"
 
+	// Sound ID Configuration
+	ConfigKeyLifeListPath = "soundid.lifelistpath"
+
 	// Security Configuration
 	ConfigKeyBaseURL = "security.baseurl"
 )
@@ -65,6 +68,9 @@ const (
 	EnvVarRangeFilterModelPath = "BIRDNET_RANGEFILTER_MODELPATH"
 	EnvVarRangeFilterDebug     = "BIRDNET_RANGEFILTER_DEBUG"
 
+	// Sound ID Configuration
+	EnvVarLifeListPath = "BIRDNET_LIFE_LIST_PATH"
+
 	// Security Configuration
 	EnvVarBaseURL = "BIRDNET_URL"
 )
@@ -124,6 +130,9 @@ func getEnvBindings() []envBinding {
 		{ConfigKeyRangeFilterModelPath, EnvVarRangeFilterModelPath, validateEnvPath},
 		{ConfigKeyRangeFilterDebug, EnvVarRangeFilterDebug, validateEnvBool},
 
+		// Sound ID Configuration
+		{ConfigKeyLifeListPath, EnvVarLifeListPath, validateEnvLifeListPath},
+
 		// Security Configuration
 		{ConfigKeyBaseURL, EnvVarBaseURL, validateEnvBaseURL},
 	}
@@ -310,6 +319,39 @@ func validateEnvPath(value string) error {
 	return nil
 }
 
+// validateEnvLifeListPath validates the BIRDNET_LIFE_LIST_PATH environment variable, which
+// may be an http(s) URL or a file path. Unlike the model paths, relative paths are allowed and
+// resolved against the working directory.
+func validateEnvLifeListPath(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("life list path must not be empty")
+	}
+	return nil
+}
+
+// resolveEnvLifeListPath expands a leading ~ to the home directory and makes a relative life
+// list path absolute against the working directory. URLs are returned unchanged, as is the
+// path when the home or working directory can't be determined.
+func resolveEnvLifeListPath(path string) string {
+	if IsLifeListURL(path) {
+		return path
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+
+	resolved, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return resolved
+}
+
 // validateEnvBaseURL validates the BIRDNET_URL environment variable
 // The URL must include a scheme (http or https) and a valid hostname
 func validateEnvBaseURL(value string) error {
@@ -372,6 +414,10 @@ func canonicalizeValue(configKey, envValue string) {
 		// Regular string values - just trim whitespace
 		viper.Set(configKey, trimmed)
 
+	case ConfigKeyLifeListPath:
+		// Resolve here, so the path doesn't depend on the data directory like configured ones
+		viper.Set(configKey, resolveEnvLifeListPath(trimmed))
+
 	case ConfigKeyBaseURL:
 		// Trim whitespace and trailing slash for consistent URL format
 		viper.Set(configKey, strings.TrimSuffix(trimmed, "/"))
@@ -858,3 +858,51 @@ func TestValidateEnvBaseURL_Integration(t *testing.T) {
 		assert.Contains(t, err.Error(), "must be http or https")
 	})
 }
+
+func TestLifeListPathEnvOverridesConfig(t *testing.T) {
+	workDir, err := os.Getwd()
+	require.NoError(t, err)
+	homeDir, err := os.UserHomeDir()
+	require.NoError(t, err)
+	configured := filepath.Join(t.TempDir(), "configured.csv")
+
+	tests := []struct {
+		name     string
+		envValue string
+		expected string
+	}{
+		{"absolute path", "/data/lifelist.csv", filepath.Clean("/data/lifelist.csv")},
+		{"relative path resolves against working directory", "lists/lifelist.csv", filepath.Join(workDir, "lists", "lifelist.csv")},
+		{"home directory is expanded", "~/lifelist.csv", filepath.Join(homeDir, "lifelist.csv")},
+		{"URL is kept", "https://example.com/lifelist.csv", "https://example.com/lifelist.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			viper.SetConfigType("yaml")
+			require.NoError(t, viper.ReadConfig(strings.NewReader(fmt.Sprintf("soundid:\n  lifelistpath: %q\n", configured))))
+			t.Setenv(EnvVarLifeListPath, tt.envValue)
+
+			require.NoError(t, configureEnvironmentVariables())
+
+			var settings Settings
+			require.NoError(t, viper.Unmarshal(&settings))
+			assert.Equal(t, tt.expected, settings.SoundId.LifeListPath, "the environment variable takes precedence over the config file")
+		})
+	}
+
+	t.Run("empty value keeps configured path", func(t *testing.T) {
+		viper.Reset()
+		defer viper.Reset()
+		viper.SetConfigType("yaml")
+		require.NoError(t, viper.ReadConfig(strings.NewReader(fmt.Sprintf("soundid:\n  lifelistpath: %q\n", configured))))
+		t.Setenv(EnvVarLifeListPath, " ")
+
+		err := configureEnvironmentVariables()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), EnvVarLifeListPath)
+		assert.Equal(t, configured, viper.GetString(ConfigKeyLifeListPath))
+	})
+}
@@ -123,6 +123,35 @@ func GetBasePath(path string) string {
 	return basePath
 }
 
+// IsLifeListURL reports whether a life list location is an http(s) URL rather than a file path
+func IsLifeListURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// ResolveLifeListPath resolves a relative life list file path against dataDir, or against
+// the config directory when dataDir is empty. URLs and absolute paths are returned unchanged,
+// as is path when the config directory can't be determined.
+func ResolveLifeListPath(path, dataDir string) string {
+	if IsLifeListURL(path) || filepath.IsAbs(path) {
+		return path
+	}
+
+	baseDir := dataDir
+	if baseDir == "" {
+		configPaths, err := GetDefaultConfigPaths()
+		if err != nil || len(configPaths) == 0 {
+			return path
+		}
+		baseDir = configPaths[0]
+	}
+
+	resolved, err := filepath.Abs(filepath.Join(baseDir, path))
+	if err != nil {
+		return path
+	}
+	return resolved
+}
+
 // GetHLSDirectory returns the directory where HLS files should be stored
 func GetHLSDirectory() (string, error) {
 	// Get config directory paths
@@ -5,6 +5,7 @@ package conf
 import (
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
 	"regexp"
 	"slices"
@@ -497,6 +498,31 @@ func ValidateSettings(settings *Settings) error {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
 
+	// Validate UI spectrogram settings
+	validateUiSpectrogramSettings(&settings.SoundId.UiSpectrogram)
+
+	if err := ValidateLifeListConfig(settings); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	if policy := settings.SoundId.LifeListCollisionPolicy; policy != "" && !slices.Contains(LifeListCollisionPolicies, policy) {
+		GetLogger().Warn("Invalid life list collision policy, using default",
+			logger.String("invalid_policy", policy),
+			logger.String("valid_policies", strings.Join(LifeListCollisionPolicies, ", ")))
+		settings.SoundId.LifeListCollisionPolicy = LifeListCollisionWarn
+	}
+
+	if format := settings.SoundId.LifeListFormat; format != "" && !slices.Contains(LifeListFormats, format) {
+		GetLogger().Warn("Invalid life list format, detecting the format instead",
+			logger.String("invalid_format", format),
+			logger.String("valid_formats", strings.Join(LifeListFormats, ", ")))
+		settings.SoundId.LifeListFormat = LifeListFormatAuto
+	}
+
+	if err := validateLifeListColumns(&settings.SoundId); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
 	// If there are any errors, return the ValidationError
 	if len(ve.Errors) > 0 {
 		return ve
@@ -504,6 +530,108 @@ func ValidateSettings(settings *Settings) error {
 	return nil
 }
 
+// ValidateLifeListConfig checks, when Sound ID is enabled, that a life list is configured
+// and that each configured life list file exists and can be read, so a misconfigured list
+// fails at startup instead of on the first load. URLs are only fetched when the list is
+// loaded and aren't checked. With LifeListSkipUnreadable set, one readable file of several
+// is enough.
+func ValidateLifeListConfig(settings *Settings) error {
+	soundID := &settings.SoundId
+	if !soundID.Enabled {
+		return nil
+	}
+
+	var paths []string
+	for _, path := range append([]string{soundID.LifeListPath}, soundID.LifeListPaths...) {
+		if path = strings.TrimSpace(path); path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return errors.Newf("soundid.lifelistpath must be set when Sound ID is enabled").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "life-list-path-empty").
+			Build()
+	}
+
+	var firstErr error
+	for _, path := range paths {
+		err := validateLifeListFile(path, soundID.DataDir)
+		if err == nil && soundID.LifeListSkipUnreadable {
+			return nil
+		}
+		if err != nil && !soundID.LifeListSkipUnreadable {
+			return err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// validateLifeListColumns rejects a scientific name column set for a life list format that
+// doesn't read it. Only the legacy format takes its columns from the configuration; the
+// others, including the default auto detection, would silently ignore it.
+func validateLifeListColumns(soundID *SoundIdConfig) error {
+	if !soundID.Enabled || soundID.LifeListScientificNameColumn == DefaultLifeListScientificNameColumn ||
+		soundID.LifeListFormat == LifeListFormatLegacy {
+		return nil
+	}
+	format := soundID.LifeListFormat
+	if format == "" {
+		format = LifeListFormatAuto
+	}
+	return errors.Newf("soundid.lifelistscientificnamecolumn is only used by the %q life list format, but soundid.lifelistformat is %q",
+		LifeListFormatLegacy, format).
+		Category(errors.CategoryValidation).
+		Context("validation_type", "life-list-column-unused").
+		Context("format", format).
+		Build()
+}
+
+// validateLifeListFile checks that the life list at path, resolved against dataDir, is a
+// readable regular file
+func validateLifeListFile(path, dataDir string) error {
+	if IsLifeListURL(path) {
+		return nil
+	}
+	resolved := ResolveLifeListPath(path, dataDir)
+
+	info, err := os.Stat(resolved)
+	switch {
+	case os.IsNotExist(err):
+		return errors.Newf("life list file %s does not exist", resolved).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "life-list-path-missing").
+			Context("path", resolved).
+			Build()
+	case err != nil:
+		return errors.New(fmt.Errorf("life list file %s can't be accessed: %w", resolved, err)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "life-list-path-unreadable").
+			Context("path", resolved).
+			Build()
+	case info.IsDir():
+		return errors.Newf("life list path %s is a directory, not a file", resolved).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "life-list-path-directory").
+			Context("path", resolved).
+			Build()
+	}
+
+	file, err := os.Open(resolved) //nolint:gosec // G304: the path comes from the user's configuration
+	if err != nil {
+		return errors.New(fmt.Errorf("life list file %s can't be read: %w", resolved, err)).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "life-list-path-unreadable").
+			Context("path", resolved).
+			Build()
+	}
+	_ = file.Close()
+	return nil
+}
+
 // validateBirdNETSettings validates the BirdNET-specific settings.
 // This function uses ValidateBirdNETSettings internally and handles side effects
 // (logging, mutation) to maintain backward compatibility.
@@ -956,6 +1084,93 @@ func validateDashboardSettings(settings *Dashboard) error {
 	return nil
 }
 
+// validateUiSpectrogramSettings validates the live UI spectrogram settings
+func validateUiSpectrogramSettings(settings *UiSpectrogramSettings) {
+	if settings.Palette != "" && !slices.Contains(UiSpectrogramPalettes, settings.Palette) {
+		// Log warning but don't fail - the palette only affects display
+		GetLogger().Warn("Invalid UI spectrogram palette, using default",
+			logger.String("invalid_palette", settings.Palette),
+			logger.String("valid_palettes", strings.Join(UiSpectrogramPalettes, ", ")))
+		settings.Palette = DefaultUiSpectrogramPalette
+	}
+	for source, palette := range settings.SourcePalettes {
+		if !slices.Contains(UiSpectrogramPalettes, palette) {
+			// Dropping the override lets the source fall back to the global palette
+			GetLogger().Warn("Invalid UI spectrogram source palette, using global palette",
+				logger.String("source", source),
+				logger.String("invalid_palette", palette),
+				logger.String("valid_palettes", strings.Join(UiSpectrogramPalettes, ", ")))
+			delete(settings.SourcePalettes, source)
+		}
+	}
+
+	if settings.MsPerColumn != 0 &&
+		(settings.MsPerColumn < UiSpectrogramMinMsPerColumn || settings.MsPerColumn > UiSpectrogramMaxMsPerColumn) {
+		GetLogger().Warn("UI spectrogram ms per column outside the supported range, using non-overlapping columns",
+			logger.Float64("ms_per_column", settings.MsPerColumn),
+			logger.Float64("min_ms_per_column", UiSpectrogramMinMsPerColumn),
+			logger.Float64("max_ms_per_column", UiSpectrogramMaxMsPerColumn))
+		settings.MsPerColumn = 0
+	}
+
+	validateUiSpectrogramFrequencyBand(settings)
+
+	if settings.SpectrogramEncoding != "" && !slices.Contains(UiSpectrogramEncodings, settings.SpectrogramEncoding) {
+		GetLogger().Warn("Invalid UI spectrogram encoding, using JSON",
+			logger.String("invalid_encoding", settings.SpectrogramEncoding),
+			logger.String("valid_encodings", strings.Join(UiSpectrogramEncodings, ", ")))
+		settings.SpectrogramEncoding = UiSpectrogramEncodingJSON
+	}
+
+	if settings.SpectrogramSSEPath != "" && !strings.HasPrefix(settings.SpectrogramSSEPath, "/") {
+		GetLogger().Warn("UI spectrogram SSE path must start with /, using default",
+			logger.String("invalid_path", settings.SpectrogramSSEPath),
+			logger.String("default_path", DefaultUiSpectrogramSSEPath))
+		settings.SpectrogramSSEPath = DefaultUiSpectrogramSSEPath
+	}
+	// A line break would end the event field and corrupt the stream framing
+	if strings.ContainsAny(settings.SpectrogramSSEEventName, "\r\n") {
+		GetLogger().Warn("UI spectrogram SSE event name contains a line break, using default",
+			logger.String("invalid_event_name", settings.SpectrogramSSEEventName),
+			logger.String("default_event_name", DefaultUiSpectrogramSSEEventName))
+		settings.SpectrogramSSEEventName = DefaultUiSpectrogramSSEEventName
+	}
+
+	if settings.SpectrogramRecordDir != "" && settings.SpectrogramRecordMaxBytes <= 0 {
+		GetLogger().Warn("UI spectrogram record size limit must be positive, using default",
+			logger.Int64("invalid_max_bytes", settings.SpectrogramRecordMaxBytes),
+			logger.Int64("default_max_bytes", DefaultUiSpectrogramRecordMaxBytes))
+		settings.SpectrogramRecordMaxBytes = DefaultUiSpectrogramRecordMaxBytes
+	}
+}
+
+// validateUiSpectrogramFrequencyBand clamps the band broadcast to clients to the frequencies
+// frames hold, from 0 to the Nyquist frequency, and broadcasts the whole band instead when
+// its lower edge isn't below its upper edge.
+func validateUiSpectrogramFrequencyBand(settings *UiSpectrogramSettings) {
+	nyquist := float64(SampleRate) / 2
+	if settings.SpectrogramMinFreqHz < 0 || settings.SpectrogramMinFreqHz > nyquist ||
+		settings.SpectrogramMaxFreqHz < 0 || settings.SpectrogramMaxFreqHz > nyquist {
+		GetLogger().Warn("UI spectrogram frequency band outside the frames' range, clamping",
+			logger.Float64("min_freq_hz", settings.SpectrogramMinFreqHz),
+			logger.Float64("max_freq_hz", settings.SpectrogramMaxFreqHz),
+			logger.Float64("nyquist_hz", nyquist))
+		settings.SpectrogramMinFreqHz = min(max(settings.SpectrogramMinFreqHz, 0), nyquist)
+		settings.SpectrogramMaxFreqHz = min(max(settings.SpectrogramMaxFreqHz, 0), nyquist)
+	}
+
+	upper := settings.SpectrogramMaxFreqHz
+	if upper == 0 {
+		upper = nyquist
+	}
+	if settings.SpectrogramMinFreqHz >= upper {
+		GetLogger().Warn("UI spectrogram frequency band is empty, broadcasting all frequencies",
+			logger.Float64("min_freq_hz", settings.SpectrogramMinFreqHz),
+			logger.Float64("max_freq_hz", settings.SpectrogramMaxFreqHz))
+		settings.SpectrogramMinFreqHz, settings.SpectrogramMaxFreqHz = 0, 0
+	}
+}
+
 // validateWeatherSettings validates weather-specific settings
 func validateWeatherSettings(settings *WeatherSettings) error {
 	// Validate poll interval (minimum 15 minutes)
@@ -1,6 +1,8 @@
 package conf
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -185,3 +187,195 @@ func BenchmarkValidateSoundLevelSettingsWithError(b *testing.B) {
 		_ = validateSoundLevelSettings(settings)
 	}
 }
+
+func TestValidateUiSpectrogramSettings_Palette(t *testing.T) {
+	tests := []struct {
+		name    string
+		palette string
+		want    string
+	}{
+		{"unset", "", ""},
+		{"grayscale", UiSpectrogramPaletteGrayscale, UiSpectrogramPaletteGrayscale},
+		{"viridis", UiSpectrogramPaletteViridis, UiSpectrogramPaletteViridis},
+		{"unknown falls back to default", "rainbow", DefaultUiSpectrogramPalette},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := UiSpectrogramSettings{Palette: tt.palette}
+			validateUiSpectrogramSettings(&settings)
+			assert.Equal(t, tt.want, settings.Palette)
+		})
+	}
+}
+
+func TestValidateUiSpectrogramSettings_SourcePalettes(t *testing.T) {
+	settings := UiSpectrogramSettings{
+		Palette: UiSpectrogramPaletteGrayscale,
+		SourcePalettes: map[string]string{
+			"feeder":   UiSpectrogramPaletteViridis,
+			"backyard": "rainbow",
+		},
+	}
+	validateUiSpectrogramSettings(&settings)
+
+	assert.Equal(t, map[string]string{"feeder": UiSpectrogramPaletteViridis}, settings.SourcePalettes)
+	assert.Equal(t, UiSpectrogramPaletteViridis, settings.SourcePalette("feeder"))
+	assert.Equal(t, UiSpectrogramPaletteGrayscale, settings.SourcePalette("backyard"), "an invalid override falls back to the global palette")
+}
+
+func TestValidateUiSpectrogramSettings_MsPerColumn(t *testing.T) {
+	tests := []struct {
+		name        string
+		msPerColumn float64
+		want        float64
+	}{
+		{"unset", 0, 0},
+		{"within range", 10, 10},
+		{"longer than a window", 50, 0},
+		{"shorter than the minimum", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := UiSpectrogramSettings{MsPerColumn: tt.msPerColumn}
+			validateUiSpectrogramSettings(&settings)
+			assert.InDelta(t, tt.want, settings.MsPerColumn, 1e-9)
+		})
+	}
+}
+
+func TestValidateUiSpectrogramSettings_FrequencyBand(t *testing.T) {
+	nyquist := float64(SampleRate) / 2
+	tests := []struct {
+		name             string
+		minHz, maxHz     float64
+		wantMin, wantMax float64
+	}{
+		{"unset", 0, 0, 0, 0},
+		{"within range", 2000, 8000, 2000, 8000},
+		{"lower edge only", 2000, 0, 2000, 0},
+		{"negative lower edge", -100, 8000, 0, 8000},
+		{"upper edge beyond Nyquist", 2000, 2 * nyquist, 2000, nyquist},
+		{"lower edge above upper edge", 8000, 2000, 0, 0},
+		{"lower edge at Nyquist", nyquist, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := UiSpectrogramSettings{SpectrogramMinFreqHz: tt.minHz, SpectrogramMaxFreqHz: tt.maxHz}
+			validateUiSpectrogramSettings(&settings)
+			assert.InDelta(t, tt.wantMin, settings.SpectrogramMinFreqHz, 1e-9)
+			assert.InDelta(t, tt.wantMax, settings.SpectrogramMaxFreqHz, 1e-9)
+		})
+	}
+}
+
+func TestValidateUiSpectrogramSettings_SSEPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"unset", "", ""},
+		{"absolute", "/birds/spectrogram", "/birds/spectrogram"},
+		{"relative falls back to default", "birds/spectrogram", DefaultUiSpectrogramSSEPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := UiSpectrogramSettings{SpectrogramSSEPath: tt.path}
+			validateUiSpectrogramSettings(&settings)
+			assert.Equal(t, tt.want, settings.SpectrogramSSEPath)
+		})
+	}
+
+	settings := UiSpectrogramSettings{SpectrogramSSEEventName: "frame\ndata: injected"}
+	validateUiSpectrogramSettings(&settings)
+	assert.Equal(t, DefaultUiSpectrogramSSEEventName, settings.SpectrogramSSEEventName)
+}
+
+func TestValidateUiSpectrogramSettings_RecordMaxBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		maxBytes int64
+		want     int64
+	}{
+		{"recording disabled", "", 0, 0},
+		{"positive limit", "/var/lib/birdnet/frames", 1 << 20, 1 << 20},
+		{"unset limit falls back to default", "/var/lib/birdnet/frames", 0, DefaultUiSpectrogramRecordMaxBytes},
+		{"negative limit falls back to default", "/var/lib/birdnet/frames", -1, DefaultUiSpectrogramRecordMaxBytes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := UiSpectrogramSettings{SpectrogramRecordDir: tt.dir, SpectrogramRecordMaxBytes: tt.maxBytes}
+			validateUiSpectrogramSettings(&settings)
+			assert.Equal(t, tt.want, settings.SpectrogramRecordMaxBytes)
+		})
+	}
+}
+
+func TestValidateLifeListColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		soundID SoundIdConfig
+		wantErr bool
+	}{
+		{"default column", SoundIdConfig{Enabled: true, LifeListFormat: LifeListFormatAuto, LifeListScientificNameColumn: DefaultLifeListScientificNameColumn}, false},
+		{"legacy format", SoundIdConfig{Enabled: true, LifeListFormat: LifeListFormatLegacy, LifeListScientificNameColumn: 1}, false},
+		{"sound id disabled", SoundIdConfig{LifeListFormat: LifeListFormatAuto, LifeListScientificNameColumn: 1}, false},
+		{"auto format", SoundIdConfig{Enabled: true, LifeListFormat: LifeListFormatAuto, LifeListScientificNameColumn: 1}, true},
+		{"ebird format", SoundIdConfig{Enabled: true, LifeListFormat: LifeListFormatEBird, LifeListScientificNameColumn: 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLifeListColumns(&tt.soundID)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			enhanced := requireEnhancedError(t, err)
+			assert.Equal(t, "life-list-column-unused", enhanced.Context["validation_type"])
+		})
+	}
+}
+
+func TestValidateLifeListConfig(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "life_list.csv")
+	require.NoError(t, os.WriteFile(listPath, []byte("Turdus migratorius\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		soundID SoundIdConfig
+		errType string // validation_type of the expected error, empty for none
+	}{
+		{"disabled", SoundIdConfig{}, ""},
+		{"readable file", SoundIdConfig{Enabled: true, LifeListPath: listPath}, ""},
+		{"relative to data dir", SoundIdConfig{Enabled: true, DataDir: dir, LifeListPath: "life_list.csv"}, ""},
+		{"url", SoundIdConfig{Enabled: true, LifeListPath: "https://example.com/life_list.csv"}, ""},
+		{"empty path", SoundIdConfig{Enabled: true, LifeListPath: "  "}, "life-list-path-empty"},
+		{"missing file", SoundIdConfig{Enabled: true, LifeListPath: filepath.Join(dir, "missing.csv")}, "life-list-path-missing"},
+		{"directory instead of file", SoundIdConfig{Enabled: true, LifeListPath: dir}, "life-list-path-directory"},
+		{"missing additional file", SoundIdConfig{
+			Enabled:       true,
+			LifeListPath:  listPath,
+			LifeListPaths: []string{filepath.Join(dir, "missing.csv")},
+		}, "life-list-path-missing"},
+		{"missing additional file skipped", SoundIdConfig{
+			Enabled:                true,
+			LifeListPath:           listPath,
+			LifeListPaths:          []string{filepath.Join(dir, "missing.csv")},
+			LifeListSkipUnreadable: true,
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLifeListConfig(&Settings{SoundId: tt.soundID})
+			if tt.errType == "" {
+				require.NoError(t, err)
+				return
+			}
+			enhanced := requireEnhancedError(t, err)
+			assert.Equal(t, errors.CategoryValidation, enhanced.Category)
+			assert.Equal(t, tt.errType, enhanced.Context["validation_type"])
+		})
+	}
+}
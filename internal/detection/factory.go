@@ -23,6 +23,7 @@ type ResultParams struct {
 	ClipName    string
 	Elapsed     time.Duration
 	Occurrence  float64
+	Threshold   float64 // Global confidence threshold, read through the processor since it changes at runtime
 }
 
 // NewResult creates a Result from the given parameters.
@@ -50,7 +51,7 @@ func NewResult(settings *conf.Settings, p *ResultParams) *Result {
 		Confidence:     roundedConfidence,
 		Latitude:       settings.BirdNET.Latitude,
 		Longitude:      settings.BirdNET.Longitude,
-		Threshold:      settings.BirdNET.Threshold,
+		Threshold:      p.Threshold,
 		Sensitivity:    settings.BirdNET.Sensitivity,
 		ClipName:       p.ClipName,
 		ProcessingTime: p.Elapsed,
@@ -82,6 +82,25 @@ const (
 // ComponentUnknown is used when the component cannot be determined.
 const ComponentUnknown = "unknown"
 
+// Severity tells operators how serious an error is
+type Severity string
+
+// Severities of errors, from least to most serious
+const (
+	SeverityWarning Severity = "warning" // Something failed, but the component keeps working, e.g. on its previous state
+	SeverityError   Severity = "error"   // The operation failed; the default
+	SeverityFatal   Severity = "fatal"   // The component can't run until the cause, typically its configuration, is fixed
+)
+
+// retryClass records whether an error was marked as worth retrying
+type retryClass uint8
+
+const (
+	retryUnknown   retryClass = iota // Not classified; IsRetryable looks at the wrapped errors
+	retryRetryable                   // Retrying the operation may succeed
+	retryPermanent                   // Retrying the operation will fail the same way
+)
+
 // EnhancedError wraps an error with additional context and metadata
 type EnhancedError struct {
 	Err       error          // Original error
@@ -89,6 +108,8 @@ type EnhancedError struct {
 	Category  ErrorCategory  // Error category for better grouping
 	Priority  string         // Explicit priority override (optional)
 	Context   map[string]any // Additional context data
+	retry     retryClass     // Whether the failed operation is worth retrying, if known
+	severity  Severity       // How serious the error is, empty for SeverityError
 	Timestamp time.Time      // When the error occurred
 	reported  bool           // Whether telemetry has been sent
 	mu        sync.RWMutex   // Mutex to protect concurrent access
@@ -151,6 +172,15 @@ func (ee *EnhancedError) GetPriority() string {
 	return ee.Priority
 }
 
+// GetSeverity returns the severity set on this error, SeverityError when none was. Unlike
+// SeverityOf it doesn't look at the errors it wraps.
+func (ee *EnhancedError) GetSeverity() Severity {
+	if ee.severity == "" {
+		return SeverityError
+	}
+	return ee.severity
+}
+
 // GetContext returns the error context
 func (ee *EnhancedError) GetContext() map[string]any {
 	ee.mu.RLock()
@@ -205,6 +235,8 @@ type ErrorBuilder struct {
 	category  ErrorCategory
 	priority  string
 	context   map[string]any
+	retry     retryClass
+	severity  Severity
 }
 
 // New creates a new error with enhanced context
@@ -249,6 +281,23 @@ func (eb *ErrorBuilder) Priority(priority string) *ErrorBuilder {
 	return eb
 }
 
+// Retryable marks whether the failed operation is worth retrying, for example a file that
+// couldn't be opened as opposed to one that couldn't be parsed
+func (eb *ErrorBuilder) Retryable(retryable bool) *ErrorBuilder {
+	if retryable {
+		eb.retry = retryRetryable
+	} else {
+		eb.retry = retryPermanent
+	}
+	return eb
+}
+
+// Severity sets how serious the error is. Errors without one are SeverityError.
+func (eb *ErrorBuilder) Severity(severity Severity) *ErrorBuilder {
+	eb.severity = severity
+	return eb
+}
+
 // Context adds context data to the error
 func (eb *ErrorBuilder) Context(key string, value any) *ErrorBuilder {
 	if eb.context == nil {
@@ -330,6 +379,8 @@ func (eb *ErrorBuilder) Build() *EnhancedError {
 			Category:  eb.category,  // Use provided or empty
 			Priority:  eb.priority,  // Use provided or empty
 			Context:   eb.context,
+			retry:     eb.retry,
+			severity:  eb.severity,
 			Timestamp: time.Now(),
 			detected:  eb.component != "", // Mark as detected if component was provided
 		}
@@ -361,6 +412,8 @@ func (eb *ErrorBuilder) Build() *EnhancedError {
 		Category:  eb.category,
 		Priority:  eb.priority,
 		Context:   eb.context,
+		retry:     eb.retry,
+		severity:  eb.severity,
 		Timestamp: time.Now(),
 		detected:  true, // Mark as detected since we just detected it
 	}
@@ -718,6 +771,59 @@ func IsCategory(err error, category ErrorCategory) bool {
 	return As(err, &enhancedErr) && enhancedErr.Category == category
 }
 
+// IsRetryable reports whether the operation that failed with err is worth retrying. The
+// outermost EnhancedError in the chain marked with Retryable decides; errors nobody marked
+// are not retryable.
+func IsRetryable(err error) bool {
+	return retryClassOf(err) == retryRetryable
+}
+
+// IsPermanent reports whether err was explicitly marked as not worth retrying, which unlike
+// !IsRetryable tells such errors apart from ones nobody classified
+func IsPermanent(err error) bool {
+	return retryClassOf(err) == retryPermanent
+}
+
+// retryClassOf returns the retry class of the outermost classified EnhancedError in err's tree
+func retryClassOf(err error) retryClass {
+	if ee := findEnhanced(err, func(ee *EnhancedError) bool { return ee.retry != retryUnknown }); ee != nil {
+		return ee.retry
+	}
+	return retryUnknown
+}
+
+// SeverityOf returns the severity of err: that of the outermost EnhancedError in its chain
+// with a severity set, or SeverityError when none has one.
+func SeverityOf(err error) Severity {
+	if ee := findEnhanced(err, func(ee *EnhancedError) bool { return ee.severity != "" }); ee != nil {
+		return ee.severity
+	}
+	return SeverityError
+}
+
+// findEnhanced returns the outermost EnhancedError in err's tree that match accepts,
+// searching joined errors in order, or nil when there is none
+func findEnhanced(err error, match func(*EnhancedError) bool) *EnhancedError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *EnhancedError:
+		if match(e) {
+			return e
+		}
+		return findEnhanced(e.Err, match)
+	case interface{ Unwrap() error }:
+		return findEnhanced(e.Unwrap(), match)
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if found := findEnhanced(wrapped, match); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
 // IsNotFound checks if an error is an EnhancedError with CategoryNotFound.
 // This is commonly used for expected conditions like unknown species or missing resources.
 func IsNotFound(err error) bool {
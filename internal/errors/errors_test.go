@@ -69,4 +69,48 @@ func TestRegexPrecompilation(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRetryable(t *testing.T) {
+	t.Parallel()
+
+	retryable := Newf("connection reset").Category(CategoryNetwork).Retryable(true).Build()
+	permanent := Newf("malformed row").Category(CategoryFileParsing).Retryable(false).Build()
+	unknown := Newf("something failed").Build()
+
+	assert.True(t, IsRetryable(retryable))
+	assert.False(t, IsPermanent(retryable))
+	assert.False(t, IsRetryable(permanent))
+	assert.True(t, IsPermanent(permanent))
+	assert.False(t, IsRetryable(unknown), "errors nobody classified aren't retried")
+	assert.False(t, IsPermanent(unknown))
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(fmt.Errorf("plain error")))
+
+	// The classification is found through wrapping, and the outermost classified error wins
+	assert.True(t, IsRetryable(fmt.Errorf("loading list: %w", retryable)))
+	assert.True(t, IsRetryable(New(retryable).Component("life_list").Build()), "an unclassified wrapper defers to the wrapped error")
+	assert.True(t, IsPermanent(New(retryable).Retryable(false).Build()), "a classified wrapper overrides the wrapped error")
+	assert.True(t, IsPermanent(Join(unknown, permanent)))
+}
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	unset := Newf("write failed").Build()
+	warning := Newf("reload failed, keeping the previous list").Severity(SeverityWarning).Build()
+	fatal := Newf("path is not set").Category(CategoryConfiguration).Severity(SeverityFatal).Build()
+
+	assert.Equal(t, SeverityError, unset.GetSeverity(), "errors default to SeverityError")
+	assert.Equal(t, SeverityError, SeverityOf(unset))
+	assert.Equal(t, SeverityWarning, warning.GetSeverity())
+	assert.Equal(t, SeverityFatal, SeverityOf(fatal))
+	assert.Equal(t, SeverityError, SeverityOf(fmt.Errorf("plain error")))
+	assert.Equal(t, SeverityError, SeverityOf(nil))
+
+	// The severity is found through wrapping, and the outermost error with one wins
+	assert.Equal(t, SeverityFatal, SeverityOf(fmt.Errorf("loading: %w", fatal)))
+	assert.Equal(t, SeverityFatal, SeverityOf(New(fatal).Component("life_list").Build()), "a wrapper without a severity keeps the wrapped one")
+	assert.Equal(t, SeverityWarning, SeverityOf(New(fatal).Severity(SeverityWarning).Build()), "a wrapper's severity overrides the wrapped one")
+	assert.Equal(t, SeverityError, New(fatal).Build().GetSeverity(), "GetSeverity doesn't look at wrapped errors")
+	assert.Equal(t, SeverityWarning, SeverityOf(Join(unset, warning)))
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MultiError reports several failures of one operation together, such as the files of a
+// multi-file load that could not be read. Each child keeps its own component and category;
+// Is, As and the classification helpers see all of them through Unwrap.
+type MultiError struct {
+	errs []error
+}
+
+// Aggregate returns the non-nil errors of errs reported together. It returns nil when there
+// are none and the error itself when there is one, so callers can collect failures in a
+// loop and return the result unconditionally. Children that are MultiErrors are flattened
+// into the result.
+func Aggregate(errs ...error) error {
+	var collected []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if multi, ok := err.(*MultiError); ok {
+			collected = append(collected, multi.errs...)
+			continue
+		}
+		collected = append(collected, err)
+	}
+
+	switch len(collected) {
+	case 0:
+		return nil
+	case 1:
+		return collected[0]
+	default:
+		return &MultiError{errs: collected}
+	}
+}
+
+// Error renders the count followed by each child as "component (category): message".
+// Children without an EnhancedError in their chain are shown with their message alone.
+func (me *MultiError) Error() string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(me.errs)))
+	b.WriteString(" errors: ")
+	for i, err := range me.errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		var enhanced *EnhancedError
+		if As(err, &enhanced) {
+			b.WriteString(enhanced.GetComponent())
+			if enhanced.Category != "" {
+				b.WriteString(" (")
+				b.WriteString(string(enhanced.Category))
+				b.WriteString(")")
+			}
+			b.WriteString(": ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the aggregated errors, in the order they were collected
+func (me *MultiError) Unwrap() []error {
+	return me.errs
+}
+
+// Len returns the number of aggregated errors
+func (me *MultiError) Len() int {
+	return len(me.errs)
+}
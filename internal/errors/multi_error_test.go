@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Aggregate(), "nothing to report")
+	assert.NoError(t, Aggregate(nil, nil))
+
+	single := Newf("only failure").Component("life_list").Category(CategoryFileIO).Build()
+	assert.Same(t, single, Aggregate(nil, single), "a single failure is returned as it is")
+}
+
+func TestAggregate_RetainsChildren(t *testing.T) {
+	t.Parallel()
+
+	fileErr := Newf("a.csv: file not found").Component("life_list").Category(CategoryFileIO).Retryable(true).Build()
+	configErr := Newf("unsupported encoding").Component("life_list").Category(CategoryConfiguration).Build()
+	timeoutErr := Newf("publishers did not stop").Component("analysis.uispectrogram").Category(CategorySystem).Build()
+	plain := fmt.Errorf("plain failure")
+
+	err := Aggregate(fileErr, nil, Aggregate(configErr, plain), fmt.Errorf("stopping: %w", timeoutErr))
+	var multi *MultiError
+	require.ErrorAs(t, err, &multi)
+	assert.Equal(t, 4, multi.Len(), "nested aggregates are flattened")
+
+	children := multi.Unwrap()
+	assert.Same(t, fileErr, children[0])
+	assert.Same(t, configErr, children[1])
+	assert.Equal(t, plain, children[2])
+
+	// Each child keeps its component and category and can be found in the tree
+	for _, child := range []*EnhancedError{fileErr, configErr, timeoutErr} {
+		assert.ErrorIs(t, err, child)
+	}
+	var found *EnhancedError
+	require.ErrorAs(t, children[3], &found)
+	assert.Equal(t, "analysis.uispectrogram", found.GetComponent())
+	assert.Equal(t, CategorySystem, found.Category)
+	assert.True(t, IsCategory(err, CategoryFileIO))
+	assert.True(t, IsRetryable(err), "classification helpers walk the children")
+
+	assert.Equal(t, "4 errors: life_list (file-io): a.csv: file not found; "+
+		"life_list (configuration): unsupported encoding; plain failure; "+
+		"analysis.uispectrogram (system-resource): stopping: publishers did not stop", err.Error())
+}
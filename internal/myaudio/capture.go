@@ -5,20 +5,20 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 	"unsafe"
-	"os"
-	"path/filepath"
 
+	"github.com/gen2brain/malgo"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
-	"github.com/gen2brain/malgo"
-	
+
 	tflite "github.com/tphakala/go-tflite"
 )
 
@@ -155,7 +155,7 @@ type AudioLevelData struct {
 type UnifiedAudioData struct {
 	// Basic audio level information (always present)
 	AudioLevel AudioLevelData `json:"audio_level"`
-	
+
 	SpectrogramData UiSpectrogramData `json:"spectrogram_data,omitempty"` // Spectrogram data (optional)
 
 	// Sound level data (present only when 10-second window is complete)
@@ -660,18 +660,18 @@ func processAudioFrame(
 
 	// Calculate audio level (use the safe bufferToUse)
 	audioLevelData := calculateAudioLevel(bufferToUse, sourceID, source.Name)
-	
-	spectrogramData, err := calculateSpectrogram(uiSpectrogramInterpreter, bufferToUse, sourceID, source.Name)
+
+	spectrogramData, err := calculateSpectrogram(uiSpectrogramInterpreter, bufferToUse, sourceID, source.Name, &settings.SoundId.UiSpectrogram)
 	if err != nil {
 		log.Warn("error generating spectrogram", logger.Error(err))
 		// Potentially non-fatal, log and continue
-	}		
+	}
 
 	// Create unified audio data structure
 	unifiedData := UnifiedAudioData{
-		AudioLevel: audioLevelData,
+		AudioLevel:      audioLevelData,
 		SpectrogramData: spectrogramData,
-		Timestamp:  time.Now(),
+		Timestamp:       time.Now(),
 	}
 
 	// Process sound level data if enabled (use the safe bufferToUse) - this may be nil if 10-second window isn't complete
@@ -798,7 +798,7 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, sourceID s
 				logger.String("source_name", source.Name))
 		}
 	}
-	
+
 	uiSpectrogramInterpreter, err = InitializeUiSpectrogramModel(settings.SoundId.UiModelPath)
 	if err != nil {
 		log.Error("Failed to initialize UI spectrogram model", logger.Error(err))
@@ -808,8 +808,14 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, sourceID s
 	var formatType malgo.FormatType // Declare formatType here
 	var scratchBuffer []byte        // Dedicated buffer for conversion destination
 	var restarting atomic.Int32     // Flag to prevent concurrent restarts
+	startupSkip := newCaptureStartupSkip(sourceID, settings.Realtime.Audio.StartupSkip)
 
 	onReceiveFrames := func(pSample2, pSamples []byte, framecount uint32) {
+		// Drop device warm-up audio before it reaches the spectrogram or the analysis buffers
+		if startupSkip.discard(int(framecount)) {
+			return
+		}
+
 		// processAudioFrame now handles pooling internally and returns buffer info
 		// Pass scratchBuffer as the potential destination for conversion
 		finalBufferPtr, fromPool, err := processAudioFrame(
@@ -910,8 +916,6 @@ func logDeviceInfo(log logger.Logger, dev *malgo.Device, format malgo.FormatType
 		logger.Int("sample_rate", int(dev.SampleRate())))
 }
 
-
-
 // getUiSpectrogramModelData returns the appropriate spectrogram model data based on the settings.
 func GetUiSpectrogramModelData(modelPath string) ([]byte, error) {
 	// Check if external model path is specified
@@ -999,36 +1003,80 @@ func InitializeUiSpectrogramModel(modelPath string) (*tflite.Interpreter, error)
 	return UISpectrogramInterpreter, nil
 }
 
-
-func calculateSpectrogram(interpreter *tflite.Interpreter, samples []byte, source, name string) (data UiSpectrogramData, err error) {
+func calculateSpectrogram(interpreter *tflite.Interpreter, samples []byte, source, name string, uiSettings *conf.UiSpectrogramSettings) (data UiSpectrogramData, err error) {
 	if len(samples) != 2048 {
 		return UiSpectrogramData{}, fmt.Errorf("no data provided for spectrogram generation")
 	}
-	
+	if interpreter == nil {
+		return UiSpectrogramData{}, fmt.Errorf("ui spectrogram model is not initialized")
+	}
+
+	return buildUiSpectrogramFrame(samples, source, uiSettings,
+		func(sample []float32) ([]byte, error) {
+			return GenerateUiSpectrogram(interpreter, sample)
+		})
+}
+
+// buildUiSpectrogramFrame turns 1024 16-bit samples into a timestamped UI spectrogram frame
+// with columns spaced by the configured ms per column and the source's calibration gain applied, attaching the downsampled audio of the same samples and the frame's spectral features
+// when those are enabled.
+func buildUiSpectrogramFrame(samples []byte, source string, uiSettings *conf.UiSpectrogramSettings, column func([]float32) ([]byte, error)) (UiSpectrogramData, error) {
+	now := time.Now()
+	if uiSettings.ClockResyncInterval > 0 {
+		now = uiSpectrogramClock.next(source, len(samples)/2, now,
+			time.Duration(uiSettings.ClockResyncInterval)*time.Second,
+			time.Duration(uiSettings.ClockMaxCorrection)*time.Millisecond)
+	}
+	timestamp, _ := uiSpectrogramTimestamps.next(source, now,
+		time.Duration(uiSettings.MinFrameInterval)*time.Millisecond)
+
 	input := convert16BitToFloat32(samples) // 1024 samples
-	size := 257 * 2;
-	
-	spectrogram := make([]byte, size)
-	
-	spectrogram1, err := GenerateUiSpectrogram(interpreter, input[0:512])
-	if err != nil {
-		return UiSpectrogramData{}, err
+	uiSpectrogramCalibration.observe(source, input)
+	applyCalibrationGain(input, uiSettings.CalibrationGains[source])
+	if uiSettings.PreEmphasisEnabled {
+		uiSpectrogramPreEmphasis.apply(source, input, uiSettings.PreEmphasisCoefficient)
 	}
-	copy(spectrogram[0:257], spectrogram1)
 
-	spectrogram2, err := GenerateUiSpectrogram(interpreter, input[512:1024])
+	hop := uiSpectrogramHop(uiSettings.MsPerColumn)
+	spectrogram, err := computeUiSpectrogramFrame(uiSpectrogramWindows.next(source, input, hop), source, column)
 	if err != nil {
 		return UiSpectrogramData{}, err
 	}
-	copy(spectrogram[257:514], spectrogram2)
 
 	spectrogramData := UiSpectrogramData{
 		Spectrogram: spectrogram,
+		Source:      source,
+		Palette:     resolveUiSpectrogramPalette(uiSettings.SourcePalette(source), GetLogger()).Name,
+		MsPerColumn: uiSpectrogramHopMs(hop),
+		Timestamp:   timestamp,
+		SampleRate:  conf.SampleRate,
+		Audio:       newUiSpectrogramAudio(samples, timestamp, uiSettings),
+		Features:    computeSpectralFeatures(spectrogram, uiSettings),
 	}
-	
+
 	return spectrogramData, nil
 }
 
+// computeUiSpectrogramFrame computes one UI spectrogram column per window and records the
+// FFT compute time, so users can tell whether the device keeps up.
+func computeUiSpectrogramFrame(windows [][]float32, source string, column func([]float32) ([]byte, error)) ([]byte, error) {
+	start := time.Now()
+
+	spectrogram := make([]byte, len(windows)*UiSpectrogramBins)
+	for i, window := range windows {
+		values, err := column(window)
+		if err != nil {
+			return nil, err
+		}
+		copy(spectrogram[i*UiSpectrogramBins:(i+1)*UiSpectrogramBins], values)
+	}
+
+	if m := getAnalysisMetrics(); m != nil {
+		m.RecordUiSpectrogramFFTDuration(source, time.Since(start).Seconds())
+	}
+
+	return spectrogram, nil
+}
 
 // GenerateUiSpectrogram generates a spectrogram for the UI.
 // The samples are expected to be 512 samples of 22,050 Hz audio, normalized between -1.0 and 1.0.
@@ -1037,8 +1085,8 @@ func GenerateUiSpectrogram(interpreter *tflite.Interpreter, sample []float32) ([
 	start := time.Now()
 
 	inputSize := 512
-	outputSize := 257
-	
+	outputSize := UiSpectrogramBins
+
 	if len(sample) != inputSize {
 		err := errors.New(fmt.Errorf("input sample length %d does not match expected length %d", len(sample), inputSize)).
 			Context("interpreter_state", "initialized").
@@ -0,0 +1,52 @@
+package myaudio
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// captureStartupSkip discards the first audio a capture device delivers after it opens,
+// since some devices emit a burst of noise while warming up that would show up in the
+// spectrogram and trigger spurious detections. The window is counted in frames rather than
+// wall time, so it covers the same amount of audio however the device batches it. Only the
+// device's callback goroutine uses it, so it needs no locking.
+type captureStartupSkip struct {
+	sourceID  string
+	window    time.Duration
+	remaining int // Frames still to discard
+	done      bool
+}
+
+// newCaptureStartupSkip returns a skip window of the given length in milliseconds at the
+// capture sample rate. Zero or negative disables it.
+func newCaptureStartupSkip(sourceID string, skipMs int) *captureStartupSkip {
+	frames := int(int64(max(skipMs, 0)) * conf.SampleRate / 1000)
+	return &captureStartupSkip{
+		sourceID:  sourceID,
+		window:    time.Duration(skipMs) * time.Millisecond,
+		remaining: frames,
+		done:      frames == 0,
+	}
+}
+
+// discard reports whether a buffer of frameCount frames falls in the skip window and must
+// be dropped. A buffer that straddles the end of the window is dropped whole. It logs once
+// when the window ends.
+func (s *captureStartupSkip) discard(frameCount int) bool {
+	if s.done {
+		return false
+	}
+	if s.remaining > 0 {
+		s.remaining -= frameCount
+		return true
+	}
+
+	s.done = true
+	GetLogger().Info("Capture startup skip ended, processing audio",
+		logger.String("source_id", s.sourceID),
+		logger.Duration("skipped", s.window),
+		logger.String("operation", "capture_startup_skip"))
+	return false
+}
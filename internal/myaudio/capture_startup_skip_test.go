@@ -0,0 +1,38 @@
+package myaudio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestCaptureStartupSkip_DropsWarmUpThenResumes(t *testing.T) {
+	t.Parallel()
+
+	const framesPerBuffer = 1024
+	skip := newCaptureStartupSkip("startup-skip-test", 500)
+	skipFrames := conf.SampleRate / 2
+
+	var produced []int // Frame offsets of the buffers that were processed
+	for offset := 0; offset < 2*conf.SampleRate; offset += framesPerBuffer {
+		if !skip.discard(framesPerBuffer) {
+			produced = append(produced, offset)
+		}
+	}
+
+	if assert.NotEmpty(t, produced, "buffers resume after the skip window") {
+		assert.GreaterOrEqual(t, produced[0], skipFrames, "nothing from the skip window is processed")
+		assert.Less(t, produced[0], skipFrames+framesPerBuffer, "processing resumes with the first buffer after the window")
+	}
+	for i := 1; i < len(produced); i++ {
+		assert.Equal(t, framesPerBuffer, produced[i]-produced[i-1], "every buffer after the window is processed")
+	}
+}
+
+func TestCaptureStartupSkip_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, newCaptureStartupSkip("no-skip", 0).discard(1024))
+	assert.False(t, newCaptureStartupSkip("negative-skip", -100).discard(1024))
+}
@@ -0,0 +1,53 @@
+package myaudio
+
+import "sync"
+
+// defaultPreEmphasisCoefficient is the customary speech/audio pre-emphasis coefficient,
+// used when the configured value is outside (0, 1).
+const defaultPreEmphasisCoefficient = 0.97
+
+// preEmphasisFilters carries each source's last input sample into its next frame, so the
+// filter runs continuously over the stream instead of restarting at every frame edge.
+type preEmphasisFilters struct {
+	mu   sync.Mutex
+	prev map[string]float32
+}
+
+// uiSpectrogramPreEmphasis is shared by all sources; each source keeps its own last sample.
+var uiSpectrogramPreEmphasis = &preEmphasisFilters{prev: make(map[string]float32)}
+
+// apply runs the pre-emphasis filter over the source's next frame in place, continuing from
+// the last sample of its previous frame. A source's first frame passes its first sample
+// through unchanged.
+func (f *preEmphasisFilters) apply(source string, samples []float32, coefficient float64) {
+	if len(samples) == 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prev[source] = applyPreEmphasis(samples, coefficient, f.prev[source])
+}
+
+// applyPreEmphasis runs a first-order high-pass filter, y[n] = x[n] - a*x[n-1], over the
+// samples in place, with prev as the sample before the first. It lifts high frequencies by
+// roughly 6 dB per octave relative to low ones, so faint high-pitched calls stand out
+// against low-frequency background on the UI spectrogram. It returns the last input sample,
+// the prev of the following frame.
+func applyPreEmphasis(samples []float32, coefficient float64, prev float32) float32 {
+	a := float32(preEmphasisCoefficient(coefficient))
+	for i, x := range samples {
+		samples[i] = x - a*prev
+		prev = x
+	}
+	return prev
+}
+
+// preEmphasisCoefficient returns the configured coefficient, or the default when it is
+// outside (0, 1).
+func preEmphasisCoefficient(coefficient float64) float64 {
+	if coefficient <= 0 || coefficient >= 1 {
+		return defaultPreEmphasisCoefficient
+	}
+	return coefficient
+}
@@ -0,0 +1,82 @@
+package myaudio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// toneEnergy measures the energy of samples at freq using the Goertzel algorithm.
+func toneEnergy(samples []float32, freq, sampleRate float64) float64 {
+	coeff := 2 * math.Cos(2*math.Pi*freq/sampleRate)
+	var s1, s2 float64
+	for _, x := range samples {
+		s := float64(x) + coeff*s1 - s2
+		s2, s1 = s1, s
+	}
+	return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+func TestApplyPreEmphasis_BoostsHighFrequencies(t *testing.T) {
+	t.Parallel()
+
+	const (
+		lowFreq  = 200.0
+		highFreq = 8000.0
+		n        = 1024
+	)
+
+	frame := make([]float32, n)
+	for i := range frame {
+		ts := float64(i) / conf.SampleRate
+		frame[i] = float32(0.4*math.Sin(2*math.Pi*lowFreq*ts) + 0.4*math.Sin(2*math.Pi*highFreq*ts))
+	}
+	filtered := make([]float32, n)
+	copy(filtered, frame)
+
+	applyPreEmphasis(filtered, 0.97, 0)
+
+	before := toneEnergy(frame, highFreq, conf.SampleRate) / toneEnergy(frame, lowFreq, conf.SampleRate)
+	after := toneEnergy(filtered, highFreq, conf.SampleRate) / toneEnergy(filtered, lowFreq, conf.SampleRate)
+	assert.Greater(t, after, 10*before, "high/low energy ratio should rise sharply with pre-emphasis")
+}
+
+func TestApplyPreEmphasis_InvalidCoefficientUsesDefault(t *testing.T) {
+	t.Parallel()
+
+	a := []float32{1, 1, 1}
+	b := []float32{1, 1, 1}
+	applyPreEmphasis(a, 0, 0)
+	applyPreEmphasis(b, defaultPreEmphasisCoefficient, 0)
+	assert.Equal(t, b, a)
+	assert.InDelta(t, 1-defaultPreEmphasisCoefficient, a[1], 1e-6)
+}
+
+func TestPreEmphasisFilters_ContinuesAcrossFramesPerSource(t *testing.T) {
+	t.Parallel()
+
+	const n = 64
+	stream := make([]float32, 2*n)
+	for i := range stream {
+		stream[i] = float32(math.Sin(float64(i) / 3))
+	}
+	whole := make([]float32, len(stream))
+	copy(whole, stream)
+	applyPreEmphasis(whole, 0.9, 0)
+
+	// Filtering the stream frame by frame matches filtering it in one go, even with another
+	// source's frame in between
+	filters := &preEmphasisFilters{prev: make(map[string]float32)}
+	first := append([]float32(nil), stream[:n]...)
+	other := []float32{1, -1, 1}
+	second := append([]float32(nil), stream[n:]...)
+	filters.apply("mic", first, 0.9)
+	filters.apply("other", other, 0.9)
+	filters.apply("mic", second, 0.9)
+
+	assert.InDeltaSlice(t, whole[:n], first, 1e-6)
+	assert.InDeltaSlice(t, whole[n:], second, 1e-6, "the filter must not restart at the frame edge")
+	assert.InDelta(t, 1.0, other[0], 1e-6, "a source's first sample passes through unchanged")
+}
@@ -12,8 +12,51 @@ import (
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
+// UiSpectrogramBins is the number of frequency bins in one UI spectrogram column.
+const UiSpectrogramBins = 257
+
+// UiSpectrogramSchemaVersion is the version of the UiSpectrogramData schema, sent to clients
+// in the spectrogram stream's metadata event. Adding an optional field keeps the version,
+// since clients ignore fields they don't know; it is bumped when an existing field changes
+// meaning or a field clients rely on is removed, so older clients can fall back to what they
+// understand instead of misreading frames.
+//
+// Version 1: columns of Bins bytes (default UiSpectrogramBins), BinHz, MsPerColumn, Palette,
+// plus the optional MinHz, audio and spectral features.
+const UiSpectrogramSchemaVersion = 1
+
+// UiSpectrogramData carries one batch of UI spectrogram columns for a source.
+// Spectrogram holds consecutive columns of UiSpectrogramBins bytes each, or of Bins
+// bytes when the frame was down-resolved or cropped to a frequency band before broadcast.
 type UiSpectrogramData struct {
-	Spectrogram []byte 		`json:"spectrogram"`
+	Spectrogram []byte  `json:"spectrogram"`
+	Source      string  `json:"source,omitempty"`  // Source ID the frame was generated from
+	Bins        int     `json:"bins,omitempty"`    // Bins per column, set only when different from UiSpectrogramBins
+	BinHz       float64 `json:"binHz,omitempty"`   // Frequency width of each bin in Hz, set only when bins were merged
+	MinHz       float64 `json:"minHz,omitempty"`   // Frequency in Hz the first bin starts at, set only when the frame was cropped to a band
+	Palette     string  `json:"palette,omitempty"` // Palette the client renders the frame with
+	MsPerColumn float64 `json:"msPerColumn"`       // Time between the starts of consecutive columns in ms
+
+	Timestamp  time.Time           `json:"timestamp"`            // When the frame's samples were captured, set when the frame is produced
+	SampleRate int                 `json:"sampleRate,omitempty"` // Sample rate in Hz of the audio the frame was computed from
+	Audio      *UiSpectrogramAudio `json:"audio,omitempty"`      // Downsampled audio of the same samples, when the audio stream is enabled
+	Features   *UiSpectralFeatures `json:"features,omitempty"`   // Spectral centroid and bandwidth, when enabled
+}
+
+// ColumnBins returns the number of bins per column in the frame.
+func (d *UiSpectrogramData) ColumnBins() int {
+	if d.Bins > 0 {
+		return d.Bins
+	}
+	return UiSpectrogramBins
+}
+
+// ColumnBinHz returns the frequency width of each bin in the frame; bin i starts at MinHz + i * ColumnBinHz.
+func (d *UiSpectrogramData) ColumnBinHz() float64 {
+	if d.BinHz > 0 {
+		return d.BinHz
+	}
+	return float64(conf.SampleRate) / uiSpectrogramWindowSize
 }
 
 // OctaveBandData represents sound level statistics for a single 1/3rd octave band
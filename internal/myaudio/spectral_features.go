@@ -0,0 +1,61 @@
+package myaudio
+
+import (
+	"math"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// uiSpectrogramWindowSize is the number of samples behind each UI spectrogram column; bin i
+// of a column is centred on i * SampleRate / uiSpectrogramWindowSize Hz.
+const uiSpectrogramWindowSize = conf.UiSpectrogramWindowSize
+
+// UiSpectralFeatures summarizes the spectral shape of a UI spectrogram frame.
+type UiSpectralFeatures struct {
+	Centroid  float64 `json:"centroid"`  // Magnitude-weighted mean frequency in Hz
+	Bandwidth float64 `json:"bandwidth"` // Magnitude-weighted standard deviation around the centroid in Hz
+}
+
+// computeSpectralFeatures computes the centroid and bandwidth of a frame's columns,
+// considering only bins within the configured band. It returns nil when the features
+// are disabled or the band holds no energy.
+func computeSpectralFeatures(spectrogram []byte, settings *conf.UiSpectrogramSettings) *UiSpectralFeatures {
+	if !settings.SpectralFeaturesEnabled {
+		return nil
+	}
+
+	binHz := float64(conf.SampleRate) / uiSpectrogramWindowSize
+	minHz := settings.SpectralFeaturesMinHz
+	maxHz := settings.SpectralFeaturesMaxHz
+	if maxHz <= 0 {
+		maxHz = float64(conf.SampleRate) / 2
+	}
+
+	var sum, weighted float64
+	for i, value := range spectrogram {
+		freq := float64(i%UiSpectrogramBins) * binHz
+		if freq < minHz || freq > maxHz {
+			continue
+		}
+		sum += float64(value)
+		weighted += float64(value) * freq
+	}
+	if sum == 0 {
+		return nil
+	}
+	centroid := weighted / sum
+
+	var variance float64
+	for i, value := range spectrogram {
+		freq := float64(i%UiSpectrogramBins) * binHz
+		if freq < minHz || freq > maxHz {
+			continue
+		}
+		variance += float64(value) * (freq - centroid) * (freq - centroid)
+	}
+
+	return &UiSpectralFeatures{
+		Centroid:  centroid,
+		Bandwidth: math.Sqrt(variance / sum),
+	}
+}
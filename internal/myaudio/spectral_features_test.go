@@ -0,0 +1,75 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// dftColumn stands in for the spectrogram model with a plain DFT, scaling the magnitudes
+// of the UiSpectrogramBins bins to 0-255.
+func dftColumn(sample []float32) ([]byte, error) {
+	magnitudes := make([]float64, UiSpectrogramBins)
+	var peak float64
+	for k := range magnitudes {
+		var sum complex128
+		for n, x := range sample {
+			sum += complex(float64(x), 0) * cmplx.Exp(complex(0, -2*math.Pi*float64(k*n)/float64(len(sample))))
+		}
+		magnitudes[k] = cmplx.Abs(sum)
+		peak = max(peak, magnitudes[k])
+	}
+
+	column := make([]byte, UiSpectrogramBins)
+	for k, m := range magnitudes {
+		column[k] = byte(math.Round(255 * m / peak))
+	}
+	return column, nil
+}
+
+func TestSpectralFeatures_PureTone(t *testing.T) {
+	t.Parallel()
+
+	// A tone centred on bin 64 puts all its energy in that bin of each column
+	toneHz := 64 * float64(conf.SampleRate) / uiSpectrogramWindowSize
+	samples := make([]byte, 2048)
+	for i := range 1024 {
+		v := 0.5 * math.Sin(2*math.Pi*toneHz*float64(i)/conf.SampleRate)
+		binary.LittleEndian.PutUint16(samples[i*2:], uint16(int16(v*math.MaxInt16)))
+	}
+
+	settings := &conf.UiSpectrogramSettings{SpectralFeaturesEnabled: true}
+	frame, err := buildUiSpectrogramFrame(samples, "mic", settings, dftColumn)
+	require.NoError(t, err)
+	require.NotNil(t, frame.Features)
+
+	binHz := float64(conf.SampleRate) / uiSpectrogramWindowSize
+	assert.InDelta(t, toneHz, frame.Features.Centroid, binHz/2)
+	assert.Less(t, frame.Features.Bandwidth, binHz, "a pure tone should have near-zero bandwidth")
+}
+
+func TestSpectralFeatures_Band(t *testing.T) {
+	t.Parallel()
+
+	binHz := float64(conf.SampleRate) / uiSpectrogramWindowSize
+	column := make([]byte, UiSpectrogramBins)
+	column[10] = 200 // Outside the band
+	column[100] = 100
+	frame := append(append([]byte{}, column...), column...)
+
+	settings := &conf.UiSpectrogramSettings{
+		SpectralFeaturesEnabled: true,
+		SpectralFeaturesMinHz:   50 * binHz,
+	}
+	features := computeSpectralFeatures(frame, settings)
+	require.NotNil(t, features)
+	assert.InDelta(t, 100*binHz, features.Centroid, 1e-9)
+	assert.InDelta(t, 0, features.Bandwidth, 1e-9)
+
+	assert.Nil(t, computeSpectralFeatures(frame, &conf.UiSpectrogramSettings{}), "disabled features are not computed")
+}
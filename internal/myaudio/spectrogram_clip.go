@@ -0,0 +1,44 @@
+package myaudio
+
+import (
+	"io"
+	"time"
+)
+
+// ClipSourceID is the source ID attached to frames generated from a saved clip.
+const ClipSourceID = "spectrogram_clip"
+
+// ClipSpectrogramFrame is one UI spectrogram frame of a saved clip, positioned by where its
+// samples start within the clip rather than by wall clock.
+type ClipSpectrogramFrame struct {
+	Offset time.Duration
+	Data   UiSpectrogramData
+}
+
+// ClipSpectrogram decodes a WAV clip and generates its UI spectrogram frames in clip order,
+// returning them with the clip length. Trailing samples that don't fill a whole frame count
+// towards the length but get no frame. When spectrogram is nil, the UI spectrogram model
+// used by live capture is used.
+func ClipSpectrogram(r io.ReadSeeker, spectrogram SpectrogramFunc) ([]ClipSpectrogramFrame, time.Duration, error) {
+	mono, err := decodeReplayWAV(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if spectrogram == nil {
+		spectrogram = liveSpectrogramFunc
+	}
+
+	frames := make([]ClipSpectrogramFrame, 0, len(mono)/replayFrameSamples)
+	err = forEachReplayFrame(mono, func(offset int, frame []byte) error {
+		data, err := spectrogram(frame, ClipSourceID, replaySourceName)
+		if err != nil {
+			return err
+		}
+		frames = append(frames, ClipSpectrogramFrame{Offset: samplesDuration(int64(offset)), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return frames, samplesDuration(int64(len(mono))), nil
+}
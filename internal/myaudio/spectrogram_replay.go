@@ -0,0 +1,163 @@
+package myaudio
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/go-audio/wav"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+const (
+	// ReplaySourceID is the source ID attached to frames produced by a WAV replay.
+	// It never matches a registered capture source, so replayed frames cannot be
+	// mistaken for (or mixed into the buffers of) a live source.
+	ReplaySourceID = "spectrogram_replay"
+
+	// replaySourceName is the display name used for replayed frames.
+	replaySourceName = "Replay"
+
+	// replayFrameSamples is the number of 16-bit samples per replayed frame. It matches
+	// the frame size calculateSpectrogram expects from the live capture path.
+	replayFrameSamples = 1024
+)
+
+// SpectrogramFunc converts one frame of 16-bit PCM into UI spectrogram data.
+type SpectrogramFunc func(samples []byte, source, name string) (UiSpectrogramData, error)
+
+// ReplayOptions configures a WAV replay through the spectrogram pipeline.
+type ReplayOptions struct {
+	// Speed is the playback rate relative to real time. 1.0 paces frames as if they
+	// were captured live, 2.0 replays twice as fast. Zero or negative disables pacing.
+	Speed float64
+
+	// Spectrogram generates spectrogram columns for each frame. When nil, the
+	// UI spectrogram model used by live capture is used.
+	Spectrogram SpectrogramFunc
+}
+
+// ReplayWAV decodes a WAV stream and emits frames as if they had come from live
+// capture: each frame carries the audio level and UI spectrogram tagged with
+// ReplaySourceID. Input is down-mixed to mono and resampled to conf.SampleRate.
+// A spectrogram generation failure is not fatal; the frame is emitted with empty
+// spectrogram data. Replay stops when the stream ends, ctx is cancelled or emit
+// returns an error.
+func ReplayWAV(ctx context.Context, r io.ReadSeeker, opts ReplayOptions, emit func(UnifiedAudioData) error) error {
+	mono, err := decodeReplayWAV(r)
+	if err != nil {
+		return err
+	}
+
+	generate := opts.Spectrogram
+	if generate == nil {
+		generate = liveSpectrogramFunc
+	}
+
+	var frameInterval time.Duration
+	if opts.Speed > 0 {
+		frameInterval = time.Duration(float64(replayFrameSamples) / float64(conf.SampleRate) / opts.Speed * float64(time.Second))
+	}
+
+	var ticker *time.Ticker
+	if frameInterval > 0 {
+		ticker = time.NewTicker(frameInterval)
+		defer ticker.Stop()
+	}
+
+	return forEachReplayFrame(mono, func(_ int, frame []byte) error {
+		spectrogram, err := generate(frame, ReplaySourceID, replaySourceName)
+		if err != nil {
+			spectrogram = UiSpectrogramData{Source: ReplaySourceID}
+		}
+
+		data := UnifiedAudioData{
+			AudioLevel:      calculateAudioLevel(frame, ReplaySourceID, replaySourceName),
+			SpectrogramData: spectrogram,
+			Timestamp:       time.Now(),
+		}
+		if err := emit(data); err != nil {
+			return err
+		}
+
+		if ticker == nil {
+			return ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			return nil
+		}
+	})
+}
+
+// liveSpectrogramFunc generates spectrogram columns with the UI spectrogram model used by
+// live capture.
+func liveSpectrogramFunc(samples []byte, source, name string) (UiSpectrogramData, error) {
+	return calculateSpectrogram(uiSpectrogramInterpreter, samples, source, name, &conf.Setting().SoundId.UiSpectrogram)
+}
+
+// decodeReplayWAV decodes a WAV stream to mono samples at conf.SampleRate.
+func decodeReplayWAV(r io.ReadSeeker) ([]float32, error) {
+	decoder := wav.NewDecoder(r)
+	decoder.ReadInfo()
+	if !decoder.IsValidFile() {
+		return nil, errors.Newf("input is not a valid WAV audio file").
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "replay_wav").
+			Build()
+	}
+
+	divisor, err := getAudioDivisor(int(decoder.BitDepth))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := decoder.FullPCMBuffer()
+	if err != nil {
+		return nil, errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryFileIO).
+			Context("operation", "replay_wav").
+			Build()
+	}
+
+	numChans := max(int(decoder.NumChans), 1)
+	mono := make([]float32, len(buf.Data)/numChans)
+	for i := range mono {
+		mono[i] = float32(buf.Data[i*numChans]) / divisor
+	}
+
+	mono, err = ResampleAudio(mono, int(decoder.SampleRate), conf.SampleRate)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryAudio).
+			Context("operation", "replay_wav").
+			Context("source_sample_rate", decoder.SampleRate).
+			Build()
+	}
+	return mono, nil
+}
+
+// forEachReplayFrame calls fn with the offset and 16-bit PCM of each whole frame of mono,
+// stopping at the first error. The frame buffer is reused between calls.
+func forEachReplayFrame(mono []float32, fn func(offset int, frame []byte) error) error {
+	frame := make([]byte, replayFrameSamples*2)
+	window := make([]float64, replayFrameSamples)
+	for start := 0; start+replayFrameSamples <= len(mono); start += replayFrameSamples {
+		for i, sample := range mono[start : start+replayFrameSamples] {
+			window[i] = float64(sample)
+		}
+		if err := Float64ToBytesPCM16(window, frame); err != nil {
+			return err
+		}
+		if err := fn(start, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package myaudio
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// writeReplayTestWAV writes a mono 16-bit WAV at conf.SampleRate made of the given
+// segments, each a sine tone of the given amplitude (0 for silence).
+func writeReplayTestWAV(t *testing.T, segments []struct {
+	amplitude float64
+	frames    int
+}) string {
+	t.Helper()
+
+	var data []int
+	for _, seg := range segments {
+		for i := range seg.frames * replayFrameSamples {
+			sample := seg.amplitude * math.Sin(2*math.Pi*1000*float64(i)/conf.SampleRate)
+			data = append(data, int(sample*math.MaxInt16))
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "replay.wav")
+	file, err := os.Create(path) //nolint:gosec // G304: test fixture path
+	require.NoError(t, err)
+
+	enc := wav.NewEncoder(file, conf.SampleRate, 16, 1, 1)
+	require.NoError(t, enc.Write(&audio.IntBuffer{
+		Data:   data,
+		Format: &audio.Format{SampleRate: conf.SampleRate, NumChannels: 1},
+	}))
+	require.NoError(t, enc.Close())
+	require.NoError(t, file.Close())
+
+	return path
+}
+
+// energySpectrogram stands in for the UI spectrogram model and encodes frame RMS
+// as a single column value so replayed energy can be asserted without TFLite.
+func energySpectrogram(samples []byte, source, _ string) (UiSpectrogramData, error) {
+	floats := BytesToFloat64PCM16(samples)
+	var sum float64
+	for _, s := range floats {
+		sum += s * s
+	}
+	rms := math.Sqrt(sum / float64(len(floats)))
+	return UiSpectrogramData{Spectrogram: []byte{byte(rms * math.MaxUint8)}, Source: source}, nil
+}
+
+func TestReplayWAV_EnergyOverTime(t *testing.T) {
+	t.Parallel()
+
+	path := writeReplayTestWAV(t, []struct {
+		amplitude float64
+		frames    int
+	}{
+		{0, 4},
+		{0.5, 4},
+		{0, 4},
+	})
+
+	file, err := os.Open(path) //nolint:gosec // G304: test fixture path
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	var frames []UnifiedAudioData
+	err = ReplayWAV(t.Context(), file, ReplayOptions{Spectrogram: energySpectrogram}, func(d UnifiedAudioData) error {
+		frames = append(frames, d)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, frames, 12)
+
+	for i, f := range frames {
+		assert.Equal(t, ReplaySourceID, f.AudioLevel.Source, "frame %d", i)
+		assert.Equal(t, ReplaySourceID, f.SpectrogramData.Source, "frame %d", i)
+		require.Len(t, f.SpectrogramData.Spectrogram, 1, "frame %d", i)
+
+		energy := f.SpectrogramData.Spectrogram[0]
+		if i >= 4 && i < 8 {
+			// 0.5 amplitude sine has RMS ~0.354
+			assert.InDelta(t, 0.354*math.MaxUint8, float64(energy), 3, "tone frame %d", i)
+			assert.Positive(t, f.AudioLevel.Level, "tone frame %d", i)
+		} else {
+			assert.Zero(t, energy, "silent frame %d", i)
+			assert.Zero(t, f.AudioLevel.Level, "silent frame %d", i)
+		}
+	}
+}
+
+func TestReplayWAV_PacesAtSpeed(t *testing.T) {
+	t.Parallel()
+
+	path := writeReplayTestWAV(t, []struct {
+		amplitude float64
+		frames    int
+	}{{0.1, 4}})
+
+	file, err := os.Open(path) //nolint:gosec // G304: test fixture path
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	// 4 frames of 1024 samples at 22050 Hz is ~186ms of audio; at 4x it takes ~46ms.
+	start := time.Now()
+	count := 0
+	err = ReplayWAV(t.Context(), file, ReplayOptions{Speed: 4, Spectrogram: energySpectrogram}, func(UnifiedAudioData) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestReplayWAV_StopsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	path := writeReplayTestWAV(t, []struct {
+		amplitude float64
+		frames    int
+	}{{0.1, 8}})
+
+	file, err := os.Open(path) //nolint:gosec // G304: test fixture path
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	count := 0
+	err = ReplayWAV(ctx, file, ReplayOptions{Spectrogram: energySpectrogram}, func(UnifiedAudioData) error {
+		count++
+		if count == 2 {
+			cancel()
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, count)
+}
+
+func TestReplayWAV_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bad.wav")
+	require.NoError(t, os.WriteFile(path, []byte("not a wav file"), 0o600))
+
+	file, err := os.Open(path) //nolint:gosec // G304: test fixture path
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	err = ReplayWAV(t.Context(), file, ReplayOptions{}, func(UnifiedAudioData) error { return nil })
+	require.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// UiSpectrogramAudio is a low-bitrate audio packet covering the same samples as the
+// spectrogram frame it is attached to, so clients can listen to what they are seeing.
+type UiSpectrogramAudio struct {
+	Timestamp  time.Time `json:"timestamp"`  // Same as the frame's timestamp
+	SampleRate int       `json:"sampleRate"` // Actual rate of PCM after downsampling
+	PCM        []byte    `json:"pcm"`        // 16-bit little-endian mono samples
+}
+
+// newUiSpectrogramAudio downsamples a frame's 16-bit PCM samples for the audio stream.
+// It returns nil when the stream is disabled.
+func newUiSpectrogramAudio(samples []byte, timestamp time.Time, settings *conf.UiSpectrogramSettings) *UiSpectrogramAudio {
+	if !settings.AudioStreamEnabled {
+		return nil
+	}
+
+	factor := 1
+	if settings.AudioStreamSampleRate > 0 {
+		factor = max(1, int(math.Round(float64(conf.SampleRate)/float64(settings.AudioStreamSampleRate))))
+	}
+
+	return &UiSpectrogramAudio{
+		Timestamp:  timestamp,
+		SampleRate: conf.SampleRate / factor,
+		PCM:        decimatePCM16(samples, factor),
+	}
+}
+
+// decimatePCM16 reduces 16-bit little-endian PCM by factor, averaging each group of
+// samples as a simple anti-aliasing filter. A trailing partial group is dropped.
+func decimatePCM16(samples []byte, factor int) []byte {
+	n := len(samples) / 2 / factor
+	out := make([]byte, n*2)
+	for i := range n {
+		var sum int
+		for j := range factor {
+			offset := (i*factor + j) * 2
+			sum += int(int16(binary.LittleEndian.Uint16(samples[offset:])))
+		}
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(int16(sum/factor)))
+	}
+	return out
+}
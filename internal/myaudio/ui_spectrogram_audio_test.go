@@ -0,0 +1,57 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// fakeUiSpectrogramColumn stands in for the TFLite spectrogram model.
+func fakeUiSpectrogramColumn([]float32) ([]byte, error) {
+	return make([]byte, UiSpectrogramBins), nil
+}
+
+func TestBuildUiSpectrogramFrame_AudioAlignedWithFrames(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.UiSpectrogramSettings{AudioStreamEnabled: true, AudioStreamSampleRate: conf.SampleRate / 2}
+
+	samples := make([]byte, 2048)
+	for i := range 1024 {
+		binary.LittleEndian.PutUint16(samples[i*2:], uint16(int16(i*10)))
+	}
+
+	var previous *UiSpectrogramData
+	for range 3 {
+		frame, err := buildUiSpectrogramFrame(samples, "mic", settings, fakeUiSpectrogramColumn)
+		require.NoError(t, err)
+		require.NotNil(t, frame.Audio, "audio packet expected when the stream is enabled")
+
+		assert.False(t, frame.Timestamp.IsZero())
+		assert.True(t, frame.Audio.Timestamp.Equal(frame.Timestamp), "audio must carry its frame's timestamp")
+		assert.Equal(t, "mic", frame.Source)
+		assert.Equal(t, conf.SampleRate/2, frame.Audio.SampleRate)
+		require.Len(t, frame.Audio.PCM, 1024, "512 samples after halving the rate")
+
+		// Each output sample averages a pair of inputs: (20i + 20i+10) / 2
+		assert.Equal(t, int16(5), int16(binary.LittleEndian.Uint16(frame.Audio.PCM[0:])))
+		assert.Equal(t, int16(25), int16(binary.LittleEndian.Uint16(frame.Audio.PCM[2:])))
+
+		if previous != nil {
+			assert.False(t, frame.Timestamp.Before(previous.Timestamp), "frames are produced in order")
+		}
+		previous = &frame
+	}
+}
+
+func TestBuildUiSpectrogramFrame_AudioDisabled(t *testing.T) {
+	t.Parallel()
+
+	frame, err := buildUiSpectrogramFrame(make([]byte, 2048), "mic", &conf.UiSpectrogramSettings{}, fakeUiSpectrogramColumn)
+	require.NoError(t, err)
+	assert.Nil(t, frame.Audio)
+	assert.False(t, frame.Timestamp.IsZero())
+}
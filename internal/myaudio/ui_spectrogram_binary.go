@@ -0,0 +1,228 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// UiSpectrogramBinaryVersion is the first byte of every binary encoded frame. It is bumped
+// when the layout changes, so decoders reject frames they would misread.
+const UiSpectrogramBinaryVersion = 1
+
+// Flags of the binary frame layout marking which optional parts follow
+const (
+	uiSpectrogramBinaryHasTimestamp byte = 1 << iota
+	uiSpectrogramBinaryHasAudio
+	uiSpectrogramBinaryHasAudioTimestamp
+	uiSpectrogramBinaryHasFeatures
+	uiSpectrogramBinaryHasSampleRate
+	uiSpectrogramBinaryHasMinHz
+)
+
+// EncodeBinary encodes the frame in a compact little-endian layout, which is smaller and
+// cheaper to produce than JSON for dense frames:
+//
+//	version u8, flags u8
+//	spectrogram: length u32, bytes
+//	source, palette: length u16, UTF-8 bytes each
+//	bins u32, binHz f64, msPerColumn f64
+//	timestamp i64 Unix nanoseconds, when flagged
+//	audio, when flagged: timestamp i64 when flagged, sampleRate u32, PCM length u32, bytes
+//	features, when flagged: centroid f64, bandwidth f64
+//	sampleRate u32, when flagged
+//	minHz f64, when flagged
+func (d *UiSpectrogramData) EncodeBinary() []byte {
+	var flags byte
+	if !d.Timestamp.IsZero() {
+		flags |= uiSpectrogramBinaryHasTimestamp
+	}
+	if d.Audio != nil {
+		flags |= uiSpectrogramBinaryHasAudio
+		if !d.Audio.Timestamp.IsZero() {
+			flags |= uiSpectrogramBinaryHasAudioTimestamp
+		}
+	}
+	if d.Features != nil {
+		flags |= uiSpectrogramBinaryHasFeatures
+	}
+	if d.SampleRate > 0 {
+		flags |= uiSpectrogramBinaryHasSampleRate
+	}
+	if d.MinHz > 0 {
+		flags |= uiSpectrogramBinaryHasMinHz
+	}
+
+	size := 2 + 4 + len(d.Spectrogram) + 2 + len(d.Source) + 2 + len(d.Palette) + 4 + 8 + 8 + 8
+	if d.Audio != nil {
+		size += 8 + 4 + 4 + len(d.Audio.PCM)
+	}
+	if d.Features != nil {
+		size += 16
+	}
+	if d.SampleRate > 0 {
+		size += 4
+	}
+	if d.MinHz > 0 {
+		size += 8
+	}
+
+	out := make([]byte, 0, size)
+	out = append(out, UiSpectrogramBinaryVersion, flags)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(d.Spectrogram))) //nolint:gosec // G115: frames are far below 4 GiB
+	out = append(out, d.Spectrogram...)
+	out = appendBinaryString(out, d.Source)
+	out = appendBinaryString(out, d.Palette)
+	out = binary.LittleEndian.AppendUint32(out, uint32(max(d.Bins, 0))) //nolint:gosec // G115: clamped to non-negative
+	out = binary.LittleEndian.AppendUint64(out, math.Float64bits(d.BinHz))
+	out = binary.LittleEndian.AppendUint64(out, math.Float64bits(d.MsPerColumn))
+	if flags&uiSpectrogramBinaryHasTimestamp != 0 {
+		out = binary.LittleEndian.AppendUint64(out, uint64(d.Timestamp.UnixNano())) //nolint:gosec // G115: reinterpreted back to int64 on decode
+	}
+	if d.Audio != nil {
+		if flags&uiSpectrogramBinaryHasAudioTimestamp != 0 {
+			out = binary.LittleEndian.AppendUint64(out, uint64(d.Audio.Timestamp.UnixNano())) //nolint:gosec // G115: reinterpreted back to int64 on decode
+		}
+		out = binary.LittleEndian.AppendUint32(out, uint32(max(d.Audio.SampleRate, 0))) //nolint:gosec // G115: clamped to non-negative
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(d.Audio.PCM)))           //nolint:gosec // G115: frames are far below 4 GiB
+		out = append(out, d.Audio.PCM...)
+	}
+	if d.Features != nil {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(d.Features.Centroid))
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(d.Features.Bandwidth))
+	}
+	if d.SampleRate > 0 {
+		out = binary.LittleEndian.AppendUint32(out, uint32(d.SampleRate)) //nolint:gosec // G115: flagged only when positive
+	}
+	if d.MinHz > 0 {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(d.MinHz))
+	}
+	return out
+}
+
+// DecodeBinary replaces the frame with one encoded by EncodeBinary. Timestamps are decoded
+// in UTC. A truncated frame, or one of an unknown version, is an error and leaves the frame
+// unchanged.
+func (d *UiSpectrogramData) DecodeBinary(data []byte) error {
+	r := binaryFrameReader{data: data, ok: true}
+	if version := r.uint8(); r.ok && version != UiSpectrogramBinaryVersion {
+		return errors.Newf("unsupported binary spectrogram frame version %d", version).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ui_spectrogram").
+			Context("version", version).
+			Build()
+	}
+	flags := r.uint8()
+
+	var frame UiSpectrogramData
+	frame.Spectrogram = r.bytes(int(r.uint32()))
+	frame.Source = string(r.bytes(int(r.uint16())))
+	frame.Palette = string(r.bytes(int(r.uint16())))
+	frame.Bins = int(r.uint32())
+	frame.BinHz = math.Float64frombits(r.uint64())
+	frame.MsPerColumn = math.Float64frombits(r.uint64())
+	if flags&uiSpectrogramBinaryHasTimestamp != 0 {
+		frame.Timestamp = r.time()
+	}
+	if flags&uiSpectrogramBinaryHasAudio != 0 {
+		frame.Audio = &UiSpectrogramAudio{}
+		if flags&uiSpectrogramBinaryHasAudioTimestamp != 0 {
+			frame.Audio.Timestamp = r.time()
+		}
+		frame.Audio.SampleRate = int(r.uint32())
+		frame.Audio.PCM = r.bytes(int(r.uint32()))
+	}
+	if flags&uiSpectrogramBinaryHasFeatures != 0 {
+		frame.Features = &UiSpectralFeatures{
+			Centroid:  math.Float64frombits(r.uint64()),
+			Bandwidth: math.Float64frombits(r.uint64()),
+		}
+	}
+	if flags&uiSpectrogramBinaryHasSampleRate != 0 {
+		frame.SampleRate = int(r.uint32())
+	}
+	if flags&uiSpectrogramBinaryHasMinHz != 0 {
+		frame.MinHz = math.Float64frombits(r.uint64())
+	}
+
+	if !r.ok {
+		return errors.Newf("binary spectrogram frame is truncated").
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ui_spectrogram").
+			Context("bytes", len(data)).
+			Build()
+	}
+	*d = frame
+	return nil
+}
+
+// appendBinaryString appends s with a u16 length prefix, cutting it at 65535 bytes
+func appendBinaryString(out []byte, s string) []byte {
+	s = s[:min(len(s), math.MaxUint16)]
+	out = binary.LittleEndian.AppendUint16(out, uint16(len(s))) //nolint:gosec // G115: cut to fit above
+	return append(out, s...)
+}
+
+// binaryFrameReader reads the fields of a binary frame in order. Once a read runs past the
+// end, ok is false and every later read returns zero values.
+type binaryFrameReader struct {
+	data []byte
+	ok   bool
+}
+
+// next returns the next n bytes, or nil when fewer remain
+func (r *binaryFrameReader) next(n int) []byte {
+	if !r.ok || n < 0 || n > len(r.data) {
+		r.ok = false
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *binaryFrameReader) uint8() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *binaryFrameReader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *binaryFrameReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *binaryFrameReader) uint64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// bytes returns a copy of the next n bytes, so the frame doesn't keep the input alive.
+// Empty fields decode as nil, as they are on unset frames.
+func (r *binaryFrameReader) bytes(n int) []byte {
+	b := r.next(n)
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+func (r *binaryFrameReader) time() time.Time {
+	return time.Unix(0, int64(r.uint64())).UTC() //nolint:gosec // G115: written from an int64
+}
@@ -0,0 +1,93 @@
+package myaudio
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// binaryTestFrame returns a dense frame of columns columns with every optional part set
+func binaryTestFrame(columns int) UiSpectrogramData {
+	spectrogram := make([]byte, columns*UiSpectrogramBins)
+	for i := range spectrogram {
+		spectrogram[i] = byte(i * 7)
+	}
+	at := time.Date(2026, 5, 15, 6, 30, 0, 123456789, time.UTC)
+	return UiSpectrogramData{
+		Spectrogram: spectrogram,
+		Source:      "backyard",
+		Bins:        UiSpectrogramBins,
+		BinHz:       93.75,
+		MinHz:       2000,
+		Palette:     "viridis",
+		MsPerColumn: 10.6667,
+		Timestamp:   at,
+		SampleRate:  48000,
+		Audio:       &UiSpectrogramAudio{Timestamp: at, SampleRate: 16000, PCM: []byte{1, 2, 3, 4}},
+		Features:    &UiSpectralFeatures{Centroid: 3125.5, Bandwidth: 812.25},
+	}
+}
+
+func TestUiSpectrogramData_BinaryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for name, frame := range map[string]UiSpectrogramData{
+		"full":    binaryTestFrame(2),
+		"minimal": {Spectrogram: []byte{0, 255}, MsPerColumn: 32},
+		"empty":   {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var decoded UiSpectrogramData
+			require.NoError(t, decoded.DecodeBinary(frame.EncodeBinary()))
+			assert.Equal(t, frame, decoded)
+		})
+	}
+}
+
+func TestUiSpectrogramData_DecodeBinaryRejectsBadInput(t *testing.T) {
+	t.Parallel()
+
+	frame := binaryTestFrame(1)
+	encoded := frame.EncodeBinary()
+	original := UiSpectrogramData{Source: "unchanged"}
+
+	for name, data := range map[string][]byte{
+		"empty":           nil,
+		"truncated":       encoded[:len(encoded)-1],
+		"unknown version": append([]byte{UiSpectrogramBinaryVersion + 1}, encoded[1:]...),
+	} {
+		decoded := original
+		require.Error(t, decoded.DecodeBinary(data), name)
+		assert.Equal(t, original, decoded, "%s: a failed decode leaves the frame alone", name)
+	}
+}
+
+func BenchmarkUiSpectrogramDataEncoding(b *testing.B) {
+	frame := binaryTestFrame(8)
+
+	b.Run("json", func(b *testing.B) {
+		b.ReportAllocs()
+		var size int
+		for b.Loop() {
+			data, err := json.Marshal(&frame)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(data)
+		}
+		b.ReportMetric(float64(size), "bytes/frame")
+	})
+
+	b.Run("binary", func(b *testing.B) {
+		b.ReportAllocs()
+		var size int
+		for b.Loop() {
+			size = len(frame.EncodeBinary())
+		}
+		b.ReportMetric(float64(size), "bytes/frame")
+	})
+}
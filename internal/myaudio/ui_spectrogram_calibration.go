@@ -0,0 +1,139 @@
+package myaudio
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// minCalibrationToneDBFS is the quietest captured level accepted as a calibration tone.
+// Anything below it is treated as silence or a disconnected input rather than a tone.
+const minCalibrationToneDBFS = -80.0
+
+// UiSpectrogramCalibration is the result of capturing a calibration tone on a source.
+type UiSpectrogramCalibration struct {
+	Source        string  `json:"source"`
+	MeasuredDBFS  float64 `json:"measuredDbfs"`  // RMS level of the captured tone before calibration
+	ReferenceDBFS float64 `json:"referenceDbfs"` // Level the tone is displayed at once the gain is applied
+	GainDB        float64 `json:"gainDb"`        // Gain applied to samples before the spectrogram FFT
+	ToneLevel     float64 `json:"toneLevel"`     // Known level of the tone, e.g. in dB SPL
+}
+
+// calibrationCapture accumulates the energy of the samples a source delivers until enough
+// were captured to measure the tone's level.
+type calibrationCapture struct {
+	sumSquares float64
+	count      int
+	need       int
+	done       chan struct{}
+}
+
+// calibrationCaptures holds the calibration captures in progress, at most one per source.
+type calibrationCaptures struct {
+	mu       sync.Mutex
+	captures map[string]*calibrationCapture
+}
+
+// uiSpectrogramCalibration collects calibration tones from the samples entering the UI
+// spectrogram, before any calibration gain is applied.
+var uiSpectrogramCalibration = &calibrationCaptures{captures: make(map[string]*calibrationCapture)}
+
+// observe adds the samples of a source to its capture, if one is running.
+func (c *calibrationCaptures) observe(source string, samples []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	capture, ok := c.captures[source]
+	if !ok || capture.count >= capture.need {
+		return
+	}
+	for _, s := range samples {
+		capture.sumSquares += float64(s) * float64(s)
+	}
+	capture.count += len(samples)
+	if capture.count >= capture.need {
+		close(capture.done)
+	}
+}
+
+// CaptureUiSpectrogramCalibration measures the RMS level in dBFS of a calibration tone
+// played into source for the given duration. It blocks until enough samples arrived or ctx
+// is done. Only one capture can run per source.
+func CaptureUiSpectrogramCalibration(ctx context.Context, source string, duration time.Duration) (float64, error) {
+	capture := &calibrationCapture{
+		need: max(int(duration.Seconds()*conf.SampleRate), 1),
+		done: make(chan struct{}),
+	}
+
+	c := uiSpectrogramCalibration
+	c.mu.Lock()
+	if _, busy := c.captures[source]; busy {
+		c.mu.Unlock()
+		return 0, errors.Newf("a calibration is already running for source %s", source).
+			Component("myaudio").
+			Category(errors.CategoryState).
+			Context("source", source).
+			Build()
+	}
+	c.captures[source] = capture
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.captures, source)
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-capture.done:
+	case <-ctx.Done():
+		return 0, errors.New(ctx.Err()).
+			Component("myaudio").
+			Category(errors.CategoryTimeout).
+			Context("source", source).
+			Context("operation", "calibration_capture").
+			Build()
+	}
+
+	c.mu.Lock()
+	level := 10 * math.Log10(capture.sumSquares/float64(capture.count))
+	c.mu.Unlock()
+
+	if math.IsInf(level, -1) || level < minCalibrationToneDBFS {
+		return 0, errors.Newf("calibration tone on source %s is too quiet to measure", source).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("source", source).
+			Context("level_dbfs", level).
+			Build()
+	}
+	return level, nil
+}
+
+// NewUiSpectrogramCalibration returns the calibration that displays a tone measured at
+// measuredDBFS at the configured reference level.
+func NewUiSpectrogramCalibration(source string, measuredDBFS, toneLevel float64, settings *conf.UiSpectrogramSettings) UiSpectrogramCalibration {
+	return UiSpectrogramCalibration{
+		Source:        source,
+		MeasuredDBFS:  measuredDBFS,
+		ReferenceDBFS: settings.CalibrationReference,
+		GainDB:        settings.CalibrationReference - measuredDBFS,
+		ToneLevel:     toneLevel,
+	}
+}
+
+// applyCalibrationGain scales samples by the calibration gain in dB, clipping them to full
+// scale like an input stage would.
+func applyCalibrationGain(samples []float32, gainDB float64) {
+	if gainDB == 0 {
+		return
+	}
+	gain := float32(math.Pow(10, gainDB/20))
+	for i, s := range samples {
+		samples[i] = max(-1, min(1, s*gain))
+	}
+}
@@ -0,0 +1,119 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// calibrationTone returns 1024 16-bit samples of a sine at the given RMS level in dBFS,
+// with a whole number of cycles in every 512-sample spectrogram window.
+func calibrationTone(levelDBFS float64) []byte {
+	amplitude := math.Sqrt2 * math.Pow(10, levelDBFS/20)
+	samples := make([]byte, 2048)
+	for i := range 1024 {
+		value := amplitude * math.Sin(2*math.Pi*8*float64(i)/512)
+		binary.LittleEndian.PutUint16(samples[i*2:], uint16(int16(math.Round(value*32767))))
+	}
+	return samples
+}
+
+// windowLevelDBFS returns the RMS level in dBFS of a spectrogram input window.
+func windowLevelDBFS(window []float32) float64 {
+	var sumSquares float64
+	for _, s := range window {
+		sumSquares += float64(s) * float64(s)
+	}
+	return 10 * math.Log10(sumSquares/float64(len(window)))
+}
+
+func TestUiSpectrogramCalibration_ToneMapsToReference(t *testing.T) {
+	const (
+		source    = "calibration_mic"
+		toneDBFS  = -32.0
+		reference = -20.0
+	)
+	settings := &conf.UiSpectrogramSettings{CalibrationReference: reference}
+	tone := calibrationTone(toneDBFS)
+
+	// Stand-in for the TFLite model that remembers the level of the last window it saw
+	var mu sync.Mutex
+	var columnLevel float64
+	column := func(window []float32) ([]byte, error) {
+		mu.Lock()
+		columnLevel = windowLevelDBFS(window)
+		mu.Unlock()
+		return make([]byte, UiSpectrogramBins), nil
+	}
+
+	type result struct {
+		level float64
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		level, err := CaptureUiSpectrogramCalibration(t.Context(), source, 100*time.Millisecond)
+		done <- result{level, err}
+	}()
+
+	var measured result
+	require.Eventually(t, func() bool {
+		_, err := buildUiSpectrogramFrame(tone, source, settings, column)
+		assert.NoError(t, err)
+		select {
+		case measured = <-done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, time.Millisecond)
+	require.NoError(t, measured.err)
+	assert.InDelta(t, toneDBFS, measured.level, 0.1)
+
+	calibration := NewUiSpectrogramCalibration(source, measured.level, 94, settings)
+	assert.InDelta(t, reference-toneDBFS, calibration.GainDB, 0.1)
+	settings.CalibrationGains = map[string]float64{source: calibration.GainDB}
+
+	_, err := buildUiSpectrogramFrame(tone, source, settings, column)
+	require.NoError(t, err)
+	mu.Lock()
+	assert.InDelta(t, reference, columnLevel, 0.1, "the calibration tone is displayed at the reference level")
+	mu.Unlock()
+
+	_, err = buildUiSpectrogramFrame(tone, "other_mic", settings, column)
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.InDelta(t, toneDBFS, columnLevel, 0.1, "the gain only applies to the calibrated source")
+}
+
+func TestCaptureUiSpectrogramCalibration_RejectsSilence(t *testing.T) {
+	const source = "silent_mic"
+	settings := &conf.UiSpectrogramSettings{}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := CaptureUiSpectrogramCalibration(t.Context(), source, 50*time.Millisecond)
+		done <- err
+	}()
+
+	var err error
+	require.Eventually(t, func() bool {
+		_, buildErr := buildUiSpectrogramFrame(make([]byte, 2048), source, settings, fakeUiSpectrogramColumn)
+		assert.NoError(t, buildErr)
+		select {
+		case err = <-done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too quiet")
+}
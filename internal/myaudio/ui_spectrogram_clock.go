@@ -0,0 +1,95 @@
+package myaudio
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// defaultClockMaxCorrection bounds a resync when no positive maximum correction is configured
+	defaultClockMaxCorrection = 100 * time.Millisecond
+	// clockSkewLogThreshold is the skew above which a resync is logged
+	clockSkewLogThreshold = 50 * time.Millisecond
+	// clockSkewResetThreshold is the skew at which the base is re-anchored instead of corrected
+	// gradually, e.g. after capture stalled or the wall clock was stepped by minutes
+	clockSkewResetThreshold = 10 * time.Second
+)
+
+// sampleClock timestamps each source's frames by the number of samples captured since a
+// wall clock base, so frames are spaced by the audio they hold rather than by when they
+// happened to be processed. The base is resynced to the wall clock periodically, moving it
+// by a bounded amount each time so NTP adjustments don't make the time axis jump.
+type sampleClock struct {
+	mu      sync.Mutex
+	sources map[string]*sampleClockSource
+}
+
+// sampleClockSource is the timestamp base of one source.
+type sampleClockSource struct {
+	base     time.Time // Wall clock time of the first sample counted
+	samples  int64     // Samples captured since base
+	lastSync time.Time // Wall clock time of the last resync
+}
+
+// uiSpectrogramClock timestamps UI spectrogram frames when clock resync is enabled.
+var uiSpectrogramClock = newSampleClock()
+
+// newSampleClock creates a sample clock without any sources.
+func newSampleClock() *sampleClock {
+	return &sampleClock{sources: make(map[string]*sampleClockSource)}
+}
+
+// next counts frameSamples more samples for source and returns the capture time of the last
+// one. now is the wall clock reading when the frame arrived; once resync has passed since the
+// last resync, the base is moved towards it by at most maxCorrection.
+func (c *sampleClock) next(source string, frameSamples int, now time.Time, resync, maxCorrection time.Duration) time.Time {
+	if maxCorrection <= 0 {
+		maxCorrection = defaultClockMaxCorrection
+	}
+	now = now.Round(0) // Drift is measured against the wall clock, not the monotonic one
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	src, ok := c.sources[source]
+	if !ok {
+		src = &sampleClockSource{base: now.Add(-samplesDuration(int64(frameSamples))), lastSync: now}
+		c.sources[source] = src
+	}
+	src.samples += int64(frameSamples)
+	ts := src.base.Add(samplesDuration(src.samples))
+
+	if now.Sub(src.lastSync) < resync {
+		return ts
+	}
+	src.lastSync = now
+
+	skew := now.Sub(ts)
+	if skew.Abs() >= clockSkewResetThreshold {
+		GetLogger().Warn("UI spectrogram sample clock too far from wall clock, re-anchoring",
+			logger.String("source", source),
+			logger.Duration("skew", skew))
+		src.base, src.samples = now, 0
+		return now
+	}
+
+	correction := min(max(skew, -maxCorrection), maxCorrection)
+	src.base = src.base.Add(correction)
+	if skew.Abs() > clockSkewLogThreshold {
+		GetLogger().Info("UI spectrogram sample clock drifted from wall clock, correcting",
+			logger.String("source", source),
+			logger.Duration("skew", skew),
+			logger.Duration("correction", correction))
+	}
+	return ts.Add(correction)
+}
+
+// samplesDuration returns how long n samples last at the capture sample rate. Whole seconds
+// are split off first so sample counts of long runs don't overflow the nanosecond product.
+func samplesDuration(n int64) time.Duration {
+	rate := int64(conf.SampleRate)
+	return time.Duration(n/rate)*time.Second + time.Duration(n%rate)*time.Second/time.Duration(rate)
+}
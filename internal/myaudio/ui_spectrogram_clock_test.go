@@ -0,0 +1,75 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleClock_ResyncCorrectsDriftWithinBounds(t *testing.T) {
+	t.Parallel()
+
+	const (
+		frameSamples  = 1024
+		resync        = time.Second
+		maxCorrection = 20 * time.Millisecond
+	)
+	frame := samplesDuration(frameSamples)
+	// The wall clock runs 1% fast against the sample clock, 10 ms per second
+	wallFrame := frame + frame/100
+
+	clock := newSampleClock()
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	now := start
+	prev := clock.next("drift", frameSamples, now, resync, maxCorrection)
+	require.True(t, prev.Equal(now), "the first frame is anchored to the wall clock")
+
+	for range 1000 {
+		now = now.Add(wallFrame)
+		ts := clock.next("drift", frameSamples, now, resync, maxCorrection)
+
+		step := ts.Sub(prev) - frame
+		assert.GreaterOrEqual(t, step, time.Duration(0), "the wall clock runs fast, so corrections move forward")
+		assert.LessOrEqual(t, step, maxCorrection, "no correction may exceed the bound")
+		prev = ts
+	}
+
+	// Without resync the base would be ~460 ms behind after 46 s; resync keeps it within
+	// one resync interval of drift
+	assert.Less(t, now.Sub(prev), 2*maxCorrection)
+}
+
+func TestSampleClock_NoResyncBeforeInterval(t *testing.T) {
+	t.Parallel()
+
+	clock := newSampleClock()
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	clock.next("steady", 1024, start, time.Minute, 0)
+
+	// The wall clock jumps ahead, but frames keep their sample spacing until the next resync
+	ts := clock.next("steady", 1024, start.Add(time.Second), time.Minute, 0)
+	assert.True(t, ts.Equal(start.Add(samplesDuration(1024))))
+}
+
+func TestSampleClock_LargeSkewReanchors(t *testing.T) {
+	t.Parallel()
+
+	clock := newSampleClock()
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	clock.next("stalled", 1024, start, time.Second, 0)
+
+	// Capture stalled for a minute; a bounded correction would take far too long to catch up
+	later := start.Add(time.Minute)
+	assert.True(t, clock.next("stalled", 1024, later, time.Second, 0).Equal(later))
+	assert.True(t, clock.next("stalled", 1024, later, time.Second, 0).Equal(later.Add(samplesDuration(1024))))
+}
+
+func TestSamplesDuration_LongRunsDontOverflow(t *testing.T) {
+	t.Parallel()
+
+	week := int64(7 * 24 * 3600 * 22050)
+	assert.Equal(t, 7*24*time.Hour, samplesDuration(week))
+	assert.Equal(t, time.Second/2, samplesDuration(22050/2))
+}
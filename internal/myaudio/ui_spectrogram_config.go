@@ -0,0 +1,85 @@
+package myaudio
+
+import (
+	"maps"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// UiSpectrogramConfig is the spectrogram configuration a running UI spectrogram session has
+// applied, which can differ from the settings after they were changed in place.
+type UiSpectrogramConfig struct {
+	Running          bool              `json:"running"`
+	SessionID        string            `json:"sessionId,omitempty"`
+	Mode             string            `json:"mode"`
+	Palette          string            `json:"palette"`
+	SourcePalettes   map[string]string `json:"sourcePalettes,omitempty"`
+	WindowSize       int               `json:"windowSize"`       // Samples behind each column
+	MsPerColumn      float64           `json:"msPerColumn"`      // Column spacing after clamping to the supported range
+	ColumnsPerSecond float64           `json:"columnsPerSecond"` // Columns produced per second of audio
+	MinFrameInterval int               `json:"minFrameInterval"` // Minimum ms between a source's frame timestamps
+	VideoFPS         int               `json:"videoFps,omitempty"`
+	Sources          []string          `json:"sources"` // IDs of the audio sources feeding the spectrogram
+
+	CalibrationGains map[string]float64 `json:"calibrationGains,omitempty"` // dB applied to each source's samples before the FFT, by source ID
+}
+
+// NewUiSpectrogramConfig describes the spectrogram produced with settings. Sources are left
+// for the caller, which knows when they were registered.
+func NewUiSpectrogramConfig(settings *conf.UiSpectrogramSettings) UiSpectrogramConfig {
+	msPerColumn := uiSpectrogramHopMs(uiSpectrogramHop(settings.MsPerColumn))
+	config := UiSpectrogramConfig{
+		Mode:             settings.Mode,
+		Palette:          settings.Palette,
+		WindowSize:       uiSpectrogramWindowSize,
+		MsPerColumn:      msPerColumn,
+		ColumnsPerSecond: 1000 / msPerColumn,
+		MinFrameInterval: settings.MinFrameInterval,
+	}
+	if len(settings.SourcePalettes) > 0 {
+		config.SourcePalettes = maps.Clone(settings.SourcePalettes)
+	}
+	if len(settings.CalibrationGains) > 0 {
+		config.CalibrationGains = maps.Clone(settings.CalibrationGains)
+	}
+	if settings.Video.Enabled {
+		config.VideoFPS = settings.Video.FPS
+	}
+	return config
+}
+
+// UiSpectrogramStats counts the frames the UI spectrogram publisher handled, so a sluggish
+// feed can be told apart from a quiet one.
+type UiSpectrogramStats struct {
+	FramesReceived    uint64 `json:"framesReceived"`    // Frames read from the spectrogram channel
+	FramesBroadcast   uint64 `json:"framesBroadcast"`   // Frames broadcast to SSE clients
+	FramesDropped     uint64 `json:"framesDropped"`     // Frames whose broadcast failed
+	FramesOverflowed  uint64 `json:"framesOverflowed"`  // Frames discarded by the overflow strategy because the spectrogram channel was full
+	FramesCollapsed   uint64 `json:"framesCollapsed"`   // Frames not broadcast because they matched the previous frame of their source
+	FramesThrottled   uint64 `json:"framesThrottled"`   // Frames discarded to keep a source's broadcasts under the maximum frame rate
+	FramesCoalesced   uint64 `json:"framesCoalesced"`   // Frames replaced by a newer one while a broadcast to slow clients was in progress
+	FramesTruncated   uint64 `json:"framesTruncated"`   // Frames down-resolved to fit under the encoded frame size cap
+	PublisherRestarts uint64 `json:"publisherRestarts"` // Times the publisher recovered from a panic and restarted
+	ChannelDepth      int    `json:"channelDepth"`      // Frames queued in the spectrogram channel when it was last sampled
+	ChannelCapacity   int    `json:"channelCapacity"`   // Frames the spectrogram channel can hold
+}
+
+// UiSpectrogramHealthStatus is the health of the UI spectrogram pipeline
+type UiSpectrogramHealthStatus string
+
+// Health states of the UI spectrogram pipeline
+const (
+	UiSpectrogramHealthy  UiSpectrogramHealthStatus = "healthy"  // Frames flow and broadcasts succeed
+	UiSpectrogramDegraded UiSpectrogramHealthStatus = "degraded" // Running, but broadcasts keep failing or no frames arrive
+	UiSpectrogramStopped  UiSpectrogramHealthStatus = "stopped"  // Monitoring isn't running
+)
+
+// UiSpectrogramHealth reports the health of the UI spectrogram pipeline with what it was
+// judged on.
+type UiSpectrogramHealth struct {
+	Status    UiSpectrogramHealthStatus `json:"status"`
+	ErrorRate float64                   `json:"errorRate"`          // Share of recent broadcasts that failed
+	LastFrame time.Time                 `json:"lastFrame,omitzero"` // When the publisher last received a frame
+	LastError time.Time                 `json:"lastError,omitzero"` // When a broadcast last failed
+}
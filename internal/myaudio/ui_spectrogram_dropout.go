@@ -0,0 +1,160 @@
+package myaudio
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Source statuses reported by the UI spectrogram dropout monitor
+const (
+	UiSpectrogramSourceStalled = "stalled"
+	UiSpectrogramSourceResumed = "resumed"
+)
+
+const (
+	// minDropoutCheckInterval bounds how often the dropout monitor checks for stalled sources
+	minDropoutCheckInterval = 100 * time.Millisecond
+	// dropoutChecksPerTimeout is how many checks run per stall timeout, so a stall is
+	// reported within a quarter of the timeout of it elapsing
+	dropoutChecksPerTimeout = 4
+)
+
+// UiSpectrogramSourceStatus reports that a source stopped feeding the UI spectrogram, or that
+// its audio came back after a stall.
+type UiSpectrogramSourceStatus struct {
+	Source     string        `json:"source"`
+	Status     string        `json:"status"`               // UiSpectrogramSourceStalled or UiSpectrogramSourceResumed
+	LastSample time.Time     `json:"lastSample"`           // When the source last produced samples before the stall
+	StalledFor time.Duration `json:"stalledFor,omitempty"` // How long the stall lasted, set on resumed events
+	Timestamp  time.Time     `json:"timestamp"`            // When the status change was detected
+}
+
+// dropoutDetector tracks when each source last produced samples. Every source is reported
+// stalled once per dropout, and resumed by the first frame after it.
+type dropoutDetector struct {
+	mu      sync.Mutex
+	sources map[string]*dropoutSource
+}
+
+// dropoutSource is the dropout state of one source.
+type dropoutSource struct {
+	lastSample time.Time
+	stalled    bool
+}
+
+var (
+	// uiSpectrogramDropouts watches the sources of live UI spectrogram frames
+	uiSpectrogramDropouts = newDropoutDetector()
+
+	sourceStatusHandlerMu sync.RWMutex
+	sourceStatusHandler   func(UiSpectrogramSourceStatus)
+)
+
+// newDropoutDetector creates a detector that hasn't seen any source.
+func newDropoutDetector() *dropoutDetector {
+	return &dropoutDetector{sources: make(map[string]*dropoutSource)}
+}
+
+// SetUiSpectrogramSourceStatusHandler sets the function that receives source stall and resume
+// events. A nil handler discards them.
+func SetUiSpectrogramSourceStatusHandler(handler func(UiSpectrogramSourceStatus)) {
+	sourceStatusHandlerMu.Lock()
+	defer sourceStatusHandlerMu.Unlock()
+	sourceStatusHandler = handler
+}
+
+// ObserveUiSpectrogramFrame records that a frame was produced for data's source, reporting the
+// source as resumed if it was stalled. It must see every produced frame, including those
+// detection triggering holds back, since those still carry audio.
+func ObserveUiSpectrogramFrame(data *UiSpectrogramData, settings *conf.UiSpectrogramSettings) {
+	if settings.StallTimeout <= 0 {
+		return
+	}
+	if status, resumed := uiSpectrogramDropouts.observe(data.Source, time.Now()); resumed {
+		emitSourceStatus(&status)
+	}
+}
+
+// MonitorUiSpectrogramDropouts reports sources that haven't produced a frame within the stall
+// timeout until stop is closed. Each stall is counted in the stall metric.
+func MonitorUiSpectrogramDropouts(stop <-chan struct{}, settings *conf.UiSpectrogramSettings) {
+	if settings.StallTimeout <= 0 {
+		return
+	}
+	timeout := time.Duration(settings.StallTimeout) * time.Millisecond
+	ticker := time.NewTicker(max(timeout/dropoutChecksPerTimeout, minDropoutCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			for _, status := range uiSpectrogramDropouts.check(now, timeout) {
+				if m := getAnalysisMetrics(); m != nil {
+					m.RecordUiSpectrogramStall(status.Source)
+				}
+				emitSourceStatus(&status)
+			}
+		}
+	}
+}
+
+// emitSourceStatus hands status to the handler, if one is set.
+func emitSourceStatus(status *UiSpectrogramSourceStatus) {
+	sourceStatusHandlerMu.RLock()
+	handler := sourceStatusHandler
+	sourceStatusHandlerMu.RUnlock()
+
+	if handler != nil {
+		handler(*status)
+	}
+}
+
+// observe records a frame of source at now. It returns a resumed status and true when the
+// source was stalled.
+func (d *dropoutDetector) observe(source string, now time.Time) (UiSpectrogramSourceStatus, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	src, ok := d.sources[source]
+	if !ok {
+		d.sources[source] = &dropoutSource{lastSample: now}
+		return UiSpectrogramSourceStatus{}, false
+	}
+
+	status := UiSpectrogramSourceStatus{
+		Source:     source,
+		Status:     UiSpectrogramSourceResumed,
+		LastSample: src.lastSample,
+		StalledFor: now.Sub(src.lastSample),
+		Timestamp:  now,
+	}
+	resumed := src.stalled
+	src.lastSample, src.stalled = now, false
+	return status, resumed
+}
+
+// check returns a stalled status for every source whose last frame is more than timeout
+// before now and that wasn't already reported.
+func (d *dropoutDetector) check(now time.Time, timeout time.Duration) []UiSpectrogramSourceStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var stalled []UiSpectrogramSourceStatus
+	for source, src := range d.sources {
+		if src.stalled || now.Sub(src.lastSample) <= timeout {
+			continue
+		}
+		src.stalled = true
+		stalled = append(stalled, UiSpectrogramSourceStatus{
+			Source:     source,
+			Status:     UiSpectrogramSourceStalled,
+			LastSample: src.lastSample,
+			Timestamp:  now,
+		})
+	}
+	return stalled
+}
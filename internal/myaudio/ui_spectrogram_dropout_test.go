@@ -0,0 +1,78 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestDropoutDetector_StallAndResume(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 2 * time.Second
+	detector := newDropoutDetector()
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	_, resumed := detector.observe("mic", start)
+	assert.False(t, resumed, "a new source isn't a resume")
+	assert.Empty(t, detector.check(start.Add(timeout), timeout), "a source isn't stalled until the timeout has passed")
+
+	// The audio stops; the stall is reported once however often it is checked
+	stalled := detector.check(start.Add(timeout+time.Millisecond), timeout)
+	require.Len(t, stalled, 1)
+	assert.Equal(t, "mic", stalled[0].Source)
+	assert.Equal(t, UiSpectrogramSourceStalled, stalled[0].Status)
+	assert.Equal(t, start, stalled[0].LastSample)
+	assert.Empty(t, detector.check(start.Add(10*time.Second), timeout))
+
+	// The first frame after the stall resumes the source
+	back := start.Add(12 * time.Second)
+	status, resumed := detector.observe("mic", back)
+	require.True(t, resumed)
+	assert.Equal(t, UiSpectrogramSourceResumed, status.Status)
+	assert.Equal(t, 12*time.Second, status.StalledFor)
+	assert.Equal(t, back, status.Timestamp)
+
+	_, resumed = detector.observe("mic", back.Add(100*time.Millisecond))
+	assert.False(t, resumed, "only the first frame after a stall resumes the source")
+	assert.Empty(t, detector.check(back.Add(timeout), timeout))
+}
+
+func TestMonitorUiSpectrogramDropouts_EmitsStalledThenResumed(t *testing.T) {
+	statuses := make(chan UiSpectrogramSourceStatus, 4)
+	SetUiSpectrogramSourceStatusHandler(func(s UiSpectrogramSourceStatus) { statuses <- s })
+	t.Cleanup(func() { SetUiSpectrogramSourceStatusHandler(nil) })
+
+	settings := &conf.UiSpectrogramSettings{StallTimeout: 200}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		MonitorUiSpectrogramDropouts(stop, settings)
+	}()
+	t.Cleanup(func() { close(stop); <-done })
+
+	frame := &UiSpectrogramData{Source: "dropout-test"}
+	ObserveUiSpectrogramFrame(frame, settings)
+
+	// No frames arrive, simulating a stalled source
+	select {
+	case s := <-statuses:
+		assert.Equal(t, "dropout-test", s.Source)
+		assert.Equal(t, UiSpectrogramSourceStalled, s.Status)
+	case <-time.After(2 * time.Second):
+		t.Fatal("a stalled source must be reported")
+	}
+
+	ObserveUiSpectrogramFrame(frame, settings)
+	select {
+	case s := <-statuses:
+		assert.Equal(t, UiSpectrogramSourceResumed, s.Status)
+		assert.GreaterOrEqual(t, s.StalledFor, 200*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Fatal("recovery must be reported as resumed")
+	}
+}
@@ -0,0 +1,71 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// useAnalysisMetrics swaps in m as the package metrics instance for the duration of the test.
+func useAnalysisMetrics(t *testing.T, m *metrics.MyAudioMetrics) {
+	t.Helper()
+
+	analysisMetricsMutex.Lock()
+	previous := analysisMetrics
+	analysisMetrics = m
+	analysisMetricsMutex.Unlock()
+
+	t.Cleanup(func() {
+		analysisMetricsMutex.Lock()
+		analysisMetrics = previous
+		analysisMetricsMutex.Unlock()
+	})
+}
+
+func TestComputeUiSpectrogramFrame_RecordsFFTDuration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := metrics.NewMyAudioMetrics(registry)
+	require.NoError(t, err)
+	useAnalysisMetrics(t, m)
+
+	const (
+		frames      = 3
+		columnDelay = 2 * time.Millisecond
+	)
+
+	// Stand-in for the TFLite model: sleeps so every frame takes at least two column delays
+	column := func(sample []float32) ([]byte, error) {
+		time.Sleep(columnDelay)
+		return make([]byte, UiSpectrogramBins), nil
+	}
+
+	windows := [][]float32{make([]float32, 512), make([]float32, 512)}
+	for range frames {
+		frame, err := computeUiSpectrogramFrame(windows, "test_source", column)
+		require.NoError(t, err)
+		assert.Len(t, frame, UiSpectrogramBins*2)
+	}
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "ui_spectrogram_fft_seconds" {
+			continue
+		}
+		found = true
+		require.Len(t, family.GetMetric(), 1)
+		histogram := family.GetMetric()[0].GetHistogram()
+
+		assert.Equal(t, uint64(frames), histogram.GetSampleCount(), "one observation per produced frame")
+		minTotal := (frames * 2 * columnDelay).Seconds()
+		assert.GreaterOrEqual(t, histogram.GetSampleSum(), minTotal)
+		assert.Less(t, histogram.GetSampleSum(), 1.0, "frame FFT time should be well under a second")
+	}
+	assert.True(t, found, "ui_spectrogram_fft_seconds should be registered")
+}
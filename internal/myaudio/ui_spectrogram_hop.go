@@ -0,0 +1,53 @@
+package myaudio
+
+import (
+	"math"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// uiSpectrogramHop converts a ms-per-column setting into the hop between column windows in
+// samples, clamped to the supported range. Zero yields the window size, so columns don't overlap.
+func uiSpectrogramHop(msPerColumn float64) int {
+	if msPerColumn <= 0 {
+		return uiSpectrogramWindowSize
+	}
+	hop := int(math.Round(msPerColumn * conf.SampleRate / 1000))
+	return min(max(hop, uiSpectrogramWindowSize/8), uiSpectrogramWindowSize)
+}
+
+// uiSpectrogramHopMs returns the column spacing in ms produced by a hop in samples.
+func uiSpectrogramHopMs(hop int) float64 {
+	return float64(hop) * 1000 / conf.SampleRate
+}
+
+// uiSpectrogramColumnWindows carries each source's samples that haven't started a column yet
+// into its next frame, so columns stay evenly spaced across frames when the hop doesn't divide
+// the frame length.
+type uiSpectrogramColumnWindows struct {
+	mu    sync.Mutex
+	tails map[string][]float32
+}
+
+// uiSpectrogramWindows is shared by all sources; each source keeps its own tail.
+var uiSpectrogramWindows = &uiSpectrogramColumnWindows{tails: make(map[string][]float32)}
+
+// next returns the column windows, hop samples apart, that the source's carried samples and
+// input complete. The windows view a buffer owned by this call and stay valid after it returns.
+func (w *uiSpectrogramColumnWindows) next(source string, input []float32, hop int) [][]float32 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tail := w.tails[source]
+	buffer := make([]float32, 0, len(tail)+len(input))
+	buffer = append(append(buffer, tail...), input...)
+
+	var windows [][]float32
+	start := 0
+	for ; start+uiSpectrogramWindowSize <= len(buffer); start += hop {
+		windows = append(windows, buffer[start:start+uiSpectrogramWindowSize])
+	}
+	w.tails[source] = append(tail[:0], buffer[min(start, len(buffer)):]...)
+	return windows
+}
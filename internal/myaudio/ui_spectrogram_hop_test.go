@@ -0,0 +1,59 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestBuildUiSpectrogramFrame_MsPerColumnSpacesColumns(t *testing.T) {
+	t.Parallel()
+
+	const msPerColumn = 10.0
+	hop := uiSpectrogramHop(msPerColumn)
+	require.Equal(t, 221, hop, "10 ms at 22050 Hz rounds to 221 samples")
+
+	// Each sample holds its position in the stream, so a window's first sample tells where it starts
+	var starts []int
+	column := func(window []float32) ([]byte, error) {
+		starts = append(starts, int(math.Round(float64(window[0])*32768)))
+		return make([]byte, UiSpectrogramBins), nil
+	}
+
+	settings := &conf.UiSpectrogramSettings{MsPerColumn: msPerColumn}
+	var columns int
+	for f := range 6 {
+		samples := make([]byte, 2048)
+		for i := range 1024 {
+			binary.LittleEndian.PutUint16(samples[i*2:], uint16(int16(f*1024+i)))
+		}
+		frame, err := buildUiSpectrogramFrame(samples, "ms-per-column-test", settings, column)
+		require.NoError(t, err)
+
+		assert.InDelta(t, 1000*float64(hop)/conf.SampleRate, frame.MsPerColumn, 1e-9, "frames report the effective spacing")
+		require.Zero(t, len(frame.Spectrogram)%UiSpectrogramBins)
+		columns += len(frame.Spectrogram) / UiSpectrogramBins
+	}
+
+	require.Len(t, starts, columns)
+	for i := 1; i < len(starts); i++ {
+		assert.Equal(t, hop, starts[i]-starts[i-1], "column %d must start one hop after column %d, across frame boundaries too", i, i-1)
+	}
+}
+
+func TestUiSpectrogramHop_DefaultsAndClamps(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uiSpectrogramWindowSize, uiSpectrogramHop(0), "unset keeps non-overlapping columns")
+	assert.Equal(t, uiSpectrogramWindowSize, uiSpectrogramHop(100), "columns can't be further apart than a window")
+	assert.Equal(t, uiSpectrogramWindowSize/8, uiSpectrogramHop(0.1))
+
+	frame, err := buildUiSpectrogramFrame(make([]byte, 2048), "ms-per-column-default", &conf.UiSpectrogramSettings{}, fakeUiSpectrogramColumn)
+	require.NoError(t, err)
+	assert.Len(t, frame.Spectrogram, 2*UiSpectrogramBins)
+	assert.InDelta(t, conf.UiSpectrogramMaxMsPerColumn, frame.MsPerColumn, 1e-9)
+}
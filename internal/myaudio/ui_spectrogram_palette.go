@@ -0,0 +1,117 @@
+package myaudio
+
+import (
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// UiSpectrogramPalette maps UI spectrogram intensities to display colors.
+type UiSpectrogramPalette struct {
+	Name   string
+	colors [256][3]uint8
+}
+
+// Color returns the RGB color for an intensity.
+func (p *UiSpectrogramPalette) Color(v byte) (r, g, b uint8) {
+	c := p.colors[v]
+	return c[0], c[1], c[2]
+}
+
+// ColorAt returns the RGB color for a normalized magnitude, 0 for silence and 1 for the
+// loudest level. Magnitudes outside that range are clamped.
+func (p *UiSpectrogramPalette) ColorAt(magnitude float64) (r, g, b uint8) {
+	if math.IsNaN(magnitude) {
+		magnitude = 0
+	}
+	return p.Color(byte(math.Round(min(max(magnitude, 0), 1) * math.MaxUint8)))
+}
+
+// paletteStop anchors a gradient palette at an intensity.
+type paletteStop struct {
+	at      byte
+	r, g, b uint8
+}
+
+// newGradientPalette builds a palette by linear interpolation between stops, which must be
+// sorted and span 0 to 255.
+func newGradientPalette(name string, stops []paletteStop) *UiSpectrogramPalette {
+	p := &UiSpectrogramPalette{Name: name}
+	for i := 1; i < len(stops); i++ {
+		from, to := stops[i-1], stops[i]
+		span := float64(to.at) - float64(from.at)
+		for v := int(from.at); v <= int(to.at); v++ {
+			t := (float64(v) - float64(from.at)) / span
+			p.colors[v] = [3]uint8{lerpByte(from.r, to.r, t), lerpByte(from.g, to.g, t), lerpByte(from.b, to.b, t)}
+		}
+	}
+	return p
+}
+
+// lerpByte interpolates between two color channel values.
+func lerpByte(from, to uint8, t float64) uint8 {
+	return uint8(float64(from) + (float64(to)-float64(from))*t + 0.5)
+}
+
+// uiSpectrogramPalettes holds the built-in palettes by name.
+var uiSpectrogramPalettes = map[string]*UiSpectrogramPalette{
+	conf.UiSpectrogramPaletteGrayscale: newGradientPalette(conf.UiSpectrogramPaletteGrayscale, []paletteStop{
+		{0, 0, 0, 0}, {255, 255, 255, 255},
+	}),
+	conf.UiSpectrogramPaletteViridis: newGradientPalette(conf.UiSpectrogramPaletteViridis, []paletteStop{
+		{0, 68, 1, 84}, {64, 59, 82, 139}, {128, 33, 145, 140}, {191, 94, 201, 98}, {255, 253, 231, 37},
+	}),
+	conf.UiSpectrogramPaletteMagma: newGradientPalette(conf.UiSpectrogramPaletteMagma, []paletteStop{
+		{0, 0, 0, 4}, {64, 81, 18, 124}, {128, 183, 55, 121}, {191, 252, 137, 97}, {255, 252, 253, 191},
+	}),
+	conf.UiSpectrogramPaletteInferno: newGradientPalette(conf.UiSpectrogramPaletteInferno, []paletteStop{
+		{0, 0, 0, 4}, {64, 87, 16, 110}, {128, 188, 55, 84}, {191, 249, 142, 9}, {255, 252, 255, 164},
+	}),
+}
+
+// warnedUiSpectrogramPalettes records unknown palette names already warned about, since the
+// palette is resolved for every frame.
+var warnedUiSpectrogramPalettes sync.Map
+
+// LookupUiSpectrogramPalette returns the built-in palette with the given name.
+func LookupUiSpectrogramPalette(name string) (*UiSpectrogramPalette, bool) {
+	p, ok := uiSpectrogramPalettes[name]
+	return p, ok
+}
+
+// ParseUiSpectrogramPalette returns the built-in palette with the given name, or an error
+// listing the valid names for renderers that take the palette from a request.
+func ParseUiSpectrogramPalette(name string) (*UiSpectrogramPalette, error) {
+	if p, ok := uiSpectrogramPalettes[name]; ok {
+		return p, nil
+	}
+	return nil, errors.Newf("unknown spectrogram palette %q, valid palettes are %s",
+		name, strings.Join(conf.UiSpectrogramPalettes, ", ")).
+		Component("myaudio").
+		Category(errors.CategoryValidation).
+		Context("operation", "ui_spectrogram_palette").
+		Context("palette", name).
+		Build()
+}
+
+// resolveUiSpectrogramPalette returns the named palette, or the default palette when the
+// name is empty or unknown. Settings validation already replaces unknown names with the
+// default; this guards settings changed at runtime, warning once per unknown name.
+func resolveUiSpectrogramPalette(name string, log logger.Logger) *UiSpectrogramPalette {
+	if p, ok := uiSpectrogramPalettes[name]; ok {
+		return p
+	}
+	if name != "" {
+		if _, warned := warnedUiSpectrogramPalettes.LoadOrStore(name, struct{}{}); !warned {
+			log.Warn("Unknown UI spectrogram palette, using default",
+				logger.String("palette", name),
+				logger.String("fallback", conf.DefaultUiSpectrogramPalette),
+				logger.String("operation", "ui_spectrogram_palette"))
+		}
+	}
+	return uiSpectrogramPalettes[conf.DefaultUiSpectrogramPalette]
+}
@@ -0,0 +1,137 @@
+package myaudio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+func TestResolveUiSpectrogramPalette_UnknownFallsBackWithWarning(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	log := logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+
+	p := resolveUiSpectrogramPalette("psychedelic-unknown", log)
+	assert.Equal(t, conf.DefaultUiSpectrogramPalette, p.Name)
+	assert.Contains(t, logs.String(), "Unknown UI spectrogram palette")
+	assert.Contains(t, logs.String(), "psychedelic-unknown")
+
+	// The name is resolved per frame, so the warning is only logged once
+	logs.Reset()
+	resolveUiSpectrogramPalette("psychedelic-unknown", log)
+	assert.Empty(t, logs.String())
+
+	// An unset palette uses the default silently
+	assert.Equal(t, conf.DefaultUiSpectrogramPalette, resolveUiSpectrogramPalette("", log).Name)
+	assert.Empty(t, logs.String())
+}
+
+func TestResolveUiSpectrogramPalette_ValidNamesHonored(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	log := logger.NewSlogLogger(&logs, logger.LogLevelInfo, time.UTC)
+
+	for _, name := range conf.UiSpectrogramPalettes {
+		p := resolveUiSpectrogramPalette(name, log)
+		require.Equal(t, name, p.Name)
+		_, ok := LookupUiSpectrogramPalette(name)
+		assert.True(t, ok, name)
+	}
+	assert.Empty(t, logs.String())
+
+	gray := resolveUiSpectrogramPalette(conf.UiSpectrogramPaletteGrayscale, log)
+	viridis := resolveUiSpectrogramPalette(conf.UiSpectrogramPaletteViridis, log)
+	r, g, b := gray.Color(255)
+	assert.Equal(t, [3]uint8{255, 255, 255}, [3]uint8{r, g, b})
+	r, g, b = viridis.Color(255)
+	assert.Equal(t, [3]uint8{253, 231, 37}, [3]uint8{r, g, b})
+
+	frame, err := buildUiSpectrogramFrame(make([]byte, 2048), "mic",
+		&conf.UiSpectrogramSettings{Palette: conf.UiSpectrogramPaletteViridis}, fakeUiSpectrogramColumn)
+	require.NoError(t, err)
+	assert.Equal(t, conf.UiSpectrogramPaletteViridis, frame.Palette)
+}
+
+func TestBuildUiSpectrogramFrame_SourcePalettes(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.UiSpectrogramSettings{
+		Palette: conf.UiSpectrogramPaletteGrayscale,
+		SourcePalettes: map[string]string{
+			"palette-feeder": conf.UiSpectrogramPaletteViridis,
+		},
+	}
+	column := func([]float32) ([]byte, error) {
+		values := make([]byte, UiSpectrogramBins)
+		for i := range values {
+			values[i] = byte(i)
+		}
+		return values, nil
+	}
+
+	backyard, err := buildUiSpectrogramFrame(make([]byte, 2048), "palette-backyard", settings, column)
+	require.NoError(t, err)
+	feeder, err := buildUiSpectrogramFrame(make([]byte, 2048), "palette-feeder", settings, column)
+	require.NoError(t, err)
+
+	assert.Equal(t, conf.UiSpectrogramPaletteGrayscale, backyard.Palette, "sources without an override use the global palette")
+	assert.Equal(t, conf.UiSpectrogramPaletteViridis, feeder.Palette)
+	require.Equal(t, backyard.Spectrogram, feeder.Spectrogram)
+
+	backyardPalette, ok := LookupUiSpectrogramPalette(backyard.Palette)
+	require.True(t, ok)
+	feederPalette, ok := LookupUiSpectrogramPalette(feeder.Palette)
+	require.True(t, ok)
+	v := backyard.Spectrogram[UiSpectrogramBins-1]
+	r1, g1, b1 := backyardPalette.Color(v)
+	r2, g2, b2 := feederPalette.Color(v)
+	assert.NotEqual(t, [3]uint8{r1, g1, b1}, [3]uint8{r2, g2, b2}, "identical input must map to different colors")
+}
+
+func TestUiSpectrogramPalettes_DistinctMonotonicRamps(t *testing.T) {
+	t.Parallel()
+
+	luminance := func(p *UiSpectrogramPalette, magnitude float64) float64 {
+		r, g, b := p.ColorAt(magnitude)
+		return 0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)
+	}
+
+	seen := map[[3]uint8]string{}
+	for _, name := range conf.UiSpectrogramPalettes {
+		p, err := ParseUiSpectrogramPalette(name)
+		require.NoError(t, err)
+
+		// Louder is brighter in every palette, so levels read the same whichever is chosen
+		for step := 1; step <= 16; step++ {
+			assert.Greater(t, luminance(p, float64(step)/16), luminance(p, float64(step-1)/16),
+				"%s at step %d", name, step)
+		}
+
+		r, g, b := p.ColorAt(0.5)
+		mid := [3]uint8{r, g, b}
+		assert.NotContains(t, seen, mid, "%s has the same midpoint color as %s", name, seen[mid])
+		seen[mid] = name
+	}
+
+	viridis, err := ParseUiSpectrogramPalette(conf.UiSpectrogramPaletteViridis)
+	require.NoError(t, err)
+	r, g, b := viridis.ColorAt(2)
+	assert.Equal(t, [3]uint8{253, 231, 37}, [3]uint8{r, g, b}, "magnitudes above 1 are clamped")
+}
+
+func TestParseUiSpectrogramPalette_Unknown(t *testing.T) {
+	t.Parallel()
+
+	p, err := ParseUiSpectrogramPalette("rainbow")
+	require.Error(t, err)
+	assert.Nil(t, p)
+	assert.Contains(t, err.Error(), `"rainbow"`)
+	assert.Contains(t, err.Error(), conf.UiSpectrogramPaletteMagma, "the error lists the valid palettes")
+}
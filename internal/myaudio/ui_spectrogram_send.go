@@ -0,0 +1,80 @@
+package myaudio
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const (
+	// dropOldestAttempts bounds how often drop-oldest evicts a frame before giving up, in case
+	// other senders keep refilling the channel.
+	dropOldestAttempts = 3
+
+	// uiSpectrogramBlockTimeout bounds how long block waits for room. Frames are sent from the
+	// shared capture loop, so a stuck publisher must not hold up BirdNET analysis for longer
+	// than a couple of frames.
+	uiSpectrogramBlockTimeout = 100 * time.Millisecond
+)
+
+// SendUiSpectrogramFrame delivers a frame to ch, handling a full channel with the given
+// overflow strategy. It reports whether the frame was delivered and how many frames were
+// dropped, counting evicted queued frames as well as the frame itself. Unknown strategies
+// act as drop-newest. With block it waits until ch has room or stop is closed, for at most
+// uiSpectrogramBlockTimeout, then falls back to drop-oldest. Every dropped frame is counted in
+// the frame drop metric.
+func SendUiSpectrogramFrame(ch chan UiSpectrogramData, data *UiSpectrogramData, strategy string, stop <-chan struct{}) (delivered bool, dropped int) {
+	select {
+	case ch <- *data:
+		return true, 0
+	default:
+	}
+
+	switch strategy {
+	case conf.UiSpectrogramOverflowBlock:
+		timer := time.NewTimer(uiSpectrogramBlockTimeout)
+		defer timer.Stop()
+		select {
+		case ch <- *data:
+			return true, 0
+		case <-stop:
+			return false, 0
+		case <-timer.C:
+			return sendUiSpectrogramFrameDropOldest(ch, data)
+		}
+
+	case conf.UiSpectrogramOverflowDropOldest:
+		return sendUiSpectrogramFrameDropOldest(ch, data)
+
+	default:
+		recordUiSpectrogramFrameDrop(data.Source, conf.UiSpectrogramOverflowDropNewest)
+		return false, 1
+	}
+}
+
+// sendUiSpectrogramFrameDropOldest evicts queued frames from ch until data fits, giving up
+// after dropOldestAttempts.
+func sendUiSpectrogramFrameDropOldest(ch chan UiSpectrogramData, data *UiSpectrogramData) (delivered bool, dropped int) {
+	for range dropOldestAttempts {
+		select {
+		case oldest := <-ch:
+			recordUiSpectrogramFrameDrop(oldest.Source, conf.UiSpectrogramOverflowDropOldest)
+			dropped++
+		default:
+		}
+		select {
+		case ch <- *data:
+			return true, dropped
+		default:
+		}
+	}
+	recordUiSpectrogramFrameDrop(data.Source, conf.UiSpectrogramOverflowDropOldest)
+	return false, dropped + 1
+}
+
+// recordUiSpectrogramFrameDrop counts a dropped frame when metrics are available.
+func recordUiSpectrogramFrameDrop(source, strategy string) {
+	if m := getAnalysisMetrics(); m != nil {
+		m.RecordUiSpectrogramFrameDrop(source, strategy)
+	}
+}
@@ -0,0 +1,117 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// fullSpectrogramChan returns a one-slot channel already holding a frame from source "old".
+func fullSpectrogramChan() chan UiSpectrogramData {
+	ch := make(chan UiSpectrogramData, 1)
+	ch <- UiSpectrogramData{Source: "old"}
+	return ch
+}
+
+// frameDropCount returns the frame drop counter for source and strategy.
+func frameDropCount(t *testing.T, registry *prometheus.Registry, source, strategy string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "ui_spectrogram_frame_drops_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["source"] == source && labels["strategy"] == strategy {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestSendUiSpectrogramFrame_OverflowStrategies(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := metrics.NewMyAudioMetrics(registry)
+	require.NoError(t, err)
+	useAnalysisMetrics(t, m)
+	drops := func(source, strategy string) float64 {
+		return frameDropCount(t, registry, source, strategy)
+	}
+	frame := &UiSpectrogramData{Source: "new"}
+
+	t.Run("drop-newest keeps the queued frame", func(t *testing.T) {
+		ch := fullSpectrogramChan()
+		delivered, dropped := SendUiSpectrogramFrame(ch, frame, conf.UiSpectrogramOverflowDropNewest, nil)
+		assert.False(t, delivered)
+		assert.Equal(t, 1, dropped)
+		assert.Equal(t, "old", (<-ch).Source)
+		assert.InDelta(t, 1, drops("new", conf.UiSpectrogramOverflowDropNewest), 0)
+	})
+
+	t.Run("unknown strategy acts as drop-newest", func(t *testing.T) {
+		ch := fullSpectrogramChan()
+		delivered, _ := SendUiSpectrogramFrame(ch, frame, "sometimes", nil)
+		assert.False(t, delivered)
+		assert.Equal(t, "old", (<-ch).Source)
+		assert.InDelta(t, 2, drops("new", conf.UiSpectrogramOverflowDropNewest), 0)
+	})
+
+	t.Run("drop-oldest replaces the queued frame", func(t *testing.T) {
+		ch := fullSpectrogramChan()
+		delivered, dropped := SendUiSpectrogramFrame(ch, frame, conf.UiSpectrogramOverflowDropOldest, nil)
+		assert.True(t, delivered)
+		assert.Equal(t, 1, dropped, "the evicted frame counts as dropped")
+		assert.Equal(t, "new", (<-ch).Source)
+		assert.InDelta(t, 1, drops("old", conf.UiSpectrogramOverflowDropOldest), 0)
+	})
+
+	t.Run("block waits for room", func(t *testing.T) {
+		ch := fullSpectrogramChan()
+		sent := make(chan bool)
+		go func() {
+			delivered, _ := SendUiSpectrogramFrame(ch, frame, conf.UiSpectrogramOverflowBlock, make(chan struct{}))
+			sent <- delivered
+		}()
+
+		select {
+		case <-sent:
+			t.Fatal("block must wait while the channel is full")
+		case <-time.After(uiSpectrogramBlockTimeout / 4):
+		}
+		assert.Equal(t, "old", (<-ch).Source)
+		assert.True(t, <-sent)
+		assert.Equal(t, "new", (<-ch).Source)
+	})
+
+	t.Run("block falls back to drop-oldest when the consumer is stuck", func(t *testing.T) {
+		ch := fullSpectrogramChan()
+		start := time.Now()
+		delivered, dropped := SendUiSpectrogramFrame(ch, frame, conf.UiSpectrogramOverflowBlock, make(chan struct{}))
+		assert.GreaterOrEqual(t, time.Since(start), uiSpectrogramBlockTimeout)
+		assert.True(t, delivered)
+		assert.Equal(t, 1, dropped, "the capture loop isn't held up past the block timeout")
+		assert.Equal(t, "new", (<-ch).Source)
+		assert.InDelta(t, 2, drops("old", conf.UiSpectrogramOverflowDropOldest), 0)
+	})
+
+	t.Run("block gives up when stopped", func(t *testing.T) {
+		ch := fullSpectrogramChan()
+		stop := make(chan struct{})
+		close(stop)
+		delivered, dropped := SendUiSpectrogramFrame(ch, frame, conf.UiSpectrogramOverflowBlock, stop)
+		assert.False(t, delivered)
+		assert.Zero(t, dropped, "an abandoned blocking send isn't a drop")
+		assert.Equal(t, "old", (<-ch).Source)
+	})
+}
@@ -0,0 +1,62 @@
+package myaudio
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// defaultMinFrameInterval is used when no positive minimum frame interval is configured
+	defaultMinFrameInterval = time.Millisecond
+
+	// timestampCorrectionLogInterval logs every Nth corrected timestamp to avoid log spam
+	timestampCorrectionLogInterval = 100
+)
+
+// frameTimestamps keeps each source's UI spectrogram frame timestamps strictly increasing,
+// so a wall clock stepped backwards (e.g. by NTP) can't break the client's time axis.
+type frameTimestamps struct {
+	mu          sync.Mutex
+	last        map[string]time.Time
+	corrections uint64
+}
+
+// uiSpectrogramTimestamps orders the timestamps of every captured UI spectrogram frame.
+var uiSpectrogramTimestamps = newFrameTimestamps()
+
+// newFrameTimestamps creates an empty timestamp tracker.
+func newFrameTimestamps() *frameTimestamps {
+	return &frameTimestamps{last: make(map[string]time.Time)}
+}
+
+// next returns the timestamp for source's next frame: ts, or the previous frame's timestamp
+// plus minInterval when ts is not at least that much later. It reports whether ts was corrected.
+func (f *frameTimestamps) next(source string, ts time.Time, minInterval time.Duration) (time.Time, bool) {
+	if minInterval <= 0 {
+		minInterval = defaultMinFrameInterval
+	}
+	// Compare wall clock readings: the monotonic reading would hide the very clock steps
+	// that clients see in the serialized timestamps.
+	ts = ts.Round(0)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	last, seen := f.last[source]
+	corrected := false
+	if earliest := last.Add(minInterval); seen && ts.Before(earliest) {
+		f.corrections++
+		if f.corrections == 1 || f.corrections%timestampCorrectionLogInterval == 0 {
+			GetLogger().Warn("UI spectrogram frame timestamp not increasing, nudging it forward",
+				logger.String("source", source),
+				logger.Duration("behind_by", earliest.Sub(ts)),
+				logger.Duration("min_interval", minInterval),
+				logger.Uint64("corrections", f.corrections))
+		}
+		ts, corrected = earliest, true
+	}
+	f.last[source] = ts
+	return ts, corrected
+}
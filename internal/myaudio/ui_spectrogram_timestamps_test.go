@@ -0,0 +1,83 @@
+package myaudio
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestFrameTimestamps_BackwardClockStepStaysMonotonic(t *testing.T) {
+	t.Parallel()
+
+	const frameInterval = 46 * time.Millisecond
+	timestamps := newFrameTimestamps()
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	// Frames at the capture rate, then the wall clock steps back a minute, then a duplicate
+	clock := []time.Time{
+		start,
+		start.Add(frameInterval),
+		start.Add(2 * frameInterval),
+		start.Add(-time.Minute),
+		start.Add(-time.Minute + frameInterval),
+		start.Add(-time.Minute + frameInterval),
+	}
+
+	var (
+		stamps      []time.Time
+		corrections int
+	)
+	for _, now := range clock {
+		ts, corrected := timestamps.next("mic", now, time.Millisecond)
+		stamps = append(stamps, ts)
+		if corrected {
+			corrections++
+		}
+	}
+
+	for i := 1; i < len(stamps); i++ {
+		assert.True(t, stamps[i].After(stamps[i-1]), "frame %d must be later than frame %d", i, i-1)
+	}
+	assert.Equal(t, 3, corrections, "only frames behind the previous timestamp are corrected")
+	assert.Equal(t, start.Add(2*frameInterval+time.Millisecond), stamps[3], "a backward step is nudged by the minimum interval")
+
+	// Each source keeps its own timeline
+	ts, corrected := timestamps.next("rtsp", start.Add(-time.Minute), time.Millisecond)
+	assert.False(t, corrected)
+	assert.Equal(t, start.Add(-time.Minute), ts)
+}
+
+func TestBuildUiSpectrogramFrame_TimestampsIncrease(t *testing.T) {
+	t.Parallel()
+
+	var previous time.Time
+	for range 5 {
+		frame, err := buildUiSpectrogramFrame(make([]byte, 2048), "timestamp-test", &conf.UiSpectrogramSettings{MinFrameInterval: 1}, fakeUiSpectrogramColumn)
+		require.NoError(t, err)
+		assert.True(t, frame.Timestamp.After(previous))
+		previous = frame.Timestamp
+	}
+}
+
+func TestBuildUiSpectrogramFrame_CaptureTimingSurvivesEncoding(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+	frame, err := buildUiSpectrogramFrame(make([]byte, 2048), "timing-test", &conf.UiSpectrogramSettings{}, fakeUiSpectrogramColumn)
+	after := time.Now()
+	require.NoError(t, err)
+	assert.False(t, frame.Timestamp.Before(before), "the timestamp is set when the frame is produced")
+	assert.False(t, frame.Timestamp.After(after))
+	assert.Equal(t, conf.SampleRate, frame.SampleRate)
+
+	encoded, err := json.Marshal(frame)
+	require.NoError(t, err)
+	var decoded UiSpectrogramData
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.True(t, frame.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, conf.SampleRate, decoded.SampleRate)
+}
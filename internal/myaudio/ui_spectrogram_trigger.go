@@ -0,0 +1,129 @@
+package myaudio
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const (
+	// defaultTriggerPreRoll is used when no positive pre-roll is configured
+	defaultTriggerPreRoll = 2 * time.Second
+	// defaultTriggerHold is used when no positive hold time is configured
+	defaultTriggerHold = 5 * time.Second
+	// maxTriggerPreRollFrames bounds each source's pre-roll buffer whatever the frame rate
+	maxTriggerPreRollFrames = 1024
+)
+
+// detectionTrigger holds back UI spectrogram frames until a detection is reported, so only
+// the audio around detections reaches clients. While idle, each source's most recent frames
+// are kept as pre-roll and are released ahead of the first frame after a detection.
+type detectionTrigger struct {
+	mu      sync.Mutex
+	sources map[string]*detectionTriggerSource
+	all     time.Time // Frames of every source pass until then, for detections of unknown source
+}
+
+// detectionTriggerSource is the trigger state of one source.
+type detectionTriggerSource struct {
+	activeUntil time.Time           // Frames pass until then
+	preRoll     []UiSpectrogramData // Frames held while idle, oldest first
+}
+
+// uiSpectrogramTrigger gates live UI spectrogram frames when detection triggering is enabled.
+var uiSpectrogramTrigger = newDetectionTrigger()
+
+// newDetectionTrigger creates a trigger with every source idle.
+func newDetectionTrigger() *detectionTrigger {
+	return &detectionTrigger{sources: make(map[string]*detectionTriggerSource)}
+}
+
+// TriggerUiSpectrogram reports a detection on source, releasing its pre-roll and letting
+// its frames through for the configured hold time. An empty source triggers every source.
+func TriggerUiSpectrogram(source string) {
+	settings := &conf.Setting().SoundId.UiSpectrogram
+	if !settings.DetectionTriggered {
+		return
+	}
+	uiSpectrogramTrigger.trigger(source, time.Now(), triggerDuration(settings.TriggerHold, defaultTriggerHold))
+}
+
+// GateUiSpectrogramFrame returns the frames to send for a newly produced frame: the frame
+// itself when detection triggering is disabled or a detection is active, any held pre-roll
+// ahead of it when the detection just started, and nothing while the source is idle.
+func GateUiSpectrogramFrame(data *UiSpectrogramData, settings *conf.UiSpectrogramSettings) []UiSpectrogramData {
+	if !settings.DetectionTriggered {
+		return []UiSpectrogramData{*data}
+	}
+	return uiSpectrogramTrigger.gate(data, time.Now(), triggerDuration(settings.TriggerPreRoll, defaultTriggerPreRoll))
+}
+
+// trigger lets source's frames through until at plus hold. A later detection extends an
+// active one; it is never shortened.
+func (t *detectionTrigger) trigger(source string, at time.Time, hold time.Duration) {
+	until := at.Add(hold)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if source == "" {
+		t.all = later(t.all, until)
+		return
+	}
+	src := t.source(source)
+	src.activeUntil = later(src.activeUntil, until)
+}
+
+// gate applies the trigger state of data's source at now. While idle, the frames captured
+// within preRoll before data are held.
+func (t *detectionTrigger) gate(data *UiSpectrogramData, now time.Time, preRoll time.Duration) []UiSpectrogramData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	src := t.source(data.Source)
+	src.preRoll = append(src.preRoll, *data)
+	src.trimPreRoll(data.Timestamp.Add(-preRoll))
+	if now.Before(src.activeUntil) || now.Before(t.all) {
+		frames := src.preRoll
+		src.preRoll = nil
+		return frames
+	}
+	return nil
+}
+
+// trimPreRoll drops held frames captured before cutoff, and the oldest frames beyond the
+// buffer limit.
+func (s *detectionTriggerSource) trimPreRoll(cutoff time.Time) {
+	drop := 0
+	for drop < len(s.preRoll) && (s.preRoll[drop].Timestamp.Before(cutoff) || len(s.preRoll)-drop > maxTriggerPreRollFrames) {
+		drop++
+	}
+	s.preRoll = s.preRoll[drop:]
+}
+
+// source returns the state of a source, creating it on first use. The caller must hold t.mu.
+func (t *detectionTrigger) source(source string) *detectionTriggerSource {
+	src, ok := t.sources[source]
+	if !ok {
+		src = &detectionTriggerSource{}
+		t.sources[source] = src
+	}
+	return src
+}
+
+// later returns the later of two times.
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// triggerDuration converts a millisecond setting, using fallback when it isn't positive.
+func triggerDuration(ms int, fallback time.Duration) time.Duration {
+	if ms <= 0 {
+		return fallback
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,67 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestDetectionTrigger_FramesOnlyAroundDetections(t *testing.T) {
+	t.Parallel()
+
+	const (
+		frameInterval = 100 * time.Millisecond
+		preRoll       = 500 * time.Millisecond
+		hold          = time.Second
+	)
+	trigger := newDetectionTrigger()
+	start := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	frameAt := func(i int) time.Time { return start.Add(time.Duration(i) * frameInterval) }
+	produce := func(i int) []UiSpectrogramData {
+		return trigger.gate(&UiSpectrogramData{Source: "mic", Timestamp: frameAt(i)}, frameAt(i), preRoll)
+	}
+
+	// Three seconds of silence produce nothing
+	for i := range 30 {
+		assert.Empty(t, produce(i), "no frames may be produced during silence (frame %d)", i)
+	}
+
+	trigger.trigger("mic", frameAt(30), hold)
+	frames := produce(30)
+	require.NotEmpty(t, frames)
+	assert.True(t, frames[0].Timestamp.Equal(frameAt(30).Add(-preRoll)), "the first frame starts the pre-roll window")
+	assert.True(t, frames[len(frames)-1].Timestamp.Equal(frameAt(30)))
+	assert.Len(t, frames, int(preRoll/frameInterval)+1)
+
+	// Frames keep flowing until the hold time after the detection has passed
+	for i := 31; i < 40; i++ {
+		assert.Len(t, produce(i), 1, "frame %d is within the hold time", i)
+	}
+	assert.Empty(t, produce(40), "frames stop once the hold time has passed")
+
+	// Other sources stay quiet
+	assert.Empty(t, trigger.gate(&UiSpectrogramData{Source: "other", Timestamp: frameAt(35)}, frameAt(35), preRoll))
+}
+
+func TestDetectionTrigger_UnknownSourceTriggersAll(t *testing.T) {
+	t.Parallel()
+
+	trigger := newDetectionTrigger()
+	now := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	trigger.trigger("", now, time.Second)
+
+	assert.Len(t, trigger.gate(&UiSpectrogramData{Source: "a", Timestamp: now}, now, time.Second), 1)
+	assert.Len(t, trigger.gate(&UiSpectrogramData{Source: "b", Timestamp: now}, now, time.Second), 1)
+}
+
+func TestGateUiSpectrogramFrame_DisabledPassesEveryFrame(t *testing.T) {
+	t.Parallel()
+
+	frame := UiSpectrogramData{Source: "mic", Timestamp: time.Now()}
+	frames := GateUiSpectrogramFrame(&frame, &conf.UiSpectrogramSettings{})
+	require.Len(t, frames, 1)
+	assert.Equal(t, frame, frames[0])
+}
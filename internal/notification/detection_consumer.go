@@ -52,6 +52,13 @@ func (c *DetectionNotificationConsumer) ProcessDetectionEvent(event events.Detec
 	// Get settings for filtering
 	settings := conf.GetSettings()
 
+	// The periodic digest reports the detection instead
+	if settings != nil && settings.Notification.Digest.Enabled {
+		c.logger.Debug("detection digest enabled, skipping notification",
+			logger.String("species", event.GetSpeciesName()))
+		return nil
+	}
+
 	// Check confidence threshold (if configured)
 	if settings != nil && settings.Notification.Push.MinConfidenceThreshold > 0 {
 		if event.GetConfidence() < settings.Notification.Push.MinConfidenceThreshold {
@@ -437,6 +437,37 @@ func TestDetectionNotificationConsumer_ConfidenceThreshold(t *testing.T) {
 	assert.Len(t, notifications, 2, "Detection at threshold should create notification")
 }
 
+// TestDetectionNotificationConsumer_DigestEnabled verifies that no per-detection
+// notification is created while the detection digest is enabled.
+func TestDetectionNotificationConsumer_DigestEnabled(t *testing.T) {
+	// Note: Cannot use t.Parallel() because tests share global settingsInstance
+
+	settings := conf.GetTestSettings()
+	settings.Notification.Digest.Enabled = true
+	conf.SetTestSettings(settings)
+	defer conf.SetTestSettings(nil) // Clean up
+
+	service, consumer, cleanup := setupTestServiceAndConsumer(t)
+	defer cleanup()
+
+	event, err := events.NewDetectionEvent(
+		"American Robin",
+		"Turdus migratorius",
+		0.92,
+		"backyard-camera",
+		true,
+		0,
+	)
+	require.NoError(t, err)
+
+	err = consumer.ProcessDetectionEvent(event)
+	require.NoError(t, err)
+
+	notifications, err := service.List(&FilterOptions{Types: []Type{TypeDetection}, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, notifications, "The digest reports the detection instead")
+}
+
 // TestDetectionNotificationConsumer_SpeciesCooldown verifies that the same species
 // doesn't trigger multiple notifications within the cooldown period.
 //
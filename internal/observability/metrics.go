@@ -29,6 +29,7 @@ type Metrics struct {
 	SoundLevel    *metrics.SoundLevelMetrics
 	HTTP          *metrics.HTTPMetrics
 	Notification  *metrics.NotificationMetrics
+	Errors        *metrics.ErrorMetrics
 }
 
 // NewMetrics creates a new instance of Metrics, initializing all metric collectors.
@@ -91,6 +92,11 @@ func NewMetrics() (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create Notification metrics: %w", err)
 	}
 
+	errorMetrics, err := metrics.NewErrorMetrics(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Error metrics: %w", err)
+	}
+
 	m := &Metrics{
 		registry:      registry,
 		MQTT:          mqttMetrics,
@@ -104,6 +110,7 @@ func NewMetrics() (*Metrics, error) {
 		SoundLevel:    soundLevelMetrics,
 		HTTP:          httpMetrics,
 		Notification:  notificationMetrics,
+		Errors:        errorMetrics,
 	}
 
 	// Initialize tracing with metrics
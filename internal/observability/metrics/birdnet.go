@@ -3,6 +3,7 @@ package metrics
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -30,6 +31,9 @@ type BirdNETMetrics struct {
 	ActiveProcessingGauge prometheus.Gauge
 	ModelLoadedGauge      prometheus.Gauge
 
+	// Life list lookups of detected species, by whether the species was on the list
+	LifeListLookups *prometheus.CounterVec
+
 	registry *prometheus.Registry
 }
 
@@ -149,6 +153,14 @@ func (m *BirdNETMetrics) initMetrics() error {
 		},
 	)
 
+	m.LifeListLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_life_list_lookups_total",
+			Help: "Total number of life list lookups of detected species, by whether the species was on the list",
+		},
+		[]string{"in_life_list"},
+	)
+
 	return nil
 }
 
@@ -158,6 +170,11 @@ func (m *BirdNETMetrics) IncrementDetectionCounter(speciesName string) {
 	m.DetectionCounter.WithLabelValues(speciesName).Inc()
 }
 
+// RecordLifeListLookup counts a life list lookup of a detected species by its outcome.
+func (m *BirdNETMetrics) RecordLifeListLookup(inLifeList bool) {
+	m.LifeListLookups.WithLabelValues(strconv.FormatBool(inLifeList)).Inc()
+}
+
 // SetProcessTime sets the most recent processing time for a BirdNET detection request.
 func (m *BirdNETMetrics) SetProcessTime(milliseconds float64) {
 	m.ProcessTimeGauge.Set(milliseconds)
@@ -267,6 +284,8 @@ func (m *BirdNETMetrics) Describe(ch chan<- *prometheus.Desc) {
 	// State gauges
 	m.ActiveProcessingGauge.Describe(ch)
 	m.ModelLoadedGauge.Describe(ch)
+
+	m.LifeListLookups.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface.
@@ -289,6 +308,8 @@ func (m *BirdNETMetrics) Collect(ch chan<- prometheus.Metric) {
 	// State gauges
 	m.ActiveProcessingGauge.Collect(ch)
 	m.ModelLoadedGauge.Collect(ch)
+
+	m.LifeListLookups.Collect(ch)
 }
 
 // RecordOperation implements the Recorder interface.
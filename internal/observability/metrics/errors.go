@@ -0,0 +1,66 @@
+// Package metrics provides error metrics for observability
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// ErrorMetrics counts errors by the component and category they carry, so error rates can
+// be charted by where failures originate
+type ErrorMetrics struct {
+	ErrorsTotal *prometheus.CounterVec // Labeled by component and category
+}
+
+// NewErrorMetrics creates and registers new error metrics
+func NewErrorMetrics(registry *prometheus.Registry) (*ErrorMetrics, error) {
+	m := &ErrorMetrics{
+		ErrorsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "errors_total",
+				Help: "Total number of errors by component and category",
+			},
+			[]string{"component", "category"},
+		),
+	}
+	if err := registry.Register(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Describe implements the Collector interface
+func (m *ErrorMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.ErrorsTotal.Describe(ch)
+}
+
+// Collect implements the Collector interface
+func (m *ErrorMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.ErrorsTotal.Collect(ch)
+}
+
+// RecordError counts err under the component and category of the first EnhancedError in its
+// chain, or as an unknown component's generic error when it has none. The children of an
+// aggregated or joined error are counted one by one. A nil err or receiver is ignored, so
+// callers with metrics disabled need no check.
+func (m *ErrorMetrics) RecordError(err error) {
+	if m == nil || err == nil {
+		return
+	}
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, child := range multi.Unwrap() {
+			m.RecordError(child)
+		}
+		return
+	}
+
+	component, category := errors.ComponentUnknown, string(errors.CategoryGeneric)
+	var enhanced *errors.EnhancedError
+	if errors.As(err, &enhanced) {
+		component = enhanced.GetComponent()
+		if enhanced.Category != "" {
+			category = enhanced.GetCategory()
+		}
+	}
+	m.ErrorsTotal.WithLabelValues(component, category).Inc()
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+func TestErrorMetrics_RecordError(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := NewErrorMetrics(registry)
+	require.NoError(t, err)
+
+	fileErr := errors.Newf("life list file not found").Component("life_list").Category(errors.CategoryFileIO).Build()
+	stateErr := errors.Newf("monitoring not running").Component("analysis.uispectrogram").Category(errors.CategoryState).Build()
+
+	m.RecordError(fileErr)
+	m.RecordError(fmt.Errorf("reloading: %w", fileErr))
+	m.RecordError(errors.Aggregate(stateErr, fileErr))
+	m.RecordError(fmt.Errorf("plain failure"))
+	m.RecordError(nil)
+	(*ErrorMetrics)(nil).RecordError(fileErr)
+
+	assert.InDelta(t, 3, testutil.ToFloat64(m.ErrorsTotal.WithLabelValues("life_list", string(errors.CategoryFileIO))), 0.01)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.ErrorsTotal.WithLabelValues("analysis.uispectrogram", string(errors.CategoryState))), 0.01)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.ErrorsTotal.WithLabelValues(errors.ComponentUnknown, string(errors.CategoryGeneric))), 0.01)
+	assert.Equal(t, 3, testutil.CollectAndCount(m), "one series per component and category")
+}
@@ -76,6 +76,26 @@ type MyAudioMetrics struct {
 	birdnetResultsTotal     *prometheus.CounterVec
 	audioQueueOperations    *prometheus.CounterVec
 
+	// UI spectrogram producer metrics
+	uiSpectrogramFFTDuration       *prometheus.HistogramVec
+	uiSpectrogramFrameDrops        *prometheus.CounterVec
+	uiSpectrogramStalls            *prometheus.CounterVec
+	uiSpectrogramPublisherRestarts prometheus.Counter
+
+	// UI spectrogram shutdown metrics
+	uiSpectrogramShutdowns        *prometheus.CounterVec
+	uiSpectrogramShutdownDuration prometheus.Histogram
+
+	// UI spectrogram broadcast metrics
+	uiSpectrogramBroadcastDuration prometheus.Histogram
+	uiSpectrogramFramesBroadcast   prometheus.Counter
+	uiSpectrogramBroadcastErrors   prometheus.Counter
+	uiSpectrogramBroadcastBackoff  prometheus.Counter
+
+	// UI spectrogram buffer metrics
+	uiSpectrogramChannelDepth    prometheus.Gauge
+	uiSpectrogramChannelCapacity prometheus.Gauge
+
 	// collectors is a slice of all collectors for easier iteration
 	collectors []prometheus.Collector
 }
@@ -474,6 +494,97 @@ func (m *MyAudioMetrics) initMetrics() error {
 		[]string{"source", "operation", "status"}, // operation: enqueue, dequeue
 	)
 
+	m.uiSpectrogramFFTDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ui_spectrogram_fft_seconds",
+			Help:    "Time taken to compute the FFT columns of one UI spectrogram frame",
+			Buckets: prometheus.ExponentialBuckets(BucketStart100us, BucketFactor2, BucketCount12), // 0.1ms to ~200ms
+		},
+		[]string{"source"},
+	)
+
+	m.uiSpectrogramFrameDrops = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ui_spectrogram_frame_drops_total",
+			Help: "Total number of UI spectrogram frames dropped because the spectrogram channel was full",
+		},
+		[]string{"source", "strategy"}, // strategy: drop-newest, drop-oldest
+	)
+
+	m.uiSpectrogramStalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ui_spectrogram_source_stalls_total",
+			Help: "Total number of times an audio source stopped feeding the UI spectrogram for longer than the stall timeout",
+		},
+		[]string{"source"},
+	)
+
+	m.uiSpectrogramPublisherRestarts = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ui_spectrogram_publisher_restarts_total",
+			Help: "Total number of times the UI spectrogram SSE publisher restarted after a panic",
+		},
+	)
+
+	m.uiSpectrogramShutdowns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ui_spectrogram_shutdowns_total",
+			Help: "Total number of UI spectrogram monitoring stops by result (clean, forced after the shutdown timeout)",
+		},
+		[]string{"result"},
+	)
+
+	m.uiSpectrogramShutdownDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "ui_spectrogram_shutdown_duration_seconds",
+			Help:    "Time stopping UI spectrogram monitoring waited for its goroutines to finish",
+			Buckets: prometheus.ExponentialBuckets(BucketStart1ms, BucketFactor2, BucketCount15), // 1ms to ~32s
+		},
+	)
+
+	m.uiSpectrogramBroadcastDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "spectrogram_broadcast_duration_seconds",
+			Help:    "Time taken to broadcast one UI spectrogram frame, or one batch of frames, to SSE clients",
+			Buckets: prometheus.ExponentialBuckets(BucketStart100us, BucketFactor2, BucketCount12), // 0.1ms to ~200ms
+		},
+	)
+
+	m.uiSpectrogramFramesBroadcast = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "spectrogram_frames_broadcast_total",
+			Help: "Total number of UI spectrogram frames broadcast to SSE clients",
+		},
+	)
+
+	m.uiSpectrogramBroadcastErrors = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "spectrogram_broadcast_errors_total",
+			Help: "Total number of failed UI spectrogram broadcasts",
+		},
+	)
+
+	m.uiSpectrogramBroadcastBackoff = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "spectrogram_broadcast_backoff_seconds_total",
+			Help: "Total time UI spectrogram broadcasting was paused after consecutive failed broadcasts",
+		},
+	)
+
+	m.uiSpectrogramChannelDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ui_spectrogram_channel_depth",
+			Help: "Frames queued in the UI spectrogram channel when last sampled",
+		},
+	)
+
+	m.uiSpectrogramChannelCapacity = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ui_spectrogram_channel_capacity",
+			Help: "Frames the UI spectrogram channel can hold",
+		},
+	)
+
 	// Initialize collectors slice with all metrics
 	m.collectors = []prometheus.Collector{
 		m.bufferAllocationsTotal,
@@ -521,6 +632,18 @@ func (m *MyAudioMetrics) initMetrics() error {
 		m.audioSampleCountTotal,
 		m.birdnetResultsTotal,
 		m.audioQueueOperations,
+		m.uiSpectrogramFFTDuration,
+		m.uiSpectrogramFrameDrops,
+		m.uiSpectrogramStalls,
+		m.uiSpectrogramPublisherRestarts,
+		m.uiSpectrogramShutdowns,
+		m.uiSpectrogramShutdownDuration,
+		m.uiSpectrogramBroadcastDuration,
+		m.uiSpectrogramFramesBroadcast,
+		m.uiSpectrogramBroadcastErrors,
+		m.uiSpectrogramBroadcastBackoff,
+		m.uiSpectrogramChannelDepth,
+		m.uiSpectrogramChannelCapacity,
 	}
 
 	return nil
@@ -795,3 +918,58 @@ func (m *MyAudioMetrics) RecordBirdNETResults(source string, resultCount int) {
 func (m *MyAudioMetrics) RecordAudioQueueOperation(source, operation, status string) {
 	m.audioQueueOperations.WithLabelValues(source, operation, status).Inc()
 }
+
+// RecordUiSpectrogramFFTDuration records the time spent computing one UI spectrogram frame's FFT
+func (m *MyAudioMetrics) RecordUiSpectrogramFFTDuration(source string, duration float64) {
+	m.uiSpectrogramFFTDuration.WithLabelValues(source).Observe(duration)
+}
+
+// RecordUiSpectrogramFrameDrop records a UI spectrogram frame dropped by the given overflow strategy
+func (m *MyAudioMetrics) RecordUiSpectrogramFrameDrop(source, strategy string) {
+	m.uiSpectrogramFrameDrops.WithLabelValues(source, strategy).Inc()
+}
+
+// RecordUiSpectrogramStall records a UI spectrogram source that stopped producing samples
+func (m *MyAudioMetrics) RecordUiSpectrogramStall(source string) {
+	m.uiSpectrogramStalls.WithLabelValues(source).Inc()
+}
+
+// RecordUiSpectrogramPublisherRestart records a restart of the UI spectrogram SSE publisher after a panic
+func (m *MyAudioMetrics) RecordUiSpectrogramPublisherRestart() {
+	m.uiSpectrogramPublisherRestarts.Inc()
+}
+
+// RecordUiSpectrogramShutdown records a stop of UI spectrogram monitoring that waited duration
+// for its goroutines, counted as forced when they didn't finish within the shutdown timeout
+func (m *MyAudioMetrics) RecordUiSpectrogramShutdown(forced bool, duration float64) {
+	result := "clean"
+	if forced {
+		result = "forced"
+	}
+	m.uiSpectrogramShutdowns.WithLabelValues(result).Inc()
+	m.uiSpectrogramShutdownDuration.Observe(duration)
+}
+
+// RecordUiSpectrogramBroadcast records one broadcast of the given number of UI spectrogram
+// frames, counting the frames when it succeeded and an error when it failed
+func (m *MyAudioMetrics) RecordUiSpectrogramBroadcast(frames int, duration float64, failed bool) {
+	m.uiSpectrogramBroadcastDuration.Observe(duration)
+	if failed {
+		m.uiSpectrogramBroadcastErrors.Inc()
+		return
+	}
+	m.uiSpectrogramFramesBroadcast.Add(float64(frames))
+}
+
+// RecordUiSpectrogramBroadcastBackoff records a pause of UI spectrogram broadcasting after
+// consecutive failed broadcasts
+func (m *MyAudioMetrics) RecordUiSpectrogramBroadcastBackoff(duration float64) {
+	m.uiSpectrogramBroadcastBackoff.Add(duration)
+}
+
+// RecordUiSpectrogramChannelDepth records how many frames the UI spectrogram channel holds
+// out of its capacity
+func (m *MyAudioMetrics) RecordUiSpectrogramChannelDepth(depth, capacity int) {
+	m.uiSpectrogramChannelDepth.Set(float64(depth))
+	m.uiSpectrogramChannelCapacity.Set(float64(capacity))
+}
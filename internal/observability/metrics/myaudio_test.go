@@ -170,3 +170,16 @@ func TestRecordBufferAllocationAttempt(t *testing.T) {
 		assert.InDelta(t, float64(3), blockedCount, 0.01, "Should have 3 blocked repeated allocations")
 	})
 }
+
+func TestRecordUiSpectrogramBroadcast(t *testing.T) {
+	m, err := NewMyAudioMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	m.RecordUiSpectrogramBroadcast(1, 0.001, false)
+	m.RecordUiSpectrogramBroadcast(4, 0.002, false)
+	m.RecordUiSpectrogramBroadcast(2, 0.5, true)
+
+	assert.InDelta(t, 5, testutil.ToFloat64(m.uiSpectrogramFramesBroadcast), 0, "frames of successful broadcasts are counted")
+	assert.InDelta(t, 1, testutil.ToFloat64(m.uiSpectrogramBroadcastErrors), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.uiSpectrogramBroadcastDuration, "spectrogram_broadcast_duration_seconds"))
+}
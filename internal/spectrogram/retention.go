@@ -0,0 +1,207 @@
+package spectrogram
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// defaultRetentionCheckInterval is used when no positive check interval is configured
+const defaultRetentionCheckInterval = time.Hour
+
+// RetentionCleaner periodically removes saved spectrogram images beyond the configured count
+// or age, oldest first. Only the images are removed; clips are left to the disk manager, and
+// a removed spectrogram is generated again on demand when its detection is viewed.
+type RetentionCleaner struct {
+	dir      string
+	maxCount int           // Images to keep, 0 for no limit
+	maxAge   time.Duration // Oldest image to keep, 0 for no limit
+	interval time.Duration
+	logger   logger.Logger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// savedSpectrogram is a spectrogram image found during cleanup.
+type savedSpectrogram struct {
+	path    string
+	modTime time.Time
+}
+
+// NewRetentionCleaner creates a cleaner for the spectrograms saved under the audio export path.
+// If log is nil, GetLogger() is used.
+func NewRetentionCleaner(settings *conf.Settings, log logger.Logger) (*RetentionCleaner, error) {
+	if log == nil {
+		log = GetLogger()
+	}
+	retention := settings.Realtime.Dashboard.Spectrogram.Retention
+
+	var maxAge time.Duration
+	if retention.MaxAge != "" {
+		hours, err := conf.ParseRetentionPeriod(retention.MaxAge)
+		if err != nil {
+			return nil, errors.New(err).
+				Component("spectrogram").
+				Category(errors.CategoryConfiguration).
+				Context("operation", "parse_retention_max_age").
+				Context("max_age", retention.MaxAge).
+				Build()
+		}
+		maxAge = time.Duration(hours) * time.Hour
+	}
+
+	interval := time.Duration(retention.CheckInterval) * time.Minute
+	if interval <= 0 {
+		interval = defaultRetentionCheckInterval
+	}
+
+	return &RetentionCleaner{
+		dir:      settings.Realtime.Audio.Export.Path,
+		maxCount: max(retention.MaxCount, 0),
+		maxAge:   maxAge,
+		interval: interval,
+		logger:   log,
+		now:      time.Now,
+	}, nil
+}
+
+// Enabled reports whether the cleaner has a count or age limit to enforce.
+func (c *RetentionCleaner) Enabled() bool {
+	return c.dir != "" && (c.maxCount > 0 || c.maxAge > 0)
+}
+
+// Start runs a cleanup immediately and then once per check interval until Stop is called.
+// Calling Start on a running cleaner does nothing.
+func (c *RetentionCleaner) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	c.logger.Info("Starting spectrogram retention cleanup",
+		logger.Int("max_count", c.maxCount),
+		logger.Duration("max_age", c.maxAge),
+		logger.Duration("interval", c.interval))
+
+	go c.run(ctx, c.done)
+}
+
+// Stop ends the periodic cleanup and waits for a cleanup in progress to finish.
+func (c *RetentionCleaner) Stop() {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	c.logger.Info("Spectrogram retention cleanup stopped")
+}
+
+// run performs the periodic cleanup until ctx is cancelled.
+func (c *RetentionCleaner) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Cleanup(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Warn("Spectrogram retention cleanup failed",
+				logger.String("dir", c.dir),
+				logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cleanup removes the spectrograms exceeding the count or age limit and returns how many
+// were removed. Files that can't be removed are logged and skipped.
+func (c *RetentionCleaner) Cleanup(ctx context.Context) (int, error) {
+	if !c.Enabled() {
+		return 0, nil
+	}
+
+	var images []savedSpectrogram
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".png") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Removed since the directory was read
+		}
+		images = append(images, savedSpectrogram{path: path, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return 0, errors.New(err).
+			Component("spectrogram").
+			Category(errors.CategoryFileIO).
+			Context("operation", "scan_spectrograms").
+			Context("dir", c.dir).
+			Build()
+	}
+
+	// Newest first, so everything past maxCount is the oldest
+	slices.SortFunc(images, func(a, b savedSpectrogram) int {
+		return b.modTime.Compare(a.modTime)
+	})
+
+	now := c.now()
+	removed := 0
+	for i, image := range images {
+		tooMany := c.maxCount > 0 && i >= c.maxCount
+		tooOld := c.maxAge > 0 && now.Sub(image.modTime) > c.maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(image.path); err != nil {
+			c.logger.Warn("Failed to remove expired spectrogram",
+				logger.String("path", image.path),
+				logger.Error(err))
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		c.logger.Info("Removed expired spectrograms",
+			logger.Int("removed", removed),
+			logger.Int("remaining", len(images)-removed),
+			logger.Int("max_count", c.maxCount),
+			logger.Duration("max_age", c.maxAge))
+	}
+	return removed, nil
+}
@@ -0,0 +1,94 @@
+package spectrogram
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// newTestRetentionCleaner creates a cleaner over a temp export dir with the given limits.
+func newTestRetentionCleaner(t *testing.T, maxCount int, maxAge string) (*RetentionCleaner, string) {
+	t.Helper()
+	dir := t.TempDir()
+	settings := &conf.Settings{}
+	settings.Realtime.Audio.Export.Path = dir
+	settings.Realtime.Dashboard.Spectrogram.Retention = conf.SpectrogramRetention{MaxCount: maxCount, MaxAge: maxAge}
+
+	cleaner, err := NewRetentionCleaner(settings, nil)
+	require.NoError(t, err)
+	return cleaner, dir
+}
+
+// writeSpectrogram creates a file aged by the given duration relative to now.
+func writeSpectrogram(t *testing.T, dir, name string, now time.Time, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte("png"), 0o600))
+	modTime := now.Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func TestRetentionCleaner_RemovesBeyondCountAndAge(t *testing.T) {
+	t.Parallel()
+
+	cleaner, dir := newTestRetentionCleaner(t, 3, "7d")
+	now := time.Now()
+	cleaner.now = func() time.Time { return now }
+
+	newest := writeSpectrogram(t, dir, "2026/05/newest.png", now, time.Hour)
+	recent := writeSpectrogram(t, dir, "2026/05/recent.png", now, 24*time.Hour)
+	stale := writeSpectrogram(t, dir, "2026/04/stale.png", now, 10*24*time.Hour)
+	older := writeSpectrogram(t, dir, "2026/05/older.PNG", now, 2*24*time.Hour)
+	oldest := writeSpectrogram(t, dir, "2026/05/oldest.png", now, 3*24*time.Hour)
+	clip := writeSpectrogram(t, dir, "2026/04/stale.wav", now, 10*24*time.Hour)
+
+	removed, err := cleaner.Cleanup(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed, "one image over the age limit, one over the count limit")
+
+	for _, kept := range []string{newest, recent, older, clip} {
+		assert.FileExists(t, kept)
+	}
+	for _, gone := range []string{stale, oldest} {
+		assert.NoFileExists(t, gone)
+	}
+}
+
+func TestRetentionCleaner_StartStop(t *testing.T) {
+	t.Parallel()
+
+	cleaner, dir := newTestRetentionCleaner(t, 1, "")
+	now := time.Now()
+	keep := writeSpectrogram(t, dir, "keep.png", now, time.Minute)
+	drop := writeSpectrogram(t, dir, "drop.png", now, time.Hour)
+
+	// Start cleans up immediately; Stop waits for that run to finish
+	cleaner.Start()
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(drop)
+		return os.IsNotExist(err)
+	}, 5*time.Second, 10*time.Millisecond)
+	cleaner.Stop()
+	cleaner.Stop()
+
+	assert.FileExists(t, keep)
+}
+
+func TestNewRetentionCleaner_Limits(t *testing.T) {
+	t.Parallel()
+
+	cleaner, _ := newTestRetentionCleaner(t, 0, "")
+	assert.False(t, cleaner.Enabled(), "no limits configured")
+
+	settings := &conf.Settings{}
+	settings.Realtime.Dashboard.Spectrogram.Retention.MaxAge = "soon"
+	_, err := NewRetentionCleaner(settings, nil)
+	require.Error(t, err)
+}